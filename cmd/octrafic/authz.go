@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Octrafic/octrafic-cli/internal/authz"
+	"github.com/Octrafic/octrafic-cli/internal/cli"
+	"github.com/Octrafic/octrafic-cli/internal/core/auth"
+	"github.com/Octrafic/octrafic-cli/internal/infra/storage"
+)
+
+// runAuthzMatrix sends every endpoint of a project as every identity and
+// compares the outcomes to the access table in --authz-matrix.
+func runAuthzMatrix() int {
+	if testProject == "" {
+		fmt.Fprintf(os.Stderr, "Error: Project name (-n, --name) is required for --authz-matrix\n")
+		return 1
+	}
+	project, err := storage.FindProjectByName(testProject)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Project '%s' not found\n", testProject)
+		return 1
+	}
+	matrix, err := authz.LoadMatrix(testAuthzMatrix)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	baseURL := apiURL
+	if baseURL == "" {
+		baseURL = project.BaseURL
+	}
+	if baseURL == "" {
+		fmt.Fprintf(os.Stderr, "Error: API URL is required (-u, --url)\n")
+		return 1
+	}
+
+	names := matrix.Identities
+	if len(names) == 0 {
+		if len(project.Identities) == 0 {
+			fmt.Fprintf(os.Stderr, "Error: Project '%s' has no identities; save them with --auth ... --identity <name>\n", project.Name)
+			return 1
+		}
+		names = append(project.IdentityNames(), storage.AnonymousIdentity)
+	}
+	providers, err := cli.IdentityProviders(project)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Failed to load identities: %v\n", err)
+		return 1
+	}
+	var identities []authz.Identity
+	for _, name := range names {
+		provider, ok := providers[name]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: Unknown identity '%s' (available: %s)\n", name, strings.Join(append(project.IdentityNames(), storage.AnonymousIdentity), ", "))
+			return 1
+		}
+		if err := provider.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Invalid identity '%s': %v\n", name, err)
+			return 1
+		}
+		if oauth2, ok := provider.(*auth.OAuth2Auth); ok {
+			signInOAuth2(oauth2)
+		}
+		identities = append(identities, authz.Identity{Name: name, Provider: provider, Anonymous: name == storage.AnonymousIdentity})
+	}
+
+	endpoints, err := storage.LoadEndpoints(project.ID, project.IsTemporary)
+	if err != nil && len(matrix.Rules) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: Failed to load project endpoints: %v\n", err)
+		return 1
+	}
+	cases := matrix.Cases(endpoints)
+	for _, rule := range matrix.Skipped() {
+		fmt.Printf("Skipping %s: add its method to methods to send it\n", rule.Endpoint)
+	}
+	if len(cases) == 0 {
+		fmt.Println("No endpoints to check.")
+		return 0
+	}
+
+	fmt.Printf("Checking %d endpoints as %s...\n\n", len(cases), strings.Join(names, ", "))
+	report := authz.Run(baseURL, identities, cases, matrix)
+	printAuthzReport(report)
+
+	if len(report.Findings()) > 0 || report.Errors() > 0 {
+		return 1
+	}
+	return 0
+}
+
+func printAuthzReport(report *authz.Report) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintf(w, "\t\t%s\n", strings.Join(report.Identities, "\t"))
+	unclear := 0
+	for _, row := range report.Rows {
+		cells := make([]string, len(row.Cells))
+		for i, cell := range row.Cells {
+			cells[i] = authzCellText(cell)
+			if cell.Actual == authz.Unclear {
+				unclear++
+			}
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", row.Case.Method, row.Case.URL, strings.Join(cells, "\t"))
+	}
+	_ = w.Flush()
+	fmt.Println("\n* = unexpected denial, !! = privilege escalation, ? = neither allowed nor denied")
+
+	findings := report.Findings()
+	escalations := 0
+	fmt.Println()
+	for _, row := range report.Rows {
+		for _, cell := range row.Cells {
+			if cell.Err != nil {
+				fmt.Printf("✗ %s %s as %s: %v\n", row.Case.Method, row.Case.URL, cell.Identity, cell.Err)
+			}
+		}
+	}
+	for _, f := range findings {
+		if f.Escalation {
+			escalations++
+			fmt.Printf("🚨 PRIVILEGE ESCALATION: %s %s as %s returned %d (expected deny)\n", f.Method, f.Path, f.Cell.Identity, f.Cell.StatusCode)
+		} else {
+			fmt.Printf("⚠️  Unexpected denial: %s %s as %s returned %d (expected allow)\n", f.Method, f.Path, f.Cell.Identity, f.Cell.StatusCode)
+		}
+	}
+
+	fmt.Println("\n=============================================")
+	fmt.Printf("Summary: %d checks, %d privilege escalations, %d unexpected denials, %d unclear, %d errors\n",
+		len(report.Rows)*len(report.Identities), escalations, len(findings)-escalations, unclear-report.Errors(), report.Errors())
+}
+
+func authzCellText(cell authz.Cell) string {
+	if cell.Err != nil {
+		return "? error"
+	}
+	switch {
+	case cell.Escalation():
+		return fmt.Sprintf("!! %d", cell.StatusCode)
+	case cell.Mismatch():
+		return fmt.Sprintf("* %d", cell.StatusCode)
+	case cell.Actual == authz.Allow:
+		return fmt.Sprintf("allowed %d", cell.StatusCode)
+	case cell.Actual == authz.Deny:
+		return fmt.Sprintf("denied %d", cell.StatusCode)
+	default:
+		return fmt.Sprintf("? %d", cell.StatusCode)
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Octrafic/octrafic-cli/internal/core/linter"
+	"github.com/Octrafic/octrafic-cli/internal/core/parser"
+	"github.com/Octrafic/octrafic-cli/internal/infra/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	lintFormat    string
+	lintConfig    string
+	lintOut       string
+	lintListRules bool
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check the quality of the API specification",
+	Run: func(cmd *cobra.Command, args []string) {
+		os.Exit(runLint())
+	},
+}
+
+// runLint lints the specification and writes the report. It returns the
+// exit status: 1 on error findings.
+func runLint() int {
+	if lintListRules {
+		for _, r := range linter.AllRules() {
+			fmt.Printf("%-26s %-8s %s\n", r.ID, r.Severity, r.Description)
+		}
+		return 0
+	}
+
+	path := specFile
+	if path == "" && projectName != "" {
+		project, err := storage.FindProjectByName(projectName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Project '%s' not found\n", projectName)
+			return 1
+		}
+		path = project.SpecPath
+	}
+	if path == "" {
+		fmt.Fprintf(os.Stderr, "Error: Specification file (-s, --spec) or project name (-n, --name) is required\n")
+		return 1
+	}
+	if lintFormat != "text" && lintFormat != "json" && lintFormat != "sarif" {
+		fmt.Fprintf(os.Stderr, "Error: Invalid format: %s (use text, json or sarif)\n", lintFormat)
+		return 1
+	}
+
+	spec, err := parser.ParseSpecification(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Failed to parse specification: %v\n", err)
+		return 1
+	}
+	if spec.Format != "openapi" {
+		fmt.Fprintf(os.Stderr, "Error: %s is a %s document; only OpenAPI and Swagger specifications can be linted\n", path, spec.Format)
+		return 1
+	}
+
+	configPath := lintConfig
+	if configPath == "" {
+		configPath = linter.FindConfig(path)
+	}
+	var cfg *linter.Config
+	if configPath != "" {
+		if cfg, err = linter.LoadConfig(configPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+	l, err := linter.New(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	findings, err := l.Lint([]byte(spec.RawContent))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	var out io.Writer = os.Stdout
+	var file *os.File
+	if lintOut != "" {
+		file, err = os.Create(lintOut)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to create %s: %v\n", lintOut, err)
+			return 1
+		}
+		out = file
+	}
+	switch lintFormat {
+	case "json":
+		err = linter.WriteJSON(out, path, findings)
+	case "sarif":
+		err = linter.WriteSARIF(out, path, version, l.Rules(), findings)
+	default:
+		err = linter.WriteText(out, path, findings)
+	}
+	if file != nil {
+		// A failed close can lose buffered data, so the report would be incomplete.
+		if closeErr := file.Close(); err == nil && closeErr != nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Failed to write report: %v\n", err)
+		return 1
+	}
+
+	if linter.Count(findings, linter.SeverityError) > 0 {
+		return 1
+	}
+	return 0
+}
+
+func printLintHelp(cmd *cobra.Command) {
+	fmt.Printf("Check the quality of the API specification\n\n")
+	fmt.Printf("Usage:\n  %s\n\n", cmd.UseLine())
+
+	fmt.Printf("Source:\n")
+	printFlag(cmd, "spec", "s", "Path to API specification file (OpenAPI/Swagger)")
+	printFlag(cmd, "name", "n", "Use the specification of a saved project")
+
+	fmt.Printf("\nRules:\n")
+	printFlag(cmd, "config", "c", "YAML/JSON file enabling, disabling or changing the severity of rules (default "+linter.ConfigFile+" beside the spec)")
+	printFlag(cmd, "rules", "", "List the rules with their default severities")
+
+	fmt.Printf("\nOutput:\n")
+	printFlag(cmd, "format", "f", "Report format: text|json|sarif (default text)")
+	printFlag(cmd, "out", "o", "Write the report to a file instead of stdout")
+	fmt.Printf("  Exits with status 1 when there are error findings.\n")
+
+	fmt.Printf("\nLearn more: https://github.com/Octrafic/octrafic-cli\n")
+}
+
+func init() {
+	lintCmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		printLintHelp(cmd)
+	})
+	lintCmd.SetUsageFunc(func(cmd *cobra.Command) error {
+		printLintHelp(cmd)
+		return nil
+	})
+
+	rootCmd.AddCommand(lintCmd)
+	lintCmd.Flags().StringVarP(&specFile, "spec", "s", "", "Path to API specification file (OpenAPI/Swagger)")
+	lintCmd.Flags().StringVarP(&projectName, "name", "n", "", "Use the specification of a saved project")
+	lintCmd.Flags().StringVarP(&lintConfig, "config", "c", "", "YAML/JSON file enabling, disabling or changing the severity of rules")
+	lintCmd.Flags().BoolVar(&lintListRules, "rules", false, "List the rules with their default severities")
+	lintCmd.Flags().StringVarP(&lintFormat, "format", "f", "text", "Report format: text|json|sarif")
+	lintCmd.Flags().StringVarP(&lintOut, "out", "o", "", "Write the report to a file instead of stdout")
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"cmp"
+	"context"
 	"crypto/rand"
 	"fmt"
 	"github.com/Octrafic/octrafic-cli/internal/cli"
@@ -9,6 +11,7 @@ import (
 	"github.com/Octrafic/octrafic-cli/internal/core/auth"
 	"github.com/Octrafic/octrafic-cli/internal/core/converter"
 	"github.com/Octrafic/octrafic-cli/internal/core/parser"
+	"github.com/Octrafic/octrafic-cli/internal/core/tester"
 	"github.com/Octrafic/octrafic-cli/internal/infra/logger"
 	"github.com/Octrafic/octrafic-cli/internal/infra/storage"
 	"github.com/Octrafic/octrafic-cli/internal/updater"
@@ -28,6 +31,29 @@ const (
 	authValueEnvVar = "OCTRAFIC_AUTH_VALUE"
 	authUserEnvVar  = "OCTRAFIC_AUTH_USER"
 	authPassEnvVar  = "OCTRAFIC_AUTH_PASS"
+
+	authTokenURLEnvVar     = "OCTRAFIC_AUTH_TOKEN_URL"
+	authClientIDEnvVar     = "OCTRAFIC_AUTH_CLIENT_ID"
+	authClientSecretEnvVar = "OCTRAFIC_AUTH_CLIENT_SECRET"
+	authRefreshTokenEnvVar = "OCTRAFIC_AUTH_REFRESH_TOKEN"
+	authScopesEnvVar       = "OCTRAFIC_AUTH_SCOPES"
+	authAudienceEnvVar     = "OCTRAFIC_AUTH_AUDIENCE"
+	authAuthorizeURLEnvVar = "OCTRAFIC_AUTH_AUTHORIZE_URL"
+	authRedirectURLEnvVar  = "OCTRAFIC_AUTH_REDIRECT_URL"
+
+	authLoginMethodEnvVar = "OCTRAFIC_AUTH_LOGIN_METHOD"
+	authLoginPathEnvVar   = "OCTRAFIC_AUTH_LOGIN_PATH"
+	authLoginBodyEnvVar   = "OCTRAFIC_AUTH_LOGIN_BODY"
+	authTokenFromEnvVar   = "OCTRAFIC_AUTH_TOKEN_FROM"
+	authExpiresFromEnvVar = "OCTRAFIC_AUTH_EXPIRES_FROM"
+	authInjectEnvVar      = "OCTRAFIC_AUTH_INJECT"
+
+	authRegionEnvVar      = "OCTRAFIC_AUTH_REGION"
+	authServiceEnvVar     = "OCTRAFIC_AUTH_SERVICE"
+	authKeyIDEnvVar       = "OCTRAFIC_AUTH_KEY_ID"
+	authSecretEnvVar      = "OCTRAFIC_AUTH_SECRET"
+	authSignEnvVar        = "OCTRAFIC_AUTH_SIGN"
+	authSignHeadersEnvVar = "OCTRAFIC_AUTH_SIGN_HEADERS"
 )
 
 var (
@@ -46,8 +72,40 @@ var (
 	authUser  string
 	authPass  string
 
+	authTokenURL     string
+	authClientID     string
+	authClientSecret string
+	authRefreshToken string
+	authScopes       string
+	authAudience     string
+	authAuthorizeURL string
+	authRedirectURL  string
+
+	authLoginMethod string
+	authLoginPath   string
+	authLoginBody   string
+	authTokenFrom   string
+	authExpiresFrom string
+	authInject      string
+
+	authAccessKey    string
+	authSecretKey    string
+	authSessionToken string
+	authRegion       string
+	authService      string
+
+	authKeyID           string
+	authSecret          string
+	authSign            string
+	authSignHeaders     string
+	authHMACAlgorithm   string
+	authHMACEncoding    string
+	authTimestampFormat string
+
 	clearAuth bool
 
+	identityName string
+
 	debugFilePath string
 
 	forceOnboarding bool
@@ -150,7 +208,7 @@ Chat naturally with your APIs - no scripts, no configuration files, just convers
 				if specFile != existingProject.SpecPath {
 					needsUpdate = true
 				} else if specFile != "" {
-					newHash, err := storage.ComputeFileHash(specFile)
+					newHash, err := storage.ComputeSpecHash(specFile, existingProject.SpecFiles)
 					if err == nil && newHash != existingProject.SpecHash {
 						needsUpdate = true
 					}
@@ -163,7 +221,7 @@ Chat naturally with your APIs - no scripts, no configuration files, just convers
 						fmt.Printf("   URL: %s → %s\n", existingProject.BaseURL, apiURL)
 					}
 					if specFile != "" {
-						newHash, _ := storage.ComputeFileHash(specFile)
+						newHash, _ := storage.ComputeSpecHash(specFile, existingProject.SpecFiles)
 						if newHash != existingProject.SpecHash {
 							fmt.Printf("   Spec: %s (modified)\n", existingProject.SpecPath)
 						} else if specFile != existingProject.SpecPath {
@@ -202,14 +260,25 @@ Chat naturally with your APIs - no scripts, no configuration files, just convers
 			os.Exit(1)
 		}
 
-		// Auto-save auth with named projects
+		// Auto-save auth with named projects, as a named identity with --identity
 		if hasName && authType != "none" && authType != "" {
-			project.AuthConfig = createAuthConfig()
+			if identityName != "" {
+				if err := project.SetIdentity(identityName, createAuthConfig()); err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+			} else {
+				project.AuthConfig = createAuthConfig()
+			}
 			if err := storage.SaveProject(project); err != nil {
 				fmt.Printf("Warning: failed to save authentication: %v\n", err)
+			} else if identityName != "" {
+				fmt.Printf("✓ Authentication saved as identity '%s'\n", identityName)
 			} else {
 				fmt.Println("✓ Authentication saved with project")
 			}
+		} else if identityName != "" {
+			authProvider = identityAuth(project)
 		}
 
 		specContent, err := parser.ParseSpecification(specFile)
@@ -241,13 +310,18 @@ func printCustomHelp(cmd *cobra.Command) {
 	printFlag(cmd, "conversation", "", "Specific conversation ID to resume")
 
 	fmt.Printf("\nAuthentication:\n")
-	printFlag(cmd, "auth", "", "Authentication type: none|bearer|apikey|basic")
+	printFlag(cmd, "auth", "", "Authentication type: none|bearer|apikey|basic|oauth2|login|sigv4|hmac")
 	printFlag(cmd, "token", "", "Bearer token value")
 	printFlag(cmd, "key", "", "API key header name (e.g., X-API-Key)")
 	printFlag(cmd, "value", "", "API key value")
 	printFlag(cmd, "user", "", "Username for basic authentication")
 	printFlag(cmd, "pass", "", "Password for basic authentication")
+	printOAuth2Flags(cmd)
+	printLoginFlags(cmd)
+	printSigningFlags(cmd)
+	printFlag(cmd, "identity", "", "Save --auth as this named identity of the project, or run as it")
 	printFlag(cmd, "clear-auth", "", "Remove saved authentication from project")
+	fmt.Printf("  Credentials may be given as env:NAME, file:PATH or cmd:COMMAND to keep them out of octrafic.\n")
 
 	fmt.Printf("\nAdvanced:\n")
 	printFlag(cmd, "auto", "a", "Run in Auto-Execute mode without manual confirmation")
@@ -325,13 +399,17 @@ func init() {
 	rootCmd.Flags().StringVarP(&specFile, "spec", "s", "", "Path to API specification file (OpenAPI/Swagger)")
 	rootCmd.Flags().StringVarP(&projectName, "name", "n", "", "Project name for saving/loading configuration")
 
-	rootCmd.Flags().StringVar(&authType, "auth", "none", "Authentication type: none|bearer|apikey|basic")
+	rootCmd.Flags().StringVar(&authType, "auth", "none", "Authentication type: none|bearer|apikey|basic|oauth2|login|sigv4|hmac")
 	rootCmd.Flags().StringVar(&authToken, "token", "", "Bearer token value")
 	rootCmd.Flags().StringVar(&authKey, "key", "", "API key header name (e.g., X-API-Key)")
 	rootCmd.Flags().StringVar(&authValue, "value", "", "API key value")
 	rootCmd.Flags().StringVar(&authUser, "user", "", "Username for basic authentication")
 	rootCmd.Flags().StringVar(&authPass, "pass", "", "Password for basic authentication")
+	addOAuth2Flags(rootCmd)
+	addLoginFlags(rootCmd)
+	addSigningFlags(rootCmd)
 	rootCmd.Flags().BoolVar(&clearAuth, "clear-auth", false, "Remove saved authentication from project")
+	rootCmd.Flags().StringVar(&identityName, "identity", "", "Save --auth as this named identity of the project, or run as it")
 
 	rootCmd.Flags().StringVar(&debugFilePath, "debug-file", "", "Path to debug log file (enables detailed logging)")
 	rootCmd.Flags().BoolVarP(&yoloMode, "auto", "a", false, "Run tests without manual confirmation")
@@ -360,7 +438,7 @@ func buildAuthFromEnvironments() auth.AuthProvider {
 			fmt.Fprintf(os.Stderr, "Error: %s is required when using %s bearer\n", authTokenEnvVar, authTypeEnvVar)
 			os.Exit(1)
 		}
-		return auth.NewBearerAuth(authToken)
+		return auth.AllowReferences(auth.NewBearerAuth(authToken))
 	case "apikey":
 		authKey := os.Getenv(authKeyEnvVar)
 		authValue := os.Getenv(authValueEnvVar)
@@ -369,7 +447,7 @@ func buildAuthFromEnvironments() auth.AuthProvider {
 			fmt.Fprintf(os.Stderr, "Error: %s and %s are required when using %s apikey\n", authKeyEnvVar, authValueEnvVar, authTypeEnvVar)
 			os.Exit(1)
 		}
-		return auth.NewAPIKeyAuth(authKey, authValue, "header")
+		return auth.AllowReferences(auth.NewAPIKeyAuth(authKey, authValue, "header"))
 	case "basic":
 		authUser := os.Getenv(authUserEnvVar)
 		authPass := os.Getenv(authPassEnvVar)
@@ -378,7 +456,47 @@ func buildAuthFromEnvironments() auth.AuthProvider {
 			fmt.Fprintf(os.Stderr, "Error: %s and %s are required when using %s basic\n", authUserEnvVar, authPassEnvVar, authTypeEnvVar)
 			os.Exit(1)
 		}
-		return auth.NewBasicAuth(authUser, authPass)
+		return auth.AllowReferences(auth.NewBasicAuth(authUser, authPass))
+	case "oauth2":
+		provider := cli.OAuth2FromConfig(&storage.AuthConfig{
+			TokenURL:     os.Getenv(authTokenURLEnvVar),
+			ClientID:     os.Getenv(authClientIDEnvVar),
+			ClientSecret: os.Getenv(authClientSecretEnvVar),
+			RefreshToken: os.Getenv(authRefreshTokenEnvVar),
+			Scopes:       os.Getenv(authScopesEnvVar),
+			Audience:     os.Getenv(authAudienceEnvVar),
+			AuthorizeURL: os.Getenv(authAuthorizeURLEnvVar),
+			RedirectURL:  os.Getenv(authRedirectURLEnvVar),
+		})
+		if err := provider.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v (set %s, %s and %s, %s or %s)\n", err, authTokenURLEnvVar, authClientIDEnvVar, authClientSecretEnvVar, authRefreshTokenEnvVar, authAuthorizeURLEnvVar)
+			os.Exit(1)
+		}
+		provider.AllowReferences()
+		signInOAuth2(provider)
+		return provider
+	case "login":
+		provider := tester.NewLoginAuth("", loginFlow(os.Getenv(authLoginMethodEnvVar), os.Getenv(authLoginPathEnvVar), os.Getenv(authLoginBodyEnvVar),
+			os.Getenv(authTokenFromEnvVar), os.Getenv(authExpiresFromEnvVar), os.Getenv(authInjectEnvVar)))
+		if err := provider.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v (set %s and %s)\n", err, authLoginPathEnvVar, authTokenFromEnvVar)
+			os.Exit(1)
+		}
+		return auth.AllowReferences(provider)
+	case "sigv4":
+		provider := sigV4Auth("", "", "", os.Getenv(authRegionEnvVar), os.Getenv(authServiceEnvVar))
+		if err := provider.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v (set AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and %s or AWS_REGION)\n", err, authRegionEnvVar)
+			os.Exit(1)
+		}
+		return auth.AllowReferences(provider)
+	case "hmac":
+		provider := hmacAuth(os.Getenv(authKeyIDEnvVar), os.Getenv(authSecretEnvVar), os.Getenv(authSignEnvVar), os.Getenv(authSignHeadersEnvVar), "", "", "")
+		if err := provider.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v (set %s)\n", err, authSecretEnvVar)
+			os.Exit(1)
+		}
+		return auth.AllowReferences(provider)
 	case "none":
 		return &auth.NoAuth{}
 	default:
@@ -395,21 +513,59 @@ func buildAuthFromFlags() auth.AuthProvider {
 			fmt.Fprintf(os.Stderr, "Error: --token is required when using --auth bearer\n")
 			os.Exit(1)
 		}
-		return auth.NewBearerAuth(authToken)
+		return auth.AllowReferences(auth.NewBearerAuth(authToken))
 
 	case "apikey":
 		if authKey == "" || authValue == "" {
 			fmt.Fprintf(os.Stderr, "Error: --key and --value are required when using --auth apikey\n")
 			os.Exit(1)
 		}
-		return auth.NewAPIKeyAuth(authKey, authValue, "header")
+		return auth.AllowReferences(auth.NewAPIKeyAuth(authKey, authValue, "header"))
 
 	case "basic":
 		if authUser == "" || authPass == "" {
 			fmt.Fprintf(os.Stderr, "Error: --user and --pass are required when using --auth basic\n")
 			os.Exit(1)
 		}
-		return auth.NewBasicAuth(authUser, authPass)
+		return auth.AllowReferences(auth.NewBasicAuth(authUser, authPass))
+
+	case "oauth2":
+		provider := cli.OAuth2FromConfig(createAuthConfig())
+		if err := provider.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v (use --token-url with --client-id and --client-secret, --refresh-token or --authorize-url)\n", err)
+			os.Exit(1)
+		}
+		// Keep the refresh token from sign-in so it is saved with the project.
+		provider.OnRefreshToken = func(refreshToken string) {
+			authRefreshToken = refreshToken
+		}
+		provider.AllowReferences()
+		signInOAuth2(provider)
+		return provider
+
+	case "login":
+		provider := tester.NewLoginAuth(apiURL, *createAuthConfig().Login)
+		if err := provider.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v (use --login-path with --token-from)\n", err)
+			os.Exit(1)
+		}
+		return auth.AllowReferences(provider)
+
+	case "sigv4":
+		provider := createAuthConfig().SigV4
+		if err := provider.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v (use --region and --access-key/--secret-key or the AWS_* environment variables)\n", err)
+			os.Exit(1)
+		}
+		return auth.AllowReferences(provider)
+
+	case "hmac":
+		provider := createAuthConfig().HMAC
+		if err := provider.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v (use --secret, with --sign and --sign-headers for custom schemes)\n", err)
+			os.Exit(1)
+		}
+		return auth.AllowReferences(provider)
 
 	case "none":
 		return &auth.NoAuth{}
@@ -426,16 +582,174 @@ func buildAuthFromProject(project *storage.Project) auth.AuthProvider {
 		return &auth.NoAuth{}
 	}
 
-	switch project.AuthConfig.Type {
-	case "bearer":
-		return auth.NewBearerAuth(project.AuthConfig.Token)
-	case "apikey":
-		return auth.NewAPIKeyAuth(project.AuthConfig.KeyName, project.AuthConfig.KeyValue, "header")
-	case "basic":
-		return auth.NewBasicAuth(project.AuthConfig.Username, project.AuthConfig.Password)
-	default:
-		return &auth.NoAuth{}
+	provider, err := cli.AuthFromConfig(project.AuthConfig, project.BaseURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Failed to load saved authentication: %v\n", err)
+		os.Exit(1)
+	}
+	if oauth2, ok := provider.(*auth.OAuth2Auth); ok {
+		cfg := project.AuthConfig
+		// Save new refresh tokens so later sessions and --resume skip sign-in.
+		oauth2.OnRefreshToken = func(refreshToken string) {
+			cfg.RefreshToken = refreshToken
+			if err := storage.SaveProject(project); err != nil {
+				logger.Warn("Failed to save refresh token", logger.Err(err))
+			}
+		}
+		signInOAuth2(oauth2)
 	}
+	return provider
+}
+
+// identityAuth returns the provider of the --identity identity of a project.
+func identityAuth(project *storage.Project) auth.AuthProvider {
+	providers, err := cli.IdentityProviders(project)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Failed to load identities: %v\n", err)
+		os.Exit(1)
+	}
+	provider, ok := providers[identityName]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: Project '%s' has no identity '%s'\n", project.Name, identityName)
+		os.Exit(1)
+	}
+	if oauth2, ok := provider.(*auth.OAuth2Auth); ok {
+		signInOAuth2(oauth2)
+	}
+	fmt.Printf("✓ Using identity '%s'\n", identityName)
+	return provider
+}
+
+// signInOAuth2 runs the browser sign-in for the authorization_code grant
+// when there is no refresh token to start from.
+func signInOAuth2(provider *auth.OAuth2Auth) {
+	if !provider.NeedsLogin() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	err := provider.Login(ctx, func(authorizeURL string) {
+		fmt.Printf("Sign in to authorize Octrafic:\n  %s\n\n", authorizeURL)
+		if err := cli.OpenBrowser(authorizeURL); err != nil {
+			fmt.Println("Open the URL above in your browser.")
+		}
+		fmt.Println("Waiting for sign-in...")
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("✓ Signed in")
+}
+
+// loginFlow builds a login flow from flag or environment values; inject is
+// location:name, e.g. header:Authorization or cookie:session.
+func loginFlow(method, path, body, tokenFrom, expiresFrom, inject string) tester.LoginFlow {
+	injectAs, injectName := tester.ParseInject(inject)
+	return tester.LoginFlow{
+		Method:      method,
+		Path:        path,
+		Body:        body,
+		TokenFrom:   tokenFrom,
+		ExpiresFrom: expiresFrom,
+		InjectAs:    injectAs,
+		InjectName:  injectName,
+	}
+}
+
+// sigV4Auth builds a SigV4 provider, taking credentials and region missing
+// from flags from the standard AWS environment variables.
+func sigV4Auth(accessKey, secretKey, sessionToken, region, service string) *auth.SigV4Auth {
+	return auth.NewSigV4Auth(
+		cmp.Or(accessKey, os.Getenv("AWS_ACCESS_KEY_ID")),
+		cmp.Or(secretKey, os.Getenv("AWS_SECRET_ACCESS_KEY")),
+		cmp.Or(sessionToken, os.Getenv("AWS_SESSION_TOKEN")),
+		cmp.Or(region, os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION")),
+		service,
+	)
+}
+
+// hmacAuth builds an HMAC provider; empty values keep the default scheme.
+func hmacAuth(keyID, secret, stringToSign, headers, algorithm, encoding, timestampFormat string) *auth.HMACAuth {
+	provider := auth.NewHMACAuth(keyID, secret)
+	provider.StringToSign = stringToSign
+	if headers != "" {
+		provider.Headers = auth.ParseHeaderTemplates(headers)
+	}
+	provider.Algorithm = algorithm
+	provider.Encoding = encoding
+	provider.TimestampFormat = timestampFormat
+	return provider
+}
+
+func addSigningFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&authAccessKey, "access-key", "", "AWS access key ID (default $AWS_ACCESS_KEY_ID)")
+	cmd.Flags().StringVar(&authSecretKey, "secret-key", "", "AWS secret access key (default $AWS_SECRET_ACCESS_KEY)")
+	cmd.Flags().StringVar(&authSessionToken, "session-token", "", "AWS session token (default $AWS_SESSION_TOKEN)")
+	cmd.Flags().StringVar(&authRegion, "region", "", "AWS region (default $AWS_REGION)")
+	cmd.Flags().StringVar(&authService, "service", "execute-api", "AWS service name")
+	cmd.Flags().StringVar(&authKeyID, "key-id", "", "HMAC key ID")
+	cmd.Flags().StringVar(&authSecret, "secret", "", "HMAC secret")
+	cmd.Flags().StringVar(&authSign, "sign", "", `HMAC string to sign template (default "{{method}}\n{{path}}\n{{timestamp}}\n{{body_sha256}}")`)
+	cmd.Flags().StringVar(&authSignHeaders, "sign-headers", "", `HMAC header templates, e.g. "X-Signature: {{signature}}; X-Timestamp: {{timestamp}}"`)
+	cmd.Flags().StringVar(&authHMACAlgorithm, "hmac-algorithm", "", "HMAC hash: sha256 (default), sha1 or sha512")
+	cmd.Flags().StringVar(&authHMACEncoding, "hmac-encoding", "", "HMAC signature encoding: hex (default) or base64")
+	cmd.Flags().StringVar(&authTimestampFormat, "timestamp-format", "", "HMAC timestamp: unix (default), unix_ms, rfc3339 or http")
+}
+
+func printSigningFlags(cmd *cobra.Command) {
+	printFlag(cmd, "access-key", "", "AWS access key ID (default $AWS_ACCESS_KEY_ID)")
+	printFlag(cmd, "secret-key", "", "AWS secret access key (default $AWS_SECRET_ACCESS_KEY)")
+	printFlag(cmd, "session-token", "", "AWS session token (default $AWS_SESSION_TOKEN)")
+	printFlag(cmd, "region", "", "AWS region (default $AWS_REGION)")
+	printFlag(cmd, "service", "", "AWS service name (default execute-api)")
+	printFlag(cmd, "key-id", "", "HMAC key ID")
+	printFlag(cmd, "secret", "", "HMAC secret")
+	printFlag(cmd, "sign", "", "HMAC string to sign template")
+	printFlag(cmd, "sign-headers", "", "HMAC header templates, e.g. \"X-Signature: {{signature}}\"")
+	printFlag(cmd, "hmac-algorithm", "", "HMAC hash: sha256 (default), sha1 or sha512")
+	printFlag(cmd, "hmac-encoding", "", "HMAC signature encoding: hex (default) or base64")
+	printFlag(cmd, "timestamp-format", "", "HMAC timestamp: unix (default), unix_ms, rfc3339 or http")
+}
+
+func addLoginFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&authLoginMethod, "login-method", "POST", "Login request method")
+	cmd.Flags().StringVar(&authLoginPath, "login-path", "", "Login request path, e.g. /auth/login")
+	cmd.Flags().StringVar(&authLoginBody, "login-body", "", "Login request body; use {{$env.NAME}} for credentials")
+	cmd.Flags().StringVar(&authTokenFrom, "token-from", "", "Where the login response has the token: body:<path>, header:<name> or cookie:<name>")
+	cmd.Flags().StringVar(&authExpiresFrom, "expires-from", "", "Where the login response has the token expiry (seconds, Unix or RFC 3339 time)")
+	cmd.Flags().StringVar(&authInject, "inject", "header:Authorization", "How to send the token: header:<name>, query:<name> or cookie:<name>")
+}
+
+func printLoginFlags(cmd *cobra.Command) {
+	printFlag(cmd, "login-method", "", "Login request method (default POST)")
+	printFlag(cmd, "login-path", "", "Login request path, e.g. /auth/login")
+	printFlag(cmd, "login-body", "", "Login request body; use {{$env.NAME}} for credentials")
+	printFlag(cmd, "token-from", "", "Where the login response has the token: body:<path>, header:<name> or cookie:<name>")
+	printFlag(cmd, "expires-from", "", "Where the login response has the token expiry")
+	printFlag(cmd, "inject", "", "How to send the token: header:<name>, query:<name> or cookie:<name>")
+}
+
+func addOAuth2Flags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&authTokenURL, "token-url", "", "OAuth2 token endpoint URL")
+	cmd.Flags().StringVar(&authClientID, "client-id", "", "OAuth2 client ID")
+	cmd.Flags().StringVar(&authClientSecret, "client-secret", "", "OAuth2 client secret")
+	cmd.Flags().StringVar(&authRefreshToken, "refresh-token", "", "OAuth2 refresh token (uses the refresh_token grant)")
+	cmd.Flags().StringVar(&authScopes, "scopes", "", "OAuth2 scopes, space- or comma-separated")
+	cmd.Flags().StringVar(&authAudience, "audience", "", "OAuth2 audience")
+	cmd.Flags().StringVar(&authAuthorizeURL, "authorize-url", "", "OAuth2 authorization URL (signs in through the browser)")
+	cmd.Flags().StringVar(&authRedirectURL, "redirect-url", "", "OAuth2 loopback redirect URL (default random port)")
+}
+
+func printOAuth2Flags(cmd *cobra.Command) {
+	printFlag(cmd, "token-url", "", "OAuth2 token endpoint URL")
+	printFlag(cmd, "client-id", "", "OAuth2 client ID")
+	printFlag(cmd, "client-secret", "", "OAuth2 client secret")
+	printFlag(cmd, "refresh-token", "", "OAuth2 refresh token (uses the refresh_token grant)")
+	printFlag(cmd, "scopes", "", "OAuth2 scopes, space- or comma-separated")
+	printFlag(cmd, "audience", "", "OAuth2 audience")
+	printFlag(cmd, "authorize-url", "", "OAuth2 authorization URL (signs in through the browser)")
+	printFlag(cmd, "redirect-url", "", "OAuth2 loopback redirect URL (default random port)")
 }
 
 func createAuthConfig() *storage.AuthConfig {
@@ -457,6 +771,23 @@ func createAuthConfig() *storage.AuthConfig {
 	case "basic":
 		config.Username = authUser
 		config.Password = authPass
+	case "oauth2":
+		config.TokenURL = authTokenURL
+		config.ClientID = authClientID
+		config.ClientSecret = authClientSecret
+		config.RefreshToken = authRefreshToken
+		config.Scopes = strings.Join(auth.ParseScopes(authScopes), " ")
+		config.Audience = authAudience
+		config.AuthorizeURL = authAuthorizeURL
+		config.RedirectURL = authRedirectURL
+		config.GrantType = cli.OAuth2FromConfig(config).GrantType
+	case "login":
+		flow := loginFlow(authLoginMethod, authLoginPath, authLoginBody, authTokenFrom, authExpiresFrom, authInject)
+		config.Login = &flow
+	case "sigv4":
+		config.SigV4 = sigV4Auth(authAccessKey, authSecretKey, authSessionToken, authRegion, authService)
+	case "hmac":
+		config.HMAC = hmacAuth(authKeyID, authSecret, authSign, authSignHeaders, authHMACAlgorithm, authHMACEncoding, authTimestampFormat)
 	}
 
 	return config
@@ -485,6 +816,8 @@ func loadAndStartProject(project *storage.Project) {
 		authProvider = buildAuthFromFlags()
 	} else if authEnv, exists := os.LookupEnv(authTypeEnvVar); exists && authEnv != "" {
 		authProvider = buildAuthFromEnvironments()
+	} else if identityName != "" {
+		authProvider = identityAuth(project)
 	} else if project.HasAuth() {
 		authProvider = buildAuthFromProject(project)
 		fmt.Printf("✓ Using saved authentication (%s)\n", project.AuthConfig.Type)
@@ -619,6 +952,24 @@ func promptNewProjectInteractive() bool {
 			Location: authData["location"],
 			Username: authData["username"],
 			Password: authData["password"],
+
+			GrantType:    authData["grant_type"],
+			TokenURL:     authData["token_url"],
+			ClientID:     authData["client_id"],
+			ClientSecret: authData["client_secret"],
+			RefreshToken: authData["refresh_token"],
+			Scopes:       authData["scopes"],
+			Audience:     authData["audience"],
+			AuthorizeURL: authData["authorize_url"],
+			RedirectURL:  authData["redirect_url"],
+		}
+		switch authType {
+		case "login":
+			project.AuthConfig.Login = cli.LoginFlowFromForm(authData)
+		case "sigv4":
+			project.AuthConfig.SigV4 = cli.SigV4FromForm(authData)
+		case "hmac":
+			project.AuthConfig.HMAC = cli.HMACFromForm(authData)
 		}
 		_ = storage.SaveProject(project)
 	}
@@ -701,6 +1052,8 @@ func loadAndStartConversation(project *storage.Project, conversation *storage.Co
 		authProvider = buildAuthFromFlags()
 	} else if authEnv, exists := os.LookupEnv(authTypeEnvVar); exists && authEnv != "" {
 		authProvider = buildAuthFromEnvironments()
+	} else if identityName != "" {
+		authProvider = identityAuth(project)
 	} else if project.HasAuth() {
 		authProvider = buildAuthFromProject(project)
 		fmt.Printf("✓ Using saved authentication (%s)\n", project.AuthConfig.Type)
@@ -758,7 +1111,7 @@ func main() {
 	skipOnboarding := false
 	if len(os.Args) > 1 {
 		cmd := os.Args[1]
-		if cmd == "test" || cmd == "scan" || cmd == "help" || cmd == "version" || cmd == "update" {
+		if cmd == "test" || cmd == "scan" || cmd == "secrets" || cmd == "mock" || cmd == "record" || cmd == "lint" || cmd == "help" || cmd == "version" || cmd == "update" {
 			skipOnboarding = true
 		}
 	}
@@ -772,6 +1125,10 @@ func main() {
 		}
 	}
 
+	if needsSecrets(os.Args) {
+		unlockSecrets()
+	}
+
 	if version != "dev" {
 		checkForUpdate(version)
 	}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Octrafic/octrafic-cli/internal/core/parser"
+	"github.com/Octrafic/octrafic-cli/internal/infra/storage"
+	"github.com/Octrafic/octrafic-cli/internal/mock"
+	"github.com/spf13/cobra"
+)
+
+var (
+	mockHost     string
+	mockPort     int
+	mockConfig   string
+	mockStateful bool
+)
+
+var mockCmd = &cobra.Command{
+	Use:   "mock",
+	Short: "Serve a mock API generated from the specification",
+	Run: func(cmd *cobra.Command, args []string) {
+		path := specFile
+		if path == "" && projectName != "" {
+			project, err := storage.FindProjectByName(projectName)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Project '%s' not found\n", projectName)
+				os.Exit(1)
+			}
+			path = project.SpecPath
+		}
+		if path == "" {
+			fmt.Fprintf(os.Stderr, "Error: Specification file (-s, --spec) or project name (-n, --name) is required\n")
+			os.Exit(1)
+		}
+
+		spec, err := parser.ParseSpecification(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to parse specification: %v\n", err)
+			os.Exit(1)
+		}
+
+		cfg := &mock.Config{}
+		if mockConfig != "" {
+			if cfg, err = mock.LoadConfig(mockConfig); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if mockStateful {
+			cfg.Stateful = true
+		}
+
+		server := mock.NewServer(spec.Endpoints, cfg)
+		if len(server.Endpoints()) == 0 {
+			fmt.Fprintf(os.Stderr, "Error: No REST endpoints found in %s\n", path)
+			os.Exit(1)
+		}
+		server.Log = func(method, path string, status int, elapsed time.Duration) {
+			fmt.Printf("%s %s → %d (%dms)\n", method, path, status, elapsed.Milliseconds())
+		}
+
+		addr := net.JoinHostPort(mockHost, strconv.Itoa(mockPort))
+		mode := "stateless"
+		if cfg.Stateful {
+			mode = "stateful"
+		}
+		fmt.Printf("Mocking %d endpoints from %s (%s) on http://%s\n\n", len(server.Endpoints()), path, mode, addr)
+
+		httpServer := &http.Server{Addr: addr, Handler: server, ReadHeaderTimeout: 10 * time.Second}
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func printMockHelp(cmd *cobra.Command) {
+	fmt.Printf("Serve a mock API generated from the specification\n\n")
+	fmt.Printf("Usage:\n  %s\n\n", cmd.UseLine())
+
+	fmt.Printf("Source:\n")
+	printFlag(cmd, "spec", "s", "Path to API specification file (OpenAPI/Swagger)")
+	printFlag(cmd, "name", "n", "Use the specification of a saved project")
+
+	fmt.Printf("\nServer:\n")
+	printFlag(cmd, "host", "", "Interface to listen on (default 127.0.0.1)")
+	printFlag(cmd, "port", "p", "Port to listen on (default 4010)")
+
+	fmt.Printf("\nBehaviour:\n")
+	printFlag(cmd, "stateful", "", "Store created resources and serve them back (simple CRUD)")
+	printFlag(cmd, "config", "c", "YAML/JSON file with latency, error injection and status overrides per endpoint")
+
+	fmt.Printf("\nLearn more: https://github.com/Octrafic/octrafic-cli\n")
+}
+
+func init() {
+	mockCmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		printMockHelp(cmd)
+	})
+	mockCmd.SetUsageFunc(func(cmd *cobra.Command) error {
+		printMockHelp(cmd)
+		return nil
+	})
+
+	rootCmd.AddCommand(mockCmd)
+	mockCmd.Flags().StringVarP(&specFile, "spec", "s", "", "Path to API specification file (OpenAPI/Swagger)")
+	mockCmd.Flags().StringVarP(&projectName, "name", "n", "", "Use the specification of a saved project")
+	mockCmd.Flags().StringVar(&mockHost, "host", "127.0.0.1", "Interface to listen on")
+	mockCmd.Flags().IntVarP(&mockPort, "port", "p", 4010, "Port to listen on")
+	mockCmd.Flags().BoolVar(&mockStateful, "stateful", false, "Store created resources and serve them back (simple CRUD)")
+	mockCmd.Flags().StringVarP(&mockConfig, "config", "c", "", "YAML/JSON file with latency, error injection and status overrides per endpoint")
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/Octrafic/octrafic-cli/internal/infra/storage"
+	"github.com/Octrafic/octrafic-cli/internal/recorder"
+	"github.com/spf13/cobra"
+)
+
+var (
+	recordHost   string
+	recordPort   int
+	recordSuite  string
+	recordOutput string
+)
+
+var recordCmd = &cobra.Command{
+	Use:   "record",
+	Short: "Record live API traffic through a proxy and save it as a test suite",
+	Run: func(cmd *cobra.Command, args []string) {
+		var project *storage.Project
+		if projectName != "" {
+			var err error
+			if project, err = storage.FindProjectByName(projectName); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Project '%s' not found\n", projectName)
+				os.Exit(1)
+			}
+		}
+		if project == nil && recordOutput == "" {
+			fmt.Fprintf(os.Stderr, "Error: Project name (-n, --name) or output file (-o, --output) is required\n")
+			os.Exit(1)
+		}
+
+		target := apiURL
+		if target == "" && project != nil {
+			target = project.BaseURL
+		}
+		if target == "" {
+			fmt.Fprintf(os.Stderr, "Error: API URL is required (-u, --url)\n")
+			os.Exit(1)
+		}
+
+		if recordSuite == "" {
+			recordSuite = "recorded-" + time.Now().Format("20060102-150405")
+		}
+		if err := storage.ValidateSuiteName(recordSuite); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		proxy, err := recorder.NewProxy(target)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		proxy.Log = func(ex recorder.Exchange) {
+			fmt.Printf("%s %s → %d (%dms)\n", ex.Method, ex.Path, ex.Status, ex.Duration.Milliseconds())
+		}
+
+		addr := net.JoinHostPort(recordHost, strconv.Itoa(recordPort))
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		httpServer := &http.Server{Handler: proxy, ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				stop()
+			}
+		}()
+
+		fmt.Printf("Recording traffic to %s\n", target)
+		fmt.Printf("Point your client at http://%s, or use it as HTTP proxy (HTTP_PROXY=http://%s).\n", addr, addr)
+		fmt.Printf("Press Ctrl+C to stop and save.\n\n")
+
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+
+		exchanges := proxy.Exchanges()
+		tests := recorder.BuildTests(exchanges)
+		fmt.Printf("\n\nRecorded %d requests → %d tests\n", len(exchanges), len(tests))
+		if len(tests) == 0 {
+			fmt.Println("Nothing to save.")
+			return
+		}
+
+		chained := 0
+		for _, tc := range tests {
+			chained += len(tc.Extract)
+		}
+		now := time.Now()
+		suite := &storage.TestSuite{
+			Name:      recordSuite,
+			Source:    "recorded",
+			BaseURL:   target,
+			CreatedAt: now,
+			UpdatedAt: now,
+			Tests:     tests,
+		}
+
+		if recordOutput != "" {
+			data, err := json.MarshalIndent(suite, "", "  ")
+			if err == nil {
+				err = os.WriteFile(recordOutput, data, 0644)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Failed to write suite: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("✓ Saved to %s (%d chained variables)\n", recordOutput, chained)
+			fmt.Printf("Replay: octrafic test --suite %s\n", recordOutput)
+			return
+		}
+
+		if err := storage.SaveTestSuite(project.ID, project.IsTemporary, suite); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Saved suite '%s' in project '%s' (%d chained variables)\n", suite.Name, project.Name, chained)
+		fmt.Printf("Replay: octrafic test -n %s --suite %s\n", project.Name, suite.Name)
+	},
+}
+
+func printRecordHelp(cmd *cobra.Command) {
+	fmt.Printf("Record live API traffic through a proxy and save it as a test suite\n\n")
+	fmt.Printf("Usage:\n  %s\n\n", cmd.UseLine())
+
+	fmt.Printf("Target:\n")
+	printFlag(cmd, "name", "n", "Project to save the suite in (its base URL is the default target)")
+	printFlag(cmd, "url", "u", "Base URL of the API to record")
+
+	fmt.Printf("\nProxy:\n")
+	printFlag(cmd, "host", "", "Interface to listen on (default 127.0.0.1)")
+	printFlag(cmd, "port", "p", "Port to listen on (default 8888)")
+
+	fmt.Printf("\nOutput:\n")
+	printFlag(cmd, "suite", "", "Suite name (default recorded-<timestamp>)")
+	printFlag(cmd, "output", "o", "Write the suite to a file instead of the project")
+
+	fmt.Printf("\nLearn more: https://github.com/Octrafic/octrafic-cli\n")
+}
+
+func init() {
+	recordCmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		printRecordHelp(cmd)
+	})
+	recordCmd.SetUsageFunc(func(cmd *cobra.Command) error {
+		printRecordHelp(cmd)
+		return nil
+	})
+
+	rootCmd.AddCommand(recordCmd)
+	recordCmd.Flags().StringVarP(&projectName, "name", "n", "", "Project to save the suite in (its base URL is the default target)")
+	recordCmd.Flags().StringVarP(&apiURL, "url", "u", "", "Base URL of the API to record")
+	recordCmd.Flags().StringVar(&recordHost, "host", "127.0.0.1", "Interface to listen on")
+	recordCmd.Flags().IntVarP(&recordPort, "port", "p", 8888, "Port to listen on")
+	recordCmd.Flags().StringVar(&recordSuite, "suite", "", "Suite name (default recorded-<timestamp>)")
+	recordCmd.Flags().StringVarP(&recordOutput, "output", "o", "", "Write the suite to a file instead of the project")
+}
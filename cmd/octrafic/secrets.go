@@ -0,0 +1,336 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	internalConfig "github.com/Octrafic/octrafic-cli/internal/config"
+	"github.com/Octrafic/octrafic-cli/internal/infra/logger"
+	"github.com/Octrafic/octrafic-cli/internal/infra/secrets"
+	"github.com/Octrafic/octrafic-cli/internal/infra/storage"
+	"github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+)
+
+var rotatePassphrase bool
+
+var secretsCmd = &cobra.Command{
+	Use:   "secrets",
+	Short: "List, rotate and remove saved credentials",
+	Run: func(cmd *cobra.Command, args []string) {
+		printSecretsHelp(cmd)
+	},
+}
+
+var secretsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved credentials and where they are used",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		backend := requireSecretsBackend()
+		refs := secretRefs()
+		fmt.Printf("Backend: %s\n\n", backend.Name())
+
+		if vault, ok := backend.(*secrets.Vault); ok {
+			// Entries nothing refers to any more, e.g. of deleted projects.
+			keys, _ := vault.Keys()
+			for _, key := range keys {
+				if !slices.ContainsFunc(refs, func(r storage.SecretRef) bool { return r.Key == key }) {
+					refs = append(refs, storage.SecretRef{Key: key, Field: "(unused)"})
+				}
+			}
+		}
+		if len(refs) == 0 {
+			fmt.Println("No saved credentials.")
+		} else {
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+			_, _ = fmt.Fprintln(w, "KEY\tUSED BY")
+			for _, ref := range refs {
+				_, _ = fmt.Fprintf(w, "%s\t%s\n", ref.Key, secretUsage(ref))
+			}
+			_ = w.Flush()
+		}
+
+		if plaintext := plaintextSecretOwners(); len(plaintext) > 0 {
+			fmt.Printf("\n⚠️  Still stored in plain text: %s\n", strings.Join(plaintext, ", "))
+		}
+	},
+}
+
+var secretsRotateCmd = &cobra.Command{
+	Use:   "rotate [key]",
+	Short: "Replace a saved credential, or the vault passphrase with --passphrase",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		backend := requireSecretsBackend()
+		if rotatePassphrase {
+			vault, ok := backend.(*secrets.Vault)
+			if !ok {
+				fmt.Fprintf(os.Stderr, "Error: The %s has no passphrase\n", backend.Name())
+				os.Exit(1)
+			}
+			passphrase := readSecret("New passphrase: ")
+			if passphrase == "" || readSecret("Repeat passphrase: ") != passphrase {
+				fmt.Fprintf(os.Stderr, "Error: Passphrases are empty or do not match\n")
+				os.Exit(1)
+			}
+			if err := vault.ChangePassphrase(passphrase); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Failed to change passphrase: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println("✓ Vault passphrase changed")
+			return
+		}
+
+		if len(args) == 0 {
+			fmt.Fprintf(os.Stderr, "Error: Secret key is required (see octrafic secrets list)\n")
+			os.Exit(1)
+		}
+		key := args[0]
+		if !slices.ContainsFunc(secretRefs(), func(r storage.SecretRef) bool { return r.Key == key }) {
+			fmt.Fprintf(os.Stderr, "Error: No saved credential uses '%s' (see octrafic secrets list)\n", key)
+			os.Exit(1)
+		}
+		value := readSecret(fmt.Sprintf("New value for %s: ", key))
+		if value == "" {
+			fmt.Fprintf(os.Stderr, "Error: Value must not be empty\n")
+			os.Exit(1)
+		}
+		if err := backend.Set(key, value); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to store secret: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Rotated %s\n", key)
+	},
+}
+
+var secretsRemoveCmd = &cobra.Command{
+	Use:   "remove <key>",
+	Short: "Delete a saved credential and clear the settings that use it",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		backend := requireSecretsBackend()
+		key := args[0]
+
+		// Load before deleting so the API key still resolves.
+		cfg, cfgErr := internalConfig.Load()
+		if err := backend.Delete(key); err != nil && !errors.Is(err, secrets.ErrNotFound) {
+			fmt.Fprintf(os.Stderr, "Error: Failed to delete secret: %v\n", err)
+			os.Exit(1)
+		}
+
+		var cleared []string
+		if cfgErr == nil && cfg.APIKeyRef() == secrets.Ref(key) {
+			cfg.APIKey = ""
+			if err := cfg.Save(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Failed to save config: %v\n", err)
+				os.Exit(1)
+			}
+			cleared = append(cleared, "LLM API key")
+		}
+		projects, err := storage.ListProjects()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to list projects: %v\n", err)
+			os.Exit(1)
+		}
+		for _, project := range projects {
+			if !project.ClearSecretRef(key) {
+				continue
+			}
+			if err := storage.SaveProject(project); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Failed to save project '%s': %v\n", project.Name, err)
+				os.Exit(1)
+			}
+			cleared = append(cleared, "project "+project.Name)
+		}
+
+		fmt.Printf("✓ Removed %s\n", key)
+		if len(cleared) > 0 {
+			fmt.Printf("  Cleared from: %s\n", strings.Join(cleared, ", "))
+		}
+	},
+}
+
+// unlockSecrets asks for the vault passphrase when credentials are kept in
+// the encrypted file, then moves plain-text credentials out of config.json
+// and project.json.
+func unlockSecrets() {
+	vault, ok := secrets.Default().(*secrets.Vault)
+	if ok && vault.Locked() && term.IsTerminal(os.Stdin.Fd()) {
+		if vault.Exists() || len(plaintextSecretOwners()) > 0 {
+			promptVaultPassphrase(vault)
+		}
+	}
+	migrateSecrets()
+}
+
+func promptVaultPassphrase(vault *secrets.Vault) {
+	if !vault.Exists() {
+		fmt.Fprintf(os.Stderr, "Saved credentials will be encrypted in %s.\n", vault.Path())
+		passphrase := readSecret("Choose a passphrase (empty to skip): ")
+		if passphrase == "" {
+			return
+		}
+		if readSecret("Repeat passphrase: ") != passphrase {
+			fmt.Fprintf(os.Stderr, "Passphrases do not match; credentials stay unencrypted for now.\n")
+			return
+		}
+		_ = vault.Unlock(passphrase)
+		return
+	}
+
+	for range 3 {
+		passphrase := readSecret(fmt.Sprintf("Passphrase for %s: ", vault.Path()))
+		if passphrase == "" {
+			return
+		}
+		err := vault.Unlock(passphrase)
+		if err == nil {
+			return
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+}
+
+// migrateSecrets moves plain-text credentials into the secret store once it
+// can be written to.
+func migrateSecrets() {
+	backend := secrets.Default()
+	if backend == nil {
+		return
+	}
+	if vault, ok := backend.(*secrets.Vault); ok && vault.Locked() {
+		return
+	}
+
+	moved := 0
+	if cfg, err := internalConfig.Load(); err == nil && cfg.HasPlaintextAPIKey() {
+		if err := cfg.Save(); err == nil && !cfg.HasPlaintextAPIKey() {
+			moved++
+		}
+	}
+	projects, err := storage.MigrateSecrets()
+	if err != nil {
+		logger.Warn("Failed to move project credentials to the secret store", logger.Err(err))
+	}
+	moved += projects
+	if moved > 0 {
+		fmt.Fprintf(os.Stderr, "✓ Moved saved credentials to the %s\n", backend.Name())
+	}
+}
+
+// needsSecrets reports whether a command line may read saved credentials.
+func needsSecrets(args []string) bool {
+	if len(args) > 1 {
+		switch args[1] {
+		case "mock", "record", "help", "version", "update", "completion":
+			return false
+		}
+	}
+	return !slices.ContainsFunc(args[1:], func(arg string) bool {
+		return arg == "-h" || arg == "--help" || arg == "-v" || arg == "--version"
+	})
+}
+
+func requireSecretsBackend() secrets.Backend {
+	backend := secrets.Default()
+	if backend == nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", secrets.ErrDisabled)
+		os.Exit(1)
+	}
+	if vault, ok := backend.(*secrets.Vault); ok && vault.Locked() {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", secrets.ErrLocked)
+		os.Exit(1)
+	}
+	return backend
+}
+
+// secretRefs returns every reference in config.json and the saved projects.
+func secretRefs() []storage.SecretRef {
+	var refs []storage.SecretRef
+	if cfg, err := internalConfig.Load(); err == nil && secrets.IsRef(cfg.APIKeyRef()) {
+		refs = append(refs, storage.SecretRef{Key: secrets.Key(cfg.APIKeyRef()), Field: "LLM API key"})
+	}
+	projects, _ := storage.ListProjects()
+	for _, project := range projects {
+		refs = append(refs, project.SecretRefs()...)
+	}
+	return refs
+}
+
+// plaintextSecretOwners names the files that still hold plain-text
+// credentials.
+func plaintextSecretOwners() []string {
+	var owners []string
+	if cfg, err := internalConfig.Load(); err == nil && cfg.HasPlaintextAPIKey() {
+		owners = append(owners, "LLM API key")
+	}
+	projects, _ := storage.ListProjects()
+	for _, project := range projects {
+		if project.HasPlaintextSecrets() {
+			owners = append(owners, "project "+project.Name)
+		}
+	}
+	return owners
+}
+
+func secretUsage(ref storage.SecretRef) string {
+	if ref.Owner == "" {
+		return ref.Field
+	}
+	return ref.Owner + ": " + ref.Field
+}
+
+// readSecret reads a value without echo from the terminal, or a line from
+// piped stdin.
+func readSecret(prompt string) string {
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		data, _ := io.ReadAll(os.Stdin)
+		return strings.TrimRight(string(data), "\r\n")
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	value, _ := term.ReadPassword(os.Stdin.Fd())
+	fmt.Fprintln(os.Stderr)
+	return string(value)
+}
+
+func printSecretsHelp(cmd *cobra.Command) {
+	fmt.Printf("List, rotate and remove saved credentials\n\n")
+	fmt.Printf("Usage:\n  octrafic secrets list\n  octrafic secrets rotate <key>\n  octrafic secrets rotate --passphrase\n  octrafic secrets remove <key>\n\n")
+
+	fmt.Printf("Credentials of projects and the LLM API key are kept in the OS keyring\n")
+	fmt.Printf("(macOS Keychain, Secret Service on Linux). Without one they are encrypted\n")
+	fmt.Printf("in ~/.octrafic/secrets.vault with a passphrase asked for at startup.\n\n")
+
+	fmt.Printf("Credentials given as env:NAME, file:PATH or cmd:COMMAND (e.g. cmd:op read\n")
+	fmt.Printf("op://dev/api/token) are saved as written and resolved on each use; command\n")
+	fmt.Printf("output is cached for 5 minutes.\n\n")
+
+	fmt.Printf("Flags:\n")
+	printFlag(secretsRotateCmd, "passphrase", "", "Change the vault passphrase (rotate)")
+
+	fmt.Printf("\nEnvironment:\n")
+	fmt.Printf("  %-28s %s\n", secrets.BackendEnv, "keyring, file or none (keep credentials in the project files)")
+	fmt.Printf("  %-28s %s\n", secrets.PassphraseEnv, "Vault passphrase for non-interactive use")
+
+	fmt.Printf("\nLearn more: https://github.com/Octrafic/octrafic-cli\n")
+}
+
+func init() {
+	secretsCmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		printSecretsHelp(cmd)
+	})
+	secretsCmd.SetUsageFunc(func(cmd *cobra.Command) error {
+		printSecretsHelp(cmd)
+		return nil
+	})
+
+	rootCmd.AddCommand(secretsCmd)
+	secretsCmd.AddCommand(secretsListCmd, secretsRotateCmd, secretsRemoveCmd)
+	secretsRotateCmd.Flags().BoolVar(&rotatePassphrase, "passphrase", false, "Change the vault passphrase")
+}
@@ -1,15 +1,20 @@
 package main
 
 import (
+	"cmp"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/Octrafic/octrafic-cli/internal/cli"
 	internalConfig "github.com/Octrafic/octrafic-cli/internal/config"
 	"github.com/Octrafic/octrafic-cli/internal/core/analyzer"
+	"github.com/Octrafic/octrafic-cli/internal/core/auth"
 	"github.com/Octrafic/octrafic-cli/internal/core/parser"
 	"github.com/Octrafic/octrafic-cli/internal/infra/storage"
+	"github.com/Octrafic/octrafic-cli/internal/recorder"
 	"github.com/Octrafic/octrafic-cli/internal/runner"
 	"github.com/spf13/cobra"
 )
@@ -19,17 +24,48 @@ var (
 	testPrompt  string
 	testEnvFile string
 	testAuto    bool
+	testSuite   string
+	testProject string
+
+	testAuthzMatrix string
+
+	testReplay bool
+	testHosts  []string
+
+	testPostmanEnv string
 )
 
 var testCmd = &cobra.Command{
 	Use:   "test",
 	Short: "Run API tests automatically without the interactive UI",
 	Run: func(cmd *cobra.Command, args []string) {
-		if testPath == "" && testPrompt == "" {
-			fmt.Println("Error: You must provide a file path (--path) or a prompt (--prompt)")
+		if testPath == "" && testPrompt == "" && testSuite == "" && testAuthzMatrix == "" {
+			fmt.Println("Error: You must provide a file path (--path), a prompt (--prompt), a suite (--suite) or an access matrix (--authz-matrix)")
 			os.Exit(1)
 		}
 
+		// The authorization matrix uses the project's identities, not the LLM.
+		if testAuthzMatrix != "" {
+			os.Exit(runAuthzMatrix())
+		}
+
+		// Saved suites, HAR replays, Postman collections and pytest files run
+		// without the LLM.
+		if testSuite != "" {
+			os.Exit(runSavedSuite())
+		}
+		if testReplay {
+			os.Exit(runHARReplay())
+		}
+		if testPath != "" && strings.EqualFold(filepath.Ext(testPath), ".json") {
+			if content, err := os.ReadFile(testPath); err == nil && parser.IsPostmanCollection(content) {
+				os.Exit(runPostmanCollection(content))
+			}
+		}
+		if testPath != "" && strings.EqualFold(filepath.Ext(testPath), ".py") {
+			os.Exit(runPytestFile())
+		}
+
 		if !internalConfig.HasValidLLMConfig() {
 			fmt.Fprintln(os.Stderr, "Error: missing LLM configuration.")
 			fmt.Fprintln(os.Stderr, "Please run 'octrafic' to complete interactive onboarding, or configure via environment variables (e.g., OCTRAFIC_PROVIDER, OCTRAFIC_API_KEY, OCTRAFIC_MODEL).")
@@ -90,18 +126,20 @@ var testCmd = &cobra.Command{
 			// Simple logic to select parser if no spec explicit
 			if strings.HasSuffix(testPath, ".sh") {
 				specContent, parseErr = parser.ParseShellScript(testPath)
-			} else {
-				// Assumes Postman or other JSON content for now
+			} else if strings.EqualFold(filepath.Ext(testPath), ".har") {
 				rawContent, fileErr := os.ReadFile(testPath)
 				if fileErr != nil {
 					fmt.Printf("Error reading test file: %v\n", fileErr)
 					os.Exit(1)
 				}
-				// Format detection handles Postman internally
+				specContent, parseErr = parser.ParseHAR(rawContent, testHosts)
+			} else {
+				// Format detection handles Postman, Insomnia, Bruno (a directory
+				// or bruno.json) and .http files internally
 				specContent, parseErr = parser.ParseSpecification(testPath)
 				if parseErr != nil {
 					// Fallback attempt to force postman if detection failed
-					if strings.Contains(string(rawContent), "postman") {
+					if rawContent, fileErr := os.ReadFile(testPath); fileErr == nil && strings.Contains(string(rawContent), "postman") {
 						// Note: parsePostman is not exported from parser package in Go.
 						// The main ParseSpecification should handle it if _postman_id or schema matches.
 						fmt.Printf("Error: File not recognized as valid API Specification or Postman Collection.\n")
@@ -119,6 +157,13 @@ var testCmd = &cobra.Command{
 				os.Exit(1)
 			}
 
+			if warning := cli.AuthMismatch(parser.SecuritySchemes(specContent.Endpoints), authProvider); warning != "" {
+				fmt.Fprintf(os.Stderr, "⚠️  %s\n", warning)
+			}
+			if warning := cli.TokenExpiryWarning(len(specContent.Endpoints), authProvider); warning != "" {
+				fmt.Fprintf(os.Stderr, "⚠️  %s\n", warning)
+			}
+
 			opts := runner.Options{
 				BaseURL:      apiURL,
 				AuthProvider: authProvider,
@@ -130,28 +175,295 @@ var testCmd = &cobra.Command{
 	},
 }
 
+// runSavedSuite replays a suite saved in a project (--name) or a suite file.
+// The URL defaults to the one recorded in the suite, then the project's, and
+// the project's saved authentication is used unless --auth is given.
+func runSavedSuite() int {
+	var project *storage.Project
+	if testProject != "" {
+		var err error
+		if project, err = storage.FindProjectByName(testProject); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Project '%s' not found\n", testProject)
+			return 1
+		}
+	}
+
+	var suite *storage.TestSuite
+	var err error
+	if info, statErr := os.Stat(testSuite); statErr == nil && !info.IsDir() {
+		suite, err = storage.LoadTestSuiteFile(testSuite)
+	} else if project != nil {
+		suite, err = storage.LoadTestSuite(project.ID, project.IsTemporary, testSuite)
+	} else {
+		err = fmt.Errorf("suite file %q not found; use -n, --name to load a suite saved in a project", testSuite)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	baseURL := apiURL
+	if baseURL == "" {
+		baseURL = suite.BaseURL
+	}
+	if baseURL == "" && project != nil {
+		baseURL = project.BaseURL
+	}
+	if baseURL == "" {
+		fmt.Fprintf(os.Stderr, "Error: API URL is required (-u, --url)\n")
+		return 1
+	}
+
+	authProvider := buildAuthFromFlags()
+	if authType == "none" && project != nil {
+		authProvider = buildAuthFromProject(project)
+	}
+	var identities map[string]auth.AuthProvider
+	if project != nil {
+		var err error
+		if identities, err = cli.IdentityProviders(project); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to load identities: %v\n", err)
+			return 1
+		}
+		if identityName != "" {
+			authProvider = identityAuth(project)
+		}
+	}
+	if err := authProvider.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Invalid authentication configuration: %v\n", err)
+		return 1
+	}
+
+	if warning := cli.TokenExpiryWarning(len(suite.Tests), authProvider); warning != "" {
+		fmt.Fprintf(os.Stderr, "⚠️  %s\n", warning)
+	}
+	fmt.Printf("Suite: %s\n", suite.Name)
+	return runner.RunSuite(suite.Tests, runner.Options{
+		BaseURL:      baseURL,
+		AuthProvider: authProvider,
+		Identities:   identities,
+	})
+}
+
+// runHARReplay replays the requests of a HAR file (--path) in order,
+// expecting the recorded status codes. Values one response returned and a
+// later request sent are chained, as in recorded suites. The URL defaults to
+// the host of the first request.
+func runHARReplay() int {
+	if !strings.EqualFold(filepath.Ext(testPath), ".har") {
+		fmt.Fprintf(os.Stderr, "Error: --replay requires a HAR file (--path)\n")
+		return 1
+	}
+	content, err := os.ReadFile(testPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading test file: %v\n", err)
+		return 1
+	}
+	entries, err := parser.ReadHAR(content, testHosts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	tests := recorder.BuildTests(recorder.FromHAR(entries))
+	if len(tests) == 0 {
+		fmt.Printf("No requests to replay in %s.\n", testPath)
+		return 1
+	}
+
+	baseURL := apiURL
+	if baseURL == "" {
+		baseURL = entries[0].URL.Scheme + "://" + entries[0].URL.Host
+	}
+	if len(testHosts) == 0 && slices.ContainsFunc(entries, func(e parser.HAREntry) bool { return e.URL.Host != entries[0].URL.Host }) {
+		fmt.Fprintf(os.Stderr, "⚠️  The HAR file has requests to several hosts; all are sent to %s. Use --hosts to keep only the API's.\n", baseURL)
+	}
+
+	authProvider := buildAuthFromFlags()
+	if err := authProvider.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Invalid authentication configuration: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Replaying %d requests from %s\n", len(tests), testPath)
+	return runner.RunSuite(tests, runner.Options{
+		BaseURL:      baseURL,
+		AuthProvider: authProvider,
+	})
+}
+
+// runPostmanCollection runs the requests of a Postman collection (--path) in
+// order, with the checks of its test scripts and the variables they set.
+// Whatever could not be translated is reported first. The URL defaults to
+// the collection's, and its authentication is used unless --auth is given.
+func runPostmanCollection(content []byte) int {
+	var environment []byte
+	if testPostmanEnv != "" {
+		var err error
+		if environment, err = os.ReadFile(testPostmanEnv); err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading Postman environment: %v\n", err)
+			return 1
+		}
+	}
+	col, err := parser.ReadPostman(content, environment)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	tests := recorder.FromPostman(col)
+	if len(tests) == 0 {
+		fmt.Printf("No requests in %s.\n", testPath)
+		return 1
+	}
+	for _, msg := range col.Report {
+		fmt.Fprintf(os.Stderr, "⚠️  %s\n", msg)
+	}
+
+	baseURL := apiURL
+	if i := slices.IndexFunc(col.Requests, func(r parser.PostmanRequest) bool { return r.BaseURL != "" }); i >= 0 && baseURL == "" {
+		baseURL = col.Requests[i].BaseURL
+	}
+	if baseURL == "" {
+		fmt.Fprintf(os.Stderr, "Error: API URL is required (-u, --url)\n")
+		return 1
+	}
+
+	authProvider := buildAuthFromFlags()
+	if i := slices.IndexFunc(col.Requests, func(r parser.PostmanRequest) bool { return r.Auth != nil }); i >= 0 && authType == "none" {
+		cfg, err := cli.PostmanAuthConfig(col.Requests[i].Auth)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v; use --auth\n", err)
+			return 1
+		}
+		// The collection may come from anyone: its credentials are sent as
+		// written, never resolved as env:, file:, cmd: or secret: references.
+		authProvider = cli.ImportedAuthFromConfig(cfg, baseURL)
+	}
+	if err := authProvider.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Invalid authentication configuration: %v (set the credentials with --environment or --auth)\n", err)
+		return 1
+	}
+
+	if warning := cli.TokenExpiryWarning(len(tests), authProvider); warning != "" {
+		fmt.Fprintf(os.Stderr, "⚠️  %s\n", warning)
+	}
+	fmt.Printf("Collection: %s\n", col.Name)
+	return runner.RunSuite(tests, runner.Options{
+		BaseURL:      baseURL,
+		AuthProvider: authProvider,
+	})
+}
+
+// runPytestFile runs the test functions of a pytest file (--path), such as
+// one written by the pytest exporter. Without --auth, the credentials are
+// read from the environment variables the file reads.
+func runPytestFile() int {
+	file, err := parser.ReadPytestFile(testPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	for _, msg := range file.Report {
+		fmt.Fprintf(os.Stderr, "⚠️  %s\n", msg)
+	}
+	tests := recorder.FromPytest(file)
+
+	baseURL := cmp.Or(apiURL, file.BaseURL)
+	if baseURL == "" {
+		fmt.Fprintf(os.Stderr, "Error: API URL is required (-u, --url)\n")
+		return 1
+	}
+
+	authProvider := buildAuthFromFlags()
+	if i := slices.IndexFunc(file.Tests, func(t parser.PytestTest) bool { return t.AuthType != "" }); i >= 0 && authType == "none" {
+		if authProvider, err = pytestAuth(file.Tests[i].AuthType, file.APIKeyName); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v; set it or use --auth\n", err)
+			return 1
+		}
+	}
+	if err := authProvider.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Invalid authentication configuration: %v\n", err)
+		return 1
+	}
+
+	if warning := cli.TokenExpiryWarning(len(tests), authProvider); warning != "" {
+		fmt.Fprintf(os.Stderr, "⚠️  %s\n", warning)
+	}
+	return runner.RunSuite(tests, runner.Options{
+		BaseURL:      baseURL,
+		AuthProvider: authProvider,
+	})
+}
+
+// pytestAuth returns the credentials of the exported pytest variables
+// AUTH_TOKEN, API_KEY_VALUE or AUTH_USER and AUTH_PASS.
+func pytestAuth(kind, keyName string) (auth.AuthProvider, error) {
+	env := func(names ...string) ([]string, error) {
+		values := make([]string, len(names))
+		for i, name := range names {
+			if values[i] = os.Getenv(name); values[i] == "" {
+				return nil, fmt.Errorf("the pytest file reads its credentials from $%s, which is not set", name)
+			}
+		}
+		return values, nil
+	}
+	switch kind {
+	case "bearer":
+		v, err := env("AUTH_TOKEN")
+		if err != nil {
+			return nil, err
+		}
+		return auth.NewBearerAuth(v[0]), nil
+	case "apikey":
+		v, err := env("API_KEY_VALUE")
+		if err != nil {
+			return nil, err
+		}
+		if keyName == "" {
+			return nil, fmt.Errorf("the pytest file does not set API_KEY_NAME")
+		}
+		return auth.NewAPIKeyAuth(keyName, v[0], "header"), nil
+	case "basic":
+		v, err := env("AUTH_USER", "AUTH_PASS")
+		if err != nil {
+			return nil, err
+		}
+		return auth.NewBasicAuth(v[0], v[1]), nil
+	default:
+		return nil, fmt.Errorf("the pytest file uses unsupported authentication %q", kind)
+	}
+}
+
 func printTestHelp(cmd *cobra.Command) {
 	fmt.Printf("Run API tests automatically without the interactive UI\n\n")
 	fmt.Printf("Usage:\n  %s\n\n", cmd.UseLine())
 
 	fmt.Printf("Test execution:\n")
-	printFlag(cmd, "path", "p", "Path to the test file to execute (Postman collection, sh script, pytest file)")
+	printFlag(cmd, "path", "p", "Path to the test file to execute (Postman, Insomnia or Bruno collection, .http file, HAR file, sh script, pytest file)")
+	printFlag(cmd, "replay", "", "Replay a HAR file (--path) in order, expecting the recorded status codes")
+	printFlag(cmd, "hosts", "", "Only use HAR requests to these hosts, e.g. api.example.com,*.example.com")
 	printFlag(cmd, "prompt", "", "Instruct the LLM to generate and run specific tests")
+	printFlag(cmd, "suite", "", "Replay a saved test suite by name (with --name) or file path")
+	printFlag(cmd, "name", "n", "Project whose saved suite to replay")
+	printFlag(cmd, "authz-matrix", "", "Run every endpoint as every identity of the project (--name) and compare to this allow/deny table")
 
 	fmt.Printf("\nCore Flags:\n")
 	printFlag(cmd, "url", "u", "Base URL of the API to test")
 	printFlag(cmd, "spec", "s", "Path to API specification file (OpenAPI/Swagger)")
 
 	fmt.Printf("\nAuthentication:\n")
-	printFlag(cmd, "auth", "", "Authentication type: none|bearer|apikey|basic")
+	printFlag(cmd, "auth", "", "Authentication type: none|bearer|apikey|basic|oauth2|login|sigv4|hmac")
 	printFlag(cmd, "token", "", "Bearer token value")
 	printFlag(cmd, "key", "", "API key header name (e.g., X-API-Key)")
 	printFlag(cmd, "value", "", "API key value")
 	printFlag(cmd, "user", "", "Username for basic authentication")
 	printFlag(cmd, "pass", "", "Password for basic authentication")
+	printOAuth2Flags(cmd)
+	printLoginFlags(cmd)
+	printSigningFlags(cmd)
+	printFlag(cmd, "identity", "", "Named identity of the project to run as")
 
 	fmt.Printf("\nEnvironment:\n")
 	printFlag(cmd, "env", "e", "Path to .env file for environment variables")
+	printFlag(cmd, "environment", "", "Postman environment file whose values override the collection variables")
 
 	fmt.Printf("\nAdvanced:\n")
 	printFlag(cmd, "auto", "a", "Run in Auto-Execute mode without manual confirmation (default true for test cmd)")
@@ -170,19 +482,29 @@ func init() {
 	})
 
 	rootCmd.AddCommand(testCmd)
-	testCmd.Flags().StringVarP(&testPath, "path", "p", "", "Path to the test file to execute (Postman collection, sh script, pytest file)")
+	testCmd.Flags().StringVarP(&testPath, "path", "p", "", "Path to the test file to execute (Postman, Insomnia or Bruno collection, .http file, HAR file, sh script, pytest file)")
+	testCmd.Flags().BoolVar(&testReplay, "replay", false, "Replay a HAR file (--path) in order, expecting the recorded status codes")
+	testCmd.Flags().StringSliceVar(&testHosts, "hosts", nil, "Only use HAR requests to these hosts, e.g. api.example.com,*.example.com")
 	testCmd.Flags().StringVar(&testPrompt, "prompt", "", "Instruct the LLM to generate and run specific tests")
 	testCmd.Flags().StringVarP(&testEnvFile, "env", "e", "", "Path to .env file for environment variables")
+	testCmd.Flags().StringVar(&testPostmanEnv, "environment", "", "Postman environment file whose values override the collection variables")
+	testCmd.Flags().StringVar(&testSuite, "suite", "", "Replay a saved test suite by name (with --name) or file path")
+	testCmd.Flags().StringVarP(&testProject, "name", "n", "", "Project whose saved suite to replay")
+	testCmd.Flags().StringVar(&testAuthzMatrix, "authz-matrix", "", "Run every endpoint as every identity of the project (--name) and compare to this allow/deny table")
+	testCmd.Flags().StringVar(&identityName, "identity", "", "Named identity of the project to run as")
 	testCmd.Flags().BoolVarP(&testAuto, "auto", "a", true, "Run in Auto-Execute mode without manual confirmation (default true for test cmd)")
 
 	// Inherit core and auth flags for the test command so they are directly accessible
 	testCmd.Flags().StringVarP(&apiURL, "url", "u", "", "Base URL of the API to test")
 	testCmd.Flags().StringVarP(&specFile, "spec", "s", "", "Path to API specification file (OpenAPI/Swagger)")
-	testCmd.Flags().StringVar(&authType, "auth", "none", "Authentication type: none|bearer|apikey|basic")
+	testCmd.Flags().StringVar(&authType, "auth", "none", "Authentication type: none|bearer|apikey|basic|oauth2|login|sigv4|hmac")
 	testCmd.Flags().StringVar(&authToken, "token", "", "Bearer token value")
 	testCmd.Flags().StringVar(&authKey, "key", "", "API key header name (e.g., X-API-Key)")
 	testCmd.Flags().StringVar(&authValue, "value", "", "API key value")
 	testCmd.Flags().StringVar(&authUser, "user", "", "Username for basic authentication")
 	testCmd.Flags().StringVar(&authPass, "pass", "", "Password for basic authentication")
+	addOAuth2Flags(testCmd)
+	addLoginFlags(testCmd)
+	addSigningFlags(testCmd)
 	testCmd.Flags().StringVar(&debugFilePath, "debug-file", "", "Path to debug log file (enables detailed logging)")
 }
@@ -17,8 +17,9 @@ const (
 )
 
 type Agent struct {
-	baseAgent *BaseAgent
-	baseURL   string
+	baseAgent  *BaseAgent
+	baseURL    string
+	identities []string
 }
 
 type TestStatus string
@@ -268,3 +269,8 @@ Requirements:
 func (a *Agent) SetBaseURL(baseURL string) {
 	a.baseURL = baseURL
 }
+
+// SetIdentities sets the names of the identities tests can run as.
+func (a *Agent) SetIdentities(names []string) {
+	a.identities = names
+}
@@ -2,6 +2,8 @@ package agent
 
 import (
 	"fmt"
+
+	"github.com/Octrafic/octrafic-cli/internal/infra/secrets"
 	"github.com/Octrafic/octrafic-cli/internal/llm/common"
 )
 
@@ -25,7 +27,7 @@ func (a *BaseAgent) Chat(systemPrompt string, tools []common.Tool, inputMessages
 	for _, msg := range inputMessages {
 		commonMsg := common.Message{
 			Role:             msg.Role,
-			Content:          msg.Content,
+			Content:          secrets.Scrub(msg.Content),
 			ReasoningContent: msg.ReasoningContent,
 		}
 
@@ -42,7 +44,7 @@ func (a *BaseAgent) Chat(systemPrompt string, tools []common.Tool, inputMessages
 			commonMsg.FunctionResponse = &common.FunctionResponseData{
 				ID:       msg.FunctionResponse.ID,
 				Name:     msg.FunctionResponse.Name,
-				Response: msg.FunctionResponse.Response,
+				Response: scrubResponse(msg.FunctionResponse.Response),
 			}
 		}
 
@@ -90,7 +92,7 @@ func (a *BaseAgent) ChatStream(systemPrompt string, tools []common.Tool, inputMe
 	for _, msg := range inputMessages {
 		commonMsg := common.Message{
 			Role:             msg.Role,
-			Content:          msg.Content,
+			Content:          secrets.Scrub(msg.Content),
 			ReasoningContent: msg.ReasoningContent,
 		}
 
@@ -107,7 +109,7 @@ func (a *BaseAgent) ChatStream(systemPrompt string, tools []common.Tool, inputMe
 			commonMsg.FunctionResponse = &common.FunctionResponseData{
 				ID:       msg.FunctionResponse.ID,
 				Name:     msg.FunctionResponse.Name,
-				Response: msg.FunctionResponse.Response,
+				Response: scrubResponse(msg.FunctionResponse.Response),
 			}
 		}
 
@@ -151,3 +153,12 @@ func (a *BaseAgent) ChatStream(systemPrompt string, tools []common.Tool, inputMe
 
 	return chatResp, nil
 }
+
+// scrubResponse hides resolved credentials, e.g. echoed in a response body,
+// before a tool result is sent to the LLM.
+func scrubResponse(response map[string]any) map[string]any {
+	if response == nil {
+		return nil
+	}
+	return secrets.ScrubValue(response).(map[string]any)
+}
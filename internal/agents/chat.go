@@ -2,6 +2,7 @@ package agent
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/Octrafic/octrafic-cli/internal/llm/common"
 )
@@ -49,12 +50,20 @@ func getMainAgentTools() []common.Tool {
 }
 
 func (a *Agent) Chat(messages []ChatMessage, thinkingEnabled bool, endpointsList ...string) (*ChatResponse, error) {
-	systemPrompt := buildSystemPrompt(a.baseURL, endpointsList...)
+	systemPrompt := buildSystemPrompt(a.baseURL, a.identities, endpointsList...)
 	tools := getMainAgentTools()
 	return a.baseAgent.Chat(systemPrompt, tools, messages, thinkingEnabled)
 }
 
-func buildSystemPrompt(baseURL string, endpointsList ...string) string {
+func buildSystemPrompt(baseURL string, identities []string, endpointsList ...string) string {
+	identitiesInfo := ""
+	if len(identities) > 0 {
+		identitiesInfo = fmt.Sprintf(`
+
+# Identities
+Saved identities (test as one with "identity"): %s, anonymous`, strings.Join(identities, ", "))
+	}
+
 	endpointsInfo := ""
 	if len(endpointsList) > 0 && endpointsList[0] != "" {
 		endpointsInfo = fmt.Sprintf(`
@@ -73,7 +82,7 @@ API testing assistant that helps users explore endpoints, generate tests, and an
 - Default to "happy path" tests unless user specifies otherwise
 
 # Available Context
-Base URL: %s%s
+Base URL: %s%s%s
 
 HTTP methods: GET, POST, PUT, DELETE, PATCH
 Authentication: Bearer token (JWT), Basic auth, API Key header
@@ -114,10 +123,70 @@ Use assertions to verify specific fields in the response body:
 {"field":"token","op":"contains","value":"Bearer"} — substring
 Operators: eq, neq, exists, not_exists, contains, gt, gte, lt, lte
 
+### Polling asynchronous operations
+For endpoints that finish later (e.g. 202 Accepted + status URL), add poll to the test that checks the status:
+{"method":"GET","endpoint":"/jobs/{{job_id}}","expected_status":200,"poll":{"until":[{"field":"status","op":"eq","value":"done"}],"interval_seconds":2,"timeout_seconds":60,"message":"job did not complete"},...}
+- The request repeats until every until assertion passes (and until_status matches, if set) or the timeout expires
+- Result includes poll_attempts, poll_satisfied and poll_failures; a timeout fails the test
+
+### Callbacks and webhooks
+get_endpoints_details lists callbacks an endpoint sends; spec webhooks appear as /webhooks/<name> and cannot be called directly.
+To verify a callback, pass {{$webhookUrl}} as the callback URL and add webhook to the same test:
+{"method":"POST","endpoint":"/payments","body":"{\"amount\":10,\"callback_url\":\"{{$webhookUrl}}/payments\"}","expected_status":202,"webhook":{"name":"paymentStatus","path":"/payments","until":[{"field":"body.status","op":"eq","value":"paid"}],"timeout_seconds":30},...}
+- Assertion fields: body.<field>, headers.<lowercase-name>, method, path, query
+- Result includes webhook_received, webhook_satisfied, webhook_callback and webhook_failures; no matching callback in time fails the test
+- {{$webhookUrl}} is local; set OCTRAFIC_WEBHOOK_URL to a public tunnel URL when the API runs elsewhere
+
+### Paginated lists
+To check that a list endpoint paginates correctly, add paginate to the test:
+{"method":"GET","endpoint":"/users","expected_status":200,"paginate":{"style":"cursor","page_size":20,"max_pages":5,"aggregate":[{"field":"id","op":"unique"},{"field":"created_at","op":"sorted_desc"}]},...}
+- style is link, cursor, page or offset; omit it to auto-detect
+- Aggregate ops: unique, sorted_asc, sorted_desc on item fields; count, pages and total with the usual operators
+- Result includes pages, item_count, pagination_issues (duplicates, skipped items, broken cursors) and aggregate_failures; any of them fails the test
+
+### Saved test suites
+Projects can hold saved suites, e.g. recorded from live traffic with octrafic record.
+- load_test_suite without a name lists them; with a name returns its tests (extract, assertions and expected_status included)
+- To refine a suite, adjust the tests, run them with ExecuteTestGroup, then save_test_suite with the same name if the user wants to keep the changes
+- Recorded assertions come from one session; replace eq on values that are not really fixed with exists
+
+### GraphQL operations
+GraphQL endpoints are listed as /graphql/<field>; get_endpoints_details returns a ready query with variables.
+Send them as POST to the GraphQL endpoint with a graphql object instead of body:
+{"method":"POST","endpoint":"/graphql","graphql":{"query":"query User($id: ID!) { user(id: $id) { id name } }","variables":{"id":"1"},"field":"user"},"expected_status":200,"assertions":[{"field":"user.name","op":"exists"}],...}
+- A 200 response with an "errors" array is a failure (graphql_errors in the result)
+- extract and assertion fields are relative to "data"
+
+### AsyncAPI channels
+AsyncAPI operations are listed as PUBLISH (clients send to the API) or SUBSCRIBE (the API sends) on the channel address; get_endpoints_details returns an asyncapi section with protocol, servers and message payloads.
+- Channels with an HTTP binding are plain requests with an HTTP method
+- WebSocket channels (protocol ws or wss) are tested with a websocket object:
+{"method":"PUBLISH","endpoint":"/rooms/1","websocket":{"send":[{"text":"hi"}],"receive":1,"timeout_seconds":10},"expected_status":101,"assertions":[{"field":"messages.0.text","op":"eq","value":"hi"}],...}
+- Kafka, AMQP, MQTT and other channels are listed as /channels/<address> for documentation and test planning only; they cannot be executed
+
+### Identities
+When the project has named identities (e.g. admin, member, readonly), set identity on a test to send it with that identity's credentials:
+{"method":"DELETE","endpoint":"/users/{{user_id}}","identity":"readonly","expected_status":403,...}
+- "anonymous" sends no credentials; without identity, requires_auth decides as usual
+- To check access control, run the same request as several identities with the status each one should get
+- A lower-privileged identity getting 2xx where it should get 401/403 is a privilege escalation — report it prominently
+
 ## ExportTests
 Export API tests to formats strictly when the user requests it (e.g. "save to postman", "export tests to sh file"). 
 Can export combinations of "postman", "pytest" or "sh".
 
+## probe_jwt
+When authentication uses a JWT bearer token and the user asks about token security, call probe_jwt on a protected endpoint the token can access.
+- The request is sent with the real token first; if it is not accepted, probe a different endpoint
+- Any tampered variant that gets 2xx means the API does not verify that part of the token — report it prominently
+- Results are recorded as security findings in the project
+
+## lint_spec
+When the user asks about the quality of the spec itself (e.g. "is my spec any good?", "what's missing from the docs?"), call lint_spec.
+- Findings are about the document, not the running API; no requests are sent
+- Summarize by severity, errors first, group repeated findings by rule and suggest concrete fixes with their locations
+- Rules can be configured in .octrafic-lint.yaml beside the spec; the same check runs headlessly with octrafic lint
+
 ## wait
 Wait N seconds before proceeding. Use when:
 - You receive a 429 status code
@@ -133,12 +202,13 @@ IMPORTANT: Only call this when the user explicitly asks for a report (e.g. "gene
 - User says "test X" → fetch details, generate & run tests
 - User says "list endpoints" → show list from available endpoints (no tool call)
 - User says "generate report" / "save PDF" / "export report" → call GenerateReport
+- User asks whether the spec is good or complete → call lint_spec
 - After 429 response → call wait(seconds=N) where N comes from Retry-After header or default to 5
-- requires_auth=true → CLI adds auth header, requires_auth=false → no auth`, baseURL, endpointsInfo)
+- requires_auth=true → CLI adds auth header, requires_auth=false → no auth`, baseURL, identitiesInfo, endpointsInfo)
 }
 
 func (a *Agent) ChatStream(messages []ChatMessage, thinkingEnabled bool, callback ReasoningCallback, endpointsList ...string) (*ChatResponse, error) {
-	systemPrompt := buildSystemPrompt(a.baseURL, endpointsList...)
+	systemPrompt := buildSystemPrompt(a.baseURL, a.identities, endpointsList...)
 	tools := getMainAgentTools()
 	return a.baseAgent.ChatStream(systemPrompt, tools, messages, thinkingEnabled, callback)
 }
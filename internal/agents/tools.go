@@ -13,6 +13,10 @@ const (
 	ToolExportTests         = "ExportTests"
 	ToolGenerateReport      = "GenerateReport"
 	ToolWait                = "wait"
+	ToolLoadTestSuite       = "load_test_suite"
+	ToolSaveTestSuite       = "save_test_suite"
+	ToolProbeJWT            = "probe_jwt"
+	ToolLintSpec            = "lint_spec"
 )
 
 // ToolMeta holds the LLM-facing definition together with UI display hints.
@@ -32,7 +36,7 @@ var registry = []ToolMeta{
 		WidgetTitle: "Getting endpoint details",
 		Definition: common.Tool{
 			Name:        ToolGetEndpointsDetails,
-			Description: "Get detailed information about specified endpoints including description, parameters, security, request body, and responses.",
+			Description: "Get detailed information about specified endpoints including operationId, tags, deprecation, parameters (schemas, enums, defaults, examples), security, request bodies per media type with named examples, responses, and AsyncAPI channel details (protocol, servers, messages).",
 			InputSchema: map[string]any{
 				"type":                 "object",
 				"additionalProperties": false,
@@ -118,6 +122,10 @@ var registry = []ToolMeta{
 									"type":        "boolean",
 									"description": "Whether authentication is required for this test",
 								},
+								"identity": map[string]any{
+									"type":        []any{"string", "null"},
+									"description": "Named identity to send the request as (see Identities). Overrides requires_auth; \"anonymous\" sends no credentials. Omit to use the current authentication.",
+								},
 								"expected_status": map[string]any{
 									"type":        "integer",
 									"description": "Expected HTTP status code. Set correctly: 201 for POST creating resources, 204 for DELETE, 400 for bad input, 401 for unauthorized, 404 for not found.",
@@ -147,6 +155,103 @@ var registry = []ToolMeta{
 										"required": []string{"field", "op"},
 									},
 								},
+								"graphql": map[string]any{
+									"type":        []any{"object", "null"},
+									"description": "GraphQL operation to send instead of body. The request is POSTed to endpoint (e.g. /graphql). A response with an \"errors\" array fails the test, and assertion/extract fields are relative to \"data\".",
+									"properties": map[string]any{
+										"query":          map[string]any{"type": "string", "description": "GraphQL document with selection set"},
+										"variables":      map[string]any{"type": []any{"object", "null"}, "description": "Operation variables"},
+										"operation_name": map[string]any{"type": []any{"string", "null"}, "description": "Operation name when the document has several"},
+										"field":          map[string]any{"type": []any{"string", "null"}, "description": "Root field, used to validate the response against the schema"},
+									},
+									"required": []string{"query"},
+								},
+								"websocket": map[string]any{
+									"type":        []any{"object", "null"},
+									"description": "Connect to a WebSocket channel (AsyncAPI ws/wss) instead of sending a request. Messages in send are sent after connecting, then up to receive messages are collected. expected_status is 101; the response body is {\"messages\": [...], \"count\": n}.",
+									"properties": map[string]any{
+										"send":            map[string]any{"type": []any{"array", "null"}, "description": "Messages to send, JSON-encoded unless strings", "items": map[string]any{}},
+										"receive":         map[string]any{"type": []any{"integer", "null"}, "description": "Number of messages to wait for"},
+										"timeout_seconds": map[string]any{"type": []any{"number", "null"}, "description": "Stop waiting after this many seconds (default 10)"},
+									},
+								},
+								"poll": map[string]any{
+									"type":        []any{"object", "null"},
+									"description": "Repeat the request until a condition holds, for asynchronous operations (e.g. poll a job status URL after 202 Accepted). expected_status and assertions are checked on the final response.",
+									"properties": map[string]any{
+										"until": map[string]any{
+											"type":        []any{"array", "null"},
+											"description": "Assertions that must all pass to stop polling (same format as assertions)",
+											"items": map[string]any{
+												"type": "object",
+												"properties": map[string]any{
+													"field": map[string]any{"type": "string"},
+													"op":    map[string]any{"type": "string"},
+													"value": map[string]any{},
+												},
+												"required": []string{"field", "op"},
+											},
+										},
+										"until_status":     map[string]any{"type": []any{"integer", "null"}, "description": "Status code that must be returned to stop polling"},
+										"interval_seconds": map[string]any{"type": []any{"number", "null"}, "description": "Delay between attempts (default 2)"},
+										"timeout_seconds":  map[string]any{"type": []any{"number", "null"}, "description": "Give up after this many seconds (default 60)"},
+										"message":          map[string]any{"type": []any{"string", "null"}, "description": "Failure message when the condition is not met in time"},
+									},
+								},
+								"webhook": map[string]any{
+									"type":        []any{"object", "null"},
+									"description": "Wait for a callback after the request. Send {{$webhookUrl}} (optionally with a path suffix) as the callback URL in the body or headers; the local receiver records every request sent to it. The test fails if no matching callback arrives in time.",
+									"properties": map[string]any{
+										"name":   map[string]any{"type": []any{"string", "null"}, "description": "Callback or webhook name from the spec, used to validate the payload schema"},
+										"path":   map[string]any{"type": []any{"string", "null"}, "description": "Path the callback must be sent to, e.g. /payments for {{$webhookUrl}}/payments"},
+										"method": map[string]any{"type": []any{"string", "null"}, "description": "Expected callback method"},
+										"until": map[string]any{
+											"type":        []any{"array", "null"},
+											"description": "Assertions on the callback: body.<field>, headers.<lowercase-name>, method, path, query",
+											"items": map[string]any{
+												"type": "object",
+												"properties": map[string]any{
+													"field": map[string]any{"type": "string"},
+													"op":    map[string]any{"type": "string"},
+													"value": map[string]any{},
+												},
+												"required": []string{"field", "op"},
+											},
+										},
+										"timeout_seconds": map[string]any{"type": []any{"number", "null"}, "description": "Give up after this many seconds (default 30)"},
+										"message":         map[string]any{"type": []any{"string", "null"}, "description": "Failure message when no matching callback arrives"},
+									},
+								},
+								"paginate": map[string]any{
+									"type":        []any{"object", "null"},
+									"description": "Follow a paginated list across pages (Link header, next cursor/URL, page or offset parameters). Duplicates, skipped items and broken cursors are reported as issues. expected_status and assertions are checked on the first page.",
+									"properties": map[string]any{
+										"style":        map[string]any{"type": []any{"string", "null"}, "description": "link, cursor, page or offset; auto-detected when omitted"},
+										"items_field":  map[string]any{"type": []any{"string", "null"}, "description": "Path to the items array (e.g. data); auto-detected when omitted"},
+										"next_field":   map[string]any{"type": []any{"string", "null"}, "description": "Path to the next cursor or URL (e.g. meta.next_cursor)"},
+										"cursor_param": map[string]any{"type": []any{"string", "null"}, "description": "Query parameter for the cursor (default cursor)"},
+										"page_param":   map[string]any{"type": []any{"string", "null"}, "description": "Query parameter for the page number (default page)"},
+										"offset_param": map[string]any{"type": []any{"string", "null"}, "description": "Query parameter for the offset (default offset)"},
+										"size_param":   map[string]any{"type": []any{"string", "null"}, "description": "Query parameter for the page size (e.g. limit)"},
+										"page_size":    map[string]any{"type": []any{"integer", "null"}, "description": "Items per page sent in size_param"},
+										"max_pages":    map[string]any{"type": []any{"integer", "null"}, "description": "Stop after this many pages (default 10)"},
+										"id_field":     map[string]any{"type": []any{"string", "null"}, "description": "Item field used to detect duplicates (default id)"},
+										"total_field":  map[string]any{"type": []any{"string", "null"}, "description": "Path to the total item count; X-Total-Count is used otherwise"},
+										"aggregate": map[string]any{
+											"type":        []any{"array", "null"},
+											"description": "Assertions over all items: {\"field\":\"id\",\"op\":\"unique\"}, {\"field\":\"created_at\",\"op\":\"sorted_desc\"}, or count/pages/total with the usual operators",
+											"items": map[string]any{
+												"type": "object",
+												"properties": map[string]any{
+													"field": map[string]any{"type": "string"},
+													"op":    map[string]any{"type": "string"},
+													"value": map[string]any{},
+												},
+												"required": []string{"field", "op"},
+											},
+										},
+									},
+								},
 							},
 							"required": []string{"method", "endpoint", "headers", "body", "requires_auth", "expected_status"},
 						},
@@ -212,6 +317,89 @@ var registry = []ToolMeta{
 			},
 		},
 	},
+	{
+		WidgetTitle: "Loading test suite",
+		Definition: common.Tool{
+			Name:        ToolLoadTestSuite,
+			Description: "Load a test suite saved in the project, e.g. one recorded from live traffic with `octrafic record`. Without a name, lists the saved suites. Run the loaded tests (refined as needed) with ExecuteTestGroup.",
+			InputSchema: map[string]any{
+				"type":                 "object",
+				"additionalProperties": false,
+				"properties": map[string]any{
+					"name": map[string]any{
+						"type":        []any{"string", "null"},
+						"description": "Suite name. Omit to list the saved suites.",
+					},
+				},
+			},
+		},
+	},
+	{
+		WidgetTitle: "Saving test suite",
+		Definition: common.Tool{
+			Name:        ToolSaveTestSuite,
+			Description: "Save the tests from the last ExecuteTestGroup call as a named suite in the project, replacing a suite with the same name. Saved suites can be replayed headlessly with `octrafic test --suite`. Call this ONLY when the user asks to save or update a suite.",
+			InputSchema: map[string]any{
+				"type":                 "object",
+				"additionalProperties": false,
+				"properties": map[string]any{
+					"name": map[string]any{
+						"type":        "string",
+						"description": "Suite name (letters, digits, '.', '_' and '-')",
+					},
+				},
+				"required": []string{"name"},
+			},
+		},
+	},
+	{
+		WidgetTitle: "Probing JWT validation",
+		Definition: common.Tool{
+			Name:        ToolProbeJWT,
+			Description: "Check that an endpoint verifies the configured JWT bearer token. Sends the request with the real token, then with tampered variants: modified signature, alg \"none\", expired exp, wrong aud and an elevated role claim. Every variant must be rejected with 401 or 403; each result is recorded as a security finding. Requires bearer authentication with a JWT.",
+			InputSchema: map[string]any{
+				"type":                 "object",
+				"additionalProperties": false,
+				"properties": map[string]any{
+					"method": map[string]any{
+						"type":        "string",
+						"description": "HTTP method of a protected endpoint",
+					},
+					"path": map[string]any{
+						"type":        "string",
+						"description": "Concrete path to request (e.g. /users/me)",
+					},
+					"headers": map[string]any{
+						"type":        []any{"object", "null"},
+						"description": "Additional request headers",
+					},
+					"body": map[string]any{
+						"type":        []any{"object", "null"},
+						"description": "Request body for methods that need one",
+					},
+				},
+				"required": []string{"method", "path"},
+			},
+		},
+	},
+	{
+		WidgetTitle: "Linting specification",
+		Definition: common.Tool{
+			Name:        ToolLintSpec,
+			Description: "Check the quality of the API specification document with the lint rules: missing descriptions, operations without error responses, inconsistent path naming, unused components, missing examples, undefined security schemes, duplicate operationIds and schemas without types. Returns findings with severity, rule, message and location. Only OpenAPI and Swagger specifications can be linted.",
+			InputSchema: map[string]any{
+				"type":                 "object",
+				"additionalProperties": false,
+				"properties": map[string]any{
+					"rules": map[string]any{
+						"type":        []any{"array", "null"},
+						"items":       map[string]any{"type": "string"},
+						"description": "Rule IDs to run (e.g. [\"missing-example\"]). Omit to run every enabled rule.",
+					},
+				},
+			},
+		},
+	},
 	{
 		WidgetTitle: "Waiting",
 		Definition: common.Tool{
@@ -27,18 +27,80 @@ type Assertion struct {
 	Value any    `json:"value,omitempty"`
 }
 
+// GraphQLRequest is a GraphQL operation sent as the body of a test request.
+type GraphQLRequest struct {
+	Query         string         `json:"query"`
+	Variables     map[string]any `json:"variables,omitempty"`
+	OperationName string         `json:"operation_name,omitempty"`
+	Field         string         `json:"field,omitempty"`
+}
+
+// Poll repeats a request until all Until assertions (and UntilStatus, if set)
+// hold, for asynchronous operations that complete later.
+type Poll struct {
+	Until           []Assertion `json:"until,omitempty"`
+	UntilStatus     int         `json:"until_status,omitempty"`
+	IntervalSeconds float64     `json:"interval_seconds,omitempty"`
+	TimeoutSeconds  float64     `json:"timeout_seconds,omitempty"`
+	Message         string      `json:"message,omitempty"`
+}
+
+// Paginate follows a list endpoint across pages and checks the combined
+// items with Aggregate assertions.
+type Paginate struct {
+	Style       string      `json:"style,omitempty"`
+	ItemsField  string      `json:"items_field,omitempty"`
+	NextField   string      `json:"next_field,omitempty"`
+	CursorParam string      `json:"cursor_param,omitempty"`
+	PageParam   string      `json:"page_param,omitempty"`
+	OffsetParam string      `json:"offset_param,omitempty"`
+	SizeParam   string      `json:"size_param,omitempty"`
+	PageSize    int         `json:"page_size,omitempty"`
+	MaxPages    int         `json:"max_pages,omitempty"`
+	IDField     string      `json:"id_field,omitempty"`
+	TotalField  string      `json:"total_field,omitempty"`
+	Aggregate   []Assertion `json:"aggregate,omitempty"`
+}
+
+// WebhookExpectation waits for a callback sent to {{$webhookUrl}} after the
+// request, until one satisfies all Until assertions.
+type WebhookExpectation struct {
+	Name           string      `json:"name,omitempty"` // callback or webhook name in the spec
+	Path           string      `json:"path,omitempty"`
+	Method         string      `json:"method,omitempty"`
+	Until          []Assertion `json:"until,omitempty"`
+	TimeoutSeconds float64     `json:"timeout_seconds,omitempty"`
+	Message        string      `json:"message,omitempty"`
+}
+
+// WebSocketExchange connects to a WebSocket channel instead of sending a
+// plain request, sends Send and waits for up to Receive messages. The
+// response body is {"messages": [...], "count": n} and a successful
+// connection has status 101.
+type WebSocketExchange struct {
+	Send           []any   `json:"send,omitempty"`
+	Receive        int     `json:"receive,omitempty"`
+	TimeoutSeconds float64 `json:"timeout_seconds,omitempty"`
+}
+
 type TestCase struct {
-	ID             int               `json:"id"`
-	Description    string            `json:"description"`
-	Method         string            `json:"method"`
-	Endpoint       string            `json:"endpoint"`
-	Headers        map[string]string `json:"headers,omitempty"`
-	Body           interface{}       `json:"body,omitempty"`
-	ExpectedStatus int               `json:"expected_status"`
-	Reasoning      string            `json:"reasoning"`
-	RequiresAuth   bool              `json:"requires_auth"`
-	Extract        []Extract         `json:"extract,omitempty"`
-	Assertions     []Assertion       `json:"assertions,omitempty"`
+	ID             int                 `json:"id"`
+	Description    string              `json:"description"`
+	Method         string              `json:"method"`
+	Endpoint       string              `json:"endpoint"`
+	Headers        map[string]string   `json:"headers,omitempty"`
+	Body           interface{}         `json:"body,omitempty"`
+	ExpectedStatus int                 `json:"expected_status"`
+	Reasoning      string              `json:"reasoning"`
+	RequiresAuth   bool                `json:"requires_auth"`
+	Identity       string              `json:"identity,omitempty"`
+	Extract        []Extract           `json:"extract,omitempty"`
+	Assertions     []Assertion         `json:"assertions,omitempty"`
+	GraphQL        *GraphQLRequest     `json:"graphql,omitempty"`
+	Poll           *Poll               `json:"poll,omitempty"`
+	Paginate       *Paginate           `json:"paginate,omitempty"`
+	Webhook        *WebhookExpectation `json:"webhook,omitempty"`
+	WebSocket      *WebSocketExchange  `json:"websocket,omitempty"`
 }
 
 // BuildTestPlanPrompt generates tests based on detailed endpoint description
@@ -77,6 +139,38 @@ Test scenarios:
 | true         | false     | No auth      | 401       |
 | false        | false     | No auth      | 200-299   |
 
+# Asynchronous operations
+
+If a response is 202 Accepted with a status URL, add a follow-up test that polls it:
+"poll": {"until": [{"field": "status", "op": "eq", "value": "done"}], "interval_seconds": 2, "timeout_seconds": 60, "message": "job did not finish"}
+
+# Callbacks
+
+Endpoints with callbacks accept a URL the API calls later. Pass "{{$webhookUrl}}" as that URL and add:
+"webhook": {"name": "paymentStatus", "until": [{"field": "body.status", "op": "eq", "value": "paid"}], "timeout_seconds": 30}
+
+# Paginated lists
+
+For list endpoints with pagination, add "paginate" to walk all pages:
+"paginate": {"style": "cursor", "page_size": 20, "max_pages": 5, "aggregate": [{"field": "id", "op": "unique"}, {"field": "count", "op": "gte", "value": 1}]}
+
+# GraphQL
+
+Endpoints with a GraphQL section are operations, not REST paths:
+- method "POST", endpoint = the GraphQL endpoint (e.g. "/graphql")
+- add "graphql": {"query": "...", "variables": {...}, "field": "rootField"}
+- start from the operation's query; adjust variables to the test
+- expected_status is 200 even for failures; errors are detected by the CLI
+
+# AsyncAPI channels
+
+Endpoints with an asyncapi section are message channels:
+- HTTP-bound channels are plain requests with the message payload as body
+- WebSocket channels (protocol ws/wss): keep method and endpoint, add
+  "websocket": {"send": [<payload>], "receive": 1, "timeout_seconds": 10}
+  expected_status is 101; assert on "messages.0.<field>" and "count"
+- Other protocols (kafka, amqp, mqtt, ...) cannot be executed; skip them
+
 # Output Format
 
 Pure JSON, no markdown:
@@ -0,0 +1,240 @@
+// Package authz runs every endpoint as every identity of a project and
+// compares the outcome to an expected allow/deny table.
+package authz
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Octrafic/octrafic-cli/internal/core/parser"
+	"gopkg.in/yaml.v3"
+)
+
+// Access is whether an identity may call an endpoint.
+type Access string
+
+const (
+	Allow   Access = "allow"
+	Deny    Access = "deny"
+	Unclear Access = "unclear" // the response neither grants nor refuses access
+)
+
+// Matrix is the expected access table. It is read from a YAML or JSON file:
+//
+//	identities: [admin, member, anonymous]
+//	methods: [GET, DELETE]
+//	params:
+//	  id: "42"
+//	rules:
+//	  - endpoint: GET /users
+//	    allow: [admin, member]
+//	  - endpoint: DELETE /users/{id}
+//	    allow: [admin]
+//
+// Identities not listed in a rule's allow list are expected to be denied,
+// and the other way around for a rule with only a deny list. "*" stands for
+// every identity.
+//
+// Requests go to the live API, so only GET, HEAD and OPTIONS are sent unless
+// methods lists others: a method that changes data is sent only when listed,
+// for spec endpoints and rules alike.
+type Matrix struct {
+	Identities []string          `yaml:"identities" json:"identities"`
+	Methods    []string          `yaml:"methods" json:"methods"` // methods checked; rules for safe methods always are
+	Params     map[string]string `yaml:"params" json:"params"`   // values for {path} parameters
+	Default    Access            `yaml:"default" json:"default"` // expectation for endpoints without a rule
+	Rules      []Rule            `yaml:"rules" json:"rules"`
+}
+
+// Rule sets the expected access to one endpoint.
+type Rule struct {
+	Endpoint string            `yaml:"endpoint" json:"endpoint"` // "METHOD /path"; the path may be a template
+	Allow    []string          `yaml:"allow" json:"allow"`
+	Deny     []string          `yaml:"deny" json:"deny"`
+	Headers  map[string]string `yaml:"headers" json:"headers"`
+	Body     any               `yaml:"body" json:"body"`
+}
+
+// Case is one request sent as every identity.
+type Case struct {
+	Method       string
+	Path         string // path template, e.g. /users/{id}
+	URL          string // path with parameters filled in
+	Headers      map[string]string
+	Body         any
+	RequiresAuth bool
+	Rule         *Rule
+}
+
+var pathParamPattern = regexp.MustCompile(`\{([^}/]+)\}`)
+
+// LoadMatrix reads an access table file.
+func LoadMatrix(path string) (*Matrix, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read access matrix: %w", err)
+	}
+	m := &Matrix{}
+	if err := yaml.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("failed to parse access matrix: %w", err)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Validate checks the rules and the default expectation.
+func (m *Matrix) Validate() error {
+	switch m.Default {
+	case "", Allow, Deny:
+	default:
+		return fmt.Errorf("invalid default %q: must be 'allow' or 'deny'", m.Default)
+	}
+	for i, r := range m.Rules {
+		if _, _, err := r.methodPath(); err != nil {
+			return fmt.Errorf("rule %d: %w", i+1, err)
+		}
+		for _, name := range r.Allow {
+			if slices.Contains(r.Deny, name) {
+				return fmt.Errorf("rule %d (%s): %s is both allowed and denied", i+1, r.Endpoint, name)
+			}
+		}
+	}
+	return nil
+}
+
+// Expect returns the expected access for an identity, or "" when the table
+// does not say.
+func (m *Matrix) Expect(c Case, identity string, anonymous bool) Access {
+	if c.Rule != nil {
+		return c.Rule.expect(identity)
+	}
+	if m.Default != "" {
+		return m.Default
+	}
+	// The spec alone says that protected endpoints refuse anonymous calls.
+	if anonymous && c.RequiresAuth {
+		return Deny
+	}
+	return ""
+}
+
+// Cases combines the project endpoints with the rules: every endpoint is
+// checked, and rules may add endpoints missing from the spec.
+func (m *Matrix) Cases(endpoints []parser.Endpoint) []Case {
+	var cases []Case
+	used := make([]bool, len(m.Rules))
+	for _, ep := range endpoints {
+		// Webhooks, GraphQL operations and message channels are not plain
+		// requests.
+		if ep.Webhook != nil || ep.GraphQL != nil || (ep.AsyncAPI != nil && !ep.AsyncAPI.IsHTTP()) {
+			continue
+		}
+		c := Case{Method: strings.ToUpper(ep.Method), Path: ep.Path, RequiresAuth: ep.RequiresAuth}
+		if ep.RequestBody != "" {
+			c.Body = ep.RequestBody
+		}
+		if i := m.ruleFor(c.Method, c.Path); i >= 0 {
+			used[i] = true
+			c.Rule = &m.Rules[i]
+		}
+		if !m.checks(c.Method, c.Rule != nil) {
+			continue
+		}
+		cases = append(cases, m.fill(c))
+	}
+	for i := range m.Rules {
+		method, path, _ := m.Rules[i].methodPath()
+		if used[i] || !m.checks(method, true) {
+			continue
+		}
+		cases = append(cases, m.fill(Case{Method: method, Path: path, RequiresAuth: true, Rule: &m.Rules[i]}))
+	}
+	return cases
+}
+
+// Skipped returns the rules for methods that change data but are not listed
+// in Methods, which Cases leaves out.
+func (m *Matrix) Skipped() []Rule {
+	var skipped []Rule
+	for _, r := range m.Rules {
+		if method, _, _ := r.methodPath(); !m.checks(method, true) {
+			skipped = append(skipped, r)
+		}
+	}
+	return skipped
+}
+
+// safeMethods do not change data and are checked without being listed.
+var safeMethods = []string{"GET", "HEAD", "OPTIONS"}
+
+// checks reports whether requests with a method are sent. Rules for safe
+// methods always are; other methods must be listed in Methods.
+func (m *Matrix) checks(method string, rule bool) bool {
+	listed := slices.ContainsFunc(m.Methods, func(mm string) bool { return strings.EqualFold(mm, method) })
+	if !slices.Contains(safeMethods, strings.ToUpper(method)) {
+		return listed
+	}
+	return rule || listed || len(m.Methods) == 0
+}
+
+func (m *Matrix) ruleFor(method, path string) int {
+	for i, r := range m.Rules {
+		rm, rp, _ := r.methodPath()
+		if rm == method && (rp == path || parser.MatchPath(path, rp)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// fill applies rule overrides and substitutes path parameters. A rule with a
+// concrete path keeps its path.
+func (m *Matrix) fill(c Case) Case {
+	path := c.Path
+	if c.Rule != nil {
+		if c.Rule.Body != nil {
+			c.Body = c.Rule.Body
+		}
+		c.Headers = c.Rule.Headers
+		_, path, _ = c.Rule.methodPath()
+	}
+	c.URL = pathParamPattern.ReplaceAllStringFunc(path, func(ref string) string {
+		if v, ok := m.Params[ref[1:len(ref)-1]]; ok {
+			return v
+		}
+		return "1"
+	})
+	return c
+}
+
+func (r Rule) methodPath() (string, string, error) {
+	method, path, ok := strings.Cut(strings.TrimSpace(r.Endpoint), " ")
+	path = strings.TrimSpace(path)
+	if !ok || method == "" || !strings.HasPrefix(path, "/") {
+		return "", "", fmt.Errorf("invalid endpoint %q: use \"METHOD /path\"", r.Endpoint)
+	}
+	return strings.ToUpper(method), path, nil
+}
+
+func (r Rule) expect(identity string) Access {
+	switch {
+	case slices.Contains(r.Allow, identity):
+		return Allow
+	case slices.Contains(r.Deny, identity):
+		return Deny
+	case slices.Contains(r.Allow, "*"):
+		return Allow
+	case slices.Contains(r.Deny, "*"):
+		return Deny
+	case len(r.Allow) > 0:
+		return Deny
+	case len(r.Deny) > 0:
+		return Allow
+	}
+	return ""
+}
@@ -0,0 +1,242 @@
+package authz
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Octrafic/octrafic-cli/internal/core/auth"
+	"github.com/Octrafic/octrafic-cli/internal/core/parser"
+)
+
+// rbacAPI lets admins do anything and members read, except that DELETE
+// /users/{id} wrongly accepts members too.
+func rbacAPI() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		role := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		switch {
+		case r.URL.Path == "/health":
+			w.WriteHeader(http.StatusOK)
+		case role == "":
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.Method == "DELETE" && r.URL.Path == "/users/42":
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method != "GET" && role != "admin":
+			w.WriteHeader(http.StatusForbidden)
+		case r.Method == "POST":
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	})
+}
+
+func TestRun_FindsPrivilegeEscalation(t *testing.T) {
+	server := httptest.NewServer(rbacAPI())
+	defer server.Close()
+
+	m := &Matrix{
+		Methods: []string{"GET", "POST", "DELETE"},
+		Params:  map[string]string{"id": "42"},
+		Rules: []Rule{
+			{Endpoint: "GET /users", Allow: []string{"admin", "member"}},
+			{Endpoint: "POST /users", Allow: []string{"admin"}},
+			{Endpoint: "DELETE /users/{id}", Allow: []string{"admin"}},
+		},
+	}
+	if err := m.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	endpoints := []parser.Endpoint{
+		{Method: "GET", Path: "/health"},
+		{Method: "GET", Path: "/users", RequiresAuth: true},
+		{Method: "DELETE", Path: "/users/{id}", RequiresAuth: true},
+	}
+	cases := m.Cases(endpoints)
+	if len(cases) != 4 || cases[2].URL != "/users/42" || cases[3].Method != "POST" {
+		t.Fatalf("unexpected cases: %+v", cases)
+	}
+
+	identities := []Identity{
+		{Name: "admin", Provider: auth.NewBearerAuth("admin")},
+		{Name: "member", Provider: auth.NewBearerAuth("member")},
+		{Name: "anonymous", Provider: &auth.NoAuth{}, Anonymous: true},
+	}
+	report := Run(server.URL, identities, cases, m)
+
+	health := report.Rows[0].Cells
+	if health[2].Expected != "" || health[2].Actual != Allow {
+		t.Errorf("public endpoint without a rule should have no expectation: %+v", health[2])
+	}
+
+	findings := report.Findings()
+	if len(findings) != 1 {
+		t.Fatalf("expected one finding, got %+v", findings)
+	}
+	f := findings[0]
+	if !f.Escalation || f.Method != "DELETE" || f.Path != "/users/42" || f.Cell.Identity != "member" || f.Cell.StatusCode != 204 {
+		t.Errorf("unexpected finding: %+v", f)
+	}
+
+	// Anonymous is denied by the allow lists and by the spec alone.
+	for _, row := range report.Rows[1:] {
+		if cell := row.Cells[2]; cell.Expected != Deny || cell.Actual != Deny {
+			t.Errorf("%s %s: unexpected anonymous cell %+v", row.Case.Method, row.Case.URL, cell)
+		}
+	}
+}
+
+func TestCases_SendsOnlySafeMethodsByDefault(t *testing.T) {
+	m := &Matrix{Rules: []Rule{
+		{Endpoint: "GET /users", Allow: []string{"admin"}},
+		{Endpoint: "DELETE /users/{id}", Allow: []string{"admin"}},
+	}}
+	cases := m.Cases([]parser.Endpoint{
+		{Method: "POST", Path: "/users"},
+		{Method: "HEAD", Path: "/users"},
+	})
+	if len(cases) != 2 || cases[0].Method != "HEAD" || cases[1].Method != "GET" {
+		t.Errorf("expected only safe methods without methods set, got %+v", cases)
+	}
+	if skipped := m.Skipped(); len(skipped) != 1 || skipped[0].Endpoint != "DELETE /users/{id}" {
+		t.Errorf("expected the DELETE rule reported as skipped, got %+v", skipped)
+	}
+}
+
+func TestRun_DeniedIdentitiesGoFirst(t *testing.T) {
+	// Anyone may delete the user, but only once: later requests get a 404.
+	deleted := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if deleted {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		deleted = true
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	m := &Matrix{
+		Methods: []string{"DELETE"},
+		Rules:   []Rule{{Endpoint: "DELETE /users/42", Allow: []string{"admin"}}},
+	}
+	identities := []Identity{
+		{Name: "admin", Provider: auth.NewBearerAuth("admin")},
+		{Name: "member", Provider: auth.NewBearerAuth("member")},
+	}
+	report := Run(server.URL, identities, m.Cases(nil), m)
+
+	findings := report.Findings()
+	if len(findings) == 0 || !findings[0].Escalation || findings[0].Cell.Identity != "member" {
+		t.Fatalf("expected the member's delete reported as an escalation, got %+v", findings)
+	}
+	if cells := report.Rows[0].Cells; cells[0].Identity != "admin" || cells[1].Identity != "member" {
+		t.Errorf("expected cells in identity order, got %+v", cells)
+	}
+}
+
+func TestRun_RedirectIsNotAccess(t *testing.T) {
+	// Members are sent to the login page instead of getting a 403.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/login":
+			w.WriteHeader(http.StatusOK)
+		case r.Header.Get("Authorization") != "Bearer admin":
+			http.Redirect(w, r, "/login", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	m := &Matrix{Rules: []Rule{{Endpoint: "GET /admin", Allow: []string{"admin"}}}}
+	identities := []Identity{
+		{Name: "admin", Provider: auth.NewBearerAuth("admin")},
+		{Name: "member", Provider: auth.NewBearerAuth("member")},
+	}
+	report := Run(server.URL, identities, []Case{{Method: "GET", URL: "/admin"}}, m)
+
+	cell := report.Rows[0].Cells[1]
+	if cell.StatusCode != http.StatusFound || cell.Actual != Unclear {
+		t.Errorf("expected the redirect reported as unclear, got %+v", cell)
+	}
+	for _, f := range report.Findings() {
+		if f.Escalation {
+			t.Errorf("expected no privilege escalation for a redirect, got %+v", f)
+		}
+	}
+}
+
+func TestRun_CountsUnreachableAPI(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	m := &Matrix{Rules: []Rule{{Endpoint: "GET /admin", Allow: []string{"admin"}}}}
+	identities := []Identity{
+		{Name: "admin", Provider: auth.NewBearerAuth("admin")},
+		{Name: "member", Provider: auth.NewBearerAuth("member")},
+	}
+	report := Run(server.URL, identities, m.Cases(nil), m)
+
+	if got := report.Errors(); got != 2 {
+		t.Errorf("expected both requests counted as errors, got %d", got)
+	}
+	if findings := report.Findings(); len(findings) != 0 {
+		t.Errorf("expected no findings without responses, got %+v", findings)
+	}
+}
+
+func TestLoadMatrix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.yaml")
+	content := `identities: [admin, readonly]
+methods: [GET, PATCH]
+params:
+  id: 7
+rules:
+  - endpoint: patch /orders/{id}
+    deny: [readonly]
+  - endpoint: GET /orders
+    allow: ["*"]
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write matrix: %v", err)
+	}
+	m, err := LoadMatrix(path)
+	if err != nil {
+		t.Fatalf("LoadMatrix failed: %v", err)
+	}
+	cases := m.Cases(nil)
+	m.Methods = []string{"get"}
+	if readOnly := m.Cases([]parser.Endpoint{{Method: "DELETE", Path: "/orders/{id}"}, {Method: "GET", Path: "/orders"}}); len(readOnly) != 1 || readOnly[0].Method != "GET" {
+		t.Errorf("expected only the GET rule without PATCH in methods, got %+v", readOnly)
+	}
+	if skipped := m.Skipped(); len(skipped) != 1 || skipped[0].Endpoint != "patch /orders/{id}" {
+		t.Errorf("expected the PATCH rule reported as skipped, got %+v", skipped)
+	}
+	if len(cases) != 2 || cases[0].Method != "PATCH" || cases[0].URL != "/orders/7" {
+		t.Fatalf("unexpected cases: %+v", cases)
+	}
+	if got := m.Expect(cases[0], "admin", false); got != Allow {
+		t.Errorf("identities missing from a deny-only rule should be allowed, got %q", got)
+	}
+	if got := m.Expect(cases[0], "readonly", false); got != Deny {
+		t.Errorf("expected readonly to be denied, got %q", got)
+	}
+	if got := m.Expect(cases[1], "anonymous", true); got != Allow {
+		t.Errorf("expected * to allow everyone, got %q", got)
+	}
+
+	invalid := []*Matrix{
+		{Default: "maybe"},
+		{Rules: []Rule{{Endpoint: "/users"}}},
+		{Rules: []Rule{{Endpoint: "GET /users", Allow: []string{"a"}, Deny: []string{"a"}}}},
+	}
+	for _, m := range invalid {
+		if err := m.Validate(); err == nil {
+			t.Errorf("expected %+v to be invalid", m)
+		}
+	}
+}
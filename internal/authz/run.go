@@ -0,0 +1,156 @@
+package authz
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+
+	"github.com/Octrafic/octrafic-cli/internal/core/auth"
+	"github.com/Octrafic/octrafic-cli/internal/core/tester"
+)
+
+// Identity is a named set of credentials requests are sent as.
+type Identity struct {
+	Name      string
+	Provider  auth.AuthProvider
+	Anonymous bool // sends no credentials
+}
+
+// Cell is the outcome of one request sent as one identity.
+type Cell struct {
+	Identity   string
+	Expected   Access // "" when the table does not say
+	Actual     Access
+	StatusCode int
+	Err        error
+}
+
+// Escalation reports an identity reaching an endpoint it must be denied.
+func (c Cell) Escalation() bool {
+	return c.Expected == Deny && c.Actual == Allow
+}
+
+// Mismatch reports an outcome that contradicts the table. Unclear outcomes
+// are not mismatches.
+func (c Cell) Mismatch() bool {
+	return c.Expected != "" && c.Actual != Unclear && c.Expected != c.Actual
+}
+
+// Row holds the outcomes of one case, in identity order.
+type Row struct {
+	Case  Case
+	Cells []Cell
+}
+
+// Report is the result of running a matrix.
+type Report struct {
+	Identities []string
+	Rows       []Row
+}
+
+// Finding is a cell that contradicts the table.
+type Finding struct {
+	Method     string
+	Path       string
+	Cell       Cell
+	Escalation bool
+}
+
+// ClassifyAccess maps a status code to an access outcome. 404 counts as
+// denied because many APIs hide resources from callers without access.
+// Redirects are unclear: they often send denied callers to a login page.
+func ClassifyAccess(status int) Access {
+	switch {
+	case status >= 200 && status < 300:
+		return Allow
+	case status == http.StatusUnauthorized || status == http.StatusForbidden || status == http.StatusNotFound:
+		return Deny
+	default:
+		return Unclear
+	}
+}
+
+// Run sends every case as every identity and compares the outcomes to the
+// matrix. Identities expected to be denied go first, so a request that
+// changes data, such as a DELETE, cannot make later requests to the same
+// resource look denied.
+func Run(baseURL string, identities []Identity, cases []Case, m *Matrix) *Report {
+	report := &Report{}
+	executors := make([]*tester.Executor, len(identities))
+	for i, id := range identities {
+		report.Identities = append(report.Identities, id.Name)
+		executors[i] = tester.NewExecutor(baseURL, id.Provider).WithoutRedirects()
+	}
+
+	for _, c := range cases {
+		row := Row{Case: c, Cells: make([]Cell, len(identities))}
+		for i, id := range identities {
+			row.Cells[i] = Cell{Identity: id.Name, Expected: m.Expect(c, id.Name, id.Anonymous), Actual: Unclear}
+		}
+		order := make([]int, len(identities))
+		for i := range order {
+			order[i] = i
+		}
+		slices.SortStableFunc(order, func(a, b int) int {
+			return cmp.Compare(sendRank(row.Cells[a].Expected), sendRank(row.Cells[b].Expected))
+		})
+		for _, i := range order {
+			cell := &row.Cells[i]
+			result, err := executors[i].ExecuteTest(c.Method, c.URL, c.Headers, c.Body, !identities[i].Anonymous)
+			if err != nil {
+				cell.Err = err
+			} else {
+				cell.StatusCode = result.StatusCode
+				cell.Actual = ClassifyAccess(result.StatusCode)
+			}
+		}
+		report.Rows = append(report.Rows, row)
+	}
+	return report
+}
+
+// sendRank orders identities for sending: expected denials, then unknown
+// expectations, then expected access.
+func sendRank(expected Access) int {
+	switch expected {
+	case Deny:
+		return 0
+	case Allow:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// Errors returns the number of requests that got no response.
+func (r *Report) Errors() int {
+	n := 0
+	for _, row := range r.Rows {
+		for _, cell := range row.Cells {
+			if cell.Err != nil {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// Findings returns the cells that contradict the table, privilege
+// escalations first.
+func (r *Report) Findings() []Finding {
+	var escalations, others []Finding
+	for _, row := range r.Rows {
+		for _, cell := range row.Cells {
+			if !cell.Mismatch() {
+				continue
+			}
+			f := Finding{Method: row.Case.Method, Path: row.Case.URL, Cell: cell, Escalation: cell.Escalation()}
+			if f.Escalation {
+				escalations = append(escalations, f)
+			} else {
+				others = append(others, f)
+			}
+		}
+	}
+	return append(escalations, others...)
+}
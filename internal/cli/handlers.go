@@ -75,6 +75,7 @@ func (m *TestUIModel) sendChatMessage(_ string) tea.Cmd {
 
 			endpointsList := ""
 			if m.currentProject != nil {
+				m.localAgent.SetIdentities(m.currentProject.IdentityNames())
 				if endpoints, err := m.loadProjectEndpoints(); err == nil && len(endpoints) > 0 {
 					endpointsList = storage.GetEndpointsList(endpoints)
 				}
@@ -249,15 +250,50 @@ func (m *TestUIModel) executeTool(toolCall agent.ToolCall) tea.Cmd {
 								"requires_auth": ep.RequiresAuth,
 								"auth_type":     ep.AuthType,
 							}
+							if ep.OperationID != "" {
+								result["operation_id"] = ep.OperationID
+							}
+							if ep.Summary != "" && ep.Summary != ep.Description {
+								result["summary"] = ep.Summary
+							}
+							if len(ep.Tags) > 0 {
+								result["tags"] = ep.Tags
+							}
+							if ep.Deprecated {
+								result["deprecated"] = true
+							}
 							if len(ep.Parameters) > 0 {
 								result["parameters"] = ep.Parameters
 							}
+							if len(ep.RequestContent) > 0 {
+								result["request_content"] = ep.RequestContent
+								result["request_body_required"] = ep.RequestBodyRequired
+							}
+							if len(ep.ResponseContent) > 0 {
+								result["response_content"] = ep.ResponseContent
+							}
+							if len(ep.Security) > 0 {
+								result["security"] = securitySummary(ep.Security)
+							}
 							if ep.RequestBody != "" {
 								result["request_body"] = ep.RequestBody
 							}
 							if len(ep.Responses) > 0 {
 								result["responses"] = ep.Responses
 							}
+							if ep.GraphQL != nil {
+								result["graphql"] = ep.GraphQL
+							}
+							if ep.AsyncAPI != nil {
+								result["asyncapi"] = ep.AsyncAPI
+								result["executable"] = ep.AsyncAPI.Executable()
+							}
+							if len(ep.Callbacks) > 0 {
+								result["callbacks"] = ep.Callbacks
+							}
+							if ep.Webhook != nil {
+								result["webhook"] = ep.Webhook
+							}
 							results = append(results, result)
 							break
 						}
@@ -362,6 +398,22 @@ func (m *TestUIModel) executeTool(toolCall agent.ToolCall) tea.Cmd {
 			return m.handleExportTests(toolCall)
 		}
 
+		if toolCall.Name == agent.ToolLoadTestSuite {
+			return m.handleLoadTestSuite(toolCall)
+		}
+
+		if toolCall.Name == agent.ToolSaveTestSuite {
+			return m.handleSaveTestSuite(toolCall)
+		}
+
+		if toolCall.Name == agent.ToolProbeJWT {
+			return m.handleProbeJWT(toolCall)
+		}
+
+		if toolCall.Name == agent.ToolLintSpec {
+			return m.handleLintSpec(toolCall)
+		}
+
 		if toolCall.Name == agent.ToolWait {
 			seconds := 5
 			if s, ok := toolCall.Arguments["seconds"].(float64); ok {
@@ -616,17 +668,23 @@ func (m *TestUIModel) handleToolResult(toolName string, toolID string, result an
 		return nil // No tool_use, so don't send response back
 	}
 
-	if toolName == agent.ToolWait {
+	if toolName == agent.ToolWait || toolName == agent.ToolLoadTestSuite || toolName == agent.ToolSaveTestSuite || toolName == agent.ToolProbeJWT || toolName == agent.ToolLintSpec {
 		if toolID != "" {
 			var resultMap map[string]any
 			if r, ok := result.(map[string]any); ok {
 				resultMap = r
 			}
+			if toolName == agent.ToolProbeJWT {
+				m.showJWTProbeResult(resultMap)
+			}
+			if toolName == agent.ToolLintSpec {
+				m.showLintResult(resultMap)
+			}
 			chatMsg := agent.ChatMessage{
 				Role: "user",
 				FunctionResponse: &agent.FunctionResponseData{
 					ID:       toolID,
-					Name:     agent.ToolWait,
+					Name:     toolName,
 					Response: resultMap,
 				},
 			}
@@ -640,6 +698,79 @@ func (m *TestUIModel) handleToolResult(toolName string, toolID string, result an
 	return nil
 }
 
+// handleLoadTestSuite lists the project's saved suites, or returns the tests
+// of the named one.
+func (m *TestUIModel) handleLoadTestSuite(toolCall agent.ToolCall) tea.Msg {
+	if m.currentProject == nil {
+		return toolResultMsg{toolID: toolCall.ID, toolName: toolCall.Name, err: fmt.Errorf("no project loaded")}
+	}
+
+	name, _ := toolCall.Arguments["name"].(string)
+	if name == "" {
+		names, err := storage.ListTestSuites(m.currentProject.ID, m.currentProject.IsTemporary)
+		if err != nil {
+			return toolResultMsg{toolID: toolCall.ID, toolName: toolCall.Name, err: err}
+		}
+		return toolResultMsg{
+			toolID:   toolCall.ID,
+			toolName: toolCall.Name,
+			result:   map[string]any{"suites": names},
+		}
+	}
+
+	suite, err := storage.LoadTestSuite(m.currentProject.ID, m.currentProject.IsTemporary, name)
+	if err != nil {
+		return toolResultMsg{toolID: toolCall.ID, toolName: toolCall.Name, err: err}
+	}
+	return toolResultMsg{
+		toolID:   toolCall.ID,
+		toolName: toolCall.Name,
+		result: map[string]any{
+			"name":       suite.Name,
+			"source":     suite.Source,
+			"test_count": len(suite.Tests),
+			"tests":      suite.Tests,
+		},
+	}
+}
+
+// handleSaveTestSuite saves the tests of the last executed group as a suite.
+func (m *TestUIModel) handleSaveTestSuite(toolCall agent.ToolCall) tea.Msg {
+	if m.currentProject == nil {
+		return toolResultMsg{toolID: toolCall.ID, toolName: toolCall.Name, err: fmt.Errorf("no project loaded")}
+	}
+	if len(m.lastTestGroup) == 0 {
+		return toolResultMsg{
+			toolID:   toolCall.ID,
+			toolName: toolCall.Name,
+			err:      fmt.Errorf("no executed tests to save. Run the tests with ExecuteTestGroup first"),
+		}
+	}
+
+	name, _ := toolCall.Arguments["name"].(string)
+	suite := &storage.TestSuite{Name: name, Source: "agent", BaseURL: m.baseURL}
+	if existing, err := storage.LoadTestSuite(m.currentProject.ID, m.currentProject.IsTemporary, name); err == nil {
+		suite.CreatedAt = existing.CreatedAt
+		if existing.Source != "" {
+			suite.Source = existing.Source
+		}
+	}
+	for i, testMap := range m.lastTestGroup {
+		tc := testCaseFromMap(testMap)
+		tc.ID = i + 1
+		suite.Tests = append(suite.Tests, *tc)
+	}
+
+	if err := storage.SaveTestSuite(m.currentProject.ID, m.currentProject.IsTemporary, suite); err != nil {
+		return toolResultMsg{toolID: toolCall.ID, toolName: toolCall.Name, err: err}
+	}
+	return toolResultMsg{
+		toolID:   toolCall.ID,
+		toolName: toolCall.Name,
+		result:   map[string]any{"success": true, "name": suite.Name, "test_count": len(suite.Tests)},
+	}
+}
+
 func (m *TestUIModel) loadProjectEndpoints() ([]parser.Endpoint, error) {
 	return storage.LoadEndpoints(m.currentProject.ID, m.currentProject.IsTemporary)
 }
@@ -652,7 +783,7 @@ func (m *TestUIModel) validateResponseSchema(method, path string, statusCode int
 	}
 	statusKey := strconv.Itoa(statusCode)
 	for _, ep := range m.analysis.Specification.Endpoints {
-		if strings.EqualFold(ep.Method, method) && matchPath(ep.Path, path) {
+		if strings.EqualFold(ep.Method, method) && parser.MatchPath(ep.Path, path) {
 			if schema, ok := ep.ResponseSchemas[statusKey]; ok {
 				return tester.ValidateSchema(body, schema)
 			}
@@ -662,26 +793,67 @@ func (m *TestUIModel) validateResponseSchema(method, path string, statusCode int
 	return nil
 }
 
-// matchPath checks if a concrete path matches an OpenAPI path template.
-// Segments wrapped in {} are treated as wildcards.
-func matchPath(template, actual string) bool {
-	if template == actual {
-		return true
+// validateGraphQLSchema checks a GraphQL response against the schema of the
+// operation for the given root field.
+func (m *TestUIModel) validateGraphQLSchema(field, body string) []string {
+	if field == "" || m.analysis == nil || m.analysis.Specification == nil {
+		return nil
 	}
-	tParts := strings.Split(strings.Trim(template, "/"), "/")
-	aParts := strings.Split(strings.Trim(actual, "/"), "/")
-	if len(tParts) != len(aParts) {
-		return false
+	for _, ep := range m.analysis.Specification.Endpoints {
+		if ep.GraphQL != nil && ep.GraphQL.Field == field {
+			if schema, ok := ep.ResponseSchemas["200"]; ok {
+				return tester.ValidateSchema(body, schema)
+			}
+			return nil
+		}
 	}
-	for i, tp := range tParts {
-		if strings.HasPrefix(tp, "{") && strings.HasSuffix(tp, "}") {
-			continue
+	return nil
+}
+
+// validateCallbackSchema checks a received callback against the payload schema
+// of the named callback or webhook, or else of the tested endpoint's callbacks.
+func (m *TestUIModel) validateCallbackSchema(name, method, path string, cb *tester.ReceivedCallback) []string {
+	if cb == nil || m.analysis == nil || m.analysis.Specification == nil {
+		return nil
+	}
+	var candidates []parser.Callback
+	for _, ep := range m.analysis.Specification.Endpoints {
+		if name != "" {
+			if ep.Webhook != nil && ep.Webhook.Name == name {
+				candidates = append(candidates, *ep.Webhook)
+			}
+			for _, c := range ep.Callbacks {
+				if c.Name == name {
+					candidates = append(candidates, c)
+				}
+			}
+		} else if strings.EqualFold(ep.Method, method) && parser.MatchPath(ep.Path, path) {
+			candidates = append(candidates, ep.Callbacks...)
 		}
-		if !strings.EqualFold(tp, aParts[i]) {
-			return false
+	}
+	for _, c := range candidates {
+		if c.Schema != nil && strings.EqualFold(c.Method, cb.Method) {
+			return tester.ValidateSchema(cb.Body, c.Schema)
 		}
 	}
-	return true
+	return nil
+}
+
+// exportPoll converts a test's polling rule for the exporters.
+func exportPoll(p *agent.Poll) *exporter.PollData {
+	if p == nil {
+		return nil
+	}
+	data := &exporter.PollData{
+		UntilStatus:     p.UntilStatus,
+		IntervalSeconds: p.IntervalSeconds,
+		TimeoutSeconds:  p.TimeoutSeconds,
+		Message:         p.Message,
+	}
+	for _, a := range p.Until {
+		data.Until = append(data.Until, exporter.Condition{Field: a.Field, Op: a.Op, Value: a.Value})
+	}
+	return data
 }
 
 func (m *TestUIModel) handleExportTests(toolCall agent.ToolCall) tea.Msg {
@@ -711,6 +883,17 @@ func (m *TestUIModel) handleExportTests(toolCall agent.ToolCall) tea.Msg {
 		}
 	}
 
+	var endpoints []parser.Endpoint
+	if m.currentProject != nil {
+		endpoints, _ = m.loadProjectEndpoints()
+	}
+	operationName := func(method, path string) string {
+		if ep := parser.FindEndpoint(endpoints, method, path); ep != nil {
+			return ep.OperationID
+		}
+		return ""
+	}
+
 	var tests []exporter.TestData
 	if len(m.testGroupResults) > 0 {
 		tests = make([]exporter.TestData, 0, len(m.testGroupResults))
@@ -724,6 +907,7 @@ func (m *TestUIModel) handleExportTests(toolCall agent.ToolCall) tea.Msg {
 			errStr, _ := result["error"].(string)
 
 			testData := exporter.TestData{
+				Name:         operationName(method, endpoint),
 				Method:       method,
 				Endpoint:     endpoint,
 				StatusCode:   statusCode,
@@ -740,18 +924,22 @@ func (m *TestUIModel) handleExportTests(toolCall agent.ToolCall) tea.Msg {
 			requiresAuth := false
 			var headers map[string]string
 			var body interface{}
+			var poll *exporter.PollData
 
 			if test.BackendTest != nil {
 				requiresAuth = test.BackendTest.RequiresAuth
 				headers = test.BackendTest.Headers
 				body = test.BackendTest.Body
+				poll = exportPoll(test.BackendTest.Poll)
 			}
 			testData := exporter.TestData{
+				Name:         operationName(test.Method, test.Endpoint),
 				Method:       test.Method,
 				Endpoint:     test.Endpoint,
 				RequiresAuth: requiresAuth,
 				Headers:      headers,
 				Body:         body,
+				Poll:         poll,
 			}
 			tests = append(tests, testData)
 		}
@@ -765,13 +953,17 @@ func (m *TestUIModel) handleExportTests(toolCall agent.ToolCall) tea.Msg {
 
 	authType := ""
 	authData := make(map[string]string)
+	var projectAuth *storage.AuthConfig
 	if m.currentProject != nil && m.currentProject.AuthConfig != nil {
-		authType = m.currentProject.AuthConfig.Type
-		authData["token"] = m.currentProject.AuthConfig.Token
-		authData["key_name"] = m.currentProject.AuthConfig.KeyName
-		authData["key_value"] = m.currentProject.AuthConfig.KeyValue
-		authData["username"] = m.currentProject.AuthConfig.Username
-		authData["password"] = m.currentProject.AuthConfig.Password
+		projectAuth, _ = m.currentProject.AuthConfig.Resolve()
+	}
+	if projectAuth != nil {
+		authType = projectAuth.Type
+		authData["token"] = projectAuth.Token
+		authData["key_name"] = projectAuth.KeyName
+		authData["key_value"] = projectAuth.KeyValue
+		authData["username"] = projectAuth.Username
+		authData["password"] = projectAuth.Password
 	} else if m.authProvider != nil {
 		authType = m.authProvider.Type()
 		switch p := m.authProvider.(type) {
@@ -3,46 +3,12 @@ package cli
 import (
 	"testing"
 
+	agent "github.com/Octrafic/octrafic-cli/internal/agents"
 	"github.com/Octrafic/octrafic-cli/internal/core/analyzer"
 	"github.com/Octrafic/octrafic-cli/internal/core/parser"
+	"github.com/Octrafic/octrafic-cli/internal/infra/storage"
 )
 
-func TestMatchPath_Exact(t *testing.T) {
-	if !matchPath("/users", "/users") {
-		t.Error("expected exact match")
-	}
-}
-
-func TestMatchPath_WithParam(t *testing.T) {
-	if !matchPath("/users/{id}", "/users/42") {
-		t.Error("expected param segment to match any value")
-	}
-}
-
-func TestMatchPath_MultipleParams(t *testing.T) {
-	if !matchPath("/users/{id}/orders/{orderId}", "/users/1/orders/99") {
-		t.Error("expected multiple params to match")
-	}
-}
-
-func TestMatchPath_DifferentSegmentCount(t *testing.T) {
-	if matchPath("/users/{id}", "/users") {
-		t.Error("expected no match for different segment count")
-	}
-}
-
-func TestMatchPath_LiteralMismatch(t *testing.T) {
-	if matchPath("/users/{id}", "/products/42") {
-		t.Error("expected no match when literal segment differs")
-	}
-}
-
-func TestMatchPath_CaseInsensitive(t *testing.T) {
-	if !matchPath("/Users/{id}", "/users/1") {
-		t.Error("expected case-insensitive match on literal segments")
-	}
-}
-
 func TestValidateResponseSchema_NilAnalysis(t *testing.T) {
 	m := &TestUIModel{}
 	errs := m.validateResponseSchema("GET", "/users", 200, `{"id":"1"}`)
@@ -136,3 +102,39 @@ func TestValidateResponseSchema_SchemaError(t *testing.T) {
 		t.Error("expected schema validation error for wrong type")
 	}
 }
+
+func TestSaveAndLoadTestSuite(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := &TestUIModel{
+		baseURL:        "http://localhost:8080",
+		currentProject: &storage.Project{ID: "suite-tools", Name: "suite-tools"},
+	}
+
+	save := agent.ToolCall{ID: "1", Name: agent.ToolSaveTestSuite, Arguments: map[string]any{"name": "smoke"}}
+	if msg := m.handleSaveTestSuite(save).(toolResultMsg); msg.err == nil {
+		t.Fatal("expected error when no tests were executed")
+	}
+
+	m.lastTestGroup = []map[string]any{
+		{"method": "POST", "endpoint": "/users", "body": `{"name":"Ada"}`, "expected_status": float64(201),
+			"extract": []any{map[string]any{"field": "id", "as": "user_id"}}},
+		{"method": "GET", "endpoint": "/users/{{user_id}}", "expected_status": float64(200)},
+	}
+	if msg := m.handleSaveTestSuite(save).(toolResultMsg); msg.err != nil {
+		t.Fatalf("save failed: %v", msg.err)
+	}
+
+	list := m.handleLoadTestSuite(agent.ToolCall{ID: "2", Name: agent.ToolLoadTestSuite, Arguments: map[string]any{}}).(toolResultMsg)
+	if names, _ := list.result.(map[string]any)["suites"].([]string); len(names) != 1 || names[0] != "smoke" {
+		t.Errorf("expected suite list [smoke], got %v", list.result)
+	}
+
+	load := m.handleLoadTestSuite(agent.ToolCall{ID: "3", Name: agent.ToolLoadTestSuite, Arguments: map[string]any{"name": "smoke"}}).(toolResultMsg)
+	if load.err != nil {
+		t.Fatalf("load failed: %v", load.err)
+	}
+	tests, _ := load.result.(map[string]any)["tests"].([]agent.TestCase)
+	if len(tests) != 2 || tests[0].Extract[0].As != "user_id" || tests[1].Endpoint != "/users/{{user_id}}" || tests[0].ID != 1 {
+		t.Errorf("unexpected loaded tests: %+v", tests)
+	}
+}
@@ -0,0 +1,218 @@
+package cli
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/Octrafic/octrafic-cli/internal/core/auth"
+	"github.com/Octrafic/octrafic-cli/internal/core/parser"
+	"github.com/Octrafic/octrafic-cli/internal/core/tester"
+	"github.com/Octrafic/octrafic-cli/internal/infra/logger"
+	"github.com/Octrafic/octrafic-cli/internal/infra/storage"
+	"go.uber.org/zap"
+)
+
+// AuthFromConfig builds an authentication provider from a saved
+// configuration, reading its credentials from the secret store. Login flows
+// without a base URL log in against the API the request goes to. The
+// configuration is the user's own, so its credentials may be env:, file: and
+// cmd: references.
+func AuthFromConfig(cfg *storage.AuthConfig, baseURL string) (auth.AuthProvider, error) {
+	if cfg == nil {
+		return &auth.NoAuth{}, nil
+	}
+	cfg, err := cfg.Resolve()
+	if err != nil {
+		return nil, err
+	}
+	return auth.AllowReferences(authFromConfig(cfg, baseURL)), nil
+}
+
+// ImportedAuthFromConfig builds an authentication provider from a
+// configuration imported from a file someone else may have written, such as
+// a Postman collection. Its credentials are sent as written: references are
+// neither looked up in the secret store nor resolved.
+func ImportedAuthFromConfig(cfg *storage.AuthConfig, baseURL string) auth.AuthProvider {
+	if cfg == nil {
+		return &auth.NoAuth{}
+	}
+	return authFromConfig(cfg, baseURL)
+}
+
+func authFromConfig(cfg *storage.AuthConfig, baseURL string) auth.AuthProvider {
+	switch cfg.Type {
+	case "bearer":
+		return auth.NewBearerAuth(cfg.Token)
+	case "apikey":
+		return auth.NewAPIKeyAuth(cfg.KeyName, cfg.KeyValue, cmp.Or(cfg.Location, "header"))
+	case "basic":
+		return auth.NewBasicAuth(cfg.Username, cfg.Password)
+	case "oauth2":
+		return OAuth2FromConfig(cfg)
+	case "login":
+		if cfg.Login == nil {
+			return &auth.NoAuth{}
+		}
+		return tester.NewLoginAuth(baseURL, *cfg.Login)
+	case "sigv4":
+		if cfg.SigV4 == nil {
+			return &auth.NoAuth{}
+		}
+		return cfg.SigV4
+	case "hmac":
+		if cfg.HMAC == nil {
+			return &auth.NoAuth{}
+		}
+		return cfg.HMAC
+	default:
+		return &auth.NoAuth{}
+	}
+}
+
+// AuthConfigFromProvider converts a provider into a configuration that can
+// be saved with a project.
+func AuthConfigFromProvider(provider auth.AuthProvider) (*storage.AuthConfig, error) {
+	switch p := provider.(type) {
+	case *auth.BearerAuth:
+		return &storage.AuthConfig{Type: p.Type(), Token: p.Token}, nil
+	case *auth.APIKeyAuth:
+		return &storage.AuthConfig{Type: p.Type(), KeyName: p.Key, KeyValue: p.Value, Location: p.Location}, nil
+	case *auth.BasicAuth:
+		return &storage.AuthConfig{Type: p.Type(), Username: p.Username, Password: p.Password}, nil
+	case *auth.OAuth2Auth:
+		return oauth2AuthConfig(p), nil
+	case *tester.LoginAuth:
+		flow := p.LoginFlow
+		return &storage.AuthConfig{Type: p.Type(), Login: &flow}, nil
+	case *auth.SigV4Auth:
+		sigv4 := *p
+		return &storage.AuthConfig{Type: p.Type(), SigV4: &sigv4}, nil
+	case *auth.HMACAuth:
+		hmac := *p
+		return &storage.AuthConfig{Type: p.Type(), HMAC: &hmac}, nil
+	default:
+		return nil, fmt.Errorf("%s authentication cannot be saved as an identity", provider.Type())
+	}
+}
+
+// PostmanAuthConfig converts the authentication of a Postman request into
+// a configuration for ImportedAuthFromConfig. OAuth 2.0 with a token URL
+// uses its flow; otherwise the saved access token is sent as a bearer token.
+func PostmanAuthConfig(a *parser.PostmanAuth) (*storage.AuthConfig, error) {
+	p := a.Params
+	switch a.Type {
+	case "bearer":
+		return &storage.AuthConfig{Type: "bearer", Token: p["token"]}, nil
+	case "basic":
+		return &storage.AuthConfig{Type: "basic", Username: p["username"], Password: p["password"]}, nil
+	case "apikey":
+		return &storage.AuthConfig{Type: "apikey", KeyName: p["key"], KeyValue: p["value"], Location: cmp.Or(p["in"], "header")}, nil
+	case "oauth2":
+		var grantType string
+		switch p["grant_type"] {
+		case "client_credentials":
+			grantType = auth.GrantClientCredentials
+		case "authorization_code", "authorization_code_with_pkce":
+			grantType = auth.GrantAuthorizationCode
+		}
+		if p["accessTokenUrl"] == "" || (grantType == "" && p["refreshToken"] == "") {
+			if p["accessToken"] == "" {
+				return nil, fmt.Errorf("Postman OAuth 2.0 %s grant is not supported", cmp.Or(p["grant_type"], "implicit"))
+			}
+			return &storage.AuthConfig{Type: "bearer", Token: p["accessToken"]}, nil
+		}
+		return &storage.AuthConfig{
+			Type:         "oauth2",
+			GrantType:    grantType,
+			TokenURL:     p["accessTokenUrl"],
+			ClientID:     p["clientId"],
+			ClientSecret: p["clientSecret"],
+			RefreshToken: p["refreshToken"],
+			Scopes:       p["scope"],
+			Audience:     p["audience"],
+			AuthorizeURL: p["authUrl"],
+			RedirectURL:  p["redirect_uri"],
+		}, nil
+	case "awsv4":
+		sigv4 := auth.NewSigV4Auth(p["accessKey"], p["secretKey"], p["sessionToken"], p["region"], p["service"])
+		return &storage.AuthConfig{Type: "sigv4", SigV4: sigv4}, nil
+	default:
+		return nil, fmt.Errorf("Postman %s authentication is not supported", a.Type)
+	}
+}
+
+// IdentityProviders builds a provider for every saved identity of a project,
+// plus the built-in anonymous identity.
+func IdentityProviders(project *storage.Project) (map[string]auth.AuthProvider, error) {
+	providers := map[string]auth.AuthProvider{storage.AnonymousIdentity: &auth.NoAuth{}}
+	if project == nil {
+		return providers, nil
+	}
+	for name, cfg := range project.Identities {
+		provider, err := AuthFromConfig(cfg, project.BaseURL)
+		if err != nil {
+			return nil, fmt.Errorf("identity %s: %w", name, err)
+		}
+		if oauth2, ok := provider.(*auth.OAuth2Auth); ok {
+			oauth2.OnRefreshToken = func(refreshToken string) {
+				cfg.RefreshToken = refreshToken
+				if err := storage.SaveProject(project); err != nil {
+					logger.Error("Failed to save refresh token", zap.Error(err))
+				}
+			}
+		}
+		providers[name] = provider
+	}
+	return providers, nil
+}
+
+// executorFor returns an executor that authenticates as the named identity,
+// or the session executor when no identity is given.
+func (m *TestUIModel) executorFor(identity string) (*tester.Executor, error) {
+	if identity == "" {
+		return m.testExecutor, nil
+	}
+	if m.identities == nil {
+		identities, err := IdentityProviders(m.currentProject)
+		if err != nil {
+			return nil, err
+		}
+		m.identities = identities
+	}
+	provider, ok := m.identities[identity]
+	if !ok {
+		return nil, fmt.Errorf("unknown identity %q (available: %s)", identity, strings.Join(slices.Sorted(maps.Keys(m.identities)), ", "))
+	}
+	return m.testExecutor.WithAuth(provider), nil
+}
+
+// saveIdentity stores a provider as a named identity of the current project.
+func (m *TestUIModel) saveIdentity(name string, provider auth.AuthProvider) error {
+	if m.currentProject == nil {
+		return fmt.Errorf("no project is open")
+	}
+	cfg, err := AuthConfigFromProvider(provider)
+	if err != nil {
+		return err
+	}
+	if err := m.currentProject.SetIdentity(name, cfg); err != nil {
+		return err
+	}
+	if err := storage.SaveProject(m.currentProject); err != nil {
+		return fmt.Errorf("failed to save project: %w", err)
+	}
+	m.identities = nil
+	return nil
+}
+
+// describeAuth renders a provider with its secrets redacted.
+func describeAuth(provider auth.AuthProvider) string {
+	redacted := provider.Redact()
+	if stringer, ok := redacted.(fmt.Stringer); ok {
+		return stringer.String()
+	}
+	return redacted.Type()
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Octrafic/octrafic-cli/internal/core/auth"
+	"github.com/Octrafic/octrafic-cli/internal/core/parser"
+	"github.com/Octrafic/octrafic-cli/internal/core/tester"
+)
+
+func TestPostmanAuthConfig(t *testing.T) {
+	tests := []struct {
+		auth parser.PostmanAuth
+		want string
+	}{
+		{parser.PostmanAuth{Type: "apikey", Params: map[string]string{"key": "api_key", "value": "k", "in": "query"}}, "apikey"},
+		{parser.PostmanAuth{Type: "oauth2", Params: map[string]string{"grant_type": "client_credentials", "accessTokenUrl": "https://auth.example.com/token", "clientId": "id"}}, "oauth2"},
+		{parser.PostmanAuth{Type: "oauth2", Params: map[string]string{"grant_type": "implicit", "accessToken": "t"}}, "bearer"},
+		{parser.PostmanAuth{Type: "awsv4", Params: map[string]string{"accessKey": "AK", "secretKey": "SK", "region": "eu-west-1"}}, "sigv4"},
+	}
+	for _, tt := range tests {
+		cfg, err := PostmanAuthConfig(&tt.auth)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.auth.Type, err)
+		}
+		if provider := ImportedAuthFromConfig(cfg, ""); provider.Type() != tt.want {
+			t.Errorf("%s: expected %s provider, got %s", tt.auth.Type, tt.want, provider.Type())
+		}
+	}
+
+	cfg, _ := PostmanAuthConfig(&parser.PostmanAuth{Type: "apikey", Params: map[string]string{"key": "api_key", "value": "k", "in": "query"}})
+	if provider := ImportedAuthFromConfig(cfg, ""); provider.(*auth.APIKeyAuth).Location != "query" {
+		t.Errorf("expected query API key, got %+v", provider)
+	}
+	if _, err := PostmanAuthConfig(&parser.PostmanAuth{Type: "hawk"}); err == nil {
+		t.Error("expected unsupported auth to fail")
+	}
+}
+
+func TestImportedAuthFromConfig_SendsReferencesAsWritten(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "ran")
+	collection := `{
+		"info": {"name": "Untrusted", "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"},
+		"auth": {"type": "bearer", "bearer": [{"key": "token", "value": "cmd:touch ` + marker + `"}]},
+		"item": [{"name": "Me", "request": {"method": "GET", "url": "{{baseUrl}}/me"}}]
+	}`
+	col, err := parser.ReadPostman([]byte(collection), nil)
+	if err != nil {
+		t.Fatalf("ReadPostman failed: %v", err)
+	}
+	cfg, err := PostmanAuthConfig(col.Requests[0].Auth)
+	if err != nil {
+		t.Fatalf("PostmanAuthConfig failed: %v", err)
+	}
+
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+	executor := tester.NewExecutor(server.URL, ImportedAuthFromConfig(cfg, server.URL))
+	if _, err := executor.ExecuteTest("GET", "/me", nil, nil, true); err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+
+	if got != "Bearer cmd:touch "+marker {
+		t.Errorf("expected the token sent unchanged, got %q", got)
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("expected the cmd: token not to run")
+	}
+}
@@ -14,6 +14,8 @@ import (
 func Start(baseURL string, specPath string, analysis *analyzer.Analysis, authProvider auth.AuthProvider, version string, yoloMode bool) {
 	model := NewTestUIModel(baseURL, specPath, analysis, authProvider, version, yoloMode, false)
 
+	defer model.closeWebhooks()
+
 	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
 	if _, err := p.Run(); err != nil {
 		logger.Error("Error running interactive mode", logger.Err(err))
@@ -27,6 +29,7 @@ func StartWithProject(baseURL string, analysis *analyzer.Analysis, project *stor
 	model := NewTestUIModel(baseURL, specPath, analysis, authProvider, version, yoloMode, false)
 
 	model.currentProject = project
+	model.setupSchemeAuth()
 
 	// Create new conversation for this project (only for named projects)
 	if !project.IsTemporary {
@@ -37,6 +40,8 @@ func StartWithProject(baseURL string, analysis *analyzer.Analysis, project *stor
 		// Conversation will be created on first user message (to get title from first prompt)
 	}
 
+	defer model.closeWebhooks()
+
 	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
 	if _, err := p.Run(); err != nil {
 		logger.Error("Error running interactive mode", logger.Err(err))
@@ -53,6 +58,7 @@ func StartWithConversation(baseURL string, analysis *analyzer.Analysis, project
 	model.currentProject = project
 	model.conversationID = conversationID
 	model.isLoadedConversation = true
+	model.setupSchemeAuth()
 
 	// Load conversation history
 	if err := model.loadConversationHistory(); err != nil {
@@ -60,6 +66,8 @@ func StartWithConversation(baseURL string, analysis *analyzer.Analysis, project
 		os.Exit(1)
 	}
 
+	defer model.closeWebhooks()
+
 	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
 	if _, err := p.Run(); err != nil {
 		logger.Error("Error running interactive mode", logger.Err(err))
@@ -75,6 +83,7 @@ func StartHeadless(baseURL string, analysis *analyzer.Analysis, project *storage
 
 	model := NewTestUIModel(baseURL, specPath, analysis, authProvider, version, yoloMode, true)
 	model.currentProject = project
+	model.setupSchemeAuth()
 	model.textarea.SetValue(prompt)
 	model.textarea.SetCursor(len(prompt))
 	model.initialPrompt = prompt
@@ -92,6 +101,8 @@ func StartHeadless(baseURL string, analysis *analyzer.Analysis, project *storage
 	}
 	_ = w.Close()
 
+	defer model.closeWebhooks()
+
 	p := tea.NewProgram(model, tea.WithInput(r))
 
 	finalModel, err := p.Run()
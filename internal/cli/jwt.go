@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	agent "github.com/Octrafic/octrafic-cli/internal/agents"
+	"github.com/Octrafic/octrafic-cli/internal/core/auth"
+	"github.com/Octrafic/octrafic-cli/internal/core/tester"
+	"github.com/Octrafic/octrafic-cli/internal/infra/storage"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// expectedRunTime estimates how long running a number of tests takes, so a
+// token that expires mid-run can be reported up front.
+func expectedRunTime(tests int) time.Duration {
+	return max(time.Minute, time.Duration(tests)*2*time.Second)
+}
+
+// TokenExpiryWarning returns a warning when a JWT bearer token among the
+// providers has expired or will expire during a run of the given number of
+// tests, or "" otherwise.
+func TokenExpiryWarning(tests int, providers ...auth.AuthProvider) string {
+	for _, provider := range providers {
+		if jwt, ok := auth.BearerJWT(provider); ok {
+			if warning := jwt.ExpiryWarning(time.Now(), expectedRunTime(tests)); warning != "" {
+				return warning
+			}
+		}
+	}
+	return ""
+}
+
+// warnTokenExpiry shows TokenExpiryWarning for the session's providers.
+func (m *TestUIModel) warnTokenExpiry(tests int) {
+	providers := []auth.AuthProvider{m.authProvider}
+	for _, provider := range m.schemeAuth {
+		providers = append(providers, provider)
+	}
+	if warning := TokenExpiryWarning(tests, providers...); warning != "" {
+		m.addMessage(lipgloss.NewStyle().Foreground(Theme.Warning).Render("⚠ " + warning))
+	}
+}
+
+// jwtInfo returns the /info lines describing the session's bearer token.
+func (m *TestUIModel) jwtInfo() []string {
+	jwt, ok := auth.BearerJWT(m.authProvider)
+	if !ok {
+		return nil
+	}
+	lines := []string{"JWT:"}
+	for _, line := range jwt.Describe(time.Now()) {
+		lines = append(lines, "  "+line)
+	}
+	return lines
+}
+
+// JWTFindings turns a probe report into security findings: a pass for every
+// rejected variant and a high or critical finding for every accepted one.
+func JWTFindings(report *tester.JWTProbeReport, now time.Time) []storage.Finding {
+	findings := make([]storage.Finding, 0, len(report.Results))
+	for _, result := range report.Results {
+		finding := storage.Finding{
+			Time:       now,
+			Category:   "jwt",
+			Check:      result.Probe.Name,
+			Method:     report.Method,
+			Path:       report.Path,
+			StatusCode: result.StatusCode,
+		}
+		switch {
+		case result.Error != nil:
+			finding.Severity = "info"
+			finding.Detail = fmt.Sprintf("Token with %s: probe failed: %v", result.Probe.Description, result.Error)
+		case result.Rejected():
+			finding.Severity = "pass"
+			finding.Detail = fmt.Sprintf("Token with %s was rejected", result.Probe.Description)
+		case result.Accepted():
+			finding.Severity = "high"
+			if result.Probe.Name == "alg-none" || result.Probe.Name == "modified-signature" {
+				finding.Severity = "critical"
+			}
+			finding.Detail = fmt.Sprintf("Token with %s was accepted", result.Probe.Description)
+		default:
+			finding.Severity = "info"
+			finding.Detail = fmt.Sprintf("Token with %s got %d instead of 401 or 403", result.Probe.Description, result.StatusCode)
+		}
+		if !report.BaselineOK() && finding.Severity == "pass" {
+			finding.Severity = "info"
+			finding.Detail += fmt.Sprintf(", but so was the real token (%d)", report.BaselineStatus)
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+// handleProbeJWT runs the JWT tampering probes against an endpoint and
+// records the results as findings.
+func (m *TestUIModel) handleProbeJWT(toolCall agent.ToolCall) tea.Msg {
+	method, _ := toolCall.Arguments["method"].(string)
+	path, _ := toolCall.Arguments["path"].(string)
+	method = strings.ToUpper(method)
+	if method == "" || path == "" {
+		return toolResultMsg{toolID: toolCall.ID, toolName: toolCall.Name, err: fmt.Errorf("method and path are required")}
+	}
+
+	var headers map[string]string
+	if h, ok := toolCall.Arguments["headers"].(map[string]any); ok {
+		headers = make(map[string]string, len(h))
+		for k, v := range h {
+			headers[k] = fmt.Sprint(v)
+		}
+	}
+	var body any
+	if b, ok := toolCall.Arguments["body"].(map[string]any); ok {
+		body = b
+	}
+
+	report, err := m.testExecutor.ProbeJWT(method, path, headers, body)
+	if err != nil {
+		return toolResultMsg{toolID: toolCall.ID, toolName: toolCall.Name, err: err}
+	}
+
+	findings := JWTFindings(report, time.Now())
+	if m.currentProject != nil {
+		if err := storage.AddFindings(m.currentProject.ID, m.currentProject.IsTemporary, findings...); err != nil {
+			return toolResultMsg{toolID: toolCall.ID, toolName: toolCall.Name, err: err}
+		}
+	}
+
+	probes := make([]map[string]any, 0, len(findings))
+	vulnerable, failed := 0, 0
+	for i, finding := range findings {
+		if finding.Severe() {
+			vulnerable++
+		}
+		probe := map[string]any{
+			"check":       finding.Check,
+			"status_code": finding.StatusCode,
+			"severity":    finding.Severity,
+			"detail":      finding.Detail,
+		}
+		if err := report.Results[i].Error; err != nil {
+			failed++
+			probe["error"] = fmt.Sprintf("probe failed: %v", err)
+		}
+		probes = append(probes, probe)
+	}
+	return toolResultMsg{
+		toolID:   toolCall.ID,
+		toolName: toolCall.Name,
+		result: map[string]any{
+			"method":          method,
+			"path":            path,
+			"baseline_status": report.BaselineStatus,
+			"probes":          probes,
+			"vulnerable":      vulnerable,
+			"failed":          failed,
+		},
+	}
+}
+
+// showJWTProbeResult prints the probe results as the agent receives them.
+func (m *TestUIModel) showJWTProbeResult(result map[string]any) {
+	method, _ := result["method"].(string)
+	path, _ := result["path"].(string)
+	probes, _ := result["probes"].([]map[string]any)
+	vulnerable, _ := result["vulnerable"].(int)
+	failed, _ := result["failed"].(int)
+
+	m.addMessage("")
+	warning := lipgloss.NewStyle().Foreground(Theme.Warning)
+	switch {
+	case vulnerable > 0:
+		m.addMessage(m.errorStyle.Render(fmt.Sprintf("✗ %s %s accepted %d tampered token(s)", method, path, vulnerable)))
+	case failed > 0:
+		m.addMessage(warning.Render(fmt.Sprintf("⚠ %d of %d JWT probes on %s %s failed", failed, len(probes), method, path)))
+	default:
+		m.addMessage(m.successStyle.Render(fmt.Sprintf("✓ JWT probes on %s %s", method, path)))
+	}
+	for _, probe := range probes {
+		severity, _ := probe["severity"].(string)
+		detail, _ := probe["detail"].(string)
+		line := fmt.Sprintf("   • [%s] %s", severity, detail)
+		switch {
+		case probe["error"] != nil:
+			m.addMessage(warning.Render(line))
+		case severity == "high" || severity == "critical":
+			m.addMessage(m.errorStyle.Render(line))
+		default:
+			m.addMessage(m.subtleStyle.Render(line))
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Octrafic/octrafic-cli/internal/core/tester"
+)
+
+func TestJWTFindings(t *testing.T) {
+	report := &tester.JWTProbeReport{
+		Method:         "GET",
+		Path:           "/me",
+		BaselineStatus: 200,
+		Results: []tester.JWTProbeResult{
+			{Probe: tester.JWTProbe{Name: "alg-none", Description: "alg none"}, StatusCode: 200},
+			{Probe: tester.JWTProbe{Name: "expired", Description: "expired exp"}, StatusCode: 200},
+			{Probe: tester.JWTProbe{Name: "wrong-audience", Description: "wrong aud"}, StatusCode: 401},
+			{Probe: tester.JWTProbe{Name: "elevated-role", Description: "admin role"}, StatusCode: 500},
+			{Probe: tester.JWTProbe{Name: "modified-signature", Description: "a modified signature"}, Error: errors.New("connection refused")},
+		},
+	}
+
+	want := []string{"critical", "high", "pass", "info", "info"}
+	findings := JWTFindings(report, time.Now())
+	if len(findings) != len(want) {
+		t.Fatalf("Expected %d findings, got %d", len(want), len(findings))
+	}
+	for i, finding := range findings {
+		if finding.Severity != want[i] {
+			t.Errorf("%s: expected severity %s, got %s (%s)", finding.Check, want[i], finding.Severity, finding.Detail)
+		}
+		if finding.Category != "jwt" || finding.Path != "/me" {
+			t.Errorf("%s: unexpected finding %+v", finding.Check, finding)
+		}
+	}
+
+	if detail := findings[4].Detail; !strings.Contains(detail, "probe failed: connection refused") {
+		t.Errorf("Expected the transport error in the detail, got %q", detail)
+	}
+
+	// Rejections prove nothing when the real token is rejected as well.
+	report.BaselineStatus = 401
+	if f := JWTFindings(report, time.Now())[2]; f.Severity != "info" {
+		t.Errorf("Expected info when the baseline fails, got %s", f.Severity)
+	}
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	agent "github.com/Octrafic/octrafic-cli/internal/agents"
+	"github.com/Octrafic/octrafic-cli/internal/core/linter"
+	"github.com/Octrafic/octrafic-cli/internal/core/parser"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxLintFindings caps the findings returned to the agent.
+const maxLintFindings = 100
+
+// LintSpec lints an OpenAPI or Swagger specification with the lint
+// configuration beside it, running only the given rules when any are named.
+func LintSpec(specPath string, only []string) (*linter.Linter, []linter.Finding, error) {
+	spec, err := parser.ParseSpecification(specPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse specification: %w", err)
+	}
+	if spec.Format != "openapi" {
+		return nil, nil, fmt.Errorf("only OpenAPI and Swagger specifications can be linted, not %s", spec.Format)
+	}
+
+	cfg := &linter.Config{}
+	if path := linter.FindConfig(specPath); path != "" {
+		if cfg, err = linter.LoadConfig(path); err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(only) > 0 {
+		if cfg.Rules == nil {
+			cfg.Rules = make(map[string]string)
+		}
+		var ids []string
+		for _, r := range linter.AllRules() {
+			ids = append(ids, r.ID)
+			if !slices.Contains(only, r.ID) {
+				cfg.Rules[r.ID] = "off"
+			}
+		}
+		for _, id := range only {
+			if !slices.Contains(ids, id) {
+				return nil, nil, fmt.Errorf("unknown lint rule %q (rules: %s)", id, strings.Join(ids, ", "))
+			}
+		}
+	}
+
+	l, err := linter.New(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	findings, err := l.Lint([]byte(spec.RawContent))
+	if err != nil {
+		return nil, nil, err
+	}
+	return l, findings, nil
+}
+
+// handleLintSpec lints the session's specification.
+func (m *TestUIModel) handleLintSpec(toolCall agent.ToolCall) tea.Msg {
+	if m.specPath == "" {
+		return toolResultMsg{toolID: toolCall.ID, toolName: toolCall.Name, err: fmt.Errorf("no specification loaded")}
+	}
+	var only []string
+	if rules, ok := toolCall.Arguments["rules"].([]any); ok {
+		for _, r := range rules {
+			if id, ok := r.(string); ok {
+				only = append(only, id)
+			}
+		}
+	}
+
+	l, findings, err := LintSpec(m.specPath, only)
+	if err != nil {
+		return toolResultMsg{toolID: toolCall.ID, toolName: toolCall.Name, err: err}
+	}
+	var rules []string
+	for _, r := range l.Rules() {
+		rules = append(rules, r.ID)
+	}
+	result := map[string]any{
+		"rules":    rules,
+		"errors":   linter.Count(findings, linter.SeverityError),
+		"warnings": linter.Count(findings, linter.SeverityWarning),
+		"info":     linter.Count(findings, linter.SeverityInfo),
+		"findings": findings[:min(len(findings), maxLintFindings)],
+	}
+	if len(findings) > maxLintFindings {
+		result["truncated"] = len(findings) - maxLintFindings
+	}
+	return toolResultMsg{toolID: toolCall.ID, toolName: toolCall.Name, result: result}
+}
+
+// showLintResult prints the lint summary as the agent receives it.
+func (m *TestUIModel) showLintResult(result map[string]any) {
+	errors, _ := result["errors"].(int)
+	warnings, _ := result["warnings"].(int)
+	info, _ := result["info"].(int)
+
+	m.addMessage("")
+	summary := fmt.Sprintf("%d errors, %d warnings, %d info", errors, warnings, info)
+	switch {
+	case errors > 0:
+		m.addMessage(m.errorStyle.Render("✗ Lint: " + summary))
+	case warnings+info > 0:
+		m.addMessage(m.subtleStyle.Render("• Lint: " + summary))
+	default:
+		m.addMessage(m.successStyle.Render("✓ Lint: no problems found"))
+	}
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	agent "github.com/Octrafic/octrafic-cli/internal/agents"
+	"github.com/Octrafic/octrafic-cli/internal/core/linter"
+)
+
+func TestHandleLintSpec(t *testing.T) {
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "openapi.yaml")
+	spec := `openapi: 3.0.3
+info: {title: Shop, version: "1"}
+paths:
+  /items:
+    get:
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {type: array, items: {type: string}}
+  /Items:
+    post:
+      operationId: createItem
+      security: [{oauth: []}]
+      responses:
+        "201": {description: Created}
+        "400": {description: Invalid}
+`
+	if err := os.WriteFile(specPath, []byte(spec), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config := "rules:\n  operation-description: off\n  missing-example: error\n"
+	if err := os.WriteFile(filepath.Join(dir, linter.ConfigFile), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := &TestUIModel{specPath: specPath}
+	msg := m.handleLintSpec(agent.ToolCall{ID: "1", Name: agent.ToolLintSpec, Arguments: map[string]any{}}).(toolResultMsg)
+	if msg.err != nil {
+		t.Fatalf("lint_spec failed: %v", msg.err)
+	}
+	result := msg.result.(map[string]any)
+	findings := result["findings"].([]linter.Finding)
+	rules := map[string]linter.Severity{}
+	for _, f := range findings {
+		rules[f.Rule] = f.Severity
+	}
+	if _, ok := rules["operation-description"]; ok {
+		t.Errorf("expected operation-description disabled by the config file, got %+v", findings)
+	}
+	if rules["missing-example"] != linter.SeverityError || rules["undefined-security-scheme"] != linter.SeverityError || rules["operation-error-response"] != linter.SeverityWarning {
+		t.Errorf("expected configured and default severities, got %+v", findings)
+	}
+	if result["errors"] != 2 || result["warnings"] != 1 {
+		t.Errorf("expected 2 errors and 1 warning, got %v", result)
+	}
+
+	msg = m.handleLintSpec(agent.ToolCall{ID: "2", Name: agent.ToolLintSpec, Arguments: map[string]any{"rules": []any{"operation-error-response"}}}).(toolResultMsg)
+	if msg.err != nil {
+		t.Fatalf("lint_spec failed: %v", msg.err)
+	}
+	if findings := msg.result.(map[string]any)["findings"].([]linter.Finding); len(findings) != 1 || findings[0].Rule != "operation-error-response" {
+		t.Errorf("expected only the requested rule, got %+v", findings)
+	}
+
+	msg = m.handleLintSpec(agent.ToolCall{ID: "3", Name: agent.ToolLintSpec, Arguments: map[string]any{"rules": []any{"style"}}}).(toolResultMsg)
+	if msg.err == nil {
+		t.Error("expected an unknown rule to fail")
+	}
+}
@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/Octrafic/octrafic-cli/internal/core/auth"
+	"github.com/Octrafic/octrafic-cli/internal/infra/logger"
+	"github.com/Octrafic/octrafic-cli/internal/infra/storage"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"go.uber.org/zap"
+)
+
+const oauth2LoginTimeout = 5 * time.Minute
+
+type oauth2LoginStartedMsg struct {
+	provider     *auth.OAuth2Auth
+	authorizeURL string
+	done         chan error
+}
+
+type oauth2LoginDoneMsg struct {
+	provider *auth.OAuth2Auth
+	err      error
+}
+
+// OAuth2FromConfig builds an OAuth2 provider from a saved configuration. A
+// missing grant type is inferred: authorization_code when there is an
+// authorize URL, refresh_token when there is a refresh token, otherwise
+// client_credentials.
+func OAuth2FromConfig(cfg *storage.AuthConfig) *auth.OAuth2Auth {
+	grantType := cfg.GrantType
+	if grantType == "" {
+		switch {
+		case cfg.AuthorizeURL != "":
+			grantType = auth.GrantAuthorizationCode
+		case cfg.RefreshToken != "":
+			grantType = auth.GrantRefreshToken
+		default:
+			grantType = auth.GrantClientCredentials
+		}
+	}
+	provider := auth.NewOAuth2Auth(grantType, cfg.TokenURL, cfg.ClientID, cfg.ClientSecret)
+	provider.RefreshToken = cfg.RefreshToken
+	provider.Scopes = auth.ParseScopes(cfg.Scopes)
+	provider.Audience = cfg.Audience
+	provider.AuthorizeURL = cfg.AuthorizeURL
+	provider.RedirectURL = cfg.RedirectURL
+	return provider
+}
+
+// oauth2AuthConfig converts a provider back into a project configuration.
+func oauth2AuthConfig(p *auth.OAuth2Auth) *storage.AuthConfig {
+	return &storage.AuthConfig{
+		Type:         p.Type(),
+		GrantType:    p.GrantType,
+		TokenURL:     p.TokenURL,
+		ClientID:     p.ClientID,
+		ClientSecret: p.ClientSecret,
+		RefreshToken: p.RefreshToken,
+		Scopes:       strings.Join(p.Scopes, " "),
+		Audience:     p.Audience,
+		AuthorizeURL: p.AuthorizeURL,
+		RedirectURL:  p.RedirectURL,
+	}
+}
+
+// OpenBrowser opens a URL in the default browser.
+func OpenBrowser(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Start()
+}
+
+// startOAuth2Login starts the browser sign-in and reports the authorize URL
+// as soon as the callback listener is ready.
+func startOAuth2Login(provider *auth.OAuth2Auth) tea.Cmd {
+	return func() tea.Msg {
+		urls := make(chan string, 1)
+		done := make(chan error, 1)
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), oauth2LoginTimeout)
+			defer cancel()
+			done <- provider.Login(ctx, func(authorizeURL string) { urls <- authorizeURL })
+		}()
+		select {
+		case authorizeURL := <-urls:
+			return oauth2LoginStartedMsg{provider: provider, authorizeURL: authorizeURL, done: done}
+		case err := <-done:
+			return oauth2LoginDoneMsg{provider: provider, err: err}
+		}
+	}
+}
+
+func waitForOAuth2Login(provider *auth.OAuth2Auth, done chan error) tea.Cmd {
+	return func() tea.Msg {
+		return oauth2LoginDoneMsg{provider: provider, err: <-done}
+	}
+}
+
+func handleOAuth2LoginStarted(m *TestUIModel, msg oauth2LoginStartedMsg) (tea.Model, tea.Cmd) {
+	m.addMessage(m.subtleStyle.Render("Sign in to authorize Octrafic:"))
+	m.addMessage(lipgloss.NewStyle().Foreground(Theme.Cyan).Render(msg.authorizeURL))
+	if err := OpenBrowser(msg.authorizeURL); err != nil {
+		m.addMessage(m.subtleStyle.Render("Open the URL above in your browser."))
+	}
+	m.addMessage(m.subtleStyle.Render("Waiting for sign-in..."))
+	m.lastMessageRole = "assistant"
+	m.updateViewport()
+	return m, waitForOAuth2Login(msg.provider, msg.done)
+}
+
+// handleOAuth2LoginDone saves the signed-in configuration with a named
+// project, so later sessions and --resume refresh without signing in again.
+func handleOAuth2LoginDone(m *TestUIModel, msg oauth2LoginDoneMsg) (tea.Model, tea.Cmd) {
+	defer m.updateViewport()
+	m.lastMessageRole = "assistant"
+	if msg.err != nil {
+		m.addMessage(m.errorStyle.Render(fmt.Sprintf("✗ Sign-in failed: %v", msg.err)))
+		m.addMessage("")
+		return m, nil
+	}
+	m.addMessage(m.successStyle.Render("✓ Signed in"))
+	m.addMessage("")
+
+	project := m.currentProject
+	if project == nil || project.IsTemporary {
+		return m, nil
+	}
+	cfg := oauth2AuthConfig(msg.provider)
+	project.AuthConfig = cfg
+	if err := storage.SaveProject(project); err != nil {
+		logger.Error("Failed to save OAuth2 sign-in", zap.Error(err))
+		return m, nil
+	}
+	msg.provider.OnRefreshToken = func(refreshToken string) {
+		cfg.RefreshToken = refreshToken
+		if err := storage.SaveProject(project); err != nil {
+			logger.Error("Failed to save refresh token", zap.Error(err))
+		}
+	}
+	return m, nil
+}
@@ -54,18 +54,26 @@ func detectSpecFormat(filePath string) (*FormatInfo, error) {
 
 	// AsyncAPI
 	if strings.Contains(textLower, "asyncapi:") || strings.Contains(textLower, `"asyncapi"`) {
-		return &FormatInfo{Name: "AsyncAPI", NeedsConversion: true}, nil
+		version := extractVersion(text, "asyncapi")
+		return &FormatInfo{Name: "AsyncAPI", Version: version, NativeSupport: true}, nil
+	}
+
+	// Insomnia v5 collection
+	if strings.Contains(text, "type: collection.insomnia.rest/") {
+		return &FormatInfo{Name: "Insomnia Collection", NativeSupport: true}, nil
 	}
 
 	// RAML
 	if strings.HasPrefix(strings.TrimSpace(text), "#%RAML") {
-		return &FormatInfo{Name: "RAML", NeedsConversion: true}, nil
+		version := strings.TrimSpace(strings.TrimPrefix(strings.SplitN(strings.TrimSpace(text), "\n", 2)[0], "#%RAML"))
+		return &FormatInfo{Name: "RAML", Version: version, NativeSupport: true}, nil
 	}
 
 	// API Blueprint
-	if strings.HasPrefix(strings.TrimSpace(text), "FORMAT:") ||
-		(ext == ".apib") ||
-		(strings.Contains(text, "# Group") && strings.Contains(text, "## ")) {
+	if strings.HasPrefix(strings.TrimSpace(text), "FORMAT:") || ext == ".apib" {
+		return &FormatInfo{Name: "API Blueprint", NativeSupport: true}, nil
+	}
+	if strings.Contains(text, "# Group") && strings.Contains(text, "## ") {
 		return &FormatInfo{Name: "API Blueprint", NeedsConversion: true}, nil
 	}
 
@@ -87,10 +95,18 @@ func detectSpecFormat(filePath string) (*FormatInfo, error) {
 	}
 
 	// HTTP/REST files
-	if ext == ".http" || ext == ".rest" || ext == ".hurl" {
+	if ext == ".http" || ext == ".rest" {
+		return &FormatInfo{Name: "HTTP File", NativeSupport: true}, nil
+	}
+	if ext == ".hurl" {
 		return &FormatInfo{Name: "HTTP File", NeedsConversion: true}, nil
 	}
 
+	// Bruno request
+	if ext == ".bru" {
+		return &FormatInfo{Name: "Bruno Collection", NativeSupport: true}, nil
+	}
+
 	// Markdown - check if it has structured API format or is just description
 	if ext == ".md" || ext == ".markdown" {
 		if hasStructuredAPIFormat(text) {
@@ -146,8 +162,8 @@ func detectJSONFormat(data map[string]interface{}) *FormatInfo {
 	}
 
 	// AsyncAPI
-	if _, ok := data["asyncapi"]; ok {
-		return &FormatInfo{Name: "AsyncAPI", NeedsConversion: true}
+	if v, ok := data["asyncapi"].(string); ok {
+		return &FormatInfo{Name: "AsyncAPI", Version: v, NativeSupport: true}
 	}
 
 	// Postman Collection (native support)
@@ -163,14 +179,25 @@ func detectJSONFormat(data map[string]interface{}) *FormatInfo {
 	// Insomnia Export
 	if t, ok := data["_type"].(string); ok && t == "export" {
 		if _, hasResources := data["resources"]; hasResources {
-			return &FormatInfo{Name: "Insomnia Export", NeedsConversion: true}
+			version := ""
+			if v, ok := data["__export_format"]; ok {
+				version = fmt.Sprint(v)
+			}
+			return &FormatInfo{Name: "Insomnia Export", Version: version, NativeSupport: true}
+		}
+	}
+
+	// Bruno Collection (bruno.json)
+	if t, ok := data["type"].(string); ok && t == "collection" {
+		if _, hasVersion := data["version"]; hasVersion {
+			return &FormatInfo{Name: "Bruno Collection", NativeSupport: true}
 		}
 	}
 
 	// HAR
 	if log, ok := data["log"].(map[string]interface{}); ok {
 		if _, hasEntries := log["entries"]; hasEntries {
-			return &FormatInfo{Name: "HAR (HTTP Archive)", NeedsConversion: true}
+			return &FormatInfo{Name: "HAR (HTTP Archive)", NativeSupport: true}
 		}
 	}
 
@@ -239,7 +266,7 @@ type ProjectCreatorModel struct {
 
 	// Auth configuration
 	configureAuth    bool
-	authType         string   // "bearer", "apikey", "basic", "none"
+	authType         string   // "bearer", "apikey", "basic", "oauth2", "login", "sigv4", "hmac", "none"
 	authMenuItems    []string // Menu options for auth type selection
 	authMenuIndex    int      // Selected menu item index
 	authFields       []FormField
@@ -382,6 +409,14 @@ func (m ProjectCreatorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.authType = "apikey"
 				case "Basic Auth":
 					m.authType = "basic"
+				case "OAuth2":
+					m.authType = "oauth2"
+				case "Login Flow":
+					m.authType = "login"
+				case "AWS SigV4":
+					m.authType = "sigv4"
+				case "HMAC Signature":
+					m.authType = "hmac"
 				case "None":
 					m.authType = "none"
 				}
@@ -422,7 +457,7 @@ func (m ProjectCreatorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "y", "Y":
 			if m.step == ProjectStepAuthPrompt {
 				m.configureAuth = true
-				m.authMenuItems = []string{"Bearer Token", "API Key", "Basic Auth", "None"}
+				m.authMenuItems = []string{"Bearer Token", "API Key", "Basic Auth", "OAuth2", "Login Flow", "AWS SigV4", "HMAC Signature", "None"}
 				m.authMenuIndex = 0
 				m.step = ProjectStepAuthType
 				return m, nil
@@ -860,8 +895,8 @@ func (m ProjectCreatorModel) GetAuthConfig() (authType string, authData map[stri
 // validateAuthFields checks if all required auth fields are filled
 func validateAuthFields(authType string, fields []FormField) bool {
 	for _, field := range fields {
-		if field.Name == "profile_name" {
-			continue // Optional
+		if field.Name == "profile_name" || field.Optional {
+			continue
 		}
 		if !field.IsRadio && strings.TrimSpace(field.Value) == "" {
 			return false
@@ -969,6 +1004,14 @@ func (m ProjectCreatorModel) renderAuthDetailsForm() string {
 		title = "API Key Authentication"
 	case "basic":
 		title = "Basic Authentication"
+	case "oauth2":
+		title = "OAuth2 Authentication"
+	case "login":
+		title = "Login Flow Authentication"
+	case "sigv4":
+		title = "AWS Signature V4 Authentication"
+	case "hmac":
+		title = "HMAC Signature Authentication"
 	}
 
 	b.WriteString(titleStyle.Render(title))
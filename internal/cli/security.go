@@ -0,0 +1,306 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/Octrafic/octrafic-cli/internal/core/auth"
+	"github.com/Octrafic/octrafic-cli/internal/core/parser"
+	"github.com/Octrafic/octrafic-cli/internal/core/tester"
+	"github.com/Octrafic/octrafic-cli/internal/infra/logger"
+	"github.com/Octrafic/octrafic-cli/internal/infra/storage"
+	"github.com/charmbracelet/lipgloss"
+	"go.uber.org/zap"
+)
+
+// SchemeProviders builds a provider for every security scheme of the spec
+// the project has credentials for.
+func SchemeProviders(project *storage.Project) (map[string]auth.AuthProvider, error) {
+	providers := make(map[string]auth.AuthProvider)
+	if project == nil {
+		return providers, nil
+	}
+	for name, cfg := range project.SchemeAuth {
+		provider, err := AuthFromConfig(cfg, project.BaseURL)
+		if err != nil {
+			return nil, fmt.Errorf("scheme %s: %w", name, err)
+		}
+		if oauth2, ok := provider.(*auth.OAuth2Auth); ok {
+			oauth2.OnRefreshToken = func(refreshToken string) {
+				cfg.RefreshToken = refreshToken
+				if err := storage.SaveProject(project); err != nil {
+					logger.Error("Failed to save refresh token", zap.Error(err))
+				}
+			}
+		}
+		providers[name] = provider
+	}
+	return providers, nil
+}
+
+// SchemeSelector picks the credentials of the security scheme an endpoint
+// requires. Endpoints for which no scheme has credentials get nil, so the
+// executor falls back to its default provider.
+func SchemeSelector(providers map[string]auth.AuthProvider, endpoints []parser.Endpoint) tester.AuthSelector {
+	return func(method, path string) auth.AuthProvider {
+		match := parser.FindEndpoint(endpoints, method, path)
+		if match == nil {
+			return nil
+		}
+		for _, req := range match.Security {
+			var required allOf
+			for _, scheme := range req.Schemes {
+				provider, ok := providers[scheme.Name]
+				if !ok {
+					required = nil
+					break
+				}
+				required = append(required, provider)
+			}
+			switch len(required) {
+			case 0:
+				continue
+			case 1:
+				return required[0]
+			default:
+				return required
+			}
+		}
+		return nil
+	}
+}
+
+// allOf applies the credentials of several schemes an endpoint requires
+// together.
+type allOf []auth.AuthProvider
+
+func (a allOf) Apply(req *http.Request) error {
+	for _, provider := range a {
+		if err := provider.Apply(req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a allOf) Type() string {
+	types := make([]string, len(a))
+	for i, provider := range a {
+		types[i] = provider.Type()
+	}
+	return strings.Join(types, "+")
+}
+
+func (a allOf) Validate() error {
+	for _, provider := range a {
+		if err := provider.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a allOf) Redact() auth.AuthProvider {
+	redacted := make(allOf, len(a))
+	for i, provider := range a {
+		redacted[i] = provider.Redact()
+	}
+	return redacted
+}
+
+// securitySummary describes an endpoint's security alternatives for the
+// agent, e.g. ["api_key (API key in header X-API-Key)", "oauth (OAuth2 ...) [read]"].
+func securitySummary(reqs []parser.SecurityRequirement) []string {
+	out := make([]string, 0, len(reqs))
+	for _, req := range reqs {
+		parts := make([]string, 0, len(req.Schemes))
+		for _, scheme := range req.Schemes {
+			part := scheme.Name + " (" + scheme.Summary() + ")"
+			if len(scheme.Scopes) > 0 {
+				part += " [" + strings.Join(scheme.Scopes, " ") + "]"
+			}
+			parts = append(parts, part)
+		}
+		out = append(out, strings.Join(parts, " + "))
+	}
+	return out
+}
+
+// PrefillSchemeFields fills the auth form with what a security scheme
+// declares: the API key name and location, or the OAuth2 flow and URLs.
+func PrefillSchemeFields(fields []FormField, scheme parser.SecurityScheme) []FormField {
+	values := map[string]string{}
+	switch scheme.AuthType() {
+	case "apikey":
+		values["key"] = scheme.ParamName
+		values["location"] = strings.ToLower(scheme.In)
+	case "oauth2":
+		if flow, ok := scheme.Flow(); ok {
+			switch flow.Type {
+			case "authorizationCode":
+				values["grant_type"] = auth.GrantAuthorizationCode
+			case "clientCredentials":
+				values["grant_type"] = auth.GrantClientCredentials
+			}
+			values["token_url"] = flow.TokenURL
+			values["authorize_url"] = flow.AuthorizationURL
+			scopes := scheme.Scopes
+			if len(scopes) == 0 {
+				for scope := range flow.Scopes {
+					scopes = append(scopes, scope)
+				}
+				slices.Sort(scopes)
+			}
+			values["scopes"] = strings.Join(scopes, " ")
+		}
+	}
+
+	for i := range fields {
+		value, ok := values[fields[i].Name]
+		if !ok || value == "" {
+			continue
+		}
+		if fields[i].IsRadio {
+			if j := slices.Index(fields[i].Options, value); j >= 0 {
+				fields[i].RadioIndex = j
+			}
+			continue
+		}
+		fields[i].Value = value
+	}
+	return fields
+}
+
+// AuthMismatch returns a warning when a provider does not satisfy any of the
+// security schemes the spec requires, or "" if it does or the spec declares
+// none.
+func AuthMismatch(schemes []parser.SecurityScheme, provider auth.AuthProvider) string {
+	var expected []string
+	for _, scheme := range schemes {
+		if scheme.AuthType() != "" {
+			expected = append(expected, scheme.Name+" ("+scheme.Summary()+")")
+		}
+	}
+	if len(expected) == 0 {
+		return ""
+	}
+	if provider == nil || provider.Type() == "none" {
+		return fmt.Sprintf("The spec requires authentication (%s) but none is configured.", strings.Join(expected, ", "))
+	}
+
+	switch provider.Type() {
+	case "login", "sigv4", "hmac":
+		// Specs cannot describe these; trust the configuration.
+		return ""
+	}
+
+	apiKey, isAPIKey := provider.(*auth.APIKeyAuth)
+	var keySchemes []parser.SecurityScheme
+	for _, scheme := range schemes {
+		if scheme.AuthType() != provider.Type() {
+			continue
+		}
+		if !isAPIKey || schemeMatchesAPIKey(scheme, apiKey) {
+			return ""
+		}
+		keySchemes = append(keySchemes, scheme)
+	}
+	if len(keySchemes) > 0 {
+		return fmt.Sprintf("The API key is sent in %s %s, but the spec expects it in %s %s.", apiKey.Location, apiKey.Key, keySchemes[0].In, keySchemes[0].ParamName)
+	}
+	return fmt.Sprintf("%s authentication is configured, but the spec expects %s.", provider.Type(), strings.Join(expected, " or "))
+}
+
+func schemeMatchesAPIKey(scheme parser.SecurityScheme, apiKey *auth.APIKeyAuth) bool {
+	if !strings.EqualFold(scheme.In, apiKey.Location) {
+		return false
+	}
+	if strings.EqualFold(scheme.In, "header") {
+		return strings.EqualFold(scheme.ParamName, apiKey.Key)
+	}
+	return scheme.ParamName == apiKey.Key
+}
+
+// specSchemes returns the security schemes of the current project's spec.
+func (m *TestUIModel) specSchemes() []parser.SecurityScheme {
+	if m.currentProject == nil {
+		return nil
+	}
+	endpoints, err := m.loadProjectEndpoints()
+	if err != nil {
+		return nil
+	}
+	return parser.SecuritySchemes(endpoints)
+}
+
+// applySchemeAuth makes the executor authenticate each endpoint with the
+// credentials saved for the scheme it requires.
+func (m *TestUIModel) applySchemeAuth() error {
+	if m.currentProject == nil {
+		return nil
+	}
+	providers, err := SchemeProviders(m.currentProject)
+	if err != nil {
+		return err
+	}
+	for name, provider := range m.schemeAuth {
+		providers[name] = provider
+	}
+	m.schemeAuth = providers
+	if len(providers) == 0 {
+		return nil
+	}
+	endpoints, err := m.loadProjectEndpoints()
+	if err != nil {
+		return err
+	}
+	m.testExecutor.SelectAuth(SchemeSelector(providers, endpoints))
+	return nil
+}
+
+// setSchemeAuth uses a provider for the endpoints that require a security
+// scheme, and saves it with named projects.
+func (m *TestUIModel) setSchemeAuth(scheme string, provider auth.AuthProvider) error {
+	if m.schemeAuth == nil {
+		m.schemeAuth = make(map[string]auth.AuthProvider)
+	}
+	m.schemeAuth[scheme] = provider
+	if m.currentProject != nil && !m.currentProject.IsTemporary {
+		cfg, err := AuthConfigFromProvider(provider)
+		if err != nil {
+			return err
+		}
+		m.currentProject.SetSchemeAuth(scheme, cfg)
+		if err := storage.SaveProject(m.currentProject); err != nil {
+			return fmt.Errorf("failed to save project: %w", err)
+		}
+	}
+	return m.applySchemeAuth()
+}
+
+// setupSchemeAuth loads the project's scheme credentials when a session
+// starts and warns if the authentication does not fit the spec.
+func (m *TestUIModel) setupSchemeAuth() {
+	if err := m.applySchemeAuth(); err != nil {
+		m.addMessage(m.errorStyle.Render("Failed to load scheme credentials: " + err.Error()))
+		m.addMessage("")
+	}
+	m.warnAuthMismatch()
+}
+
+// warnAuthMismatch shows a warning when the session's authentication does
+// not match the schemes of the spec that have no credentials of their own.
+func (m *TestUIModel) warnAuthMismatch() {
+	var schemes []parser.SecurityScheme
+	for _, scheme := range m.specSchemes() {
+		if _, ok := m.schemeAuth[scheme.Name]; !ok {
+			schemes = append(schemes, scheme)
+		}
+	}
+	if warning := AuthMismatch(schemes, m.authProvider); warning != "" {
+		m.addMessage(lipgloss.NewStyle().Foreground(Theme.Warning).Render("⚠ " + warning))
+		m.addMessage("")
+	}
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Octrafic/octrafic-cli/internal/core/auth"
+	"github.com/Octrafic/octrafic-cli/internal/core/parser"
+)
+
+var (
+	apiKeyScheme = parser.SecurityScheme{Name: "api_key", Type: "apiKey", In: "header", ParamName: "X-API-Key"}
+	jwtScheme    = parser.SecurityScheme{Name: "jwt", Type: "http", Scheme: "bearer"}
+	oauthScheme  = parser.SecurityScheme{
+		Name: "oauth",
+		Type: "oauth2",
+		Flows: []parser.OAuthFlow{{
+			Type:     "clientCredentials",
+			TokenURL: "https://auth.example.com/token",
+			Scopes:   map[string]string{"write": "", "read": ""},
+		}},
+	}
+)
+
+func TestSchemeSelector(t *testing.T) {
+	endpoints := []parser.Endpoint{
+		{Method: "GET", Path: "/users/{id}", Security: []parser.SecurityRequirement{{Schemes: []parser.SecurityScheme{apiKeyScheme}}}},
+		{Method: "GET", Path: "/users/me", Security: []parser.SecurityRequirement{
+			{Schemes: []parser.SecurityScheme{oauthScheme}},
+			{Schemes: []parser.SecurityScheme{jwtScheme}},
+		}},
+		{Method: "POST", Path: "/admin", Security: []parser.SecurityRequirement{{Schemes: []parser.SecurityScheme{apiKeyScheme, jwtScheme}}}},
+	}
+	apiKey := auth.NewAPIKeyAuth("X-API-Key", "key-123", "header")
+	bearer := auth.NewBearerAuth("token-456")
+	selector := SchemeSelector(map[string]auth.AuthProvider{"api_key": apiKey, "jwt": bearer}, endpoints)
+
+	if got := selector("GET", "/users/42?expand=1"); got != apiKey {
+		t.Errorf("expected the api key for /users/{id}, got %v", got)
+	}
+	if got := selector("GET", "/users/me"); got != bearer {
+		t.Errorf("expected the bearer token for /users/me, got %v", got)
+	}
+	if got := selector("GET", "/unknown"); got != nil {
+		t.Errorf("expected no provider for an unknown endpoint, got %v", got)
+	}
+
+	both := selector("POST", "/admin")
+	req, _ := http.NewRequest("POST", "http://example.com/admin", nil)
+	if err := both.Apply(req); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if req.Header.Get("X-API-Key") != "key-123" || req.Header.Get("Authorization") != "Bearer token-456" {
+		t.Errorf("expected both schemes to be applied, got %v", req.Header)
+	}
+}
+
+func TestPrefillSchemeFields(t *testing.T) {
+	fields := PrefillSchemeFields(CreateAuthFormFields("apikey"), parser.SecurityScheme{Type: "apiKey", In: "cookie", ParamName: "session"})
+	values := map[string]FormField{}
+	for _, f := range fields {
+		values[f.Name] = f
+	}
+	if values["key"].Value != "session" || values["location"].Options[values["location"].RadioIndex] != "cookie" {
+		t.Errorf("expected the cookie key to be prefilled, got %+v", fields)
+	}
+
+	fields = PrefillSchemeFields(CreateAuthFormFields("oauth2"), oauthScheme)
+	for _, f := range fields {
+		values[f.Name] = f
+	}
+	if values["token_url"].Value != "https://auth.example.com/token" || values["scopes"].Value != "read write" {
+		t.Errorf("expected the OAuth2 flow to be prefilled, got %+v", fields)
+	}
+	if values["grant_type"].Options[values["grant_type"].RadioIndex] != auth.GrantClientCredentials {
+		t.Errorf("expected the client_credentials grant")
+	}
+}
+
+func TestAuthMismatch(t *testing.T) {
+	schemes := []parser.SecurityScheme{apiKeyScheme}
+	if w := AuthMismatch(schemes, auth.NewAPIKeyAuth("x-api-key", "k", "header")); w != "" {
+		t.Errorf("header names are case-insensitive, got %q", w)
+	}
+	if w := AuthMismatch(schemes, auth.NewAPIKeyAuth("api_key", "k", "query")); !strings.Contains(w, "header X-API-Key") {
+		t.Errorf("expected a location mismatch, got %q", w)
+	}
+	if w := AuthMismatch(schemes, auth.NewBearerAuth("t")); !strings.Contains(w, "bearer authentication is configured") {
+		t.Errorf("expected a type mismatch, got %q", w)
+	}
+	if w := AuthMismatch(schemes, &auth.NoAuth{}); !strings.Contains(w, "none is configured") {
+		t.Errorf("expected a missing auth warning, got %q", w)
+	}
+	if w := AuthMismatch(nil, &auth.NoAuth{}); w != "" {
+		t.Errorf("specs without security need no auth, got %q", w)
+	}
+}
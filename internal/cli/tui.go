@@ -129,6 +129,8 @@ type TestUIModel struct {
 	localAgent     *agent.Agent
 	testExecutor   *tester.Executor
 	authProvider   auth.AuthProvider
+	identities     map[string]auth.AuthProvider // Providers of the project's named identities, built on first use
+	schemeAuth     map[string]auth.AuthProvider // Providers for the spec's security schemes, by scheme name
 
 	// Agent state
 	agentState               AgentState
@@ -197,6 +199,8 @@ type TestUIModel struct {
 	currentTestToolName     string           // Name of the tool being executed (e.g., "ExecuteTestGroup")
 	currentTestToolID       string           // ID of the tool_use for FunctionResponse
 	testVars                map[string]string
+	lastTestGroup           []map[string]any        // tests of the last group, for save_test_suite
+	webhooks                *tester.WebhookReceiver // started on first use of {{$webhookUrl}}
 
 	// Version
 	currentVersion string
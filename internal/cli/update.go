@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -494,9 +495,18 @@ func (m *TestUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case startTestGroupMsg:
 		return handleStartTestGroup(m, msg)
 
+	case oauth2LoginStartedMsg:
+		return handleOAuth2LoginStarted(m, msg)
+
+	case oauth2LoginDoneMsg:
+		return handleOAuth2LoginDone(m, msg)
+
 	case runNextTestMsg:
 		return handleRunNextTest(m, msg)
 
+	case testDoneMsg:
+		return handleTestDone(m, msg)
+
 	case spinner.TickMsg:
 		var cmd tea.Cmd
 		m.spinner, cmd = m.spinner.Update(msg)
@@ -877,7 +887,7 @@ func handleSlashCommands(m *TestUIModel, userInput string) (*TestUIModel, tea.Cm
 		m.addMessage(m.subtleStyle.Render("Opening authentication wizard..."))
 		m.lastMessageRole = "assistant"
 
-		m.wizardState = NewAuthWizard()
+		m.wizardState = NewAuthWizard(m.specSchemes()...)
 		m.agentState = StateWizard
 		return m, nil, true
 
@@ -933,6 +943,21 @@ func handleSlashCommands(m *TestUIModel, userInput string) (*TestUIModel, tea.Cm
 			}
 		}
 		m.addMessage(m.subtleStyle.Render(fmt.Sprintf("  Created: %s", m.currentProject.CreatedAt.Format("2006-01-02 15:04"))))
+		if m.authProvider != nil && m.authProvider.Type() != "none" {
+			m.addMessage(fmt.Sprintf("  Auth: %s", m.authProvider.Type()))
+			for _, line := range m.jwtInfo() {
+				m.addMessage(m.subtleStyle.Render("  " + line))
+			}
+		}
+		if findings, err := storage.LoadFindings(m.currentProject.ID, m.currentProject.IsTemporary); err == nil && len(findings) > 0 {
+			failed := 0
+			for _, finding := range findings {
+				if finding.Severe() {
+					failed++
+				}
+			}
+			m.addMessage(fmt.Sprintf("  Findings: %d recorded, %d high or critical", len(findings), failed))
+		}
 		m.lastMessageRole = "assistant"
 		return m, nil, true
 
@@ -1139,7 +1164,8 @@ func handleAuthCommand(m *TestUIModel, userInput string) (*TestUIModel, tea.Cmd,
 	parts := strings.Fields(userInput)
 	if len(parts) < 2 {
 		m.addMessage(m.errorStyle.Render("Usage: auth <command>"))
-		m.addMessage(m.subtleStyle.Render("Commands: bearer <token> | apikey <key> <value> | basic <user> <pass> | show | clear"))
+		m.addMessage(m.subtleStyle.Render("Commands: bearer <token> | apikey <key> <value> | basic <user> <pass> | login | show | clear"))
+		m.addMessage(m.subtleStyle.Render("Identities: identities | use <name> | save <name> | remove <name>"))
 		m.lastMessageRole = "assistant"
 		return m, nil, true
 	}
@@ -1152,7 +1178,7 @@ func handleAuthCommand(m *TestUIModel, userInput string) (*TestUIModel, tea.Cmd,
 			m.lastMessageRole = "assistant"
 			return m, nil, true
 		}
-		m.authProvider = auth.NewBearerAuth(parts[2])
+		m.authProvider = auth.AllowReferences(auth.NewBearerAuth(parts[2]))
 		m.testExecutor.UpdateAuthProvider(m.authProvider)
 		m.addMessage(m.successStyle.Render("✓ Bearer authentication configured"))
 		m.lastMessageRole = "assistant"
@@ -1165,7 +1191,7 @@ func handleAuthCommand(m *TestUIModel, userInput string) (*TestUIModel, tea.Cmd,
 			m.lastMessageRole = "assistant"
 			return m, nil, true
 		}
-		m.authProvider = auth.NewAPIKeyAuth(parts[2], parts[3], "header")
+		m.authProvider = auth.AllowReferences(auth.NewAPIKeyAuth(parts[2], parts[3], "header"))
 		m.testExecutor.UpdateAuthProvider(m.authProvider)
 		m.addMessage(m.successStyle.Render(fmt.Sprintf("✓ API Key authentication configured (%s)", parts[2])))
 		m.lastMessageRole = "assistant"
@@ -1177,26 +1203,101 @@ func handleAuthCommand(m *TestUIModel, userInput string) (*TestUIModel, tea.Cmd,
 			m.lastMessageRole = "assistant"
 			return m, nil, true
 		}
-		m.authProvider = auth.NewBasicAuth(parts[2], parts[3])
+		m.authProvider = auth.AllowReferences(auth.NewBasicAuth(parts[2], parts[3]))
 		m.testExecutor.UpdateAuthProvider(m.authProvider)
 		m.addMessage(m.successStyle.Render(fmt.Sprintf("✓ Basic authentication configured (%s)", parts[2])))
 		m.lastMessageRole = "assistant"
 		return m, nil, true
 
+	case "login":
+		provider, ok := m.authProvider.(*auth.OAuth2Auth)
+		if !ok || provider.GrantType != auth.GrantAuthorizationCode {
+			m.addMessage(m.errorStyle.Render("auth login needs OAuth2 with the authorization_code grant"))
+			m.addMessage(m.subtleStyle.Render("Configure it with /auth → OAuth2"))
+			m.lastMessageRole = "assistant"
+			return m, nil, true
+		}
+		m.lastMessageRole = "assistant"
+		return m, startOAuth2Login(provider), true
+
 	case "show":
 		if m.authProvider == nil {
 			m.addMessage(m.subtleStyle.Render("No authentication configured"))
 		} else {
-			redacted := m.authProvider.Redact()
-			if stringer, ok := redacted.(fmt.Stringer); ok {
-				m.addMessage(m.subtleStyle.Render("Current auth: " + stringer.String()))
-			} else {
-				m.addMessage(m.subtleStyle.Render(fmt.Sprintf("Current auth: %s", redacted.Type())))
+			m.addMessage(m.subtleStyle.Render("Current auth: " + describeAuth(m.authProvider)))
+		}
+		if m.currentProject != nil && len(m.currentProject.Identities) > 0 {
+			m.addMessage(m.subtleStyle.Render("Identities: " + strings.Join(m.currentProject.IdentityNames(), ", ")))
+		}
+		m.lastMessageRole = "assistant"
+		return m, nil, true
+
+	case "identities":
+		if m.currentProject == nil || len(m.currentProject.Identities) == 0 {
+			m.addMessage(m.subtleStyle.Render("No identities saved. Use auth save <name> or the /auth wizard to add one."))
+		} else {
+			for _, name := range m.currentProject.IdentityNames() {
+				provider, err := AuthFromConfig(m.currentProject.Identities[name], m.currentProject.BaseURL)
+				if err != nil {
+					m.addMessage(m.errorStyle.Render(fmt.Sprintf("  %s: %v", name, err)))
+					continue
+				}
+				m.addMessage(m.subtleStyle.Render(fmt.Sprintf("  %s: %s", name, describeAuth(provider))))
 			}
 		}
 		m.lastMessageRole = "assistant"
 		return m, nil, true
 
+	case "use":
+		if len(parts) < 3 {
+			m.addMessage(m.errorStyle.Render("Usage: auth use <identity>"))
+			m.lastMessageRole = "assistant"
+			return m, nil, true
+		}
+		if _, err := m.executorFor(parts[2]); err != nil {
+			m.addMessage(m.errorStyle.Render(err.Error()))
+			m.lastMessageRole = "assistant"
+			return m, nil, true
+		}
+		m.authProvider = m.identities[parts[2]]
+		m.testExecutor.UpdateAuthProvider(m.authProvider)
+		m.addMessage(m.successStyle.Render(fmt.Sprintf("✓ Using identity '%s'", parts[2])))
+		m.lastMessageRole = "assistant"
+		return m, nil, true
+
+	case "save":
+		if len(parts) < 3 {
+			m.addMessage(m.errorStyle.Render("Usage: auth save <identity>"))
+			m.lastMessageRole = "assistant"
+			return m, nil, true
+		}
+		if m.authProvider == nil {
+			m.addMessage(m.errorStyle.Render("No authentication configured"))
+		} else if err := m.saveIdentity(parts[2], m.authProvider); err != nil {
+			m.addMessage(m.errorStyle.Render("Failed to save identity: " + err.Error()))
+		} else {
+			m.addMessage(m.successStyle.Render(fmt.Sprintf("✓ Current authentication saved as identity '%s'", parts[2])))
+		}
+		m.lastMessageRole = "assistant"
+		return m, nil, true
+
+	case "remove":
+		if len(parts) < 3 {
+			m.addMessage(m.errorStyle.Render("Usage: auth remove <identity>"))
+			m.lastMessageRole = "assistant"
+			return m, nil, true
+		}
+		if m.currentProject == nil || !m.currentProject.RemoveIdentity(parts[2]) {
+			m.addMessage(m.errorStyle.Render(fmt.Sprintf("Identity '%s' not found", parts[2])))
+		} else if err := storage.SaveProject(m.currentProject); err != nil {
+			m.addMessage(m.errorStyle.Render("Failed to save project: " + err.Error()))
+		} else {
+			m.identities = nil
+			m.addMessage(m.successStyle.Render(fmt.Sprintf("✓ Identity '%s' removed", parts[2])))
+		}
+		m.lastMessageRole = "assistant"
+		return m, nil, true
+
 	case "clear":
 		m.authProvider = &auth.NoAuth{}
 		m.testExecutor.UpdateAuthProvider(m.authProvider)
@@ -1206,7 +1307,7 @@ func handleAuthCommand(m *TestUIModel, userInput string) (*TestUIModel, tea.Cmd,
 
 	default:
 		m.addMessage(m.errorStyle.Render(fmt.Sprintf("Unknown auth command: %s", subCmd)))
-		m.addMessage(m.subtleStyle.Render("Commands: bearer | apikey | basic | show | clear"))
+		m.addMessage(m.subtleStyle.Render("Commands: bearer | apikey | basic | login | show | clear | identities | use | save | remove"))
 		m.lastMessageRole = "assistant"
 		return m, nil, true
 	}
@@ -1256,16 +1357,7 @@ func handleTestPlanState(m *TestUIModel, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 		tests := make([]map[string]any, 0)
 		for _, test := range selectedTests {
-			tests = append(tests, map[string]any{
-				"method":          test.Method,
-				"endpoint":        test.Endpoint,
-				"headers":         test.BackendTest.Headers,
-				"body":            test.BackendTest.Body,
-				"requires_auth":   test.BackendTest.RequiresAuth,
-				"expected_status": test.BackendTest.ExpectedStatus,
-				"extract":         extractsToAny(test.BackendTest.Extract),
-				"assertions":      assertionsToAny(test.BackendTest.Assertions),
-			})
+			tests = append(tests, testCaseToMap(test.BackendTest))
 		}
 
 		label := "Running tests"
@@ -1544,6 +1636,45 @@ func handleProcessToolCalls(m *TestUIModel, _ processToolCallsMsg) (tea.Model, t
 			m.spinner.Style = lipgloss.NewStyle().Foreground(Theme.Primary)
 			return m, tea.Batch(animationTick(), m.executeTool(toolCall))
 
+		case agent.ToolLoadTestSuite, agent.ToolSaveTestSuite:
+			m.currentTestToolID = toolCall.ID
+			m.currentTestToolName = toolCall.Name
+
+			name, _ := toolCall.Arguments["name"].(string)
+			if name == "" {
+				name = "list"
+			}
+			m.showToolMessage(agent.GetToolMeta(toolCall.Name).WidgetTitle, name)
+			m.updateViewport()
+			m.agentState = StateUsingTool
+			m.animationFrame = 0
+			m.spinner.Style = lipgloss.NewStyle().Foreground(Theme.Primary)
+			return m, tea.Batch(animationTick(), m.executeTool(toolCall))
+
+		case agent.ToolProbeJWT:
+			m.currentTestToolID = toolCall.ID
+			m.currentTestToolName = toolCall.Name
+
+			method, _ := toolCall.Arguments["method"].(string)
+			path, _ := toolCall.Arguments["path"].(string)
+			m.showToolMessage(agent.GetToolMeta(toolCall.Name).WidgetTitle, strings.ToUpper(method)+" "+path)
+			m.updateViewport()
+			m.agentState = StateUsingTool
+			m.animationFrame = 0
+			m.spinner.Style = lipgloss.NewStyle().Foreground(Theme.Primary)
+			return m, tea.Batch(animationTick(), m.executeTool(toolCall))
+
+		case agent.ToolLintSpec:
+			m.currentTestToolID = toolCall.ID
+			m.currentTestToolName = toolCall.Name
+
+			m.showToolMessage(agent.GetToolMeta(toolCall.Name).WidgetTitle, filepath.Base(m.specPath))
+			m.updateViewport()
+			m.agentState = StateUsingTool
+			m.animationFrame = 0
+			m.spinner.Style = lipgloss.NewStyle().Foreground(Theme.Primary)
+			return m, tea.Batch(animationTick(), m.executeTool(toolCall))
+
 		case agent.ToolWait:
 			m.currentTestToolID = toolCall.ID
 			m.currentTestToolName = agent.ToolWait
@@ -1592,52 +1723,8 @@ func handleProcessToolCalls(m *TestUIModel, _ processToolCallsMsg) (tea.Model, t
 func handleShowTestSelection(m *TestUIModel, msg showTestSelectionMsg) (tea.Model, tea.Cmd) {
 	m.tests = make([]Test, 0, len(msg.tests))
 	for i, testMap := range msg.tests {
-		method, _ := testMap["method"].(string)
-		endpoint, _ := testMap["endpoint"].(string)
-
-		requiresAuth := false
-		if ra, ok := testMap["requires_auth"].(bool); ok {
-			requiresAuth = ra
-		}
-
-		expectedStatus := 0
-		if es, ok := testMap["expected_status"].(float64); ok {
-			expectedStatus = int(es)
-		} else if es, ok := testMap["expected_status"].(int); ok {
-			expectedStatus = es
-		}
-
-		headers := make(map[string]string)
-		if h, ok := testMap["headers"].(map[string]string); ok {
-			headers = h
-		}
-
-		var extractList []agent.Extract
-		for _, e := range toMapsSlice(testMap["extract"]) {
-			field, _ := e["field"].(string)
-			as, _ := e["as"].(string)
-			if field != "" && as != "" {
-				extractList = append(extractList, agent.Extract{Field: field, As: as})
-			}
-		}
-		var assertionList []agent.Assertion
-		for _, a := range toMapsSlice(testMap["assertions"]) {
-			field, _ := a["field"].(string)
-			op, _ := a["op"].(string)
-			if field != "" && op != "" {
-				assertionList = append(assertionList, agent.Assertion{Field: field, Op: op, Value: a["value"]})
-			}
-		}
-		testCase := &agent.TestCase{
-			Method:         method,
-			Endpoint:       endpoint,
-			Headers:        headers,
-			Body:           testMap["body"],
-			RequiresAuth:   requiresAuth,
-			ExpectedStatus: expectedStatus,
-			Extract:        extractList,
-			Assertions:     assertionList,
-		}
+		testCase := testCaseFromMap(testMap)
+		method, endpoint := testCase.Method, testCase.Endpoint
 
 		m.tests = append(m.tests, Test{
 			ID:          i + 1,
@@ -1665,16 +1752,7 @@ func handleShowTestSelection(m *TestUIModel, msg showTestSelectionMsg) (tea.Mode
 
 		tests := make([]map[string]any, 0)
 		for _, test := range m.tests {
-			tests = append(tests, map[string]any{
-				"method":          test.Method,
-				"endpoint":        test.Endpoint,
-				"headers":         test.BackendTest.Headers,
-				"body":            test.BackendTest.Body,
-				"requires_auth":   test.BackendTest.RequiresAuth,
-				"expected_status": test.BackendTest.ExpectedStatus,
-				"extract":         extractsToAny(test.BackendTest.Extract),
-				"assertions":      assertionsToAny(test.BackendTest.Assertions),
-			})
+			tests = append(tests, testCaseToMap(test.BackendTest))
 		}
 
 		label := "Running tests"
@@ -4,9 +4,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	agent "github.com/Octrafic/octrafic-cli/internal/agents"
+	"github.com/Octrafic/octrafic-cli/internal/config"
+	"github.com/Octrafic/octrafic-cli/internal/core/graphql"
 	"github.com/Octrafic/octrafic-cli/internal/core/tester"
+	"github.com/Octrafic/octrafic-cli/internal/infra/logger"
+	"github.com/Octrafic/octrafic-cli/internal/infra/storage"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -36,6 +41,11 @@ func handleGenerateTestPlanResult(m *TestUIModel, msg generateTestPlanResultMsg)
 			"expected_status": bt.TestCase.ExpectedStatus,
 			"extract":         bt.TestCase.Extract,
 			"assertions":      bt.TestCase.Assertions,
+			"graphql":         bt.TestCase.GraphQL,
+			"poll":            bt.TestCase.Poll,
+			"paginate":        bt.TestCase.Paginate,
+			"webhook":         bt.TestCase.Webhook,
+			"websocket":       bt.TestCase.WebSocket,
 		})
 	}
 
@@ -92,6 +102,7 @@ func handleGenerateTestPlanResult(m *TestUIModel, msg generateTestPlanResultMsg)
 // handleStartTestGroup initiates execution of a test group.
 func handleStartTestGroup(m *TestUIModel, msg startTestGroupMsg) (tea.Model, tea.Cmd) {
 	m.pendingTests = msg.tests
+	m.lastTestGroup = msg.tests
 	m.currentTestGroupLabel = msg.label
 	m.currentTestToolName = msg.toolName
 
@@ -106,19 +117,54 @@ func handleStartTestGroup(m *TestUIModel, msg startTestGroupMsg) (tea.Model, tea
 
 	m.addMessage("")
 	m.addMessage(m.subtleStyle.Render(msg.label))
+	m.warnTokenExpiry(len(msg.tests))
 	m.updateViewport()
 
 	return m, runNextTest()
 }
 
-// applyVars substitutes {{var}} placeholders with values from m.testVars.
+// webhookURLVar is replaced with the address of the local webhook receiver.
+const webhookURLVar = "{{$webhookUrl}}"
+
+// applyVars substitutes {{var}} placeholders with values from m.testVars,
+// and {{$webhookUrl}} with the webhook receiver's URL.
 func (m *TestUIModel) applyVars(s string) string {
 	for k, v := range m.testVars {
 		s = strings.ReplaceAll(s, "{{"+k+"}}", v)
 	}
+	if strings.Contains(s, webhookURLVar) {
+		receiver, err := m.webhookReceiver()
+		if err != nil {
+			logger.Warn("Webhook receiver unavailable", logger.Err(err))
+			return s
+		}
+		s = strings.ReplaceAll(s, webhookURLVar, receiver.URL())
+	}
 	return s
 }
 
+// webhookReceiver starts the local callback listener on first use.
+// OCTRAFIC_WEBHOOK_ADDR sets the listen address and OCTRAFIC_WEBHOOK_URL the
+// public URL, e.g. a tunnel, when the API cannot reach this machine directly.
+func (m *TestUIModel) webhookReceiver() (*tester.WebhookReceiver, error) {
+	if m.webhooks == nil {
+		receiver, err := tester.StartWebhookReceiver(config.GetEnv("WEBHOOK_ADDR"), config.GetEnv("WEBHOOK_URL"))
+		if err != nil {
+			return nil, err
+		}
+		m.webhooks = receiver
+	}
+	return m.webhooks, nil
+}
+
+// closeWebhooks stops the webhook receiver if it was started.
+func (m *TestUIModel) closeWebhooks() {
+	if m.webhooks != nil {
+		_ = m.webhooks.Close()
+		m.webhooks = nil
+	}
+}
+
 // extractVars runs extract rules against a response body, storing results in m.testVars.
 func (m *TestUIModel) extractVars(body string, extracts []map[string]any) {
 	if len(extracts) == 0 || strings.TrimSpace(body) == "" {
@@ -199,47 +245,249 @@ func handleRunNextTest(m *TestUIModel, _ runNextTestMsg) (tea.Model, tea.Cmd) {
 	testMap := m.pendingTests[0]
 	m.pendingTests = m.pendingTests[1:]
 
-	method, _ := testMap["method"].(string)
-	endpoint, _ := testMap["endpoint"].(string)
+	pt := m.prepareTest(testMap)
 
-	if m.testVars != nil {
-		endpoint = m.applyVars(endpoint)
+	if pt.webhook != nil {
+		if _, err := m.webhookReceiver(); err != nil {
+			return handleTestDone(m, testDoneMsg{test: pt, err: err})
+		}
 	}
 
-	requiresAuth := false
-	if ra, ok := testMap["requires_auth"].(bool); ok {
-		requiresAuth = ra
+	executor, err := m.executorFor(pt.identity)
+	if err != nil {
+		return handleTestDone(m, testDoneMsg{test: pt, err: err})
 	}
 
-	expectedStatus := 0
-	if es, ok := testMap["expected_status"].(float64); ok {
-		expectedStatus = int(es)
-	} else if es, ok := testMap["expected_status"].(int); ok {
-		expectedStatus = es
+	if pt.websocket != nil {
+		m.addMessage(m.subtleStyle.Render(fmt.Sprintf("  Connecting to %s, waiting up to %s for %d message(s)...",
+			pt.endpoint, pt.websocket.Timeout, pt.websocket.Receive)))
+		m.updateViewport()
+		return m, func() tea.Msg {
+			result, err := executor.ExecuteWebSocket(pt.method, pt.endpoint, pt.headers, *pt.websocket, pt.requiresAuth)
+			return testDoneMsg{test: pt, result: result, err: err}
+		}
 	}
-	if expectedStatus == 0 {
-		expectedStatus = 200
+
+	if pt.poll == nil && pt.paginate == nil && pt.webhook == nil {
+		result, err := executor.ExecuteTest(pt.method, pt.endpoint, pt.headers, pt.body, pt.requiresAuth)
+		return handleTestDone(m, testDoneMsg{test: pt, result: result, err: err})
 	}
 
-	headers := make(map[string]string)
-	if h, ok := testMap["headers"].(map[string]any); ok {
-		for k, v := range h {
-			if vs, ok := v.(string); ok {
-				headers[k] = vs
+	if pt.poll != nil {
+		m.addMessage(m.subtleStyle.Render(fmt.Sprintf("  Polling %s %s every %s for up to %s...",
+			pt.method, pt.endpoint, pt.poll.Interval, pt.poll.Timeout)))
+	} else if pt.paginate != nil {
+		m.addMessage(m.subtleStyle.Render(fmt.Sprintf("  Following pages of %s %s (up to %d)...",
+			pt.method, pt.endpoint, pt.paginate.MaxPages)))
+	}
+	if pt.webhook != nil {
+		m.addMessage(m.subtleStyle.Render(fmt.Sprintf("  Waiting up to %s for a callback at %s...",
+			pt.webhook.Timeout, m.webhooks.URL()+pt.webhook.Path)))
+	}
+	m.updateViewport()
+
+	receiver := m.webhooks
+	return m, func() tea.Msg {
+		since := time.Now()
+		var msg testDoneMsg
+		switch {
+		case pt.poll != nil:
+			res, err := tester.Poll(func() (*tester.TestResult, error) {
+				return executor.ExecuteTest(pt.method, pt.endpoint, pt.headers, pt.body, pt.requiresAuth)
+			}, *pt.poll)
+			msg = testDoneMsg{test: pt, poll: res, err: err}
+			if res != nil {
+				msg.result = res.Result
+			}
+		case pt.paginate != nil:
+			res, err := tester.Paginate(func(ep string) (*tester.TestResult, error) {
+				return executor.ExecuteTest(pt.method, ep, pt.headers, pt.body, pt.requiresAuth)
+			}, pt.endpoint, *pt.paginate)
+			msg = testDoneMsg{test: pt, pagination: res, err: err}
+			if res != nil {
+				msg.result = res.First
 			}
+		default:
+			result, err := executor.ExecuteTest(pt.method, pt.endpoint, pt.headers, pt.body, pt.requiresAuth)
+			msg = testDoneMsg{test: pt, result: result, err: err}
+		}
+		if pt.webhook != nil && msg.err == nil {
+			msg.webhook = receiver.Wait(since, *pt.webhook)
 		}
+		return msg
+	}
+}
+
+// preparedTest is a queued test with variables applied, ready to execute.
+type preparedTest struct {
+	testMap        map[string]any
+	method         string
+	endpoint       string
+	headers        map[string]string
+	body           any
+	requiresAuth   bool
+	identity       string
+	expectedStatus int
+	isGraphQL      bool
+	gqlField       string
+	poll           *tester.PollOptions
+	pollMessage    string
+	paginate       *tester.PaginateOptions
+	aggregate      []map[string]any
+	webhook        *tester.WebhookOptions
+	webhookName    string
+	webhookMessage string
+	websocket      *tester.WebSocketOptions
+}
+
+// testDoneMsg carries the outcome of a test executed in the background.
+type testDoneMsg struct {
+	test       preparedTest
+	result     *tester.TestResult
+	poll       *tester.PollResult
+	pagination *tester.PaginationResult
+	webhook    *tester.WebhookResult
+	err        error
+}
+
+// prepareTest resolves a queued test map into a request.
+func (m *TestUIModel) prepareTest(testMap map[string]any) preparedTest {
+	pt := preparedTest{testMap: testMap}
+	pt.method, _ = testMap["method"].(string)
+	pt.endpoint, _ = testMap["endpoint"].(string)
+
+	pt.endpoint = m.applyVars(pt.endpoint)
+
+	pt.requiresAuth, _ = testMap["requires_auth"].(bool)
+
+	// A named identity always authenticates; anonymous never does.
+	pt.identity, _ = testMap["identity"].(string)
+	if pt.identity != "" {
+		pt.requiresAuth = pt.identity != storage.AnonymousIdentity
+	}
+
+	pt.expectedStatus = toInt(testMap["expected_status"])
+	if pt.expectedStatus == 0 {
+		pt.expectedStatus = 200
+	}
+
+	pt.headers = toStringMap(testMap["headers"])
+	for k, v := range pt.headers {
+		pt.headers[k] = m.applyVars(v)
 	}
 
-	var body any
 	if b, ok := testMap["body"]; ok {
-		if bs, ok := b.(string); ok && m.testVars != nil {
-			body = m.applyVars(bs)
+		if bs, ok := b.(string); ok {
+			pt.body = m.applyVars(bs)
 		} else {
-			body = b
+			pt.body = b
+		}
+	}
+
+	// GraphQL operations are always POSTed as {"query", "variables"}.
+	if gql, ok := testMap["graphql"].(map[string]any); ok {
+		pt.isGraphQL = true
+		query, _ := gql["query"].(string)
+		vars, _ := gql["variables"].(map[string]any)
+		opName, _ := gql["operation_name"].(string)
+		pt.gqlField, _ = gql["field"].(string)
+		pt.method = "POST"
+		pt.body = m.applyVars(graphql.RequestBody(query, vars, opName))
+	}
+
+	if p, ok := testMap["poll"].(map[string]any); ok {
+		until := toMapsSlice(p["until"])
+		if pt.isGraphQL {
+			until = graphQLPaths(until)
 		}
+		pt.poll = &tester.PollOptions{
+			Until:       until,
+			UntilStatus: toInt(p["until_status"]),
+			Interval:    toSeconds(p["interval_seconds"], tester.DefaultPollInterval),
+			Timeout:     toSeconds(p["timeout_seconds"], tester.DefaultPollTimeout),
+		}
+		pt.pollMessage, _ = p["message"].(string)
+	}
+
+	// Polling takes precedence; a paginated test is a plain list request.
+	if p, ok := testMap["paginate"].(map[string]any); ok && pt.poll == nil {
+		opts := &tester.PaginateOptions{
+			BaseURL:  m.baseURL,
+			PageSize: toInt(p["page_size"]),
+			MaxPages: toInt(p["max_pages"]),
+		}
+		opts.Style, _ = p["style"].(string)
+		opts.ItemsField, _ = p["items_field"].(string)
+		opts.NextField, _ = p["next_field"].(string)
+		opts.CursorParam, _ = p["cursor_param"].(string)
+		opts.PageParam, _ = p["page_param"].(string)
+		opts.OffsetParam, _ = p["offset_param"].(string)
+		opts.SizeParam, _ = p["size_param"].(string)
+		opts.IDField, _ = p["id_field"].(string)
+		opts.TotalField, _ = p["total_field"].(string)
+		if opts.MaxPages <= 0 {
+			opts.MaxPages = tester.DefaultMaxPages
+		}
+		pt.paginate = opts
+		pt.aggregate = toMapsSlice(p["aggregate"])
+	}
+
+	if w, ok := testMap["webhook"].(map[string]any); ok {
+		opts := &tester.WebhookOptions{
+			Until:   toMapsSlice(w["until"]),
+			Timeout: toSeconds(w["timeout_seconds"], tester.DefaultWebhookTimeout),
+		}
+		opts.Path, _ = w["path"].(string)
+		opts.Method, _ = w["method"].(string)
+		pt.webhook = opts
+		pt.webhookName, _ = w["name"].(string)
+		pt.webhookMessage, _ = w["message"].(string)
+	}
+
+	// WebSocket channels are connected to instead of requested.
+	switch ws := testMap["websocket"].(type) {
+	case map[string]any:
+		send, _ := ws["send"].([]any)
+		pt.websocket = &tester.WebSocketOptions{
+			Send:    send,
+			Receive: toInt(ws["receive"]),
+			Timeout: toSeconds(ws["timeout_seconds"], tester.DefaultWebSocketTimeout),
+		}
+	case *agent.WebSocketExchange:
+		if ws != nil {
+			pt.websocket = &tester.WebSocketOptions{
+				Send:    ws.Send,
+				Receive: ws.Receive,
+				Timeout: toSeconds(ws.TimeoutSeconds, tester.DefaultWebSocketTimeout),
+			}
+		}
+	}
+	if pt.websocket != nil {
+		send := make([]any, 0, len(pt.websocket.Send))
+		for _, msg := range pt.websocket.Send {
+			text, ok := msg.(string)
+			if !ok {
+				data, err := json.Marshal(msg)
+				if err != nil {
+					send = append(send, msg)
+					continue
+				}
+				text = string(data)
+			}
+			send = append(send, m.applyVars(text))
+		}
+		pt.websocket.Send = send
 	}
 
-	result, err := m.testExecutor.ExecuteTest(method, endpoint, headers, body, requiresAuth)
+	return pt
+}
+
+// handleTestDone reports a finished test and queues the next one.
+func handleTestDone(m *TestUIModel, msg testDoneMsg) (tea.Model, tea.Cmd) {
+	pt := msg.test
+	method, endpoint := pt.method, pt.endpoint
+	requiresAuth, expectedStatus := pt.requiresAuth, pt.expectedStatus
+	result, err := msg.result, msg.err
 
 	methodStyle, ok := m.methodStyles[method]
 	if !ok {
@@ -248,7 +496,9 @@ func handleRunNextTest(m *TestUIModel, _ runNextTestMsg) (tea.Model, tea.Cmd) {
 	methodFormatted := methodStyle.Render(method)
 
 	authIndicator := ""
-	if requiresAuth {
+	if pt.identity != "" {
+		authIndicator = " " + lipgloss.NewStyle().Foreground(Theme.Warning).Render("• as "+pt.identity)
+	} else if requiresAuth {
 		authIndicator = " " + lipgloss.NewStyle().Foreground(Theme.Warning).Render("• Auth")
 	}
 
@@ -259,24 +509,62 @@ func handleRunNextTest(m *TestUIModel, _ runNextTestMsg) (tea.Model, tea.Cmd) {
 			m.headlessExitCode = 1
 		}
 
-		m.testGroupResults = append(m.testGroupResults, map[string]any{
+		testResult := map[string]any{
 			"method":          method,
 			"endpoint":        endpoint,
 			"error":           err.Error(),
 			"requires_auth":   requiresAuth,
 			"expected_status": expectedStatus,
 			"passed":          false,
-		})
+		}
+		if pt.identity != "" {
+			testResult["identity"] = pt.identity
+		}
+		if msg.poll != nil {
+			testResult["poll_attempts"] = msg.poll.Attempts
+			testResult["poll_satisfied"] = false
+		}
+		m.testGroupResults = append(m.testGroupResults, testResult)
 	} else {
-		if extracts := toMapsSlice(testMap["extract"]); len(extracts) > 0 {
+		testMap := pt.testMap
+		extracts := toMapsSlice(testMap["extract"])
+		assertions := toMapsSlice(testMap["assertions"])
+		var gqlErrors []string
+		if pt.isGraphQL {
+			extracts = graphQLPaths(extracts)
+			assertions = graphQLPaths(assertions)
+			gqlErrors = graphql.ResponseErrors(result.ResponseBody)
+		}
+
+		if len(extracts) > 0 {
 			m.extractVars(result.ResponseBody, extracts)
 		}
 
-		passed := result.StatusCode == expectedStatus
-		schemaErrors := m.validateResponseSchema(method, endpoint, result.StatusCode, result.ResponseBody)
+		pollSatisfied := msg.poll == nil || msg.poll.Satisfied
+		passed := result.StatusCode == expectedStatus && len(gqlErrors) == 0 && pollSatisfied
+
+		var paginationIssues, aggregateFailures []string
+		if msg.pagination != nil {
+			paginationIssues = msg.pagination.Issues
+			aggregateFailures = tester.RunAggregateAssertions(msg.pagination, pt.aggregate)
+		}
+		paginationOK := len(paginationIssues) == 0 && len(aggregateFailures) == 0
+
+		webhookOK := msg.webhook == nil || msg.webhook.Satisfied
+		passed = passed && paginationOK && webhookOK
+		var callbackSchemaErrors []string
+		if msg.webhook != nil && msg.webhook.Satisfied {
+			callbackSchemaErrors = m.validateCallbackSchema(pt.webhookName, method, endpoint, msg.webhook.Callback)
+		}
+		var schemaErrors []string
+		if pt.isGraphQL {
+			schemaErrors = m.validateGraphQLSchema(pt.gqlField, result.ResponseBody)
+		} else {
+			schemaErrors = m.validateResponseSchema(method, endpoint, result.StatusCode, result.ResponseBody)
+		}
 		schemaValid := len(schemaErrors) == 0
 
-		assertionFailures := tester.RunAssertions(result.ResponseBody, toMapsSlice(testMap["assertions"]))
+		assertionFailures := tester.RunAssertions(result.ResponseBody, assertions)
 		assertionsPassed := len(assertionFailures) == 0
 
 		statusIcon := "✓"
@@ -287,7 +575,7 @@ func handleRunNextTest(m *TestUIModel, _ runNextTestMsg) (tea.Model, tea.Cmd) {
 			if m.isHeadless {
 				m.headlessExitCode = 1
 			}
-		} else if !schemaValid || !assertionsPassed {
+		} else if !schemaValid || !assertionsPassed || len(callbackSchemaErrors) > 0 {
 			statusIcon = "⚠"
 			statusStyle = lipgloss.NewStyle().Foreground(Theme.Warning)
 			if m.isHeadless {
@@ -296,14 +584,63 @@ func handleRunNextTest(m *TestUIModel, _ runNextTestMsg) (tea.Model, tea.Cmd) {
 		}
 
 		statusMsg := fmt.Sprintf("    Status: %d", result.StatusCode)
-		if !passed {
+		if result.StatusCode != expectedStatus {
 			statusMsg += fmt.Sprintf(" (expected %d)", expectedStatus)
 		}
 		statusMsg += fmt.Sprintf(" | Duration: %dms", result.Duration.Milliseconds())
+		if msg.poll != nil {
+			statusMsg += fmt.Sprintf(" | Attempts: %d in %s", msg.poll.Attempts, msg.poll.Elapsed.Round(time.Millisecond))
+		}
+		if msg.pagination != nil {
+			statusMsg += fmt.Sprintf(" | Pages: %d | Items: %d", msg.pagination.Pages, len(msg.pagination.Items))
+			if msg.pagination.Truncated {
+				statusMsg += " (stopped at max_pages)"
+			}
+		}
+		if msg.webhook != nil && msg.webhook.Satisfied {
+			statusMsg += fmt.Sprintf(" | Callback: %s after %s", msg.webhook.Callback.Method, msg.webhook.Elapsed.Round(time.Millisecond))
+		}
 
 		m.addMessage(fmt.Sprintf("  %s %s %s%s", statusStyle.Render(statusIcon), methodFormatted, endpoint, authIndicator))
 		m.addMessage(m.subtleStyle.Render(statusMsg))
 
+		if !pollSatisfied {
+			reason := pt.pollMessage
+			if reason == "" {
+				reason = "condition not met"
+			}
+			m.addMessage(m.errorStyle.Render(fmt.Sprintf("    Polling timed out: %s", reason)))
+			for _, pf := range msg.poll.Failures {
+				m.addMessage(m.errorStyle.Render("      · " + pf))
+			}
+		}
+
+		if !webhookOK {
+			reason := pt.webhookMessage
+			if reason == "" {
+				reason = fmt.Sprintf("%d callbacks received, none matched", msg.webhook.Received)
+			}
+			m.addMessage(m.errorStyle.Render(fmt.Sprintf("    No matching callback: %s", reason)))
+			for _, wf := range msg.webhook.Failures {
+				m.addMessage(m.errorStyle.Render("      · " + wf))
+			}
+		}
+
+		if len(callbackSchemaErrors) > 0 {
+			schemaStyle := lipgloss.NewStyle().Foreground(Theme.Warning)
+			m.addMessage(schemaStyle.Render("    Callback schema mismatch:"))
+			for _, se := range callbackSchemaErrors {
+				m.addMessage(schemaStyle.Render("      · " + se))
+			}
+		}
+
+		if len(gqlErrors) > 0 {
+			m.addMessage(m.errorStyle.Render("    GraphQL errors:"))
+			for _, ge := range gqlErrors {
+				m.addMessage(m.errorStyle.Render("      · " + ge))
+			}
+		}
+
 		if !schemaValid {
 			schemaStyle := lipgloss.NewStyle().Foreground(Theme.Warning)
 			m.addMessage(schemaStyle.Render("    Schema mismatch:"))
@@ -320,7 +657,21 @@ func handleRunNextTest(m *TestUIModel, _ runNextTestMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 
-		m.testGroupResults = append(m.testGroupResults, map[string]any{
+		if len(paginationIssues) > 0 {
+			m.addMessage(m.errorStyle.Render("    Pagination issues:"))
+			for _, pi := range paginationIssues {
+				m.addMessage(m.errorStyle.Render("      · " + pi))
+			}
+		}
+
+		if len(aggregateFailures) > 0 {
+			m.addMessage(m.errorStyle.Render("    Aggregate assertion failures:"))
+			for _, af := range aggregateFailures {
+				m.addMessage(m.errorStyle.Render("      · " + af))
+			}
+		}
+
+		testResult := map[string]any{
 			"method":             method,
 			"endpoint":           endpoint,
 			"status_code":        result.StatusCode,
@@ -333,7 +684,47 @@ func handleRunNextTest(m *TestUIModel, _ runNextTestMsg) (tea.Model, tea.Cmd) {
 			"schema_errors":      schemaErrors,
 			"assertions_passed":  assertionsPassed,
 			"assertion_failures": assertionFailures,
-		})
+		}
+		if pt.identity != "" {
+			testResult["identity"] = pt.identity
+		}
+		if pt.isGraphQL {
+			testResult["graphql_errors"] = gqlErrors
+		}
+		if msg.poll != nil {
+			testResult["poll_attempts"] = msg.poll.Attempts
+			testResult["poll_satisfied"] = msg.poll.Satisfied
+			if !msg.poll.Satisfied {
+				testResult["poll_failures"] = msg.poll.Failures
+				testResult["poll_message"] = pt.pollMessage
+			}
+		}
+		if msg.pagination != nil {
+			testResult["pages"] = msg.pagination.Pages
+			testResult["item_count"] = len(msg.pagination.Items)
+			testResult["truncated"] = msg.pagination.Truncated
+			testResult["pagination_ok"] = paginationOK
+			testResult["pagination_issues"] = paginationIssues
+			testResult["aggregate_failures"] = aggregateFailures
+			if msg.pagination.Total >= 0 {
+				testResult["total"] = msg.pagination.Total
+			}
+		}
+		if msg.webhook != nil {
+			testResult["webhook_received"] = msg.webhook.Received
+			testResult["webhook_satisfied"] = msg.webhook.Satisfied
+			if msg.webhook.Callback != nil {
+				testResult["webhook_callback"] = msg.webhook.Callback.Fields()
+			}
+			if !msg.webhook.Satisfied {
+				testResult["webhook_failures"] = msg.webhook.Failures
+				testResult["webhook_message"] = pt.webhookMessage
+			}
+			if len(callbackSchemaErrors) > 0 {
+				testResult["webhook_schema_errors"] = callbackSchemaErrors
+			}
+		}
+		m.testGroupResults = append(m.testGroupResults, testResult)
 	}
 	m.testGroupCompletedCount++
 	m.updateViewport()
@@ -341,6 +732,224 @@ func handleRunNextTest(m *TestUIModel, _ runNextTestMsg) (tea.Model, tea.Cmd) {
 	return m, runNextTest()
 }
 
+// testCaseFromMap builds a test case from an ExecuteTestGroup tool argument.
+func testCaseFromMap(testMap map[string]any) *agent.TestCase {
+	method, _ := testMap["method"].(string)
+	endpoint, _ := testMap["endpoint"].(string)
+	requiresAuth, _ := testMap["requires_auth"].(bool)
+	identity, _ := testMap["identity"].(string)
+
+	tc := &agent.TestCase{
+		Method:         method,
+		Endpoint:       endpoint,
+		Headers:        toStringMap(testMap["headers"]),
+		Body:           testMap["body"],
+		RequiresAuth:   requiresAuth,
+		Identity:       identity,
+		ExpectedStatus: toInt(testMap["expected_status"]),
+	}
+	for _, e := range toMapsSlice(testMap["extract"]) {
+		field, _ := e["field"].(string)
+		as, _ := e["as"].(string)
+		if field != "" && as != "" {
+			tc.Extract = append(tc.Extract, agent.Extract{Field: field, As: as})
+		}
+	}
+	for _, a := range toMapsSlice(testMap["assertions"]) {
+		field, _ := a["field"].(string)
+		op, _ := a["op"].(string)
+		if field != "" && op != "" {
+			tc.Assertions = append(tc.Assertions, agent.Assertion{Field: field, Op: op, Value: a["value"]})
+		}
+	}
+	if g, ok := testMap["graphql"].(map[string]any); ok {
+		query, _ := g["query"].(string)
+		if query != "" {
+			vars, _ := g["variables"].(map[string]any)
+			opName, _ := g["operation_name"].(string)
+			field, _ := g["field"].(string)
+			tc.GraphQL = &agent.GraphQLRequest{Query: query, Variables: vars, OperationName: opName, Field: field}
+		}
+	}
+	if p, ok := testMap["poll"].(map[string]any); ok {
+		poll := &agent.Poll{
+			UntilStatus:     toInt(p["until_status"]),
+			IntervalSeconds: toSeconds(p["interval_seconds"], 0).Seconds(),
+			TimeoutSeconds:  toSeconds(p["timeout_seconds"], 0).Seconds(),
+		}
+		poll.Message, _ = p["message"].(string)
+		for _, a := range toMapsSlice(p["until"]) {
+			field, _ := a["field"].(string)
+			op, _ := a["op"].(string)
+			if field != "" && op != "" {
+				poll.Until = append(poll.Until, agent.Assertion{Field: field, Op: op, Value: a["value"]})
+			}
+		}
+		tc.Poll = poll
+	}
+	if p, ok := testMap["paginate"].(map[string]any); ok {
+		pg := &agent.Paginate{
+			PageSize: toInt(p["page_size"]),
+			MaxPages: toInt(p["max_pages"]),
+		}
+		pg.Style, _ = p["style"].(string)
+		pg.ItemsField, _ = p["items_field"].(string)
+		pg.NextField, _ = p["next_field"].(string)
+		pg.CursorParam, _ = p["cursor_param"].(string)
+		pg.PageParam, _ = p["page_param"].(string)
+		pg.OffsetParam, _ = p["offset_param"].(string)
+		pg.SizeParam, _ = p["size_param"].(string)
+		pg.IDField, _ = p["id_field"].(string)
+		pg.TotalField, _ = p["total_field"].(string)
+		for _, a := range toMapsSlice(p["aggregate"]) {
+			field, _ := a["field"].(string)
+			op, _ := a["op"].(string)
+			if field != "" && op != "" {
+				pg.Aggregate = append(pg.Aggregate, agent.Assertion{Field: field, Op: op, Value: a["value"]})
+			}
+		}
+		tc.Paginate = pg
+	}
+	if w, ok := testMap["webhook"].(map[string]any); ok {
+		wh := &agent.WebhookExpectation{
+			TimeoutSeconds: toSeconds(w["timeout_seconds"], 0).Seconds(),
+		}
+		wh.Name, _ = w["name"].(string)
+		wh.Path, _ = w["path"].(string)
+		wh.Method, _ = w["method"].(string)
+		wh.Message, _ = w["message"].(string)
+		for _, a := range toMapsSlice(w["until"]) {
+			field, _ := a["field"].(string)
+			op, _ := a["op"].(string)
+			if field != "" && op != "" {
+				wh.Until = append(wh.Until, agent.Assertion{Field: field, Op: op, Value: a["value"]})
+			}
+		}
+		tc.Webhook = wh
+	}
+	if ws, ok := testMap["websocket"].(map[string]any); ok {
+		send, _ := ws["send"].([]any)
+		tc.WebSocket = &agent.WebSocketExchange{
+			Send:           send,
+			Receive:        toInt(ws["receive"]),
+			TimeoutSeconds: toSeconds(ws["timeout_seconds"], 0).Seconds(),
+		}
+	}
+	return tc
+}
+
+// testCaseToMap is the inverse of testCaseFromMap, used to queue tests for execution.
+func testCaseToMap(tc *agent.TestCase) map[string]any {
+	testMap := map[string]any{
+		"method":          tc.Method,
+		"endpoint":        tc.Endpoint,
+		"headers":         tc.Headers,
+		"body":            tc.Body,
+		"requires_auth":   tc.RequiresAuth,
+		"expected_status": tc.ExpectedStatus,
+		"extract":         extractsToAny(tc.Extract),
+		"assertions":      assertionsToAny(tc.Assertions),
+	}
+	if tc.Identity != "" {
+		testMap["identity"] = tc.Identity
+	}
+	if tc.GraphQL != nil {
+		testMap["graphql"] = map[string]any{
+			"query":          tc.GraphQL.Query,
+			"variables":      tc.GraphQL.Variables,
+			"operation_name": tc.GraphQL.OperationName,
+			"field":          tc.GraphQL.Field,
+		}
+	}
+	if tc.Poll != nil {
+		testMap["poll"] = map[string]any{
+			"until":            assertionsToAny(tc.Poll.Until),
+			"until_status":     tc.Poll.UntilStatus,
+			"interval_seconds": tc.Poll.IntervalSeconds,
+			"timeout_seconds":  tc.Poll.TimeoutSeconds,
+			"message":          tc.Poll.Message,
+		}
+	}
+	if tc.Paginate != nil {
+		testMap["paginate"] = map[string]any{
+			"style":        tc.Paginate.Style,
+			"items_field":  tc.Paginate.ItemsField,
+			"next_field":   tc.Paginate.NextField,
+			"cursor_param": tc.Paginate.CursorParam,
+			"page_param":   tc.Paginate.PageParam,
+			"offset_param": tc.Paginate.OffsetParam,
+			"size_param":   tc.Paginate.SizeParam,
+			"page_size":    tc.Paginate.PageSize,
+			"max_pages":    tc.Paginate.MaxPages,
+			"id_field":     tc.Paginate.IDField,
+			"total_field":  tc.Paginate.TotalField,
+			"aggregate":    assertionsToAny(tc.Paginate.Aggregate),
+		}
+	}
+	if tc.Webhook != nil {
+		testMap["webhook"] = map[string]any{
+			"name":            tc.Webhook.Name,
+			"path":            tc.Webhook.Path,
+			"method":          tc.Webhook.Method,
+			"until":           assertionsToAny(tc.Webhook.Until),
+			"timeout_seconds": tc.Webhook.TimeoutSeconds,
+			"message":         tc.Webhook.Message,
+		}
+	}
+	if tc.WebSocket != nil {
+		testMap["websocket"] = map[string]any{
+			"send":            tc.WebSocket.Send,
+			"receive":         tc.WebSocket.Receive,
+			"timeout_seconds": tc.WebSocket.TimeoutSeconds,
+		}
+	}
+	return testMap
+}
+
+// toStringMap accepts both decoded JSON objects and string maps.
+func toStringMap(v any) map[string]string {
+	out := make(map[string]string)
+	switch h := v.(type) {
+	case map[string]string:
+		for k, val := range h {
+			out[k] = val
+		}
+	case map[string]any:
+		for k, val := range h {
+			if vs, ok := val.(string); ok {
+				out[k] = vs
+			}
+		}
+	}
+	return out
+}
+
+// toSeconds converts a number of seconds into a duration, or returns def.
+func toSeconds(v any, def time.Duration) time.Duration {
+	var secs float64
+	switch n := v.(type) {
+	case float64:
+		secs = n
+	case int:
+		secs = float64(n)
+	}
+	if secs <= 0 {
+		return def
+	}
+	return time.Duration(secs * float64(time.Second))
+}
+
+// toInt accepts JSON numbers as well as ints.
+func toInt(v any) int {
+	switch n := v.(type) {
+	case float64:
+		return int(n)
+	case int:
+		return n
+	}
+	return 0
+}
+
 func extractsToAny(extracts []agent.Extract) []any {
 	if len(extracts) == 0 {
 		return nil
@@ -363,6 +972,21 @@ func assertionsToAny(assertions []agent.Assertion) []any {
 	return out
 }
 
+// graphQLPaths roots extract and assertion fields at the "data" object.
+func graphQLPaths(rules []map[string]any) []map[string]any {
+	out := make([]map[string]any, 0, len(rules))
+	for _, r := range rules {
+		rooted := make(map[string]any, len(r))
+		for k, v := range r {
+			rooted[k] = v
+		}
+		field, _ := r["field"].(string)
+		rooted["field"] = graphql.DataPath(field)
+		out = append(out, rooted)
+	}
+	return out
+}
+
 func toMapsSlice(v any) []map[string]any {
 	raw, ok := v.([]any)
 	if !ok {
@@ -0,0 +1,215 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Octrafic/octrafic-cli/internal/core/tester"
+)
+
+func TestTestCaseMapRoundTrip(t *testing.T) {
+	in := map[string]any{
+		"method":          "POST",
+		"endpoint":        "/graphql",
+		"headers":         map[string]any{"X-Trace": "1"},
+		"requires_auth":   true,
+		"expected_status": float64(200),
+		"assertions":      []any{map[string]any{"field": "user.id", "op": "exists"}},
+		"graphql": map[string]any{
+			"query":     "query User($id: ID!) { user(id: $id) { id } }",
+			"variables": map[string]any{"id": "1"},
+			"field":     "user",
+		},
+	}
+
+	tc := testCaseFromMap(in)
+	if tc.Headers["X-Trace"] != "1" {
+		t.Errorf("expected header from JSON object, got %v", tc.Headers)
+	}
+	if tc.ExpectedStatus != 200 || !tc.RequiresAuth {
+		t.Errorf("unexpected status/auth: %d, %v", tc.ExpectedStatus, tc.RequiresAuth)
+	}
+	if tc.GraphQL == nil || tc.GraphQL.Field != "user" || tc.GraphQL.Variables["id"] != "1" {
+		t.Fatalf("unexpected graphql request: %+v", tc.GraphQL)
+	}
+
+	out := testCaseToMap(tc)
+	if toStringMap(out["headers"])["X-Trace"] != "1" {
+		t.Errorf("expected header to survive round trip, got %v", out["headers"])
+	}
+	g, ok := out["graphql"].(map[string]any)
+	if !ok || g["query"] != tc.GraphQL.Query {
+		t.Errorf("expected graphql to survive round trip, got %v", out["graphql"])
+	}
+}
+
+func TestGraphQLPaths(t *testing.T) {
+	rules := []map[string]any{
+		{"field": "user.id", "op": "exists"},
+		{"field": "errors", "op": "not_exists"},
+	}
+	got := graphQLPaths(rules)
+	if got[0]["field"] != "data.user.id" || got[1]["field"] != "errors" {
+		t.Errorf("unexpected fields: %v", got)
+	}
+	if rules[0]["field"] != "user.id" {
+		t.Error("expected input rules to be left unchanged")
+	}
+}
+
+func TestPrepareTest_Poll(t *testing.T) {
+	m := &TestUIModel{testVars: map[string]string{"job_id": "42"}}
+	tc := testCaseFromMap(map[string]any{
+		"method":   "GET",
+		"endpoint": "/jobs/{{job_id}}",
+		"poll": map[string]any{
+			"until":            []any{map[string]any{"field": "status", "op": "eq", "value": "done"}},
+			"interval_seconds": float64(0.5),
+			"message":          "job did not finish",
+		},
+	})
+	if tc.Poll == nil || len(tc.Poll.Until) != 1 || tc.Poll.IntervalSeconds != 0.5 {
+		t.Fatalf("unexpected poll: %+v", tc.Poll)
+	}
+
+	pt := m.prepareTest(testCaseToMap(tc))
+	if pt.endpoint != "/jobs/42" {
+		t.Errorf("expected variables applied, got %q", pt.endpoint)
+	}
+	if pt.poll == nil {
+		t.Fatal("expected poll options")
+	}
+	if pt.poll.Interval != 500*time.Millisecond || pt.poll.Timeout != tester.DefaultPollTimeout {
+		t.Errorf("unexpected interval/timeout: %s, %s", pt.poll.Interval, pt.poll.Timeout)
+	}
+	if pt.pollMessage != "job did not finish" || len(pt.poll.Until) != 1 {
+		t.Errorf("unexpected poll options: %+v", pt.poll)
+	}
+}
+
+func TestPrepareTest_Paginate(t *testing.T) {
+	m := &TestUIModel{baseURL: "https://api.example.com"}
+	tc := testCaseFromMap(map[string]any{
+		"method":   "GET",
+		"endpoint": "/users",
+		"paginate": map[string]any{
+			"style":     "page",
+			"page_size": float64(25),
+			"aggregate": []any{map[string]any{"field": "id", "op": "unique"}},
+		},
+	})
+	if tc.Paginate == nil || tc.Paginate.PageSize != 25 || len(tc.Paginate.Aggregate) != 1 {
+		t.Fatalf("unexpected paginate: %+v", tc.Paginate)
+	}
+
+	pt := m.prepareTest(testCaseToMap(tc))
+	if pt.paginate == nil {
+		t.Fatal("expected paginate options")
+	}
+	if pt.paginate.Style != tester.PaginationPage || pt.paginate.BaseURL != "https://api.example.com" {
+		t.Errorf("unexpected paginate options: %+v", pt.paginate)
+	}
+	if pt.paginate.MaxPages != tester.DefaultMaxPages || len(pt.aggregate) != 1 {
+		t.Errorf("expected default max pages and one aggregate assertion, got %d, %v", pt.paginate.MaxPages, pt.aggregate)
+	}
+}
+
+func TestPrepareTest_Webhook(t *testing.T) {
+	m := &TestUIModel{}
+	t.Cleanup(m.closeWebhooks)
+
+	tc := testCaseFromMap(map[string]any{
+		"method":   "POST",
+		"endpoint": "/payments",
+		"headers":  map[string]any{"X-Callback": "{{$webhookUrl}}"},
+		"body":     `{"callback_url":"{{$webhookUrl}}/payments"}`,
+		"webhook": map[string]any{
+			"name":            "paymentStatus",
+			"path":            "/payments",
+			"until":           []any{map[string]any{"field": "body.status", "op": "eq", "value": "paid"}},
+			"timeout_seconds": float64(5),
+		},
+	})
+	if tc.Webhook == nil || tc.Webhook.Name != "paymentStatus" || len(tc.Webhook.Until) != 1 {
+		t.Fatalf("unexpected webhook: %+v", tc.Webhook)
+	}
+
+	pt := m.prepareTest(testCaseToMap(tc))
+	if m.webhooks == nil {
+		t.Fatal("expected the receiver to start on first use of {{$webhookUrl}}")
+	}
+	url := m.webhooks.URL()
+	if pt.body != `{"callback_url":"`+url+`/payments"}` || pt.headers["X-Callback"] != url {
+		t.Errorf("expected webhook URL substituted, got body %v, headers %v", pt.body, pt.headers)
+	}
+	if pt.webhook == nil || pt.webhook.Timeout != 5*time.Second || pt.webhook.Path != "/payments" {
+		t.Errorf("unexpected webhook options: %+v", pt.webhook)
+	}
+}
+
+func TestPrepareTest_WebSocket(t *testing.T) {
+	m := &TestUIModel{testVars: map[string]string{"room": "42"}}
+
+	tc := testCaseFromMap(map[string]any{
+		"method":          "PUBLISH",
+		"endpoint":        "/rooms/{{room}}",
+		"expected_status": float64(101),
+		"websocket": map[string]any{
+			"send":    []any{map[string]any{"room": "{{room}}"}, "ping"},
+			"receive": float64(2),
+		},
+	})
+	if tc.WebSocket == nil || tc.WebSocket.Receive != 2 || len(tc.WebSocket.Send) != 2 {
+		t.Fatalf("unexpected websocket exchange: %+v", tc.WebSocket)
+	}
+
+	pt := m.prepareTest(testCaseToMap(tc))
+	if pt.endpoint != "/rooms/42" || pt.websocket == nil {
+		t.Fatalf("expected websocket test on /rooms/42, got %s %+v", pt.endpoint, pt.websocket)
+	}
+	if pt.websocket.Send[0] != `{"room":"42"}` || pt.websocket.Send[1] != "ping" {
+		t.Errorf("expected variables applied to messages, got %v", pt.websocket.Send)
+	}
+	if pt.websocket.Timeout != tester.DefaultWebSocketTimeout {
+		t.Errorf("expected default timeout, got %s", pt.websocket.Timeout)
+	}
+}
+
+func TestHandleTestDone_PaginationFails(t *testing.T) {
+	m := &TestUIModel{isHeadless: true}
+	pt := preparedTest{
+		method:         "GET",
+		endpoint:       "/items",
+		expectedStatus: 200,
+		testMap:        map[string]any{},
+		aggregate:      []map[string]any{{"field": "id", "op": "unique"}},
+	}
+	page := &tester.TestResult{StatusCode: 200, ResponseBody: `[]`}
+	handleTestDone(m, testDoneMsg{
+		test:   pt,
+		result: page,
+		pagination: &tester.PaginationResult{
+			Items:  []any{map[string]any{"id": 1}, map[string]any{"id": 1}},
+			Pages:  2,
+			Total:  -1,
+			Issues: []string{"page 2 repeats 1 items of earlier pages"},
+		},
+	})
+
+	if len(m.testGroupResults) != 1 {
+		t.Fatalf("expected one result, got %d", len(m.testGroupResults))
+	}
+	result := m.testGroupResults[0]
+	if result["passed"] != false || result["pagination_ok"] != false {
+		t.Errorf("expected pagination issues to fail the test, got %v", result)
+	}
+	if issues, _ := result["pagination_issues"].([]string); len(issues) != 1 {
+		t.Errorf("expected pagination issues reported, got %v", result["pagination_issues"])
+	}
+	if failures, _ := result["aggregate_failures"].([]string); len(failures) != 1 {
+		t.Errorf("expected the duplicate id reported, got %v", result["aggregate_failures"])
+	}
+	if m.headlessExitCode != 1 {
+		t.Errorf("expected exit code 1, got %d", m.headlessExitCode)
+	}
+}
@@ -115,6 +115,7 @@ func (m *TestUIModel) shouldAskForConfirmation(toolName string) bool {
 		agent.ToolGenerateTestPlan: true, // Planning is safe, doesn't execute anything
 		agent.ToolExecuteTestGroup: true, // Plan was already approved via checkboxes
 		agent.ToolGenerateReport:   true, // Generating a report is safe
+		agent.ToolLintSpec:         true, // Linting only reads the spec
 	}
 
 	return !safeTools[toolName]
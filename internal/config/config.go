@@ -6,8 +6,13 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/Octrafic/octrafic-cli/internal/infra/secrets"
 )
 
+// apiKeySecret is the secret store key of the LLM API key.
+const apiKeySecret = "llm/api_key"
+
 // Config holds the application configuration
 type Config struct {
 	Provider        string    `json:"provider"`
@@ -17,6 +22,11 @@ type Config struct {
 	Onboarded       bool      `json:"onboarded"`
 	LastUpdateCheck time.Time `json:"last_update_check,omitempty"`
 	LatestVersion   string    `json:"latest_version,omitempty"`
+
+	// apiKeyRef is the reference api_key was loaded from (secret store, env:,
+	// file: or cmd:), and apiKeyStored the value it resolved to.
+	apiKeyRef    string
+	apiKeyStored string
 }
 
 // ShouldCheckForUpdate returns true if more than 24 hours since last check
@@ -73,9 +83,28 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 
+	if secrets.IsReference(config.APIKey) {
+		config.apiKeyRef = config.APIKey
+		// A locked store or a missing variable leaves the key empty; Save
+		// keeps the reference.
+		config.APIKey, _ = secrets.Resolve(config.apiKeyRef)
+		config.apiKeyStored = config.APIKey
+	}
+
 	return &config, nil
 }
 
+// APIKeyRef returns the reference the API key was loaded from, if any.
+func (c *Config) APIKeyRef() string {
+	return c.apiKeyRef
+}
+
+// HasPlaintextAPIKey reports whether the API key is saved in config.json
+// rather than in the secret store.
+func (c *Config) HasPlaintextAPIKey() bool {
+	return c.APIKey != "" && c.apiKeyRef == ""
+}
+
 // Save saves the configuration to disk
 func (c *Config) Save() error {
 	path, err := configPath()
@@ -83,12 +112,29 @@ func (c *Config) Save() error {
 		return err
 	}
 
-	data, err := json.MarshalIndent(c, "", "  ")
+	saved := *c
+	switch {
+	case c.apiKeyRef != "" && c.APIKey == c.apiKeyStored:
+		saved.APIKey = c.apiKeyRef
+	case c.APIKey != "":
+		// If the secret store is locked or disabled the key stays in the
+		// file, which only the owner can read.
+		if ref, err := secrets.Put(apiKeySecret, c.APIKey); err == nil {
+			saved.APIKey = ref
+			c.apiKeyRef, c.apiKeyStored = ref, c.APIKey
+		}
+	}
+
+	data, err := json.MarshalIndent(&saved, "", "  ")
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(path, data, 0644)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return err
+	}
+	// Files written by older versions were world-readable.
+	return os.Chmod(path, 0600)
 }
 
 // IsFirstLaunch checks if this is the first launch (no config or not onboarded)
@@ -8,9 +8,11 @@ import (
 
 // APIKeyAuth represents API Key authentication
 type APIKeyAuth struct {
+	References
+
 	Key      string `json:"key"`      // The key name (e.g., "X-API-Key")
 	Value    string `json:"value"`    // The key value
-	Location string `json:"location"` // "header" or "query"
+	Location string `json:"location"` // "header", "query" or "cookie"
 }
 
 // NewAPIKeyAuth creates a new API Key authentication provider
@@ -31,15 +33,22 @@ func (a *APIKeyAuth) Apply(req *http.Request) error {
 		return err
 	}
 
+	value, err := a.Resolve("API key", a.Value)
+	if err != nil {
+		return err
+	}
+
 	switch strings.ToLower(a.Location) {
 	case "header":
-		req.Header.Set(a.Key, a.Value)
+		req.Header.Set(a.Key, value)
 	case "query":
 		q := req.URL.Query()
-		q.Set(a.Key, a.Value)
+		q.Set(a.Key, value)
 		req.URL.RawQuery = q.Encode()
+	case "cookie":
+		req.AddCookie(&http.Cookie{Name: a.Key, Value: value})
 	default:
-		return fmt.Errorf("invalid location: %s (must be 'header' or 'query')", a.Location)
+		return fmt.Errorf("invalid location: %s (must be 'header', 'query' or 'cookie')", a.Location)
 	}
 
 	return nil
@@ -59,8 +68,8 @@ func (a *APIKeyAuth) Validate() error {
 		return fmt.Errorf("API key value cannot be empty")
 	}
 	location := strings.ToLower(a.Location)
-	if location != "header" && location != "query" {
-		return fmt.Errorf("location must be 'header' or 'query', got: %s", a.Location)
+	if location != "header" && location != "query" && location != "cookie" {
+		return fmt.Errorf("location must be 'header', 'query' or 'cookie', got: %s", a.Location)
 	}
 	return nil
 }
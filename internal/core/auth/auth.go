@@ -3,6 +3,8 @@ package auth
 import (
 	"fmt"
 	"net/http"
+
+	"github.com/Octrafic/octrafic-cli/internal/infra/secrets"
 )
 
 // AuthProvider applies authentication to HTTP requests
@@ -46,22 +48,73 @@ func ParseAuthType(authType string) (string, error) {
 		"bearer": true,
 		"apikey": true,
 		"basic":  true,
+		"oauth2": true,
+		"login":  true,
+		"sigv4":  true,
+		"hmac":   true,
 	}
 
 	if !validTypes[authType] {
-		return "", fmt.Errorf("invalid auth type: %s (valid: none, bearer, apikey, basic)", authType)
+		return "", fmt.Errorf("invalid auth type: %s (valid: none, bearer, apikey, basic, oauth2, login, sigv4, hmac)", authType)
 	}
 
 	return authType, nil
 }
 
-// RedactString hides sensitive data for logging
+// RedactString hides sensitive data for logging. References such as
+// env:TOKEN are not secret and are shown as they are.
 func RedactString(s string) string {
 	if len(s) == 0 {
 		return "<empty>"
 	}
+	if secrets.IsReference(s) {
+		return s
+	}
 	if len(s) <= 8 {
 		return "***"
 	}
 	return s[:4] + "***" + s[len(s)-4:]
 }
+
+// References controls whether a provider resolves env:, file:, cmd: and
+// secret: references in its credentials. Resolving one may run a command or
+// read a file, so it is off until allowed: only configuration the user
+// wrote, such as flags, the /auth wizard, projects and identities, allows
+// it. Credentials imported from collections are sent as written.
+type References struct {
+	allowed bool
+}
+
+// AllowReferences lets the provider resolve references in its credentials.
+func (r *References) AllowReferences() {
+	r.allowed = true
+}
+
+// ReferencesAllowed reports whether the provider resolves references.
+func (r *References) ReferencesAllowed() bool {
+	return r.allowed
+}
+
+// Resolve returns the credential a reference such as env:TOKEN, file:path or
+// cmd:command points to, or the value itself when it is not a reference or
+// references are not allowed. Providers call it when a request is sent, so
+// references are never stored in resolved form.
+func (r *References) Resolve(field, value string) (string, error) {
+	if !r.allowed {
+		return value, nil
+	}
+	v, err := secrets.Resolve(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", field, err)
+	}
+	return v, nil
+}
+
+// AllowReferences lets a provider built from the user's own configuration
+// resolve references, and returns it.
+func AllowReferences(provider AuthProvider) AuthProvider {
+	if p, ok := provider.(interface{ AllowReferences() }); ok {
+		p.AllowReferences()
+	}
+	return provider
+}
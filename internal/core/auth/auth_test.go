@@ -1,7 +1,10 @@
 package auth
 
 import (
+	"encoding/base64"
 	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -38,6 +41,29 @@ func TestBearerAuth(t *testing.T) {
 	}
 }
 
+func TestBearerAuth_ResolvesReferenceOnApply(t *testing.T) {
+	auth := NewBearerAuth("env:OCTRAFIC_TEST_BEARER")
+	auth.AllowReferences()
+
+	req, _ := http.NewRequest("GET", "http://example.com", nil)
+	if err := auth.Apply(req); err == nil {
+		t.Error("expected an error while the variable is unset")
+	}
+
+	t.Setenv("OCTRAFIC_TEST_BEARER", "rotated-token-456")
+	req, _ = http.NewRequest("GET", "http://example.com", nil)
+	if err := auth.Apply(req); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer rotated-token-456" {
+		t.Errorf("expected resolved token, got %q", got)
+	}
+
+	if bearer := auth.Redact().(*BearerAuth); bearer.Token != "env:OCTRAFIC_TEST_BEARER" {
+		t.Errorf("references should be shown as written, got %q", bearer.Token)
+	}
+}
+
 func TestAPIKeyAuth(t *testing.T) {
 	auth := NewAPIKeyAuth("X-API-Key", "my-secret-key", "header")
 
@@ -143,3 +169,41 @@ func TestValidation(t *testing.T) {
 		t.Error("empty username should fail validation")
 	}
 }
+
+func TestProviders_SendReferencesAsWrittenUnlessAllowed(t *testing.T) {
+	t.Setenv("OCTRAFIC_TEST_LITERAL", "from-env")
+	marker := filepath.Join(t.TempDir(), "ran")
+
+	providers := []AuthProvider{
+		NewBearerAuth("cmd:touch " + marker),
+		NewAPIKeyAuth("X-Api-Key", "env:OCTRAFIC_TEST_LITERAL", "header"),
+		NewBasicAuth("file:/etc/passwd", "secret:api/password"),
+	}
+	want := []string{
+		"Bearer cmd:touch " + marker,
+		"env:OCTRAFIC_TEST_LITERAL",
+		"Basic " + base64.StdEncoding.EncodeToString([]byte("file:/etc/passwd:secret:api/password")),
+	}
+	for i, provider := range providers {
+		req, _ := http.NewRequest("GET", "http://example.com", nil)
+		if err := provider.Apply(req); err != nil {
+			t.Fatalf("%s: Apply failed: %v", provider.Type(), err)
+		}
+		got := req.Header.Get("Authorization")
+		if provider.Type() == "apikey" {
+			got = req.Header.Get("X-Api-Key")
+		}
+		if got != want[i] {
+			t.Errorf("%s: expected the value as written, got %q", provider.Type(), got)
+		}
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("expected the cmd: reference not to run")
+	}
+
+	allowed := AllowReferences(NewAPIKeyAuth("X-Api-Key", "env:OCTRAFIC_TEST_LITERAL", "header"))
+	req, _ := http.NewRequest("GET", "http://example.com", nil)
+	if err := allowed.Apply(req); err != nil || req.Header.Get("X-Api-Key") != "from-env" {
+		t.Errorf("expected the reference resolved once allowed, got %q (%v)", req.Header.Get("X-Api-Key"), err)
+	}
+}
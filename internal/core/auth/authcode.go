@@ -0,0 +1,188 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const defaultRedirectURL = "http://127.0.0.1:0/callback"
+
+const callbackPage = `<!DOCTYPE html><html><body style="font-family:sans-serif">
+<h3>%s</h3><p>You can close this window and return to Octrafic.</p></body></html>`
+
+type callbackResult struct {
+	code string
+	err  error
+}
+
+// Login runs the authorization_code grant with PKCE (RFC 7636). It listens
+// on the loopback redirect URL, passes the authorize URL to onURL so the
+// caller can print or open it, and exchanges the returned code for tokens.
+// The refresh token is reported through OnRefreshToken.
+func (o *OAuth2Auth) Login(ctx context.Context, onURL func(authorizeURL string)) error {
+	if o.GrantType != GrantAuthorizationCode {
+		return fmt.Errorf("login requires the %s grant, got %s", GrantAuthorizationCode, o.GrantType)
+	}
+	if err := o.Validate(); err != nil {
+		return err
+	}
+
+	redirectURL := o.RedirectURL
+	if redirectURL == "" {
+		redirectURL = defaultRedirectURL
+	}
+	redirect, err := loopbackRedirect(redirectURL)
+	if err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", redirect.Host)
+	if err != nil {
+		return fmt.Errorf("failed to start callback listener: %w", err)
+	}
+	if redirect.Port() == "0" {
+		port := listener.Addr().(*net.TCPAddr).Port
+		redirect.Host = net.JoinHostPort(redirect.Hostname(), fmt.Sprint(port))
+	}
+
+	verifier, err := randomToken(32)
+	if err != nil {
+		return err
+	}
+	state, err := randomToken(16)
+	if err != nil {
+		return err
+	}
+	clientID, err := o.Resolve("client ID", o.ClientID)
+	if err != nil {
+		return err
+	}
+
+	results := make(chan callbackResult, 1)
+	var mismatched atomic.Int32
+	server := &http.Server{
+		ReadHeaderTimeout: 10 * time.Second,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != redirect.Path {
+				http.NotFound(w, r)
+				return
+			}
+			// A stale browser tab or a stray request to the port does not
+			// end the sign-in; only the callback for this state does.
+			if r.URL.Query().Get("state") != state {
+				mismatched.Add(1)
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = fmt.Fprintf(w, callbackPage, "Sign-in failed: this callback belongs to another sign-in")
+				return
+			}
+			res := parseCallback(r.URL.Query())
+			if res.err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = fmt.Fprintf(w, callbackPage, "Sign-in failed")
+			} else {
+				_, _ = fmt.Fprintf(w, callbackPage, "Signed in")
+			}
+			select {
+			case results <- res:
+			default:
+			}
+		}),
+	}
+	go func() { _ = server.Serve(listener) }()
+	defer func() { _ = server.Close() }()
+
+	onURL(o.authorizeURL(clientID, redirect.String(), state, pkceChallenge(verifier)))
+
+	var res callbackResult
+	select {
+	case <-ctx.Done():
+		if n := mismatched.Load(); n > 0 {
+			return fmt.Errorf("sign-in was not completed (ignored %d callbacks with a mismatched state): %w", n, ctx.Err())
+		}
+		return fmt.Errorf("sign-in was not completed: %w", ctx.Err())
+	case res = <-results:
+	}
+	if res.err != nil {
+		return res.err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.exchange(url.Values{
+		"grant_type":    {GrantAuthorizationCode},
+		"code":          {res.code},
+		"redirect_uri":  {redirect.String()},
+		"code_verifier": {verifier},
+	})
+}
+
+func (o *OAuth2Auth) authorizeURL(clientID, redirectURI, state, challenge string) string {
+	u, _ := url.Parse(o.AuthorizeURL)
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", clientID)
+	q.Set("redirect_uri", redirectURI)
+	q.Set("state", state)
+	q.Set("code_challenge", challenge)
+	q.Set("code_challenge_method", "S256")
+	if len(o.Scopes) > 0 {
+		q.Set("scope", strings.Join(o.Scopes, " "))
+	}
+	if o.Audience != "" {
+		q.Set("audience", o.Audience)
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+func parseCallback(q url.Values) callbackResult {
+	if e := q.Get("error"); e != "" {
+		if d := q.Get("error_description"); d != "" {
+			return callbackResult{err: fmt.Errorf("authorization failed: %s: %s", e, d)}
+		}
+		return callbackResult{err: fmt.Errorf("authorization failed: %s", e)}
+	}
+	if q.Get("code") == "" {
+		return callbackResult{err: errors.New("sign-in callback has no authorization code")}
+	}
+	return callbackResult{code: q.Get("code")}
+}
+
+// loopbackRedirect checks that a redirect URL points at this machine, since
+// the callback listener cannot receive anything else.
+func loopbackRedirect(s string) (*url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme != "http" || u.Port() == "" {
+		return nil, fmt.Errorf("redirect URL must be http://<loopback>:<port>/<path>, got: %q", s)
+	}
+	host := u.Hostname()
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return nil, fmt.Errorf("redirect URL must use a loopback host, got: %q", host)
+	}
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	return u, nil
+}
+
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,186 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// visit follows the authorize URL like a browser whose user approved access,
+// calling back to the redirect URI with the given state.
+func visit(t *testing.T, authorizeURL, code, state string) {
+	t.Helper()
+	u, err := url.Parse(authorizeURL)
+	if err != nil {
+		t.Errorf("invalid authorize URL: %v", err)
+		return
+	}
+	q := u.Query()
+	if state == "" {
+		state = q.Get("state")
+	}
+	callback := q.Get("redirect_uri") + "?" + url.Values{"code": {code}, "state": {state}}.Encode()
+	go func() {
+		resp, err := http.Get(callback)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+	}()
+}
+
+func TestOAuth2Auth_LoginWithPKCE(t *testing.T) {
+	var challenge string
+	var forms []url.Values
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		forms = append(forms, r.PostForm)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.PostForm.Get("grant_type") {
+		case GrantAuthorizationCode:
+			if r.PostForm.Get("code") != "the-code" || pkceChallenge(r.PostForm.Get("code_verifier")) != challenge {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = fmt.Fprint(w, `{"error":"invalid_grant"}`)
+				return
+			}
+			_, _ = fmt.Fprint(w, `{"access_token":"user-token","token_type":"Bearer","expires_in":60,"refresh_token":"refresh-1"}`)
+		case GrantRefreshToken:
+			_, _ = fmt.Fprint(w, `{"access_token":"user-token-2","token_type":"Bearer","expires_in":3600}`)
+		}
+	}))
+	defer tokenServer.Close()
+
+	o := NewOAuth2Auth(GrantAuthorizationCode, tokenServer.URL, "cli-app", "")
+	o.AuthorizeURL = "https://auth.example.com/authorize?prompt=consent"
+	o.Scopes = []string{"openid", "offline_access"}
+	var saved string
+	o.OnRefreshToken = func(token string) { saved = token }
+
+	if !o.NeedsLogin() {
+		t.Fatal("expected sign-in to be needed")
+	}
+	req, _ := http.NewRequest("GET", "http://api.example.com", nil)
+	if err := o.Apply(req); !errors.Is(err, ErrLoginRequired) {
+		t.Fatalf("expected ErrLoginRequired before sign-in, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := o.Login(ctx, func(authorizeURL string) {
+		q, _ := url.ParseQuery(authorizeURL[strings.Index(authorizeURL, "?")+1:])
+		if q.Get("prompt") != "consent" || q.Get("response_type") != "code" || q.Get("code_challenge_method") != "S256" ||
+			q.Get("client_id") != "cli-app" || q.Get("scope") != "openid offline_access" {
+			t.Errorf("unexpected authorize URL: %s", authorizeURL)
+		}
+		if !strings.HasPrefix(q.Get("redirect_uri"), "http://127.0.0.1:") {
+			t.Errorf("expected a loopback redirect, got %q", q.Get("redirect_uri"))
+		}
+		challenge = q.Get("code_challenge")
+		visit(t, authorizeURL, "the-code", "")
+	})
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	if saved != "refresh-1" || o.NeedsLogin() {
+		t.Errorf("expected the refresh token to be reported, got %q", saved)
+	}
+	if got := forms[0].Get("client_id"); got != "cli-app" {
+		t.Errorf("expected public client ID in the form, got %q", got)
+	}
+	if got := applyAuth(t, o); got != "Bearer user-token" {
+		t.Errorf("expected signed-in token, got %q", got)
+	}
+
+	// A later session starts from the saved refresh token.
+	next := NewOAuth2Auth(GrantAuthorizationCode, tokenServer.URL, "cli-app", "")
+	next.AuthorizeURL = o.AuthorizeURL
+	next.RefreshToken = saved
+	if got := applyAuth(t, next); got != "Bearer user-token-2" {
+		t.Errorf("expected token from refresh, got %q", got)
+	}
+	if last := forms[len(forms)-1]; last.Get("grant_type") != GrantRefreshToken || last.Get("refresh_token") != "refresh-1" {
+		t.Errorf("expected a refresh_token request, got %v", last)
+	}
+}
+
+func TestOAuth2Auth_LoginIgnoresMismatchedState(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.PostForm.Get("code") != "the-code" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = fmt.Fprint(w, `{"error":"invalid_grant"}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"access_token":"user-token","token_type":"Bearer","expires_in":60}`)
+	}))
+	defer tokenServer.Close()
+
+	o := NewOAuth2Auth(GrantAuthorizationCode, tokenServer.URL, "cli-app", "")
+	o.AuthorizeURL = "https://auth.example.com/authorize"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := o.Login(ctx, func(authorizeURL string) {
+		u, _ := url.Parse(authorizeURL)
+		stale := u.Query().Get("redirect_uri") + "?" + url.Values{"code": {"stale-code"}, "state": {"forged"}}.Encode()
+		resp, err := http.Get(stale)
+		if err != nil {
+			t.Errorf("stale callback failed: %v", err)
+			return
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("expected 400 for a mismatched state, got %d", resp.StatusCode)
+		}
+		visit(t, authorizeURL, "the-code", "")
+	})
+	if err != nil {
+		t.Fatalf("expected sign-in to complete after a stale callback, got %v", err)
+	}
+	if got := applyAuth(t, o); got != "Bearer user-token" {
+		t.Errorf("expected signed-in token, got %q", got)
+	}
+
+	// Without a matching callback, sign-in waits until the context ends.
+	o = NewOAuth2Auth(GrantAuthorizationCode, tokenServer.URL, "cli-app", "")
+	o.AuthorizeURL = "https://auth.example.com/authorize"
+	ctx, cancel = context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	err = o.Login(ctx, func(authorizeURL string) {
+		visit(t, authorizeURL, "the-code", "forged")
+	})
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "mismatched state") {
+		t.Errorf("expected a timeout mentioning the mismatched state, got %v", err)
+	}
+}
+
+func TestOAuth2Auth_AuthorizationCodeValidateAndRedact(t *testing.T) {
+	o := NewOAuth2Auth(GrantAuthorizationCode, "https://auth.example.com/token", "cli-app", "")
+	if err := o.Validate(); err == nil {
+		t.Error("expected an error without authorize URL")
+	}
+	o.AuthorizeURL = "https://auth.example.com/authorize"
+	if err := o.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	o.RedirectURL = "http://example.com:8080/callback"
+	if err := o.Validate(); err == nil {
+		t.Error("expected a non-loopback redirect URL to be rejected")
+	}
+	o.RedirectURL = "http://localhost:8765/callback"
+	if err := o.Validate(); err != nil {
+		t.Errorf("unexpected error for localhost redirect: %v", err)
+	}
+
+	o.RefreshToken = "refresh-token-value"
+	redacted := o.Redact().(*OAuth2Auth)
+	if redacted.RefreshToken == o.RefreshToken || redacted.AuthorizeURL != o.AuthorizeURL || redacted.RedirectURL != o.RedirectURL {
+		t.Errorf("unexpected redacted provider: %+v", redacted)
+	}
+}
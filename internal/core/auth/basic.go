@@ -8,6 +8,8 @@ import (
 
 // BasicAuth represents HTTP Basic authentication
 type BasicAuth struct {
+	References
+
 	Username string `json:"username"`
 	Password string `json:"password"`
 }
@@ -25,7 +27,15 @@ func (b *BasicAuth) Apply(req *http.Request) error {
 	if err := b.Validate(); err != nil {
 		return err
 	}
-	req.SetBasicAuth(b.Username, b.Password)
+	username, err := b.Resolve("username", b.Username)
+	if err != nil {
+		return err
+	}
+	password, err := b.Resolve("password", b.Password)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(username, password)
 	return nil
 }
 
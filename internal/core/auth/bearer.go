@@ -8,6 +8,8 @@ import (
 
 // BearerAuth represents Bearer token authentication
 type BearerAuth struct {
+	References
+
 	Token string `json:"token"`
 }
 
@@ -21,7 +23,11 @@ func (b *BearerAuth) Apply(req *http.Request) error {
 	if err := b.Validate(); err != nil {
 		return err
 	}
-	req.Header.Set("Authorization", "Bearer "+b.Token)
+	token, err := b.Resolve("bearer token", b.Token)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
 	return nil
 }
 
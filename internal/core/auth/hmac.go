@@ -0,0 +1,239 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultHMACStringToSign = `{{method}}\n{{path}}\n{{timestamp}}\n{{body_sha256}}`
+	defaultHMACHeaders      = "Authorization: HMAC {{key_id}}:{{signature}}; X-Timestamp: {{timestamp}}"
+)
+
+var hmacVarPattern = regexp.MustCompile(`\{\{([a-z0-9_]+)\}\}`)
+
+// HMACAuth signs requests with a configurable HMAC scheme. StringToSign and
+// the header values are templates over the final request:
+//
+//	{{method}} {{path}} {{query}} {{host}} {{content_type}} {{timestamp}}
+//	{{nonce}} {{body_sha256}} {{body_md5}} {{key_id}}
+//
+// Header templates may also use {{signature}}. A literal \n in StringToSign
+// is a newline.
+type HMACAuth struct {
+	References
+
+	KeyID           string            `json:"key_id,omitempty"`
+	Secret          string            `json:"secret"`
+	Algorithm       string            `json:"algorithm,omitempty"`        // sha256 (default), sha1 or sha512
+	Encoding        string            `json:"encoding,omitempty"`         // hex (default) or base64
+	TimestampFormat string            `json:"timestamp_format,omitempty"` // unix (default), unix_ms, rfc3339 or http
+	StringToSign    string            `json:"string_to_sign,omitempty"`
+	Headers         map[string]string `json:"headers,omitempty"`
+
+	now   func() time.Time
+	nonce func() (string, error)
+}
+
+// NewHMACAuth creates an HMAC provider with the default scheme: an
+// "Authorization: HMAC <key id>:<signature>" header over method, path,
+// Unix timestamp and body SHA-256.
+func NewHMACAuth(keyID, secret string) *HMACAuth {
+	return &HMACAuth{
+		KeyID:   keyID,
+		Secret:  secret,
+		Headers: ParseHeaderTemplates(defaultHMACHeaders),
+		now:     time.Now,
+		nonce:   func() (string, error) { return randomToken(12) },
+	}
+}
+
+// ParseHeaderTemplates parses "Name: template; Name2: template" pairs.
+func ParseHeaderTemplates(s string) map[string]string {
+	headers := make(map[string]string)
+	for _, part := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(part, ":")
+		if ok && strings.TrimSpace(name) != "" {
+			headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		}
+	}
+	return headers
+}
+
+// Apply signs the request and sets the templated headers
+func (h *HMACAuth) Apply(req *http.Request) error {
+	if err := h.Validate(); err != nil {
+		return err
+	}
+	secret, err := h.Resolve("HMAC secret", h.Secret)
+	if err != nil {
+		return err
+	}
+	vars, err := h.requestVars(req)
+	if err != nil {
+		return err
+	}
+	if vars["key_id"], err = h.Resolve("HMAC key ID", h.KeyID); err != nil {
+		return err
+	}
+
+	template := h.StringToSign
+	if template == "" {
+		template = defaultHMACStringToSign
+	}
+	vars["signature"] = h.sign(secret, expandHMACTemplate(strings.ReplaceAll(template, `\n`, "\n"), vars))
+
+	for name, value := range h.Headers {
+		req.Header.Set(name, expandHMACTemplate(value, vars))
+	}
+	return nil
+}
+
+// Type returns the authentication type
+func (h *HMACAuth) Type() string {
+	return "hmac"
+}
+
+// Validate checks if the configuration is valid
+func (h *HMACAuth) Validate() error {
+	if h.Secret == "" {
+		return fmt.Errorf("HMAC secret cannot be empty")
+	}
+	if h.hashFunc() == nil {
+		return fmt.Errorf("unsupported HMAC algorithm: %s (must be 'sha256', 'sha1' or 'sha512')", h.Algorithm)
+	}
+	switch h.Encoding {
+	case "", "hex", "base64":
+	default:
+		return fmt.Errorf("unsupported signature encoding: %s (must be 'hex' or 'base64')", h.Encoding)
+	}
+	switch h.TimestampFormat {
+	case "", "unix", "unix_ms", "rfc3339", "http":
+	default:
+		return fmt.Errorf("unsupported timestamp format: %s (must be 'unix', 'unix_ms', 'rfc3339' or 'http')", h.TimestampFormat)
+	}
+	if len(h.Headers) == 0 {
+		return fmt.Errorf("at least one signature header template is required")
+	}
+	if !slices.ContainsFunc(slices.Collect(maps.Values(h.Headers)), func(v string) bool { return strings.Contains(v, "{{signature}}") }) {
+		return fmt.Errorf("no header template contains {{signature}}")
+	}
+	return nil
+}
+
+// Redact returns a copy with the secret redacted
+func (h *HMACAuth) Redact() AuthProvider {
+	return &HMACAuth{
+		KeyID:           h.KeyID,
+		Secret:          RedactString(h.Secret),
+		Algorithm:       h.Algorithm,
+		Encoding:        h.Encoding,
+		TimestampFormat: h.TimestampFormat,
+		StringToSign:    h.StringToSign,
+		Headers:         h.Headers,
+	}
+}
+
+// String returns a human-readable representation
+func (h *HMACAuth) String() string {
+	names := slices.Sorted(maps.Keys(h.Headers))
+	algorithm := h.Algorithm
+	if algorithm == "" {
+		algorithm = "sha256"
+	}
+	return fmt.Sprintf("HMAC-%s signature (%s) in %s", strings.ToUpper(algorithm), h.KeyID, strings.Join(names, ", "))
+}
+
+func (h *HMACAuth) hashFunc() func() hash.Hash {
+	switch strings.ToLower(h.Algorithm) {
+	case "", "sha256":
+		return sha256.New
+	case "sha1":
+		return sha1.New
+	case "sha512":
+		return sha512.New
+	}
+	return nil
+}
+
+func (h *HMACAuth) sign(secret, stringToSign string) string {
+	mac := hmac.New(h.hashFunc(), []byte(secret))
+	mac.Write([]byte(stringToSign))
+	sum := mac.Sum(nil)
+	if h.Encoding == "base64" {
+		return base64.StdEncoding.EncodeToString(sum)
+	}
+	return hex.EncodeToString(sum)
+}
+
+func (h *HMACAuth) requestVars(req *http.Request) (map[string]string, error) {
+	body, err := requestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now
+	if h.now != nil {
+		now = h.now
+	}
+	t := now()
+	var timestamp string
+	switch h.TimestampFormat {
+	case "unix_ms":
+		timestamp = strconv.FormatInt(t.UnixMilli(), 10)
+	case "rfc3339":
+		timestamp = t.UTC().Format(time.RFC3339)
+	case "http":
+		timestamp = t.UTC().Format(http.TimeFormat)
+	default:
+		timestamp = strconv.FormatInt(t.Unix(), 10)
+	}
+	nonceFunc := h.nonce
+	if nonceFunc == nil {
+		nonceFunc = func() (string, error) { return randomToken(12) }
+	}
+	nonce, err := nonceFunc()
+	if err != nil {
+		return nil, err
+	}
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	md5Sum := md5.Sum(body)
+
+	return map[string]string{
+		"method":       req.Method,
+		"path":         req.URL.EscapedPath(),
+		"query":        req.URL.RawQuery,
+		"host":         host,
+		"content_type": req.Header.Get("Content-Type"),
+		"timestamp":    timestamp,
+		"nonce":        nonce,
+		"body_sha256":  sha256Hex(body),
+		"body_md5":     hex.EncodeToString(md5Sum[:]),
+		"key_id":       h.KeyID,
+	}, nil
+}
+
+func expandHMACTemplate(template string, vars map[string]string) string {
+	return hmacVarPattern.ReplaceAllStringFunc(template, func(ref string) string {
+		if v, ok := vars[ref[2:len(ref)-2]]; ok {
+			return v
+		}
+		return ref
+	})
+}
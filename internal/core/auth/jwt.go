@@ -0,0 +1,196 @@
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// JWT is a decoded JSON Web Token. The signature is not verified; octrafic
+// only reads the claims the API will see.
+type JWT struct {
+	Header    map[string]any
+	Claims    map[string]any
+	Signature string // base64url, as sent
+}
+
+// ParseJWT decodes a compact JWS token (header.payload.signature).
+func ParseJWT(token string) (*JWT, error) {
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("not a JWT: expected 3 parts, got %d", len(parts))
+	}
+	jwt := &JWT{Signature: parts[2]}
+	if err := decodeJWTPart(parts[0], &jwt.Header); err != nil {
+		return nil, fmt.Errorf("failed to decode JWT header: %w", err)
+	}
+	if err := decodeJWTPart(parts[1], &jwt.Claims); err != nil {
+		return nil, fmt.Errorf("failed to decode JWT claims: %w", err)
+	}
+	return jwt, nil
+}
+
+func decodeJWTPart(part string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(part, "="))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// Encode returns the compact form of the token.
+func (j *JWT) Encode() string {
+	header, _ := json.Marshal(j.Header)
+	claims, _ := json.Marshal(j.Claims)
+	return base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims) + "." + j.Signature
+}
+
+// Clone returns a deep copy that can be modified.
+func (j *JWT) Clone() *JWT {
+	clone := &JWT{Signature: j.Signature}
+	data, _ := json.Marshal(j.Header)
+	_ = json.Unmarshal(data, &clone.Header)
+	data, _ = json.Marshal(j.Claims)
+	_ = json.Unmarshal(data, &clone.Claims)
+	return clone
+}
+
+// Subject returns the sub claim.
+func (j *JWT) Subject() string {
+	sub, _ := j.Claims["sub"].(string)
+	return sub
+}
+
+// Audience returns the aud claim, which may be a string or a list.
+func (j *JWT) Audience() []string {
+	return claimStrings(j.Claims["aud"])
+}
+
+// Scopes returns the scopes of the scope or scp claim.
+func (j *JWT) Scopes() []string {
+	if scope, ok := j.Claims["scope"].(string); ok {
+		return strings.Fields(scope)
+	}
+	if scp, ok := j.Claims["scp"].(string); ok {
+		return strings.Fields(scp)
+	}
+	return claimStrings(j.Claims["scp"])
+}
+
+// ExpiresAt returns the exp claim, or false if there is none.
+func (j *JWT) ExpiresAt() (time.Time, bool) {
+	return j.timeClaim("exp")
+}
+
+// IssuedAt returns the iat claim, or false if there is none.
+func (j *JWT) IssuedAt() (time.Time, bool) {
+	return j.timeClaim("iat")
+}
+
+func (j *JWT) timeClaim(name string) (time.Time, bool) {
+	switch v := j.Claims[name].(type) {
+	case float64:
+		return time.Unix(int64(v), 0), true
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return time.Unix(n, 0), true
+		}
+	}
+	return time.Time{}, false
+}
+
+func claimStrings(v any) []string {
+	switch val := v.(type) {
+	case string:
+		return []string{val}
+	case []any:
+		out := make([]string, 0, len(val))
+		for _, item := range val {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// ExpiryWarning returns a warning when the token has expired or expires
+// within the given duration, e.g. the expected length of a test run.
+func (j *JWT) ExpiryWarning(now time.Time, within time.Duration) string {
+	exp, ok := j.ExpiresAt()
+	if !ok {
+		return ""
+	}
+	switch left := exp.Sub(now); {
+	case left <= 0:
+		return fmt.Sprintf("The bearer token expired %s ago (%s); requests will likely be rejected", roundDuration(-left), exp.Local().Format("2006-01-02 15:04"))
+	case left < within:
+		return fmt.Sprintf("The bearer token expires in %s (%s), possibly before the run finishes", roundDuration(left), exp.Local().Format("15:04:05"))
+	}
+	return ""
+}
+
+// Describe returns the claims worth showing, one "Name: value" per line.
+func (j *JWT) Describe(now time.Time) []string {
+	var lines []string
+	if alg, ok := j.Header["alg"].(string); ok {
+		lines = append(lines, "Algorithm: "+alg)
+	}
+	if sub := j.Subject(); sub != "" {
+		lines = append(lines, "Subject: "+sub)
+	}
+	if iss, ok := j.Claims["iss"].(string); ok {
+		lines = append(lines, "Issuer: "+iss)
+	}
+	if aud := j.Audience(); len(aud) > 0 {
+		lines = append(lines, "Audience: "+strings.Join(aud, ", "))
+	}
+	if scopes := j.Scopes(); len(scopes) > 0 {
+		lines = append(lines, "Scopes: "+strings.Join(scopes, " "))
+	}
+	for _, name := range roleClaims {
+		if roles := claimStrings(j.Claims[name]); len(roles) > 0 {
+			lines = append(lines, "Roles: "+strings.Join(roles, ", "))
+			break
+		}
+	}
+	if exp, ok := j.ExpiresAt(); ok {
+		if left := exp.Sub(now); left > 0 {
+			lines = append(lines, fmt.Sprintf("Expires: %s (in %s)", exp.Local().Format("2006-01-02 15:04"), roundDuration(left)))
+		} else {
+			lines = append(lines, fmt.Sprintf("Expires: %s (expired %s ago)", exp.Local().Format("2006-01-02 15:04"), roundDuration(-left)))
+		}
+	}
+	return lines
+}
+
+// roleClaims are the claims APIs commonly authorize on.
+var roleClaims = []string{"role", "roles", "groups", "permissions"}
+
+func roundDuration(d time.Duration) time.Duration {
+	if d >= time.Hour {
+		return d.Round(time.Minute)
+	}
+	return d.Round(time.Second)
+}
+
+// BearerJWT returns the decoded token of a bearer provider, or false if the
+// provider sends no JWT.
+func BearerJWT(provider AuthProvider) (*JWT, bool) {
+	bearer, ok := provider.(*BearerAuth)
+	if !ok {
+		return nil, false
+	}
+	token, err := bearer.Resolve("bearer token", bearer.Token)
+	if err != nil {
+		return nil, false
+	}
+	jwt, err := ParseJWT(token)
+	if err != nil {
+		return nil, false
+	}
+	return jwt, true
+}
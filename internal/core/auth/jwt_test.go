@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testJWT(claims string) string {
+	enc := base64.RawURLEncoding.EncodeToString
+	return enc([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." + enc([]byte(claims)) + ".c2lnbmF0dXJl"
+}
+
+func TestParseJWT(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	token := testJWT(`{"sub":"user-1","scope":"read write","aud":["api","admin"],"exp":1700003600,"roles":["member"]}`)
+
+	jwt, err := ParseJWT(token)
+	if err != nil {
+		t.Fatalf("ParseJWT failed: %v", err)
+	}
+	if jwt.Subject() != "user-1" {
+		t.Errorf("Expected subject user-1, got %q", jwt.Subject())
+	}
+	if got := strings.Join(jwt.Scopes(), ","); got != "read,write" {
+		t.Errorf("Expected scopes read,write, got %q", got)
+	}
+	if got := strings.Join(jwt.Audience(), ","); got != "api,admin" {
+		t.Errorf("Expected audience api,admin, got %q", got)
+	}
+	exp, ok := jwt.ExpiresAt()
+	if !ok || !exp.Equal(now.Add(time.Hour)) {
+		t.Errorf("Expected exp one hour after now, got %v", exp)
+	}
+	if again, err := ParseJWT(jwt.Encode()); err != nil || again.Subject() != "user-1" || again.Signature != jwt.Signature {
+		t.Errorf("Expected Encode to round-trip, got %v %v", again, err)
+	}
+
+	describe := strings.Join(jwt.Describe(now), "\n")
+	for _, want := range []string{"Subject: user-1", "Scopes: read write", "Roles: member", "(in 1h0m0s)"} {
+		if !strings.Contains(describe, want) {
+			t.Errorf("Expected %q in description:\n%s", want, describe)
+		}
+	}
+
+	if _, err := ParseJWT("not-a-token"); err == nil {
+		t.Error("Expected error for a token without 3 parts")
+	}
+}
+
+func TestJWT_ExpiryWarning(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	jwt, err := ParseJWT(testJWT(`{"exp":1700000300}`))
+	if err != nil {
+		t.Fatalf("ParseJWT failed: %v", err)
+	}
+
+	if w := jwt.ExpiryWarning(now, time.Minute); w != "" {
+		t.Errorf("Expected no warning for a token valid for the whole run, got %q", w)
+	}
+	if w := jwt.ExpiryWarning(now, 10*time.Minute); !strings.Contains(w, "expires in 5m0s") {
+		t.Errorf("Expected mid-run expiry warning, got %q", w)
+	}
+	if w := jwt.ExpiryWarning(now.Add(time.Hour), time.Minute); !strings.Contains(w, "expired 55m0s ago") {
+		t.Errorf("Expected expired warning, got %q", w)
+	}
+}
+
+func TestBearerJWT(t *testing.T) {
+	t.Setenv("TEST_JWT", testJWT(`{"sub":"env-user"}`))
+
+	jwt, ok := BearerJWT(AllowReferences(NewBearerAuth("env:TEST_JWT")))
+	if !ok || jwt.Subject() != "env-user" {
+		t.Errorf("Expected the resolved JWT, got %v %v", jwt, ok)
+	}
+	if _, ok := BearerJWT(NewBearerAuth("opaque-token")); ok {
+		t.Error("Expected no JWT for an opaque token")
+	}
+	if _, ok := BearerJWT(NewBasicAuth("user", "pass")); ok {
+		t.Error("Expected no JWT for basic auth")
+	}
+}
@@ -0,0 +1,332 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	GrantClientCredentials = "client_credentials"
+	GrantRefreshToken      = "refresh_token"
+	GrantAuthorizationCode = "authorization_code"
+
+	// tokenExpirySkew renews tokens this long before they expire, so a
+	// token does not run out while a request is in flight.
+	tokenExpirySkew = 30 * time.Second
+)
+
+// Refresher is implemented by providers whose credentials can be renewed.
+// The executor calls Refresh and retries once when a request gets a 401.
+type Refresher interface {
+	Refresh() error
+}
+
+// ErrLoginRequired is returned by the authorization_code grant when there
+// is no refresh token yet and the user has to sign in with Login.
+var ErrLoginRequired = errors.New("OAuth2 sign-in required (run 'auth login')")
+
+// OAuth2Auth obtains access tokens from an OAuth2 token endpoint using the
+// client_credentials, refresh_token or authorization_code grant, caches them
+// until shortly before they expire and sends them as Bearer tokens.
+type OAuth2Auth struct {
+	References
+
+	GrantType    string   `json:"grant_type"`
+	TokenURL     string   `json:"token_url"`
+	ClientID     string   `json:"client_id"`
+	ClientSecret string   `json:"client_secret,omitempty"`
+	RefreshToken string   `json:"refresh_token,omitempty"`
+	Scopes       []string `json:"scopes,omitempty"`
+	Audience     string   `json:"audience,omitempty"`
+
+	// AuthorizeURL and RedirectURL are used by the authorization_code grant.
+	// RedirectURL must be a loopback http URL; it defaults to a random port.
+	AuthorizeURL string `json:"authorize_url,omitempty"`
+	RedirectURL  string `json:"redirect_url,omitempty"`
+
+	// OnRefreshToken, if set, is called when the server rotates the
+	// refresh token, so the new one can be saved.
+	OnRefreshToken func(refreshToken string) `json:"-"`
+
+	mu          sync.Mutex
+	accessToken string
+	expiry      time.Time // zero if the server sent no expires_in
+	bodyAuth    bool      // send client credentials in the form instead of Basic auth
+	client      *http.Client
+	now         func() time.Time
+}
+
+// NewOAuth2Auth creates an OAuth2 provider. Scopes, Audience and
+// RefreshToken are set on the returned value as needed.
+func NewOAuth2Auth(grantType, tokenURL, clientID, clientSecret string) *OAuth2Auth {
+	if grantType == "" {
+		grantType = GrantClientCredentials
+	}
+	return &OAuth2Auth{
+		GrantType:    grantType,
+		TokenURL:     tokenURL,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		client:       &http.Client{Timeout: 30 * time.Second},
+		now:          time.Now,
+	}
+}
+
+// ParseScopes splits a space- or comma-separated scope list.
+func ParseScopes(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == ',' })
+}
+
+// Apply adds a valid access token to the Authorization header, fetching
+// a new one if none is cached or the cached one is about to expire.
+func (o *OAuth2Auth) Apply(req *http.Request) error {
+	if err := o.Validate(); err != nil {
+		return err
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.accessToken == "" || (!o.expiry.IsZero() && !o.clock().Add(tokenExpirySkew).Before(o.expiry)) {
+		if err := o.fetchToken(); err != nil {
+			return err
+		}
+	}
+	req.Header.Set("Authorization", "Bearer "+o.accessToken)
+	return nil
+}
+
+// Refresh discards the cached token and fetches a new one.
+func (o *OAuth2Auth) Refresh() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.accessToken = ""
+	return o.fetchToken()
+}
+
+// Type returns the authentication type
+func (o *OAuth2Auth) Type() string {
+	return "oauth2"
+}
+
+// Validate checks that the grant has what it needs
+func (o *OAuth2Auth) Validate() error {
+	u, err := url.Parse(o.TokenURL)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("token URL must be an http(s) URL, got: %q", o.TokenURL)
+	}
+	switch o.GrantType {
+	case GrantClientCredentials:
+		if strings.TrimSpace(o.ClientID) == "" || strings.TrimSpace(o.ClientSecret) == "" {
+			return fmt.Errorf("client ID and client secret are required for the client_credentials grant")
+		}
+	case GrantRefreshToken:
+		if strings.TrimSpace(o.RefreshToken) == "" {
+			return fmt.Errorf("refresh token is required for the refresh_token grant")
+		}
+	case GrantAuthorizationCode:
+		u, err := url.Parse(o.AuthorizeURL)
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("authorize URL must be an http(s) URL, got: %q", o.AuthorizeURL)
+		}
+		if strings.TrimSpace(o.ClientID) == "" {
+			return fmt.Errorf("client ID is required for the authorization_code grant")
+		}
+		if o.RedirectURL != "" {
+			if _, err := loopbackRedirect(o.RedirectURL); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported grant type: %s (must be '%s', '%s' or '%s')", o.GrantType, GrantClientCredentials, GrantRefreshToken, GrantAuthorizationCode)
+	}
+	return nil
+}
+
+// Redact returns a copy with secrets redacted and no cached token
+func (o *OAuth2Auth) Redact() AuthProvider {
+	redacted := &OAuth2Auth{
+		GrantType:    o.GrantType,
+		TokenURL:     o.TokenURL,
+		ClientID:     o.ClientID,
+		Scopes:       o.Scopes,
+		Audience:     o.Audience,
+		AuthorizeURL: o.AuthorizeURL,
+		RedirectURL:  o.RedirectURL,
+	}
+	if o.ClientSecret != "" {
+		redacted.ClientSecret = RedactString(o.ClientSecret)
+	}
+	if o.RefreshToken != "" {
+		redacted.RefreshToken = RedactString(o.RefreshToken)
+	}
+	return redacted
+}
+
+// String returns a human-readable representation
+func (o *OAuth2Auth) String() string {
+	s := fmt.Sprintf("OAuth2 %s via %s (client %s)", o.GrantType, o.TokenURL, o.ClientID)
+	if len(o.Scopes) > 0 {
+		s += fmt.Sprintf(", scopes: %s", strings.Join(o.Scopes, " "))
+	}
+	if o.NeedsLogin() {
+		s += ", not signed in"
+	}
+	return s
+}
+
+// NeedsLogin reports whether the authorization_code grant has no tokens yet.
+func (o *OAuth2Auth) NeedsLogin() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.GrantType == GrantAuthorizationCode && o.RefreshToken == "" && o.accessToken == ""
+}
+
+func (o *OAuth2Auth) clock() time.Time {
+	if o.now == nil {
+		return time.Now()
+	}
+	return o.now()
+}
+
+// tokenResponse is the token endpoint reply (RFC 6749 section 5).
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	RefreshToken     string `json:"refresh_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// fetchToken requests a new access token for the configured grant. The
+// authorization_code grant renews its tokens with the refresh token it got
+// from Login. The caller holds o.mu.
+func (o *OAuth2Auth) fetchToken() error {
+	form := url.Values{"grant_type": {o.GrantType}}
+	switch o.GrantType {
+	case GrantAuthorizationCode:
+		if o.RefreshToken == "" {
+			return ErrLoginRequired
+		}
+		refreshToken, err := o.Resolve("refresh token", o.RefreshToken)
+		if err != nil {
+			return err
+		}
+		form.Set("grant_type", GrantRefreshToken)
+		form.Set("refresh_token", refreshToken)
+	case GrantRefreshToken:
+		refreshToken, err := o.Resolve("refresh token", o.RefreshToken)
+		if err != nil {
+			return err
+		}
+		form.Set("refresh_token", refreshToken)
+	}
+	if len(o.Scopes) > 0 {
+		form.Set("scope", strings.Join(o.Scopes, " "))
+	}
+	if o.Audience != "" {
+		form.Set("audience", o.Audience)
+	}
+	return o.exchange(form)
+}
+
+// exchange posts a token request and stores the tokens it returns. Client
+// credentials go in a Basic Authorization header first; servers that reject
+// that with invalid_client are retried with the credentials in the form,
+// which is then remembered. The caller holds o.mu.
+func (o *OAuth2Auth) exchange(form url.Values) error {
+	resp, err := o.requestToken(form)
+	if err == nil && resp.Error == "invalid_client" && !o.bodyAuth && o.ClientSecret != "" {
+		o.bodyAuth = true
+		resp, err = o.requestToken(form)
+	}
+	if err != nil {
+		return err
+	}
+	if resp.Error != "" {
+		if resp.ErrorDescription != "" {
+			return fmt.Errorf("token request failed: %s: %s", resp.Error, resp.ErrorDescription)
+		}
+		return fmt.Errorf("token request failed: %s", resp.Error)
+	}
+	if resp.AccessToken == "" {
+		return fmt.Errorf("token response has no access_token")
+	}
+	if resp.TokenType != "" && !strings.EqualFold(resp.TokenType, "bearer") {
+		return fmt.Errorf("unsupported token type: %s", resp.TokenType)
+	}
+
+	o.accessToken = resp.AccessToken
+	o.expiry = time.Time{}
+	if resp.ExpiresIn > 0 {
+		o.expiry = o.clock().Add(time.Duration(resp.ExpiresIn) * time.Second)
+	}
+	if resp.RefreshToken != "" && resp.RefreshToken != o.RefreshToken {
+		o.RefreshToken = resp.RefreshToken
+		if o.OnRefreshToken != nil {
+			o.OnRefreshToken(resp.RefreshToken)
+		}
+	}
+	return nil
+}
+
+func (o *OAuth2Auth) requestToken(params url.Values) (*tokenResponse, error) {
+	clientID, err := o.Resolve("client ID", o.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	clientSecret, err := o.Resolve("client secret", o.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+	form := maps.Clone(params)
+	if clientSecret == "" || o.bodyAuth {
+		form.Set("client_id", clientID)
+		if clientSecret != "" {
+			form.Set("client_secret", clientSecret)
+		}
+	}
+
+	req, err := http.NewRequest("POST", o.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if clientSecret != "" && !o.bodyAuth {
+		req.SetBasicAuth(url.QueryEscape(clientID), url.QueryEscape(clientSecret))
+	}
+
+	client := o.client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token response: %w", err)
+	}
+	var tr tokenResponse
+	if err := json.Unmarshal(body, &tr); err != nil {
+		if resp.StatusCode >= 400 {
+			return nil, fmt.Errorf("token request failed: %s", resp.Status)
+		}
+		return nil, fmt.Errorf("failed to parse token response: %w", err)
+	}
+	if resp.StatusCode >= 400 && tr.Error == "" {
+		tr.Error = resp.Status
+	}
+	return &tr, nil
+}
@@ -0,0 +1,169 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeTokenServer is a minimal OAuth2 token endpoint.
+type fakeTokenServer struct {
+	mu        sync.Mutex
+	requests  []url.Values
+	basicAuth []string
+	expiresIn int
+	rotate    bool // issue a new refresh token on every request
+	formOnly  bool // reject Basic client authentication
+}
+
+func (f *fakeTokenServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, r.PostForm)
+	user, _, _ := r.BasicAuth()
+	f.basicAuth = append(f.basicAuth, user)
+	n := len(f.requests)
+
+	w.Header().Set("Content-Type", "application/json")
+	if f.formOnly && user != "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = fmt.Fprint(w, `{"error":"invalid_client"}`)
+		return
+	}
+	refresh := ""
+	if f.rotate {
+		refresh = fmt.Sprintf(`,"refresh_token":"refresh-%d"`, n)
+	}
+	_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":%d%s}`, n, f.expiresIn, refresh)
+}
+
+func (f *fakeTokenServer) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.requests)
+}
+
+func applyAuth(t *testing.T, p AuthProvider) string {
+	t.Helper()
+	req, _ := http.NewRequest("GET", "http://api.example.com/users", nil)
+	if err := p.Apply(req); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	return req.Header.Get("Authorization")
+}
+
+func TestOAuth2Auth_ClientCredentialsCachesAndRenews(t *testing.T) {
+	fake := &fakeTokenServer{expiresIn: 300}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	now := time.Now()
+	o := NewOAuth2Auth(GrantClientCredentials, server.URL, "my-client", "s3cret")
+	o.Scopes = ParseScopes("read,write admin")
+	o.Audience = "https://api.example.com"
+	o.now = func() time.Time { return now }
+
+	if got := applyAuth(t, o); got != "Bearer token-1" {
+		t.Errorf("expected first token, got %q", got)
+	}
+	if got := applyAuth(t, o); got != "Bearer token-1" || fake.count() != 1 {
+		t.Errorf("expected cached token, got %q after %d requests", got, fake.count())
+	}
+
+	form := fake.requests[0]
+	if form.Get("grant_type") != "client_credentials" || form.Get("scope") != "read write admin" || form.Get("audience") != "https://api.example.com" {
+		t.Errorf("unexpected token request: %v", form)
+	}
+	if fake.basicAuth[0] != "my-client" || form.Get("client_secret") != "" {
+		t.Errorf("expected Basic client authentication, got user %q and form %v", fake.basicAuth[0], form)
+	}
+
+	// Within the expiry skew the token is renewed before use.
+	now = now.Add(290 * time.Second)
+	if got := applyAuth(t, o); got != "Bearer token-2" {
+		t.Errorf("expected renewed token before expiry, got %q", got)
+	}
+
+	if err := o.Refresh(); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if got := applyAuth(t, o); got != "Bearer token-3" {
+		t.Errorf("expected refreshed token, got %q", got)
+	}
+}
+
+func TestOAuth2Auth_RefreshTokenRotation(t *testing.T) {
+	fake := &fakeTokenServer{expiresIn: 3600, rotate: true}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	var saved []string
+	o := NewOAuth2Auth(GrantRefreshToken, server.URL, "public-client", "")
+	o.RefreshToken = "refresh-0"
+	o.OnRefreshToken = func(token string) { saved = append(saved, token) }
+
+	if got := applyAuth(t, o); got != "Bearer token-1" {
+		t.Errorf("expected access token, got %q", got)
+	}
+	if err := o.Refresh(); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	if got := fake.requests[0]; got.Get("grant_type") != "refresh_token" || got.Get("refresh_token") != "refresh-0" || got.Get("client_id") != "public-client" {
+		t.Errorf("unexpected first refresh request: %v", got)
+	}
+	if got := fake.requests[1].Get("refresh_token"); got != "refresh-1" {
+		t.Errorf("expected the rotated refresh token to be used, got %q", got)
+	}
+	if len(saved) != 2 || saved[1] != "refresh-2" || o.RefreshToken != "refresh-2" {
+		t.Errorf("expected rotated tokens to be reported, got %v (current %q)", saved, o.RefreshToken)
+	}
+}
+
+func TestOAuth2Auth_FallsBackToFormCredentials(t *testing.T) {
+	fake := &fakeTokenServer{formOnly: true}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	o := NewOAuth2Auth("", server.URL, "my-client", "s3cret")
+	if got := applyAuth(t, o); got != "Bearer token-2" {
+		t.Errorf("expected token after fallback, got %q", got)
+	}
+	if form := fake.requests[1]; form.Get("client_id") != "my-client" || form.Get("client_secret") != "s3cret" {
+		t.Errorf("expected credentials in the form, got %v", form)
+	}
+}
+
+func TestOAuth2Auth_ValidateAndRedact(t *testing.T) {
+	tests := []struct {
+		name  string
+		auth  *OAuth2Auth
+		valid bool
+	}{
+		{"client credentials", NewOAuth2Auth(GrantClientCredentials, "https://auth.example.com/token", "id", "secret"), true},
+		{"missing secret", NewOAuth2Auth(GrantClientCredentials, "https://auth.example.com/token", "id", ""), false},
+		{"bad token URL", NewOAuth2Auth(GrantClientCredentials, "auth.example.com/token", "id", "secret"), false},
+		{"missing refresh token", NewOAuth2Auth(GrantRefreshToken, "https://auth.example.com/token", "id", ""), false},
+		{"unknown grant", NewOAuth2Auth("password", "https://auth.example.com/token", "id", "secret"), false},
+	}
+	for _, tt := range tests {
+		if err := tt.auth.Validate(); (err == nil) != tt.valid {
+			t.Errorf("%s: expected valid=%v, got %v", tt.name, tt.valid, err)
+		}
+	}
+
+	o := NewOAuth2Auth(GrantRefreshToken, "https://auth.example.com/token", "id", "client-secret-value")
+	o.RefreshToken = "refresh-token-value"
+	redacted := o.Redact().(*OAuth2Auth)
+	if redacted.ClientSecret == o.ClientSecret || redacted.RefreshToken == o.RefreshToken {
+		t.Error("secrets were not redacted")
+	}
+	if redacted.ClientID != "id" || redacted.Type() != "oauth2" {
+		t.Errorf("unexpected redacted provider: %+v", redacted)
+	}
+}
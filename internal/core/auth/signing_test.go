@@ -0,0 +1,172 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Credentials and time used by the AWS Signature Version 4 test suite.
+const (
+	exampleAccessKey = "AKIDEXAMPLE"
+	exampleSecretKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+)
+
+var exampleTime = time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+
+func signedRequest(t *testing.T, s *SigV4Auth, method, url string, headers map[string]string, body string) *http.Request {
+	t.Helper()
+	var reader io.Reader
+	if body != "" {
+		reader = bytes.NewReader([]byte(body))
+	}
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	s.now = func() time.Time { return exampleTime }
+	if err := s.Apply(req); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	return req
+}
+
+func TestSigV4SigningKey(t *testing.T) {
+	// From the AWS documentation on deriving a signing key.
+	got := hex.EncodeToString(sigV4SigningKey(exampleSecretKey, "20120215", "us-east-1", "iam"))
+	if got != "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d" {
+		t.Errorf("unexpected signing key: %s", got)
+	}
+}
+
+func TestSigV4Auth_TestVectors(t *testing.T) {
+	tests := []struct {
+		name    string
+		service string
+		method  string
+		url     string
+		headers map[string]string
+		want    string
+	}{
+		{
+			name:    "get-vanilla",
+			service: "service",
+			method:  "GET",
+			url:     "https://example.amazonaws.com/",
+			want:    "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		},
+		{
+			name:    "post-vanilla",
+			service: "service",
+			method:  "POST",
+			url:     "https://example.amazonaws.com/",
+			want:    "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5da7c1a2acd57cee7505fc6676e4e544621c30862966e37dddb68e92efbe5d6b",
+		},
+		{
+			name:    "iam-list-users",
+			service: "iam",
+			method:  "GET",
+			url:     "https://iam.amazonaws.com/?Action=ListUsers&Version=2010-05-08",
+			headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded; charset=utf-8"},
+			want:    "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/iam/aws4_request, SignedHeaders=content-type;host;x-amz-date, Signature=5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7",
+		},
+	}
+	for _, tt := range tests {
+		s := NewSigV4Auth(exampleAccessKey, exampleSecretKey, "", "us-east-1", tt.service)
+		req := signedRequest(t, s, tt.method, tt.url, tt.headers, "")
+		if got := req.Header.Get("Authorization"); got != tt.want {
+			t.Errorf("%s:\n got %s\nwant %s", tt.name, got, tt.want)
+		}
+		if req.Header.Get("X-Amz-Date") != "20150830T123600Z" {
+			t.Errorf("%s: unexpected X-Amz-Date %q", tt.name, req.Header.Get("X-Amz-Date"))
+		}
+	}
+}
+
+func TestSigV4Auth_BodyAndSessionToken(t *testing.T) {
+	s := NewSigV4Auth(exampleAccessKey, exampleSecretKey, "session-token", "eu-west-1", "")
+	a := signedRequest(t, s, "POST", "https://api.example.com/prod/items", nil, `{"name":"a"}`)
+	b := signedRequest(t, s, "POST", "https://api.example.com/prod/items", nil, `{"name":"b"}`)
+
+	if a.Header.Get("X-Amz-Security-Token") != "session-token" {
+		t.Error("expected the session token header")
+	}
+	auth := a.Header.Get("Authorization")
+	if !strings.Contains(auth, "/eu-west-1/execute-api/aws4_request") || !strings.Contains(auth, "x-amz-security-token") {
+		t.Errorf("unexpected Authorization header: %s", auth)
+	}
+	if auth == b.Header.Get("Authorization") {
+		t.Error("signature must cover the body")
+	}
+	if data, _ := io.ReadAll(a.Body); string(data) != `{"name":"a"}` {
+		t.Errorf("signing must not consume the body, got %q", data)
+	}
+
+	redacted := s.Redact().(*SigV4Auth)
+	if redacted.SecretAccessKey == s.SecretAccessKey || redacted.SessionToken == s.SessionToken {
+		t.Error("secrets were not redacted")
+	}
+	if err := NewSigV4Auth(exampleAccessKey, exampleSecretKey, "", "", "").Validate(); err == nil {
+		t.Error("expected an error without region")
+	}
+}
+
+func TestHMACAuth_RFC4231Vector(t *testing.T) {
+	// RFC 4231 test case 2, with a constant string to sign.
+	h := NewHMACAuth("", "Jefe")
+	h.StringToSign = "what do ya want for nothing?"
+	h.Headers = map[string]string{"X-Signature": "{{signature}}"}
+
+	req, _ := http.NewRequest("GET", "https://api.example.com/", nil)
+	if err := h.Apply(req); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if got := req.Header.Get("X-Signature"); got != "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843" {
+		t.Errorf("unexpected signature: %s", got)
+	}
+}
+
+func TestHMACAuth_Templates(t *testing.T) {
+	h := NewHMACAuth("partner-1", "top-secret")
+	h.now = func() time.Time { return time.Unix(1700000000, 0) }
+	h.nonce = func() (string, error) { return "n0nce", nil }
+	h.Encoding = "base64"
+	h.StringToSign = `{{method}}\n{{path}}?{{query}}\n{{timestamp}}\n{{nonce}}\n{{body_sha256}}`
+	h.Headers = ParseHeaderTemplates("X-Partner-Signature: v1={{signature}}; X-Partner-Key: {{key_id}}; X-Partner-Time: {{timestamp}}; X-Nonce: {{nonce}}")
+
+	body := `{"amount":10}`
+	req, _ := http.NewRequest("POST", "https://api.example.com/v1/payments?dry=1", strings.NewReader(body))
+	if err := h.Apply(req); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	sum := sha256.Sum256([]byte(body))
+	mac := hmac.New(sha256.New, []byte("top-secret"))
+	mac.Write([]byte("POST\n/v1/payments?dry=1\n1700000000\nn0nce\n" + hex.EncodeToString(sum[:])))
+	want := "v1=" + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	if got := req.Header.Get("X-Partner-Signature"); got != want {
+		t.Errorf("unexpected signature header:\n got %s\nwant %s", got, want)
+	}
+	if req.Header.Get("X-Partner-Key") != "partner-1" || req.Header.Get("X-Partner-Time") != "1700000000" || req.Header.Get("X-Nonce") != "n0nce" {
+		t.Errorf("unexpected headers: %v", req.Header)
+	}
+
+	h.Headers = map[string]string{"X-Time": "{{timestamp}}"}
+	if err := h.Validate(); err == nil {
+		t.Error("expected an error when no header carries the signature")
+	}
+	if redacted := h.Redact().(*HMACAuth); redacted.Secret == h.Secret {
+		t.Error("secret was not redacted")
+	}
+}
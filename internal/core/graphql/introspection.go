@@ -0,0 +1,254 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// IntrospectionQuery is the standard introspection query, limited to the
+// parts of the type system needed to build operations.
+const IntrospectionQuery = `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types {
+      kind
+      name
+      description
+      fields(includeDeprecated: true) {
+        name
+        description
+        args { ...InputValue }
+        type { ...TypeRef }
+      }
+      inputFields { ...InputValue }
+      interfaces { ...TypeRef }
+      enumValues(includeDeprecated: true) { name }
+      possibleTypes { ...TypeRef }
+    }
+  }
+}
+
+fragment InputValue on __InputValue {
+  name
+  description
+  type { ...TypeRef }
+  defaultValue
+}
+
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType {
+    kind
+    name
+    ofType {
+      kind
+      name
+      ofType {
+        kind
+        name
+        ofType {
+          kind
+          name
+          ofType {
+            kind
+            name
+            ofType { kind name }
+          }
+        }
+      }
+    }
+  }
+}`
+
+type introspectionResult struct {
+	Data   *introspectionData   `json:"data"`
+	Schema *introspectionSchema `json:"__schema"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+type introspectionData struct {
+	Schema *introspectionSchema `json:"__schema"`
+}
+
+type namedRef struct {
+	Name string `json:"name"`
+}
+
+type introspectionSchema struct {
+	QueryType        *namedRef           `json:"queryType"`
+	MutationType     *namedRef           `json:"mutationType"`
+	SubscriptionType *namedRef           `json:"subscriptionType"`
+	Types            []introspectionType `json:"types"`
+}
+
+type introspectionType struct {
+	Kind        string `json:"kind"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Fields      []struct {
+		Name        string               `json:"name"`
+		Description string               `json:"description"`
+		Args        []introspectionInput `json:"args"`
+		Type        *TypeRef             `json:"type"`
+	} `json:"fields"`
+	InputFields   []introspectionInput `json:"inputFields"`
+	Interfaces    []TypeRef            `json:"interfaces"`
+	EnumValues    []namedRef           `json:"enumValues"`
+	PossibleTypes []TypeRef            `json:"possibleTypes"`
+}
+
+type introspectionInput struct {
+	Name         string   `json:"name"`
+	Description  string   `json:"description"`
+	Type         *TypeRef `json:"type"`
+	DefaultValue *string  `json:"defaultValue"`
+}
+
+// UnmarshalJSON decodes a __Type reference from an introspection result.
+func (r *TypeRef) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Kind   string   `json:"kind"`
+		Name   *string  `json:"name"`
+		OfType *TypeRef `json:"ofType"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	r.OfType = raw.OfType
+	if raw.Kind == KindList || raw.Kind == KindNonNull {
+		r.Kind = raw.Kind
+		return nil
+	}
+	if raw.Name != nil {
+		r.Name = *raw.Name
+	}
+	return nil
+}
+
+// IsIntrospection reports whether content looks like an introspection result.
+func IsIntrospection(content []byte) bool {
+	return bytes.Contains(content, []byte(`"__schema"`))
+}
+
+// FromIntrospection builds a schema from an introspection result. Both the
+// full response ({"data": {"__schema": ...}}) and the bare __schema wrapper
+// are accepted.
+func FromIntrospection(content []byte) (*Schema, error) {
+	var result introspectionResult
+	if err := json.Unmarshal(content, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse introspection result: %w", err)
+	}
+	raw := result.Schema
+	if result.Data != nil && result.Data.Schema != nil {
+		raw = result.Data.Schema
+	}
+	if raw == nil {
+		if len(result.Errors) > 0 {
+			return nil, fmt.Errorf("introspection failed: %s", result.Errors[0].Message)
+		}
+		return nil, fmt.Errorf("introspection result has no __schema")
+	}
+
+	s := NewSchema()
+	if raw.QueryType != nil {
+		s.QueryType = raw.QueryType.Name
+	}
+	if raw.MutationType != nil {
+		s.MutationType = raw.MutationType.Name
+	}
+	if raw.SubscriptionType != nil {
+		s.SubscriptionType = raw.SubscriptionType.Name
+	}
+
+	for _, it := range raw.Types {
+		t := &Type{Kind: it.Kind, Name: it.Name, Description: it.Description}
+		for _, f := range it.Fields {
+			t.Fields = append(t.Fields, Field{
+				Name:        f.Name,
+				Description: f.Description,
+				Args:        convertInputs(f.Args),
+				Type:        f.Type,
+			})
+		}
+		t.InputFields = convertInputs(it.InputFields)
+		for _, ev := range it.EnumValues {
+			t.EnumValues = append(t.EnumValues, ev.Name)
+		}
+		for _, ref := range it.Interfaces {
+			t.Interfaces = append(t.Interfaces, ref.Named())
+		}
+		for _, ref := range it.PossibleTypes {
+			t.PossibleTypes = append(t.PossibleTypes, ref.Named())
+		}
+		s.Types[t.Name] = t
+	}
+
+	s.resolveDefaults()
+	return s, nil
+}
+
+func convertInputs(inputs []introspectionInput) []InputValue {
+	var values []InputValue
+	for _, in := range inputs {
+		v := InputValue{Name: in.Name, Description: in.Description, Type: in.Type}
+		if in.DefaultValue != nil {
+			// Default values are GraphQL literals encoded as strings.
+			p := &sdlParser{lex: newLexer(*in.DefaultValue)}
+			if err := p.advance(); err == nil {
+				if dv, err := p.parseValue(); err == nil {
+					v.DefaultValue = dv
+					v.HasDefault = true
+				}
+			}
+		}
+		values = append(values, v)
+	}
+	return values
+}
+
+// Introspect runs the introspection query against a GraphQL endpoint and
+// returns the raw result for FromIntrospection. The optional apply callback
+// can add authentication to the request.
+func Introspect(endpoint string, apply func(*http.Request) error) ([]byte, error) {
+	payload, err := json.Marshal(map[string]any{"query": IntrospectionQuery})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode introspection query: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if apply != nil {
+		if err := apply(req); err != nil {
+			return nil, fmt.Errorf("failed to apply auth: %w", err)
+		}
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("network error: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("introspection returned HTTP %d", resp.StatusCode)
+	}
+	return body, nil
+}
@@ -0,0 +1,301 @@
+package graphql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultDepth is the default nesting depth of generated selection sets.
+const DefaultDepth = 3
+
+// Operation is an executable document for a single root field.
+type Operation struct {
+	Type           string         // "query", "mutation" or "subscription"
+	Field          string         // root field name
+	Name           string         // operation name
+	Document       string         // full GraphQL document
+	Variables      map[string]any // sample variable values
+	ReturnType     string         // SDL notation of the field type
+	ResponseSchema map[string]any // JSON schema of the expected response
+}
+
+// Operations builds an operation for every query and mutation root field.
+func (s *Schema) Operations() []Operation {
+	var ops []Operation
+	for _, opType := range []string{"query", "mutation"} {
+		for _, f := range s.RootFields(opType) {
+			if strings.HasPrefix(f.Name, "__") {
+				continue
+			}
+			if op, err := s.BuildOperation(opType, f.Name, DefaultDepth); err == nil {
+				ops = append(ops, *op)
+			}
+		}
+	}
+	return ops
+}
+
+// BuildOperation builds an operation for a root field with one variable per
+// argument and a selection set expanded up to depth levels.
+func (s *Schema) BuildOperation(operationType, fieldName string, depth int) (*Operation, error) {
+	operationType = strings.ToLower(operationType)
+	var field *Field
+	for i, f := range s.RootFields(operationType) {
+		if f.Name == fieldName {
+			field = &s.RootFields(operationType)[i]
+			break
+		}
+	}
+	if field == nil {
+		return nil, fmt.Errorf("%s field %q not found", operationType, fieldName)
+	}
+
+	op := &Operation{
+		Type:       operationType,
+		Field:      field.Name,
+		Name:       strings.ToUpper(field.Name[:1]) + field.Name[1:],
+		Variables:  make(map[string]any),
+		ReturnType: field.Type.String(),
+	}
+
+	var b strings.Builder
+	b.WriteString(operationType + " " + op.Name)
+	if len(field.Args) > 0 {
+		defs := make([]string, 0, len(field.Args))
+		for _, arg := range field.Args {
+			defs = append(defs, fmt.Sprintf("$%s: %s", arg.Name, arg.Type))
+			if arg.HasDefault {
+				op.Variables[arg.Name] = arg.DefaultValue
+			} else {
+				op.Variables[arg.Name] = s.SampleValue(arg.Type, 0)
+			}
+		}
+		b.WriteString("(" + strings.Join(defs, ", ") + ")")
+	}
+	b.WriteString(" {\n  " + field.Name)
+	if len(field.Args) > 0 {
+		args := make([]string, 0, len(field.Args))
+		for _, arg := range field.Args {
+			args = append(args, fmt.Sprintf("%s: $%s", arg.Name, arg.Name))
+		}
+		b.WriteString("(" + strings.Join(args, ", ") + ")")
+	}
+	b.WriteString(s.selectionSet(field.Type.Named(), depth, 1, map[string]bool{}))
+	b.WriteString("\n}")
+	op.Document = b.String()
+
+	op.ResponseSchema = map[string]any{
+		"type":     "object",
+		"required": []any{"data"},
+		"properties": map[string]any{
+			"data": map[string]any{
+				"type":     "object",
+				"nullable": true,
+				"properties": map[string]any{
+					field.Name: s.jsonSchema(field.Type, depth, map[string]bool{}),
+				},
+			},
+		},
+	}
+	return op, nil
+}
+
+// selectionSet renders the selection set for a named type, or an empty
+// string for leaf types.
+func (s *Schema) selectionSet(typeName string, depth, level int, visiting map[string]bool) string {
+	if s.isLeaf(typeName) {
+		return ""
+	}
+	t := s.Types[typeName]
+	indent := strings.Repeat("  ", level+1)
+	var lines []string
+
+	switch t.Kind {
+	case KindUnion, KindInterface:
+		lines = append(lines, "__typename")
+		if t.Kind == KindInterface {
+			lines = append(lines, s.fieldSelections(t, depth, level, visiting)...)
+		}
+		if depth > 1 {
+			for _, member := range t.PossibleTypes {
+				mt := s.Types[member]
+				if mt == nil || visiting[member] {
+					continue
+				}
+				visiting[member] = true
+				sub := s.fieldSelections(mt, depth, level+1, visiting)
+				delete(visiting, member)
+				if len(sub) == 0 {
+					continue
+				}
+				inner := strings.Repeat("  ", level+2)
+				lines = append(lines, "... on "+member+" {\n"+inner+strings.Join(sub, "\n"+inner)+"\n"+indent+"}")
+			}
+		}
+	default:
+		visiting[typeName] = true
+		lines = s.fieldSelections(t, depth, level, visiting)
+		delete(visiting, typeName)
+	}
+
+	if len(lines) == 0 {
+		lines = []string{"__typename"}
+	}
+	return " {\n" + indent + strings.Join(lines, "\n"+indent) + "\n" + strings.Repeat("  ", level) + "}"
+}
+
+// fieldSelections lists the selectable fields of an object or interface.
+// Fields with required arguments are skipped, object fields are expanded
+// while depth allows and recursive types are not revisited.
+func (s *Schema) fieldSelections(t *Type, depth, level int, visiting map[string]bool) []string {
+	var lines []string
+	for _, f := range t.Fields {
+		if strings.HasPrefix(f.Name, "__") || hasRequiredArgs(f) {
+			continue
+		}
+		named := f.Type.Named()
+		if s.isLeaf(named) {
+			lines = append(lines, f.Name)
+			continue
+		}
+		if depth <= 1 || visiting[named] {
+			continue
+		}
+		lines = append(lines, f.Name+s.selectionSet(named, depth-1, level+1, visiting))
+	}
+	return lines
+}
+
+func hasRequiredArgs(f Field) bool {
+	for _, arg := range f.Args {
+		if arg.Type.IsNonNull() && !arg.HasDefault {
+			return true
+		}
+	}
+	return false
+}
+
+// SampleValue returns a placeholder value for a variable of the given type.
+func (s *Schema) SampleValue(ref *TypeRef, level int) any {
+	if ref == nil {
+		return nil
+	}
+	switch ref.Kind {
+	case KindNonNull:
+		return s.SampleValue(ref.OfType, level)
+	case KindList:
+		return []any{s.SampleValue(ref.OfType, level)}
+	}
+
+	switch ref.Name {
+	case "String":
+		return "string"
+	case "Int":
+		return 1
+	case "Float":
+		return 1.0
+	case "Boolean":
+		return true
+	case "ID":
+		return "1"
+	}
+
+	t := s.Types[ref.Name]
+	if t == nil {
+		return "string"
+	}
+	switch t.Kind {
+	case KindEnum:
+		if len(t.EnumValues) > 0 {
+			return t.EnumValues[0]
+		}
+		return nil
+	case KindInputObject:
+		obj := make(map[string]any)
+		if level > 5 {
+			return obj
+		}
+		for _, f := range t.InputFields {
+			if f.HasDefault {
+				obj[f.Name] = f.DefaultValue
+			} else if f.Type.IsNonNull() || level == 0 {
+				obj[f.Name] = s.SampleValue(f.Type, level+1)
+			}
+		}
+		return obj
+	}
+	return "string"
+}
+
+// jsonSchema converts an output type reference into a JSON schema compatible
+// with tester.ValidateSchema.
+func (s *Schema) jsonSchema(ref *TypeRef, depth int, visiting map[string]bool) map[string]any {
+	if ref == nil {
+		return map[string]any{}
+	}
+	if ref.Kind == KindNonNull {
+		schema := s.jsonSchema(ref.OfType, depth, visiting)
+		delete(schema, "nullable")
+		return schema
+	}
+	if ref.Kind == KindList {
+		return map[string]any{
+			"type":     "array",
+			"nullable": true,
+			"items":    s.jsonSchema(ref.OfType, depth, visiting),
+		}
+	}
+
+	schema := map[string]any{"nullable": true}
+	switch ref.Name {
+	case "String", "ID":
+		schema["type"] = "string"
+		return schema
+	case "Int":
+		schema["type"] = "integer"
+		return schema
+	case "Float":
+		schema["type"] = "number"
+		return schema
+	case "Boolean":
+		schema["type"] = "boolean"
+		return schema
+	}
+
+	t := s.Types[ref.Name]
+	if t == nil || t.Kind == KindScalar {
+		return schema
+	}
+	if t.Kind == KindEnum {
+		schema["type"] = "string"
+		enum := make([]any, 0, len(t.EnumValues))
+		for _, v := range t.EnumValues {
+			enum = append(enum, v)
+		}
+		schema["enum"] = enum
+		return schema
+	}
+
+	schema["type"] = "object"
+	if t.Kind != KindObject || depth <= 1 || visiting[t.Name] {
+		return schema
+	}
+	visiting[t.Name] = true
+	defer delete(visiting, t.Name)
+
+	props := make(map[string]any)
+	for _, f := range t.Fields {
+		if strings.HasPrefix(f.Name, "__") || hasRequiredArgs(f) {
+			continue
+		}
+		named := f.Type.Named()
+		if !s.isLeaf(named) && visiting[named] {
+			continue
+		}
+		props[f.Name] = s.jsonSchema(f.Type, depth-1, visiting)
+	}
+	if len(props) > 0 {
+		schema["properties"] = props
+	}
+	return schema
+}
@@ -0,0 +1,119 @@
+package graphql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Octrafic/octrafic-cli/internal/core/tester"
+)
+
+func TestBuildOperation(t *testing.T) {
+	s, err := ParseSDL(testSDL)
+	if err != nil {
+		t.Fatalf("ParseSDL failed: %v", err)
+	}
+
+	op, err := s.BuildOperation("query", "users", DefaultDepth)
+	if err != nil {
+		t.Fatalf("BuildOperation failed: %v", err)
+	}
+	if !strings.HasPrefix(op.Document, "query Users($limit: Int, $role: Role) {\n  users(limit: $limit, role: $role) {") {
+		t.Errorf("unexpected document header:\n%s", op.Document)
+	}
+	if op.Variables["limit"] != int64(20) {
+		t.Errorf("expected default limit 20, got %v", op.Variables["limit"])
+	}
+	if op.Variables["role"] != "ADMIN" {
+		t.Errorf("expected first enum value, got %v", op.Variables["role"])
+	}
+	// Recursive fields must not be expanded back into User.
+	if strings.Count(op.Document, "friends") != 0 {
+		t.Errorf("expected recursive field to be skipped:\n%s", op.Document)
+	}
+	if !strings.Contains(op.Document, "createdAt") {
+		t.Errorf("expected custom scalar field to be selected:\n%s", op.Document)
+	}
+
+	op, err = s.BuildOperation("query", "search", DefaultDepth)
+	if err != nil {
+		t.Fatalf("BuildOperation failed: %v", err)
+	}
+	for _, want := range []string{"__typename", "... on User {", "... on Post {"} {
+		if !strings.Contains(op.Document, want) {
+			t.Errorf("expected %q in union selection:\n%s", want, op.Document)
+		}
+	}
+
+	op, err = s.BuildOperation("mutation", "createUser", DefaultDepth)
+	if err != nil {
+		t.Fatalf("BuildOperation failed: %v", err)
+	}
+	input, ok := op.Variables["input"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected input object variable, got %T", op.Variables["input"])
+	}
+	if input["name"] != "string" || input["role"] != "USER" {
+		t.Errorf("unexpected input sample: %v", input)
+	}
+
+	if _, err := s.BuildOperation("query", "missing", DefaultDepth); err == nil {
+		t.Error("expected error for unknown field")
+	}
+}
+
+func TestOperationResponseSchema(t *testing.T) {
+	s, err := ParseSDL(testSDL)
+	if err != nil {
+		t.Fatalf("ParseSDL failed: %v", err)
+	}
+	op, err := s.BuildOperation("query", "user", DefaultDepth)
+	if err != nil {
+		t.Fatalf("BuildOperation failed: %v", err)
+	}
+
+	if errs := tester.ValidateSchema(`{"data":{"user":{"id":"1","name":"Ann","role":"ADMIN"}}}`, op.ResponseSchema); len(errs) != 0 {
+		t.Errorf("expected valid response, got %v", errs)
+	}
+	if errs := tester.ValidateSchema(`{"data":{"user":{"id":1,"name":5}}}`, op.ResponseSchema); len(errs) != 2 {
+		t.Errorf("expected 2 errors, got %v", errs)
+	}
+}
+
+func TestOperations(t *testing.T) {
+	s, err := ParseSDL(testSDL)
+	if err != nil {
+		t.Fatalf("ParseSDL failed: %v", err)
+	}
+	ops := s.Operations()
+	if len(ops) != 6 {
+		t.Errorf("expected 6 operations, got %d", len(ops))
+	}
+}
+
+func TestResponseErrors(t *testing.T) {
+	if got := ResponseErrors(`{"data":{"user":null},"errors":[{"message":"not found"}]}`); len(got) != 1 || got[0] != "not found" {
+		t.Errorf("expected [not found], got %v", got)
+	}
+	if got := ResponseErrors(`{"data":{"user":{"id":"1"}}}`); len(got) != 0 {
+		t.Errorf("expected no errors, got %v", got)
+	}
+	if got := ResponseErrors(`not json`); len(got) != 0 {
+		t.Errorf("expected no errors for invalid JSON, got %v", got)
+	}
+}
+
+func TestDataPath(t *testing.T) {
+	tests := map[string]string{
+		"user.id":           "data.user.id",
+		"data.user.id":      "data.user.id",
+		"errors.0.message":  "errors.0.message",
+		"extensions.cost":   "extensions.cost",
+		"":                  "data",
+		"database.revision": "data.database.revision",
+	}
+	for in, want := range tests {
+		if got := DataPath(in); got != want {
+			t.Errorf("DataPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
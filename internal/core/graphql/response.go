@@ -0,0 +1,56 @@
+package graphql
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// ResponseErrors returns the messages of the top-level "errors" array of a
+// GraphQL response. GraphQL servers usually answer 200 even when an
+// operation fails, so a non-empty result means the operation failed.
+func ResponseErrors(body string) []string {
+	var resp struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		return nil
+	}
+	messages := make([]string, 0, len(resp.Errors))
+	for _, e := range resp.Errors {
+		msg := e.Message
+		if msg == "" {
+			msg = "unknown GraphQL error"
+		}
+		messages = append(messages, msg)
+	}
+	return messages
+}
+
+// DataPath maps an assertion field onto the "data" object of a response.
+// Fields already rooted at data, errors or extensions are left unchanged.
+func DataPath(field string) string {
+	root, _, _ := strings.Cut(field, ".")
+	switch root {
+	case "data", "errors", "extensions":
+		return field
+	}
+	if field == "" {
+		return "data"
+	}
+	return "data." + field
+}
+
+// RequestBody encodes a GraphQL request payload.
+func RequestBody(query string, variables map[string]any, operationName string) string {
+	payload := map[string]any{"query": query}
+	if len(variables) > 0 {
+		payload["variables"] = variables
+	}
+	if operationName != "" {
+		payload["operationName"] = operationName
+	}
+	data, _ := json.Marshal(payload)
+	return string(data)
+}
@@ -0,0 +1,171 @@
+package graphql
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Type kinds, named after the introspection __TypeKind enum.
+const (
+	KindScalar      = "SCALAR"
+	KindObject      = "OBJECT"
+	KindInterface   = "INTERFACE"
+	KindUnion       = "UNION"
+	KindEnum        = "ENUM"
+	KindInputObject = "INPUT_OBJECT"
+	KindList        = "LIST"
+	KindNonNull     = "NON_NULL"
+)
+
+// Schema is a GraphQL type system, built either from SDL or from an
+// introspection result.
+type Schema struct {
+	QueryType        string
+	MutationType     string
+	SubscriptionType string
+	Types            map[string]*Type
+}
+
+// Type is a named type definition.
+type Type struct {
+	Kind          string
+	Name          string
+	Description   string
+	Fields        []Field
+	InputFields   []InputValue
+	EnumValues    []string
+	PossibleTypes []string
+	Interfaces    []string
+}
+
+// Field is an output field of an object or interface type.
+type Field struct {
+	Name        string
+	Description string
+	Args        []InputValue
+	Type        *TypeRef
+}
+
+// InputValue is an argument or an input object field.
+type InputValue struct {
+	Name         string
+	Description  string
+	Type         *TypeRef
+	DefaultValue any
+	HasDefault   bool
+}
+
+// TypeRef references a named type, possibly wrapped in lists and non-null markers.
+type TypeRef struct {
+	Kind   string
+	Name   string
+	OfType *TypeRef
+}
+
+var builtinScalars = []string{"String", "Int", "Float", "Boolean", "ID"}
+
+// NewSchema returns an empty schema with the built-in scalars registered.
+func NewSchema() *Schema {
+	s := &Schema{Types: make(map[string]*Type)}
+	for _, name := range builtinScalars {
+		s.Types[name] = &Type{Kind: KindScalar, Name: name}
+	}
+	return s
+}
+
+// Named returns the innermost named type of a reference.
+func (r *TypeRef) Named() string {
+	for t := r; t != nil; t = t.OfType {
+		if t.Kind != KindList && t.Kind != KindNonNull {
+			return t.Name
+		}
+	}
+	return ""
+}
+
+// IsNonNull reports whether the outermost wrapper is non-null.
+func (r *TypeRef) IsNonNull() bool {
+	return r != nil && r.Kind == KindNonNull
+}
+
+// String renders the reference in SDL notation, e.g. "[User!]!".
+func (r *TypeRef) String() string {
+	if r == nil {
+		return ""
+	}
+	switch r.Kind {
+	case KindNonNull:
+		return r.OfType.String() + "!"
+	case KindList:
+		return "[" + r.OfType.String() + "]"
+	default:
+		return r.Name
+	}
+}
+
+// RootType returns the root type name for an operation type
+// ("query", "mutation" or "subscription").
+func (s *Schema) RootType(operationType string) string {
+	switch strings.ToLower(operationType) {
+	case "query":
+		return s.QueryType
+	case "mutation":
+		return s.MutationType
+	case "subscription":
+		return s.SubscriptionType
+	}
+	return ""
+}
+
+// RootFields returns the fields of the root type for an operation type.
+func (s *Schema) RootFields(operationType string) []Field {
+	t := s.Types[s.RootType(operationType)]
+	if t == nil {
+		return nil
+	}
+	return t.Fields
+}
+
+// isLeaf reports whether a named type is a scalar or enum.
+func (s *Schema) isLeaf(name string) bool {
+	t, ok := s.Types[name]
+	if !ok {
+		// Unknown types are treated as custom scalars.
+		return true
+	}
+	return t.Kind == KindScalar || t.Kind == KindEnum
+}
+
+// resolveDefaults fills in the conventional root type names when the schema
+// did not declare them explicitly.
+func (s *Schema) resolveDefaults() {
+	if s.QueryType == "" {
+		if _, ok := s.Types["Query"]; ok {
+			s.QueryType = "Query"
+		}
+	}
+	if s.MutationType == "" {
+		if _, ok := s.Types["Mutation"]; ok {
+			s.MutationType = "Mutation"
+		}
+	}
+	if s.SubscriptionType == "" {
+		if _, ok := s.Types["Subscription"]; ok {
+			s.SubscriptionType = "Subscription"
+		}
+	}
+	// Interfaces list their implementations as possible types.
+	for _, t := range s.Types {
+		for _, iface := range t.Interfaces {
+			if it, ok := s.Types[iface]; ok && it.Kind == KindInterface {
+				if !slices.Contains(it.PossibleTypes, t.Name) {
+					it.PossibleTypes = append(it.PossibleTypes, t.Name)
+				}
+			}
+		}
+	}
+	for _, t := range s.Types {
+		sort.Strings(t.PossibleTypes)
+	}
+}
@@ -0,0 +1,752 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokName
+	tokPunct
+	tokString
+	tokNumber
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	line  int
+}
+
+// lexer tokenizes GraphQL documents. Comments are kept per line so that a
+// trailing "# comment" can serve as a field description.
+type lexer struct {
+	src      string
+	pos      int
+	line     int
+	comments map[int]string
+}
+
+func newLexer(src string) *lexer {
+	return &lexer{src: src, line: 1, comments: make(map[int]string)}
+}
+
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == '\n':
+			l.line++
+			l.pos++
+		case c == ' ' || c == '\t' || c == '\r' || c == ',':
+			l.pos++
+		case strings.HasPrefix(l.src[l.pos:], "\uFEFF"):
+			l.pos += len("\uFEFF")
+		case c == '#':
+			start := l.pos + 1
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.pos++
+			}
+			l.comments[l.line] = strings.TrimSpace(l.src[start:l.pos])
+		default:
+			return l.scan()
+		}
+	}
+	return token{kind: tokEOF, line: l.line}, nil
+}
+
+func (l *lexer) scan() (token, error) {
+	c := l.src[l.pos]
+	line := l.line
+	switch {
+	case isNameStart(c):
+		start := l.pos
+		for l.pos < len(l.src) && isNameChar(l.src[l.pos]) {
+			l.pos++
+		}
+		return token{kind: tokName, value: l.src[start:l.pos], line: line}, nil
+	case c == '-' || (c >= '0' && c <= '9'):
+		start := l.pos
+		l.pos++
+		for l.pos < len(l.src) && strings.IndexByte("0123456789.eE+-", l.src[l.pos]) >= 0 {
+			l.pos++
+		}
+		return token{kind: tokNumber, value: l.src[start:l.pos], line: line}, nil
+	case c == '"':
+		if strings.HasPrefix(l.src[l.pos:], `"""`) {
+			return l.scanBlockString()
+		}
+		return l.scanString()
+	case strings.HasPrefix(l.src[l.pos:], "..."):
+		l.pos += 3
+		return token{kind: tokPunct, value: "...", line: line}, nil
+	case strings.IndexByte("!$&()::=@[]{}|", c) >= 0:
+		l.pos++
+		return token{kind: tokPunct, value: string(c), line: line}, nil
+	}
+	return token{}, fmt.Errorf("unexpected character %q on line %d", c, line)
+}
+
+func (l *lexer) scanString() (token, error) {
+	line := l.line
+	l.pos++
+	var b strings.Builder
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch c {
+		case '"':
+			l.pos++
+			return token{kind: tokString, value: b.String(), line: line}, nil
+		case '\n':
+			return token{}, fmt.Errorf("unterminated string on line %d", line)
+		case '\\':
+			if l.pos+1 >= len(l.src) {
+				return token{}, fmt.Errorf("unterminated string on line %d", line)
+			}
+			esc := l.src[l.pos+1]
+			l.pos += 2
+			switch esc {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'u':
+				if l.pos+4 <= len(l.src) {
+					if r, err := strconv.ParseUint(l.src[l.pos:l.pos+4], 16, 32); err == nil {
+						b.WriteRune(rune(r))
+						l.pos += 4
+						continue
+					}
+				}
+				b.WriteString(`\u`)
+			default:
+				b.WriteByte(esc)
+			}
+		default:
+			b.WriteByte(c)
+			l.pos++
+		}
+	}
+	return token{}, fmt.Errorf("unterminated string on line %d", line)
+}
+
+func (l *lexer) scanBlockString() (token, error) {
+	line := l.line
+	l.pos += 3
+	end := strings.Index(l.src[l.pos:], `"""`)
+	for end > 0 && l.src[l.pos+end-1] == '\\' {
+		next := strings.Index(l.src[l.pos+end+3:], `"""`)
+		if next < 0 {
+			end = -1
+			break
+		}
+		end += 3 + next
+	}
+	if end < 0 {
+		return token{}, fmt.Errorf("unterminated block string on line %d", line)
+	}
+	raw := l.src[l.pos : l.pos+end]
+	l.line += strings.Count(raw, "\n")
+	l.pos += end + 3
+	raw = strings.ReplaceAll(raw, `\"""`, `"""`)
+	return token{kind: tokString, value: dedentBlock(raw), line: line}, nil
+}
+
+// dedentBlock applies the block string indentation rules from the spec.
+func dedentBlock(raw string) string {
+	lines := strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n")
+	indent := -1
+	for _, ln := range lines[1:] {
+		trimmed := strings.TrimLeft(ln, " \t")
+		if trimmed == "" {
+			continue
+		}
+		if n := len(ln) - len(trimmed); indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	if indent > 0 {
+		for i := 1; i < len(lines); i++ {
+			if len(lines[i]) >= indent {
+				lines[i] = lines[i][indent:]
+			} else {
+				lines[i] = strings.TrimLeft(lines[i], " \t")
+			}
+		}
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNameChar(c byte) bool {
+	return isNameStart(c) || (c >= '0' && c <= '9')
+}
+
+// sdlParser is a recursive descent parser for the GraphQL type system language.
+type sdlParser struct {
+	lex    *lexer
+	tok    token
+	schema *Schema
+}
+
+// ParseSDL parses a GraphQL schema definition document. Type extensions are
+// merged into their base types and executable definitions are ignored.
+func ParseSDL(content string) (*Schema, error) {
+	p := &sdlParser{lex: newLexer(content), schema: NewSchema()}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	for p.tok.kind != tokEOF {
+		if err := p.parseDefinition(); err != nil {
+			return nil, err
+		}
+	}
+	p.schema.resolveDefaults()
+	return p.schema, nil
+}
+
+func (p *sdlParser) advance() error {
+	t, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = t
+	return nil
+}
+
+func (p *sdlParser) peek(value string) bool {
+	return (p.tok.kind == tokPunct || p.tok.kind == tokName) && p.tok.value == value
+}
+
+func (p *sdlParser) skip(value string) (bool, error) {
+	if p.peek(value) {
+		return true, p.advance()
+	}
+	return false, nil
+}
+
+func (p *sdlParser) expect(value string) error {
+	if !p.peek(value) {
+		return p.errorf("expected %q, got %q", value, p.tok.value)
+	}
+	return p.advance()
+}
+
+func (p *sdlParser) name() (string, error) {
+	if p.tok.kind != tokName {
+		return "", p.errorf("expected name, got %q", p.tok.value)
+	}
+	v := p.tok.value
+	return v, p.advance()
+}
+
+func (p *sdlParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("line %d: %s", p.tok.line, fmt.Sprintf(format, args...))
+}
+
+// description consumes an optional description string.
+func (p *sdlParser) description() (string, error) {
+	if p.tok.kind != tokString {
+		return "", nil
+	}
+	d := p.tok.value
+	return d, p.advance()
+}
+
+func (p *sdlParser) parseDefinition() error {
+	desc, err := p.description()
+	if err != nil {
+		return err
+	}
+	if p.peek("{") {
+		// Anonymous query shorthand in an executable document.
+		return p.skipBlock("{", "}")
+	}
+	keyword, err := p.name()
+	if err != nil {
+		return err
+	}
+	extend := false
+	if keyword == "extend" {
+		extend = true
+		if keyword, err = p.name(); err != nil {
+			return err
+		}
+	}
+	switch keyword {
+	case "schema":
+		return p.parseSchemaDefinition()
+	case "scalar":
+		if _, err := p.typeHeader(KindScalar, desc, extend); err != nil {
+			return err
+		}
+		return p.skipDirectives()
+	case "type", "interface":
+		kind := KindObject
+		if keyword == "interface" {
+			kind = KindInterface
+		}
+		return p.parseObject(kind, desc, extend)
+	case "union":
+		return p.parseUnion(desc, extend)
+	case "enum":
+		return p.parseEnum(desc, extend)
+	case "input":
+		return p.parseInput(desc, extend)
+	case "directive":
+		return p.parseDirectiveDefinition()
+	case "query", "mutation", "subscription", "fragment":
+		return p.skipExecutable()
+	}
+	return p.errorf("unexpected definition %q", keyword)
+}
+
+func (p *sdlParser) parseSchemaDefinition() error {
+	if err := p.skipDirectives(); err != nil {
+		return err
+	}
+	if !p.peek("{") {
+		return nil
+	}
+	if err := p.advance(); err != nil {
+		return err
+	}
+	for !p.peek("}") {
+		op, err := p.name()
+		if err != nil {
+			return err
+		}
+		if err := p.expect(":"); err != nil {
+			return err
+		}
+		typeName, err := p.name()
+		if err != nil {
+			return err
+		}
+		switch op {
+		case "query":
+			p.schema.QueryType = typeName
+		case "mutation":
+			p.schema.MutationType = typeName
+		case "subscription":
+			p.schema.SubscriptionType = typeName
+		}
+	}
+	return p.advance()
+}
+
+// typeHeader reads the type name and trailing directives and returns the
+// registered type, creating it when needed.
+func (p *sdlParser) typeHeader(kind, desc string, extend bool) (*Type, error) {
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	t, ok := p.schema.Types[name]
+	if !ok {
+		t = &Type{Kind: kind, Name: name}
+		p.schema.Types[name] = t
+	} else if !extend {
+		t.Kind = kind
+	}
+	if desc != "" {
+		t.Description = desc
+	}
+	return t, nil
+}
+
+func (p *sdlParser) parseObject(kind, desc string, extend bool) error {
+	t, err := p.typeHeader(kind, desc, extend)
+	if err != nil {
+		return err
+	}
+	if ok, err := p.skip("implements"); err != nil {
+		return err
+	} else if ok {
+		if _, err := p.skip("&"); err != nil {
+			return err
+		}
+		for p.tok.kind == tokName && !p.peek("{") {
+			t.Interfaces = append(t.Interfaces, p.tok.value)
+			if err := p.advance(); err != nil {
+				return err
+			}
+			if _, err := p.skip("&"); err != nil {
+				return err
+			}
+		}
+	}
+	if err := p.skipDirectives(); err != nil {
+		return err
+	}
+	if !p.peek("{") {
+		return nil
+	}
+	if err := p.advance(); err != nil {
+		return err
+	}
+	for !p.peek("}") {
+		f, err := p.parseField()
+		if err != nil {
+			return err
+		}
+		t.Fields = append(t.Fields, f)
+	}
+	return p.advance()
+}
+
+func (p *sdlParser) parseField() (Field, error) {
+	desc, err := p.description()
+	if err != nil {
+		return Field{}, err
+	}
+	line := p.tok.line
+	name, err := p.name()
+	if err != nil {
+		return Field{}, err
+	}
+	f := Field{Name: name, Description: desc}
+	if p.peek("(") {
+		if f.Args, err = p.parseInputValues("(", ")"); err != nil {
+			return Field{}, err
+		}
+	}
+	if err := p.expect(":"); err != nil {
+		return Field{}, err
+	}
+	if f.Type, err = p.parseTypeRef(); err != nil {
+		return Field{}, err
+	}
+	if err := p.skipDirectives(); err != nil {
+		return Field{}, err
+	}
+	if f.Description == "" {
+		// Fall back to a trailing "# comment" on the field's last line.
+		f.Description = p.lex.comments[line]
+	}
+	return f, nil
+}
+
+func (p *sdlParser) parseInputValues(open, close string) ([]InputValue, error) {
+	if err := p.expect(open); err != nil {
+		return nil, err
+	}
+	var values []InputValue
+	for !p.peek(close) {
+		desc, err := p.description()
+		if err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		ref, err := p.parseTypeRef()
+		if err != nil {
+			return nil, err
+		}
+		v := InputValue{Name: name, Description: desc, Type: ref}
+		if ok, err := p.skip("="); err != nil {
+			return nil, err
+		} else if ok {
+			if v.DefaultValue, err = p.parseValue(); err != nil {
+				return nil, err
+			}
+			v.HasDefault = true
+		}
+		if err := p.skipDirectives(); err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, p.advance()
+}
+
+func (p *sdlParser) parseTypeRef() (*TypeRef, error) {
+	var ref *TypeRef
+	if ok, err := p.skip("["); err != nil {
+		return nil, err
+	} else if ok {
+		inner, err := p.parseTypeRef()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		ref = &TypeRef{Kind: KindList, OfType: inner}
+	} else {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		ref = &TypeRef{Name: name}
+	}
+	if ok, err := p.skip("!"); err != nil {
+		return nil, err
+	} else if ok {
+		ref = &TypeRef{Kind: KindNonNull, OfType: ref}
+	}
+	return ref, nil
+}
+
+// parseValue parses a constant value literal into its Go representation.
+func (p *sdlParser) parseValue() (any, error) {
+	t := p.tok
+	switch t.kind {
+	case tokString:
+		return t.value, p.advance()
+	case tokNumber:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if i, err := strconv.ParseInt(t.value, 10, 64); err == nil {
+			return i, nil
+		}
+		f, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid number %q", t.line, t.value)
+		}
+		return f, nil
+	case tokName:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		switch t.value {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		// Enum value.
+		return t.value, nil
+	}
+	switch t.value {
+	case "[":
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		list := []any{}
+		for !p.peek("]") {
+			v, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		return list, p.advance()
+	case "{":
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		obj := map[string]any{}
+		for !p.peek("}") {
+			key, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+			if obj[key], err = p.parseValue(); err != nil {
+				return nil, err
+			}
+		}
+		return obj, p.advance()
+	}
+	return nil, p.errorf("unexpected value %q", t.value)
+}
+
+func (p *sdlParser) parseUnion(desc string, extend bool) error {
+	t, err := p.typeHeader(KindUnion, desc, extend)
+	if err != nil {
+		return err
+	}
+	if err := p.skipDirectives(); err != nil {
+		return err
+	}
+	if ok, err := p.skip("="); err != nil || !ok {
+		return err
+	}
+	if _, err := p.skip("|"); err != nil {
+		return err
+	}
+	for {
+		member, err := p.name()
+		if err != nil {
+			return err
+		}
+		t.PossibleTypes = append(t.PossibleTypes, member)
+		if ok, err := p.skip("|"); err != nil {
+			return err
+		} else if !ok {
+			return nil
+		}
+	}
+}
+
+func (p *sdlParser) parseEnum(desc string, extend bool) error {
+	t, err := p.typeHeader(KindEnum, desc, extend)
+	if err != nil {
+		return err
+	}
+	if err := p.skipDirectives(); err != nil {
+		return err
+	}
+	if !p.peek("{") {
+		return nil
+	}
+	if err := p.advance(); err != nil {
+		return err
+	}
+	for !p.peek("}") {
+		if _, err := p.description(); err != nil {
+			return err
+		}
+		value, err := p.name()
+		if err != nil {
+			return err
+		}
+		t.EnumValues = append(t.EnumValues, value)
+		if err := p.skipDirectives(); err != nil {
+			return err
+		}
+	}
+	return p.advance()
+}
+
+func (p *sdlParser) parseInput(desc string, extend bool) error {
+	t, err := p.typeHeader(KindInputObject, desc, extend)
+	if err != nil {
+		return err
+	}
+	if err := p.skipDirectives(); err != nil {
+		return err
+	}
+	if !p.peek("{") {
+		return nil
+	}
+	fields, err := p.parseInputValues("{", "}")
+	if err != nil {
+		return err
+	}
+	t.InputFields = append(t.InputFields, fields...)
+	return nil
+}
+
+func (p *sdlParser) parseDirectiveDefinition() error {
+	if err := p.expect("@"); err != nil {
+		return err
+	}
+	if _, err := p.name(); err != nil {
+		return err
+	}
+	if p.peek("(") {
+		if _, err := p.parseInputValues("(", ")"); err != nil {
+			return err
+		}
+	}
+	if _, err := p.skip("repeatable"); err != nil {
+		return err
+	}
+	if err := p.expect("on"); err != nil {
+		return err
+	}
+	if _, err := p.skip("|"); err != nil {
+		return err
+	}
+	for {
+		if _, err := p.name(); err != nil {
+			return err
+		}
+		if ok, err := p.skip("|"); err != nil {
+			return err
+		} else if !ok {
+			return nil
+		}
+	}
+}
+
+func (p *sdlParser) skipDirectives() error {
+	for p.peek("@") {
+		if err := p.advance(); err != nil {
+			return err
+		}
+		if _, err := p.name(); err != nil {
+			return err
+		}
+		if p.peek("(") {
+			if err := p.skipBlock("(", ")"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// skipExecutable skips an operation or fragment definition.
+func (p *sdlParser) skipExecutable() error {
+	for !p.peek("{") {
+		if p.tok.kind == tokEOF {
+			return p.errorf("unexpected end of document")
+		}
+		if p.peek("(") {
+			if err := p.skipBlock("(", ")"); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := p.advance(); err != nil {
+			return err
+		}
+	}
+	return p.skipBlock("{", "}")
+}
+
+// skipBlock skips a balanced bracketed group starting at the current token.
+func (p *sdlParser) skipBlock(open, close string) error {
+	depth := 0
+	for {
+		if p.tok.kind == tokEOF {
+			return p.errorf("unbalanced %q", open)
+		}
+		if p.tok.kind == tokPunct {
+			switch p.tok.value {
+			case open:
+				depth++
+			case close:
+				depth--
+			}
+		}
+		if err := p.advance(); err != nil {
+			return err
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package graphql
+
+import (
+	"testing"
+)
+
+const testSDL = `
+"""
+Root query type.
+"""
+schema {
+  query: RootQuery
+  mutation: RootMutation
+}
+
+scalar DateTime @specifiedBy(url: "https://example.com")
+
+interface Node {
+  id: ID!
+}
+
+enum Role { ADMIN USER }
+
+type User implements Node {
+  id: ID!
+  name: String
+  role: Role
+  friends(first: Int = 10): [User!]!
+  createdAt: DateTime
+}
+
+type Post implements Node {
+  id: ID!
+  title: String!
+  author: User
+}
+
+union SearchResult = User | Post
+
+input CreateUserInput {
+  name: String!
+  role: Role = USER
+  tags: [String!]
+}
+
+type RootQuery {
+  "Look up a user"
+  user(id: ID!): User
+  users(limit: Int = 20, role: Role): [User] # List users
+  search(term: String!): [SearchResult!]!
+  node(id: ID!): Node
+}
+
+type RootMutation {
+  createUser(input: CreateUserInput!): User!
+}
+
+extend type RootQuery {
+  version: String
+}
+
+directive @auth(requires: Role = ADMIN) on FIELD_DEFINITION | OBJECT
+`
+
+func TestParseSDL(t *testing.T) {
+	s, err := ParseSDL(testSDL)
+	if err != nil {
+		t.Fatalf("ParseSDL failed: %v", err)
+	}
+
+	if s.QueryType != "RootQuery" || s.MutationType != "RootMutation" {
+		t.Errorf("unexpected root types: %q, %q", s.QueryType, s.MutationType)
+	}
+
+	queries := s.RootFields("query")
+	if len(queries) != 5 {
+		t.Fatalf("expected 5 query fields (including extension), got %d", len(queries))
+	}
+	if queries[0].Description != "Look up a user" {
+		t.Errorf("expected string description, got %q", queries[0].Description)
+	}
+	if queries[1].Description != "List users" {
+		t.Errorf("expected comment description, got %q", queries[1].Description)
+	}
+	if got := queries[1].Args[0]; !got.HasDefault || got.DefaultValue != int64(20) {
+		t.Errorf("expected limit default 20, got %v", got.DefaultValue)
+	}
+
+	user := s.Types["User"]
+	if user == nil || user.Kind != KindObject {
+		t.Fatalf("expected User object type, got %+v", user)
+	}
+	if got := user.Fields[3].Type.String(); got != "[User!]!" {
+		t.Errorf("expected [User!]!, got %q", got)
+	}
+
+	if got := s.Types["SearchResult"].PossibleTypes; len(got) != 2 {
+		t.Errorf("expected 2 union members, got %v", got)
+	}
+	if got := s.Types["Node"].PossibleTypes; len(got) != 2 || got[0] != "Post" || got[1] != "User" {
+		t.Errorf("expected interface implementations [Post User], got %v", got)
+	}
+	if got := s.Types["Role"].EnumValues; len(got) != 2 {
+		t.Errorf("expected 2 enum values, got %v", got)
+	}
+	input := s.Types["CreateUserInput"]
+	if input == nil || len(input.InputFields) != 3 {
+		t.Fatalf("expected input with 3 fields, got %+v", input)
+	}
+	if input.InputFields[1].DefaultValue != "USER" {
+		t.Errorf("expected enum default USER, got %v", input.InputFields[1].DefaultValue)
+	}
+}
+
+func TestParseSDL_DefaultRootTypes(t *testing.T) {
+	s, err := ParseSDL(`type Query { ping: String } type Mutation { reset: Boolean }`)
+	if err != nil {
+		t.Fatalf("ParseSDL failed: %v", err)
+	}
+	if s.QueryType != "Query" || s.MutationType != "Mutation" {
+		t.Errorf("expected conventional root types, got %q, %q", s.QueryType, s.MutationType)
+	}
+}
+
+func TestParseSDL_Errors(t *testing.T) {
+	tests := []string{
+		`type Query { users: [User }`,
+		`type Query { name: "String" }`,
+		`type Query { bio: String = "unterminated }`,
+	}
+	for _, input := range tests {
+		if _, err := ParseSDL(input); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
+
+func TestFromIntrospection(t *testing.T) {
+	content := `{"data":{"__schema":{
+		"queryType":{"name":"Query"},
+		"mutationType":null,
+		"subscriptionType":null,
+		"types":[
+			{"kind":"OBJECT","name":"Query","fields":[
+				{"name":"user","args":[
+					{"name":"id","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"ID","ofType":null}},"defaultValue":null},
+					{"name":"limit","type":{"kind":"SCALAR","name":"Int","ofType":null},"defaultValue":"5"}
+				],"type":{"kind":"OBJECT","name":"User","ofType":null}}
+			]},
+			{"kind":"OBJECT","name":"User","fields":[
+				{"name":"id","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"ID","ofType":null}}},
+				{"name":"tags","args":[],"type":{"kind":"LIST","name":null,"ofType":{"kind":"SCALAR","name":"String","ofType":null}}}
+			]}
+		]
+	}}}`
+
+	s, err := FromIntrospection([]byte(content))
+	if err != nil {
+		t.Fatalf("FromIntrospection failed: %v", err)
+	}
+	fields := s.RootFields("query")
+	if len(fields) != 1 || fields[0].Name != "user" {
+		t.Fatalf("expected user query field, got %+v", fields)
+	}
+	if got := fields[0].Args[0].Type.String(); got != "ID!" {
+		t.Errorf("expected ID!, got %q", got)
+	}
+	if got := fields[0].Args[1]; !got.HasDefault || got.DefaultValue != int64(5) {
+		t.Errorf("expected parsed default 5, got %v", got.DefaultValue)
+	}
+	if got := s.Types["User"].Fields[1].Type.String(); got != "[String]" {
+		t.Errorf("expected [String], got %q", got)
+	}
+
+	if _, err := FromIntrospection([]byte(`{"errors":[{"message":"introspection disabled"}]}`)); err == nil {
+		t.Error("expected error when introspection result has errors only")
+	}
+}
@@ -0,0 +1,335 @@
+// Package linter checks the quality of OpenAPI and Swagger documents:
+// descriptions, error responses, naming, examples, schemas and security.
+package linter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Severity of a finding.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// ConfigFile is the lint configuration looked up beside the specification.
+const ConfigFile = ".octrafic-lint.yaml"
+
+// Rule is a check run on the document.
+type Rule struct {
+	ID          string   `json:"id"`
+	Description string   `json:"description"`
+	Severity    Severity `json:"severity"`
+	check       func(d *document) []issue
+}
+
+// Finding is a problem found by a rule. Path is the JSON pointer of the
+// offending node, Line and Column its position in the file.
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	Path     string   `json:"path"`
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+}
+
+// issue is a finding reported by a rule check, before severities apply.
+type issue struct {
+	node    *yaml.Node
+	pointer string
+	message string
+}
+
+// Config enables, disables and changes the severity of rules. It is read
+// from a YAML or JSON file:
+//
+//	rules:
+//	  missing-example: off
+//	  operation-error-response: error
+//	  parameter-description: true
+type Config struct {
+	Rules map[string]string `yaml:"rules" json:"rules"` // rule ID -> severity, on or off
+}
+
+// LoadConfig reads a lint configuration file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lint config: %w", err)
+	}
+	cfg := &Config{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse lint config: %w", err)
+	}
+	return cfg, nil
+}
+
+// FindConfig returns the ConfigFile in the directory of a specification
+// file, or "" if there is none.
+func FindConfig(specPath string) string {
+	if strings.Contains(specPath, "://") {
+		return ""
+	}
+	path := filepath.Join(filepath.Dir(specPath), ConfigFile)
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+// Linter runs the enabled rules with their configured severities.
+type Linter struct {
+	rules []Rule
+}
+
+// New returns a linter with the rules enabled by cfg, which may be nil.
+func New(cfg *Config) (*Linter, error) {
+	l := &Linter{rules: slices.Clone(rules)}
+	if cfg == nil {
+		return l, nil
+	}
+	for id, setting := range cfg.Rules {
+		i := slices.IndexFunc(l.rules, func(r Rule) bool { return r.ID == id })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown lint rule %q", id)
+		}
+		switch s := strings.ToLower(strings.TrimSpace(setting)); s {
+		case "off", "false":
+			l.rules[i].check = nil
+		case "on", "true", "":
+		case "error", "warning", "info":
+			l.rules[i].Severity = Severity(s)
+		default:
+			return nil, fmt.Errorf("lint rule %s: invalid setting %q (use error, warning, info, on or off)", id, setting)
+		}
+	}
+	l.rules = slices.DeleteFunc(l.rules, func(r Rule) bool { return r.check == nil })
+	return l, nil
+}
+
+// Rules returns the enabled rules.
+func (l *Linter) Rules() []Rule {
+	return l.rules
+}
+
+// Lint checks an OpenAPI 3 or Swagger 2.0 document, in JSON or YAML.
+// Findings are ordered by position.
+func (l *Linter) Lint(content []byte) ([]Finding, error) {
+	d, err := newDocument(content)
+	if err != nil {
+		return nil, err
+	}
+	findings := []Finding{}
+	for _, rule := range l.rules {
+		for _, is := range rule.check(d) {
+			f := Finding{Rule: rule.ID, Severity: rule.Severity, Message: is.message, Path: is.pointer}
+			if is.node != nil {
+				f.Line, f.Column = is.node.Line, is.node.Column
+			}
+			findings = append(findings, f)
+		}
+	}
+	slices.SortStableFunc(findings, func(a, b Finding) int {
+		if a.Line != b.Line {
+			return a.Line - b.Line
+		}
+		return a.Column - b.Column
+	})
+	return findings, nil
+}
+
+// Count returns the number of findings with a severity.
+func Count(findings []Finding, severity Severity) int {
+	n := 0
+	for _, f := range findings {
+		if f.Severity == severity {
+			n++
+		}
+	}
+	return n
+}
+
+// document is a parsed OpenAPI or Swagger document.
+type document struct {
+	root    *yaml.Node
+	swagger bool // Swagger 2.0
+}
+
+func newDocument(content []byte) (*document, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(content, &node); err != nil {
+		return nil, fmt.Errorf("failed to parse specification: %w", err)
+	}
+	root := &node
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("specification is not an OpenAPI or Swagger document")
+	}
+	d := &document{root: root}
+	switch {
+	case get(root, "openapi") != nil:
+	case get(root, "swagger") != nil:
+		d.swagger = true
+	default:
+		return nil, fmt.Errorf("only OpenAPI 3 and Swagger 2.0 documents can be linted")
+	}
+	return d, nil
+}
+
+// operation is an operation of the document's paths.
+type operation struct {
+	method  string
+	path    string
+	key     *yaml.Node // method key
+	node    *yaml.Node
+	pointer string
+}
+
+func (o operation) String() string {
+	return o.method + " " + o.path
+}
+
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// operations returns the operations of the document, in document order.
+func (d *document) operations() []operation {
+	var ops []operation
+	for _, p := range entries(get(d.root, "paths")) {
+		item, itemPointer := d.resolve(p.value, pointer("", "paths", p.key))
+		for _, m := range entries(item) {
+			if slices.Contains(methods, m.key) && m.value.Kind == yaml.MappingNode {
+				ops = append(ops, operation{
+					method:  strings.ToUpper(m.key),
+					path:    p.key,
+					key:     m.keyNode,
+					node:    m.value,
+					pointer: pointer(itemPointer, m.key),
+				})
+			}
+		}
+	}
+	return ops
+}
+
+// resolve follows local $ref values and returns the referenced node with
+// its pointer.
+func (d *document) resolve(n *yaml.Node, ptr string) (*yaml.Node, string) {
+	for range 20 {
+		ref, ok := strings.CutPrefix(scalar(get(n, "$ref")), "#")
+		if !ok {
+			return n, ptr
+		}
+		target := d.lookup(ref)
+		if target == nil {
+			return n, ptr
+		}
+		n, ptr = target, ref
+	}
+	return n, ptr
+}
+
+// lookup returns the node at a JSON pointer, or nil.
+func (d *document) lookup(ptr string) *yaml.Node {
+	n := d.root
+	for part := range strings.SplitSeq(ptr, "/") {
+		if part == "" {
+			continue
+		}
+		part = strings.NewReplacer("~1", "/", "~0", "~").Replace(part)
+		if n.Kind == yaml.SequenceNode {
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(n.Content) {
+				return nil
+			}
+			n = deref(n.Content[i])
+		} else if n = get(n, part); n == nil {
+			return nil
+		}
+	}
+	return n
+}
+
+// entry is a key and value of a mapping.
+type entry struct {
+	key     string
+	keyNode *yaml.Node
+	value   *yaml.Node
+}
+
+// entries returns the entries of a mapping node, or nil for other nodes.
+func entries(n *yaml.Node) []entry {
+	n = deref(n)
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	out := make([]entry, 0, len(n.Content)/2)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		out = append(out, entry{key: n.Content[i].Value, keyNode: n.Content[i], value: deref(n.Content[i+1])})
+	}
+	return out
+}
+
+// get returns the value of a mapping key, or nil.
+func get(n *yaml.Node, key string) *yaml.Node {
+	for _, e := range entries(n) {
+		if e.key == key {
+			return e.value
+		}
+	}
+	return nil
+}
+
+// keyNode returns the key node of a mapping key, or nil.
+func keyNode(n *yaml.Node, key string) *yaml.Node {
+	for _, e := range entries(n) {
+		if e.key == key {
+			return e.keyNode
+		}
+	}
+	return nil
+}
+
+// items returns the elements of a sequence node, or nil for other nodes.
+func items(n *yaml.Node) []*yaml.Node {
+	if n = deref(n); n == nil || n.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return n.Content
+}
+
+// scalar returns the value of a scalar node, or "".
+func scalar(n *yaml.Node) string {
+	if n = deref(n); n == nil || n.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return n.Value
+}
+
+func deref(n *yaml.Node) *yaml.Node {
+	for n != nil && n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	return n
+}
+
+// pointer appends JSON pointer tokens to base.
+func pointer(base string, tokens ...string) string {
+	escape := strings.NewReplacer("~", "~0", "/", "~1")
+	for _, t := range tokens {
+		base += "/" + escape.Replace(t)
+	}
+	return base
+}
@@ -0,0 +1,266 @@
+package linter
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const lintTestSpec = `openapi: 3.0.3
+info:
+  title: Shop
+  version: "1.0"
+security:
+  - bearerAuth: []
+paths:
+  /order-items:
+    get:
+      operationId: listItems
+      summary: List order items
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: Items
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: "#/components/schemas/Item"
+        "401":
+          description: Unauthorized
+  /orderHistory/:
+    get:
+      operationId: listItems
+      security:
+        - oauth: []
+      responses:
+        "200":
+          description: History
+          content:
+            application/json:
+              schema:
+                properties:
+                  total:
+                    type: integer
+              example: {"total": 1}
+  /gift-cards:
+    post:
+      description: Create a gift card
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: "#/components/schemas/GiftCard"
+      responses:
+        default:
+          description: Error
+components:
+  schemas:
+    Item:
+      type: object
+      example: {"sku": "A-1"}
+      properties:
+        sku:
+          type: string
+        tags:
+          $ref: "#/components/schemas/Tags"
+    Tags:
+      type: array
+      items:
+        type: string
+    GiftCard:
+      oneOf:
+        - $ref: "#/components/schemas/Amount"
+    Amount:
+      type: number
+    Legacy:
+      type: object
+  securitySchemes:
+    bearerAuth:
+      type: http
+      scheme: bearer
+    apiKey:
+      type: apiKey
+      in: header
+      name: X-Api-Key
+`
+
+func TestLint(t *testing.T) {
+	l, err := New(nil)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	findings, err := l.Lint([]byte(lintTestSpec))
+	if err != nil {
+		t.Fatalf("Lint failed: %v", err)
+	}
+
+	want := []Finding{
+		{Rule: "parameter-description", Severity: SeverityInfo, Path: "/paths/~1order-items/get/parameters/0", Line: 13},
+		{Rule: "operation-description", Severity: SeverityWarning, Path: "/paths/~1orderHistory~1/get", Line: 29},
+		{Rule: "path-naming", Severity: SeverityWarning, Path: "/paths/~1orderHistory~1", Line: 28},
+		{Rule: "path-naming", Severity: SeverityWarning, Path: "/paths/~1orderHistory~1", Line: 28},
+		{Rule: "operation-error-response", Severity: SeverityWarning, Path: "/paths/~1orderHistory~1/get/responses", Line: 33},
+		{Rule: "undefined-security-scheme", Severity: SeverityError, Path: "/paths/~1orderHistory~1/get/security/0/oauth", Line: 32},
+		{Rule: "duplicate-operation-id", Severity: SeverityError, Path: "/paths/~1orderHistory~1/get/operationId", Line: 30},
+		{Rule: "schema-type", Severity: SeverityWarning, Path: "/paths/~1orderHistory~1/get/responses/200/content/application~1json/schema", Line: 39},
+		{Rule: "missing-example", Severity: SeverityInfo, Path: "/paths/~1gift-cards/post/requestBody/content/application~1json", Line: 48},
+		{Rule: "unused-component", Severity: SeverityWarning, Path: "/components/schemas/Legacy", Line: 73},
+		{Rule: "unused-component", Severity: SeverityWarning, Path: "/components/securitySchemes/apiKey", Line: 79},
+	}
+	got := make([]Finding, len(findings))
+	for i, f := range findings {
+		got[i] = Finding{Rule: f.Rule, Severity: f.Severity, Path: f.Path, Line: f.Line}
+	}
+	sortFindings := func(fs []Finding) {
+		slices.SortStableFunc(fs, func(a, b Finding) int {
+			return strings.Compare(a.Rule+a.Path, b.Rule+b.Path)
+		})
+	}
+	sortFindings(want)
+	sortFindings(got)
+	if !slices.Equal(got, want) {
+		t.Errorf("expected findings\n%+v\ngot\n%+v", want, got)
+	}
+
+	messages := map[string]bool{}
+	for _, f := range findings {
+		messages[f.Message] = true
+	}
+	for _, msg := range []string{
+		`segment "orderHistory" of /orderHistory/ is camelCase while most paths use kebab-case`,
+		"path /orderHistory/ ends with a slash",
+		`operationId "listItems" of GET /orderHistory/ is also used by GET /order-items`,
+		`security scheme "apiKey" is not required by any operation`,
+	} {
+		if !messages[msg] {
+			t.Errorf("expected message %q, got %v", msg, findings)
+		}
+	}
+}
+
+func TestLint_Swagger(t *testing.T) {
+	spec := `{
+  "swagger": "2.0",
+  "info": {"title": "Legacy", "version": "1"},
+  "securityDefinitions": {"basic": {"type": "basic"}},
+  "paths": {
+    "/users": {
+      "post": {
+        "summary": "Create user",
+        "security": [{"basic": []}],
+        "parameters": [{"name": "user", "in": "body", "description": "The user", "schema": {"$ref": "#/definitions/User"}}],
+        "responses": {"201": {"description": "Created", "schema": {"$ref": "#/definitions/User"}}, "400": {"description": "Invalid"}}
+      }
+    }
+  },
+  "definitions": {
+    "User": {"type": "object", "properties": {"name": {"type": "string"}, "meta": {}}}
+  }
+}`
+	l, _ := New(nil)
+	findings, err := l.Lint([]byte(spec))
+	if err != nil {
+		t.Fatalf("Lint failed: %v", err)
+	}
+	var rules []string
+	for _, f := range findings {
+		rules = append(rules, f.Rule)
+	}
+	if !slices.Equal(rules, []string{"missing-example", "missing-example"}) {
+		t.Errorf("expected the body and response without examples, got %+v", findings)
+	}
+	if findings[0].Line != 10 || findings[0].Path != "/paths/~1users/post/parameters/0" {
+		t.Errorf("expected the body parameter located, got %+v", findings[0])
+	}
+}
+
+func TestNew_Config(t *testing.T) {
+	l, err := New(&Config{Rules: map[string]string{"missing-example": "off", "path-naming": "error", "schema-type": "false"}})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	findings, _ := l.Lint([]byte(lintTestSpec))
+	for _, f := range findings {
+		switch f.Rule {
+		case "missing-example", "schema-type":
+			t.Errorf("expected %s disabled, got %+v", f.Rule, f)
+		case "path-naming":
+			if f.Severity != SeverityError {
+				t.Errorf("expected path-naming as error, got %+v", f)
+			}
+		}
+	}
+	if len(l.Rules()) != len(AllRules())-2 {
+		t.Errorf("expected 2 rules disabled, got %d of %d", len(l.Rules()), len(AllRules()))
+	}
+
+	if _, err := New(&Config{Rules: map[string]string{"no-such-rule": "off"}}); err == nil {
+		t.Error("expected unknown rule to fail")
+	}
+	if _, err := New(&Config{Rules: map[string]string{"schema-type": "fatal"}}); err == nil {
+		t.Error("expected invalid severity to fail")
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	l, _ := New(nil)
+	findings, _ := l.Lint([]byte(lintTestSpec))
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf, "api/openapi.yaml", "1.2.3", l.Rules(), findings); err != nil {
+		t.Fatalf("WriteSARIF failed: %v", err)
+	}
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				RuleIndex int    `json:"ruleIndex"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region struct {
+							StartLine int `json:"startLine"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid SARIF JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || len(log.Runs[0].Results) != len(findings) {
+		t.Fatalf("expected one run with every finding, got %s", buf.String())
+	}
+	run := log.Runs[0]
+	for _, r := range run.Results {
+		if run.Tool.Driver.Rules[r.RuleIndex].ID != r.RuleID {
+			t.Errorf("ruleIndex %d does not point to %s", r.RuleIndex, r.RuleID)
+		}
+		loc := r.Locations[0].PhysicalLocation
+		if loc.ArtifactLocation.URI != "api/openapi.yaml" || loc.Region.StartLine == 0 {
+			t.Errorf("expected a located result, got %+v", loc)
+		}
+		if r.RuleID == "missing-example" && r.Level != "note" {
+			t.Errorf("expected info findings as notes, got %s", r.Level)
+		}
+	}
+}
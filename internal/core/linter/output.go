@@ -0,0 +1,96 @@
+package linter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// WriteText writes findings one per line as file:line:column, followed by
+// a summary.
+func WriteText(w io.Writer, file string, findings []Finding) error {
+	if len(findings) == 0 {
+		_, err := fmt.Fprintf(w, "No problems found in %s\n", file)
+		return err
+	}
+	for _, f := range findings {
+		if _, err := fmt.Fprintf(w, "%s:%d:%d: %s: %s [%s]\n", file, f.Line, f.Column, f.Severity, f.Message, f.Rule); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "\n%d problems (%d errors, %d warnings, %d info)\n", len(findings),
+		Count(findings, SeverityError), Count(findings, SeverityWarning), Count(findings, SeverityInfo))
+	return err
+}
+
+// WriteJSON writes the findings as a JSON document.
+func WriteJSON(w io.Writer, file string, findings []Finding) error {
+	out := map[string]any{
+		"file":     file,
+		"findings": findings,
+		"summary": map[string]int{
+			"error":   Count(findings, SeverityError),
+			"warning": Count(findings, SeverityWarning),
+			"info":    Count(findings, SeverityInfo),
+		},
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// sarifLevels maps severities to SARIF result levels.
+var sarifLevels = map[Severity]string{SeverityError: "error", SeverityWarning: "warning", SeverityInfo: "note"}
+
+// WriteSARIF writes the findings as a SARIF 2.1.0 log, for code scanning
+// tools such as GitHub code scanning.
+func WriteSARIF(w io.Writer, file, version string, rules []Rule, findings []Finding) error {
+	uri := file
+	if !strings.Contains(file, "://") {
+		uri = filepath.ToSlash(file)
+	}
+	sarifRules := make([]map[string]any, len(rules))
+	for i, r := range rules {
+		sarifRules[i] = map[string]any{
+			"id":                   r.ID,
+			"shortDescription":     map[string]string{"text": r.Description},
+			"defaultConfiguration": map[string]string{"level": sarifLevels[r.Severity]},
+		}
+	}
+	results := make([]map[string]any, len(findings))
+	for i, f := range findings {
+		location := map[string]any{"artifactLocation": map[string]string{"uri": uri}}
+		if f.Line > 0 {
+			location["region"] = map[string]int{"startLine": f.Line, "startColumn": max(f.Column, 1)}
+		}
+		results[i] = map[string]any{
+			"ruleId":    f.Rule,
+			"ruleIndex": slices.IndexFunc(rules, func(r Rule) bool { return r.ID == f.Rule }),
+			"level":     sarifLevels[f.Severity],
+			"message":   map[string]string{"text": f.Message},
+			"locations": []map[string]any{{
+				"physicalLocation": location,
+				"logicalLocations": []map[string]string{{"fullyQualifiedName": f.Path}},
+			}},
+		}
+	}
+	log := map[string]any{
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"version": "2.1.0",
+		"runs": []map[string]any{{
+			"tool": map[string]any{"driver": map[string]any{
+				"name":           "octrafic",
+				"version":        version,
+				"informationUri": "https://github.com/Octrafic/octrafic-cli",
+				"rules":          sarifRules,
+			}},
+			"results": results,
+		}},
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
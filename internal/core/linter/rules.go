@@ -0,0 +1,449 @@
+package linter
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// rules are all rules, with their default severities.
+var rules = []Rule{
+	{ID: "operation-description", Severity: SeverityWarning, Description: "Operations have a summary or description", check: operationDescription},
+	{ID: "parameter-description", Severity: SeverityInfo, Description: "Parameters have a description", check: parameterDescription},
+	{ID: "operation-error-response", Severity: SeverityWarning, Description: "Operations document at least one 4xx, 5xx or default response", check: operationErrorResponse},
+	{ID: "path-naming", Severity: SeverityWarning, Description: "Path segments share one naming style and paths have no trailing slash", check: pathNaming},
+	{ID: "unused-component", Severity: SeverityWarning, Description: "Reusable components and security schemes are used", check: unusedComponent},
+	{ID: "missing-example", Severity: SeverityInfo, Description: "Request bodies and successful responses have examples", check: missingExample},
+	{ID: "undefined-security-scheme", Severity: SeverityError, Description: "Security requirements name defined security schemes", check: undefinedSecurityScheme},
+	{ID: "duplicate-operation-id", Severity: SeverityError, Description: "Operation IDs are unique", check: duplicateOperationID},
+	{ID: "schema-type", Severity: SeverityWarning, Description: "Schemas declare a type", check: schemaType},
+}
+
+// AllRules returns every rule with its default severity.
+func AllRules() []Rule {
+	return slices.Clone(rules)
+}
+
+func operationDescription(d *document) []issue {
+	var issues []issue
+	for _, op := range d.operations() {
+		if strings.TrimSpace(scalar(get(op.node, "summary"))) == "" && strings.TrimSpace(scalar(get(op.node, "description"))) == "" {
+			issues = append(issues, issue{op.key, op.pointer, fmt.Sprintf("%s has no summary or description", op)})
+		}
+	}
+	return issues
+}
+
+func parameterDescription(d *document) []issue {
+	var issues []issue
+	check := func(params *yaml.Node, ptr, owner string) {
+		if params == nil {
+			return
+		}
+		for i, p := range params.Content {
+			p = deref(p)
+			if get(p, "$ref") != nil {
+				continue // checked where it is defined
+			}
+			if strings.TrimSpace(scalar(get(p, "description"))) == "" {
+				issues = append(issues, issue{p, pointer(ptr, fmt.Sprint(i)), fmt.Sprintf("parameter %q of %s has no description", scalar(get(p, "name")), owner)})
+			}
+		}
+	}
+	for _, p := range entries(get(d.root, "paths")) {
+		item, itemPointer := d.resolve(p.value, pointer("", "paths", p.key))
+		check(deref(get(item, "parameters")), pointer(itemPointer, "parameters"), p.key)
+	}
+	for _, op := range d.operations() {
+		check(deref(get(op.node, "parameters")), pointer(op.pointer, "parameters"), op.String())
+	}
+	parameters, ptr := get(get(d.root, "components"), "parameters"), "/components/parameters"
+	if d.swagger {
+		parameters, ptr = get(d.root, "parameters"), "/parameters"
+	}
+	for _, e := range entries(parameters) {
+		if get(e.value, "$ref") == nil && strings.TrimSpace(scalar(get(e.value, "description"))) == "" {
+			issues = append(issues, issue{e.keyNode, pointer(ptr, e.key), fmt.Sprintf("parameter component %q has no description", e.key)})
+		}
+	}
+	return issues
+}
+
+func operationErrorResponse(d *document) []issue {
+	var issues []issue
+	for _, op := range d.operations() {
+		responses := get(op.node, "responses")
+		if len(entries(responses)) == 0 {
+			issues = append(issues, issue{op.key, op.pointer, fmt.Sprintf("%s documents no responses", op)})
+			continue
+		}
+		if !slices.ContainsFunc(entries(responses), func(e entry) bool {
+			return e.key == "default" || strings.HasPrefix(e.key, "4") || strings.HasPrefix(e.key, "5")
+		}) {
+			issues = append(issues, issue{keyNode(op.node, "responses"), pointer(op.pointer, "responses"), fmt.Sprintf("%s documents no error responses", op)})
+		}
+	}
+	return issues
+}
+
+// segmentStyle returns the naming style of a literal path segment, or ""
+// for segments without one, such as single lowercase words or versions.
+func segmentStyle(segment string) string {
+	if segment == "" || strings.HasPrefix(segment, "{") || strings.ContainsAny(segment, ".:") {
+		return ""
+	}
+	hasUpper := strings.IndexFunc(segment, unicode.IsUpper) >= 0
+	switch {
+	case strings.Contains(segment, "_"):
+		return "snake_case"
+	case strings.Contains(segment, "-"):
+		return "kebab-case"
+	case hasUpper:
+		return "camelCase"
+	}
+	return ""
+}
+
+func pathNaming(d *document) []issue {
+	paths := entries(get(d.root, "paths"))
+	counts := map[string]int{}
+	for _, p := range paths {
+		for segment := range strings.SplitSeq(p.key, "/") {
+			if style := segmentStyle(segment); style != "" {
+				counts[style]++
+			}
+		}
+	}
+	dominant := ""
+	for _, style := range []string{"kebab-case", "snake_case", "camelCase"} {
+		if counts[style] > counts[dominant] {
+			dominant = style
+		}
+	}
+
+	var issues []issue
+	for _, p := range paths {
+		ptr := pointer("", "paths", p.key)
+		if p.key != "/" && strings.HasSuffix(p.key, "/") {
+			issues = append(issues, issue{p.keyNode, ptr, fmt.Sprintf("path %s ends with a slash", p.key)})
+		}
+		if len(counts) < 2 {
+			continue
+		}
+		for segment := range strings.SplitSeq(p.key, "/") {
+			if style := segmentStyle(segment); style != "" && style != dominant {
+				issues = append(issues, issue{p.keyNode, ptr, fmt.Sprintf("segment %q of %s is %s while most paths use %s", segment, p.key, style, dominant)})
+				break
+			}
+		}
+	}
+	return issues
+}
+
+// componentSections returns the pointers of the component maps of the
+// document.
+func (d *document) componentSections() []string {
+	if d.swagger {
+		return []string{"/definitions", "/parameters", "/responses"}
+	}
+	var sections []string
+	for _, kind := range []string{"schemas", "responses", "parameters", "examples", "requestBodies", "headers", "links", "callbacks", "pathItems"} {
+		sections = append(sections, "/components/"+kind)
+	}
+	return sections
+}
+
+func unusedComponent(d *document) []issue {
+	sections := d.componentSections()
+	// component returns the component pointer a reference points into.
+	component := func(ref string) string {
+		ref, ok := strings.CutPrefix(ref, "#")
+		if !ok {
+			return ""
+		}
+		for _, section := range sections {
+			if name, ok := strings.CutPrefix(ref, section+"/"); ok {
+				name, _, _ = strings.Cut(name, "/")
+				return section + "/" + name
+			}
+		}
+		return ""
+	}
+
+	used := map[string]bool{}
+	var queue []string
+	var collect func(n *yaml.Node, ptr string, skip bool)
+	collect = func(n *yaml.Node, ptr string, skip bool) {
+		n = deref(n)
+		if n == nil {
+			return
+		}
+		if n.Kind == yaml.SequenceNode {
+			for i, child := range n.Content {
+				collect(child, pointer(ptr, fmt.Sprint(i)), skip)
+			}
+			return
+		}
+		for _, e := range entries(n) {
+			child := pointer(ptr, e.key)
+			if skip && slices.Contains(sections, child) {
+				continue
+			}
+			var refs []string
+			switch e.key {
+			case "$ref":
+				refs = append(refs, e.value.Value)
+			case "mapping": // discriminator mapping, by reference or schema name
+				for _, m := range entries(e.value) {
+					switch target := scalar(m.value); {
+					case target == "":
+					case strings.HasPrefix(target, "#"):
+						refs = append(refs, target)
+					case d.swagger:
+						refs = append(refs, "#/definitions/"+target)
+					default:
+						refs = append(refs, "#/components/schemas/"+target)
+					}
+				}
+			}
+			for _, ref := range refs {
+				if c := component(ref); c != "" && !used[c] {
+					used[c] = true
+					queue = append(queue, c)
+				}
+			}
+			collect(e.value, child, skip)
+		}
+	}
+	// Components are used when reachable from outside the component maps.
+	collect(d.root, "", true)
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		collect(d.lookup(c), c, false)
+	}
+
+	var issues []issue
+	for _, section := range sections {
+		kind := section[strings.LastIndex(section, "/")+1:]
+		for _, e := range entries(d.lookup(section)) {
+			if !used[pointer(section, e.key)] {
+				issues = append(issues, issue{e.keyNode, pointer(section, e.key), fmt.Sprintf("%s component %q is not used", kind, e.key)})
+			}
+		}
+	}
+
+	schemes, ptr := d.securitySchemes()
+	required := d.requiredSchemes()
+	for _, e := range entries(schemes) {
+		if !slices.ContainsFunc(required, func(r entry) bool { return r.key == e.key }) {
+			issues = append(issues, issue{e.keyNode, pointer(ptr, e.key), fmt.Sprintf("security scheme %q is not required by any operation", e.key)})
+		}
+	}
+	return issues
+}
+
+// securitySchemes returns the map of security schemes and its pointer.
+func (d *document) securitySchemes() (*yaml.Node, string) {
+	if d.swagger {
+		return get(d.root, "securityDefinitions"), "/securityDefinitions"
+	}
+	return get(get(d.root, "components"), "securitySchemes"), "/components/securitySchemes"
+}
+
+// requiredSchemes returns the scheme names of the document's security
+// requirements, top-level and per operation, with their pointers as values.
+func (d *document) requiredSchemes() []entry {
+	var names []entry
+	add := func(security *yaml.Node, ptr string) {
+		if security = deref(security); security == nil {
+			return
+		}
+		for i, requirement := range security.Content {
+			for _, e := range entries(requirement) {
+				names = append(names, entry{key: e.key, keyNode: e.keyNode, value: &yaml.Node{Value: pointer(ptr, fmt.Sprint(i), e.key)}})
+			}
+		}
+	}
+	add(get(d.root, "security"), "/security")
+	for _, op := range d.operations() {
+		add(get(op.node, "security"), pointer(op.pointer, "security"))
+	}
+	return names
+}
+
+func undefinedSecurityScheme(d *document) []issue {
+	schemes, _ := d.securitySchemes()
+	var issues []issue
+	for _, r := range d.requiredSchemes() {
+		if get(schemes, r.key) == nil {
+			issues = append(issues, issue{r.keyNode, r.value.Value, fmt.Sprintf("security requirement names undefined scheme %q", r.key)})
+		}
+	}
+	return issues
+}
+
+func duplicateOperationID(d *document) []issue {
+	first := map[string]operation{}
+	var issues []issue
+	for _, op := range d.operations() {
+		id := scalar(get(op.node, "operationId"))
+		if id == "" {
+			continue
+		}
+		if prev, ok := first[id]; ok {
+			issues = append(issues, issue{get(op.node, "operationId"), pointer(op.pointer, "operationId"), fmt.Sprintf("operationId %q of %s is also used by %s", id, op, prev)})
+			continue
+		}
+		first[id] = op
+	}
+	return issues
+}
+
+// hasExample reports whether a media type, response or schema carries an
+// example, directly or in the schema it describes.
+func (d *document) hasExample(n *yaml.Node, ptr string) bool {
+	n, _ = d.resolve(n, ptr)
+	if n == nil {
+		return false
+	}
+	if get(n, "example") != nil || get(n, "examples") != nil || get(n, "x-example") != nil {
+		return true
+	}
+	if schema := get(n, "schema"); schema != nil {
+		return d.hasExample(schema, "")
+	}
+	if items := get(n, "items"); items != nil && scalar(get(n, "type")) == "array" {
+		return d.hasExample(items, "")
+	}
+	for _, key := range []string{"allOf", "oneOf", "anyOf"} {
+		if list := get(n, key); list != nil && slices.ContainsFunc(list.Content, func(s *yaml.Node) bool { return d.hasExample(s, "") }) {
+			return true
+		}
+	}
+	return false
+}
+
+func missingExample(d *document) []issue {
+	var issues []issue
+	for _, op := range d.operations() {
+		if d.swagger {
+			for i, p := range items(get(op.node, "parameters")) {
+				p, _ = d.resolve(p, "")
+				if scalar(get(p, "in")) == "body" && !d.hasExample(get(p, "schema"), "") {
+					issues = append(issues, issue{p, pointer(op.pointer, "parameters", fmt.Sprint(i)), fmt.Sprintf("%s request body has no example", op)})
+				}
+			}
+		} else if body, _ := d.resolve(get(op.node, "requestBody"), ""); body != nil {
+			for _, mt := range entries(get(body, "content")) {
+				if get(mt.value, "schema") != nil && !d.hasExample(mt.value, "") {
+					issues = append(issues, issue{mt.keyNode, pointer(op.pointer, "requestBody", "content", mt.key), fmt.Sprintf("%s request body (%s) has no example", op, mt.key)})
+				}
+			}
+		}
+
+		for _, r := range entries(get(op.node, "responses")) {
+			if !strings.HasPrefix(r.key, "2") {
+				continue
+			}
+			response, _ := d.resolve(r.value, "")
+			ptr := pointer(op.pointer, "responses", r.key)
+			if d.swagger {
+				if get(response, "schema") != nil && !d.hasExample(response, "") {
+					issues = append(issues, issue{r.keyNode, ptr, fmt.Sprintf("%s response %s has no example", op, r.key)})
+				}
+				continue
+			}
+			for _, mt := range entries(get(response, "content")) {
+				if get(mt.value, "schema") != nil && !d.hasExample(mt.value, "") {
+					issues = append(issues, issue{r.keyNode, pointer(ptr, "content", mt.key), fmt.Sprintf("%s response %s (%s) has no example", op, r.key, mt.key)})
+				}
+			}
+		}
+	}
+	return issues
+}
+
+// schemaKeys are the keywords holding subschemas.
+var schemaKeys = []string{"properties", "patternProperties", "items", "additionalProperties", "allOf", "anyOf", "oneOf", "not", "prefixItems"}
+
+func schemaType(d *document) []issue {
+	var issues []issue
+	seen := map[string]bool{}
+	var walk func(n *yaml.Node, ptr string, composed bool)
+	walk = func(n *yaml.Node, ptr string, composed bool) {
+		n = deref(n)
+		if n == nil || n.Kind != yaml.MappingNode || len(n.Content) == 0 || seen[ptr] {
+			return
+		}
+		seen[ptr] = true
+		if get(n, "$ref") != nil {
+			return
+		}
+		untyped := get(n, "type") == nil
+		for _, key := range []string{"allOf", "anyOf", "oneOf", "not", "const"} {
+			untyped = untyped && get(n, key) == nil
+		}
+		if untyped && !composed {
+			issues = append(issues, issue{n, ptr, "schema has no type"})
+		}
+		for _, key := range schemaKeys {
+			v := get(n, key)
+			switch key {
+			case "properties", "patternProperties":
+				for _, e := range entries(v) {
+					walk(e.value, pointer(ptr, key, e.key), false)
+				}
+			case "allOf", "anyOf", "oneOf", "prefixItems":
+				for i, s := range items(v) {
+					walk(s, pointer(ptr, key, fmt.Sprint(i)), key == "allOf")
+				}
+			default:
+				if v != nil && v.Kind == yaml.SequenceNode { // Swagger tuple items
+					for i, s := range v.Content {
+						walk(s, pointer(ptr, key, fmt.Sprint(i)), false)
+					}
+				} else {
+					walk(v, pointer(ptr, key), false)
+				}
+			}
+		}
+	}
+
+	definitions, ptr := get(get(d.root, "components"), "schemas"), "/components/schemas"
+	if d.swagger {
+		definitions, ptr = get(d.root, "definitions"), "/definitions"
+	}
+	for _, e := range entries(definitions) {
+		walk(e.value, pointer(ptr, e.key), false)
+	}
+
+	// Inline schemas of parameters, bodies, responses and headers.
+	var find func(n *yaml.Node, ptr string)
+	find = func(n *yaml.Node, ptr string) {
+		n = deref(n)
+		if n == nil || ptr == "/components/schemas" || ptr == "/definitions" {
+			return
+		}
+		if n.Kind == yaml.SequenceNode {
+			for i, child := range n.Content {
+				find(child, pointer(ptr, fmt.Sprint(i)))
+			}
+			return
+		}
+		for _, e := range entries(n) {
+			switch {
+			case e.key == "example" || e.key == "examples" || strings.HasPrefix(e.key, "x-"):
+			case e.key == "schema":
+				walk(e.value, pointer(ptr, e.key), false)
+			default:
+				find(e.value, pointer(ptr, e.key))
+			}
+		}
+	}
+	find(d.root, "")
+	return issues
+}
@@ -0,0 +1,518 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AsyncOperation is an operation on an AsyncAPI channel. The enclosing
+// Endpoint's method is PUBLISH when clients send messages to the API and
+// SUBSCRIBE when the API sends them, except for channels with an HTTP
+// binding, which are plain requests. WebSocket channels are tested with a
+// websocket exchange. For other protocols (Kafka, AMQP, MQTT, ...) Path is
+// a synthetic identifier, /channels/<address>, and nothing is executed.
+type AsyncOperation struct {
+	Channel         string         `json:"channel"`            // channel key
+	Address         string         `json:"address"`            // e.g. /chat or user.signedup
+	Action          string         `json:"action"`             // "send" or "receive", from the API's point of view
+	Protocol        string         `json:"protocol,omitempty"` // ws, http, kafka, amqp, ...
+	Servers         []string       `json:"servers,omitempty"`  // URLs of the servers the channel is available on
+	Messages        []AsyncMessage `json:"messages,omitempty"`
+	Bindings        map[string]any `json:"bindings,omitempty"` // protocol -> operation binding
+	ChannelBindings map[string]any `json:"channel_bindings,omitempty"`
+}
+
+// AsyncMessage is a message that can be sent on a channel.
+type AsyncMessage struct {
+	Name        string         `json:"name,omitempty"`
+	Title       string         `json:"title,omitempty"`
+	Summary     string         `json:"summary,omitempty"`
+	ContentType string         `json:"content_type,omitempty"`
+	Payload     map[string]any `json:"payload,omitempty"`
+	Headers     map[string]any `json:"headers,omitempty"`
+	Examples    []Example      `json:"examples,omitempty"` // payload examples
+}
+
+// IsHTTP reports whether the channel is bound to HTTP requests.
+func (op *AsyncOperation) IsHTTP() bool {
+	return op.Protocol == "http" || op.Protocol == "https"
+}
+
+// IsWebSocket reports whether the channel is a WebSocket connection.
+func (op *AsyncOperation) IsWebSocket() bool {
+	return op.Protocol == "ws" || op.Protocol == "wss"
+}
+
+// Executable reports whether octrafic can send requests to the channel.
+func (op *AsyncOperation) Executable() bool {
+	return op.IsHTTP() || op.IsWebSocket()
+}
+
+// isAsyncAPI reports whether a JSON or YAML document declares an AsyncAPI
+// version.
+func isAsyncAPI(content []byte) bool {
+	var doc struct {
+		AsyncAPI any `yaml:"asyncapi"`
+	}
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return false
+	}
+	return doc.AsyncAPI != nil
+}
+
+// asyncDocument is an AsyncAPI document being parsed.
+type asyncDocument struct {
+	doc                map[string]any
+	refs               *refResolver
+	schemes            map[string]SecurityScheme
+	servers            map[string]asyncServer
+	defaultContentType string
+}
+
+type asyncServer struct {
+	url      string
+	protocol string
+	security any
+}
+
+// parseAsyncAPI parses an AsyncAPI 2.x or 3.x document read from location.
+func parseAsyncAPI(content []byte, location string) (*Specification, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(content, &doc); err != nil {
+		if err := yaml.Unmarshal(content, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse AsyncAPI (tried JSON and YAML): %w", err)
+		}
+	}
+
+	spec := &Specification{
+		Format:     "asyncapi",
+		RawContent: string(content),
+		Endpoints:  []Endpoint{},
+	}
+	spec.Version = fmt.Sprint(doc["asyncapi"])
+
+	refs, err := newRefResolver(doc, location)
+	if err != nil {
+		return nil, err
+	}
+	spec.Files = refs.files
+	spec.SecuritySchemes = parseSecuritySchemes(doc, refs)
+
+	a := &asyncDocument{doc: doc, refs: refs, schemes: spec.SecuritySchemes}
+	a.defaultContentType, _ = doc["defaultContentType"].(string)
+	a.servers = a.parseServers()
+
+	if strings.HasPrefix(spec.Version, "2.") {
+		spec.Endpoints = append(spec.Endpoints, a.endpointsV2()...)
+	} else {
+		spec.Endpoints = append(spec.Endpoints, a.endpointsV3()...)
+	}
+	slices.SortFunc(spec.Endpoints, func(a, b Endpoint) int {
+		return strings.Compare(a.Path+" "+a.Method, b.Path+" "+b.Method)
+	})
+	return spec, nil
+}
+
+// parseServers reads the servers object. Variables in 2.x URLs and 3.x hosts
+// are replaced by their defaults.
+func (a *asyncDocument) parseServers() map[string]asyncServer {
+	raw, _ := a.doc["servers"].(map[string]any)
+	servers := make(map[string]asyncServer, len(raw))
+	for name, v := range raw {
+		m, ok := a.refs.deref(v)
+		if !ok {
+			continue
+		}
+		server := asyncServer{security: m["security"]}
+		server.protocol, _ = m["protocol"].(string)
+		server.protocol = strings.ToLower(server.protocol)
+		if u, ok := m["url"].(string); ok {
+			server.url = u
+		} else {
+			host, _ := m["host"].(string)
+			pathname, _ := m["pathname"].(string)
+			server.url = host + pathname
+		}
+		if vars, ok := m["variables"].(map[string]any); ok {
+			for varName, variable := range vars {
+				if vm, ok := a.refs.deref(variable); ok {
+					if def, ok := vm["default"].(string); ok {
+						server.url = strings.ReplaceAll(server.url, "{"+varName+"}", def)
+					}
+				}
+			}
+		}
+		if server.protocol != "" && !strings.Contains(server.url, "://") {
+			server.url = server.protocol + "://" + server.url
+		}
+		servers[name] = server
+	}
+	return servers
+}
+
+// channelServers returns the servers a channel is available on: those it
+// lists by name (2.x) or reference (3.x), or all of them.
+func (a *asyncDocument) channelServers(v any) []asyncServer {
+	var names []string
+	items, _ := v.([]any)
+	for _, item := range items {
+		switch val := item.(type) {
+		case string:
+			names = append(names, val)
+		case map[string]any:
+			if ref, ok := val["$ref"].(string); ok {
+				names = append(names, refName(ref))
+			}
+		}
+	}
+	if len(names) == 0 {
+		names = slices.Sorted(maps.Keys(a.servers))
+	}
+	var servers []asyncServer
+	for _, name := range names {
+		if server, ok := a.servers[name]; ok {
+			servers = append(servers, server)
+		}
+	}
+	return servers
+}
+
+// endpointsV2 reads the publish and subscribe operations of 2.x channels.
+// In 2.x, publish means clients publish to the channel and the API
+// receives the messages.
+func (a *asyncDocument) endpointsV2() []Endpoint {
+	channels, _ := a.doc["channels"].(map[string]any)
+	var out []Endpoint
+	for _, address := range slices.Sorted(maps.Keys(channels)) {
+		ch, ok := a.refs.deref(channels[address])
+		if !ok {
+			continue
+		}
+		servers := a.channelServers(ch["servers"])
+		for kind, action := range map[string]string{"publish": "receive", "subscribe": "send"} {
+			op, ok := a.refs.deref(ch[kind])
+			if !ok {
+				continue
+			}
+			var messages []AsyncMessage
+			if m, ok := a.refs.deref(op["message"]); ok {
+				if oneOf, ok := m["oneOf"].([]any); ok {
+					for _, item := range oneOf {
+						messages = a.appendMessage(messages, item, "")
+					}
+				} else {
+					messages = a.appendMessage(messages, op["message"], "")
+				}
+			}
+
+			var security []SecurityRequirement
+			for _, server := range servers {
+				security = append(security, a.security(server.security)...)
+			}
+			ep := a.endpoint(address, address, action, ch, op, messages, servers, security)
+			ep.OperationID, _ = op["operationId"].(string)
+			out = append(out, ep)
+		}
+	}
+	return out
+}
+
+// endpointsV3 reads the operations of a 3.x document, each of which sends
+// or receives on one channel.
+func (a *asyncDocument) endpointsV3() []Endpoint {
+	operations, _ := a.doc["operations"].(map[string]any)
+	var out []Endpoint
+	for _, name := range slices.Sorted(maps.Keys(operations)) {
+		op, ok := a.refs.deref(operations[name])
+		if !ok {
+			continue
+		}
+		ch, ok := a.refs.deref(op["channel"])
+		if !ok {
+			continue
+		}
+		key := name
+		if ref, ok := op["channel"].(map[string]any)["$ref"].(string); ok {
+			key = refName(ref)
+		}
+		address := key
+		if addr, ok := ch["address"].(string); ok {
+			address = addr
+		}
+
+		var messages []AsyncMessage
+		if refs, ok := op["messages"].([]any); ok && len(refs) > 0 {
+			for _, item := range refs {
+				messages = a.appendMessage(messages, item, "")
+			}
+		} else if chMessages, ok := ch["messages"].(map[string]any); ok {
+			for _, msgName := range slices.Sorted(maps.Keys(chMessages)) {
+				messages = a.appendMessage(messages, chMessages[msgName], msgName)
+			}
+		}
+
+		servers := a.channelServers(ch["servers"])
+		security := a.security(op["security"])
+		if _, ok := op["security"]; !ok {
+			for _, server := range servers {
+				security = append(security, a.security(server.security)...)
+			}
+		}
+		action, _ := op["action"].(string)
+		ep := a.endpoint(key, address, action, ch, op, messages, servers, security)
+		ep.OperationID = name
+		out = append(out, ep)
+	}
+	return out
+}
+
+// endpoint builds the endpoint of an operation on a channel.
+func (a *asyncDocument) endpoint(channel, address, action string, ch, op map[string]any, messages []AsyncMessage, servers []asyncServer, security []SecurityRequirement) Endpoint {
+	async := &AsyncOperation{
+		Channel:  channel,
+		Address:  address,
+		Action:   action,
+		Messages: messages,
+	}
+	async.Bindings, _ = op["bindings"].(map[string]any)
+	async.ChannelBindings, _ = ch["bindings"].(map[string]any)
+	for _, server := range servers {
+		async.Servers = append(async.Servers, server.url)
+	}
+	async.Protocol = channelProtocol(async.Bindings, async.ChannelBindings, servers)
+
+	ep := Endpoint{
+		Method:   "SUBSCRIBE",
+		Path:     "/channels/" + strings.TrimPrefix(address, "/"),
+		AuthType: "none",
+		AsyncAPI: async,
+	}
+	if action == "receive" {
+		ep.Method = "PUBLISH"
+	}
+	ep.Summary, _ = op["summary"].(string)
+	ep.Description, _ = op["description"].(string)
+	if ep.Description == "" {
+		ep.Description = ep.Summary
+	}
+	if ep.Description == "" {
+		ep.Description, _ = ch["description"].(string)
+	}
+	if tags, ok := op["tags"].([]any); ok {
+		for _, tag := range tags {
+			if t, ok := a.refs.deref(tag); ok {
+				if name, ok := t["name"].(string); ok {
+					ep.Tags = append(ep.Tags, name)
+				}
+			}
+		}
+	}
+
+	if async.Executable() {
+		ep.Path = "/" + strings.TrimPrefix(address, "/")
+		ep.Parameters = a.parameters(ch["parameters"])
+	}
+	if async.IsHTTP() {
+		ep.Method = "GET"
+		if action == "receive" {
+			ep.Method = "POST"
+		}
+		if binding, ok := async.Bindings["http"].(map[string]any); ok {
+			if method, ok := binding["method"].(string); ok && method != "" {
+				ep.Method = strings.ToUpper(method)
+			}
+		}
+	}
+
+	content := make([]MediaType, 0, len(messages))
+	for _, msg := range messages {
+		content = append(content, MediaType{ContentType: msg.ContentType, Schema: msg.Payload, Examples: msg.Examples})
+	}
+	if len(content) > 0 {
+		if ep.Method == "SUBSCRIBE" || (async.IsHTTP() && action == "send") {
+			ep.ResponseContent = map[string][]MediaType{"200": content}
+			ep.ResponseSchemas = map[string]map[string]any{"200": content[0].Schema}
+		} else {
+			ep.RequestContent = content
+			ep.RequestSchema = content[0].Schema
+			if len(content[0].Examples) > 0 {
+				ep.RequestBody = exampleBody(content[0].Examples[0].Value)
+			}
+		}
+	}
+
+	if len(security) > 0 {
+		ep.Security = security
+		ep.RequiresAuth = true
+		ep.AuthType = securityAuthType(security)
+	}
+	return ep
+}
+
+// channelProtocol returns the protocol of a channel: HTTP or WebSocket if an
+// operation or channel binding says so, otherwise that of its servers,
+// preferring ones octrafic can execute.
+func channelProtocol(bindings, channelBindings map[string]any, servers []asyncServer) string {
+	for _, protocol := range []string{"http", "ws"} {
+		if _, ok := bindings[protocol]; ok {
+			return protocol
+		}
+		if _, ok := channelBindings[protocol]; ok {
+			return protocol
+		}
+	}
+	for _, server := range servers {
+		switch server.protocol {
+		case "http", "https", "ws", "wss":
+			return server.protocol
+		}
+	}
+	if len(servers) > 0 {
+		return servers[0].protocol
+	}
+	return ""
+}
+
+// appendMessage reads a message object. Messages without a name are named
+// after the reference or channel key they were found under.
+func (a *asyncDocument) appendMessage(messages []AsyncMessage, v any, name string) []AsyncMessage {
+	m, ok := a.refs.deref(v)
+	if !ok {
+		return messages
+	}
+	msg := AsyncMessage{Name: name}
+	if n, ok := m["name"].(string); ok && n != "" {
+		msg.Name = n
+	} else if ref, ok := v.(map[string]any)["$ref"].(string); ok && msg.Name == "" {
+		msg.Name = refName(ref)
+	}
+	msg.Title, _ = m["title"].(string)
+	msg.Summary, _ = m["summary"].(string)
+	msg.ContentType, _ = m["contentType"].(string)
+	if msg.ContentType == "" {
+		msg.ContentType = a.defaultContentType
+	}
+	if msg.ContentType == "" {
+		msg.ContentType = "application/json"
+	}
+	msg.Payload = a.messageSchema(m["payload"])
+	msg.Headers = a.messageSchema(m["headers"])
+
+	examples, _ := m["examples"].([]any)
+	for i, item := range examples {
+		ex, ok := a.refs.deref(item)
+		if !ok {
+			continue
+		}
+		example := Example{Name: fmt.Sprintf("example%d", i+1), Value: ex["payload"]}
+		if n, ok := ex["name"].(string); ok && n != "" {
+			example.Name = n
+		}
+		example.Summary, _ = ex["summary"].(string)
+		msg.Examples = append(msg.Examples, example)
+	}
+	if len(msg.Examples) == 0 {
+		if example, ok := msg.Payload["example"]; ok {
+			msg.Examples = []Example{{Name: "default", Value: example}}
+		} else if values, ok := msg.Payload["examples"].([]any); ok && len(values) > 0 {
+			msg.Examples = []Example{{Name: "default", Value: values[0]}}
+		}
+	}
+	return append(messages, msg)
+}
+
+// messageSchema returns a payload or headers schema, unwrapping the 3.x
+// multi-format schema object ({schemaFormat, schema}).
+func (a *asyncDocument) messageSchema(v any) map[string]any {
+	if m, ok := a.refs.deref(v); ok {
+		if _, ok := m["schemaFormat"]; ok {
+			if schema, ok := m["schema"]; ok {
+				return a.refs.schema(schema)
+			}
+		}
+	}
+	return a.refs.schema(v)
+}
+
+// parameters reads the parameters of a channel address, which are all
+// required. 3.x parameters have enum, default and examples instead of a
+// schema.
+func (a *asyncDocument) parameters(v any) []Parameter {
+	params, _ := v.(map[string]any)
+	var out []Parameter
+	for _, name := range slices.Sorted(maps.Keys(params)) {
+		p, ok := a.refs.deref(params[name])
+		if !ok {
+			continue
+		}
+		param := Parameter{Name: name, In: "path", Required: true}
+		param.Description, _ = p["description"].(string)
+		param.Schema = a.refs.schema(p["schema"])
+		if param.Schema == nil {
+			param.Schema = map[string]any{"type": "string"}
+			for _, key := range []string{"enum", "default"} {
+				if val, ok := p[key]; ok {
+					param.Schema[key] = val
+				}
+			}
+		}
+		param.Type, _ = param.Schema["type"].(string)
+		param.Format, _ = param.Schema["format"].(string)
+		param.Enum, _ = param.Schema["enum"].([]any)
+		param.Default = param.Schema["default"]
+		if examples, ok := p["examples"].([]any); ok && len(examples) > 0 {
+			param.Examples = []Example{{Name: "default", Value: examples[0]}}
+		}
+		out = append(out, param)
+	}
+	return out
+}
+
+// security reads a security list. 2.x lists requirement objects as OpenAPI
+// does; 3.x lists security schemes, any one of which is sufficient.
+func (a *asyncDocument) security(v any) []SecurityRequirement {
+	items, _ := v.([]any)
+	var reqs []SecurityRequirement
+	for _, item := range items {
+		m, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		ref, isRef := m["$ref"].(string)
+		if _, isScheme := m["type"]; !isRef && !isScheme {
+			reqs = append(reqs, parseSecurity([]any{m}, a.schemes)...)
+			continue
+		}
+		scheme, ok := a.refs.deref(m)
+		if !ok {
+			continue
+		}
+		name := refName(ref)
+		if name == "" {
+			name, _ = scheme["type"].(string)
+		}
+		s := parseSecurityScheme(name, scheme)
+		if scopes, ok := scheme["scopes"].([]any); ok {
+			for _, scope := range scopes {
+				if str, ok := scope.(string); ok {
+					s.Scopes = append(s.Scopes, str)
+				}
+			}
+		}
+		reqs = append(reqs, SecurityRequirement{Schemes: []SecurityScheme{s}})
+	}
+	return reqs
+}
+
+// refName returns the last token of a reference, e.g. UserSignedUp for
+// #/components/messages/UserSignedUp.
+func refName(ref string) string {
+	_, fragment, _ := strings.Cut(ref, "#")
+	name := fragment[strings.LastIndex(fragment, "/")+1:]
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~1", "/"), "~0", "~")
+}
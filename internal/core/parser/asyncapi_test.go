@@ -0,0 +1,203 @@
+package parser
+
+import (
+	"testing"
+)
+
+func findAsyncEndpoint(t *testing.T, spec *Specification, method, path string) Endpoint {
+	t.Helper()
+	for _, ep := range spec.Endpoints {
+		if ep.Method == method && ep.Path == path {
+			return ep
+		}
+	}
+	t.Fatalf("endpoint %s %s not found in %+v", method, path, spec.Endpoints)
+	return Endpoint{}
+}
+
+func TestParseAsyncAPI_V2(t *testing.T) {
+	dir := t.TempDir()
+	root := writeSpecFile(t, dir, "asyncapi.yaml", `
+asyncapi: "2.6.0"
+info: {title: Chat, version: "1.0"}
+defaultContentType: application/json
+servers:
+  production:
+    url: chat.example.com/{version}
+    protocol: wss
+    variables:
+      version: {default: v1}
+    security:
+      - token: []
+  broker:
+    url: kafka.example.com:9092
+    protocol: kafka
+channels:
+  /rooms/{roomId}:
+    servers: [production]
+    parameters:
+      roomId:
+        description: Room to join
+        schema: {type: string}
+    publish:
+      operationId: sendMessage
+      summary: Send a chat message
+      message:
+        $ref: "#/components/messages/ChatMessage"
+    subscribe:
+      operationId: receiveEvents
+      message:
+        oneOf:
+          - $ref: "#/components/messages/ChatMessage"
+          - $ref: "#/components/messages/UserJoined"
+  user.signedup:
+    servers: [broker]
+    subscribe:
+      operationId: userSignedUp
+      tags: [{name: users}]
+      message:
+        payload:
+          type: object
+          properties:
+            email: {type: string, format: email}
+components:
+  securitySchemes:
+    token: {type: http, scheme: bearer}
+  messages:
+    ChatMessage:
+      payload: {$ref: "#/components/schemas/Message"}
+      examples:
+        - name: hello
+          payload: {text: hi}
+    UserJoined:
+      name: userJoined
+      payload: {type: object, properties: {user: {type: string}}}
+  schemas:
+    Message:
+      type: object
+      properties:
+        text: {type: string}
+`)
+
+	spec, err := ParseSpecification(root)
+	if err != nil {
+		t.Fatalf("ParseSpecification failed: %v", err)
+	}
+	if spec.Format != "asyncapi" || spec.Version != "2.6.0" {
+		t.Errorf("expected asyncapi 2.6.0, got %s %s", spec.Format, spec.Version)
+	}
+	if len(spec.Endpoints) != 3 {
+		t.Fatalf("expected 3 endpoints, got %d", len(spec.Endpoints))
+	}
+
+	pub := findAsyncEndpoint(t, spec, "PUBLISH", "/rooms/{roomId}")
+	if pub.OperationID != "sendMessage" || pub.AsyncAPI.Action != "receive" {
+		t.Errorf("expected sendMessage received by the API, got %q %q", pub.OperationID, pub.AsyncAPI.Action)
+	}
+	if !pub.AsyncAPI.IsWebSocket() || pub.AsyncAPI.Servers[0] != "wss://chat.example.com/v1" {
+		t.Errorf("expected wss server, got %s %v", pub.AsyncAPI.Protocol, pub.AsyncAPI.Servers)
+	}
+	if len(pub.Parameters) != 1 || pub.Parameters[0].Name != "roomId" || pub.Parameters[0].Type != "string" {
+		t.Errorf("expected roomId parameter, got %+v", pub.Parameters)
+	}
+	if pub.RequestBody != `{"text":"hi"}` || pub.RequestSchema["type"] != "object" {
+		t.Errorf("expected example body and schema, got %q %v", pub.RequestBody, pub.RequestSchema)
+	}
+	if !pub.RequiresAuth || pub.AuthType != "bearer" {
+		t.Errorf("expected bearer auth from the server, got %v %q", pub.RequiresAuth, pub.AuthType)
+	}
+
+	sub := findAsyncEndpoint(t, spec, "SUBSCRIBE", "/rooms/{roomId}")
+	if len(sub.AsyncAPI.Messages) != 2 || sub.AsyncAPI.Messages[0].Name != "ChatMessage" || sub.AsyncAPI.Messages[1].Name != "userJoined" {
+		t.Errorf("expected oneOf messages, got %+v", sub.AsyncAPI.Messages)
+	}
+	if sub.ResponseSchemas["200"] == nil {
+		t.Error("expected received message schema")
+	}
+
+	kafka := findAsyncEndpoint(t, spec, "SUBSCRIBE", "/channels/user.signedup")
+	if kafka.AsyncAPI.Executable() || kafka.AsyncAPI.Protocol != "kafka" {
+		t.Errorf("expected documentation-only kafka channel, got %q", kafka.AsyncAPI.Protocol)
+	}
+	if kafka.RequiresAuth || len(kafka.Tags) != 1 || kafka.Tags[0] != "users" {
+		t.Errorf("expected public channel tagged users, got %v %v", kafka.RequiresAuth, kafka.Tags)
+	}
+	if kafka.AsyncAPI.Messages[0].ContentType != "application/json" {
+		t.Errorf("expected default content type, got %q", kafka.AsyncAPI.Messages[0].ContentType)
+	}
+}
+
+func TestParseAsyncAPI_V3(t *testing.T) {
+	content := []byte(`{
+  "asyncapi": "3.0.0",
+  "info": {"title": "Orders", "version": "1.0"},
+  "servers": {
+    "api": {"host": "api.example.com", "protocol": "https", "security": [{"$ref": "#/components/securitySchemes/key"}]}
+  },
+  "channels": {
+    "orderEvents": {
+      "address": "/orders/{orderId}/events",
+      "parameters": {"orderId": {"description": "Order ID", "examples": ["42"]}},
+      "messages": {
+        "orderCreated": {"$ref": "#/components/messages/OrderCreated"},
+        "orderShipped": {"payload": {"type": "object"}}
+      }
+    }
+  },
+  "operations": {
+    "publishOrderEvent": {
+      "action": "receive",
+      "channel": {"$ref": "#/channels/orderEvents"},
+      "messages": [{"$ref": "#/channels/orderEvents/messages/orderCreated"}],
+      "bindings": {"http": {"method": "PUT"}}
+    },
+    "streamOrderEvents": {
+      "action": "send",
+      "channel": {"$ref": "#/channels/orderEvents"},
+      "security": []
+    }
+  },
+  "components": {
+    "securitySchemes": {"key": {"type": "httpApiKey", "name": "X-API-Key", "in": "header"}},
+    "messages": {
+      "OrderCreated": {
+        "contentType": "application/json",
+        "payload": {"schemaFormat": "application/schema+json;version=draft-07", "schema": {"type": "object", "required": ["id"], "properties": {"id": {"type": "string"}}}}
+      }
+    }
+  }
+}`)
+
+	spec, err := parseAsyncAPI(content, "")
+	if err != nil {
+		t.Fatalf("parseAsyncAPI failed: %v", err)
+	}
+
+	put := findAsyncEndpoint(t, spec, "PUT", "/orders/{orderId}/events")
+	if put.OperationID != "publishOrderEvent" || !put.AsyncAPI.IsHTTP() {
+		t.Errorf("expected HTTP operation publishOrderEvent, got %q %q", put.OperationID, put.AsyncAPI.Protocol)
+	}
+	if len(put.AsyncAPI.Messages) != 1 || put.AsyncAPI.Messages[0].Name != "orderCreated" {
+		t.Errorf("expected only the referenced message, got %+v", put.AsyncAPI.Messages)
+	}
+	if put.RequestSchema["type"] != "object" || put.RequestSchema["schemaFormat"] != nil {
+		t.Errorf("expected unwrapped multi-format schema, got %v", put.RequestSchema)
+	}
+	if !put.RequiresAuth || put.AuthType != "apikey" || put.Security[0].Schemes[0].ParamName != "X-API-Key" {
+		t.Errorf("expected API key auth from the server, got %v %q %+v", put.RequiresAuth, put.AuthType, put.Security)
+	}
+	if path, _, _ := put.SampleRequest(); path != "/orders/42/events" {
+		t.Errorf("expected parameter example in sample path, got %s", path)
+	}
+
+	get := findAsyncEndpoint(t, spec, "GET", "/orders/{orderId}/events")
+	if get.AsyncAPI.Action != "send" || len(get.AsyncAPI.Messages) != 2 {
+		t.Errorf("expected all channel messages, got %q %+v", get.AsyncAPI.Action, get.AsyncAPI.Messages)
+	}
+	if get.RequiresAuth {
+		t.Error("expected empty operation security to override the server's")
+	}
+	if get.AsyncAPI.Servers[0] != "https://api.example.com" {
+		t.Errorf("expected server URL from host, got %v", get.AsyncAPI.Servers)
+	}
+}
@@ -0,0 +1,798 @@
+package parser
+
+import (
+	"encoding/json"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	bpGroupHeading     = regexp.MustCompile(`^Group\s+(.+)$`)
+	bpResourceHeading  = regexp.MustCompile(`^(.*?)\s*\[(/[^\]]*)\]$`)
+	bpActionHeading    = regexp.MustCompile(`^(.*?)\s*\[([A-Z]+)(?:\s+(/[^\]]*))?\]$`)
+	bpShortAction      = regexp.MustCompile(`^([A-Z]+)\s+(/\S*)$`)
+	bpTypeHeading      = regexp.MustCompile(`^(.+?)(?:\s*\((.*)\))?$`)
+	bpPayloadSignature = regexp.MustCompile(`^(Request|Response|Model)(?:\s+([^(]*?))?\s*(?:\(([^)]*)\))?$`)
+	bpMember           = regexp.MustCompile("^(`[^`]+`|[^\\s:(]+)(?:\\s*:\\s*(`[^`]*`|[^(]*?))?\\s*(?:\\(([^)]*)\\))?\\s*(?:-\\s*(.*))?$")
+	bpModelReference   = regexp.MustCompile(`^\[(.+)\]\[\]$`)
+)
+
+// bpItem is an item of a Blueprint list, e.g. + Response 200, with its
+// nested items and the text or code block under it.
+type bpItem struct {
+	text     string
+	indent   int
+	children []*bpItem
+	content  []string
+}
+
+// bpSection is a heading with the lines under it.
+type bpSection struct {
+	heading string
+	lines   []string
+}
+
+// bpPayload is a request, response or model with its assets.
+type bpPayload struct {
+	name        string // request name or response status
+	contentType string
+	headers     [][2]string
+	body        string
+	schema      map[string]any
+}
+
+// blueprint is an API Blueprint document being parsed.
+type blueprint struct {
+	structures map[string]*bpItem // data structure name -> members
+	models     map[string]bpPayload
+}
+
+// isBlueprint reports whether content starts with the API Blueprint
+// metadata line.
+func isBlueprint(content []byte) bool {
+	return strings.HasPrefix(strings.TrimSpace(string(content)), "FORMAT: 1A")
+}
+
+// parseBlueprint parses an API Blueprint document.
+func parseBlueprint(content string) (*Specification, error) {
+	spec := &Specification{
+		Format:     "apiblueprint",
+		Version:    "1A",
+		RawContent: content,
+		Endpoints:  []Endpoint{},
+	}
+	b := &blueprint{structures: make(map[string]*bpItem), models: make(map[string]bpPayload)}
+	sections := bpSections(content)
+
+	// Data structures and models may be referenced before they are defined.
+	inStructures := false
+	for _, s := range sections {
+		if strings.EqualFold(s.heading, "Data Structures") {
+			inStructures = true
+			continue
+		}
+		if inStructures && bpGroupHeading.MatchString(s.heading) {
+			inStructures = false
+		}
+		if inStructures && !bpResourceHeading.MatchString(s.heading) && !bpActionHeading.MatchString(s.heading) {
+			if m := bpTypeHeading.FindStringSubmatch(s.heading); m != nil {
+				b.structures[strings.TrimSpace(m[1])] = &bpItem{text: "(" + m[2] + ")", children: bpItems(s.lines)}
+			}
+			continue
+		}
+		if m := bpResourceHeading.FindStringSubmatch(s.heading); m != nil {
+			for _, item := range bpItems(s.lines) {
+				if sig := bpPayloadSignature.FindStringSubmatch(item.text); sig != nil && sig[1] == "Model" {
+					b.models[strings.TrimSpace(m[1])] = b.payload(item, sig)
+				}
+			}
+		}
+	}
+
+	var group string
+	var resourcePath, resourceDescription string
+	var resourceParams []Parameter
+	var resourceItems []*bpItem
+	inStructures = false
+	for _, s := range sections {
+		heading := s.heading
+		if strings.EqualFold(heading, "Data Structures") {
+			inStructures = true
+			continue
+		}
+		if m := bpGroupHeading.FindStringSubmatch(heading); m != nil {
+			group, inStructures = strings.TrimSpace(m[1]), false
+			resourcePath = ""
+			continue
+		}
+		if inStructures {
+			continue
+		}
+
+		items := bpItems(s.lines)
+		description := bpDescription(s.lines)
+		if m := bpActionHeading.FindStringSubmatch(heading); m != nil {
+			path := resourcePath
+			if m[3] != "" {
+				path = m[3]
+			}
+			if path == "" {
+				continue
+			}
+			spec.Endpoints = append(spec.Endpoints, b.endpoint(m[2], path, strings.TrimSpace(m[1]), description, group, resourceParams, resourceItems, resourceDescription, items))
+			continue
+		}
+		if m := bpShortAction.FindStringSubmatch(heading); m != nil && isHTTPMethod(m[1]) {
+			resourcePath, resourceParams, resourceItems, resourceDescription = m[2], nil, nil, ""
+			spec.Endpoints = append(spec.Endpoints, b.endpoint(m[1], m[2], "", description, group, nil, nil, "", items))
+			continue
+		}
+		if m := bpResourceHeading.FindStringSubmatch(heading); m != nil {
+			resourcePath, resourceDescription, resourceItems = m[2], description, items
+			resourceParams = b.parameters(m[2], bpFind(items, "Parameters"))
+			continue
+		}
+		if strings.HasPrefix(heading, "/") && !strings.ContainsAny(heading, " \t") {
+			resourcePath, resourceDescription, resourceItems = heading, description, items
+			resourceParams = b.parameters(heading, bpFind(items, "Parameters"))
+		}
+	}
+	return spec, nil
+}
+
+// bpSections splits a document at its headings, ignoring # lines in code
+// blocks.
+func bpSections(content string) []bpSection {
+	var sections []bpSection
+	current := &bpSection{}
+	fenced := false
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			fenced = !fenced
+		}
+		if !fenced && strings.HasPrefix(line, "#") {
+			sections = append(sections, *current)
+			current = &bpSection{heading: strings.TrimSpace(strings.TrimLeft(line, "#"))}
+			continue
+		}
+		current.lines = append(current.lines, line)
+	}
+	return append(sections, *current)
+}
+
+// bpDescription returns the text of a section before its first list item.
+func bpDescription(lines []string) string {
+	var text []string
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if bpIsItem(trimmed) {
+			break
+		}
+		if trimmed != "" {
+			text = append(text, trimmed)
+		}
+	}
+	return strings.Join(text, " ")
+}
+
+func bpIsItem(trimmed string) bool {
+	return strings.HasPrefix(trimmed, "+ ") || strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* ")
+}
+
+// bpItems parses the nested lists of a section. Lines indented by eight or
+// more spaces past an item, and fenced blocks, are its code block.
+func bpItems(lines []string) []*bpItem {
+	root := &bpItem{indent: -1}
+	stack := []*bpItem{root}
+	fenced := false
+	for _, raw := range lines {
+		line := strings.ReplaceAll(raw, "\t", "    ")
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		top := stack[len(stack)-1]
+
+		if strings.HasPrefix(trimmed, "```") {
+			fenced = !fenced
+			continue
+		}
+		if fenced || (top != root && trimmed != "" && indent >= top.indent+8) {
+			top.content = append(top.content, line)
+			continue
+		}
+		if !bpIsItem(trimmed) {
+			if top != root {
+				top.content = append(top.content, line)
+			}
+			continue
+		}
+
+		for len(stack) > 1 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		item := &bpItem{text: strings.TrimSpace(trimmed[2:]), indent: indent}
+		parent := stack[len(stack)-1]
+		parent.children = append(parent.children, item)
+		stack = append(stack, item)
+	}
+	return root.children
+}
+
+// code returns an item's content without common indentation and
+// surrounding blank lines.
+func (item *bpItem) code() string {
+	minIndent := -1
+	for _, line := range item.content {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if minIndent < 0 || indent < minIndent {
+			minIndent = indent
+		}
+	}
+	out := make([]string, 0, len(item.content))
+	for _, line := range item.content {
+		if len(line) >= minIndent && minIndent > 0 {
+			line = line[minIndent:]
+		}
+		out = append(out, strings.TrimRight(line, " "))
+	}
+	return strings.Trim(strings.Join(out, "\n"), "\n")
+}
+
+// bpFind returns the first item whose text starts with keyword.
+func bpFind(items []*bpItem, keyword string) *bpItem {
+	for _, item := range items {
+		if item.text == keyword || strings.HasPrefix(item.text, keyword+" ") || strings.HasPrefix(item.text, keyword+"(") {
+			return item
+		}
+	}
+	return nil
+}
+
+// endpoint builds an action's endpoint.
+func (b *blueprint) endpoint(method, uri, name, description, group string, resourceParams []Parameter, resourceItems []*bpItem, resourceDescription string, items []*bpItem) Endpoint {
+	path, _ := bpPath(uri)
+	ep := Endpoint{
+		Method:          method,
+		Path:            path,
+		Summary:         name,
+		Description:     description,
+		Responses:       make(map[string]string),
+		ResponseSchemas: make(map[string]map[string]any),
+		AuthType:        "none",
+	}
+	if ep.Description == "" {
+		ep.Description = name
+	}
+	if ep.Description == "" {
+		ep.Description = resourceDescription
+	}
+	if group != "" {
+		ep.Tags = []string{group}
+	}
+
+	ep.Parameters = slices.Clone(resourceParams)
+	ep.Parameters = mergeParameters(ep.Parameters, b.parameters(uri, bpFind(items, "Parameters")))
+
+	// Attributes of the action, or else of the resource, describe the
+	// request body.
+	attributes := bpFind(items, "Attributes")
+	if attributes == nil {
+		attributes = bpFind(resourceItems, "Attributes")
+	}
+
+	for _, item := range items {
+		sig := bpPayloadSignature.FindStringSubmatch(item.text)
+		if sig == nil {
+			continue
+		}
+		payload := b.payload(item, sig)
+		switch sig[1] {
+		case "Request":
+			if payload.schema == nil && attributes != nil && ep.Method != "GET" {
+				payload.schema = b.attributes(attributes)
+			}
+			b.addRequest(&ep, payload)
+		case "Response":
+			b.addResponse(&ep, payload)
+		}
+	}
+	if len(ep.RequestContent) == 0 && attributes != nil && ep.Method != "GET" && ep.Method != "DELETE" {
+		b.addRequest(&ep, bpPayload{contentType: "application/json", schema: b.attributes(attributes)})
+	}
+
+	for _, p := range ep.Parameters {
+		if p.In == "header" && strings.EqualFold(p.Name, "Authorization") {
+			ep.RequiresAuth = true
+			ep.AuthType = "bearer"
+			if value, ok := p.SampleValue(); ok && strings.HasPrefix(strings.ToLower(value), "basic ") {
+				ep.AuthType = "basic"
+			}
+		}
+	}
+	return ep
+}
+
+// addRequest adds a request payload's media type, headers and example.
+func (b *blueprint) addRequest(ep *Endpoint, payload bpPayload) {
+	for _, h := range payload.headers {
+		if strings.EqualFold(h[0], "Content-Type") {
+			continue
+		}
+		ep.Parameters = mergeParameters(ep.Parameters, []Parameter{{
+			Name:     h[0],
+			In:       "header",
+			Type:     "string",
+			Required: true,
+			Schema:   map[string]any{"type": "string"},
+			Examples: []Example{{Name: "default", Value: h[1]}},
+		}})
+	}
+	if payload.body == "" && payload.schema == nil {
+		return
+	}
+	if payload.body == "" {
+		if example, ok := payload.schema["example"]; ok {
+			payload.body = exampleBody(example)
+		}
+	}
+
+	example := Example{Name: payload.name, Value: bpValue(payload.body, payload.contentType)}
+	if example.Name == "" {
+		example.Name = "default"
+	}
+	i := slices.IndexFunc(ep.RequestContent, func(m MediaType) bool { return m.ContentType == payload.contentType })
+	if i < 0 {
+		ep.RequestContent = append(ep.RequestContent, MediaType{ContentType: payload.contentType, Schema: payload.schema})
+		i = len(ep.RequestContent) - 1
+	}
+	if payload.body != "" {
+		ep.RequestContent[i].Examples = append(ep.RequestContent[i].Examples, example)
+	}
+	sortMediaTypes(ep.RequestContent)
+	ep.RequestBodyRequired = true
+
+	if ep.RequestBody == "" {
+		ep.RequestBody = payload.body
+	}
+	if ep.RequestSchema == nil && isJSON(payload.contentType) {
+		ep.RequestSchema = payload.schema
+	}
+}
+
+// addResponse adds a response payload.
+func (b *blueprint) addResponse(ep *Endpoint, payload bpPayload) {
+	status := payload.name
+	if status == "" {
+		status = "200"
+	}
+	if _, ok := ep.Responses[status]; !ok {
+		ep.Responses[status] = httpStatusText(status)
+	}
+	if payload.body == "" && payload.schema == nil {
+		return
+	}
+	if ep.ResponseContent == nil {
+		ep.ResponseContent = make(map[string][]MediaType)
+	}
+	media := MediaType{ContentType: payload.contentType, Schema: payload.schema}
+	if payload.body != "" {
+		media.Examples = []Example{{Name: "default", Value: bpValue(payload.body, payload.contentType)}}
+	}
+	ep.ResponseContent[status] = append(ep.ResponseContent[status], media)
+	if !isJSON(payload.contentType) {
+		return
+	}
+	if payload.schema != nil {
+		ep.ResponseSchemas[status] = payload.schema
+	}
+	if payload.body != "" {
+		if ep.ResponseExamples == nil {
+			ep.ResponseExamples = make(map[string]any)
+		}
+		ep.ResponseExamples[status] = media.Examples[0].Value
+	}
+}
+
+// httpStatusText describes a status code for the responses map.
+func httpStatusText(status string) string {
+	code, err := strconv.Atoi(status)
+	if err != nil {
+		return ""
+	}
+	switch {
+	case code < 300:
+		return "Success"
+	case code < 400:
+		return "Redirect"
+	case code < 500:
+		return "Client error"
+	}
+	return "Server error"
+}
+
+// bpValue decodes a JSON body, or returns it as text.
+func bpValue(body, contentType string) any {
+	if isJSON(contentType) {
+		var v any
+		if err := json.Unmarshal([]byte(body), &v); err == nil {
+			return v
+		}
+	}
+	return body
+}
+
+// payload reads a request, response or model item. Its assets are nested
+// Headers, Body, Schema and Attributes items, or a code block that is the
+// body, or a [Model][] reference.
+func (b *blueprint) payload(item *bpItem, sig []string) bpPayload {
+	p := bpPayload{name: strings.Trim(strings.TrimSpace(sig[2]), "[]"), contentType: strings.TrimSpace(sig[3])}
+	if ref := bpModelReference.FindStringSubmatch(strings.TrimSpace(item.code())); ref != nil {
+		if model, ok := b.models[ref[1]]; ok {
+			model.name = p.name
+			return model
+		}
+	}
+	if len(item.children) == 0 {
+		p.body = item.code()
+	}
+	for _, child := range item.children {
+		switch {
+		case child.text == "Headers":
+			for _, line := range strings.Split(child.code(), "\n") {
+				if name, value, ok := strings.Cut(line, ":"); ok {
+					p.headers = append(p.headers, [2]string{strings.TrimSpace(name), strings.TrimSpace(value)})
+				}
+			}
+		case child.text == "Body":
+			p.body = child.code()
+		case child.text == "Schema":
+			var schema map[string]any
+			if err := json.Unmarshal([]byte(child.code()), &schema); err == nil {
+				delete(schema, "$schema")
+				p.schema = schema
+			}
+		case strings.HasPrefix(child.text, "Attributes"):
+			if p.schema == nil {
+				p.schema = b.attributes(child)
+			}
+		}
+	}
+	if p.contentType == "" {
+		for _, h := range p.headers {
+			if strings.EqualFold(h[0], "Content-Type") {
+				p.contentType = h[1]
+			}
+		}
+	}
+	if p.contentType == "" && (p.body != "" || p.schema != nil) {
+		p.contentType = "application/json"
+	}
+	return p
+}
+
+// bpPath returns the path of a URI template and the names of its query
+// parameters, e.g. /users/{id} and [page] for /users/{id}{?page}.
+func bpPath(uri string) (string, []string) {
+	var query []string
+	var path strings.Builder
+	for uri != "" {
+		start := strings.Index(uri, "{")
+		if start < 0 {
+			path.WriteString(uri)
+			break
+		}
+		end := strings.Index(uri[start:], "}")
+		if end < 0 {
+			path.WriteString(uri)
+			break
+		}
+		path.WriteString(uri[:start])
+		expr := uri[start+1 : start+end]
+		uri = uri[start+end+1:]
+		switch {
+		case strings.HasPrefix(expr, "?"), strings.HasPrefix(expr, "&"):
+			for _, name := range strings.Split(expr[1:], ",") {
+				query = append(query, strings.TrimSuffix(strings.TrimSpace(name), "*"))
+			}
+		default:
+			path.WriteString("{" + strings.TrimLeft(expr, "+#./;") + "}")
+		}
+	}
+	return path.String(), query
+}
+
+// parameters reads a Parameters section. Parameters are required unless
+// marked optional and are in the path unless the URI lists them as query
+// parameters.
+func (b *blueprint) parameters(uri string, section *bpItem) []Parameter {
+	if section == nil {
+		return nil
+	}
+	_, query := bpPath(uri)
+	var out []Parameter
+	for _, item := range section.children {
+		m := bpMember.FindStringSubmatch(item.text)
+		if m == nil {
+			continue
+		}
+		param := Parameter{Name: strings.Trim(m[1], "`"), In: "path", Required: true, Description: strings.TrimSpace(m[4])}
+		if slices.Contains(query, param.Name) {
+			param.In = "query"
+		}
+		attrs := bpAttributes(m[3])
+		if slices.Contains(attrs, "optional") {
+			param.Required = false
+		}
+		param.Type = "string"
+		for _, attr := range attrs {
+			switch attr {
+			case "number", "string", "boolean", "integer":
+				param.Type = attr
+			case "enum[string]", "enum[number]", "enum":
+				param.Type = strings.TrimSuffix(strings.TrimPrefix(attr, "enum["), "]")
+				if param.Type == "enum" {
+					param.Type = "string"
+				}
+			}
+		}
+		param.Schema = map[string]any{"type": param.Type}
+		if example := strings.Trim(strings.TrimSpace(m[2]), "`"); example != "" {
+			param.Examples = []Example{{Name: "default", Value: bpScalar(example, param.Type)}}
+		}
+		for _, child := range item.children {
+			switch {
+			case strings.HasPrefix(child.text, "Default:"):
+				param.Default = bpScalar(strings.Trim(strings.TrimSpace(strings.TrimPrefix(child.text, "Default:")), "`"), param.Type)
+				param.Schema["default"] = param.Default
+			case child.text == "Members":
+				for _, member := range child.children {
+					value, _, _ := strings.Cut(member.text, " - ")
+					param.Enum = append(param.Enum, bpScalar(strings.Trim(strings.TrimSpace(value), "`"), param.Type))
+				}
+				param.Schema["enum"] = param.Enum
+			}
+		}
+		out = append(out, param)
+	}
+	return out
+}
+
+// bpAttributes splits a type definition such as "number, required".
+func bpAttributes(s string) []string {
+	var attrs []string
+	for _, attr := range strings.Split(s, ",") {
+		if attr = strings.TrimSpace(attr); attr != "" {
+			attrs = append(attrs, attr)
+		}
+	}
+	return attrs
+}
+
+// bpScalar converts a sample value to the parameter or property type.
+func bpScalar(value, typ string) any {
+	switch typ {
+	case "number", "integer":
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return n
+		}
+	case "boolean":
+		if v, err := strconv.ParseBool(value); err == nil {
+			return v
+		}
+	}
+	return value
+}
+
+// attributes converts an Attributes item (MSON) to JSON Schema, with an
+// example built from the member samples.
+func (b *blueprint) attributes(item *bpItem) map[string]any {
+	typ := ""
+	if start := strings.Index(item.text, "("); start >= 0 {
+		typ = strings.TrimSuffix(item.text[start+1:], ")")
+	}
+	c := &msonConverter{b: b, defs: make(map[string]any)}
+	schema := c.convert(typ, item.children, "")
+	if len(c.defs) > 0 {
+		schema["$defs"] = c.defs
+	}
+	return schema
+}
+
+// msonConverter converts the MSON types of one schema. Recursive data
+// structures are kept as #/$defs/<name> references.
+type msonConverter struct {
+	b     *blueprint
+	stack []string
+	defs  map[string]any
+}
+
+// convert converts a type definition with nested members and a sample.
+func (c *msonConverter) convert(typeDef string, members []*bpItem, sample string) map[string]any {
+	base := ""
+	for _, attr := range bpAttributes(typeDef) {
+		switch attr {
+		case "required", "optional", "nullable", "fixed", "fixed-type", "sample", "default":
+		default:
+			if base == "" {
+				base = attr
+			}
+		}
+	}
+	if base == "" {
+		switch {
+		case len(members) > 0 && bpFind(members, "Members") == nil && bpFind(members, "Items") == nil:
+			base = "object"
+		case bpFind(members, "Items") != nil:
+			base = "array"
+		default:
+			base = "string"
+		}
+	}
+
+	var schema map[string]any
+	switch {
+	case base == "object":
+		schema = map[string]any{"type": "object"}
+	case base == "array" || strings.HasPrefix(base, "array["):
+		schema = map[string]any{"type": "array"}
+		itemType := strings.TrimSuffix(strings.TrimPrefix(base, "array["), "]")
+		if itemType != "array" && itemType != "" {
+			schema["items"] = c.convert(itemType, nil, "")
+		}
+	case base == "enum" || strings.HasPrefix(base, "enum["):
+		valueType := strings.TrimSuffix(strings.TrimPrefix(base, "enum["), "]")
+		if valueType == "enum" {
+			valueType = "string"
+		}
+		schema = map[string]any{"type": valueType}
+	case base == "string" || base == "number" || base == "boolean":
+		schema = map[string]any{"type": base}
+	default:
+		schema = c.named(base)
+	}
+	if nullable := slices.Contains(bpAttributes(typeDef), "nullable"); nullable {
+		schema["nullable"] = true
+	}
+
+	typ, _ := schema["type"].(string)
+	if sample != "" {
+		if typ == "array" {
+			var values []any
+			for _, v := range strings.Split(sample, ",") {
+				itemType, _ := schema["items"].(map[string]any)["type"].(string)
+				values = append(values, bpScalar(strings.Trim(strings.TrimSpace(v), "`"), itemType))
+			}
+			schema["example"] = values
+		} else {
+			schema["example"] = bpScalar(sample, typ)
+		}
+	}
+
+	switch typ {
+	case "object":
+		props, _ := schema["properties"].(map[string]any)
+		props = maps.Clone(props)
+		if props == nil {
+			props = make(map[string]any)
+		}
+		required, _ := schema["required"].([]any)
+		required = slices.Clone(required)
+		example := make(map[string]any)
+		if base, ok := schema["example"].(map[string]any); ok {
+			maps.Copy(example, base)
+		}
+		for _, member := range c.properties(members) {
+			m := bpMember.FindStringSubmatch(member.text)
+			if m == nil {
+				continue
+			}
+			name := strings.Trim(m[1], "`")
+			prop := c.convert(m[3], member.children, strings.Trim(strings.TrimSpace(m[2]), "`"))
+			if desc := strings.TrimSpace(m[4]); desc != "" {
+				prop["description"] = desc
+			}
+			props[name] = prop
+			if slices.Contains(bpAttributes(m[3]), "required") && !slices.Contains(required, any(name)) {
+				required = append(required, name)
+			}
+			if v, ok := prop["example"]; ok {
+				example[name] = v
+			}
+		}
+		if len(props) > 0 {
+			schema["properties"] = props
+		}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		if len(example) > 0 {
+			schema["example"] = example
+		}
+	case "array":
+		if items := bpFind(members, "Items"); items != nil {
+			members = items.children
+		}
+		var values []any
+		for _, member := range members {
+			if strings.HasPrefix(member.text, "(") {
+				itemType := strings.TrimSuffix(strings.TrimPrefix(member.text, "("), ")")
+				item := c.convert(itemType, member.children, "")
+				schema["items"] = item
+				if v, ok := item["example"]; ok {
+					values = append(values, v)
+				}
+			} else if m := bpMember.FindStringSubmatch(member.text); m != nil {
+				itemType, _ := schema["items"].(map[string]any)["type"].(string)
+				values = append(values, bpScalar(strings.Trim(m[1], "`"), itemType))
+			}
+		}
+		if len(values) > 0 {
+			schema["example"] = values
+		}
+	default:
+		if enum := bpFind(members, "Members"); enum != nil || strings.HasPrefix(base, "enum") {
+			list := members
+			if enum != nil {
+				list = enum.children
+			}
+			var values []any
+			for _, member := range list {
+				value, _, _ := strings.Cut(member.text, " - ")
+				value, _, _ = strings.Cut(value, " (")
+				values = append(values, bpScalar(strings.Trim(strings.TrimSpace(value), "`"), typ))
+			}
+			if len(values) > 0 {
+				schema["enum"] = values
+			}
+		}
+	}
+	return schema
+}
+
+// properties returns the property members of an object, including those of
+// Properties sections and Include mixins.
+func (c *msonConverter) properties(members []*bpItem) []*bpItem {
+	var out []*bpItem
+	for _, member := range members {
+		switch {
+		case member.text == "Properties":
+			out = append(out, c.properties(member.children)...)
+		case strings.HasPrefix(member.text, "Include "):
+			name := strings.Trim(strings.TrimSpace(strings.TrimPrefix(member.text, "Include ")), "()")
+			if structure, ok := c.b.structures[name]; ok && !slices.Contains(c.stack, name) {
+				c.stack = append(c.stack, name)
+				out = append(out, c.properties(structure.children)...)
+				c.stack = c.stack[:len(c.stack)-1]
+			}
+		case member.text == "Members", member.text == "Items", strings.HasPrefix(member.text, "Default"), strings.HasPrefix(member.text, "Sample"):
+		default:
+			out = append(out, member)
+		}
+	}
+	return out
+}
+
+// named converts a data structure by name.
+func (c *msonConverter) named(name string) map[string]any {
+	structure, ok := c.b.structures[name]
+	if !ok {
+		return map[string]any{}
+	}
+	if slices.Contains(c.stack, name) {
+		c.defs[name] = nil
+		return map[string]any{"$ref": "#/$defs/" + name}
+	}
+	c.stack = append(c.stack, name)
+	typeDef := strings.TrimSuffix(strings.TrimPrefix(structure.text, "("), ")")
+	schema := c.convert(typeDef, structure.children, "")
+	c.stack = c.stack[:len(c.stack)-1]
+	if _, recursive := c.defs[name]; recursive {
+		c.defs[name] = schema
+	}
+	return schema
+}
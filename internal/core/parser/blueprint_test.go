@@ -0,0 +1,175 @@
+package parser
+
+import (
+	"slices"
+	"testing"
+)
+
+const testBlueprint = "FORMAT: 1A\n" + `HOST: https://api.example.com
+
+# Notes API
+
+# Group Notes
+
+## Note Collection [/notes{?page,tag}]
+
++ Parameters
+    + page: ` + "`2`" + ` (number, optional) - Page number
+        + Default: ` + "`1`" + `
+    + tag (enum[string], optional)
+        + Members
+            + ` + "`work`" + `
+            + ` + "`home`" + `
+
+### List Notes [GET]
+
+Returns every note.
+
++ Response 200 (application/json)
+
+        [{"id": 1, "title": "Buy milk"}]
+
+### Create a Note [POST]
+
++ Attributes (Note)
+
++ Request (application/json)
+
+    + Headers
+
+            Authorization: Bearer abc123
+
++ Response 201 (application/json)
+
+    + Attributes (Note)
+
++ Response 422
+
+## Note [/notes/{id}]
+
++ Model (application/json)
+
+    + Body
+
+            {"id": 1, "title": "Buy milk"}
+
+    + Schema
+
+            {"$schema": "http://json-schema.org/draft-04/schema#", "type": "object", "properties": {"id": {"type": "number"}}}
+
++ Parameters
+    + id: ` + "`1`" + ` (number) - Note ID
+
+### Get a Note [GET]
+
++ Response 200
+
+    [Note][]
+
+### Delete a Note [DELETE /notes/{id}/archive]
+
++ Response 204
+
+## GET /health
+
++ Response 200 (text/plain)
+
+        ok
+
+# Data Structures
+
+## Note (object)
++ id: 1 (number, required)
++ title: Buy milk (string, required) - Note title
++ tags (array[string])
+    + work
++ author (Person)
+
+## Person
++ name: Ada
++ friends (array[Person])
+`
+
+func TestParseBlueprint(t *testing.T) {
+	spec, err := parseBlueprint(testBlueprint)
+	if err != nil {
+		t.Fatalf("parseBlueprint failed: %v", err)
+	}
+	if spec.Format != "apiblueprint" {
+		t.Errorf("expected apiblueprint format, got %s", spec.Format)
+	}
+	if len(spec.Endpoints) != 5 {
+		t.Fatalf("expected 5 endpoints, got %d: %+v", len(spec.Endpoints), spec.Endpoints)
+	}
+
+	list := findAsyncEndpoint(t, spec, "GET", "/notes")
+	if list.Summary != "List Notes" || list.Description != "Returns every note." || !slices.Equal(list.Tags, []string{"Notes"}) {
+		t.Errorf("expected summary, description and group tag, got %q %q %v", list.Summary, list.Description, list.Tags)
+	}
+	page := findParam(list.Parameters, "page")
+	if page == nil || page.In != "query" || page.Required || page.Type != "number" || page.Default != float64(1) {
+		t.Errorf("expected optional numeric page query parameter, got %+v", page)
+	}
+	if tag := findParam(list.Parameters, "tag"); tag == nil || len(tag.Enum) != 2 || tag.Enum[0] != "work" {
+		t.Errorf("expected tag enum, got %+v", tag)
+	}
+	if examples, _ := list.ResponseExamples["200"].([]any); len(examples) != 1 {
+		t.Errorf("expected parsed JSON response body, got %v", list.ResponseExamples["200"])
+	}
+
+	create := findAsyncEndpoint(t, spec, "POST", "/notes")
+	if !create.RequiresAuth || create.AuthType != "bearer" {
+		t.Errorf("expected bearer auth from the Authorization header, got %v %q", create.RequiresAuth, create.AuthType)
+	}
+	if create.RequestBody != `{"author":{"name":"Ada"},"id":1,"tags":["work"],"title":"Buy milk"}` {
+		t.Errorf("expected request body from attribute samples, got %q", create.RequestBody)
+	}
+	props, _ := create.RequestSchema["properties"].(map[string]any)
+	if title, _ := props["title"].(map[string]any); title["description"] != "Note title" {
+		t.Errorf("expected title property, got %v", props["title"])
+	}
+	if required, _ := create.RequestSchema["required"].([]any); len(required) != 2 {
+		t.Errorf("expected id and title required, got %v", create.RequestSchema["required"])
+	}
+	if create.RequestSchema["$defs"] == nil {
+		t.Errorf("expected recursive Person in $defs, got %v", create.RequestSchema)
+	}
+	if create.ResponseSchemas["201"]["type"] != "object" || create.Responses["422"] == "" {
+		t.Errorf("expected 201 schema and 422 response, got %v %v", create.ResponseSchemas, create.Responses)
+	}
+
+	get := findAsyncEndpoint(t, spec, "GET", "/notes/{id}")
+	if id := findParam(get.Parameters, "id"); id == nil || id.In != "path" || !id.Required {
+		t.Errorf("expected required id path parameter, got %+v", id)
+	}
+	if get.ResponseSchemas["200"]["type"] != "object" || get.ResponseSchemas["200"]["$schema"] != nil {
+		t.Errorf("expected model schema, got %v", get.ResponseSchemas["200"])
+	}
+	if example, _ := get.ResponseExamples["200"].(map[string]any); example["title"] != "Buy milk" {
+		t.Errorf("expected model body, got %v", get.ResponseExamples["200"])
+	}
+
+	archive := findAsyncEndpoint(t, spec, "DELETE", "/notes/{id}/archive")
+	if findParam(archive.Parameters, "id") == nil || archive.Responses["204"] == "" {
+		t.Errorf("expected inherited id parameter and 204 response, got %+v %v", archive.Parameters, archive.Responses)
+	}
+
+	health := findAsyncEndpoint(t, spec, "GET", "/health")
+	if health.ResponseContent["200"][0].Examples[0].Value != "ok" || health.ResponseExamples != nil {
+		t.Errorf("expected plain text response, got %+v", health.ResponseContent)
+	}
+}
+
+func TestParseSpecification_Blueprint(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"api.apib", "api.md"} {
+		path := writeSpecFile(t, dir, name, testBlueprint)
+		spec, err := ParseSpecification(path)
+		if err != nil {
+			t.Fatalf("ParseSpecification(%s) failed: %v", name, err)
+		}
+		if spec.Format != "apiblueprint" {
+			t.Errorf("expected %s parsed as API Blueprint, got %s", name, spec.Format)
+		}
+	}
+}
@@ -0,0 +1,392 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var bruBlockStart = regexp.MustCompile(`^([\w:\-]+)\s*([{\[])\s*$`)
+
+// bruBlock is a block of a .bru file, e.g. headers { ... }, with its lines
+// unindented.
+type bruBlock struct {
+	name  string
+	lines []string
+}
+
+// bruFile is a parsed .bru file.
+type bruFile struct {
+	path   string
+	blocks []bruBlock
+}
+
+// parseBruFile splits a .bru file into its blocks.
+func parseBruFile(path string, content string) bruFile {
+	file := bruFile{path: path}
+	var current *bruBlock
+	var closing string
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		if current == nil {
+			if m := bruBlockStart.FindStringSubmatch(line); m != nil {
+				current = &bruBlock{name: m[1]}
+				closing = map[string]string{"{": "}", "[": "]"}[m[2]]
+			}
+			continue
+		}
+		if strings.TrimRight(line, " \t") == closing {
+			file.blocks = append(file.blocks, *current)
+			current = nil
+			continue
+		}
+		current.lines = append(current.lines, strings.TrimPrefix(line, "  "))
+	}
+	return file
+}
+
+// block returns the block with the given name, or nil.
+func (f bruFile) block(name string) *bruBlock {
+	for i := range f.blocks {
+		if f.blocks[i].name == name {
+			return &f.blocks[i]
+		}
+	}
+	return nil
+}
+
+// dict returns the enabled key: value pairs of a block. Pairs starting
+// with ~ are disabled.
+func (b *bruBlock) dict() [][2]string {
+	if b == nil {
+		return nil
+	}
+	var pairs [][2]string
+	for _, line := range b.lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "~") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		pairs = append(pairs, [2]string{strings.TrimSpace(key), strings.TrimSpace(value)})
+	}
+	return pairs
+}
+
+// value returns the value of a key of a block.
+func (b *bruBlock) value(key string) string {
+	for _, pair := range b.dict() {
+		if pair[0] == key {
+			return pair[1]
+		}
+	}
+	return ""
+}
+
+// text returns the raw content of a block.
+func (b *bruBlock) text() string {
+	if b == nil {
+		return ""
+	}
+	return strings.TrimSpace(strings.Join(b.lines, "\n"))
+}
+
+// bruMethods are the request blocks of a .bru file.
+var bruMethods = []string{"get", "post", "put", "patch", "delete", "options", "head", "connect", "trace"}
+
+// bruno is a Bruno collection being parsed.
+type bruno struct {
+	root  string
+	vars  map[string]string
+	only  string // parse only this request file
+	files []string
+	raw   []string
+}
+
+// isBrunoCollection reports whether dir holds a Bruno collection.
+func isBrunoCollection(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, "bruno.json"))
+	return err == nil
+}
+
+// parseBruno parses a Bruno collection from its directory, its bruno.json
+// or one of its .bru request files. Variables come from the collection's
+// first environment (by file name), its .env file (as process.env.NAME)
+// and the folders' pre-request vars; headers and authentication are
+// inherited from the collection and folders.
+func parseBruno(path string) (*Specification, error) {
+	b := &bruno{vars: make(map[string]string)}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Bruno collection: %w", err)
+	}
+	switch {
+	case info.IsDir():
+		b.root = path
+	case filepath.Base(path) == "bruno.json":
+		b.root = filepath.Dir(path)
+	default:
+		b.only = path
+		b.root = filepath.Dir(path)
+		for dir := b.root; ; dir = filepath.Dir(dir) {
+			if isBrunoCollection(dir) {
+				b.root = dir
+				break
+			}
+			if filepath.Dir(dir) == dir {
+				break
+			}
+		}
+	}
+	if abs, err := filepath.Abs(b.root); err == nil {
+		b.root = abs
+	}
+	if b.only != "" {
+		if abs, err := filepath.Abs(b.only); err == nil {
+			b.only = abs
+		}
+	}
+
+	spec := &Specification{
+		Format:    "bruno",
+		Endpoints: []Endpoint{},
+	}
+	if data, err := b.read(filepath.Join(b.root, "bruno.json")); err == nil {
+		spec.Version = bruVersion(data)
+	}
+	if data, err := os.ReadFile(filepath.Join(b.root, ".env")); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			if key, value, ok := strings.Cut(strings.TrimSpace(line), "="); ok && !strings.HasPrefix(key, "#") {
+				b.vars["process.env."+strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"'`)
+			}
+		}
+	}
+	if envs, _ := filepath.Glob(filepath.Join(b.root, "environments", "*.bru")); len(envs) > 0 {
+		slices.Sort(envs)
+		if data, err := b.read(envs[0]); err == nil {
+			for _, pair := range parseBruFile(envs[0], data).block("vars").dict() {
+				b.vars[pair[0]] = pair[1]
+			}
+		}
+	}
+
+	var collection bruFile
+	if data, err := b.read(filepath.Join(b.root, "collection.bru")); err == nil {
+		collection = parseBruFile("collection.bru", data)
+	}
+	if err := b.walk(b.root, nil, collection, nil, nil, &spec.Endpoints); err != nil {
+		return nil, err
+	}
+	if b.only != "" && len(spec.Endpoints) == 0 {
+		return nil, fmt.Errorf("failed to parse %s: not a Bruno request", path)
+	}
+	spec.Files = b.files
+	spec.RawContent = strings.Join(b.raw, "\n")
+	return spec, nil
+}
+
+// read reads a collection file and records it for change detection.
+func (b *bruno) read(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	b.files = append(b.files, path)
+	return string(data), nil
+}
+
+// bruVersion returns the version of a bruno.json file.
+func bruVersion(data string) string {
+	var m map[string]any
+	if err := json.Unmarshal([]byte(data), &m); err != nil {
+		return ""
+	}
+	if v, ok := m["version"]; ok {
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
+// walk appends the requests of dir in Bruno's order (by seq, then name),
+// with the headers, auth and vars of settings (collection.bru or
+// folder.bru) applied.
+func (b *bruno) walk(dir string, folder []string, settings bruFile, headers [][2]string, auth *collectionAuth, out *[]Endpoint) error {
+	saved := maps.Clone(b.vars)
+	defer func() { b.vars = saved }()
+	for _, pair := range settings.block("vars:pre-request").dict() {
+		b.vars[pair[0]] = substituteVariables(pair[1], b.vars)
+	}
+	headers = append(slices.Clone(headers), settings.block("headers").dict()...)
+	if mode := settings.block("auth").value("mode"); mode != "" && mode != "inherit" {
+		auth = bruAuth(settings, mode)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read Bruno collection: %w", err)
+	}
+	type entry struct {
+		path string
+		name string
+		seq  float64
+		file bruFile
+	}
+	var requests, folders []entry
+	for _, e := range entries {
+		path := filepath.Join(dir, e.Name())
+		switch {
+		case e.IsDir():
+			if (dir == b.root && e.Name() == "environments") || strings.HasPrefix(e.Name(), ".") || e.Name() == "node_modules" {
+				continue
+			}
+			f := entry{path: path, name: e.Name(), seq: -1}
+			if data, err := os.ReadFile(filepath.Join(path, "folder.bru")); err == nil {
+				f.file = parseBruFile("folder.bru", string(data))
+				if name := f.file.block("meta").value("name"); name != "" {
+					f.name = name
+				}
+				f.seq, _ = strconv.ParseFloat(f.file.block("meta").value("seq"), 64)
+			}
+			folders = append(folders, f)
+		case strings.HasSuffix(e.Name(), ".bru") && e.Name() != "folder.bru" && e.Name() != "collection.bru":
+			if b.only != "" && path != b.only {
+				continue
+			}
+			data, err := b.read(path)
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", path, err)
+			}
+			r := entry{path: path, file: parseBruFile(path, data)}
+			r.name = r.file.block("meta").value("name")
+			r.seq, _ = strconv.ParseFloat(r.file.block("meta").value("seq"), 64)
+			b.raw = append(b.raw, data)
+			requests = append(requests, r)
+		}
+	}
+	bySeq := func(a, b entry) int {
+		if a.seq != b.seq {
+			if a.seq < b.seq {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.name, b.name)
+	}
+	slices.SortStableFunc(requests, bySeq)
+	slices.SortStableFunc(folders, bySeq)
+
+	for _, r := range requests {
+		if ep, ok := b.request(r.file, folder, headers, auth); ok {
+			*out = append(*out, ep)
+		}
+	}
+	for _, f := range folders {
+		if f.file.path != "" {
+			b.files = append(b.files, filepath.Join(f.path, "folder.bru"))
+		}
+		if err := b.walk(f.path, append(slices.Clone(folder), f.name), f.file, headers, auth, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// request converts a request file. Files without a method block are
+// skipped.
+func (b *bruno) request(file bruFile, folder []string, inherited [][2]string, inheritedAuth *collectionAuth) (Endpoint, bool) {
+	var method string
+	var def *bruBlock
+	for _, m := range bruMethods {
+		if def = file.block(m); def != nil {
+			method = m
+			break
+		}
+	}
+	if def == nil {
+		return Endpoint{}, false
+	}
+
+	sub := func(s string) string { return substituteVariables(s, b.vars) }
+	req := collectionRequest{
+		Name:        file.block("meta").value("name"),
+		Description: file.block("docs").text(),
+		Folder:      folder,
+		Method:      method,
+		URL:         sub(def.value("url")),
+		PathParams:  make(map[string]string),
+	}
+	if req.Name == "" {
+		req.Name = strings.TrimSuffix(filepath.Base(file.path), ".bru")
+	}
+	for _, pair := range file.block("params:query").dict() {
+		req.Query = append(req.Query, [2]string{pair[0], sub(pair[1])})
+	}
+	for _, pair := range file.block("params:path").dict() {
+		req.PathParams[pair[0]] = sub(pair[1])
+	}
+
+	own := file.block("headers").dict()
+	for _, h := range inherited {
+		if !slices.ContainsFunc(own, func(o [2]string) bool { return strings.EqualFold(o[0], h[0]) }) {
+			req.Headers = append(req.Headers, [2]string{h[0], sub(h[1])})
+		}
+	}
+	for _, h := range own {
+		req.Headers = append(req.Headers, [2]string{h[0], sub(h[1])})
+	}
+
+	switch def.value("body") {
+	case "json":
+		req.ContentType, req.Body = "application/json", sub(file.block("body:json").text())
+	case "text":
+		req.ContentType, req.Body = "text/plain", sub(file.block("body:text").text())
+	case "xml":
+		req.ContentType, req.Body = "application/xml", sub(file.block("body:xml").text())
+	case "formUrlEncoded":
+		var fields [][2]string
+		for _, pair := range file.block("body:form-urlencoded").dict() {
+			fields = append(fields, [2]string{pair[0], sub(pair[1])})
+		}
+		req.ContentType, req.Body = "application/x-www-form-urlencoded", formBody(fields)
+	case "multipartForm":
+		for _, pair := range file.block("body:multipart-form").dict() {
+			req.Form = append(req.Form, [2]string{pair[0], sub(pair[1])})
+		}
+	case "graphql":
+		req.ContentType = "application/json"
+		req.Body = graphQLBody(sub(file.block("body:graphql").text()), sub(file.block("body:graphql:vars").text()))
+	}
+
+	switch mode := def.value("auth"); mode {
+	case "", "inherit":
+		req.Auth = inheritedAuth
+	default:
+		req.Auth = bruAuth(file, mode)
+	}
+	return req.endpoint(), true
+}
+
+// bruAuth returns the authentication of a request, folder or collection
+// for its auth mode.
+func bruAuth(file bruFile, mode string) *collectionAuth {
+	switch mode {
+	case "bearer", "basic", "digest", "oauth2":
+		return &collectionAuth{Type: mode}
+	case "apikey":
+		block := file.block("auth:apikey")
+		auth := &collectionAuth{Type: "apikey", In: "header", Name: block.value("key")}
+		if strings.EqualFold(block.value("placement"), "queryparams") {
+			auth.In = "query"
+		}
+		return auth
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package parser
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func writeBrunoCollection(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	writeSpecFile(t, dir, "bruno.json", `{"version": "1", "name": "Shop", "type": "collection"}`)
+	writeSpecFile(t, dir, "collection.bru", `headers {
+  X-Client: octrafic
+}
+
+auth {
+  mode: bearer
+}
+`)
+	writeSpecFile(t, dir, "environments/local.bru", `vars {
+  baseUrl: http://localhost:8080
+  ~unused: x
+}
+`)
+	writeSpecFile(t, dir, ".env", "API_KEY=secret\n")
+	writeSpecFile(t, dir, "Health.bru", `meta {
+  name: Health
+  type: http
+  seq: 1
+}
+
+get {
+  url: {{baseUrl}}/health
+  body: none
+  auth: none
+}
+`)
+	writeSpecFile(t, dir, "users/folder.bru", `meta {
+  name: Users
+  seq: 1
+}
+
+vars:pre-request {
+  userId: 42
+}
+`)
+	writeSpecFile(t, dir, "users/Create user.bru", `meta {
+  name: Create user
+  type: http
+  seq: 2
+}
+
+post {
+  url: {{baseUrl}}/users
+  body: json
+  auth: apikey
+}
+
+headers {
+  X-Client: tests
+}
+
+auth:apikey {
+  key: X-Api-Key
+  value: {{process.env.API_KEY}}
+  placement: header
+}
+
+body:json {
+  {
+    "name": "Ada"
+  }
+}
+`)
+	writeSpecFile(t, dir, "users/Get user.bru", `meta {
+  name: Get user
+  type: http
+  seq: 1
+}
+
+get {
+  url: {{baseUrl}}/users/:id?expand=true
+  body: none
+  auth: inherit
+}
+
+params:query {
+  expand: true
+  ~debug: 1
+}
+
+params:path {
+  id: {{userId}}
+}
+
+docs {
+  Returns a user.
+}
+`)
+	return dir
+}
+
+func TestParseBruno(t *testing.T) {
+	dir := writeBrunoCollection(t)
+	spec, err := ParseSpecification(dir)
+	if err != nil {
+		t.Fatalf("ParseSpecification failed: %v", err)
+	}
+	if spec.Format != "bruno" || spec.Version != "1" {
+		t.Errorf("expected bruno 1, got %s %s", spec.Format, spec.Version)
+	}
+	var summaries []string
+	for _, ep := range spec.Endpoints {
+		summaries = append(summaries, ep.Summary)
+	}
+	if !slices.Equal(summaries, []string{"Health", "Get user", "Create user"}) {
+		t.Errorf("expected requests in sequence order, got %v", summaries)
+	}
+
+	health := findAsyncEndpoint(t, spec, "GET", "/health")
+	if health.RequiresAuth {
+		t.Error("expected auth: none to override the collection auth")
+	}
+
+	get := findAsyncEndpoint(t, spec, "GET", "/users/{id}")
+	if id := findParam(get.Parameters, "id"); id == nil || len(id.Examples) != 1 || id.Examples[0].Value != "42" {
+		t.Errorf("expected id from folder vars, got %+v", id)
+	}
+	if findParam(get.Parameters, "debug") != nil {
+		t.Error("expected disabled query parameter to be skipped")
+	}
+	if client := findParam(get.Parameters, "X-Client"); client == nil || client.Examples[0].Value != "octrafic" {
+		t.Errorf("expected collection header, got %+v", client)
+	}
+	if get.AuthType != "bearer" || get.Description != "Returns a user." || len(get.Tags) != 1 || get.Tags[0] != "Users" {
+		t.Errorf("expected inherited bearer auth, docs and folder tag, got %s %q %v", get.AuthType, get.Description, get.Tags)
+	}
+
+	create := findAsyncEndpoint(t, spec, "POST", "/users")
+	if client := findParam(create.Parameters, "X-Client"); client == nil || client.Examples[0].Value != "tests" {
+		t.Errorf("expected request header to override the collection's, got %+v", client)
+	}
+	if create.AuthType != "apikey" || create.RequestSchema["type"] != "object" {
+		t.Errorf("expected API key auth and JSON body, got %s %v", create.AuthType, create.RequestSchema)
+	}
+	if !slices.Contains(spec.Files, filepath.Join(dir, "users", "Get user.bru")) {
+		t.Errorf("expected request files to be tracked, got %v", spec.Files)
+	}
+}
+
+func TestParseBruno_SingleRequest(t *testing.T) {
+	dir := writeBrunoCollection(t)
+	spec, err := ParseSpecification(filepath.Join(dir, "users", "Get user.bru"))
+	if err != nil {
+		t.Fatalf("ParseSpecification failed: %v", err)
+	}
+	if len(spec.Endpoints) != 1 {
+		t.Fatalf("expected 1 endpoint, got %+v", spec.Endpoints)
+	}
+	if ep := spec.Endpoints[0]; ep.Path != "/users/{id}" || ep.AuthType != "bearer" {
+		t.Errorf("expected the request with collection settings, got %s %s", ep.Path, ep.AuthType)
+	}
+}
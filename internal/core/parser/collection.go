@@ -0,0 +1,237 @@
+package parser
+
+import (
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// collectionVariable matches {{name}} and Insomnia's {{ _.name }}.
+var collectionVariable = regexp.MustCompile(`\{\{\s*(?:_\.)?([A-Za-z_$][\w.\-]*)\s*\}\}`)
+
+// collectionRequest is a request saved by an API client (Insomnia, Bruno,
+// REST Client .http files), converted to an endpoint by endpoint.
+type collectionRequest struct {
+	Name        string
+	Description string
+	Folder      []string
+	Method      string
+	URL         string
+	Query       [][2]string // in addition to the URL's query
+	PathParams  map[string]string
+	Headers     [][2]string
+	ContentType string
+	Body        string
+	Form        [][2]string // multipart fields, sent as a schema only
+	Auth        *collectionAuth
+}
+
+// collectionAuth is a request's authentication.
+type collectionAuth struct {
+	Type string // bearer, basic, digest, apikey or oauth2
+	In   string // apikey: header or query
+	Name string // apikey: header or parameter name
+}
+
+// substituteVariables replaces {{name}} with the value of a variable.
+// Variables may refer to each other; unknown ones are left as they are.
+func substituteVariables(s string, vars map[string]string) string {
+	for range 5 {
+		next := collectionVariable.ReplaceAllStringFunc(s, func(match string) string {
+			name := collectionVariable.FindStringSubmatch(match)[1]
+			if value, ok := vars[name]; ok {
+				return value
+			}
+			return match
+		})
+		if next == s {
+			break
+		}
+		s = next
+	}
+	return s
+}
+
+// collectionPath returns the path of a request URL and its query. A leading
+// unresolved {{base_url}} and the scheme and host are dropped; :name and
+// {{name}} segments become {name} path parameters.
+func collectionPath(rawURL string) (string, [][2]string) {
+	if loc := collectionVariable.FindStringIndex(rawURL); loc != nil && loc[0] == 0 {
+		rawURL = rawURL[loc[1]:]
+	}
+	if _, rest, ok := strings.Cut(rawURL, "://"); ok {
+		rawURL = "/"
+		if i := strings.Index(rest, "/"); i >= 0 {
+			rawURL = rest[i:]
+		} else if i := strings.Index(rest, "?"); i >= 0 {
+			rawURL = "/" + rest[i:]
+		}
+	}
+	rawURL, _, _ = strings.Cut(rawURL, "#")
+	path, rawQuery, _ := strings.Cut(rawURL, "?")
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		if name, ok := strings.CutPrefix(seg, ":"); ok && name != "" {
+			segments[i] = "{" + name + "}"
+		} else if m := collectionVariable.FindStringSubmatch(seg); m != nil && m[0] == seg {
+			segments[i] = "{" + m[1] + "}"
+		}
+	}
+
+	var query [][2]string
+	for _, pair := range strings.Split(rawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(pair, "=")
+		if v, err := url.QueryUnescape(value); err == nil {
+			value = v
+		}
+		query = append(query, [2]string{name, value})
+	}
+	return strings.Join(segments, "/"), query
+}
+
+// endpoint converts the request. Headers and query parameters become
+// required parameters with their saved values as examples, so the request
+// is sent as saved.
+func (r collectionRequest) endpoint() Endpoint {
+	path, query := collectionPath(r.URL)
+	ep := Endpoint{
+		Method:      strings.ToUpper(r.Method),
+		Path:        path,
+		Summary:     r.Name,
+		Description: r.Description,
+		Responses:   make(map[string]string),
+		AuthType:    "none",
+	}
+	if ep.Method == "" {
+		ep.Method = "GET"
+	}
+	if ep.Description == "" {
+		ep.Description = r.Name
+	}
+	if len(r.Folder) > 0 {
+		ep.Tags = []string{strings.Join(r.Folder, "/")}
+	}
+
+	for _, name := range templateNames(path) {
+		param := Parameter{Name: name, In: "path", Type: "string", Required: true, Schema: map[string]any{"type": "string"}}
+		if value, ok := r.PathParams[name]; ok && value != "" {
+			param.Examples = []Example{{Name: "default", Value: value}}
+		}
+		ep.Parameters = append(ep.Parameters, param)
+	}
+	for _, q := range append(query, r.Query...) {
+		if slices.ContainsFunc(ep.Parameters, func(p Parameter) bool { return p.In == "query" && p.Name == q[0] }) {
+			continue
+		}
+		ep.Parameters = append(ep.Parameters, collectionParameter(q[0], "query", q[1]))
+	}
+
+	contentType := r.ContentType
+	for _, h := range r.Headers {
+		switch {
+		case strings.EqualFold(h[0], "Content-Type"):
+			if contentType == "" {
+				contentType = h[1]
+			}
+		case strings.EqualFold(h[0], "Authorization") && r.Auth == nil:
+			scheme, _, _ := strings.Cut(h[1], " ")
+			ep.Security = []SecurityRequirement{{Schemes: []SecurityScheme{{Name: "collection", Type: "http", Scheme: strings.ToLower(scheme)}}}}
+		default:
+			ep.Parameters = append(ep.Parameters, collectionParameter(h[0], "header", h[1]))
+		}
+	}
+
+	if auth := r.Auth; auth != nil {
+		scheme := SecurityScheme{Name: "collection"}
+		switch auth.Type {
+		case "bearer", "basic", "digest":
+			scheme.Type, scheme.Scheme = "http", auth.Type
+		case "apikey":
+			scheme.Type, scheme.In, scheme.ParamName = "apiKey", auth.In, auth.Name
+		case "oauth2":
+			scheme.Type = "oauth2"
+		}
+		if scheme.Type != "" {
+			ep.Security = []SecurityRequirement{{Schemes: []SecurityScheme{scheme}}}
+		}
+	}
+	if len(ep.Security) > 0 {
+		ep.RequiresAuth = true
+		if ep.AuthType = securityAuthType(ep.Security); ep.AuthType == "" {
+			ep.AuthType = "bearer"
+		}
+	}
+
+	contentType, _, _ = strings.Cut(contentType, ";")
+	contentType = strings.TrimSpace(contentType)
+	switch {
+	case r.Body != "":
+		if contentType == "" {
+			contentType = "text/plain"
+			if json.Valid([]byte(r.Body)) {
+				contentType = "application/json"
+			}
+		}
+		media := MediaType{ContentType: contentType, Examples: []Example{{Name: "default", Value: r.Body}}}
+		if isJSON(contentType) {
+			var parsed any
+			if err := json.Unmarshal([]byte(r.Body), &parsed); err == nil {
+				media.Schema = inferSchema(parsed)
+				media.Examples[0].Value = parsed
+				ep.RequestSchema = media.Schema
+			}
+		}
+		ep.RequestContent = []MediaType{media}
+		ep.RequestBody = r.Body
+		ep.RequestBodyRequired = true
+	case len(r.Form) > 0:
+		props := make(map[string]any, len(r.Form))
+		for _, f := range r.Form {
+			props[f[0]] = map[string]any{"type": "string", "example": f[1]}
+		}
+		if contentType == "" {
+			contentType = "multipart/form-data"
+		}
+		ep.RequestContent = []MediaType{{ContentType: contentType, Schema: map[string]any{"type": "object", "properties": props}}}
+		ep.RequestBodyRequired = true
+	}
+	return ep
+}
+
+func collectionParameter(name, in, value string) Parameter {
+	param := Parameter{Name: name, In: in, Type: "string", Required: true, Schema: map[string]any{"type": "string"}}
+	if value != "" {
+		param.Examples = []Example{{Name: "default", Value: value}}
+	}
+	return param
+}
+
+// formBody encodes fields as an application/x-www-form-urlencoded body.
+func formBody(fields [][2]string) string {
+	form := make([]string, 0, len(fields))
+	for _, f := range fields {
+		form = append(form, url.QueryEscape(f[0])+"="+url.QueryEscape(f[1]))
+	}
+	return strings.Join(form, "&")
+}
+
+// graphQLBody encodes a GraphQL query and its JSON variables as a request
+// body.
+func graphQLBody(query, variables string) string {
+	body := map[string]any{"query": query}
+	var vars any
+	if strings.TrimSpace(variables) != "" && json.Unmarshal([]byte(variables), &vars) == nil {
+		body["variables"] = vars
+	}
+	data, _ := json.Marshal(body)
+	return string(data)
+}
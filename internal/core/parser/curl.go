@@ -0,0 +1,503 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// curlShort maps curl's short options to their long names.
+var curlShort = map[byte]string{
+	'X': "--request", 'H': "--header", 'd': "--data", 'F': "--form", 'u': "--user",
+	'A': "--user-agent", 'e': "--referer", 'b': "--cookie", 'c': "--cookie-jar",
+	'T': "--upload-file", 'o': "--output", 'w': "--write-out", 'm': "--max-time",
+	'x': "--proxy", 'K': "--config", 'E': "--cert", 'D': "--dump-header", 'r': "--range",
+	'U': "--proxy-user", 'Y': "--speed-limit", 'y': "--speed-time", 'z': "--time-cond",
+	'C': "--continue-at", 'Q': "--quote", 't': "--telnet-option", 'P': "--ftp-port",
+	'G': "--get", 'I': "--head",
+}
+
+// curlArgOptions are the long options that take an argument. Others are
+// flags.
+var curlArgOptions = map[string]bool{
+	"--request": true, "--header": true, "--data": true, "--data-raw": true, "--data-binary": true,
+	"--data-ascii": true, "--data-urlencode": true, "--json": true, "--form": true, "--form-string": true,
+	"--user": true, "--user-agent": true, "--referer": true, "--cookie": true, "--cookie-jar": true,
+	"--url": true, "--url-query": true, "--output": true, "--output-dir": true, "--write-out": true,
+	"--max-time": true, "--connect-timeout": true, "--retry": true, "--retry-delay": true,
+	"--retry-max-time": true, "--proxy": true, "--proxy-user": true, "--proxy-header": true,
+	"--preproxy": true, "--noproxy": true, "--cacert": true, "--capath": true, "--cert": true,
+	"--cert-type": true, "--key": true, "--key-type": true, "--pass": true, "--upload-file": true,
+	"--oauth2-bearer": true, "--config": true, "--dump-header": true, "--range": true,
+	"--resolve": true, "--connect-to": true, "--interface": true, "--limit-rate": true,
+	"--max-redirs": true, "--max-filesize": true, "--aws-sigv4": true, "--unix-socket": true,
+	"--abstract-unix-socket": true, "--ciphers": true, "--tls-max": true, "--expect100-timeout": true,
+	"--keepalive-time": true, "--local-port": true, "--dns-servers": true, "--doh-url": true,
+	"--netrc-file": true, "--trace": true, "--trace-ascii": true, "--stderr": true,
+	"--variable": true, "--speed-limit": true, "--speed-time": true, "--time-cond": true,
+	"--continue-at": true, "--quote": true, "--telnet-option": true, "--ftp-port": true,
+	"--request-target": true, "--pinnedpubkey": true, "--hostpubsha256": true,
+}
+
+// shellReference matches a variable reference left in a value, such as the
+// ${AUTH_TOKEN} credentials placeholders of exported scripts.
+var shellReference = regexp.MustCompile(`\$\{?[A-Za-z_][A-Za-z0-9_]*\}?`)
+
+// curlTestComment matches the "# Test 1: name (GET /path)" and
+// "# Test 1: GET /path" comments CurlExporter writes before each command.
+var curlTestComment = regexp.MustCompile(`^Test \d+: (?:(.+) \(([A-Z]+) (\S+)\)|([A-Z]+) (\S+))$`)
+
+// multipartBoundary separates the parts of -F form bodies.
+const multipartBoundary = "------------------------octrafic"
+
+// curlCommand is a parsed curl command line.
+type curlCommand struct {
+	dir     string
+	stdin   string
+	files   []string // files read with @file
+	method  string
+	url     *shellWord
+	query   []string // --url-query and -G data
+	headers []curlHeader
+	data    []string
+	json    bool
+	get     bool
+	head    bool
+	form    []curlFormField
+	upload  string
+	user    *shellWord
+	digest  bool
+	bearer  *shellWord
+}
+
+type curlHeader struct {
+	name        string
+	value       string
+	placeholder bool // the value references an unset variable
+}
+
+type curlFormField struct {
+	name        string
+	value       string
+	filename    string
+	contentType string
+}
+
+// parseCurlArgs parses the arguments of a curl command. The first URL is
+// the request; output, connection and TLS options are ignored.
+func parseCurlArgs(args []shellWord, stdin, dir string) *curlCommand {
+	c := &curlCommand{dir: dir, stdin: stdin}
+	for i := 0; i < len(args); i++ {
+		arg := args[i].value
+		switch {
+		case arg == "--":
+			for _, w := range args[i+1:] {
+				c.setURL(w)
+			}
+			return c
+		case strings.HasPrefix(arg, "--"):
+			if !curlArgOptions[arg] {
+				c.flag(arg)
+				continue
+			}
+			if i+1 < len(args) {
+				i++
+				c.option(arg, args[i])
+			}
+		case strings.HasPrefix(arg, "-") && len(arg) > 1:
+			// Short options combine (-sSL) and take attached arguments (-XPOST).
+			for j := 1; j < len(arg); j++ {
+				name, ok := curlShort[arg[j]]
+				if !ok || !curlArgOptions[name] {
+					c.flag(name)
+					continue
+				}
+				if j+1 < len(arg) {
+					c.option(name, shellWord{value: arg[j+1:], unresolved: args[i].unresolved})
+				} else if i+1 < len(args) {
+					i++
+					c.option(name, args[i])
+				}
+				break
+			}
+		default:
+			c.setURL(args[i])
+		}
+	}
+	return c
+}
+
+func (c *curlCommand) setURL(w shellWord) {
+	if c.url == nil {
+		c.url = &w
+	}
+}
+
+func (c *curlCommand) flag(name string) {
+	switch name {
+	case "--get":
+		c.get = true
+	case "--head":
+		c.head = true
+	case "--digest":
+		c.digest = true
+	case "--basic":
+		c.digest = false
+	}
+}
+
+func (c *curlCommand) option(name string, v shellWord) {
+	switch name {
+	case "--request":
+		c.method = strings.ToUpper(v.value)
+	case "--url":
+		c.setURL(v)
+	case "--header":
+		c.header(v)
+	case "--user-agent":
+		c.header(shellWord{value: "User-Agent: " + v.value, unresolved: v.unresolved})
+	case "--referer":
+		c.header(shellWord{value: "Referer: " + strings.TrimSuffix(v.value, ";auto"), unresolved: v.unresolved})
+	case "--cookie":
+		// Without a = the argument is a cookie file.
+		if strings.Contains(v.value, "=") {
+			c.header(shellWord{value: "Cookie: " + v.value, unresolved: v.unresolved})
+		}
+	case "--data", "--data-ascii":
+		c.data = append(c.data, strings.NewReplacer("\r", "", "\n", "").Replace(c.fileArg(v.value)))
+	case "--data-binary":
+		c.data = append(c.data, c.fileArg(v.value))
+	case "--data-raw":
+		c.data = append(c.data, v.value)
+	case "--data-urlencode":
+		c.data = append(c.data, c.urlencode(v.value))
+	case "--url-query":
+		c.query = append(c.query, c.urlencode(v.value))
+	case "--json":
+		c.json = true
+		c.data = append(c.data, c.fileArg(v.value))
+	case "--form":
+		c.formField(v.value, true)
+	case "--form-string":
+		c.formField(v.value, false)
+	case "--upload-file":
+		c.upload = c.readFile(v.value)
+	case "--user":
+		c.user = &v
+	case "--oauth2-bearer":
+		c.bearer = &v
+	}
+}
+
+// header adds a -H header. "Name:" removes a header curl would send and
+// "Name;" sends an empty one.
+func (c *curlCommand) header(v shellWord) {
+	name, value, ok := strings.Cut(v.value, ":")
+	if !ok {
+		if name, ok := strings.CutSuffix(strings.TrimSpace(v.value), ";"); ok && name != "" {
+			c.headers = append(c.headers, curlHeader{name: name})
+		}
+		return
+	}
+	name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+	if name == "" || value == "" {
+		return
+	}
+	c.headers = append(c.headers, curlHeader{
+		name:        name,
+		value:       value,
+		placeholder: v.unresolved || shellReference.MatchString(value),
+	})
+}
+
+func (c *curlCommand) hasHeader(name string) bool {
+	for _, h := range c.headers {
+		if strings.EqualFold(h.name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// fileArg returns the data of a -d @file argument, or the argument.
+func (c *curlCommand) fileArg(arg string) string {
+	if name, ok := strings.CutPrefix(arg, "@"); ok {
+		return c.readFile(name)
+	}
+	return arg
+}
+
+// readFile reads a file named in the command, relative to the script; - is
+// the command's input.
+func (c *curlCommand) readFile(name string) string {
+	if name == "-" {
+		return c.stdin
+	}
+	if !filepath.IsAbs(name) {
+		name = filepath.Join(c.dir, name)
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return ""
+	}
+	c.files = append(c.files, name)
+	return string(data)
+}
+
+// urlencode implements --data-urlencode: content, =content, name=content,
+// @file and name@file.
+func (c *curlCommand) urlencode(arg string) string {
+	escape := func(s string) string { return strings.ReplaceAll(url.QueryEscape(s), "+", "%20") }
+	if i := strings.IndexByte(arg, '='); i >= 0 {
+		if i == 0 {
+			return escape(arg[1:])
+		}
+		return arg[:i] + "=" + escape(arg[i+1:])
+	}
+	if i := strings.IndexByte(arg, '@'); i >= 0 {
+		content := escape(c.readFile(arg[i+1:]))
+		if i == 0 {
+			return content
+		}
+		return arg[:i] + "=" + content
+	}
+	return escape(arg)
+}
+
+// formField adds a -F field. With special, name=@file uploads a file and
+// name=<file sends its content, each with optional ;type= and ;filename=.
+func (c *curlCommand) formField(arg string, special bool) {
+	name, value, _ := strings.Cut(arg, "=")
+	field := curlFormField{name: name, value: value}
+	if special && (strings.HasPrefix(value, "@") || strings.HasPrefix(value, "<")) {
+		parts := strings.Split(value[1:], ";")
+		path := parts[0]
+		for _, p := range parts[1:] {
+			if t, ok := strings.CutPrefix(p, "type="); ok {
+				field.contentType = t
+			} else if f, ok := strings.CutPrefix(p, "filename="); ok {
+				field.filename = strings.Trim(f, `"`)
+			}
+		}
+		field.value = c.readFile(path)
+		if value[0] == '@' && field.filename == "" {
+			field.filename = filepath.Base(path)
+		}
+	}
+	c.form = append(c.form, field)
+}
+
+// requestMethod returns the method curl uses for the command.
+func (c *curlCommand) requestMethod() string {
+	switch {
+	case c.method != "":
+		return c.method
+	case c.head:
+		return "HEAD"
+	case c.get:
+		return "GET"
+	case c.upload != "":
+		return "PUT"
+	case len(c.data) > 0 || len(c.form) > 0:
+		return "POST"
+	}
+	return "GET"
+}
+
+// endpoint converts the command. Credentials are an auth requirement;
+// literal ones are also kept as headers, so the request is sent as written
+// unless an auth provider replaces them. Comments written by CurlExporter
+// give the test its name.
+func (c *curlCommand) endpoint(comments []string) (Endpoint, bool) {
+	if c.url == nil {
+		return Endpoint{}, false
+	}
+	path, query := curlPath(*c.url)
+	query = append(query, c.query...)
+
+	req := collectionRequest{Method: c.requestMethod(), URL: path}
+	var operationID string
+	var notes []string
+	for _, comment := range comments {
+		if m := curlTestComment.FindStringSubmatch(comment); m != nil {
+			operationID = m[1]
+			continue
+		}
+		if !strings.HasPrefix(comment, "!") {
+			notes = append(notes, comment)
+		}
+	}
+	req.Name = operationID
+	if req.Name == "" && len(notes) > 0 {
+		req.Name = notes[0]
+	}
+	req.Description = strings.Join(notes, " ")
+
+	for _, h := range c.headers {
+		lower := strings.ToLower(h.name)
+		switch {
+		case lower == "authorization":
+			scheme, _, _ := strings.Cut(h.value, " ")
+			if t := strings.ToLower(scheme); t == "bearer" || t == "basic" || t == "digest" {
+				req.Auth = &collectionAuth{Type: t}
+				if h.placeholder {
+					continue
+				}
+			}
+		case strings.Contains(lower, "api-key") || strings.Contains(lower, "apikey") || strings.Contains(lower, "api_key") ||
+			(h.placeholder && strings.Contains(h.value, "API_KEY")):
+			req.Auth = &collectionAuth{Type: "apikey", In: "header", Name: h.name}
+			if h.placeholder {
+				continue
+			}
+		}
+		req.Headers = append(req.Headers, [2]string{h.name, h.value})
+	}
+	if u := c.user; u != nil {
+		req.Auth = &collectionAuth{Type: "basic"}
+		if c.digest {
+			req.Auth.Type = "digest"
+		} else if !u.unresolved && !shellReference.MatchString(u.value) {
+			credentials := u.value
+			if !strings.Contains(credentials, ":") {
+				credentials += ":"
+			}
+			req.Headers = append(req.Headers, [2]string{"Authorization", "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials))})
+		}
+	}
+	if b := c.bearer; b != nil {
+		req.Auth = &collectionAuth{Type: "bearer"}
+		if !b.unresolved && !shellReference.MatchString(b.value) {
+			req.Headers = append(req.Headers, [2]string{"Authorization", "Bearer " + b.value})
+		}
+	}
+	if c.json {
+		if !c.hasHeader("Content-Type") {
+			req.Headers = append(req.Headers, [2]string{"Content-Type", "application/json"})
+		}
+		if !c.hasHeader("Accept") {
+			req.Headers = append(req.Headers, [2]string{"Accept", "application/json"})
+		}
+	}
+
+	separator := "&"
+	if c.json {
+		separator = ""
+	}
+	switch {
+	case c.get && len(c.data) > 0:
+		query = append(query, strings.Join(c.data, "&"))
+	case len(c.data) > 0:
+		req.Body = strings.Join(c.data, separator)
+		if !c.hasHeader("Content-Type") && !c.json {
+			req.ContentType = "application/x-www-form-urlencoded"
+		}
+	case c.upload != "":
+		req.Body = c.upload
+		if !c.hasHeader("Content-Type") {
+			req.ContentType = "application/octet-stream"
+		}
+	}
+
+	ep := req.endpoint()
+	if len(query) > 0 {
+		ep.Path += "?" + strings.Join(query, "&")
+	}
+	ep.OperationID = operationID
+	if ep.Description == "" {
+		ep.Description = fmt.Sprintf("Curl command to %s", ep.Path)
+	}
+	if len(c.form) > 0 {
+		c.addForm(&ep)
+	}
+	return ep, true
+}
+
+// addForm sets the multipart body of -F fields, with the boundary in its
+// content type unless a Content-Type header was given.
+func (c *curlCommand) addForm(ep *Endpoint) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	_ = w.SetBoundary(multipartBoundary)
+	props := make(map[string]any, len(c.form))
+	for _, f := range c.form {
+		h := make(textproto.MIMEHeader)
+		disposition := fmt.Sprintf("form-data; name=%q", f.name)
+		prop := map[string]any{"type": "string"}
+		if f.filename != "" {
+			disposition += fmt.Sprintf("; filename=%q", f.filename)
+			prop["format"] = "binary"
+			if f.contentType == "" {
+				f.contentType = "application/octet-stream"
+			}
+		} else {
+			prop["example"] = f.value
+		}
+		h.Set("Content-Disposition", disposition)
+		if f.contentType != "" {
+			h.Set("Content-Type", f.contentType)
+		}
+		part, _ := w.CreatePart(h)
+		_, _ = part.Write([]byte(f.value))
+		props[f.name] = prop
+	}
+	_ = w.Close()
+
+	contentType := w.FormDataContentType()
+	for _, h := range c.headers {
+		if strings.EqualFold(h.name, "Content-Type") {
+			contentType = h.value
+		}
+	}
+	ep.RequestBody = body.String()
+	ep.RequestBodyRequired = true
+	ep.RequestContent = []MediaType{{
+		ContentType: contentType,
+		Schema:      map[string]any{"type": "object", "properties": props},
+		Examples:    []Example{{Name: "default", Value: ep.RequestBody}},
+	}}
+}
+
+// curlPath returns the path and query of a curl URL. A leading variable,
+// such as the ${BASE_URL} of exported scripts, is the base URL and is
+// dropped like a scheme and host.
+func curlPath(w shellWord) (string, []string) {
+	raw := w.value
+	if w.lead > 0 {
+		if rest := raw[w.lead:]; rest == "" || rest[0] == '/' || rest[0] == '?' {
+			raw = rest
+		}
+	}
+	if _, rest, ok := strings.Cut(raw, "://"); ok {
+		raw = rest
+		if i := strings.IndexAny(raw, "/?"); i >= 0 {
+			raw = raw[i:]
+		} else {
+			raw = ""
+		}
+	} else if raw != "" && raw[0] != '/' && raw[0] != '?' {
+		// curl accepts URLs without a scheme, e.g. localhost:8080/users.
+		if i := strings.IndexAny(raw, "/?"); i >= 0 {
+			raw = raw[i:]
+		} else {
+			raw = ""
+		}
+	}
+	raw, _, _ = strings.Cut(raw, "#")
+	path, rawQuery, _ := strings.Cut(raw, "?")
+	if path == "" {
+		path = "/"
+	}
+	var query []string
+	if rawQuery != "" {
+		query = append(query, rawQuery)
+	}
+	return path, query
+}
@@ -0,0 +1,541 @@
+package parser
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// harStaticTypes are response content types of assets rather than API calls.
+var harStaticTypes = []string{"text/html", "text/css", "javascript", "image/", "font/", "audio/", "video/"}
+
+var (
+	harUUID = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	harHex  = regexp.MustCompile(`^[0-9a-fA-F]*[0-9][0-9a-fA-F]*$`)
+)
+
+// HAREntry is a request and its response recorded in a HAR file.
+type HAREntry struct {
+	Method          string
+	URL             *url.URL
+	RequestHeaders  map[string]string
+	RequestBody     string
+	Status          int
+	StatusText      string
+	ResponseHeaders map[string]string
+	ResponseBody    string
+	Duration        time.Duration
+}
+
+type harPair struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harLog struct {
+	Log struct {
+		Version string `json:"version"`
+		Entries []struct {
+			Time         float64 `json:"time"`
+			ResourceType string  `json:"_resourceType"`
+			Request      struct {
+				Method   string    `json:"method"`
+				URL      string    `json:"url"`
+				Headers  []harPair `json:"headers"`
+				PostData *struct {
+					MimeType string    `json:"mimeType"`
+					Text     string    `json:"text"`
+					Params   []harPair `json:"params"`
+				} `json:"postData"`
+			} `json:"request"`
+			Response struct {
+				Status     int       `json:"status"`
+				StatusText string    `json:"statusText"`
+				Headers    []harPair `json:"headers"`
+				Content    struct {
+					MimeType string `json:"mimeType"`
+					Text     string `json:"text"`
+					Encoding string `json:"encoding"`
+				} `json:"content"`
+			} `json:"response"`
+		} `json:"entries"`
+	} `json:"log"`
+}
+
+// isHAR reports whether JSON content is an HTTP Archive.
+func isHAR(data map[string]any) bool {
+	log, ok := data["log"].(map[string]any)
+	if !ok {
+		return false
+	}
+	_, ok = log["entries"]
+	return ok
+}
+
+// ReadHAR returns the API calls of a HAR file in recorded order. Preflight
+// requests, failed requests and static assets are dropped, and so are
+// requests to hosts other than the given ones, if any. A host may start
+// with *. to match its subdomains.
+func ReadHAR(content []byte, hosts []string) ([]HAREntry, error) {
+	_, entries, err := readHAR(content, hosts)
+	return entries, err
+}
+
+func readHAR(content []byte, hosts []string) (string, []HAREntry, error) {
+	var har harLog
+	if err := json.Unmarshal(content, &har); err != nil {
+		return "", nil, fmt.Errorf("failed to parse HAR: %w", err)
+	}
+
+	var entries []HAREntry
+	for _, e := range har.Log.Entries {
+		u, err := url.Parse(e.Request.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			continue
+		}
+		if len(hosts) > 0 && !slices.ContainsFunc(hosts, func(h string) bool { return harHostMatches(h, u) }) {
+			continue
+		}
+		method := strings.ToUpper(e.Request.Method)
+		if method == "OPTIONS" || method == "HEAD" || method == "CONNECT" || e.Response.Status == 0 {
+			continue
+		}
+		switch e.ResourceType {
+		case "image", "stylesheet", "script", "font", "media", "manifest", "document":
+			continue
+		}
+		mimeType := strings.ToLower(e.Response.Content.MimeType)
+		if slices.ContainsFunc(harStaticTypes, func(t string) bool { return strings.Contains(mimeType, t) }) {
+			continue
+		}
+
+		entry := HAREntry{
+			Method:          method,
+			URL:             u,
+			RequestHeaders:  harHeaders(e.Request.Headers),
+			Status:          e.Response.Status,
+			StatusText:      e.Response.StatusText,
+			ResponseHeaders: harHeaders(e.Response.Headers),
+			ResponseBody:    e.Response.Content.Text,
+			Duration:        time.Duration(e.Time * float64(time.Millisecond)),
+		}
+		if e.Response.Content.Encoding == "base64" {
+			if decoded, err := base64.StdEncoding.DecodeString(e.Response.Content.Text); err == nil {
+				entry.ResponseBody = string(decoded)
+			}
+		}
+		if _, ok := entry.ResponseHeaders["Content-Type"]; !ok && mimeType != "" {
+			entry.ResponseHeaders["Content-Type"] = e.Response.Content.MimeType
+		}
+		if post := e.Request.PostData; post != nil {
+			entry.RequestBody = post.Text
+			if entry.RequestBody == "" && len(post.Params) > 0 {
+				form := url.Values{}
+				for _, p := range post.Params {
+					form.Add(p.Name, p.Value)
+				}
+				entry.RequestBody = form.Encode()
+			}
+			if _, ok := entry.RequestHeaders["Content-Type"]; !ok && post.MimeType != "" {
+				entry.RequestHeaders["Content-Type"] = post.MimeType
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return har.Log.Version, entries, nil
+}
+
+// harHostMatches reports whether a request is to host, e.g. api.example.com,
+// api.example.com:8443 or *.example.com.
+func harHostMatches(host string, u *url.URL) bool {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if suffix, ok := strings.CutPrefix(host, "*."); ok {
+		return strings.HasSuffix(strings.ToLower(u.Hostname()), "."+suffix)
+	}
+	return host == strings.ToLower(u.Host) || host == strings.ToLower(u.Hostname())
+}
+
+// harHeaders returns headers by canonical name. HTTP/2 pseudo-headers such
+// as :authority are dropped.
+func harHeaders(pairs []harPair) map[string]string {
+	headers := make(map[string]string, len(pairs))
+	for _, h := range pairs {
+		if strings.HasPrefix(h.Name, ":") {
+			continue
+		}
+		headers[http.CanonicalHeaderKey(h.Name)] = h.Value
+	}
+	return headers
+}
+
+// ParseHAR builds a specification from the API calls of a HAR file (see
+// ReadHAR). Requests are grouped into endpoints by method and path, with
+// numeric, UUID and hex identifier segments templated as {id}; parameter,
+// body and response schemas are inferred from every recorded request.
+func ParseHAR(content []byte, hosts []string) (*Specification, error) {
+	version, entries, err := readHAR(content, hosts)
+	if err != nil {
+		return nil, err
+	}
+	spec := &Specification{
+		Format:     "har",
+		Version:    version,
+		RawContent: string(content),
+		Endpoints:  []Endpoint{},
+	}
+
+	groups := make(map[string][]HAREntry)
+	for _, entry := range entries {
+		key := entry.Method + " " + harPathTemplate(entry.URL.Path)
+		groups[key] = append(groups[key], entry)
+	}
+	for _, key := range slices.Sorted(maps.Keys(groups)) {
+		spec.Endpoints = append(spec.Endpoints, harEndpoint(groups[key]))
+	}
+	slices.SortStableFunc(spec.Endpoints, func(a, b Endpoint) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return spec, nil
+}
+
+// harIdentifier reports whether a path segment looks like a generated
+// identifier rather than a resource name.
+func harIdentifier(seg string) bool {
+	if seg == "" {
+		return false
+	}
+	if _, err := strconv.ParseInt(seg, 10, 64); err == nil {
+		return true
+	}
+	return harUUID.MatchString(seg) || (len(seg) >= 16 && harHex.MatchString(seg))
+}
+
+// harPathTemplate replaces identifier segments with {id}, {id2}, ...
+func harPathTemplate(path string) string {
+	segments := strings.Split(path, "/")
+	n := 0
+	for i, seg := range segments {
+		if harIdentifier(seg) {
+			n++
+			segments[i] = "{" + harParamName(n) + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func harParamName(n int) string {
+	if n == 1 {
+		return "id"
+	}
+	return "id" + strconv.Itoa(n)
+}
+
+// harEndpoint builds the endpoint of the requests grouped under it.
+func harEndpoint(entries []HAREntry) Endpoint {
+	first := entries[0]
+	ep := Endpoint{
+		Method:          first.Method,
+		Path:            harPathTemplate(first.URL.Path),
+		Description:     fmt.Sprintf("Recorded from %s (%d requests)", first.URL.Host, len(entries)),
+		Responses:       make(map[string]string),
+		ResponseSchemas: make(map[string]map[string]any),
+		AuthType:        "none",
+	}
+
+	// Path parameters, in the order of their segments.
+	var values [][]string
+	for _, entry := range entries {
+		var ids []string
+		for _, seg := range strings.Split(entry.URL.Path, "/") {
+			if harIdentifier(seg) {
+				ids = append(ids, seg)
+			}
+		}
+		values = append(values, ids)
+	}
+	for i := range values[0] {
+		var column []string
+		for _, ids := range values {
+			column = append(column, ids[i])
+		}
+		param := harParameter(harParamName(i+1), "path", column)
+		param.Required = true
+		ep.Parameters = append(ep.Parameters, param)
+	}
+
+	// Query parameters, required if every request sent them.
+	var names []string
+	queries := make(map[string][]string)
+	for _, entry := range entries {
+		for name, vals := range entry.URL.Query() {
+			if _, ok := queries[name]; !ok {
+				names = append(names, name)
+			}
+			queries[name] = append(queries[name], vals[0])
+		}
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		param := harParameter(name, "query", queries[name])
+		param.Required = len(queries[name]) == len(entries)
+		ep.Parameters = append(ep.Parameters, param)
+	}
+
+	ep.Security = harSecurity(first.RequestHeaders)
+	if len(ep.Security) > 0 {
+		ep.RequiresAuth = true
+		ep.AuthType = securityAuthType(ep.Security)
+	}
+
+	withBody := 0
+	for _, entry := range entries {
+		if entry.RequestBody == "" {
+			continue
+		}
+		withBody++
+		ep.RequestContent = harAddContent(ep.RequestContent, entry.RequestHeaders["Content-Type"], entry.RequestBody)
+	}
+	if len(ep.RequestContent) > 0 {
+		sortMediaTypes(ep.RequestContent)
+		ep.RequestBodyRequired = withBody == len(entries)
+		media := ep.RequestContent[0]
+		ep.RequestBody = exampleBody(media.Examples[0].Value)
+		if isJSON(media.ContentType) {
+			ep.RequestSchema = media.Schema
+		}
+	}
+
+	for _, entry := range entries {
+		status := strconv.Itoa(entry.Status)
+		if _, ok := ep.Responses[status]; !ok {
+			ep.Responses[status] = entry.StatusText
+			if ep.Responses[status] == "" {
+				ep.Responses[status] = http.StatusText(entry.Status)
+			}
+		}
+		if entry.ResponseBody == "" {
+			continue
+		}
+		if ep.ResponseContent == nil {
+			ep.ResponseContent = make(map[string][]MediaType)
+		}
+		ep.ResponseContent[status] = harAddContent(ep.ResponseContent[status], entry.ResponseHeaders["Content-Type"], entry.ResponseBody)
+	}
+	addResponseExamples(&ep)
+	return ep
+}
+
+// addResponseExamples sets the schema and example of each status from its
+// preferred JSON response content.
+func addResponseExamples(ep *Endpoint) {
+	for status, content := range ep.ResponseContent {
+		sortMediaTypes(content)
+		if media := content[0]; isJSON(media.ContentType) && media.Schema != nil {
+			if ep.ResponseSchemas == nil {
+				ep.ResponseSchemas = make(map[string]map[string]any)
+			}
+			ep.ResponseSchemas[status] = media.Schema
+			if ep.ResponseExamples == nil {
+				ep.ResponseExamples = make(map[string]any)
+			}
+			ep.ResponseExamples[status] = media.Examples[0].Value
+		}
+	}
+}
+
+// harParameter infers a parameter's type from its recorded values.
+func harParameter(name, in string, values []string) Parameter {
+	var schema map[string]any
+	for _, v := range values {
+		schema = mergeInferredSchema(schema, inferScalarSchema(v))
+	}
+	param := Parameter{Name: name, In: in, Schema: schema, Examples: []Example{{Name: "default", Value: values[0]}}}
+	param.Type, _ = schema["type"].(string)
+	param.Format, _ = schema["format"].(string)
+	return param
+}
+
+// inferScalarSchema infers the schema of a path or query value.
+func inferScalarSchema(v string) map[string]any {
+	if _, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return map[string]any{"type": "integer"}
+	}
+	if _, err := strconv.ParseFloat(v, 64); err == nil {
+		return map[string]any{"type": "number"}
+	}
+	if v == "true" || v == "false" {
+		return map[string]any{"type": "boolean"}
+	}
+	return inferSchema(v)
+}
+
+// harSecurity infers the authentication of a request from its headers.
+func harSecurity(headers map[string]string) []SecurityRequirement {
+	if value, ok := headers["Authorization"]; ok {
+		scheme, _, _ := strings.Cut(value, " ")
+		return []SecurityRequirement{{Schemes: []SecurityScheme{{Name: "recorded", Type: "http", Scheme: strings.ToLower(scheme)}}}}
+	}
+	for _, name := range slices.Sorted(maps.Keys(headers)) {
+		lower := strings.ToLower(name)
+		if strings.Contains(lower, "api-key") || strings.Contains(lower, "apikey") || strings.Contains(lower, "api_key") {
+			return []SecurityRequirement{{Schemes: []SecurityScheme{{Name: "recorded", Type: "apiKey", In: "header", ParamName: name}}}}
+		}
+	}
+	return nil
+}
+
+// harAddContent adds a body to the media type of its content type, merging
+// the inferred schema of JSON bodies. The first body is the example.
+func harAddContent(content []MediaType, contentType, body string) []MediaType {
+	contentType, _, _ = strings.Cut(contentType, ";")
+	contentType = strings.TrimSpace(contentType)
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	var value any = body
+	var schema map[string]any
+	if isJSON(contentType) {
+		var parsed any
+		if err := json.Unmarshal([]byte(body), &parsed); err == nil {
+			value = parsed
+			schema = inferSchema(parsed)
+		}
+	}
+	i := slices.IndexFunc(content, func(m MediaType) bool { return m.ContentType == contentType })
+	if i < 0 {
+		return append(content, MediaType{ContentType: contentType, Schema: schema, Examples: []Example{{Name: "default", Value: value}}})
+	}
+	content[i].Schema = mergeInferredSchema(content[i].Schema, schema)
+	return content
+}
+
+// inferSchema infers the JSON Schema of a decoded JSON value. Object
+// properties are all required; mergeInferredSchema relaxes that across
+// values.
+func inferSchema(v any) map[string]any {
+	switch val := v.(type) {
+	case nil:
+		return map[string]any{"nullable": true}
+	case bool:
+		return map[string]any{"type": "boolean"}
+	case float64:
+		if val == float64(int64(val)) {
+			return map[string]any{"type": "integer"}
+		}
+		return map[string]any{"type": "number"}
+	case string:
+		schema := map[string]any{"type": "string"}
+		if harUUID.MatchString(val) {
+			schema["format"] = "uuid"
+		} else if _, err := time.Parse(time.RFC3339, val); err == nil {
+			schema["format"] = "date-time"
+		}
+		return schema
+	case []any:
+		var items map[string]any
+		for _, item := range val {
+			items = mergeInferredSchema(items, inferSchema(item))
+		}
+		schema := map[string]any{"type": "array"}
+		if items != nil {
+			schema["items"] = items
+		}
+		return schema
+	case map[string]any:
+		props := make(map[string]any, len(val))
+		for k, item := range val {
+			props[k] = inferSchema(item)
+		}
+		required := make([]any, 0, len(val))
+		for _, k := range slices.Sorted(maps.Keys(val)) {
+			required = append(required, k)
+		}
+		return map[string]any{"type": "object", "properties": props, "required": required}
+	}
+	return map[string]any{}
+}
+
+// mergeInferredSchema combines the schemas inferred from two values:
+// properties are united and stay required only if both have them, integer
+// and number widen to number, null makes a schema nullable and other type
+// conflicts become anyOf.
+func mergeInferredSchema(a, b map[string]any) map[string]any {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	aType, _ := a["type"].(string)
+	bType, _ := b["type"].(string)
+	switch {
+	case aType == "" && a["nullable"] == true && a["anyOf"] == nil:
+		merged := maps.Clone(b)
+		merged["nullable"] = true
+		return merged
+	case bType == "" && b["nullable"] == true && b["anyOf"] == nil:
+		merged := maps.Clone(a)
+		merged["nullable"] = true
+		return merged
+	case aType != bType:
+		if (aType == "integer" && bType == "number") || (aType == "number" && bType == "integer") {
+			merged := maps.Clone(a)
+			merged["type"] = "number"
+			return merged
+		}
+		anyOf, _ := a["anyOf"].([]any)
+		if anyOf == nil {
+			anyOf = []any{a}
+		}
+		for _, s := range anyOf {
+			if t, _ := s.(map[string]any)["type"].(string); t == bType {
+				return a
+			}
+		}
+		return map[string]any{"anyOf": append(slices.Clone(anyOf), b)}
+	}
+
+	merged := maps.Clone(a)
+	if a["format"] != b["format"] {
+		delete(merged, "format")
+	}
+	if b["nullable"] == true {
+		merged["nullable"] = true
+	}
+	switch aType {
+	case "array":
+		aItems, _ := a["items"].(map[string]any)
+		bItems, _ := b["items"].(map[string]any)
+		if items := mergeInferredSchema(aItems, bItems); items != nil {
+			merged["items"] = items
+		}
+	case "object":
+		aProps, _ := a["properties"].(map[string]any)
+		bProps, _ := b["properties"].(map[string]any)
+		props := maps.Clone(aProps)
+		for k, v := range bProps {
+			bProp, _ := v.(map[string]any)
+			aProp, _ := props[k].(map[string]any)
+			props[k] = mergeInferredSchema(aProp, bProp)
+		}
+		merged["properties"] = props
+		aRequired, _ := a["required"].([]any)
+		bRequired, _ := b["required"].([]any)
+		required := make([]any, 0, len(aRequired))
+		for _, k := range aRequired {
+			if slices.Contains(bRequired, k) {
+				required = append(required, k)
+			}
+		}
+		merged["required"] = required
+	}
+	return merged
+}
@@ -0,0 +1,139 @@
+package parser
+
+import (
+	"slices"
+	"testing"
+)
+
+const testHAR = `{
+  "log": {
+    "version": "1.2",
+    "entries": [
+      {
+        "time": 12.5,
+        "request": {
+          "method": "GET", "url": "https://api.example.com/users/42?page=1&limit=10",
+          "headers": [{"name": ":authority", "value": "api.example.com"}, {"name": "authorization", "value": "Bearer abc"}]
+        },
+        "response": {
+          "status": 200, "statusText": "OK",
+          "headers": [{"name": "content-type", "value": "application/json; charset=utf-8"}],
+          "content": {"mimeType": "application/json", "text": "{\"id\": 42, \"name\": \"Ada\", \"email\": null}"}
+        }
+      },
+      {
+        "request": {
+          "method": "GET", "url": "https://api.example.com/users/7?page=2",
+          "headers": [{"name": "Authorization", "value": "Bearer abc"}]
+        },
+        "response": {
+          "status": 200, "headers": [],
+          "content": {"mimeType": "application/json", "encoding": "base64", "text": "eyJpZCI6IDcsICJuYW1lIjogIkdyYWNlIiwgImVtYWlsIjogImdAZXhhbXBsZS5jb20iLCAiYWRtaW4iOiB0cnVlfQ=="}
+        }
+      },
+      {
+        "request": {
+          "method": "POST", "url": "https://api.example.com/users/42/orders/5f8d0d55b54764421b7156c3",
+          "headers": [{"name": "X-Api-Key", "value": "k"}],
+          "postData": {"mimeType": "application/json", "text": "{\"qty\": 1}"}
+        },
+        "response": {
+          "status": 201, "statusText": "Created", "headers": [],
+          "content": {"mimeType": "application/json", "text": "{\"total\": 9.5}"}
+        }
+      },
+      {
+        "request": {"method": "OPTIONS", "url": "https://api.example.com/users/42", "headers": []},
+        "response": {"status": 204, "headers": [], "content": {}}
+      },
+      {
+        "_resourceType": "image",
+        "request": {"method": "GET", "url": "https://cdn.example.com/logo.png", "headers": []},
+        "response": {"status": 200, "headers": [], "content": {"mimeType": "image/png"}}
+      },
+      {
+        "request": {"method": "POST", "url": "https://analytics.tracker.io/collect", "headers": [],
+          "postData": {"mimeType": "application/x-www-form-urlencoded", "params": [{"name": "event", "value": "view"}]}},
+        "response": {"status": 204, "headers": [], "content": {}}
+      }
+    ]
+  }
+}`
+
+func TestParseHAR(t *testing.T) {
+	spec, err := ParseHAR([]byte(testHAR), []string{"*.example.com"})
+	if err != nil {
+		t.Fatalf("ParseHAR failed: %v", err)
+	}
+	if spec.Format != "har" || spec.Version != "1.2" {
+		t.Errorf("expected har 1.2, got %s %s", spec.Format, spec.Version)
+	}
+	if len(spec.Endpoints) != 2 {
+		t.Fatalf("expected 2 endpoints without the preflight and third-party calls, got %+v", spec.Endpoints)
+	}
+
+	get := findAsyncEndpoint(t, spec, "GET", "/users/{id}")
+	if id := findParam(get.Parameters, "id"); id == nil || id.In != "path" || id.Type != "integer" {
+		t.Errorf("expected integer id path parameter, got %+v", id)
+	}
+	if page := findParam(get.Parameters, "page"); page == nil || !page.Required || page.Type != "integer" {
+		t.Errorf("expected required page sent by every request, got %+v", page)
+	}
+	if limit := findParam(get.Parameters, "limit"); limit == nil || limit.Required {
+		t.Errorf("expected optional limit, got %+v", limit)
+	}
+	if !get.RequiresAuth || get.AuthType != "bearer" {
+		t.Errorf("expected bearer auth, got %v %q", get.RequiresAuth, get.AuthType)
+	}
+
+	schema := get.ResponseSchemas["200"]
+	props, _ := schema["properties"].(map[string]any)
+	if email, _ := props["email"].(map[string]any); email["type"] != "string" || email["nullable"] != true {
+		t.Errorf("expected nullable string email merged from both responses, got %v", props["email"])
+	}
+	if required, _ := schema["required"].([]any); !slices.Equal(required, []any{"email", "id", "name"}) {
+		t.Errorf("expected admin optional since only one response had it, got %v", schema["required"])
+	}
+	if _, ok := props["admin"]; !ok {
+		t.Errorf("expected admin property from the base64 response, got %v", props)
+	}
+
+	post := findAsyncEndpoint(t, spec, "POST", "/users/{id}/orders/{id2}")
+	if post.RequestBody != `{"qty":1}` || post.RequestSchema["type"] != "object" {
+		t.Errorf("expected request body and schema, got %q %v", post.RequestBody, post.RequestSchema)
+	}
+	if post.AuthType != "apikey" || post.Security[0].Schemes[0].ParamName != "X-Api-Key" {
+		t.Errorf("expected API key auth, got %q %+v", post.AuthType, post.Security)
+	}
+	total, _ := post.ResponseSchemas["201"]["properties"].(map[string]any)["total"].(map[string]any)
+	if total["type"] != "number" || post.Responses["201"] != "Created" {
+		t.Errorf("expected number total in 201 Created, got %v %v", total, post.Responses)
+	}
+}
+
+func TestReadHAR_AllHosts(t *testing.T) {
+	entries, err := ReadHAR([]byte(testHAR), nil)
+	if err != nil {
+		t.Fatalf("ReadHAR failed: %v", err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("expected 4 API calls without the preflight and image, got %d", len(entries))
+	}
+	if entries[3].RequestBody != "event=view" || entries[3].RequestHeaders["Content-Type"] != "application/x-www-form-urlencoded" {
+		t.Errorf("expected form body from params, got %q %v", entries[3].RequestBody, entries[3].RequestHeaders)
+	}
+	if _, ok := entries[0].RequestHeaders[":authority"]; ok {
+		t.Error("expected pseudo-headers dropped")
+	}
+}
+
+func TestParseSpecification_HAR(t *testing.T) {
+	path := writeSpecFile(t, t.TempDir(), "traffic.har", testHAR)
+	spec, err := ParseSpecification(path)
+	if err != nil {
+		t.Fatalf("ParseSpecification failed: %v", err)
+	}
+	if spec.Format != "har" || len(spec.Endpoints) != 3 {
+		t.Errorf("expected 3 har endpoints from every host, got %s %d", spec.Format, len(spec.Endpoints))
+	}
+}
@@ -0,0 +1,184 @@
+package parser
+
+import (
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	httpFileVariable = regexp.MustCompile(`^@([\w.\-]+)\s*=\s*(.*)$`)
+	httpFileName     = regexp.MustCompile(`^(?:#|//)\s*@name\s*=?\s*(\S+)`)
+	httpFileRequest  = regexp.MustCompile(`^([A-Z]+)\s+(\S+)(?:\s+HTTP/[\d.]+)?$`)
+)
+
+// parseHTTPFile parses a .http or .rest file of the JetBrains HTTP Client or
+// VS Code REST Client. Requests are separated by ### lines; @name = value
+// lines define variables, which override those of the first environment
+// (by name) of http-client.env.json and http-client.private.env.json next
+// to the file.
+func parseHTTPFile(content []byte, location string) (*Specification, error) {
+	spec := &Specification{
+		Format:     "http",
+		RawContent: string(content),
+		Endpoints:  []Endpoint{},
+	}
+	dir := filepath.Dir(location)
+	lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+
+	vars := httpEnvironment(dir, &spec.Files)
+	for _, line := range lines {
+		if m := httpFileVariable.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			vars[m[1]] = substituteVariables(strings.TrimSpace(m[2]), vars)
+		}
+	}
+
+	var block []string
+	name := ""
+	flush := func() {
+		if req, ok := httpFileRequestBlock(block, name, dir, vars, &spec.Files); ok {
+			spec.Endpoints = append(spec.Endpoints, req.endpoint())
+		}
+	}
+	for _, line := range lines {
+		if strings.HasPrefix(line, "###") {
+			flush()
+			block, name = nil, strings.TrimSpace(strings.TrimLeft(line, "#"))
+			continue
+		}
+		block = append(block, line)
+	}
+	flush()
+	return spec, nil
+}
+
+// httpEnvironment returns the variables of the shared and first named
+// environment of the HTTP Client environment files in dir.
+func httpEnvironment(dir string, files *[]string) map[string]string {
+	vars := make(map[string]string)
+	var env string
+	for _, name := range []string{"http-client.env.json", "http-client.private.env.json"} {
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		*files = append(*files, path)
+		var envs map[string]map[string]any
+		if err := json.Unmarshal(data, &envs); err != nil {
+			continue
+		}
+		if env == "" {
+			for _, n := range slices.Sorted(maps.Keys(envs)) {
+				if n != "$shared" {
+					env = n
+					break
+				}
+			}
+		}
+		flattenVariables(envs["$shared"], "", vars)
+		flattenVariables(envs[env], "", vars)
+	}
+	return vars
+}
+
+// httpFileRequestBlock parses the request of a block: comments and
+// variables, the request line and query continuation lines, headers, then
+// the body up to a response handler. A body line "< ./file" includes file.
+func httpFileRequestBlock(block []string, name, dir string, vars map[string]string, files *[]string) (collectionRequest, bool) {
+	req := collectionRequest{Name: name}
+	sub := func(s string) string { return substituteVariables(s, vars) }
+
+	i := 0
+	for ; i < len(block); i++ {
+		line := strings.TrimSpace(block[i])
+		if m := httpFileName.FindStringSubmatch(line); m != nil {
+			if req.Name == "" {
+				req.Name = m[1]
+			}
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") || httpFileVariable.MatchString(line) {
+			continue
+		}
+		if m := httpFileRequest.FindStringSubmatch(line); m != nil && isHTTPMethod(m[1]) {
+			req.Method, req.URL = m[1], m[2]
+		} else if strings.HasPrefix(line, "http") || strings.HasPrefix(line, "{{") || strings.HasPrefix(line, "/") {
+			// A request line without a method is a GET.
+			req.Method, req.URL = "GET", strings.Fields(line)[0]
+		} else {
+			return req, false
+		}
+		i++
+		break
+	}
+	if req.URL == "" {
+		return req, false
+	}
+	for ; i < len(block); i++ {
+		line := strings.TrimSpace(block[i])
+		if !strings.HasPrefix(line, "?") && !strings.HasPrefix(line, "&") {
+			break
+		}
+		req.URL += line
+	}
+	req.URL = sub(req.URL)
+
+	graphQL := false
+	for ; i < len(block); i++ {
+		line := strings.TrimSpace(block[i])
+		if line == "" {
+			i++
+			break
+		}
+		if strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.TrimSpace(key), sub(strings.TrimSpace(value))
+		if strings.EqualFold(key, "X-Request-Type") && strings.EqualFold(value, "GraphQL") {
+			graphQL = true
+			continue
+		}
+		req.Headers = append(req.Headers, [2]string{key, value})
+	}
+
+	var body []string
+	for ; i < len(block); i++ {
+		line := block[i]
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "> ") || strings.HasPrefix(trimmed, ">> ") || strings.HasPrefix(trimmed, "<> ") {
+			break
+		}
+		if path, ok := strings.CutPrefix(trimmed, "< "); ok {
+			path = sub(strings.TrimSpace(path))
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(dir, path)
+			}
+			if data, err := os.ReadFile(path); err == nil {
+				*files = append(*files, path)
+				line = strings.TrimRight(string(data), "\n")
+			}
+		}
+		body = append(body, line)
+	}
+	req.Body = sub(strings.TrimSpace(strings.Join(body, "\n")))
+
+	if graphQL {
+		query, variables, _ := strings.Cut(req.Body, "\n\n")
+		req.ContentType = "application/json"
+		req.Body = graphQLBody(query, variables)
+	}
+	if req.Name == "" {
+		path, _ := collectionPath(req.URL)
+		req.Name = req.Method + " " + path
+	}
+	return req, true
+}
@@ -0,0 +1,77 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestParseSpecification_HTTPFile(t *testing.T) {
+	dir := t.TempDir()
+	writeSpecFile(t, dir, "http-client.env.json", `{
+  "$shared": {"version": "v1"},
+  "dev": {"host": "https://dev.example.com"},
+  "prod": {"host": "https://api.example.com"}
+}`)
+	writeSpecFile(t, dir, "order.json", `{"item": "book", "qty": 2}`)
+	path := writeSpecFile(t, dir, "api.http", `@token = abc
+@base = {{host}}/{{version}}
+
+### List users
+GET {{base}}/users
+    ?page=1
+    &limit=10
+Accept: application/json
+Authorization: Bearer {{token}}
+
+###
+# @name createOrder
+POST {{base}}/orders HTTP/1.1
+Content-Type: application/json
+
+< ./order.json
+
+> {% client.global.set("id", response.body.id) %}
+
+###
+POST {{base}}/graphql
+X-Request-Type: GraphQL
+
+query User($id: ID!) { user(id: $id) { name } }
+
+{"id": "1"}
+`)
+
+	spec, err := ParseSpecification(path)
+	if err != nil {
+		t.Fatalf("ParseSpecification failed: %v", err)
+	}
+	if spec.Format != "http" || len(spec.Endpoints) != 3 {
+		t.Fatalf("expected 3 http endpoints, got %s %+v", spec.Format, spec.Endpoints)
+	}
+
+	list := findAsyncEndpoint(t, spec, "GET", "/v1/users")
+	if list.Summary != "List users" {
+		t.Errorf("expected name from the separator, got %q", list.Summary)
+	}
+	if limit := findParam(list.Parameters, "limit"); limit == nil || limit.Examples[0].Value != "10" {
+		t.Errorf("expected query from continuation lines, got %+v", limit)
+	}
+	if !list.RequiresAuth || list.AuthType != "bearer" || findParam(list.Parameters, "Authorization") != nil {
+		t.Errorf("expected Authorization header as bearer auth, got %s %+v", list.AuthType, list.Parameters)
+	}
+
+	order := findAsyncEndpoint(t, spec, "POST", "/v1/orders")
+	if order.Summary != "createOrder" {
+		t.Errorf("expected @name, got %q", order.Summary)
+	}
+	if order.RequestBody != `{"item": "book", "qty": 2}` {
+		t.Errorf("expected body from the included file without the handler, got %q", order.RequestBody)
+	}
+
+	gql := findAsyncEndpoint(t, spec, "POST", "/v1/graphql")
+	if gql.RequestBody != `{"query":"query User($id: ID!) { user(id: $id) { name } }","variables":{"id":"1"}}` {
+		t.Errorf("expected GraphQL body, got %s", gql.RequestBody)
+	}
+	if len(spec.Files) != 2 {
+		t.Errorf("expected environment and included files to be tracked, got %v", spec.Files)
+	}
+}
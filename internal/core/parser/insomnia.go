@@ -0,0 +1,276 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// insomniaItem is a request or folder of an Insomnia export. Version 4
+// exports list resources with parent IDs; version 5 nests children.
+type insomniaItem struct {
+	ID             string         `json:"_id" yaml:"-"`
+	Type           string         `json:"_type" yaml:"-"`
+	ParentID       string         `json:"parentId" yaml:"-"`
+	MetaSortKey    float64        `json:"metaSortKey" yaml:"-"`
+	Name           string         `json:"name" yaml:"name"`
+	Description    string         `json:"description" yaml:"description"`
+	Method         string         `json:"method" yaml:"method"`
+	URL            string         `json:"url" yaml:"url"`
+	Headers        []insomniaPair `json:"headers" yaml:"headers"`
+	Parameters     []insomniaPair `json:"parameters" yaml:"parameters"`
+	PathParameters []insomniaPair `json:"pathParameters" yaml:"pathParameters"`
+	Body           insomniaBody   `json:"body" yaml:"body"`
+	Authentication map[string]any `json:"authentication" yaml:"authentication"`
+	Environment    map[string]any `json:"environment" yaml:"environment"` // folder variables
+	Data           map[string]any `json:"data" yaml:"-"`                  // environment variables
+	Children       []insomniaItem `json:"-" yaml:"children"`
+}
+
+type insomniaPair struct {
+	Name     string `json:"name" yaml:"name"`
+	Value    string `json:"value" yaml:"value"`
+	Disabled bool   `json:"disabled" yaml:"disabled"`
+	Type     string `json:"type" yaml:"type"` // file for multipart file fields
+}
+
+type insomniaBody struct {
+	MimeType string         `json:"mimeType" yaml:"mimeType"`
+	Text     string         `json:"text" yaml:"text"`
+	Params   []insomniaPair `json:"params" yaml:"params"`
+}
+
+// insomniaEnvironment is a version 5 environment with its sub-environments.
+type insomniaEnvironment struct {
+	Name            string                `yaml:"name"`
+	Data            map[string]any        `yaml:"data"`
+	SubEnvironments []insomniaEnvironment `yaml:"subEnvironments"`
+}
+
+// isInsomniaExport reports whether JSON content is an Insomnia v4 export.
+func isInsomniaExport(data map[string]any) bool {
+	t, _ := data["_type"].(string)
+	_, hasResources := data["resources"]
+	return t == "export" && hasResources
+}
+
+// isInsomniaCollection reports whether YAML content is an Insomnia v5
+// collection.
+func isInsomniaCollection(content []byte) bool {
+	for _, line := range strings.SplitN(string(content), "\n", 20) {
+		if strings.HasPrefix(line, "type: collection.insomnia.rest/") {
+			return true
+		}
+	}
+	return false
+}
+
+// parseInsomnia parses an Insomnia export: a v4 JSON export or a v5 YAML
+// collection. Variables come from the base environment, overridden by its
+// first sub-environment and by the variables of enclosing folders.
+func parseInsomnia(content []byte) (*Specification, error) {
+	spec := &Specification{
+		Format:     "insomnia",
+		RawContent: string(content),
+		Endpoints:  []Endpoint{},
+	}
+
+	var items []insomniaItem
+	vars := make(map[string]string)
+	if isInsomniaCollection(content) {
+		var doc struct {
+			Type         string              `yaml:"type"`
+			Collection   []insomniaItem      `yaml:"collection"`
+			Environments insomniaEnvironment `yaml:"environments"`
+		}
+		if err := yaml.Unmarshal(content, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse Insomnia collection: %w", err)
+		}
+		spec.Version = strings.TrimPrefix(doc.Type, "collection.insomnia.rest/")
+		items = doc.Collection
+		flattenVariables(doc.Environments.Data, "", vars)
+		if len(doc.Environments.SubEnvironments) > 0 {
+			flattenVariables(doc.Environments.SubEnvironments[0].Data, "", vars)
+		}
+	} else {
+		var export struct {
+			Format    int            `json:"__export_format"`
+			Resources []insomniaItem `json:"resources"`
+		}
+		if err := json.Unmarshal(content, &export); err != nil {
+			return nil, fmt.Errorf("failed to parse Insomnia export: %w", err)
+		}
+		spec.Version = fmt.Sprint(export.Format)
+		items = insomniaTree(export.Resources, vars)
+	}
+
+	insomniaRequests(items, nil, vars, nil, &spec.Endpoints)
+	return spec, nil
+}
+
+// insomniaTree nests the resources of a v4 export under their workspaces,
+// in Insomnia's order, and collects the variables of its environments.
+func insomniaTree(resources []insomniaItem, vars map[string]string) []insomniaItem {
+	byParent := make(map[string][]insomniaItem)
+	ids := make(map[string]string) // id -> type
+	for _, r := range resources {
+		byParent[r.ParentID] = append(byParent[r.ParentID], r)
+		ids[r.ID] = r.Type
+	}
+	for _, children := range byParent {
+		slices.SortStableFunc(children, func(a, b insomniaItem) int {
+			switch {
+			case a.MetaSortKey < b.MetaSortKey:
+				return -1
+			case a.MetaSortKey > b.MetaSortKey:
+				return 1
+			}
+			return 0
+		})
+	}
+
+	// Base environments belong to a workspace; their sub-environments to
+	// them.
+	for _, r := range resources {
+		if r.Type != "environment" || ids[r.ParentID] != "workspace" {
+			continue
+		}
+		flattenVariables(r.Data, "", vars)
+		for _, sub := range byParent[r.ID] {
+			if sub.Type == "environment" {
+				flattenVariables(sub.Data, "", vars)
+				break
+			}
+		}
+	}
+
+	var build func(parentID string) []insomniaItem
+	build = func(parentID string) []insomniaItem {
+		var items []insomniaItem
+		for _, r := range byParent[parentID] {
+			switch r.Type {
+			case "request_group":
+				r.Children = build(r.ID)
+				items = append(items, r)
+			case "request":
+				items = append(items, r)
+			}
+		}
+		return items
+	}
+	var items []insomniaItem
+	for _, r := range resources {
+		if r.Type == "workspace" {
+			items = append(items, build(r.ID)...)
+		}
+	}
+	return items
+}
+
+// flattenVariables adds environment data to vars. Nested objects are
+// addressed with dots, e.g. {{ _.api.host }}.
+func flattenVariables(data map[string]any, prefix string, vars map[string]string) {
+	for _, k := range slices.Sorted(maps.Keys(data)) {
+		switch v := data[k].(type) {
+		case map[string]any:
+			flattenVariables(v, prefix+k+".", vars)
+		case nil:
+		default:
+			vars[prefix+k] = fmt.Sprint(v)
+		}
+	}
+}
+
+// insomniaRequests appends an endpoint for every request, with folder
+// variables and authentication inherited.
+func insomniaRequests(items []insomniaItem, folder []string, vars map[string]string, auth map[string]any, out *[]Endpoint) {
+	for _, item := range items {
+		if item.Children != nil || item.Type == "request_group" || (item.URL == "" && item.Method == "") {
+			scoped := maps.Clone(vars)
+			flattenVariables(item.Environment, "", scoped)
+			folderAuth := auth
+			if len(item.Authentication) > 0 {
+				folderAuth = item.Authentication
+			}
+			insomniaRequests(item.Children, append(slices.Clone(folder), item.Name), scoped, folderAuth, out)
+			continue
+		}
+
+		sub := func(s string) string { return substituteVariables(s, vars) }
+		req := collectionRequest{
+			Name:        item.Name,
+			Description: item.Description,
+			Folder:      folder,
+			Method:      item.Method,
+			URL:         sub(item.URL),
+			PathParams:  make(map[string]string),
+		}
+		for _, p := range item.Parameters {
+			if !p.Disabled && p.Name != "" {
+				req.Query = append(req.Query, [2]string{p.Name, sub(p.Value)})
+			}
+		}
+		for _, p := range item.PathParameters {
+			req.PathParams[p.Name] = sub(p.Value)
+		}
+		for _, h := range item.Headers {
+			if !h.Disabled && h.Name != "" {
+				req.Headers = append(req.Headers, [2]string{h.Name, sub(h.Value)})
+			}
+		}
+
+		req.ContentType = item.Body.MimeType
+		switch {
+		case item.Body.MimeType == "application/graphql":
+			req.ContentType = "application/json"
+			req.Body = sub(item.Body.Text)
+		case item.Body.MimeType == "application/x-www-form-urlencoded":
+			var fields [][2]string
+			for _, p := range item.Body.Params {
+				if !p.Disabled {
+					fields = append(fields, [2]string{p.Name, sub(p.Value)})
+				}
+			}
+			req.Body = formBody(fields)
+		case item.Body.MimeType == "multipart/form-data":
+			for _, p := range item.Body.Params {
+				if !p.Disabled {
+					req.Form = append(req.Form, [2]string{p.Name, sub(p.Value)})
+				}
+			}
+		default:
+			req.Body = sub(item.Body.Text)
+		}
+
+		authentication := item.Authentication
+		if len(authentication) == 0 {
+			authentication = auth
+		}
+		req.Auth = insomniaAuth(authentication)
+		*out = append(*out, req.endpoint())
+	}
+}
+
+// insomniaAuth maps an Insomnia authentication to its type.
+func insomniaAuth(m map[string]any) *collectionAuth {
+	if disabled, _ := m["disabled"].(bool); disabled {
+		return nil
+	}
+	t, _ := m["type"].(string)
+	switch t {
+	case "bearer", "basic", "digest", "oauth2":
+		return &collectionAuth{Type: t}
+	case "apikey":
+		auth := &collectionAuth{Type: "apikey", In: "header"}
+		auth.Name, _ = m["key"].(string)
+		if addTo, _ := m["addTo"].(string); addTo == "queryParams" {
+			auth.In = "query"
+		}
+		return auth
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"testing"
+)
+
+const testInsomniaExport = `{
+  "_type": "export",
+  "__export_format": 4,
+  "resources": [
+    {"_id": "wrk_1", "_type": "workspace", "name": "Shop"},
+    {"_id": "env_base", "_type": "environment", "parentId": "wrk_1", "data": {"base_url": "https://api.example.com", "api": {"version": "v1"}}},
+    {"_id": "env_dev", "_type": "environment", "parentId": "env_base", "data": {"token": "dev-token"}},
+    {"_id": "fld_1", "_type": "request_group", "parentId": "wrk_1", "name": "Users", "metaSortKey": 1,
+     "environment": {"page_size": "20"}, "authentication": {"type": "bearer", "token": "{{ _.token }}"}},
+    {"_id": "req_2", "_type": "request", "parentId": "fld_1", "name": "Create user", "metaSortKey": 2,
+     "method": "POST", "url": "{{ _.base_url }}/{{ _.api.version }}/users",
+     "headers": [{"name": "Content-Type", "value": "application/json"}, {"name": "X-Trace", "value": "1", "disabled": true}],
+     "body": {"mimeType": "application/json", "text": "{\"name\": \"Ada\"}"}},
+    {"_id": "req_1", "_type": "request", "parentId": "fld_1", "name": "Get user", "metaSortKey": 1,
+     "method": "GET", "url": "{{ _.base_url }}/{{ _.api.version }}/users/:id",
+     "parameters": [{"name": "limit", "value": "{{ _.page_size }}"}],
+     "pathParameters": [{"name": "id", "value": "42"}]},
+    {"_id": "req_3", "_type": "request", "parentId": "wrk_1", "name": "Login", "metaSortKey": 3,
+     "method": "POST", "url": "{{ _.base_url }}/login",
+     "body": {"mimeType": "application/x-www-form-urlencoded", "params": [{"name": "user", "value": "ada"}, {"name": "pass", "value": "a b"}]},
+     "authentication": {"type": "apikey", "key": "X-Api-Key", "value": "k"}}
+  ]
+}`
+
+func TestParseInsomnia_Export(t *testing.T) {
+	spec, err := parseInsomnia([]byte(testInsomniaExport))
+	if err != nil {
+		t.Fatalf("parseInsomnia failed: %v", err)
+	}
+	if spec.Format != "insomnia" || spec.Version != "4" {
+		t.Errorf("expected insomnia 4, got %s %s", spec.Format, spec.Version)
+	}
+	if len(spec.Endpoints) != 3 {
+		t.Fatalf("expected 3 endpoints, got %+v", spec.Endpoints)
+	}
+	if spec.Endpoints[0].Summary != "Get user" || spec.Endpoints[1].Summary != "Create user" {
+		t.Errorf("expected requests in sort order, got %s, %s", spec.Endpoints[0].Summary, spec.Endpoints[1].Summary)
+	}
+
+	get := findAsyncEndpoint(t, spec, "GET", "/v1/users/{id}")
+	if len(get.Tags) != 1 || get.Tags[0] != "Users" {
+		t.Errorf("expected folder tag, got %v", get.Tags)
+	}
+	if id := findParam(get.Parameters, "id"); id == nil || id.In != "path" || len(id.Examples) != 1 || id.Examples[0].Value != "42" {
+		t.Errorf("expected id path parameter with its value, got %+v", id)
+	}
+	if limit := findParam(get.Parameters, "limit"); limit == nil || limit.Examples[0].Value != "20" {
+		t.Errorf("expected limit resolved from folder variables, got %+v", limit)
+	}
+	if !get.RequiresAuth || get.AuthType != "bearer" {
+		t.Errorf("expected bearer auth inherited from the folder, got %v %s", get.RequiresAuth, get.AuthType)
+	}
+
+	create := findAsyncEndpoint(t, spec, "POST", "/v1/users")
+	if findParam(create.Parameters, "X-Trace") != nil {
+		t.Error("expected disabled header to be skipped")
+	}
+	if create.RequestSchema["type"] != "object" || create.RequestBody != `{"name": "Ada"}` {
+		t.Errorf("expected JSON body with inferred schema, got %s %v", create.RequestBody, create.RequestSchema)
+	}
+
+	login := findAsyncEndpoint(t, spec, "POST", "/login")
+	if login.RequestBody != "user=ada&pass=a+b" {
+		t.Errorf("expected form body, got %q", login.RequestBody)
+	}
+	if login.AuthType != "apikey" || login.Security[0].Schemes[0].ParamName != "X-Api-Key" {
+		t.Errorf("expected API key auth, got %s %+v", login.AuthType, login.Security)
+	}
+}
+
+func TestParseSpecification_InsomniaCollection(t *testing.T) {
+	path := writeSpecFile(t, t.TempDir(), "shop.yaml", `type: collection.insomnia.rest/5.0
+name: Shop
+collection:
+  - name: Orders
+    environment:
+      order: "7"
+    children:
+      - name: Get order
+        method: GET
+        url: "{{ _.base_url }}/orders/{{ _.order }}"
+        headers:
+          - name: Accept
+            value: application/json
+environments:
+  name: Base
+  data:
+    base_url: https://api.example.com
+`)
+	spec, err := ParseSpecification(path)
+	if err != nil {
+		t.Fatalf("ParseSpecification failed: %v", err)
+	}
+	if spec.Format != "insomnia" || spec.Version != "5.0" {
+		t.Errorf("expected insomnia 5.0, got %s %s", spec.Format, spec.Version)
+	}
+	ep := findAsyncEndpoint(t, spec, "GET", "/orders/7")
+	if len(ep.Tags) != 1 || ep.Tags[0] != "Orders" {
+		t.Errorf("expected folder tag, got %v", ep.Tags)
+	}
+	if accept := findParam(ep.Parameters, "Accept"); accept == nil || accept.In != "header" {
+		t.Errorf("expected Accept header, got %+v", accept)
+	}
+}
@@ -0,0 +1,438 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// MediaType is a request or response body in one content type.
+type MediaType struct {
+	ContentType string         `json:"content_type"`
+	Schema      map[string]any `json:"schema,omitempty"`
+	Examples    []Example      `json:"examples,omitempty"`
+}
+
+// Example is a named example of a parameter or body. Unnamed examples
+// (example instead of examples) are called "default".
+type Example struct {
+	Name          string `json:"name"`
+	Summary       string `json:"summary,omitempty"`
+	Description   string `json:"description,omitempty"`
+	Value         any    `json:"value,omitempty"`
+	ExternalValue string `json:"external_value,omitempty"`
+}
+
+// schemaKeywords are the Swagger 2.0 parameter fields that belong to its schema.
+var schemaKeywords = []string{"type", "format", "items", "enum", "default", "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "minLength", "maxLength", "pattern", "minItems", "maxItems", "uniqueItems", "multipleOf"}
+
+// parseOperation fills the endpoint from an operation object: its
+// identification, parameters, request body and responses.
+func parseOperation(endpoint *Endpoint, r *refResolver, pathItem, op map[string]any) {
+	endpoint.OperationID, _ = op["operationId"].(string)
+	endpoint.Summary, _ = op["summary"].(string)
+	endpoint.Deprecated, _ = op["deprecated"].(bool)
+	if tags, ok := op["tags"].([]any); ok {
+		for _, tag := range tags {
+			if s, ok := tag.(string); ok {
+				endpoint.Tags = append(endpoint.Tags, s)
+			}
+		}
+	}
+
+	endpoint.Parameters = parseParameters(r, pathItem["parameters"], op["parameters"])
+
+	if body, ok := r.deref(op["requestBody"]); ok {
+		endpoint.RequestBodyRequired, _ = body["required"].(bool)
+		endpoint.RequestContent = parseContent(r, body["content"])
+	} else {
+		endpoint.RequestContent, endpoint.RequestBodyRequired = swaggerRequestContent(r, op, pathItem)
+	}
+	if media, ok := preferredMediaType(endpoint.RequestContent); ok {
+		if media.Schema != nil && isJSON(media.ContentType) {
+			endpoint.RequestSchema = media.Schema
+		}
+		if len(media.Examples) > 0 {
+			endpoint.RequestBody = exampleBody(media.Examples[0].Value)
+		}
+	}
+
+	responses, _ := op["responses"].(map[string]any)
+	for statusCode, respData := range responses {
+		resp, ok := r.deref(respData)
+		if !ok {
+			continue
+		}
+		if desc, ok := resp["description"].(string); ok {
+			endpoint.Responses[statusCode] = desc
+		}
+		content := parseContent(r, resp["content"])
+		if content == nil {
+			content = swaggerResponseContent(r, op, resp)
+		}
+		if len(content) == 0 {
+			continue
+		}
+		if endpoint.ResponseContent == nil {
+			endpoint.ResponseContent = make(map[string][]MediaType)
+		}
+		endpoint.ResponseContent[statusCode] = content
+
+		for _, media := range content {
+			if !isJSON(media.ContentType) {
+				continue
+			}
+			if media.Schema != nil {
+				if _, ok := endpoint.ResponseSchemas[statusCode]; !ok {
+					endpoint.ResponseSchemas[statusCode] = media.Schema
+				}
+			}
+			if len(media.Examples) > 0 {
+				if endpoint.ResponseExamples == nil {
+					endpoint.ResponseExamples = make(map[string]any)
+				}
+				if _, ok := endpoint.ResponseExamples[statusCode]; !ok {
+					endpoint.ResponseExamples[statusCode] = media.Examples[0].Value
+				}
+			}
+		}
+	}
+}
+
+// parseParameters merges path-level and operation-level parameters; an
+// operation parameter overrides a path parameter with the same name and location.
+func parseParameters(r *refResolver, pathParams, opParams any) []Parameter {
+	var out []Parameter
+	index := make(map[string]int)
+	for _, list := range []any{pathParams, opParams} {
+		items, _ := list.([]any)
+		for _, item := range items {
+			p, ok := r.deref(item)
+			if !ok {
+				continue
+			}
+			param := Parameter{}
+			param.Name, _ = p["name"].(string)
+			param.In, _ = p["in"].(string)
+			if param.Name == "" || param.In == "body" {
+				continue
+			}
+			param.Required, _ = p["required"].(bool)
+			param.Description, _ = p["description"].(string)
+			param.Deprecated, _ = p["deprecated"].(bool)
+
+			if schema := r.schema(p["schema"]); schema != nil {
+				param.Schema = schema
+			} else if _, ok := p["type"]; ok {
+				// Swagger 2.0 describes the schema on the parameter itself.
+				param.Schema = make(map[string]any)
+				for _, key := range schemaKeywords {
+					if v, ok := p[key]; ok {
+						param.Schema[key] = v
+					}
+				}
+			}
+			param.Type, _ = param.Schema["type"].(string)
+			param.Format, _ = param.Schema["format"].(string)
+			param.Enum, _ = param.Schema["enum"].([]any)
+			param.Default = param.Schema["default"]
+
+			param.Examples = parseExamples(r, p)
+			if len(param.Examples) == 0 {
+				if example, ok := param.Schema["example"]; ok {
+					param.Examples = []Example{{Name: "default", Value: example}}
+				} else if example, ok := p["x-example"]; ok {
+					param.Examples = []Example{{Name: "default", Value: example}}
+				}
+			}
+
+			key := param.In + ":" + param.Name
+			if i, ok := index[key]; ok {
+				out[i] = param
+				continue
+			}
+			index[key] = len(out)
+			out = append(out, param)
+		}
+	}
+	return out
+}
+
+// parseContent reads an OpenAPI 3.x content map, JSON media types first.
+func parseContent(r *refResolver, v any) []MediaType {
+	content, ok := v.(map[string]any)
+	if !ok {
+		return nil
+	}
+	out := make([]MediaType, 0, len(content))
+	for contentType, mediaData := range content {
+		media := MediaType{ContentType: contentType}
+		if mediaMap, ok := mediaData.(map[string]any); ok {
+			media.Schema = r.schema(mediaMap["schema"])
+			media.Examples = parseExamples(r, mediaMap)
+			if len(media.Examples) == 0 {
+				if example, ok := media.Schema["example"]; ok {
+					media.Examples = []Example{{Name: "default", Value: example}}
+				}
+			}
+		}
+		out = append(out, media)
+	}
+	sortMediaTypes(out)
+	return out
+}
+
+// parseExamples reads example and the named examples of a parameter or
+// media type object, sorted by name.
+func parseExamples(r *refResolver, m map[string]any) []Example {
+	var out []Example
+	if example, ok := m["example"]; ok {
+		out = append(out, Example{Name: "default", Value: example})
+	}
+	examples, _ := m["examples"].(map[string]any)
+	names := make([]string, 0, len(examples))
+	for name := range examples {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		ex, ok := r.deref(examples[name])
+		if !ok {
+			continue
+		}
+		example := Example{Name: name, Value: ex["value"]}
+		example.Summary, _ = ex["summary"].(string)
+		example.Description, _ = ex["description"].(string)
+		example.ExternalValue, _ = ex["externalValue"].(string)
+		out = append(out, example)
+	}
+	return out
+}
+
+// mediaTypes returns the consumes or produces list of an operation, falling
+// back to the document's.
+func mediaTypes(r *refResolver, op map[string]any, key string) []string {
+	list, ok := op[key].([]any)
+	if !ok {
+		root, _ := r.docs[""].(map[string]any)
+		list, _ = root[key].([]any)
+	}
+	var out []string
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	if len(out) == 0 {
+		out = []string{"application/json"}
+	}
+	return out
+}
+
+// swaggerRequestContent builds the request content of a Swagger 2.0
+// operation from its body or formData parameters.
+func swaggerRequestContent(r *refResolver, op, pathItem map[string]any) ([]MediaType, bool) {
+	var params []map[string]any
+	for _, list := range []any{pathItem["parameters"], op["parameters"]} {
+		items, _ := list.([]any)
+		for _, item := range items {
+			if p, ok := r.deref(item); ok {
+				params = append(params, p)
+			}
+		}
+	}
+
+	var schema map[string]any
+	var required bool
+	var examples []Example
+	form := map[string]any{"type": "object", "properties": map[string]any{}}
+	var formRequired []any
+	for _, p := range params {
+		switch p["in"] {
+		case "body":
+			schema = r.schema(p["schema"])
+			required, _ = p["required"].(bool)
+			if example, ok := p["x-example"]; ok {
+				examples = []Example{{Name: "default", Value: example}}
+			} else if example, ok := schema["example"]; ok {
+				examples = []Example{{Name: "default", Value: example}}
+			}
+		case "formData":
+			name, _ := p["name"].(string)
+			prop := make(map[string]any)
+			for _, key := range schemaKeywords {
+				if v, ok := p[key]; ok {
+					prop[key] = v
+				}
+			}
+			form["properties"].(map[string]any)[name] = prop
+			if r, _ := p["required"].(bool); r {
+				formRequired = append(formRequired, name)
+				required = true
+			}
+		}
+	}
+
+	consumes := mediaTypes(r, op, "consumes")
+	var out []MediaType
+	switch {
+	case schema != nil:
+		for _, contentType := range consumes {
+			out = append(out, MediaType{ContentType: contentType, Schema: schema, Examples: examples})
+		}
+	case len(form["properties"].(map[string]any)) > 0:
+		if len(formRequired) > 0 {
+			form["required"] = formRequired
+		}
+		for _, contentType := range consumes {
+			if strings.Contains(contentType, "form") {
+				out = append(out, MediaType{ContentType: contentType, Schema: form})
+			}
+		}
+		if len(out) == 0 {
+			out = append(out, MediaType{ContentType: "application/x-www-form-urlencoded", Schema: form})
+		}
+	}
+	sortMediaTypes(out)
+	return out, required
+}
+
+// swaggerResponseContent builds the content of a Swagger 2.0 response from
+// its schema, examples and the operation's produces list.
+func swaggerResponseContent(r *refResolver, op, resp map[string]any) []MediaType {
+	schema := r.schema(resp["schema"])
+	examples, _ := resp["examples"].(map[string]any)
+	if schema == nil && len(examples) == 0 {
+		return nil
+	}
+	var out []MediaType
+	for _, contentType := range mediaTypes(r, op, "produces") {
+		media := MediaType{ContentType: contentType, Schema: schema}
+		if example, ok := examples[contentType]; ok {
+			media.Examples = []Example{{Name: "default", Value: example}}
+		}
+		out = append(out, media)
+	}
+	for contentType, example := range examples {
+		if !slices.ContainsFunc(out, func(m MediaType) bool { return m.ContentType == contentType }) {
+			out = append(out, MediaType{ContentType: contentType, Examples: []Example{{Name: "default", Value: example}}})
+		}
+	}
+	sortMediaTypes(out)
+	return out
+}
+
+// sortMediaTypes orders JSON media types first, then by name.
+func sortMediaTypes(media []MediaType) {
+	slices.SortFunc(media, func(a, b MediaType) int {
+		if aj, bj := isJSON(a.ContentType), isJSON(b.ContentType); aj != bj {
+			if aj {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.ContentType, b.ContentType)
+	})
+}
+
+func isJSON(contentType string) bool {
+	return strings.Contains(contentType, "json")
+}
+
+// preferredMediaType returns the media type requests are sent with.
+func preferredMediaType(media []MediaType) (MediaType, bool) {
+	if len(media) == 0 {
+		return MediaType{}, false
+	}
+	return media[0], true
+}
+
+// exampleBody renders an example value as a request body.
+func exampleBody(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	if v == nil {
+		return ""
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// SampleValue returns a value for the parameter from its examples, default
+// or enum, or false if the spec gives none.
+func (p Parameter) SampleValue() (string, bool) {
+	var v any
+	switch {
+	case len(p.Examples) > 0 && p.Examples[0].Value != nil:
+		v = p.Examples[0].Value
+	case p.Default != nil:
+		v = p.Default
+	case len(p.Enum) > 0:
+		v = p.Enum[0]
+	default:
+		return "", false
+	}
+	if s, ok := v.(string); ok {
+		return s, true
+	}
+	return fmt.Sprint(v), true
+}
+
+// SampleRequest builds a request for the endpoint from the spec's examples:
+// path parameters are filled in, required query and header parameters added
+// and the example body sent with its content type. Parameters without a
+// sample value are left as they are.
+func (ep Endpoint) SampleRequest() (path string, headers map[string]string, body string) {
+	path = ep.Path
+	headers = make(map[string]string)
+	var query []string
+	for _, p := range ep.Parameters {
+		value, ok := p.SampleValue()
+		if !ok {
+			continue
+		}
+		switch p.In {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+p.Name+"}", value)
+		case "query":
+			if p.Required {
+				query = append(query, p.Name+"="+value)
+			}
+		case "header":
+			if p.Required {
+				headers[p.Name] = value
+			}
+		}
+	}
+	if len(query) > 0 {
+		path += "?" + strings.Join(query, "&")
+	}
+
+	body = ep.RequestBody
+	if media, ok := preferredMediaType(ep.RequestContent); ok && body != "" {
+		headers["Content-Type"] = media.ContentType
+	}
+	return path, headers, body
+}
+
+// FindEndpoint returns the endpoint a request goes to, or nil. A literal
+// template such as /users/me wins over /users/{id}.
+func FindEndpoint(endpoints []Endpoint, method, path string) *Endpoint {
+	path, _, _ = strings.Cut(path, "?")
+	var match *Endpoint
+	for i := range endpoints {
+		ep := &endpoints[i]
+		if !strings.EqualFold(ep.Method, method) || !MatchPath(ep.Path, path) {
+			continue
+		}
+		if match == nil || ep.Path == path {
+			match = ep
+		}
+	}
+	return match
+}
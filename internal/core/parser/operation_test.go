@@ -0,0 +1,179 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseOpenAPI_OperationModel(t *testing.T) {
+	content := `
+openapi: "3.0.0"
+components:
+  parameters:
+    Status:
+      name: status
+      in: query
+      required: true
+      schema: {type: string, enum: [active, banned], default: active}
+  requestBodies:
+    NewUser:
+      required: true
+      content:
+        application/xml:
+          schema: {type: object}
+        application/json:
+          schema: {$ref: "#/components/schemas/User"}
+          examples:
+            minimal: {$ref: "#/components/examples/Minimal"}
+  examples:
+    Minimal:
+      summary: Only the name
+      value: {name: Ann}
+  schemas:
+    User:
+      type: object
+      properties:
+        name: {type: string}
+paths:
+  /orgs/{org}/users:
+    parameters:
+      - name: org
+        in: path
+        required: true
+        schema: {type: string}
+        example: acme
+    post:
+      operationId: createUser
+      summary: Create a user
+      tags: [users, admin]
+      deprecated: true
+      parameters:
+        - $ref: "#/components/parameters/Status"
+      requestBody: {$ref: "#/components/requestBodies/NewUser"}
+      responses:
+        "201":
+          description: Created
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/User"}
+              example: {name: Ann}
+`
+	spec, err := parseOpenAPI([]byte(content))
+	if err != nil {
+		t.Fatalf("parseOpenAPI failed: %v", err)
+	}
+	ep := spec.Endpoints[0]
+	if ep.OperationID != "createUser" || ep.Summary != "Create a user" || !ep.Deprecated {
+		t.Errorf("unexpected operation fields: %q %q %v", ep.OperationID, ep.Summary, ep.Deprecated)
+	}
+	if strings.Join(ep.Tags, ",") != "users,admin" {
+		t.Errorf("expected tags users,admin, got %v", ep.Tags)
+	}
+
+	if len(ep.Parameters) != 2 {
+		t.Fatalf("expected 2 parameters, got %+v", ep.Parameters)
+	}
+	status := ep.Parameters[1]
+	if status.Name != "status" || status.Type != "string" || len(status.Enum) != 2 || status.Default != "active" {
+		t.Errorf("expected referenced status parameter with enum and default, got %+v", status)
+	}
+	if len(ep.Parameters[0].Examples) != 1 || ep.Parameters[0].Examples[0].Value != "acme" {
+		t.Errorf("expected org example, got %+v", ep.Parameters[0].Examples)
+	}
+
+	if !ep.RequestBodyRequired || len(ep.RequestContent) != 2 {
+		t.Fatalf("expected required body with 2 media types, got %+v", ep.RequestContent)
+	}
+	json := ep.RequestContent[0]
+	if json.ContentType != "application/json" || json.Schema["type"] != "object" {
+		t.Errorf("expected JSON media type first with resolved schema, got %+v", json)
+	}
+	if len(json.Examples) != 1 || json.Examples[0].Name != "minimal" || json.Examples[0].Summary != "Only the name" {
+		t.Errorf("expected referenced named example, got %+v", json.Examples)
+	}
+	if ep.RequestBody != `{"name":"Ann"}` || ep.RequestSchema["type"] != "object" {
+		t.Errorf("expected example request body and schema, got %q %v", ep.RequestBody, ep.RequestSchema)
+	}
+
+	if ep.ResponseContent["201"][0].ContentType != "application/json" || ep.ResponseSchemas["201"]["type"] != "object" {
+		t.Errorf("expected 201 response content, got %+v", ep.ResponseContent)
+	}
+	if example, _ := ep.ResponseExamples["201"].(map[string]any); example["name"] != "Ann" {
+		t.Errorf("expected 201 example, got %v", ep.ResponseExamples)
+	}
+
+	path, headers, body := ep.SampleRequest()
+	if path != "/orgs/acme/users?status=active" {
+		t.Errorf("expected sample path with parameters, got %q", path)
+	}
+	if headers["Content-Type"] != "application/json" || body != `{"name":"Ann"}` {
+		t.Errorf("expected JSON sample body, got %v %q", headers, body)
+	}
+}
+
+func TestParseOpenAPI_SwaggerOperationModel(t *testing.T) {
+	content := `{
+  "swagger": "2.0",
+  "consumes": ["application/x-www-form-urlencoded"],
+  "paths": {
+    "/login": {
+      "post": {
+        "operationId": "login",
+        "parameters": [
+          {"name": "user", "in": "formData", "type": "string", "required": true},
+          {"name": "mode", "in": "query", "type": "string", "enum": ["fast", "slow"]}
+        ],
+        "produces": ["application/json"],
+        "responses": {
+          "200": {
+            "description": "OK",
+            "schema": {"type": "object"},
+            "examples": {"application/json": {"token": "t"}}
+          }
+        }
+      }
+    }
+  }
+}`
+	spec, err := parseOpenAPI([]byte(content))
+	if err != nil {
+		t.Fatalf("parseOpenAPI failed: %v", err)
+	}
+	ep := spec.Endpoints[0]
+	if len(ep.RequestContent) != 1 || ep.RequestContent[0].ContentType != "application/x-www-form-urlencoded" || !ep.RequestBodyRequired {
+		t.Fatalf("expected required form body, got %+v", ep.RequestContent)
+	}
+	props, _ := ep.RequestContent[0].Schema["properties"].(map[string]any)
+	if _, ok := props["user"]; !ok {
+		t.Errorf("expected user form field, got %v", ep.RequestContent[0].Schema)
+	}
+
+	var mode *Parameter
+	for i := range ep.Parameters {
+		if ep.Parameters[i].Name == "mode" {
+			mode = &ep.Parameters[i]
+		}
+	}
+	if mode == nil || mode.Type != "string" || len(mode.Enum) != 2 {
+		t.Errorf("expected mode parameter with enum, got %+v", ep.Parameters)
+	}
+	if example, _ := ep.ResponseExamples["200"].(map[string]any); example["token"] != "t" {
+		t.Errorf("expected Swagger response example, got %v", ep.ResponseExamples)
+	}
+}
+
+func TestFindEndpoint(t *testing.T) {
+	endpoints := []Endpoint{
+		{Method: "GET", Path: "/users/{id}"},
+		{Method: "GET", Path: "/users/me"},
+	}
+	if ep := FindEndpoint(endpoints, "get", "/users/me?full=1"); ep == nil || ep.Path != "/users/me" {
+		t.Errorf("expected literal path to win, got %+v", ep)
+	}
+	if ep := FindEndpoint(endpoints, "GET", "/users/42"); ep == nil || ep.Path != "/users/{id}" {
+		t.Errorf("expected template match, got %+v", ep)
+	}
+	if ep := FindEndpoint(endpoints, "DELETE", "/users/42"); ep != nil {
+		t.Errorf("expected no match, got %+v", ep)
+	}
+}
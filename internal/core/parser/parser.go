@@ -2,6 +2,7 @@ package parser
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -12,34 +13,83 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Octrafic/octrafic-cli/internal/core/graphql"
 	"gopkg.in/yaml.v3"
 )
 
+// ErrUnsupportedFormat is returned for files no native parser understands.
+var ErrUnsupportedFormat = errors.New("unsupported file format")
+
 type Specification struct {
-	Format     string     `json:"format"`
-	Version    string     `json:"version,omitempty"`
-	Endpoints  []Endpoint `json:"endpoints"`
-	RawContent string     `json:"raw_content"`
+	Format          string                    `json:"format"`
+	Version         string                    `json:"version,omitempty"`
+	Endpoints       []Endpoint                `json:"endpoints"`
+	SecuritySchemes map[string]SecurityScheme `json:"security_schemes,omitempty"`
+	Files           []string                  `json:"files,omitempty"` // local files referenced with $ref
+	RawContent      string                    `json:"raw_content"`
 }
 
 type Endpoint struct {
-	Method          string                    `json:"method"`
-	Path            string                    `json:"path"`
-	Description     string                    `json:"description"`
-	Parameters      []Parameter               `json:"parameters,omitempty"`
-	RequestBody     string                    `json:"request_body,omitempty"`
-	Responses       map[string]string         `json:"responses,omitempty"`
-	ResponseSchemas map[string]map[string]any `json:"response_schemas,omitempty"`
-	RequiresAuth    bool                      `json:"requires_auth"`
-	AuthType        string                    `json:"auth_type"` // "bearer", "basic", "apikey", "none"
+	Method              string                    `json:"method"`
+	Path                string                    `json:"path"`
+	OperationID         string                    `json:"operation_id,omitempty"`
+	Summary             string                    `json:"summary,omitempty"`
+	Description         string                    `json:"description"`
+	Tags                []string                  `json:"tags,omitempty"`
+	Deprecated          bool                      `json:"deprecated,omitempty"`
+	Parameters          []Parameter               `json:"parameters,omitempty"`
+	RequestBody         string                    `json:"request_body,omitempty"` // example body, ready to send
+	RequestBodyRequired bool                      `json:"request_body_required,omitempty"`
+	RequestContent      []MediaType               `json:"request_content,omitempty"` // JSON first
+	Responses           map[string]string         `json:"responses,omitempty"`
+	ResponseSchemas     map[string]map[string]any `json:"response_schemas,omitempty"`
+	ResponseContent     map[string][]MediaType    `json:"response_content,omitempty"` // status -> media types
+	RequestSchema       map[string]any            `json:"request_schema,omitempty"`
+	ResponseExamples    map[string]any            `json:"response_examples,omitempty"` // status -> example body
+	RequiresAuth        bool                      `json:"requires_auth"`
+	AuthType            string                    `json:"auth_type"`          // "bearer", "basic", "apikey", "none"
+	Security            []SecurityRequirement     `json:"security,omitempty"` // alternatives; the endpoint needs one of them
+	GraphQL             *GraphQLOperation         `json:"graphql,omitempty"`
+	AsyncAPI            *AsyncOperation           `json:"asyncapi,omitempty"`
+	Callbacks           []Callback                `json:"callbacks,omitempty"`
+	Webhook             *Callback                 `json:"webhook,omitempty"`
+}
+
+// Callback is a request the API sends to a client, from an operation's
+// callbacks or the top-level webhooks of OpenAPI 3.1. For webhooks, Path on
+// the enclosing Endpoint is a synthetic identifier and nothing is executed.
+type Callback struct {
+	Name        string         `json:"name"`
+	URL         string         `json:"url,omitempty"` // runtime expression, e.g. {$request.body#/callback_url}
+	Method      string         `json:"method"`
+	Description string         `json:"description,omitempty"`
+	Schema      map[string]any `json:"schema,omitempty"` // payload schema
+}
+
+// GraphQLOperation describes how to execute a GraphQL endpoint. Path on the
+// enclosing Endpoint is a synthetic identifier; requests go to Endpoint.
+type GraphQLOperation struct {
+	Endpoint      string         `json:"endpoint"`
+	OperationType string         `json:"operation_type"` // "query" or "mutation"
+	Field         string         `json:"field"`
+	OperationName string         `json:"operation_name,omitempty"`
+	Query         string         `json:"query"`
+	Variables     map[string]any `json:"variables,omitempty"`
+	ReturnType    string         `json:"return_type,omitempty"`
 }
 
 type Parameter struct {
-	Name        string `json:"name"`
-	In          string `json:"in"`
-	Type        string `json:"type"`
-	Required    bool   `json:"required"`
-	Description string `json:"description"`
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Type        string         `json:"type"`
+	Format      string         `json:"format,omitempty"`
+	Required    bool           `json:"required"`
+	Deprecated  bool           `json:"deprecated,omitempty"`
+	Description string         `json:"description"`
+	Schema      map[string]any `json:"schema,omitempty"`
+	Enum        []any          `json:"enum,omitempty"`
+	Default     any            `json:"default,omitempty"`
+	Examples    []Example      `json:"examples,omitempty"`
 }
 
 func ParseSpecification(path string) (*Specification, error) {
@@ -47,24 +97,45 @@ func ParseSpecification(path string) (*Specification, error) {
 	var err error
 	var isURL bool
 
-	if isURL = isHTTPURL(path); isURL {
+	if isURL = isHTTPURL(path); isURL && isGraphQLURL(path) {
+		return introspectGraphQL(path)
+	}
+
+	if isURL {
 		content, err = fetchFromURL(path)
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch spec from URL: %w", err)
 		}
 	} else {
+		// Bruno collections are directories of .bru files.
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			return parseBruno(path)
+		}
 		content, err = os.ReadFile(path)
 		if err != nil {
 			return nil, fmt.Errorf("failed to read file: %w", err)
 		}
 	}
 
+	location := path
+	if !isURL {
+		if abs, err := filepath.Abs(path); err == nil {
+			location = abs
+		}
+	}
+
 	ext := strings.ToLower(filepath.Ext(path))
 	if isURL && ext == "" {
 		ext = detectFormatFromContent(content)
 	}
 
 	if ext == ".json" {
+		if !isURL && filepath.Base(path) == "bruno.json" {
+			return parseBruno(path)
+		}
+		if graphql.IsIntrospection(content) {
+			return parseGraphQLIntrospection(content)
+		}
 		var data map[string]any
 		if err := json.Unmarshal(content, &data); err == nil {
 			if info, ok := data["info"].(map[string]any); ok {
@@ -75,25 +146,59 @@ func ParseSpecification(path string) (*Specification, error) {
 					return parsePostman(content)
 				}
 			}
+			if isHAR(data) {
+				return ParseHAR(content, nil)
+			}
+			if isInsomniaExport(data) {
+				return parseInsomnia(content)
+			}
+			if _, ok := data["asyncapi"]; ok {
+				return parseAsyncAPI(content, location)
+			}
 			if _, ok := data["openapi"]; ok {
-				return parseOpenAPI(content)
+				return parseOpenAPIAt(content, location)
 			}
 			if _, ok := data["swagger"]; ok {
-				return parseOpenAPI(content)
+				return parseOpenAPIAt(content, location)
 			}
 		}
-		return parseOpenAPI(content)
+		return parseOpenAPIAt(content, location)
 	}
 
 	switch ext {
 	case ".md", ".markdown":
+		if isBlueprint(content) {
+			return parseBlueprint(string(content))
+		}
 		return parseMarkdown(string(content))
+	case ".har":
+		return ParseHAR(content, nil)
+	case ".bru":
+		if isURL {
+			return nil, fmt.Errorf("failed to parse %s: Bruno collections must be local", path)
+		}
+		return parseBruno(path)
+	case ".http", ".rest":
+		return parseHTTPFile(content, location)
+	case ".apib":
+		return parseBlueprint(string(content))
+	case ".raml":
+		return parseRAML(content, location)
 	case ".yaml", ".yml":
-		return parseOpenAPI(content)
+		if isAsyncAPI(content) {
+			return parseAsyncAPI(content, location)
+		}
+		if isRAML(content) {
+			return parseRAML(content, location)
+		}
+		if isInsomniaCollection(content) {
+			return parseInsomnia(content)
+		}
+		return parseOpenAPIAt(content, location)
 	case ".graphql", ".gql":
 		return parseGraphQL(string(content))
 	default:
-		return nil, fmt.Errorf("unsupported file format: %s", ext)
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, ext)
 	}
 }
 
@@ -140,6 +245,12 @@ func parseMarkdown(content string) (*Specification, error) {
 }
 
 func parseOpenAPI(content []byte) (*Specification, error) {
+	return parseOpenAPIAt(content, "")
+}
+
+// parseOpenAPIAt parses an OpenAPI document read from location, a file path
+// or URL that relative $ref locations are resolved against.
+func parseOpenAPIAt(content []byte, location string) (*Specification, error) {
 	var openapi map[string]any
 
 	if err := json.Unmarshal(content, &openapi); err != nil {
@@ -158,27 +269,21 @@ func parseOpenAPI(content []byte) (*Specification, error) {
 		spec.Version = version
 	}
 
-	definitions := make(map[string]any)
-	if components, ok := openapi["components"].(map[string]any); ok {
-		if schemas, ok := components["schemas"].(map[string]any); ok {
-			for k, v := range schemas {
-				definitions[k] = v
-			}
-		}
-	}
-	if defs, ok := openapi["definitions"].(map[string]any); ok {
-		for k, v := range defs {
-			definitions[k] = v
-		}
+	refs, err := newRefResolver(openapi, location)
+	if err != nil {
+		return nil, err
 	}
+	spec.Files = refs.files
 
+	spec.SecuritySchemes = parseSecuritySchemes(openapi, refs)
 	globalSecurity := hasSecurityRequirement(openapi["security"])
+	globalRequirements := parseSecurity(openapi["security"], spec.SecuritySchemes)
 
 	if paths, ok := openapi["paths"].(map[string]any); ok {
 		for path, methods := range paths {
-			if methodMap, ok := methods.(map[string]any); ok {
+			if methodMap, ok := refs.deref(methods); ok {
 				for method, details := range methodMap {
-					if method == "parameters" || method == "summary" || method == "description" {
+					if !isHTTPMethod(strings.ToUpper(method)) {
 						continue
 					}
 					endpoint := Endpoint{
@@ -200,23 +305,17 @@ func parseOpenAPI(content []byte) (*Specification, error) {
 
 						if _, hasSecurity := detailsMap["security"]; hasSecurity {
 							endpoint.RequiresAuth = hasSecurityRequirement(detailsMap["security"])
+							endpoint.Security = parseSecurity(detailsMap["security"], spec.SecuritySchemes)
 						} else {
 							endpoint.RequiresAuth = globalSecurity
+							endpoint.Security = globalRequirements
 						}
-
-						if responses, ok := detailsMap["responses"].(map[string]any); ok {
-							for statusCode, respData := range responses {
-								if respMap, ok := respData.(map[string]any); ok {
-									if desc, ok := respMap["description"].(string); ok {
-										endpoint.Responses[statusCode] = desc
-									}
-									schema := extractResponseSchema(respMap, definitions)
-									if schema != nil {
-										endpoint.ResponseSchemas[statusCode] = schema
-									}
-								}
-							}
+						if endpoint.RequiresAuth {
+							endpoint.AuthType = securityAuthType(endpoint.Security)
 						}
+
+						parseOperation(&endpoint, refs, methodMap, detailsMap)
+						endpoint.Callbacks = parseCallbacks(detailsMap["callbacks"], refs)
 					}
 
 					spec.Endpoints = append(spec.Endpoints, endpoint)
@@ -225,359 +324,230 @@ func parseOpenAPI(content []byte) (*Specification, error) {
 		}
 	}
 
+	if webhooks, ok := openapi["webhooks"].(map[string]any); ok {
+		for name, item := range webhooks {
+			for _, cb := range callbackOperations(name, "", item, refs) {
+				spec.Endpoints = append(spec.Endpoints, Endpoint{
+					Method:      cb.Method,
+					Path:        "/webhooks/" + name,
+					Description: cb.Description,
+					AuthType:    "none",
+					Webhook:     &cb,
+				})
+			}
+		}
+	}
+
 	return spec, nil
 }
 
-// hasSecurityRequirement returns true if the security value is a non-empty array
-// containing at least one non-empty security requirement object.
-func hasSecurityRequirement(v any) bool {
-	reqs, ok := v.([]any)
-	if !ok || len(reqs) == 0 {
-		return false
+// parseCallbacks reads an operation's callbacks object:
+// name -> URL expression -> path item.
+func parseCallbacks(v any, refs *refResolver) []Callback {
+	callbacks, ok := v.(map[string]any)
+	if !ok {
+		return nil
 	}
-	for _, req := range reqs {
-		if m, ok := req.(map[string]any); ok && len(m) > 0 {
-			return true
+	var out []Callback
+	for name, expressions := range callbacks {
+		exprMap, ok := refs.deref(expressions)
+		if !ok {
+			continue
 		}
-	}
-	return false
-}
-
-// extractResponseSchema pulls the JSON schema from a response object.
-// Handles both OpenAPI 3.x (content/application/json/schema) and Swagger 2.0 (schema).
-func extractResponseSchema(response map[string]any, definitions map[string]any) map[string]any {
-	if content, ok := response["content"].(map[string]any); ok {
-		for mediaType, mediaData := range content {
-			if strings.Contains(mediaType, "json") {
-				if mediaMap, ok := mediaData.(map[string]any); ok {
-					if schema, ok := mediaMap["schema"].(map[string]any); ok {
-						return resolveRefs(schema, definitions, 0)
-					}
-				}
-			}
+		for expr, item := range exprMap {
+			out = append(out, callbackOperations(name, expr, item, refs)...)
 		}
 	}
-	if schema, ok := response["schema"].(map[string]any); ok {
-		return resolveRefs(schema, definitions, 0)
-	}
-	return nil
+	slices.SortFunc(out, func(a, b Callback) int {
+		return strings.Compare(a.Name+a.Method, b.Name+b.Method)
+	})
+	return out
 }
 
-// resolveRefs recursively resolves $ref pointers in a JSON schema.
-// depth limits recursion to guard against circular references.
-func resolveRefs(schema map[string]any, definitions map[string]any, depth int) map[string]any {
-	if depth > 10 {
-		return schema
-	}
-	if ref, ok := schema["$ref"].(string); ok {
-		parts := strings.Split(ref, "/")
-		name := parts[len(parts)-1]
-		if def, ok := definitions[name].(map[string]any); ok {
-			return resolveRefs(def, definitions, depth+1)
+// callbackOperations returns one Callback per method of a path item.
+func callbackOperations(name, expr string, item any, refs *refResolver) []Callback {
+	itemMap, ok := refs.deref(item)
+	if !ok {
+		return nil
+	}
+	var out []Callback
+	for method, op := range itemMap {
+		opMap, ok := op.(map[string]any)
+		if !ok || !isHTTPMethod(strings.ToUpper(method)) {
+			continue
 		}
-		return schema
-	}
-
-	result := make(map[string]any, len(schema))
-	for k, v := range schema {
-		switch val := v.(type) {
-		case map[string]any:
-			result[k] = resolveRefs(val, definitions, depth+1)
-		case []any:
-			resolved := make([]any, len(val))
-			for i, item := range val {
-				if itemMap, ok := item.(map[string]any); ok {
-					resolved[i] = resolveRefs(itemMap, definitions, depth+1)
-				} else {
-					resolved[i] = item
-				}
+		cb := Callback{Name: name, URL: expr, Method: strings.ToUpper(method)}
+		cb.Description, _ = opMap["description"].(string)
+		if cb.Description == "" {
+			cb.Description, _ = opMap["summary"].(string)
+		}
+		if body, ok := refs.deref(opMap["requestBody"]); ok {
+			if media, ok := preferredMediaType(parseContent(refs, body["content"])); ok && isJSON(media.ContentType) {
+				cb.Schema = media.Schema
 			}
-			result[k] = resolved
-		default:
-			result[k] = v
 		}
+		out = append(out, cb)
 	}
-	return result
+	return out
 }
 
-func isHTTPMethod(s string) bool {
-	methods := []string{"GET", "POST", "PUT", "DELETE", "PATCH", "HEAD", "OPTIONS"}
-	return slices.Contains(methods, s)
-}
-
-func parsePostman(content []byte) (*Specification, error) {
-	var collection struct {
-		Info struct {
-			Name   string `json:"name"`
-			Schema string `json:"schema"`
-		} `json:"info"`
-		Item []postmanItem `json:"item"`
-	}
-
-	if err := json.Unmarshal(content, &collection); err != nil {
-		return nil, fmt.Errorf("failed to parse Postman collection: %w", err)
+// MatchPath checks if a concrete path matches an OpenAPI path template.
+// Segments wrapped in {} are treated as wildcards.
+func MatchPath(template, actual string) bool {
+	if template == actual {
+		return true
 	}
-
-	spec := &Specification{
-		Format:     "postman",
-		RawContent: string(content),
-		Endpoints:  []Endpoint{},
-	}
-
-	if strings.Contains(collection.Info.Schema, "v2.1") {
-		spec.Version = "2.1"
-	} else if strings.Contains(collection.Info.Schema, "v2.0") {
-		spec.Version = "2.0"
+	tParts := strings.Split(strings.Trim(template, "/"), "/")
+	aParts := strings.Split(strings.Trim(actual, "/"), "/")
+	if len(tParts) != len(aParts) {
+		return false
 	}
-
-	extractPostmanItems(collection.Item, &spec.Endpoints)
-
-	return spec, nil
-}
-
-type postmanItem struct {
-	Name    string          `json:"name"`
-	Request *postmanRequest `json:"request,omitempty"`
-	Item    []postmanItem   `json:"item,omitempty"` // Nested folders
-}
-
-type postmanRequest struct {
-	Method      string          `json:"method"`
-	Header      []postmanHeader `json:"header"`
-	URL         any             `json:"url"` // Can be string or object
-	Body        *postmanBody    `json:"body,omitempty"`
-	Description string          `json:"description,omitempty"`
-}
-
-type postmanHeader struct {
-	Key   string `json:"key"`
-	Value string `json:"value"`
-}
-
-type postmanBody struct {
-	Mode string `json:"mode"`
-	Raw  string `json:"raw,omitempty"`
-}
-
-func extractPostmanItems(items []postmanItem, endpoints *[]Endpoint) {
-	for _, item := range items {
-		if len(item.Item) > 0 {
-			extractPostmanItems(item.Item, endpoints)
+	for i, tp := range tParts {
+		if strings.HasPrefix(tp, "{") && strings.HasSuffix(tp, "}") {
 			continue
 		}
-
-		if item.Request != nil {
-			endpoint := Endpoint{
-				Method:      strings.ToUpper(item.Request.Method),
-				Path:        extractPostmanURL(item.Request.URL),
-				Description: item.Name,
-				Responses:   make(map[string]string),
-			}
-
-			if item.Request.Description != "" {
-				endpoint.Description = item.Request.Description
-			}
-
-			for _, header := range item.Request.Header {
-				headerKey := strings.ToLower(header.Key)
-				if headerKey == "authorization" {
-					endpoint.RequiresAuth = true
-					if strings.HasPrefix(strings.ToLower(header.Value), "bearer") {
-						endpoint.AuthType = "bearer"
-					} else if strings.HasPrefix(strings.ToLower(header.Value), "basic") {
-						endpoint.AuthType = "basic"
-					}
-				}
-				if headerKey == "x-api-key" || headerKey == "api-key" {
-					endpoint.RequiresAuth = true
-					endpoint.AuthType = "apikey"
-				}
-			}
-
-			if item.Request.Body != nil && item.Request.Body.Raw != "" {
-				endpoint.RequestBody = item.Request.Body.Raw
-			}
-
-			*endpoints = append(*endpoints, endpoint)
+		if !strings.EqualFold(tp, aParts[i]) {
+			return false
 		}
 	}
+	return true
 }
 
-func extractPostmanURL(url any) string {
-	switch v := url.(type) {
-	case string:
-		return extractPathFromURL(v)
-	case map[string]any:
-		// URL object format
-		if raw, ok := v["raw"].(string); ok {
-			return extractPathFromURL(raw)
-		}
-		// Build from path array
-		if pathArr, ok := v["path"].([]any); ok {
-			var parts []string
-			for _, p := range pathArr {
-				if s, ok := p.(string); ok {
-					parts = append(parts, s)
-				}
-			}
-			return "/" + strings.Join(parts, "/")
+// hasSecurityRequirement returns true if the security value is a non-empty array
+// containing at least one non-empty security requirement object.
+func hasSecurityRequirement(v any) bool {
+	reqs, ok := v.([]any)
+	if !ok || len(reqs) == 0 {
+		return false
+	}
+	for _, req := range reqs {
+		if m, ok := req.(map[string]any); ok && len(m) > 0 {
+			return true
 		}
 	}
-	return "/"
+	return false
 }
 
-func extractPathFromURL(rawURL string) string {
-	if idx := strings.Index(rawURL, "}}"); idx != -1 {
-		rawURL = rawURL[idx+2:]
-	}
-	if strings.HasPrefix(rawURL, "http") {
-		parts := strings.SplitN(rawURL, "/", 4)
-		if len(parts) >= 4 {
-			return "/" + parts[3]
-		}
-		return "/"
+func isHTTPMethod(s string) bool {
+	methods := []string{"GET", "POST", "PUT", "DELETE", "PATCH", "HEAD", "OPTIONS"}
+	return slices.Contains(methods, s)
+}
+
+func parseGraphQL(content string) (*Specification, error) {
+	schema, err := graphql.ParseSDL(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse GraphQL schema: %w", err)
 	}
-	if !strings.HasPrefix(rawURL, "/") {
-		return "/" + rawURL
+	return graphQLSpecification(schema, content), nil
+}
+
+func parseGraphQLIntrospection(content []byte) (*Specification, error) {
+	schema, err := graphql.FromIntrospection(content)
+	if err != nil {
+		return nil, err
 	}
-	return rawURL
+	return graphQLSpecification(schema, string(content)), nil
 }
 
-func parseGraphQL(content string) (*Specification, error) {
+// graphQLSpecification turns every query and mutation root field into an
+// endpoint carrying a ready-to-run operation.
+func graphQLSpecification(schema *graphql.Schema, raw string) *Specification {
 	spec := &Specification{
 		Format:     "graphql",
-		RawContent: content,
+		RawContent: raw,
 		Endpoints:  []Endpoint{},
 	}
 
-	lines := strings.Split(content, "\n")
-	var currentType string // "Query" or "Mutation"
-	var braceDepth int
-	var currentField strings.Builder
-	var inType bool
-
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-
-		if strings.HasPrefix(trimmed, "type Query") && (strings.Contains(trimmed, "{") || strings.Contains(trimmed, "implements")) {
-			currentType = "Query"
-			inType = true
-			braceDepth = strings.Count(trimmed, "{") - strings.Count(trimmed, "}")
-			continue
-		}
-		if strings.HasPrefix(trimmed, "type Mutation") && (strings.Contains(trimmed, "{") || strings.Contains(trimmed, "implements")) {
-			currentType = "Mutation"
-			inType = true
-			braceDepth = strings.Count(trimmed, "{") - strings.Count(trimmed, "}")
-			continue
-		}
-
-		if !inType {
-			continue
-		}
-
-		for _, c := range trimmed {
-			switch c {
-			case '{':
-				braceDepth++
-			case '}':
-				braceDepth--
-				if braceDepth <= 0 {
-					inType = false
-					currentType = ""
-				}
-			}
-		}
-
-		if trimmed == "{" || trimmed == "}" {
-			continue
-		}
-
-		if braceDepth > 0 && currentType != "" && !strings.HasPrefix(trimmed, "#") {
-			currentField.WriteString(trimmed)
-
-			fieldStr := currentField.String()
-			if strings.Contains(fieldStr, ":") {
-				endpoint := parseGraphQLField(fieldStr, currentType)
-				if endpoint != nil {
-					spec.Endpoints = append(spec.Endpoints, *endpoint)
-				}
-				currentField.Reset()
+	for _, opType := range []string{"query", "mutation"} {
+		for _, field := range schema.RootFields(opType) {
+			if strings.HasPrefix(field.Name, "__") {
+				continue
 			}
+			spec.Endpoints = append(spec.Endpoints, graphQLEndpoint(schema, opType, field))
 		}
 	}
 
-	return spec, nil
+	return spec
 }
 
-func parseGraphQLField(field string, queryType string) *Endpoint {
-	field = strings.TrimSpace(field)
-
-	var name string
-	var args string
-	var description string
-
-	if idx := strings.Index(field, "#"); idx != -1 {
-		description = strings.TrimSpace(field[idx+1:])
-		field = strings.TrimSpace(field[:idx])
-	}
-
-	if parenIdx := strings.Index(field, "("); parenIdx != -1 {
-		name = strings.TrimSpace(field[:parenIdx])
-		closeIdx := strings.Index(field, ")")
-		if closeIdx != -1 && closeIdx > parenIdx {
-			args = field[parenIdx+1 : closeIdx]
-		}
-	} else if colonIdx := strings.Index(field, ":"); colonIdx != -1 {
-		name = strings.TrimSpace(field[:colonIdx])
-	}
-
-	if name == "" {
-		return nil
-	}
-
+func graphQLEndpoint(schema *graphql.Schema, opType string, field graphql.Field) Endpoint {
+	// Queries map to GET and mutations to POST so they read naturally in
+	// endpoint listings; the operation itself is always sent as a POST.
+	queryType := "Mutation"
 	method := "POST"
-	path := "/graphql"
-
-	if queryType == "Query" {
+	if opType == "query" {
+		queryType = "Query"
 		method = "GET"
 	}
 
-	endpoint := &Endpoint{
+	endpoint := Endpoint{
 		Method:      method,
-		Path:        path + "/" + name,
-		Description: fmt.Sprintf("GraphQL %s: %s", queryType, name),
+		Path:        "/graphql/" + field.Name,
+		Description: fmt.Sprintf("GraphQL %s: %s", queryType, field.Name),
 		Responses:   make(map[string]string),
 	}
+	if field.Description != "" {
+		endpoint.Description = field.Description
+	}
+
+	for _, arg := range field.Args {
+		endpoint.Parameters = append(endpoint.Parameters, Parameter{
+			Name:        arg.Name,
+			In:          "body",
+			Type:        strings.TrimSuffix(arg.Type.String(), "!"),
+			Required:    arg.Type.IsNonNull(),
+			Description: arg.Description,
+		})
+	}
+
+	if op, err := schema.BuildOperation(opType, field.Name, graphql.DefaultDepth); err == nil {
+		endpoint.GraphQL = &GraphQLOperation{
+			Endpoint:      "/graphql",
+			OperationType: op.Type,
+			Field:         op.Field,
+			OperationName: op.Name,
+			Query:         op.Document,
+			Variables:     op.Variables,
+			ReturnType:    op.ReturnType,
+		}
+		endpoint.Responses["200"] = op.ReturnType
+		endpoint.ResponseSchemas = map[string]map[string]any{"200": op.ResponseSchema}
+	}
 
-	if description != "" {
-		endpoint.Description = description
+	return endpoint
+}
+
+// isGraphQLURL reports whether a URL points at a GraphQL endpoint rather
+// than a schema document: its last path segment is exactly "graphql", as in
+// https://api.example.com/graphql. Schema files such as schema.graphql are
+// fetched as SDL.
+func isGraphQLURL(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return false
 	}
+	segments := strings.Split(strings.TrimSuffix(parsed.Path, "/"), "/")
+	return segments[len(segments)-1] == "graphql"
+}
 
-	if args != "" {
-		for _, arg := range strings.Split(args, ",") {
-			arg = strings.TrimSpace(arg)
-			if arg == "" {
-				continue
-			}
-			parts := strings.SplitN(arg, ":", 2)
-			if len(parts) == 2 {
-				paramName := strings.TrimSpace(parts[0])
-				paramType := strings.TrimSpace(parts[1])
-				required := strings.HasSuffix(paramType, "!")
-				paramType = strings.TrimSuffix(paramType, "!")
-
-				endpoint.Parameters = append(endpoint.Parameters, Parameter{
-					Name:     paramName,
-					In:       "body",
-					Type:     paramType,
-					Required: required,
-				})
+// introspectGraphQL builds a specification from a live GraphQL endpoint.
+func introspectGraphQL(endpointURL string) (*Specification, error) {
+	content, err := graphql.Introspect(endpointURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to introspect GraphQL endpoint: %w", err)
+	}
+	spec, err := parseGraphQLIntrospection(content)
+	if err != nil {
+		return nil, err
+	}
+	if parsed, err := url.Parse(endpointURL); err == nil && parsed.Path != "" {
+		for i := range spec.Endpoints {
+			if spec.Endpoints[i].GraphQL != nil {
+				spec.Endpoints[i].GraphQL.Endpoint = parsed.Path
 			}
 		}
 	}
-
-	return endpoint
+	return spec, nil
 }
 
 func isHTTPURL(path string) bool {
@@ -618,5 +588,11 @@ func detectFormatFromContent(content []byte) string {
 	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
 		return ".json"
 	}
+	if strings.HasPrefix(trimmed, "#%RAML") {
+		return ".raml"
+	}
+	if strings.HasPrefix(trimmed, "FORMAT:") {
+		return ".apib"
+	}
 	return ".yaml"
 }
@@ -2,6 +2,9 @@ package parser
 
 import (
 	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -231,6 +234,108 @@ paths:
 	}
 }
 
+func TestParseOpenAPI_ParametersAndExamples(t *testing.T) {
+	content := `
+openapi: "3.0.0"
+paths:
+  /users/{id}:
+    parameters:
+      - {name: id, in: path, required: true, schema: {type: integer}}
+    put:
+      parameters:
+        - {name: dryRun, in: query, schema: {type: boolean}}
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name: {type: string}
+      responses:
+        "200":
+          description: Updated
+          content:
+            application/json:
+              examples:
+                second: {value: {name: "B"}}
+                first: {value: {name: "A"}}
+`
+	spec, err := parseOpenAPI([]byte(content))
+	if err != nil {
+		t.Fatalf("parseOpenAPI failed: %v", err)
+	}
+	if len(spec.Endpoints) != 1 {
+		t.Fatalf("expected 1 endpoint (path-level parameters are not a method), got %d", len(spec.Endpoints))
+	}
+	ep := spec.Endpoints[0]
+	if len(ep.Parameters) != 2 || ep.Parameters[0].Name != "id" || ep.Parameters[0].Type != "integer" || !ep.Parameters[0].Required {
+		t.Errorf("unexpected parameters: %+v", ep.Parameters)
+	}
+	if ep.RequestSchema["type"] != "object" {
+		t.Errorf("expected request schema, got %v", ep.RequestSchema)
+	}
+	example, _ := ep.ResponseExamples["200"].(map[string]any)
+	if example["name"] != "A" {
+		t.Errorf("expected first named example, got %v", ep.ResponseExamples)
+	}
+}
+
+func TestParseOpenAPI_CallbacksAndWebhooks(t *testing.T) {
+	content := `
+openapi: "3.1.0"
+paths:
+  /payments:
+    post:
+      summary: Create payment
+      callbacks:
+        paymentStatus:
+          "{$request.body#/callback_url}":
+            post:
+              summary: Payment status changed
+              requestBody:
+                content:
+                  application/json:
+                    schema:
+                      $ref: "#/components/schemas/PaymentEvent"
+webhooks:
+  refundIssued:
+    post:
+      description: A refund was issued
+components:
+  schemas:
+    PaymentEvent:
+      type: object
+      required: [status]
+      properties:
+        status:
+          type: string
+`
+	spec, err := parseOpenAPI([]byte(content))
+	if err != nil {
+		t.Fatalf("parseOpenAPI failed: %v", err)
+	}
+	if len(spec.Endpoints) != 2 {
+		t.Fatalf("expected payment endpoint and webhook, got %d endpoints", len(spec.Endpoints))
+	}
+
+	payment := spec.Endpoints[0]
+	if len(payment.Callbacks) != 1 {
+		t.Fatalf("expected 1 callback, got %+v", payment.Callbacks)
+	}
+	cb := payment.Callbacks[0]
+	if cb.Name != "paymentStatus" || cb.URL != "{$request.body#/callback_url}" || cb.Method != "POST" {
+		t.Errorf("unexpected callback: %+v", cb)
+	}
+	if cb.Schema["type"] != "object" || cb.Description != "Payment status changed" {
+		t.Errorf("expected resolved payload schema, got %+v", cb)
+	}
+
+	webhook := spec.Endpoints[1]
+	if webhook.Webhook == nil || webhook.Path != "/webhooks/refundIssued" || webhook.Method != "POST" {
+		t.Errorf("unexpected webhook endpoint: %+v", webhook)
+	}
+}
+
 func TestParsePostman(t *testing.T) {
 	collection := map[string]any{
 		"info": map[string]any{
@@ -412,50 +517,109 @@ type User {
 	}
 }
 
-func TestParseGraphQLField(t *testing.T) {
-	// Simple field
-	ep := parseGraphQLField("users: [User]", "Query")
-	if ep == nil {
-		t.Fatal("expected non-nil endpoint")
+func TestParseGraphQL_Operations(t *testing.T) {
+	content := `
+type Query {
+  user(id: ID!): User
+}
+
+type User {
+  id: ID!
+  name: String
+}
+`
+	spec, err := parseGraphQL(content)
+	if err != nil {
+		t.Fatalf("parseGraphQL failed: %v", err)
+	}
+	if len(spec.Endpoints) != 1 {
+		t.Fatalf("expected 1 endpoint, got %d", len(spec.Endpoints))
+	}
+	op := spec.Endpoints[0].GraphQL
+	if op == nil {
+		t.Fatal("expected GraphQL operation on endpoint")
+	}
+	if op.Endpoint != "/graphql" || op.OperationType != "query" || op.Field != "user" {
+		t.Errorf("unexpected operation: %+v", op)
+	}
+	if !strings.Contains(op.Query, "user(id: $id)") || !strings.Contains(op.Query, "name") {
+		t.Errorf("unexpected query:\n%s", op.Query)
 	}
-	if ep.Method != "GET" {
-		t.Errorf("expected GET for Query, got %q", ep.Method)
+	if op.Variables["id"] != "1" {
+		t.Errorf("expected sample id variable, got %v", op.Variables["id"])
 	}
-	if ep.Path != "/graphql/users" {
-		t.Errorf("expected path '/graphql/users', got %q", ep.Path)
+	if _, ok := spec.Endpoints[0].ResponseSchemas["200"]; !ok {
+		t.Error("expected response schema for 200")
 	}
 
-	// Field with args
-	ep = parseGraphQLField("createUser(name: String!, email: String): User", "Mutation")
-	if ep == nil {
-		t.Fatal("expected non-nil endpoint")
+	if _, err := parseGraphQL("type Query { users: [User }"); err == nil {
+		t.Error("expected error for invalid SDL")
 	}
-	if ep.Method != "POST" {
-		t.Errorf("expected POST for Mutation, got %q", ep.Method)
+}
+
+func TestParseGraphQL_Fields(t *testing.T) {
+	content := `
+type Query {
+  users: [User] # Get all users
+}
+
+type Mutation {
+  createUser(name: String!, email: String): User
+}
+
+type User {
+  id: ID
+}
+`
+	spec, err := parseGraphQL(content)
+	if err != nil {
+		t.Fatalf("parseGraphQL failed: %v", err)
 	}
-	if len(ep.Parameters) != 2 {
-		t.Fatalf("expected 2 parameters, got %d", len(ep.Parameters))
+	if len(spec.Endpoints) != 2 {
+		t.Fatalf("expected 2 endpoints, got %d", len(spec.Endpoints))
 	}
-	if !ep.Parameters[0].Required {
-		t.Error("expected name parameter to be required (has !)")
+
+	users := spec.Endpoints[0]
+	if users.Method != "GET" || users.Path != "/graphql/users" {
+		t.Errorf("expected GET /graphql/users, got %s %s", users.Method, users.Path)
 	}
-	if ep.Parameters[1].Required {
-		t.Error("expected email parameter to not be required")
+	if users.Description != "Get all users" {
+		t.Errorf("expected description from the comment, got %q", users.Description)
 	}
 
-	// Field with comment
-	ep = parseGraphQLField("users: [User] # Get all users", "Query")
-	if ep == nil {
-		t.Fatal("expected non-nil endpoint")
+	create := spec.Endpoints[1]
+	if create.Method != "POST" || len(create.Parameters) != 2 {
+		t.Fatalf("expected POST with 2 parameters, got %s %+v", create.Method, create.Parameters)
 	}
-	if ep.Description != "Get all users" {
-		t.Errorf("expected description 'Get all users', got %q", ep.Description)
+	if !create.Parameters[0].Required || create.Parameters[1].Required {
+		t.Errorf("expected only name to be required (has !), got %+v", create.Parameters)
 	}
 
-	// Empty field
-	ep = parseGraphQLField(": String", "Query")
-	if ep != nil {
-		t.Error("expected nil for field with no name")
+	if _, err := parseGraphQL("type Query {\n: String\n}"); err == nil {
+		t.Error("expected error for a field with no name")
+	}
+}
+
+func TestParseSpecification_GraphQLIntrospection(t *testing.T) {
+	content := `{"data":{"__schema":{"queryType":{"name":"Query"},"types":[
+		{"kind":"OBJECT","name":"Query","fields":[
+			{"name":"ping","args":[],"type":{"kind":"SCALAR","name":"String","ofType":null}}
+		]}
+	]}}}`
+	path := filepath.Join(t.TempDir(), "schema.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	spec, err := ParseSpecification(path)
+	if err != nil {
+		t.Fatalf("ParseSpecification failed: %v", err)
+	}
+	if spec.Format != "graphql" {
+		t.Errorf("expected format 'graphql', got %q", spec.Format)
+	}
+	if len(spec.Endpoints) != 1 || spec.Endpoints[0].Path != "/graphql/ping" {
+		t.Fatalf("unexpected endpoints: %+v", spec.Endpoints)
 	}
 }
 
@@ -482,6 +646,27 @@ func TestIsHTTPURL(t *testing.T) {
 	}
 }
 
+func TestIsGraphQLURL(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"https://api.example.com/graphql", true},
+		{"https://api.example.com/v1/graphql/", true},
+		{"https://api.example.com/graphql?x=1", true},
+		{"https://api.example.com/schema.graphql", false},
+		{"https://api.example.com/schema.gql", false},
+		{"https://api.example.com/mygraphql", false},
+		{"https://api.example.com/graphql/schema", false},
+	}
+
+	for _, tt := range tests {
+		if got := isGraphQLURL(tt.input); got != tt.expected {
+			t.Errorf("isGraphQLURL(%q) = %v, want %v", tt.input, got, tt.expected)
+		}
+	}
+}
+
 func TestDetectFormatFromContent(t *testing.T) {
 	tests := []struct {
 		content  string
@@ -503,6 +688,51 @@ func TestDetectFormatFromContent(t *testing.T) {
 	}
 }
 
+func TestMatchPath_Exact(t *testing.T) {
+	if !MatchPath("/users", "/users") {
+		t.Error("expected exact match")
+	}
+}
+
+func TestMatchPath_WithParam(t *testing.T) {
+	if !MatchPath("/users/{id}", "/users/42") {
+		t.Error("expected param segment to match any value")
+	}
+}
+
+func TestMatchPath_MultipleParams(t *testing.T) {
+	if !MatchPath("/users/{id}/orders/{orderId}", "/users/1/orders/99") {
+		t.Error("expected multiple params to match")
+	}
+}
+
+func TestMatchPath_DifferentSegmentCount(t *testing.T) {
+	if MatchPath("/users/{id}", "/users") {
+		t.Error("expected no match for different segment count")
+	}
+}
+
+func TestMatchPath_LiteralMismatch(t *testing.T) {
+	if MatchPath("/users/{id}", "/products/42") {
+		t.Error("expected no match when literal segment differs")
+	}
+}
+
+func TestMatchPath_CaseInsensitive(t *testing.T) {
+	if !MatchPath("/Users/{id}", "/users/1") {
+		t.Error("expected case-insensitive match on literal segments")
+	}
+}
+
+func testResolver(t *testing.T, root map[string]any) *refResolver {
+	t.Helper()
+	r, err := newRefResolver(root, "")
+	if err != nil {
+		t.Fatalf("newRefResolver failed: %v", err)
+	}
+	return r
+}
+
 func TestResolveRefs_NoRef(t *testing.T) {
 	schema := map[string]any{
 		"type": "object",
@@ -510,23 +740,27 @@ func TestResolveRefs_NoRef(t *testing.T) {
 			"id": map[string]any{"type": "string"},
 		},
 	}
-	result := resolveRefs(schema, nil, 0)
+	result := testResolver(t, map[string]any{}).schema(schema)
 	if result["type"] != "object" {
 		t.Errorf("expected type=object, got %v", result["type"])
 	}
 }
 
 func TestResolveRefs_WithRef(t *testing.T) {
-	definitions := map[string]any{
-		"User": map[string]any{
-			"type": "object",
-			"properties": map[string]any{
-				"name": map[string]any{"type": "string"},
+	root := map[string]any{
+		"components": map[string]any{
+			"schemas": map[string]any{
+				"User": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"name": map[string]any{"type": "string"},
+					},
+				},
 			},
 		},
 	}
 	schema := map[string]any{"$ref": "#/components/schemas/User"}
-	result := resolveRefs(schema, definitions, 0)
+	result := testResolver(t, root).schema(schema)
 	if result["type"] != "object" {
 		t.Errorf("expected resolved type=object, got %v", result["type"])
 	}
@@ -534,39 +768,60 @@ func TestResolveRefs_WithRef(t *testing.T) {
 
 func TestResolveRefs_UnknownRef(t *testing.T) {
 	schema := map[string]any{"$ref": "#/components/schemas/Missing"}
-	result := resolveRefs(schema, map[string]any{}, 0)
+	result := testResolver(t, map[string]any{}).schema(schema)
 	if result["$ref"] == nil {
 		t.Error("expected $ref to remain when definition not found")
 	}
 }
 
-func TestResolveRefs_DepthLimit(t *testing.T) {
-	schema := map[string]any{"type": "string"}
-	result := resolveRefs(schema, nil, 11)
-	if result["type"] != "string" {
-		t.Errorf("expected schema returned as-is at depth limit, got %v", result)
+func TestResolveRefs_Recursive(t *testing.T) {
+	root := map[string]any{
+		"definitions": map[string]any{
+			"Node": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"children": map[string]any{
+						"type":  "array",
+						"items": map[string]any{"$ref": "#/definitions/Node"},
+					},
+				},
+			},
+		},
+	}
+	result := testResolver(t, root).schema(map[string]any{"$ref": "#/definitions/Node"})
+	if result["type"] != "object" {
+		t.Fatalf("expected resolved type=object, got %v", result)
+	}
+	children := result["properties"].(map[string]any)["children"].(map[string]any)
+	if ref := children["items"].(map[string]any)["$ref"]; ref != "#/$defs/Node" {
+		t.Errorf("expected recursive reference to $defs, got %v", ref)
+	}
+	defs, _ := result["$defs"].(map[string]any)
+	if node, _ := defs["Node"].(map[string]any); node["type"] != "object" {
+		t.Errorf("expected Node in $defs, got %v", result["$defs"])
+	}
+	if _, err := json.Marshal(result); err != nil {
+		t.Errorf("expected recursive schema to marshal, got %v", err)
 	}
 }
 
 func TestExtractResponseSchema_OpenAPI3(t *testing.T) {
-	response := map[string]any{
-		"content": map[string]any{
-			"application/json": map[string]any{
-				"schema": map[string]any{
-					"type": "object",
-					"properties": map[string]any{
-						"id": map[string]any{"type": "integer"},
-					},
+	content := map[string]any{
+		"application/json": map[string]any{
+			"schema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"id": map[string]any{"type": "integer"},
 				},
 			},
 		},
 	}
-	schema := extractResponseSchema(response, nil)
-	if schema == nil {
+	media := parseContent(testResolver(t, map[string]any{}), content)
+	if len(media) != 1 || media[0].Schema == nil {
 		t.Fatal("expected non-nil schema")
 	}
-	if schema["type"] != "object" {
-		t.Errorf("expected type=object, got %v", schema["type"])
+	if media[0].Schema["type"] != "object" {
+		t.Errorf("expected type=object, got %v", media[0].Schema["type"])
 	}
 }
 
@@ -579,45 +834,47 @@ func TestExtractResponseSchema_Swagger2(t *testing.T) {
 			},
 		},
 	}
-	schema := extractResponseSchema(response, nil)
-	if schema == nil {
+	media := swaggerResponseContent(testResolver(t, map[string]any{}), map[string]any{}, response)
+	if len(media) != 1 || media[0].Schema == nil {
 		t.Fatal("expected non-nil schema")
 	}
-	if schema["type"] != "array" {
-		t.Errorf("expected type=array, got %v", schema["type"])
+	if media[0].Schema["type"] != "array" {
+		t.Errorf("expected type=array, got %v", media[0].Schema["type"])
 	}
 }
 
 func TestExtractResponseSchema_NoSchema(t *testing.T) {
-	schema := extractResponseSchema(map[string]any{}, nil)
-	if schema != nil {
-		t.Errorf("expected nil for response with no schema, got %v", schema)
+	media := swaggerResponseContent(testResolver(t, map[string]any{}), map[string]any{}, map[string]any{})
+	if media != nil {
+		t.Errorf("expected nil for response with no schema, got %v", media)
 	}
 }
 
 func TestExtractResponseSchema_WithRef(t *testing.T) {
-	definitions := map[string]any{
-		"Product": map[string]any{
-			"type": "object",
-			"properties": map[string]any{
-				"price": map[string]any{"type": "number"},
+	root := map[string]any{
+		"components": map[string]any{
+			"schemas": map[string]any{
+				"Product": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"price": map[string]any{"type": "number"},
+					},
+				},
 			},
 		},
 	}
-	response := map[string]any{
-		"content": map[string]any{
-			"application/json": map[string]any{
-				"schema": map[string]any{
-					"$ref": "#/components/schemas/Product",
-				},
+	content := map[string]any{
+		"application/json": map[string]any{
+			"schema": map[string]any{
+				"$ref": "#/components/schemas/Product",
 			},
 		},
 	}
-	schema := extractResponseSchema(response, definitions)
-	if schema == nil {
+	media := parseContent(testResolver(t, root), content)
+	if len(media) != 1 || media[0].Schema == nil {
 		t.Fatal("expected non-nil schema after ref resolution")
 	}
-	if schema["type"] != "object" {
-		t.Errorf("expected resolved type=object, got %v", schema["type"])
+	if media[0].Schema["type"] != "object" {
+		t.Errorf("expected resolved type=object, got %v", media[0].Schema["type"])
 	}
 }
@@ -0,0 +1,1283 @@
+package parser
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"mime/multipart"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// PostmanCollection is a Postman collection (v2.0 or v2.1) read by
+// ReadPostman, with its requests in run order.
+type PostmanCollection struct {
+	Name      string
+	Version   string
+	Requests  []PostmanRequest
+	Variables map[string]string // collection variables, overridden by the environment
+	Report    []string          // what could not be translated
+}
+
+// PostmanRequest is a request of a collection with its variables resolved.
+// Variables that test scripts of earlier requests set from a response are
+// left as {{name}} and extracted when the requests run, as in suites.
+type PostmanRequest struct {
+	Name           string
+	Folder         []string
+	Method         string
+	BaseURL        string // scheme and host; empty when the URL starts with an undefined variable
+	Path           string // path and query as sent
+	Headers        map[string]string
+	Body           string
+	Auth           *PostmanAuth // inherited from folders and the collection; nil for none
+	ExpectedStatus int
+	Assertions     []PostmanAssertion
+	Extract        []PostmanExtract
+	Endpoint       Endpoint
+}
+
+// PostmanAuth is a Postman auth block, e.g. bearer with a token parameter.
+type PostmanAuth struct {
+	Type   string
+	Params map[string]string
+}
+
+// PostmanAssertion is a pm.expect check translated to an Octrafic
+// assertion operator.
+type PostmanAssertion struct {
+	Field string
+	Op    string
+	Value any
+}
+
+// PostmanExtract is a variable a test script sets from the response body.
+type PostmanExtract struct {
+	Field string
+	As    string
+}
+
+// postmanAuthTypes are the auth types Octrafic has a provider for.
+var postmanAuthTypes = []string{"bearer", "basic", "apikey", "oauth2", "awsv4"}
+
+// postmanLanguages maps the language of a raw body to its content type.
+var postmanLanguages = map[string]string{
+	"json":       "application/json",
+	"xml":        "application/xml",
+	"html":       "text/html",
+	"javascript": "application/javascript",
+	"text":       "text/plain",
+}
+
+// postmanChainWords are Chai language chains, which do not change an
+// assertion.
+var postmanChainWords = []string{"to", "be", "been", "is", "that", "which", "and", "has", "have", "with", "at", "of", "same", "but", "does", "still", "also", "deep", "nested", "own"}
+
+var (
+	postmanTestBlock = regexp.MustCompile(`pm\.test\(\s*(?:'[^']*'|"[^"]*"|` + "`[^`]*`" + `)\s*,\s*(?:(?:async\s+)?function\s*\(\s*\)|\(\s*\)\s*=>)\s*\{`)
+	postmanTestArrow = regexp.MustCompile(`^pm\.test\(\s*(?:'[^']*'|"[^"]*"|` + "`[^`]*`" + `)\s*,\s*\(\s*\)\s*=>\s*(.+)\)$`)
+	postmanAlias     = regexp.MustCompile(`^(?:var|let|const)\s+([A-Za-z_$][\w$]*)\s*=\s*(.+)$`)
+	postmanSet       = regexp.MustCompile(`^(?:pm\.(?:environment|collectionVariables|globals|variables)\.set|postman\.set(?:Environment|Global)Variable)\(\s*['"]([^'"]+)['"]\s*,\s*(.+)\)$`)
+	postmanLegacy    = regexp.MustCompile(`^tests\[.*\]\s*=\s*responseCode\.code\s*===?\s*(\d{3})$`)
+	postmanIgnored   = regexp.MustCompile(`^(?:console\.\w+|pm\.(?:environment|collectionVariables|globals|variables)\.unset|postman\.clear(?:Environment|Global)Variable)\(`)
+	postmanRoot      = regexp.MustCompile(`^(?:pm\.response\.json\(\)|JSON\.parse\(\s*(?:responseBody|pm\.response\.text\(\))\s*\)|([A-Za-z_$][\w$]*))`)
+	postmanAccessor  = regexp.MustCompile(`^(?:\.([A-Za-z_$][\w$]*)|\[\s*(\d+)\s*\]|\[\s*(?:'([^']*)'|"([^"]*)")\s*\])`)
+)
+
+type postmanCollection struct {
+	Info struct {
+		Name   string `json:"name"`
+		Schema string `json:"schema"`
+	} `json:"info"`
+	Item     []postmanItem  `json:"item"`
+	Auth     *PostmanAuth   `json:"auth,omitempty"`
+	Event    []postmanEvent `json:"event,omitempty"`
+	Variable []postmanPair  `json:"variable,omitempty"`
+}
+
+type postmanItem struct {
+	Name     string            `json:"name"`
+	Request  *postmanRequest   `json:"request,omitempty"`
+	Response []postmanResponse `json:"response,omitempty"` // Saved examples
+	Item     []postmanItem     `json:"item,omitempty"`     // Nested folders
+	Auth     *PostmanAuth      `json:"auth,omitempty"`     // Folder auth
+	Event    []postmanEvent    `json:"event,omitempty"`
+}
+
+type postmanRequest struct {
+	Method      string         `json:"method"`
+	Header      postmanHeaders `json:"header"`
+	URL         any            `json:"url"` // Can be string or object
+	Body        *postmanBody   `json:"body,omitempty"`
+	Auth        *PostmanAuth   `json:"auth,omitempty"`
+	Description postmanText    `json:"description,omitempty"`
+}
+
+// postmanPair is a header, query parameter, form field or variable.
+type postmanPair struct {
+	Key      string       `json:"key"`
+	ID       string       `json:"id"` // v2.0 variables
+	Value    postmanValue `json:"value"`
+	Disabled bool         `json:"disabled"`
+	Enabled  *bool        `json:"enabled"` // environment values
+	Type     string       `json:"type"`    // form fields: text or file
+}
+
+type postmanBody struct {
+	Mode       string        `json:"mode"`
+	Raw        string        `json:"raw,omitempty"`
+	URLEncoded []postmanPair `json:"urlencoded,omitempty"`
+	FormData   []postmanPair `json:"formdata,omitempty"`
+	GraphQL    *struct {
+		Query     string       `json:"query"`
+		Variables postmanValue `json:"variables"`
+	} `json:"graphql,omitempty"`
+	Options struct {
+		Raw struct {
+			Language string `json:"language"`
+		} `json:"raw"`
+	} `json:"options"`
+	Disabled bool `json:"disabled"`
+}
+
+type postmanResponse struct {
+	Name   string         `json:"name"`
+	Code   int            `json:"code"`
+	Status string         `json:"status"`
+	Header postmanHeaders `json:"header"`
+	Body   string         `json:"body"`
+}
+
+type postmanEvent struct {
+	Listen string `json:"listen"`
+	Script struct {
+		Exec postmanLines `json:"exec"`
+	} `json:"script"`
+	Disabled bool `json:"disabled"`
+}
+
+// postmanValue is a string that may be saved as any JSON value.
+type postmanValue string
+
+func (v *postmanValue) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*v = postmanValue(s)
+	} else if string(data) != "null" {
+		*v = postmanValue(data)
+	}
+	return nil
+}
+
+// postmanText is a description: a string or an object with its content.
+type postmanText string
+
+func (t *postmanText) UnmarshalJSON(data []byte) error {
+	var desc struct {
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal(data, &desc); err == nil {
+		*t = postmanText(desc.Content)
+		return nil
+	}
+	var v postmanValue
+	_ = v.UnmarshalJSON(data)
+	*t = postmanText(v)
+	return nil
+}
+
+// postmanLines is a script: a list of lines or a single string.
+type postmanLines []string
+
+func (l *postmanLines) UnmarshalJSON(data []byte) error {
+	var lines []string
+	if err := json.Unmarshal(data, &lines); err == nil {
+		*l = lines
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*l = strings.Split(s, "\n")
+	return nil
+}
+
+// postmanHeaders are headers: a list of pairs or a "Key: value" string.
+type postmanHeaders []postmanPair
+
+func (h *postmanHeaders) UnmarshalJSON(data []byte) error {
+	var pairs []postmanPair
+	if err := json.Unmarshal(data, &pairs); err == nil {
+		*h = pairs
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil
+	}
+	for _, line := range strings.Split(s, "\n") {
+		if key, value, ok := strings.Cut(line, ":"); ok {
+			*h = append(*h, postmanPair{Key: strings.TrimSpace(key), Value: postmanValue(strings.TrimSpace(value))})
+		}
+	}
+	return nil
+}
+
+// UnmarshalJSON reads the parameters of an auth block, a list of
+// key/value pairs in v2.1 and an object in v2.0.
+func (a *PostmanAuth) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	_ = json.Unmarshal(raw["type"], &a.Type)
+	a.Params = make(map[string]string)
+	var pairs []postmanPair
+	if err := json.Unmarshal(raw[a.Type], &pairs); err == nil {
+		for _, p := range pairs {
+			a.Params[p.Key] = string(p.Value)
+		}
+		return nil
+	}
+	var params map[string]postmanValue
+	if err := json.Unmarshal(raw[a.Type], &params); err == nil {
+		for k, v := range params {
+			a.Params[k] = string(v)
+		}
+	}
+	return nil
+}
+
+// UnmarshalJSON accepts a request saved as just its URL.
+func (r *postmanRequest) UnmarshalJSON(data []byte) error {
+	var url string
+	if err := json.Unmarshal(data, &url); err == nil {
+		*r = postmanRequest{Method: "GET", URL: url}
+		return nil
+	}
+	type request postmanRequest
+	return json.Unmarshal(data, (*request)(r))
+}
+
+func (p postmanPair) name() string {
+	return cmp.Or(p.Key, p.ID)
+}
+
+func (p postmanPair) active() bool {
+	return !p.Disabled && (p.Enabled == nil || *p.Enabled)
+}
+
+// collectionAuth describes the authentication in a specification.
+func (a *PostmanAuth) collectionAuth() *collectionAuth {
+	switch a.Type {
+	case "bearer", "basic", "digest", "oauth2":
+		return &collectionAuth{Type: a.Type}
+	case "apikey":
+		return &collectionAuth{Type: "apikey", In: cmp.Or(a.Params["in"], "header"), Name: a.Params["key"]}
+	}
+	return nil
+}
+
+// IsPostmanCollection reports whether JSON content is a Postman collection.
+func IsPostmanCollection(content []byte) bool {
+	var col struct {
+		Info map[string]any `json:"info"`
+	}
+	if err := json.Unmarshal(content, &col); err != nil {
+		return false
+	}
+	_, hasPostmanID := col.Info["_postman_id"]
+	schema, _ := col.Info["schema"].(string)
+	return hasPostmanID || strings.Contains(schema, "postman")
+}
+
+// ReadPostman reads a Postman collection. Values of a Postman environment
+// export, if given, override the collection variables.
+func ReadPostman(content, environment []byte) (*PostmanCollection, error) {
+	var raw postmanCollection
+	if err := json.Unmarshal(content, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse Postman collection: %w", err)
+	}
+
+	col := &PostmanCollection{Name: raw.Info.Name, Variables: make(map[string]string)}
+	if strings.Contains(raw.Info.Schema, "v2.1") {
+		col.Version = "2.1"
+	} else if strings.Contains(raw.Info.Schema, "v2.0") {
+		col.Version = "2.0"
+	}
+	for _, v := range raw.Variable {
+		if v.active() {
+			col.Variables[v.name()] = string(v.Value)
+		}
+	}
+	if len(environment) > 0 {
+		var env struct {
+			Values []postmanPair `json:"values"`
+		}
+		if err := json.Unmarshal(environment, &env); err != nil {
+			return nil, fmt.Errorf("failed to parse Postman environment: %w", err)
+		}
+		for _, v := range env.Values {
+			if v.active() {
+				col.Variables[v.name()] = string(v.Value)
+			}
+		}
+	}
+
+	r := &postmanReader{
+		col:      col,
+		vars:     maps.Clone(col.Variables),
+		dynamic:  make(map[string]bool),
+		reported: make(map[string]bool),
+	}
+	root := postmanScope{name: cmp.Or(raw.Info.Name, "Collection"), auth: raw.Auth, events: raw.Event}
+	r.items(raw.Item, nil, []postmanScope{root})
+	return col, nil
+}
+
+// postmanReader converts the requests of a collection in run order, keeping
+// track of the variables test scripts set.
+type postmanReader struct {
+	col      *PostmanCollection
+	vars     map[string]string
+	dynamic  map[string]bool // set from a response, so resolved when the requests run
+	reported map[string]bool
+}
+
+// postmanScope is the collection or a folder, whose authentication and
+// scripts apply to the requests it contains.
+type postmanScope struct {
+	name   string
+	auth   *PostmanAuth
+	events []postmanEvent
+}
+
+func (r *postmanReader) report(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	if !r.reported[msg] {
+		r.reported[msg] = true
+		r.col.Report = append(r.col.Report, msg)
+	}
+}
+
+func (r *postmanReader) resolve(s string) string {
+	return substituteVariables(s, r.vars)
+}
+
+func (r *postmanReader) items(items []postmanItem, folder []string, scopes []postmanScope) {
+	for _, item := range items {
+		if len(item.Item) > 0 {
+			scope := postmanScope{name: item.Name, auth: item.Auth, events: item.Event}
+			r.items(item.Item, append(slices.Clone(folder), item.Name), append(slices.Clone(scopes), scope))
+			continue
+		}
+		if item.Request != nil {
+			r.request(item, folder, scopes)
+		}
+	}
+}
+
+func (r *postmanReader) request(item postmanItem, folder []string, scopes []postmanScope) {
+	src := item.Request
+	req := PostmanRequest{
+		Name:    item.Name,
+		Folder:  folder,
+		Method:  strings.ToUpper(cmp.Or(src.Method, "GET")),
+		Headers: make(map[string]string),
+	}
+	if req.Name == "" {
+		req.Name = req.Method + " " + extractPostmanURL(src.URL)
+	}
+
+	rawURL, pathVars := postmanRawURL(src.URL)
+	rawURL = postmanAbsoluteURL(r.resolve(rawURL))
+	for name, value := range pathVars {
+		pathVars[name] = r.resolve(value)
+	}
+	req.BaseURL, req.Path = postmanSplitURL(rawURL, pathVars)
+	creq := collectionRequest{
+		Name:        req.Name,
+		Description: string(src.Description),
+		Folder:      folder,
+		Method:      req.Method,
+		URL:         rawURL,
+		PathParams:  pathVars,
+	}
+
+	auth := src.Auth
+	for i := len(scopes) - 1; i >= 0 && (auth == nil || auth.Type == "inherit"); i-- {
+		auth = scopes[i].auth
+	}
+	if auth != nil && auth.Type != "noauth" && auth.Type != "inherit" && auth.Type != "" {
+		req.Auth = &PostmanAuth{Type: auth.Type, Params: make(map[string]string, len(auth.Params))}
+		for k, v := range auth.Params {
+			req.Auth.Params[k] = r.resolve(v)
+		}
+		if !slices.Contains(postmanAuthTypes, auth.Type) {
+			r.report("%s authentication is not supported", auth.Type)
+		}
+	}
+
+	contentType := ""
+	for _, h := range src.Header {
+		if !h.active() {
+			continue
+		}
+		value := r.resolve(string(h.Value))
+		if req.Auth == nil && r.placeholderAuth(&req, h.Key, value) {
+			continue
+		}
+		if strings.EqualFold(h.Key, "Content-Type") {
+			contentType = value
+		}
+		req.Headers[h.Key] = value
+		creq.Headers = append(creq.Headers, [2]string{h.Key, value})
+	}
+	if req.Auth != nil {
+		creq.Auth = req.Auth.collectionAuth()
+	}
+
+	if body := src.Body; body != nil && !body.Disabled {
+		switch body.Mode {
+		case "raw":
+			creq.Body = r.resolve(body.Raw)
+			creq.ContentType = postmanLanguages[body.Options.Raw.Language]
+		case "urlencoded":
+			var fields [][2]string
+			for _, f := range body.URLEncoded {
+				if f.active() {
+					fields = append(fields, [2]string{f.Key, r.resolve(string(f.Value))})
+				}
+			}
+			// Variables set by earlier requests are substituted when sent.
+			creq.Body = strings.NewReplacer("%7B%7B", "{{", "%7D%7D", "}}").Replace(formBody(fields))
+			creq.ContentType = "application/x-www-form-urlencoded"
+		case "formdata":
+			// The boundary of the encoded body replaces a saved one.
+			for key := range req.Headers {
+				if strings.EqualFold(key, "Content-Type") {
+					delete(req.Headers, key)
+					contentType = ""
+				}
+			}
+			for _, f := range body.FormData {
+				if !f.active() {
+					continue
+				}
+				if f.Type == "file" {
+					r.report("%s: file field %q is not sent", req.Name, f.Key)
+					continue
+				}
+				creq.Form = append(creq.Form, [2]string{f.Key, r.resolve(string(f.Value))})
+			}
+			if len(creq.Form) > 0 {
+				req.Body, creq.ContentType = multipartBody(creq.Form)
+			}
+		case "file":
+			r.report("%s: file body is not sent", req.Name)
+		case "graphql":
+			if body.GraphQL != nil {
+				creq.Body = graphQLBody(r.resolve(body.GraphQL.Query), r.resolve(string(body.GraphQL.Variables)))
+				creq.ContentType = "application/json"
+			}
+		}
+	}
+	if creq.Body != "" {
+		req.Body = creq.Body
+	}
+	if contentType != "" {
+		creq.ContentType = ""
+	} else if creq.ContentType != "" && req.Body != "" {
+		req.Headers["Content-Type"] = creq.ContentType
+	}
+	req.Endpoint = creq.endpoint()
+	r.undefined(req)
+
+	success := false
+	for _, scope := range scopes {
+		r.scripts(&req, scope.name, scope.events, &success)
+	}
+	r.scripts(&req, req.Name, item.Event, &success)
+
+	ep := &req.Endpoint
+	for _, resp := range item.Response {
+		if resp.Code == 0 {
+			continue
+		}
+		status := strconv.Itoa(resp.Code)
+		if _, ok := ep.Responses[status]; !ok {
+			ep.Responses[status] = cmp.Or(resp.Status, http.StatusText(resp.Code))
+		}
+		if req.ExpectedStatus == 0 {
+			req.ExpectedStatus = resp.Code
+		}
+		if resp.Body == "" {
+			continue
+		}
+		responseType := ""
+		for _, h := range resp.Header {
+			if strings.EqualFold(h.Key, "Content-Type") {
+				responseType = string(h.Value)
+			}
+		}
+		if responseType == "" && json.Valid([]byte(resp.Body)) {
+			responseType = "application/json"
+		}
+		if ep.ResponseContent == nil {
+			ep.ResponseContent = make(map[string][]MediaType)
+		}
+		ep.ResponseContent[status] = harAddContent(ep.ResponseContent[status], responseType, resp.Body)
+	}
+	addResponseExamples(ep)
+	if success && req.ExpectedStatus == 0 {
+		r.report("%s: pm.response.to.be.success is checked as status 200", req.Name)
+	}
+
+	r.col.Requests = append(r.col.Requests, req)
+}
+
+// placeholderAuth turns an Authorization or API key header whose value is
+// an undefined variable, e.g. Bearer {{token}}, into the request's
+// authentication, so the credentials come from Octrafic.
+func (r *postmanReader) placeholderAuth(req *PostmanRequest, name, value string) bool {
+	undefined := func(s string) bool {
+		m := collectionVariable.FindStringSubmatch(strings.TrimSpace(s))
+		return m != nil && m[0] == strings.TrimSpace(s) && !r.dynamic[m[1]]
+	}
+	lower := strings.ToLower(name)
+	switch {
+	case lower == "authorization":
+		scheme, credentials, ok := strings.Cut(value, " ")
+		if t := strings.ToLower(scheme); ok && (t == "bearer" || t == "basic") && undefined(credentials) {
+			req.Auth = &PostmanAuth{Type: t, Params: map[string]string{}}
+			return true
+		}
+	case strings.Contains(lower, "api-key") || strings.Contains(lower, "apikey") || strings.Contains(lower, "api_key"):
+		if undefined(value) {
+			req.Auth = &PostmanAuth{Type: "apikey", Params: map[string]string{"key": name, "in": "header"}}
+			return true
+		}
+	}
+	return false
+}
+
+// undefined reports variables a request uses that have no value and are not
+// set by an earlier request's test script.
+func (r *postmanReader) undefined(req PostmanRequest) {
+	values := []string{req.Path, req.Body}
+	for _, name := range slices.Sorted(maps.Keys(req.Headers)) {
+		values = append(values, req.Headers[name])
+	}
+	if req.Auth != nil {
+		for _, name := range slices.Sorted(maps.Keys(req.Auth.Params)) {
+			values = append(values, req.Auth.Params[name])
+		}
+	}
+	for _, v := range values {
+		for _, m := range collectionVariable.FindAllStringSubmatch(v, -1) {
+			switch {
+			case strings.HasPrefix(m[1], "$"):
+				r.report("dynamic variable {{%s}} is not supported and is sent as written", m[1])
+			case !r.dynamic[m[1]]:
+				r.report("variable {{%s}} is not defined and is sent as written", m[1])
+			}
+		}
+	}
+}
+
+// scripts translates the test scripts of the request or of an enclosing
+// folder, and reports pre-request scripts, which are not run.
+func (r *postmanReader) scripts(req *PostmanRequest, owner string, events []postmanEvent, success *bool) {
+	for _, event := range events {
+		if event.Disabled {
+			continue
+		}
+		statements := postmanStatements(strings.Join(event.Script.Exec, "\n"))
+		switch event.Listen {
+		case "prerequest":
+			if slices.ContainsFunc(statements, func(s string) bool { return !postmanIgnored.MatchString(s) }) {
+				r.report("%s: pre-request script is not run", owner)
+			}
+		case "test":
+			script := &postmanScript{r: r, req: req, aliases: make(map[string]string), success: success}
+			var skipped []string
+			for _, s := range statements {
+				if !script.statement(s) {
+					skipped = append(skipped, s)
+				}
+			}
+			if len(skipped) == 0 {
+				continue
+			}
+			msg := fmt.Sprintf("%s: could not translate %s", owner, postmanQuote(skipped[0]))
+			if len(skipped) > 1 {
+				msg += fmt.Sprintf(" and %d more test script statements", len(skipped)-1)
+			}
+			r.report("%s", msg)
+		}
+	}
+}
+
+// postmanQuote shortens a statement for the report.
+func postmanQuote(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if len(s) > 80 {
+		s = s[:77] + "..."
+	}
+	return "`" + s + "`"
+}
+
+// postmanScript translates the statements of a test script.
+type postmanScript struct {
+	r       *postmanReader
+	req     *PostmanRequest
+	aliases map[string]string // variables holding the response body or part of it
+	success *bool
+}
+
+// statement translates a statement into an expected status, assertion,
+// extraction or variable, and reports whether it could.
+func (t *postmanScript) statement(s string) bool {
+	s = strings.TrimSpace(strings.TrimSuffix(s, ";"))
+	if m := postmanTestArrow.FindStringSubmatch(s); m != nil {
+		return t.statement(m[1])
+	}
+	if postmanIgnored.MatchString(s) {
+		return true
+	}
+	if m := postmanAlias.FindStringSubmatch(s); m != nil {
+		path, ok := t.path(m[2])
+		if ok {
+			t.aliases[m[1]] = path
+		}
+		return ok
+	}
+	if m := postmanSet.FindStringSubmatch(s); m != nil {
+		return t.set(m[1], m[2])
+	}
+	if m := postmanLegacy.FindStringSubmatch(s); m != nil {
+		t.req.ExpectedStatus, _ = strconv.Atoi(m[1])
+		return true
+	}
+	if rest, ok := strings.CutPrefix(s, "pm.response."); ok {
+		steps, ok := postmanChain("." + rest)
+		return ok && t.response(steps)
+	}
+	if rest, ok := strings.CutPrefix(s, "pm.expect("); ok {
+		inner, chain, ok := postmanParens("(" + rest)
+		if !ok {
+			return false
+		}
+		steps, ok := postmanChain(chain)
+		return ok && t.expect(strings.TrimSpace(inner), steps)
+	}
+	return false
+}
+
+// response translates pm.response.to... checks.
+func (t *postmanScript) response(steps []postmanCall) bool {
+	var main []postmanCall
+	for _, s := range steps {
+		if s.call || !slices.Contains(postmanChainWords, s.name) {
+			main = append(main, s)
+		}
+	}
+	if len(main) != 1 {
+		if len(main) == 2 && main[0].name == "not" && main[1].name == "jsonBody" {
+			return t.expect("pm.response.json()", steps)
+		}
+		return false
+	}
+	switch s := main[0]; {
+	case s.name == "status" && s.call && len(s.args) == 1:
+		code, err := strconv.Atoi(s.args[0])
+		if err != nil {
+			return false
+		}
+		t.req.ExpectedStatus = code
+	case s.name == "ok" && !s.call:
+		t.req.ExpectedStatus = http.StatusOK
+	case s.name == "success" && !s.call:
+		*t.success = true
+	case s.name == "json" && !s.call, s.name == "jsonBody" && len(s.args) == 0:
+	case s.name == "jsonBody":
+		return t.expect("pm.response.json()", steps)
+	default:
+		return false
+	}
+	return true
+}
+
+// expect translates pm.expect(actual) followed by an assertion chain.
+func (t *postmanScript) expect(actual string, steps []postmanCall) bool {
+	suffix, op, value, ok := postmanAssertion(steps)
+	if !ok {
+		return false
+	}
+	if actual == "pm.response.code" {
+		code, isNumber := value.(float64)
+		if op != "eq" || suffix != "" || !isNumber {
+			return false
+		}
+		t.req.ExpectedStatus = int(code)
+		return true
+	}
+	path, ok := t.path(actual)
+	if !ok {
+		return false
+	}
+	field := strings.Trim(path+"."+suffix, ".")
+	if field == "" {
+		return false
+	}
+	t.req.Assertions = append(t.req.Assertions, PostmanAssertion{Field: field, Op: op, Value: value})
+	return true
+}
+
+// set translates a variable set from the response body into an extraction,
+// and one set to a literal into a variable of later requests.
+func (t *postmanScript) set(name, expr string) bool {
+	if path, ok := t.path(expr); ok && path != "" {
+		t.req.Extract = append(t.req.Extract, PostmanExtract{Field: path, As: name})
+		t.r.dynamic[name] = true
+		delete(t.r.vars, name)
+		return true
+	}
+	value, ok := postmanLiteral(expr)
+	if !ok {
+		return false
+	}
+	if s, isString := value.(string); isString {
+		t.r.vars[name] = s
+	} else {
+		t.r.vars[name] = strings.TrimSpace(expr)
+	}
+	delete(t.r.dynamic, name)
+	return true
+}
+
+// path converts an expression reading the response body, e.g.
+// pm.response.json().items[0].id, into a dot path (items.0.id).
+func (t *postmanScript) path(expr string) (string, bool) {
+	expr = strings.TrimSpace(expr)
+	m := postmanRoot.FindStringSubmatch(expr)
+	if m == nil {
+		return "", false
+	}
+	var parts []string
+	if m[1] != "" {
+		prefix, ok := t.aliases[m[1]]
+		if !ok {
+			return "", false
+		}
+		if prefix != "" {
+			parts = append(parts, prefix)
+		}
+	}
+	for rest := expr[len(m[0]):]; rest != ""; {
+		a := postmanAccessor.FindStringSubmatch(rest)
+		if a == nil || a[1] == "length" {
+			return "", false
+		}
+		parts = append(parts, a[1]+a[2]+a[3]+a[4])
+		rest = rest[len(a[0]):]
+	}
+	return strings.Join(parts, "."), true
+}
+
+// postmanAssertion converts a Chai assertion chain, e.g. .to.not.equal(1),
+// into an Octrafic operator and value. .property(name) and .jsonBody(path)
+// return the name or path as a field suffix.
+func postmanAssertion(steps []postmanCall) (suffix, op string, value any, ok bool) {
+	negate := false
+	var main []postmanCall
+	for _, s := range steps {
+		switch {
+		case s.name == "not" && !s.call:
+			negate = !negate
+		case !s.call && slices.Contains(postmanChainWords, s.name):
+		default:
+			main = append(main, s)
+		}
+	}
+	if len(main) != 1 {
+		return "", "", nil, false
+	}
+
+	s := main[0]
+	switch {
+	case !s.call:
+		switch s.name {
+		case "exist", "ok":
+			op = "exists"
+		case "undefined":
+			op = "not_exists"
+		case "null":
+			op = "eq"
+		case "true", "false":
+			op, value = "eq", s.name == "true"
+		default:
+			return "", "", nil, false
+		}
+	case len(s.args) == 1 && slices.Contains([]string{"eql", "equal", "equals", "eq"}, s.name):
+		op = "eq"
+		if value, ok = postmanLiteral(s.args[0]); !ok {
+			return "", "", nil, false
+		}
+	case len(s.args) == 1 && slices.Contains([]string{"include", "includes", "contain", "contains"}, s.name):
+		op = "contains"
+		if value, ok = postmanLiteral(s.args[0]); !ok {
+			return "", "", nil, false
+		}
+		if _, isString := value.(string); !isString {
+			return "", "", nil, false
+		}
+	case len(s.args) == 1 && slices.Contains([]string{"above", "gt", "greaterThan", "least", "gte", "below", "lt", "lessThan", "most", "lte"}, s.name):
+		switch s.name {
+		case "above", "gt", "greaterThan":
+			op = "gt"
+		case "least", "gte":
+			op = "gte"
+		case "below", "lt", "lessThan":
+			op = "lt"
+		default:
+			op = "lte"
+		}
+		if value, ok = postmanLiteral(s.args[0]); !ok {
+			return "", "", nil, false
+		}
+		if _, isNumber := value.(float64); !isNumber {
+			return "", "", nil, false
+		}
+	case (s.name == "property" || s.name == "jsonBody") && (len(s.args) == 1 || len(s.args) == 2):
+		name, isString := postmanLiteralString(s.args[0])
+		if !isString {
+			return "", "", nil, false
+		}
+		suffix, op = name, "exists"
+		if len(s.args) == 2 {
+			op = "eq"
+			if value, ok = postmanLiteral(s.args[1]); !ok {
+				return "", "", nil, false
+			}
+		}
+	default:
+		return "", "", nil, false
+	}
+
+	if negate {
+		switch op {
+		case "eq":
+			op = "neq"
+		case "exists":
+			op = "not_exists"
+		case "not_exists":
+			op = "exists"
+		default:
+			return "", "", nil, false
+		}
+	}
+	return suffix, op, value, true
+}
+
+// postmanLiteral parses a JavaScript literal: a JSON value or a single
+// quoted string.
+func postmanLiteral(s string) (any, bool) {
+	s = strings.TrimSpace(s)
+	if v, ok := postmanLiteralString(s); ok {
+		return v, true
+	}
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return nil, false
+	}
+	return v, true
+}
+
+func postmanLiteralString(s string) (string, bool) {
+	s = strings.TrimSpace(s)
+	if len(s) < 2 || s[0] != s[len(s)-1] || !strings.ContainsRune(`'"`+"`", rune(s[0])) {
+		return "", false
+	}
+	inner := s[1 : len(s)-1]
+	if s[0] == '"' {
+		var v string
+		err := json.Unmarshal([]byte(s), &v)
+		return v, err == nil
+	}
+	if s[0] == '`' && strings.Contains(inner, "${") {
+		return "", false
+	}
+	return strings.NewReplacer(`\'`, `'`, `\"`, `"`, `\\`, `\`, "\\`", "`").Replace(inner), true
+}
+
+// postmanCall is a step of a chained expression: a property, or a method
+// call with its arguments.
+type postmanCall struct {
+	name string
+	args []string
+	call bool
+}
+
+// postmanChain splits a chain such as .to.be.above(1) into its steps.
+func postmanChain(s string) ([]postmanCall, bool) {
+	var steps []postmanCall
+	for s = strings.TrimSpace(s); s != ""; s = strings.TrimSpace(s) {
+		if s[0] != '.' {
+			return nil, false
+		}
+		s = s[1:]
+		n := strings.IndexFunc(s, func(r rune) bool {
+			return r != '_' && r != '$' && !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9')
+		})
+		if n < 0 {
+			n = len(s)
+		}
+		if n == 0 {
+			return nil, false
+		}
+		step := postmanCall{name: s[:n]}
+		s = s[n:]
+		if strings.HasPrefix(s, "(") {
+			inner, rest, ok := postmanParens(s)
+			if !ok {
+				return nil, false
+			}
+			step.call, step.args, s = true, postmanArgs(inner), rest
+		}
+		steps = append(steps, step)
+	}
+	return steps, true
+}
+
+// postmanWalk calls fn with the index and bracket depth of every byte of
+// JavaScript source outside string literals, until fn returns false.
+func postmanWalk(s string, fn func(i, depth int) bool) {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '\'', '"', '`':
+			quote = c
+			continue
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		}
+		if !fn(i, depth) {
+			return
+		}
+	}
+}
+
+// postmanParens splits s, which starts with an opening parenthesis, into
+// the text inside it and the text after the matching closing one.
+func postmanParens(s string) (inner, rest string, ok bool) {
+	postmanWalk(s, func(i, depth int) bool {
+		if depth == 0 && s[i] == ')' {
+			inner, rest, ok = s[1:i], s[i+1:], true
+			return false
+		}
+		return true
+	})
+	return inner, rest, ok
+}
+
+// postmanArgs splits the arguments of a call.
+func postmanArgs(s string) []string {
+	var args []string
+	start := 0
+	postmanWalk(s, func(i, depth int) bool {
+		if depth == 0 && s[i] == ',' {
+			args = append(args, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+		return true
+	})
+	if last := strings.TrimSpace(s[start:]); last != "" || len(args) > 0 {
+		args = append(args, last)
+	}
+	return args
+}
+
+// postmanStatements splits a script into statements. The bodies of
+// pm.test blocks are unwrapped and comments dropped; other blocks, such as
+// if statements, stay whole.
+func postmanStatements(script string) []string {
+	script = postmanTestBlock.ReplaceAllString(script, "\x00")
+	var statements []string
+	var cur strings.Builder
+	flush := func() {
+		if s := strings.TrimSpace(cur.String()); s != "" && s != ")" {
+			statements = append(statements, s)
+		}
+		cur.Reset()
+	}
+
+	var blocks []bool // open braces, true for pm.test blocks
+	depth := 0
+	var quote byte
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+		if quote != 0 {
+			cur.WriteByte(c)
+			if c == '\\' && i+1 < len(script) {
+				i++
+				cur.WriteByte(script[i])
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case strings.HasPrefix(script[i:], "//"):
+			if end := strings.IndexByte(script[i:], '\n'); end >= 0 {
+				i += end - 1
+			} else {
+				i = len(script)
+			}
+			continue
+		case strings.HasPrefix(script[i:], "/*"):
+			if end := strings.Index(script[i+2:], "*/"); end >= 0 {
+				i += end + 3
+			} else {
+				i = len(script)
+			}
+			continue
+		case c == 0:
+			flush()
+			blocks = append(blocks, true)
+			continue
+		case c == '{':
+			blocks = append(blocks, false)
+		case c == '}' && len(blocks) > 0:
+			test := blocks[len(blocks)-1]
+			blocks = blocks[:len(blocks)-1]
+			if test {
+				flush()
+				rest := strings.TrimLeft(script[i+1:], " \t\r\n")
+				if strings.HasPrefix(rest, ")") {
+					i = len(script) - len(rest)
+				}
+				continue
+			}
+		case c == '(' || c == '[':
+			depth++
+		case c == ')' || c == ']':
+			depth--
+		case c == '\n' && strings.HasPrefix(strings.TrimLeft(script[i+1:], " \t\r\n"), "."):
+			// A chain continued on the next line.
+			c = ' '
+		case (c == ';' || c == '\n') && depth == 0 && !slices.Contains(blocks, false):
+			flush()
+			continue
+		}
+		cur.WriteByte(c)
+	}
+	flush()
+	return statements
+}
+
+// postmanRawURL returns the URL of a request and the values of its :name
+// path variables.
+func postmanRawURL(url any) (string, map[string]string) {
+	vars := make(map[string]string)
+	v, ok := url.(map[string]any)
+	if !ok {
+		s, _ := url.(string)
+		return s, vars
+	}
+	if list, ok := v["variable"].([]any); ok {
+		for _, item := range list {
+			if m, ok := item.(map[string]any); ok {
+				name, _ := m["key"].(string)
+				if name == "" {
+					name, _ = m["id"].(string)
+				}
+				if value, ok := m["value"]; ok && value != nil && name != "" {
+					vars[name] = fmt.Sprint(value)
+				}
+			}
+		}
+	}
+	if raw, ok := v["raw"].(string); ok {
+		return raw, vars
+	}
+
+	join := func(part any, sep string) string {
+		list, ok := part.([]any)
+		if !ok {
+			s, _ := part.(string)
+			return s
+		}
+		parts := make([]string, 0, len(list))
+		for _, p := range list {
+			switch p := p.(type) {
+			case string:
+				parts = append(parts, p)
+			case map[string]any:
+				s, _ := p["value"].(string)
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, sep)
+	}
+	var b strings.Builder
+	if protocol, _ := v["protocol"].(string); protocol != "" {
+		b.WriteString(protocol + "://")
+	}
+	b.WriteString(join(v["host"], "."))
+	if port, _ := v["port"].(string); port != "" {
+		b.WriteString(":" + port)
+	}
+	b.WriteString("/" + join(v["path"], "/"))
+	var query []string
+	if list, ok := v["query"].([]any); ok {
+		for _, item := range list {
+			m, _ := item.(map[string]any)
+			if disabled, _ := m["disabled"].(bool); m == nil || disabled {
+				continue
+			}
+			key, _ := m["key"].(string)
+			value, _ := m["value"].(string)
+			query = append(query, key+"="+value)
+		}
+	}
+	if len(query) > 0 {
+		b.WriteString("?" + strings.Join(query, "&"))
+	}
+	return b.String(), vars
+}
+
+// postmanAbsoluteURL adds the scheme Postman defaults to when a URL starts
+// with a host.
+func postmanAbsoluteURL(rawURL string) string {
+	if rawURL == "" || strings.HasPrefix(rawURL, "/") || strings.HasPrefix(rawURL, "{{") || strings.Contains(rawURL, "://") {
+		return rawURL
+	}
+	host, _, _ := strings.Cut(rawURL, "/")
+	if strings.ContainsAny(host, ".:") || host == "localhost" {
+		return "http://" + rawURL
+	}
+	return rawURL
+}
+
+// postmanSplitURL splits a resolved URL into its scheme and host and the
+// path and query as sent, with :name path variables replaced by their
+// values.
+func postmanSplitURL(rawURL string, pathVars map[string]string) (base, path string) {
+	rest := rawURL
+	if loc := collectionVariable.FindStringIndex(rest); loc != nil && loc[0] == 0 {
+		rest = rest[loc[1]:]
+	} else if scheme, after, ok := strings.Cut(rest, "://"); ok {
+		host := after
+		rest = ""
+		if i := strings.IndexAny(after, "/?#"); i >= 0 {
+			host, rest = after[:i], after[i:]
+		}
+		base = scheme + "://" + host
+	}
+	rest, _, _ = strings.Cut(rest, "#")
+	path, query, hasQuery := strings.Cut(rest, "?")
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		if name, ok := strings.CutPrefix(seg, ":"); ok && pathVars[name] != "" {
+			segments[i] = pathVars[name]
+		}
+	}
+	path = strings.Join(segments, "/")
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	if hasQuery {
+		path += "?" + query
+	}
+	return base, path
+}
+
+// multipartBody encodes text fields as a multipart/form-data body.
+func multipartBody(fields [][2]string) (body, contentType string) {
+	var b bytes.Buffer
+	w := multipart.NewWriter(&b)
+	_ = w.SetBoundary(multipartBoundary)
+	for _, f := range fields {
+		_ = w.WriteField(f[0], f[1])
+	}
+	_ = w.Close()
+	return b.String(), w.FormDataContentType()
+}
+
+func parsePostman(content []byte) (*Specification, error) {
+	col, err := ReadPostman(content, nil)
+	if err != nil {
+		return nil, err
+	}
+	spec := &Specification{
+		Format:     "postman",
+		Version:    col.Version,
+		RawContent: string(content),
+		Endpoints:  make([]Endpoint, 0, len(col.Requests)),
+	}
+	for _, req := range col.Requests {
+		spec.Endpoints = append(spec.Endpoints, req.Endpoint)
+	}
+	return spec, nil
+}
+
+func extractPostmanURL(url any) string {
+	switch v := url.(type) {
+	case string:
+		return extractPathFromURL(v)
+	case map[string]any:
+		// URL object format
+		if raw, ok := v["raw"].(string); ok {
+			return extractPathFromURL(raw)
+		}
+		// Build from path array
+		if pathArr, ok := v["path"].([]any); ok {
+			var parts []string
+			for _, p := range pathArr {
+				if s, ok := p.(string); ok {
+					parts = append(parts, s)
+				}
+			}
+			return "/" + strings.Join(parts, "/")
+		}
+	}
+	return "/"
+}
+
+func extractPathFromURL(rawURL string) string {
+	if idx := strings.Index(rawURL, "}}"); idx != -1 {
+		rawURL = rawURL[idx+2:]
+	}
+	if strings.HasPrefix(rawURL, "http") {
+		parts := strings.SplitN(rawURL, "/", 4)
+		if len(parts) >= 4 {
+			return "/" + parts[3]
+		}
+		return "/"
+	}
+	if !strings.HasPrefix(rawURL, "/") {
+		return "/" + rawURL
+	}
+	return rawURL
+}
@@ -0,0 +1,227 @@
+package parser
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+const postmanTestCollection = `{
+  "info": {"name": "Shop", "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"},
+  "auth": {"type": "bearer", "bearer": [{"key": "token", "value": "{{token}}", "type": "string"}]},
+  "variable": [
+    {"key": "baseUrl", "value": "https://staging.example.com/v1"},
+    {"key": "token", "value": "staging-token"}
+  ],
+  "event": [{"listen": "test", "script": {"exec": ["pm.test(\"fast\", function () {", "    pm.expect(pm.response.responseTime).to.be.below(500);", "});"]}}],
+  "item": [
+    {
+      "name": "Auth",
+      "auth": {"type": "noauth"},
+      "item": [{
+        "name": "Login",
+        "event": [{"listen": "test", "script": {"exec": [
+          "var jsonData = pm.response.json();",
+          "pm.test(\"Status code is 200\", function () {",
+          "    pm.response.to.have.status(200);",
+          "});",
+          "pm.environment.set(\"session\", jsonData.data.session_id);"
+        ]}}],
+        "request": {
+          "method": "POST",
+          "header": [{"key": "X-Debug", "value": "1", "disabled": true}],
+          "body": {"mode": "urlencoded", "urlencoded": [
+            {"key": "user", "value": "{{user}}"},
+            {"key": "remember", "value": "yes", "disabled": true}
+          ]},
+          "url": {"raw": "{{baseUrl}}/login", "host": ["{{baseUrl}}"], "path": ["login"]}
+        }
+      }]
+    },
+    {
+      "name": "Get order",
+      "event": [
+        {"listen": "prerequest", "script": {"exec": ["pm.variables.set('ts', Date.now());"]}},
+        {"listen": "test", "script": {"exec": [
+          "pm.test('order', () => {",
+          "  const order = pm.response.json().order;",
+          "  pm.expect(order.id).to.eql(\"o-1\");",
+          "  pm.expect(order.items[0]['sku']).to.not.equal('none');",
+          "  pm.expect(order.total).to.be.above(10);",
+          "  pm.expect(order).to.have.property('status');",
+          "  pm.expect(order.note)",
+          "    .to.include('gift');",
+          "});",
+          "pm.test('count', () => pm.expect(pm.response.json().items.length).to.eql(2));"
+        ]}}
+      ],
+      "request": {
+        "method": "GET",
+        "header": [{"key": "X-Session", "value": "{{session}}"}, {"key": "Accept", "value": "application/json"}],
+        "url": {
+          "raw": "{{baseUrl}}/orders/:orderId?expand={{expand}}",
+          "host": ["{{baseUrl}}"],
+          "path": ["orders", ":orderId"],
+          "query": [{"key": "expand", "value": "{{expand}}"}],
+          "variable": [{"key": "orderId", "value": "o-1"}]
+        }
+      },
+      "response": [{
+        "name": "Found",
+        "code": 200,
+        "status": "OK",
+        "header": [{"key": "Content-Type", "value": "application/json"}],
+        "body": "{\"order\": {\"id\": \"o-1\", \"total\": 12.5}}"
+      }]
+    },
+    {
+      "name": "Upload receipt",
+      "request": {
+        "method": "POST",
+        "auth": {"type": "apikey", "apikey": [{"key": "key", "value": "X-Api-Key"}, {"key": "value", "value": "{{apiKey}}"}, {"key": "in", "value": "header"}]},
+        "body": {"mode": "formdata", "formdata": [
+          {"key": "note", "value": "paid", "type": "text"},
+          {"key": "file", "type": "file", "src": "/tmp/receipt.pdf"}
+        ]},
+        "url": "{{baseUrl}}/receipts"
+      }
+    }
+  ]
+}`
+
+func TestReadPostman(t *testing.T) {
+	env := `{"name": "prod", "values": [
+	  {"key": "baseUrl", "value": "https://api.example.com/v1", "enabled": true},
+	  {"key": "expand", "value": "items", "enabled": true},
+	  {"key": "user", "value": "ada", "enabled": false}
+	]}`
+	col, err := ReadPostman([]byte(postmanTestCollection), []byte(env))
+	if err != nil {
+		t.Fatalf("ReadPostman failed: %v", err)
+	}
+	if col.Version != "2.1" || len(col.Requests) != 3 {
+		t.Fatalf("expected 3 requests of a v2.1 collection, got %s %d", col.Version, len(col.Requests))
+	}
+
+	login := col.Requests[0]
+	if login.BaseURL != "https://api.example.com" || login.Path != "/v1/login" {
+		t.Errorf("expected the environment's base URL, got %s %s", login.BaseURL, login.Path)
+	}
+	if login.Auth != nil || login.Headers["X-Debug"] != "" {
+		t.Errorf("expected the folder's noauth and disabled headers dropped, got %+v %v", login.Auth, login.Headers)
+	}
+	if login.Body != "user={{user}}" || login.Headers["Content-Type"] != "application/x-www-form-urlencoded" {
+		t.Errorf("expected the enabled form fields, got %q %v", login.Body, login.Headers)
+	}
+	if login.ExpectedStatus != 200 || !slices.Equal(login.Extract, []PostmanExtract{{Field: "data.session_id", As: "session"}}) {
+		t.Errorf("expected status and extraction from the test script, got %d %+v", login.ExpectedStatus, login.Extract)
+	}
+
+	order := col.Requests[1]
+	if order.Path != "/v1/orders/o-1?expand=items" || order.Headers["X-Session"] != "{{session}}" {
+		t.Errorf("expected resolved path and the extracted variable kept, got %s %v", order.Path, order.Headers)
+	}
+	if order.Auth == nil || order.Auth.Type != "bearer" || order.Auth.Params["token"] != "staging-token" {
+		t.Errorf("expected the collection's bearer auth, got %+v", order.Auth)
+	}
+	want := []PostmanAssertion{
+		{Field: "order.id", Op: "eq", Value: "o-1"},
+		{Field: "order.items.0.sku", Op: "neq", Value: "none"},
+		{Field: "order.total", Op: "gt", Value: float64(10)},
+		{Field: "order.status", Op: "exists"},
+		{Field: "order.note", Op: "contains", Value: "gift"},
+	}
+	if !slices.Equal(order.Assertions, want) {
+		t.Errorf("expected assertions %+v, got %+v", want, order.Assertions)
+	}
+	ep := order.Endpoint
+	if ep.Path != "/v1/orders/{orderId}" || !ep.RequiresAuth || ep.AuthType != "bearer" {
+		t.Errorf("expected endpoint with path parameter and bearer auth, got %s %v %s", ep.Path, ep.RequiresAuth, ep.AuthType)
+	}
+	if order.ExpectedStatus != 200 || ep.Responses["200"] != "OK" || ep.ResponseSchemas["200"]["type"] != "object" {
+		t.Errorf("expected the saved example as the expected response, got %d %+v", order.ExpectedStatus, ep.Responses)
+	}
+
+	upload := col.Requests[2]
+	if upload.Auth == nil || upload.Auth.Type != "apikey" || upload.Auth.Params["key"] != "X-Api-Key" {
+		t.Errorf("expected request API key auth, got %+v", upload.Auth)
+	}
+	if !strings.HasPrefix(upload.Headers["Content-Type"], "multipart/form-data; boundary=") || !strings.Contains(upload.Body, "paid") {
+		t.Errorf("expected multipart body, got %v %q", upload.Headers, upload.Body)
+	}
+	if upload.Endpoint.AuthType != "apikey" {
+		t.Errorf("expected apikey endpoint, got %s", upload.Endpoint.AuthType)
+	}
+
+	wantReport := []string{
+		"variable {{user}} is not defined and is sent as written",
+		"Shop: could not translate `pm.expect(pm.response.responseTime).to.be.below(500)`",
+		"Get order: pre-request script is not run",
+		"Get order: could not translate `pm.test('count', () => pm.expect(pm.response.json().items.length).to.eql(2))`",
+		`Upload receipt: file field "file" is not sent`,
+		"variable {{apiKey}} is not defined and is sent as written",
+	}
+	for _, msg := range wantReport {
+		if !slices.Contains(col.Report, msg) {
+			t.Errorf("expected report %q, got %q", msg, col.Report)
+		}
+	}
+}
+
+func TestReadPostman_V20(t *testing.T) {
+	collection := `{
+	  "info": {"name": "Legacy", "schema": "https://schema.getpostman.com/json/collection/v2.0.0/collection.json"},
+	  "variable": [{"id": "host", "value": "https://legacy.example.com"}],
+	  "item": [{
+	    "name": "Create",
+	    "event": [{"listen": "test", "script": {"exec": "tests[\"Created\"] = responseCode.code === 201;"}}],
+	    "request": {
+	      "url": "{{host}}/items",
+	      "method": "POST",
+	      "auth": {"type": "basic", "basic": {"username": "admin", "password": "secret"}},
+	      "header": "Content-Type: application/json\nAuthorization: Bearer {{accessToken}}",
+	      "body": {"mode": "raw", "raw": "{\"name\": \"pen\"}"}
+	    }
+	  }]
+	}`
+	col, err := ReadPostman([]byte(collection), nil)
+	if err != nil {
+		t.Fatalf("ReadPostman failed: %v", err)
+	}
+	if col.Version != "2.0" || len(col.Requests) != 1 {
+		t.Fatalf("expected 1 request of a v2.0 collection, got %s %d", col.Version, len(col.Requests))
+	}
+	req := col.Requests[0]
+	if req.BaseURL != "https://legacy.example.com" || req.Path != "/items" || req.ExpectedStatus != 201 {
+		t.Errorf("expected POST /items expecting 201, got %s %s %d", req.BaseURL, req.Path, req.ExpectedStatus)
+	}
+	if req.Auth == nil || req.Auth.Type != "basic" || req.Auth.Params["username"] != "admin" {
+		t.Errorf("expected v2.0 basic auth object, got %+v", req.Auth)
+	}
+	if req.Headers["Content-Type"] != "application/json" || req.Headers["Authorization"] != "Bearer {{accessToken}}" {
+		t.Errorf("expected headers from the header string, got %v", req.Headers)
+	}
+}
+
+func TestReadPostman_PlaceholderAuth(t *testing.T) {
+	// Collections exported by Octrafic carry credentials as placeholders.
+	collection := `{
+	  "info": {"name": "Octrafic Generated Tests", "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"},
+	  "variable": [{"key": "baseUrl", "value": "http://localhost:8080"}],
+	  "item": [{
+	    "name": "GET /me",
+	    "request": {"method": "GET", "header": [{"key": "Authorization", "value": "Bearer {{AUTH_TOKEN}}"}], "url": {"raw": "{{baseUrl}}/me"}}
+	  }]
+	}`
+	col, err := ReadPostman([]byte(collection), nil)
+	if err != nil {
+		t.Fatalf("ReadPostman failed: %v", err)
+	}
+	req := col.Requests[0]
+	if req.Auth == nil || req.Auth.Type != "bearer" || req.Headers["Authorization"] != "" {
+		t.Errorf("expected the placeholder header as bearer auth, got %+v %v", req.Auth, req.Headers)
+	}
+	if len(col.Report) != 0 {
+		t.Errorf("expected nothing to report, got %q", col.Report)
+	}
+}
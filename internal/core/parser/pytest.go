@@ -0,0 +1,752 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// PytestFile is a pytest file of requests calls, as written by the pytest
+// exporter, read back by ReadPytest.
+type PytestFile struct {
+	BaseURL    string
+	APIKeyName string // header of API_KEY_VALUE, when the file uses API key auth
+	Tests      []PytestTest
+	Report     []string // what could not be translated
+}
+
+// PytestTest is a test function: the request it sends and what it asserts.
+type PytestTest struct {
+	Name           string
+	Method         string
+	Path           string // path and query
+	Headers        map[string]string
+	Body           string
+	AuthType       string // bearer, apikey or basic when the test sends the file's credentials
+	ExpectedStatus int
+	Poll           *PytestPoll
+}
+
+// PytestPoll is a loop repeating the request until its conditions hold.
+type PytestPoll struct {
+	Until           []PytestCondition
+	UntilStatus     int
+	IntervalSeconds float64
+	TimeoutSeconds  float64
+	Message         string
+}
+
+// PytestCondition is a poll condition on a response field.
+type PytestCondition struct {
+	Field string
+	Op    string
+	Value any
+}
+
+var (
+	pyDef         = regexp.MustCompile(`^def\s+(test_\w*)\s*\(\s*\)\s*(?:->\s*None\s*)?:$`)
+	pyAssign      = regexp.MustCompile(`^([A-Za-z_]\w*)\s*=\s*(.+)$`)
+	pyHeaderSet   = regexp.MustCompile(`^headers\[\s*(.+?)\s*\]\s*=\s*(.+)$`)
+	pyCall        = regexp.MustCompile(`^response\s*=\s*requests\.(get|post|put|patch|delete|head|options|request)\((.*)\)$`)
+	pyStatus      = regexp.MustCompile(`^assert\s+response\.status_code\s*==\s*(\d{3})$`)
+	pyEnvDefault  = regexp.MustCompile(`^os\.(?:environ\.get|getenv)\(\s*(.+)\)$`)
+	pyDeadline    = regexp.MustCompile(`^deadline\s*=\s*time\.monotonic\(\)\s*\+\s*([\d.]+)$`)
+	pySleep       = regexp.MustCompile(`^time\.sleep\(\s*([\d.]+)\s*\)$`)
+	pyDeadlineMsg = regexp.MustCompile(`^assert\s+time\.monotonic\(\)\s*<\s*deadline\s*,\s*(.+)$`)
+	pyNumber      = regexp.MustCompile(`^-?\d+(?:\.\d+)?(?:[eE][+-]?\d+)?`)
+)
+
+// pyIgnored are statements of generated tests with no effect on the request.
+var pyIgnored = []string{"auth = None", "while True:", "break", "body = _json(response)"}
+
+// ReadPytest reads a pytest file written by the pytest exporter. Every
+// test_ function becomes a test with the request it sends, its expected
+// status and its poll loop; statements with no Octrafic equivalent are
+// reported.
+func ReadPytest(content []byte) (*PytestFile, error) {
+	file := &PytestFile{}
+	var test *pytestTest
+	flush := func() {
+		if test != nil {
+			test.finish(file)
+			test = nil
+		}
+	}
+	for _, line := range pyLines(string(content)) {
+		if line.indent == 0 {
+			flush()
+			if m := pyDef.FindStringSubmatch(line.text); m != nil {
+				test = &pytestTest{PytestTest: PytestTest{Name: m[1], Headers: make(map[string]string)}, file: file}
+				continue
+			}
+			if m := pyAssign.FindStringSubmatch(line.text); m != nil {
+				switch m[1] {
+				case "BASE_URL":
+					file.BaseURL, _ = pyStringExpr(m[2])
+				case "API_KEY_NAME":
+					file.APIKeyName, _ = pyStringExpr(m[2])
+				}
+			}
+			continue
+		}
+		if test != nil {
+			test.statement(line.text)
+		} else if strings.HasPrefix(line.text, "def test_") {
+			file.Report = append(file.Report, fmt.Sprintf("test methods of classes are not run: %s", line.text))
+		}
+	}
+	flush()
+	if len(file.Tests) == 0 {
+		return nil, fmt.Errorf("no test functions sending requests found")
+	}
+	return file, nil
+}
+
+// ReadPytestFile reads a pytest file from disk (see ReadPytest).
+func ReadPytestFile(path string) (*PytestFile, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pytest file: %w", err)
+	}
+	return ReadPytest(content)
+}
+
+// pytestTest collects the statements of a test function.
+type pytestTest struct {
+	PytestTest
+	file    *PytestFile
+	url     string
+	locals  map[string]any
+	sent    bool
+	skipped []string
+	notes   []string
+}
+
+func (t *pytestTest) finish(file *PytestFile) {
+	for _, note := range t.notes {
+		file.Report = append(file.Report, t.Name+": "+note)
+	}
+	if len(t.skipped) > 0 {
+		msg := fmt.Sprintf("%s: could not translate `%s`", t.Name, t.skipped[0])
+		if len(t.skipped) > 1 {
+			msg += fmt.Sprintf(" and %d more statements", len(t.skipped)-1)
+		}
+		file.Report = append(file.Report, msg)
+	}
+	if !t.sent {
+		file.Report = append(file.Report, fmt.Sprintf("%s: sends no request", t.Name))
+		return
+	}
+	file.Tests = append(file.Tests, t.PytestTest)
+}
+
+// statement applies a statement of the function body, or records it as
+// not translated.
+func (t *pytestTest) statement(s string) {
+	if !t.apply(s) {
+		t.skipped = append(t.skipped, strings.Join(strings.Fields(s), " "))
+	}
+}
+
+func (t *pytestTest) apply(s string) bool {
+	if slices.Contains(pyIgnored, s) {
+		return true
+	}
+	if v, rest, ok := pyValue(s); ok && rest == "" {
+		_, isString := v.(string) // docstring
+		return isString
+	}
+	if m := pyCall.FindStringSubmatch(s); m != nil {
+		return t.call(m[1], m[2])
+	}
+	if m := pyStatus.FindStringSubmatch(s); m != nil {
+		t.ExpectedStatus, _ = strconv.Atoi(m[1])
+		return true
+	}
+	if s == "assert response.status_code < 500" {
+		t.notes = append(t.notes, "any status below 500 is expected, 200 is checked")
+		return true
+	}
+	if m := pyDeadline.FindStringSubmatch(s); m != nil {
+		t.poll().TimeoutSeconds, _ = strconv.ParseFloat(m[1], 64)
+		return true
+	}
+	if m := pySleep.FindStringSubmatch(s); m != nil {
+		t.poll().IntervalSeconds, _ = strconv.ParseFloat(m[1], 64)
+		return true
+	}
+	if m := pyDeadlineMsg.FindStringSubmatch(s); m != nil {
+		msg, ok := pyStringExpr(m[1])
+		t.poll().Message = msg
+		return ok
+	}
+	if cond, ok := strings.CutPrefix(s, "if "); ok && strings.HasSuffix(cond, ":") {
+		return t.until(strings.TrimSuffix(cond, ":"))
+	}
+	if m := pyHeaderSet.FindStringSubmatch(s); m != nil {
+		return t.header(m[1], m[2])
+	}
+	if m := pyAssign.FindStringSubmatch(s); m != nil {
+		switch m[1] {
+		case "url":
+			url, ok := t.urlExpr(m[2])
+			t.url = url
+			return ok
+		case "headers":
+			headers, ok := pyStringMap(m[2])
+			for k, v := range headers {
+				t.Headers[k] = v
+			}
+			return ok
+		case "auth":
+			if m[2] == "(AUTH_USER, AUTH_PASS)" {
+				t.AuthType = "basic"
+				return true
+			}
+			return false
+		}
+		v, rest, ok := pyValue(m[2])
+		if ok && rest == "" {
+			if t.locals == nil {
+				t.locals = make(map[string]any)
+			}
+			t.locals[m[1]] = v
+		}
+		return ok && rest == ""
+	}
+	return false
+}
+
+func (t *pytestTest) poll() *PytestPoll {
+	if t.Poll == nil {
+		t.Poll = &PytestPoll{}
+	}
+	return t.Poll
+}
+
+// header applies headers[key] = value, where the credentials of the file
+// set the test's authentication.
+func (t *pytestTest) header(key, value string) bool {
+	switch {
+	case key == `"Authorization"` && value == `f"Bearer {AUTH_TOKEN}"`:
+		t.AuthType = "bearer"
+	case key == "API_KEY_NAME" && value == "API_KEY_VALUE":
+		t.AuthType = "apikey"
+	default:
+		k, okKey := pyStringExpr(key)
+		v, okValue := pyStringExpr(value)
+		if !okKey || !okValue {
+			return false
+		}
+		t.Headers[k] = v
+	}
+	return true
+}
+
+// urlExpr converts BASE_URL + "/path", f"{BASE_URL}/path" or an absolute
+// URL into a path.
+func (t *pytestTest) urlExpr(expr string) (string, bool) {
+	if name, ok := t.locals[expr].(string); ok {
+		expr = strconv.Quote(name)
+	}
+	if rest, ok := strings.CutPrefix(expr, "BASE_URL"); ok {
+		rest = strings.TrimSpace(rest)
+		if rest == "" {
+			return "/", true
+		}
+		if rest, ok = strings.CutPrefix(rest, "+"); !ok {
+			return "", false
+		}
+		return pyStringExpr(rest)
+	}
+	if rest, ok := strings.CutPrefix(expr, `f"{BASE_URL}`); ok && strings.HasSuffix(rest, `"`) && !strings.ContainsAny(rest, "{}") {
+		return pyStringExpr(`"` + rest)
+	}
+	url, ok := pyStringExpr(expr)
+	if !ok {
+		return "", false
+	}
+	if scheme, after, found := strings.Cut(url, "://"); found {
+		host, path := after, "/"
+		if i := strings.IndexAny(after, "/?"); i >= 0 {
+			host, path = after[:i], after[i:]
+		}
+		if t.file.BaseURL == "" {
+			t.file.BaseURL = scheme + "://" + host
+		}
+		return path, true
+	}
+	return url, true
+}
+
+// call applies response = requests.method(url, ...).
+func (t *pytestTest) call(method, args string) bool {
+	params := pyArgs(args)
+	if method == "request" {
+		if len(params) == 0 {
+			return false
+		}
+		m, ok := pyStringExpr(params[0])
+		if !ok {
+			return false
+		}
+		method, params = m, params[1:]
+	}
+	t.Method = strings.ToUpper(method)
+	t.sent = true
+	path := t.url
+	ok := true
+	for i, p := range params {
+		name, value, isKeyword := strings.Cut(p, "=")
+		if !isKeyword || strings.ContainsAny(name, `"'(`) {
+			if i > 0 {
+				return false
+			}
+			if p != "url" {
+				path, ok = t.urlExpr(p)
+			}
+			continue
+		}
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		var v any
+		if local, found := t.locals[value]; found {
+			v = local
+		} else if value != name {
+			var rest string
+			var parsed bool
+			if v, rest, parsed = pyValue(value); !parsed || rest != "" {
+				v = nil
+			}
+		}
+		switch name {
+		case "url":
+			if value != "url" {
+				path, ok = t.urlExpr(value)
+			}
+		case "headers":
+			if value != "headers" {
+				headers, isMap := pyStringMap(value)
+				for k, h := range headers {
+					t.Headers[k] = h
+				}
+				ok = ok && isMap
+			}
+		case "auth":
+			if value == "(AUTH_USER, AUTH_PASS)" {
+				t.AuthType = "basic"
+			}
+		case "data":
+			s, isString := v.(string)
+			t.Body, ok = s, ok && isString
+		case "json":
+			data, err := json.Marshal(v)
+			t.Body, ok = string(data), ok && err == nil && v != nil
+			if _, set := t.Headers["Content-Type"]; !set {
+				t.Headers["Content-Type"] = "application/json"
+			}
+		case "params":
+			query, isMap := pyStringMap(value)
+			if local, found := t.locals[value].(map[string]any); found {
+				query, isMap = pyStrings(local)
+			}
+			ok = ok && isMap
+			sep := "?"
+			if strings.Contains(path, "?") {
+				sep = "&"
+			}
+			for _, k := range slices.Sorted(maps.Keys(query)) {
+				path += sep + k + "=" + query[k]
+				sep = "&"
+			}
+		case "timeout", "allow_redirects", "verify":
+		default:
+			ok = false
+		}
+	}
+	t.Path = path
+	return ok && path != ""
+}
+
+// until reads the condition of a poll loop, as written by the exporter.
+func (t *pytestTest) until(cond string) bool {
+	poll := t.poll()
+	for _, part := range pySplitAnd(cond) {
+		switch {
+		case part == "response.ok":
+		case strings.HasPrefix(part, "response.status_code == "):
+			code, err := strconv.Atoi(strings.TrimPrefix(part, "response.status_code == "))
+			if err != nil {
+				return false
+			}
+			poll.UntilStatus = code
+		default:
+			c, ok := pyCondition(part)
+			if !ok {
+				return false
+			}
+			poll.Until = append(poll.Until, c)
+		}
+	}
+	return true
+}
+
+// pyCondition parses a field condition: _get(body, "field") compared to a
+// value, tested for None, or searched for a substring.
+func pyCondition(s string) (PytestCondition, bool) {
+	if inner, ok := strings.CutPrefix(s, "("); ok && strings.HasSuffix(inner, ")") {
+		parts := pySplitAnd(strings.TrimSuffix(inner, ")"))
+		if len(parts) != 2 {
+			return PytestCondition{}, false
+		}
+		first, ok := pyCondition(parts[0])
+		if !ok || first.Op != "exists" {
+			return PytestCondition{}, false
+		}
+		c, ok := pyCondition(parts[1])
+		return c, ok && c.Field == first.Field
+	}
+	if value, rest, ok := pyValue(s); ok {
+		rest = strings.TrimSpace(rest)
+		if rest, ok = strings.CutPrefix(rest, "in str("); !ok {
+			return PytestCondition{}, false
+		}
+		field, rest, ok := pyGet(rest)
+		if !ok || strings.TrimSpace(rest) != `or "")` {
+			return PytestCondition{}, false
+		}
+		return PytestCondition{Field: field, Op: "contains", Value: value}, true
+	}
+
+	field, rest, ok := pyGet(s)
+	if !ok {
+		return PytestCondition{}, false
+	}
+	switch rest = strings.TrimSpace(rest); rest {
+	case "is not None":
+		return PytestCondition{Field: field, Op: "exists"}, true
+	case "is None":
+		return PytestCondition{Field: field, Op: "not_exists"}, true
+	}
+	for _, op := range []struct{ py, op string }{{"==", "eq"}, {"!=", "neq"}, {">=", "gte"}, {"<=", "lte"}, {">", "gt"}, {"<", "lt"}} {
+		if operand, found := strings.CutPrefix(rest, op.py); found {
+			value, after, ok := pyValue(operand)
+			return PytestCondition{Field: field, Op: op.op, Value: value}, ok && strings.TrimSpace(after) == ""
+		}
+	}
+	return PytestCondition{}, false
+}
+
+// pyGet parses _get(body, "field") at the start of s.
+func pyGet(s string) (field, rest string, ok bool) {
+	rest, ok = strings.CutPrefix(strings.TrimSpace(s), "_get(body,")
+	if !ok {
+		return "", "", false
+	}
+	v, rest, ok := pyValue(rest)
+	field, isString := v.(string)
+	rest = strings.TrimSpace(rest)
+	if !ok || !isString || !strings.HasPrefix(rest, ")") {
+		return "", "", false
+	}
+	return field, rest[1:], true
+}
+
+// pySplitAnd splits a condition at its top-level "and" operators.
+func pySplitAnd(s string) []string {
+	var parts []string
+	start := 0
+	pyWalk(s, func(i, depth int) {
+		if depth == 0 && strings.HasPrefix(s[i:], " and ") {
+			parts = append(parts, strings.TrimSpace(s[start:i]))
+			start = i + len(" and ")
+		}
+	})
+	return append(parts, strings.TrimSpace(s[start:]))
+}
+
+// pyArgs splits the arguments of a call.
+func pyArgs(s string) []string {
+	var args []string
+	start := 0
+	pyWalk(s, func(i, depth int) {
+		if depth == 0 && s[i] == ',' {
+			args = append(args, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	})
+	if last := strings.TrimSpace(s[start:]); last != "" {
+		args = append(args, last)
+	}
+	return args
+}
+
+// pyWalk calls fn with the index and bracket depth of every byte of Python
+// source outside string literals. Brackets are counted before fn is called.
+func pyWalk(s string, fn func(i, depth int)) {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"', '\'':
+			if _, rest, ok := pyString(s[i:]); ok {
+				i = len(s) - len(rest) - 1
+				continue
+			}
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		}
+		fn(i, depth)
+	}
+}
+
+// pyLine is a logical line of Python source: physical lines joined while
+// brackets or triple-quoted strings are open, without comments.
+type pyLine struct {
+	indent int
+	text   string
+}
+
+func pyLines(src string) []pyLine {
+	var lines []pyLine
+	var cur strings.Builder
+	indent, depth := -1, 0
+	flush := func() {
+		if text := strings.TrimSpace(cur.String()); text != "" {
+			lines = append(lines, pyLine{indent: indent, text: text})
+		}
+		cur.Reset()
+		indent = -1
+	}
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		if indent < 0 {
+			if c == ' ' || c == '\t' {
+				continue
+			}
+			indent = 0
+			for j := i - 1; j >= 0 && src[j] != '\n'; j-- {
+				indent++
+			}
+		}
+		switch c {
+		case '"', '\'':
+			if _, rest, ok := pyString(src[i:]); ok {
+				end := len(src) - len(rest)
+				cur.WriteString(src[i:end])
+				i = end - 1
+				continue
+			}
+		case '#':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			i--
+			continue
+		case '\\':
+			if i+1 < len(src) && src[i+1] == '\n' {
+				i++
+				continue
+			}
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case '\n':
+			if depth <= 0 {
+				depth = 0
+				flush()
+				continue
+			}
+			c = ' '
+		}
+		cur.WriteByte(c)
+	}
+	flush()
+	return lines
+}
+
+// pyValue parses a Python literal at the start of s: a string, number,
+// True, False, None, list, tuple or dict. Adjacent strings are joined.
+func pyValue(s string) (value any, rest string, ok bool) {
+	s = strings.TrimSpace(s)
+	switch {
+	case s == "":
+		return nil, s, false
+	case s[0] == '[' || s[0] == '(':
+		closing := map[byte]byte{'[': ']', '(': ')'}[s[0]]
+		list := []any{}
+		rest = strings.TrimSpace(s[1:])
+		for !strings.HasPrefix(rest, string(closing)) {
+			var item any
+			if item, rest, ok = pyValue(rest); !ok {
+				return nil, s, false
+			}
+			list = append(list, item)
+			rest = strings.TrimSpace(rest)
+			if r, found := strings.CutPrefix(rest, ","); found {
+				rest = strings.TrimSpace(r)
+			} else if !strings.HasPrefix(rest, string(closing)) {
+				return nil, s, false
+			}
+		}
+		return list, rest[1:], true
+	case s[0] == '{':
+		dict := map[string]any{}
+		rest = strings.TrimSpace(s[1:])
+		for !strings.HasPrefix(rest, "}") {
+			var key, item any
+			if key, rest, ok = pyValue(rest); !ok {
+				return nil, s, false
+			}
+			rest = strings.TrimSpace(rest)
+			if rest, ok = strings.CutPrefix(rest, ":"); !ok {
+				return nil, s, false
+			}
+			if item, rest, ok = pyValue(rest); !ok {
+				return nil, s, false
+			}
+			dict[fmt.Sprint(key)] = item
+			rest = strings.TrimSpace(rest)
+			if r, found := strings.CutPrefix(rest, ","); found {
+				rest = strings.TrimSpace(r)
+			} else if !strings.HasPrefix(rest, "}") {
+				return nil, s, false
+			}
+		}
+		return dict, rest[1:], true
+	}
+	for word, v := range map[string]any{"True": true, "False": false, "None": nil} {
+		if r, found := strings.CutPrefix(s, word); found && (r == "" || !isPyIdent(r[0])) {
+			return v, r, true
+		}
+	}
+	if n := pyNumber.FindString(s); n != "" {
+		f, err := strconv.ParseFloat(n, 64)
+		return f, s[len(n):], err == nil
+	}
+	str, rest, ok := pyString(s)
+	if !ok {
+		return nil, s, false
+	}
+	for {
+		next, after, found := pyString(strings.TrimSpace(rest))
+		if !found {
+			return str, rest, true
+		}
+		str, rest = str+next, after
+	}
+}
+
+// pyString parses a string literal at the start of s, with an optional
+// r, b or u prefix. f-strings are not parsed.
+func pyString(s string) (value, rest string, ok bool) {
+	i, raw := 0, false
+	for i < len(s) && i < 2 && strings.ContainsRune("rRbBuU", rune(s[i])) {
+		raw = raw || s[i] == 'r' || s[i] == 'R'
+		i++
+	}
+	if i >= len(s) || (s[i] != '"' && s[i] != '\'') {
+		return "", s, false
+	}
+	quote := s[i : i+1]
+	if strings.HasPrefix(s[i:], strings.Repeat(quote, 3)) {
+		quote = strings.Repeat(quote, 3)
+	}
+	var b strings.Builder
+	for i += len(quote); i < len(s); {
+		if strings.HasPrefix(s[i:], quote) {
+			return b.String(), s[i+len(quote):], true
+		}
+		c := s[i]
+		switch {
+		case c == '\n' && len(quote) == 1:
+			return "", s, false
+		case c == '\\' && i+1 < len(s) && raw:
+			b.WriteString(s[i : i+2])
+			i += 2
+		case c == '\\' && i+1 < len(s):
+			n := pyEscape(&b, s[i+1:])
+			i += 1 + n
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return "", s, false
+}
+
+// pyEscape writes the character of the escape sequence s starts with and
+// returns its length.
+func pyEscape(b *strings.Builder, s string) int {
+	simple := map[byte]string{'n': "\n", 't': "\t", 'r': "\r", 'a': "\a", 'b': "\b", 'f': "\f", 'v': "\v", '0': "\x00", '\\': `\`, '\'': "'", '"': `"`, '\n': ""}
+	if v, ok := simple[s[0]]; ok {
+		b.WriteString(v)
+		return 1
+	}
+	digits := map[byte]int{'x': 2, 'u': 4, 'U': 8}[s[0]]
+	if digits > 0 && len(s) > digits {
+		if n, err := strconv.ParseUint(s[1:1+digits], 16, 32); err == nil {
+			if s[0] == 'x' {
+				b.WriteByte(byte(n))
+			} else if r := rune(n); utf8.ValidRune(r) {
+				b.WriteRune(r)
+			}
+			return 1 + digits
+		}
+	}
+	b.WriteByte('\\')
+	b.WriteByte(s[0])
+	return 1
+}
+
+// pyStringExpr parses an expression that is a string literal.
+func pyStringExpr(s string) (string, bool) {
+	s = strings.TrimSpace(s)
+	if m := pyEnvDefault.FindStringSubmatch(s); m != nil {
+		if args := pyArgs(m[1]); len(args) == 2 {
+			s = args[1]
+		}
+	}
+	v, rest, ok := pyValue(s)
+	str, isString := v.(string)
+	return str, ok && isString && strings.TrimSpace(rest) == ""
+}
+
+// pyStringMap parses a dict literal of strings; numbers and booleans are
+// converted as requests converts them.
+func pyStringMap(s string) (map[string]string, bool) {
+	v, rest, ok := pyValue(s)
+	dict, isDict := v.(map[string]any)
+	if !ok || !isDict || strings.TrimSpace(rest) != "" {
+		return nil, false
+	}
+	return pyStrings(dict)
+}
+
+func pyStrings(dict map[string]any) (map[string]string, bool) {
+	out := make(map[string]string, len(dict))
+	for k, v := range dict {
+		switch v := v.(type) {
+		case string:
+			out[k] = v
+		case float64:
+			out[k] = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			out[k] = map[bool]string{true: "True", false: "False"}[v]
+		default:
+			return nil, false
+		}
+	}
+	return out, true
+}
+
+func isPyIdent(c byte) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
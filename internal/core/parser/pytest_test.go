@@ -0,0 +1,79 @@
+package parser
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+const pytestTestFile = `import os
+import requests
+
+BASE_URL = os.environ.get("BASE_URL", "http://localhost:8080")  # override in CI
+
+
+def test_create_order():
+    '''Creates an order.'''
+    payload = {
+        "sku": "A-1",  # the cheap one
+        "qty": 2,
+        "gift": True,
+        "note": None,
+    }
+    response = requests.post(
+        f"{BASE_URL}/orders",
+        json=payload,
+        headers={"X-Trace": 'a' "b", "X-Retry": 3},
+    )
+    assert response.status_code == 201
+
+
+def test_search():
+    response = requests.request("GET", BASE_URL + "/orders", params={"q": "pen"}, timeout=5)
+    assert response.status_code == 200
+    assert response.json()["total"] > 0
+
+
+def helper():
+    return requests.get(BASE_URL)
+
+
+def test_nothing():
+    assert helper() is not None
+`
+
+func TestReadPytest(t *testing.T) {
+	file, err := ReadPytest([]byte(pytestTestFile))
+	if err != nil {
+		t.Fatalf("ReadPytest failed: %v", err)
+	}
+	if file.BaseURL != "http://localhost:8080" || len(file.Tests) != 2 {
+		t.Fatalf("expected 2 tests against the default base URL, got %s %d", file.BaseURL, len(file.Tests))
+	}
+
+	create := file.Tests[0]
+	if create.Method != "POST" || create.Path != "/orders" || create.ExpectedStatus != 201 {
+		t.Errorf("expected POST /orders expecting 201, got %s %s %d", create.Method, create.Path, create.ExpectedStatus)
+	}
+	if create.Body != `{"gift":true,"note":null,"qty":2,"sku":"A-1"}` {
+		t.Errorf("expected the json dict as body, got %q", create.Body)
+	}
+	wantHeaders := map[string]string{"X-Trace": "ab", "X-Retry": "3", "Content-Type": "application/json"}
+	if !maps.Equal(create.Headers, wantHeaders) {
+		t.Errorf("expected headers %v, got %v", wantHeaders, create.Headers)
+	}
+
+	search := file.Tests[1]
+	if search.Method != "GET" || search.Path != "/orders?q=pen" || search.ExpectedStatus != 200 {
+		t.Errorf("expected GET /orders?q=pen expecting 200, got %s %s %d", search.Method, search.Path, search.ExpectedStatus)
+	}
+
+	wantReport := []string{
+		"test_search: could not translate `assert response.json()[\"total\"] > 0`",
+		"test_nothing: could not translate `assert helper() is not None`",
+		"test_nothing: sends no request",
+	}
+	if !slices.Equal(file.Report, wantReport) {
+		t.Errorf("expected report %q, got %q", wantReport, file.Report)
+	}
+}
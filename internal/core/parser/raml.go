@@ -0,0 +1,907 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ramlMethods are the RAML method keys of a resource.
+var ramlMethods = []string{"get", "post", "put", "patch", "delete", "head", "options"}
+
+// ramlFacets are the RAML type facets that carry over to JSON Schema as is.
+var ramlFacets = []string{"description", "enum", "pattern", "minLength", "maxLength", "minimum", "maximum", "format", "multipleOf", "minItems", "maxItems", "uniqueItems", "minProperties", "maxProperties", "default"}
+
+// ramlDocument is a RAML 1.0 API definition being parsed.
+type ramlDocument struct {
+	location      string
+	files         []string
+	types         map[string]any
+	traits        map[string]any
+	resourceTypes map[string]any
+	schemes       map[string]SecurityScheme
+	mediaTypes    []string
+}
+
+// parseRAML parses a RAML 1.0 API definition read from location, resolving
+// !include files and uses libraries relative to it.
+func parseRAML(content []byte, location string) (*Specification, error) {
+	d := &ramlDocument{
+		location:      location,
+		types:         make(map[string]any),
+		traits:        make(map[string]any),
+		resourceTypes: make(map[string]any),
+	}
+	root, err := d.load(content, location)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := &Specification{
+		Format:     "raml",
+		RawContent: string(content),
+		Endpoints:  []Endpoint{},
+	}
+	if header, _, _ := strings.Cut(string(content), "\n"); strings.HasPrefix(header, "#%RAML") {
+		spec.Version = strings.TrimSpace(strings.TrimPrefix(header, "#%RAML"))
+	}
+
+	if err := d.declarations(root, ""); err != nil {
+		return nil, err
+	}
+	spec.SecuritySchemes = d.schemes
+
+	switch mt := root["mediaType"].(type) {
+	case string:
+		d.mediaTypes = []string{mt}
+	case []any:
+		for _, v := range mt {
+			if s, ok := v.(string); ok {
+				d.mediaTypes = append(d.mediaTypes, s)
+			}
+		}
+	}
+	if len(d.mediaTypes) == 0 {
+		d.mediaTypes = []string{"application/json"}
+	}
+
+	params := d.parameters(root["baseUriParameters"], "path")
+	d.resources(root, "", params, root["securedBy"], &spec.Endpoints)
+	spec.Files = d.files
+	return spec, nil
+}
+
+// load parses a RAML or YAML document with its !include tags resolved.
+func (d *ramlDocument) load(content []byte, location string) (map[string]any, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(content, &node); err != nil {
+		return nil, fmt.Errorf("failed to parse RAML: %w", err)
+	}
+	if err := d.include(&node, location); err != nil {
+		return nil, err
+	}
+	var doc map[string]any
+	if err := node.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse RAML: %w", err)
+	}
+	return doc, nil
+}
+
+// include replaces !include nodes by the file they name: YAML and RAML files
+// are parsed, others (JSON or XML schemas, examples) become strings.
+func (d *ramlDocument) include(node *yaml.Node, location string) error {
+	if node.Tag == "!include" {
+		path := d.resolve(location, strings.TrimSpace(node.Value))
+		content, err := d.read(path)
+		if err != nil {
+			return fmt.Errorf("failed to include %s: %w", node.Value, err)
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".raml", ".yaml", ".yml":
+			var included yaml.Node
+			if err := yaml.Unmarshal(content, &included); err != nil {
+				return fmt.Errorf("failed to parse %s: %w", node.Value, err)
+			}
+			if err := d.include(&included, path); err != nil {
+				return err
+			}
+			if included.Kind == yaml.DocumentNode && len(included.Content) > 0 {
+				*node = *included.Content[0]
+			} else {
+				*node = included
+			}
+		default:
+			*node = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: string(content)}
+		}
+		return nil
+	}
+	for i, child := range node.Content {
+		// Keys such as status codes decode as strings.
+		if node.Kind == yaml.MappingNode && i%2 == 0 && child.Kind == yaml.ScalarNode {
+			child.Tag = "!!str"
+		}
+		if err := d.include(child, location); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolve returns the location of a file referenced from base.
+func (d *ramlDocument) resolve(base, ref string) string {
+	if isHTTPURL(ref) || filepath.IsAbs(ref) {
+		return ref
+	}
+	r := &refResolver{root: d.location}
+	location, _, _ := strings.Cut(r.absolute(base, ref), "#")
+	if location == "" {
+		return d.location
+	}
+	return location
+}
+
+// read reads an included file or URL.
+func (d *ramlDocument) read(location string) ([]byte, error) {
+	if isHTTPURL(location) {
+		return fetchFromURL(location)
+	}
+	content, err := os.ReadFile(location)
+	if err == nil && !slices.Contains(d.files, location) {
+		d.files = append(d.files, location)
+	}
+	return content, err
+}
+
+// declarations collects types, traits, resource types and security schemes
+// of a document and its libraries. Library declarations are prefixed with
+// the library name, e.g. common.User.
+func (d *ramlDocument) declarations(doc map[string]any, prefix string) error {
+	for _, key := range []string{"types", "schemas"} {
+		if types, ok := doc[key].(map[string]any); ok {
+			for name, decl := range types {
+				d.types[prefix+name] = decl
+			}
+		}
+	}
+	if traits, ok := doc["traits"].(map[string]any); ok {
+		for name, trait := range traits {
+			d.traits[prefix+name] = trait
+		}
+	}
+	if resourceTypes, ok := doc["resourceTypes"].(map[string]any); ok {
+		for name, rt := range resourceTypes {
+			d.resourceTypes[prefix+name] = rt
+		}
+	}
+	if schemes, ok := doc["securitySchemes"].(map[string]any); ok {
+		if d.schemes == nil {
+			d.schemes = make(map[string]SecurityScheme)
+		}
+		for name, v := range schemes {
+			if m, ok := v.(map[string]any); ok {
+				d.schemes[prefix+name] = ramlSecurityScheme(prefix+name, m)
+			}
+		}
+	}
+
+	uses, _ := doc["uses"].(map[string]any)
+	for _, name := range slices.Sorted(maps.Keys(uses)) {
+		ref, ok := uses[name].(string)
+		if !ok {
+			continue
+		}
+		location := d.resolve(d.location, ref)
+		content, err := d.read(location)
+		if err != nil {
+			return fmt.Errorf("failed to load library %s: %w", ref, err)
+		}
+		lib, err := d.load(content, location)
+		if err != nil {
+			return err
+		}
+		if err := d.declarations(lib, prefix+name+"."); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ramlSecurityScheme maps a RAML security scheme to its OpenAPI equivalent.
+func ramlSecurityScheme(name string, m map[string]any) SecurityScheme {
+	scheme := SecurityScheme{Name: name}
+	scheme.Description, _ = m["description"].(string)
+	settings, _ := m["settings"].(map[string]any)
+	ramlType, _ := m["type"].(string)
+	switch ramlType {
+	case "OAuth 2.0":
+		scheme.Type = "oauth2"
+		flow := OAuthFlow{}
+		flow.AuthorizationURL, _ = settings["authorizationUri"].(string)
+		flow.TokenURL, _ = settings["accessTokenUri"].(string)
+		if scopes, ok := settings["scopes"].([]any); ok {
+			flow.Scopes = make(map[string]string, len(scopes))
+			for _, s := range scopes {
+				if scope, ok := s.(string); ok {
+					flow.Scopes[scope] = ""
+				}
+			}
+		}
+		grants, _ := settings["authorizationGrants"].([]any)
+		for _, g := range grants {
+			grant, _ := g.(string)
+			f := flow
+			switch grant {
+			case "authorization_code":
+				f.Type = "authorizationCode"
+			case "client_credentials":
+				f.Type = "clientCredentials"
+			case "password", "implicit":
+				f.Type = grant
+			default:
+				continue
+			}
+			scheme.Flows = append(scheme.Flows, f)
+		}
+		slices.SortFunc(scheme.Flows, func(a, b OAuthFlow) int { return strings.Compare(a.Type, b.Type) })
+	case "Basic Authentication":
+		scheme.Type, scheme.Scheme = "http", "basic"
+	case "Digest Authentication":
+		scheme.Type, scheme.Scheme = "http", "digest"
+	case "Pass Through":
+		scheme.Type = "apiKey"
+		describedBy, _ := m["describedBy"].(map[string]any)
+		for _, in := range []string{"headers", "queryParameters"} {
+			if params, ok := describedBy[in].(map[string]any); ok && len(params) > 0 {
+				scheme.In = "header"
+				if in == "queryParameters" {
+					scheme.In = "query"
+				}
+				scheme.ParamName = slices.Sorted(maps.Keys(params))[0]
+				break
+			}
+		}
+	default:
+		scheme.Type = ramlType
+		describedBy, _ := m["describedBy"].(map[string]any)
+		if headers, ok := describedBy["headers"].(map[string]any); ok {
+			if _, ok := headers["Authorization"]; ok {
+				scheme.Type, scheme.Scheme = "http", "bearer"
+			}
+		}
+	}
+	return scheme
+}
+
+// resources walks the nested resources of node and appends an endpoint for
+// every method. URI parameters and securedBy are inherited.
+func (d *ramlDocument) resources(node map[string]any, prefix string, inherited []Parameter, securedBy any, out *[]Endpoint) {
+	for _, key := range slices.Sorted(maps.Keys(node)) {
+		if !strings.HasPrefix(key, "/") {
+			continue
+		}
+		res, _ := node[key].(map[string]any)
+		if res == nil {
+			res = make(map[string]any)
+		}
+		path := prefix + key
+		res = d.applyResourceType(res, path)
+
+		params := slices.Clone(inherited)
+		params = mergeParameters(params, d.parameters(res["uriParameters"], "path"))
+		for _, name := range templateNames(key) {
+			if !slices.ContainsFunc(params, func(p Parameter) bool { return p.In == "path" && p.Name == name }) {
+				params = append(params, Parameter{Name: name, In: "path", Type: "string", Required: true, Schema: map[string]any{"type": "string"}})
+			}
+		}
+
+		resSecuredBy := securedBy
+		if v, ok := res["securedBy"]; ok {
+			resSecuredBy = v
+		}
+		for _, method := range ramlMethods {
+			m, ok := res[method].(map[string]any)
+			if !ok {
+				if _, present := res[method]; !present {
+					continue
+				}
+				m = make(map[string]any)
+			}
+			*out = append(*out, d.endpoint(path, method, res, m, params, resSecuredBy))
+		}
+		d.resources(res, path, params, resSecuredBy, out)
+	}
+}
+
+// templateNames returns the names of {params} in a URI template.
+func templateNames(uri string) []string {
+	var names []string
+	for {
+		start := strings.Index(uri, "{")
+		end := strings.Index(uri, "}")
+		if start < 0 || end < start {
+			return names
+		}
+		names = append(names, uri[start+1:end])
+		uri = uri[end+1:]
+	}
+}
+
+// endpoint builds the endpoint of a method with the traits it and its
+// resource apply merged in.
+func (d *ramlDocument) endpoint(path, method string, res, m map[string]any, uriParams []Parameter, securedBy any) Endpoint {
+	vars := map[string]string{
+		"resourcePath":     path,
+		"resourcePathName": resourcePathName(path),
+		"methodName":       method,
+	}
+	m = maps.Clone(m)
+	for _, is := range []any{m["is"], res["is"]} {
+		for _, trait := range d.references(is, d.traits, vars) {
+			deepMerge(m, trait)
+		}
+	}
+
+	ep := Endpoint{
+		Method:          strings.ToUpper(method),
+		Path:            path,
+		Responses:       make(map[string]string),
+		ResponseSchemas: make(map[string]map[string]any),
+		AuthType:        "none",
+	}
+	ep.Summary, _ = m["displayName"].(string)
+	ep.Description, _ = m["description"].(string)
+	if ep.Description == "" {
+		ep.Description = ep.Summary
+	}
+
+	ep.Parameters = slices.Clone(uriParams)
+	ep.Parameters = mergeParameters(ep.Parameters, d.parameters(m["queryParameters"], "query"))
+	ep.Parameters = mergeParameters(ep.Parameters, d.parameters(m["headers"], "header"))
+
+	if body, ok := m["body"]; ok {
+		ep.RequestContent = d.bodies(body)
+		ep.RequestBodyRequired = len(ep.RequestContent) > 0
+		if media, ok := preferredMediaType(ep.RequestContent); ok {
+			if isJSON(media.ContentType) {
+				ep.RequestSchema = media.Schema
+			}
+			if len(media.Examples) > 0 {
+				ep.RequestBody = exampleBody(media.Examples[0].Value)
+			}
+		}
+	}
+
+	responses, _ := m["responses"].(map[string]any)
+	for code, v := range responses {
+		status := code
+		resp, _ := v.(map[string]any)
+		ep.Responses[status], _ = resp["description"].(string)
+		content := d.bodies(resp["body"])
+		if len(content) == 0 {
+			continue
+		}
+		if ep.ResponseContent == nil {
+			ep.ResponseContent = make(map[string][]MediaType)
+		}
+		ep.ResponseContent[status] = content
+		if media, ok := preferredMediaType(content); ok && isJSON(media.ContentType) {
+			ep.ResponseSchemas[status] = media.Schema
+			if len(media.Examples) > 0 {
+				if ep.ResponseExamples == nil {
+					ep.ResponseExamples = make(map[string]any)
+				}
+				ep.ResponseExamples[status] = media.Examples[0].Value
+			}
+		}
+	}
+
+	if v, ok := m["securedBy"]; ok {
+		securedBy = v
+	}
+	ep.Security = d.security(securedBy)
+	if len(ep.Security) > 0 {
+		ep.RequiresAuth = true
+		ep.AuthType = securityAuthType(ep.Security)
+	}
+	return ep
+}
+
+// resourcePathName is the last segment of a resource path that is not a
+// URI parameter, e.g. users for /users/{id}.
+func resourcePathName(path string) string {
+	segments := strings.Split(path, "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		if s := segments[i]; s != "" && !strings.HasPrefix(s, "{") {
+			return s
+		}
+	}
+	return ""
+}
+
+// applyResourceType merges the resource type a resource declares into it.
+// Optional methods (get?) apply only if the resource has the method.
+func (d *ramlDocument) applyResourceType(res map[string]any, path string) map[string]any {
+	vars := map[string]string{"resourcePath": path, "resourcePathName": resourcePathName(path)}
+	types := d.references(res["type"], d.resourceTypes, vars)
+	if len(types) == 0 {
+		return res
+	}
+	res = maps.Clone(res)
+	for key, v := range types[0] {
+		if optional, ok := strings.CutSuffix(key, "?"); ok {
+			if m, ok := res[optional].(map[string]any); ok {
+				if vm, ok := v.(map[string]any); ok {
+					m = maps.Clone(m)
+					deepMerge(m, vm)
+					res[optional] = m
+				}
+			}
+			continue
+		}
+		if existing, ok := res[key].(map[string]any); ok {
+			if vm, ok := v.(map[string]any); ok {
+				existing = maps.Clone(existing)
+				deepMerge(existing, vm)
+				res[key] = existing
+			}
+		} else if _, ok := res[key]; !ok || res[key] == nil {
+			res[key] = v
+		}
+	}
+	return res
+}
+
+// references returns the declarations a type or is value names, with
+// <<parameters>> replaced. Values are a name, a {name: {params}} map or a
+// list of them.
+func (d *ramlDocument) references(v any, decls map[string]any, vars map[string]string) []map[string]any {
+	var out []map[string]any
+	add := func(name string, params map[string]any) {
+		decl, ok := decls[name].(map[string]any)
+		if !ok {
+			return
+		}
+		values := maps.Clone(vars)
+		for k, p := range params {
+			values[k] = fmt.Sprint(p)
+		}
+		if m, ok := substitute(decl, values).(map[string]any); ok {
+			out = append(out, m)
+		}
+	}
+	switch val := v.(type) {
+	case string:
+		add(val, nil)
+	case map[string]any:
+		for name, params := range val {
+			p, _ := params.(map[string]any)
+			add(name, p)
+		}
+	case []any:
+		for _, item := range val {
+			out = append(out, d.references(item, decls, vars)...)
+		}
+	}
+	return out
+}
+
+// ramlPlaceholder matches a <<name>> parameter with an optional transform
+// function, e.g. <<resourcePathName | !singularize>>.
+var ramlPlaceholder = regexp.MustCompile(`<<\s*(\w+)\s*(?:\|\s*!(\w+)\s*)?>>`)
+
+// substitute returns a copy of v with <<name>> placeholders replaced in
+// keys and strings.
+func substitute(v any, vars map[string]string) any {
+	replace := func(s string) string {
+		return ramlPlaceholder.ReplaceAllStringFunc(s, func(match string) string {
+			m := ramlPlaceholder.FindStringSubmatch(match)
+			val, ok := vars[m[1]]
+			if !ok {
+				return match
+			}
+			return ramlTransform(val, m[2])
+		})
+	}
+	switch val := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, item := range val {
+			out[replace(k)] = substitute(item, vars)
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = substitute(item, vars)
+		}
+		return out
+	case string:
+		return replace(val)
+	}
+	return v
+}
+
+// ramlTransform applies a parameter transform function. The singular and
+// plural forms only handle regular English nouns.
+func ramlTransform(s, fn string) string {
+	switch strings.ToLower(fn) {
+	case "singularize":
+		switch {
+		case strings.HasSuffix(s, "ies"):
+			return strings.TrimSuffix(s, "ies") + "y"
+		case strings.HasSuffix(s, "ses"), strings.HasSuffix(s, "xes"):
+			return strings.TrimSuffix(s, "es")
+		case strings.HasSuffix(s, "s") && !strings.HasSuffix(s, "ss"):
+			return strings.TrimSuffix(s, "s")
+		}
+	case "pluralize":
+		switch {
+		case strings.HasSuffix(s, "y") && !strings.ContainsAny(s[max(len(s)-2, 0):len(s)-1], "aeiou"):
+			return strings.TrimSuffix(s, "y") + "ies"
+		case strings.HasSuffix(s, "s"), strings.HasSuffix(s, "x"):
+			return s + "es"
+		default:
+			return s + "s"
+		}
+	case "uppercase":
+		return strings.ToUpper(s)
+	case "lowercase":
+		return strings.ToLower(s)
+	case "uppercamelcase", "lowercamelcase":
+		var b strings.Builder
+		for i, word := range strings.FieldsFunc(s, func(r rune) bool { return r == '-' || r == '_' || r == ' ' }) {
+			if i == 0 && strings.ToLower(fn) == "lowercamelcase" {
+				b.WriteString(strings.ToLower(word))
+				continue
+			}
+			b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+		return b.String()
+	}
+	return s
+}
+
+// deepMerge adds the values of src that dst does not have, merging nested
+// maps. Values already in dst win.
+func deepMerge(dst, src map[string]any) {
+	for k, v := range src {
+		existing, ok := dst[k]
+		if !ok || existing == nil {
+			dst[k] = v
+			continue
+		}
+		em, ok1 := existing.(map[string]any)
+		vm, ok2 := v.(map[string]any)
+		if ok1 && ok2 {
+			em = maps.Clone(em)
+			deepMerge(em, vm)
+			dst[k] = em
+		}
+	}
+}
+
+// mergeParameters adds params to list, replacing parameters of the same
+// name and location.
+func mergeParameters(list, params []Parameter) []Parameter {
+	for _, p := range params {
+		i := slices.IndexFunc(list, func(q Parameter) bool { return q.In == p.In && q.Name == p.Name })
+		if i >= 0 {
+			list[i] = p
+		} else {
+			list = append(list, p)
+		}
+	}
+	return list
+}
+
+// parameters reads uriParameters, queryParameters or headers. RAML 1.0
+// parameters are required unless their name ends in ? or required is false.
+func (d *ramlDocument) parameters(v any, in string) []Parameter {
+	decls, _ := v.(map[string]any)
+	var out []Parameter
+	for _, key := range slices.Sorted(maps.Keys(decls)) {
+		name, optional := strings.CutSuffix(key, "?")
+		param := Parameter{Name: name, In: in, Required: !optional}
+		if m, ok := decls[key].(map[string]any); ok {
+			if required, ok := m["required"].(bool); ok {
+				param.Required = required
+			}
+			param.Description, _ = m["description"].(string)
+		}
+		param.Schema = d.schema(decls[key])
+		param.Type, _ = param.Schema["type"].(string)
+		param.Format, _ = param.Schema["format"].(string)
+		param.Enum, _ = param.Schema["enum"].([]any)
+		param.Default = param.Schema["default"]
+		if example, ok := param.Schema["example"]; ok {
+			param.Examples = []Example{{Name: "default", Value: example}}
+		}
+		out = append(out, param)
+	}
+	return out
+}
+
+// bodies reads a body: a map of media types, or a type declaration for the
+// API's default media types.
+func (d *ramlDocument) bodies(v any) []MediaType {
+	if v == nil {
+		return nil
+	}
+	var out []MediaType
+	body, ok := v.(map[string]any)
+	if ok && slices.ContainsFunc(slices.Collect(maps.Keys(body)), func(k string) bool { return strings.Contains(k, "/") }) {
+		for contentType, decl := range body {
+			out = append(out, d.mediaType(contentType, decl))
+		}
+	} else {
+		for _, contentType := range d.mediaTypes {
+			out = append(out, d.mediaType(contentType, v))
+		}
+	}
+	sortMediaTypes(out)
+	return out
+}
+
+func (d *ramlDocument) mediaType(contentType string, decl any) MediaType {
+	media := MediaType{ContentType: contentType, Schema: d.schema(decl)}
+	if example, ok := media.Schema["example"]; ok {
+		if s, ok := example.(string); ok && isJSON(contentType) {
+			var parsed any
+			if err := json.Unmarshal([]byte(s), &parsed); err == nil {
+				example = parsed
+			}
+		}
+		media.Examples = []Example{{Name: "default", Value: example}}
+	}
+	return media
+}
+
+// security reads a securedBy list. null makes authentication optional and
+// {name: {scopes: [...]}} requires scopes.
+func (d *ramlDocument) security(v any) []SecurityRequirement {
+	items, _ := v.([]any)
+	var reqs []SecurityRequirement
+	for _, item := range items {
+		var name string
+		var params map[string]any
+		switch val := item.(type) {
+		case string:
+			name = val
+		case map[string]any:
+			for k, p := range val {
+				name = k
+				params, _ = p.(map[string]any)
+			}
+		}
+		scheme, ok := d.schemes[name]
+		if !ok {
+			continue
+		}
+		if scopes, ok := params["scopes"].([]any); ok {
+			for _, s := range scopes {
+				if scope, ok := s.(string); ok {
+					scheme.Scopes = append(scheme.Scopes, scope)
+				}
+			}
+		}
+		reqs = append(reqs, SecurityRequirement{Schemes: []SecurityScheme{scheme}})
+	}
+	return reqs
+}
+
+// schema converts a RAML type declaration to JSON Schema. Recursive types
+// are kept as #/$defs/<name> references, as refResolver.schema does.
+func (d *ramlDocument) schema(decl any) map[string]any {
+	c := &ramlConverter{d: d, defs: make(map[string]any)}
+	schema := c.convert(decl)
+	if len(c.defs) > 0 {
+		schema = maps.Clone(schema)
+		schema["$defs"] = c.defs
+	}
+	return schema
+}
+
+// ramlConverter converts the type declarations of one schema.
+type ramlConverter struct {
+	d     *ramlDocument
+	stack []string
+	defs  map[string]any
+}
+
+func (c *ramlConverter) convert(decl any) map[string]any {
+	switch val := decl.(type) {
+	case nil:
+		return map[string]any{"type": "string"}
+	case string:
+		return c.expression(val)
+	case []any:
+		// Multiple inheritance: properties of all types.
+		schema := map[string]any{"type": "object"}
+		for _, item := range val {
+			if s, ok := item.(string); ok {
+				mergeObjectSchema(schema, c.expression(s))
+			}
+		}
+		return schema
+	case map[string]any:
+		return c.declaration(val)
+	}
+	return map[string]any{}
+}
+
+// declaration converts a type declaration map: a base type with facets.
+func (c *ramlConverter) declaration(m map[string]any) map[string]any {
+	base, hasBase := m["type"]
+	if !hasBase {
+		base, hasBase = m["schema"]
+	}
+	var schema map[string]any
+	switch {
+	case hasBase:
+		schema = maps.Clone(c.convert(base))
+	case m["properties"] != nil:
+		schema = map[string]any{"type": "object"}
+	case m["items"] != nil:
+		schema = map[string]any{"type": "array"}
+	default:
+		schema = map[string]any{"type": "string"}
+	}
+
+	for _, facet := range ramlFacets {
+		if v, ok := m[facet]; ok {
+			schema[facet] = v
+		}
+	}
+	if items, ok := m["items"]; ok {
+		schema["items"] = c.convert(items)
+	}
+	if props, ok := m["properties"].(map[string]any); ok {
+		properties := make(map[string]any)
+		if existing, ok := schema["properties"].(map[string]any); ok {
+			properties = maps.Clone(existing)
+		}
+		required, _ := schema["required"].([]any)
+		required = slices.Clone(required)
+		for _, key := range slices.Sorted(maps.Keys(props)) {
+			name, optional := strings.CutSuffix(key, "?")
+			if pm, ok := props[key].(map[string]any); ok {
+				if r, ok := pm["required"].(bool); ok {
+					optional = !r
+				}
+			}
+			properties[name] = c.convert(props[key])
+			if !optional && !slices.Contains(required, any(name)) {
+				required = append(required, name)
+			}
+		}
+		schema["type"] = "object"
+		schema["properties"] = properties
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+	}
+	if ap, ok := m["additionalProperties"].(bool); ok {
+		schema["additionalProperties"] = ap
+	}
+	if example, ok := m["example"]; ok {
+		schema["example"] = example
+	} else if examples, ok := m["examples"].(map[string]any); ok && len(examples) > 0 {
+		first := examples[slices.Sorted(maps.Keys(examples))[0]]
+		if em, ok := first.(map[string]any); ok {
+			if value, ok := em["value"]; ok {
+				first = value
+			}
+		}
+		schema["example"] = first
+	}
+	return schema
+}
+
+// expression converts a type expression: a built-in or declared type name,
+// Type[], A | B, or an inline JSON schema.
+func (c *ramlConverter) expression(expr string) map[string]any {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "{") {
+		var schema map[string]any
+		if err := json.Unmarshal([]byte(expr), &schema); err == nil {
+			delete(schema, "$schema")
+			return schema
+		}
+		return map[string]any{}
+	}
+	if strings.HasPrefix(expr, "<") {
+		// XML schemas are not converted.
+		return map[string]any{}
+	}
+	if strings.HasPrefix(expr, "(") && strings.HasSuffix(expr, ")") && !strings.Contains(expr[1:], "(") {
+		expr = expr[1 : len(expr)-1]
+	}
+	if strings.Contains(expr, "|") {
+		var anyOf []any
+		for _, part := range strings.Split(expr, "|") {
+			anyOf = append(anyOf, c.expression(part))
+		}
+		return map[string]any{"anyOf": anyOf}
+	}
+	if item, ok := strings.CutSuffix(expr, "[]"); ok {
+		return map[string]any{"type": "array", "items": c.expression(item)}
+	}
+
+	switch expr {
+	case "string", "number", "integer", "boolean", "object", "array":
+		return map[string]any{"type": expr}
+	case "nil":
+		return map[string]any{"type": "null"}
+	case "any", "":
+		return map[string]any{}
+	case "date-only":
+		return map[string]any{"type": "string", "format": "date"}
+	case "time-only":
+		return map[string]any{"type": "string", "format": "time"}
+	case "datetime", "datetime-only":
+		return map[string]any{"type": "string", "format": "date-time"}
+	case "file":
+		return map[string]any{"type": "string", "format": "binary"}
+	}
+
+	decl, ok := c.d.types[expr]
+	if !ok && len(c.stack) > 0 {
+		// Types of a library refer to each other without the library name.
+		top := c.stack[len(c.stack)-1]
+		if i := strings.LastIndex(top, "."); i >= 0 {
+			expr = top[:i+1] + expr
+			decl, ok = c.d.types[expr]
+		}
+	}
+	if !ok {
+		return map[string]any{}
+	}
+	if slices.Contains(c.stack, expr) {
+		c.defs[expr] = nil
+		return map[string]any{"$ref": "#/$defs/" + expr}
+	}
+	c.stack = append(c.stack, expr)
+	schema := c.convert(decl)
+	c.stack = c.stack[:len(c.stack)-1]
+	if _, recursive := c.defs[expr]; recursive {
+		c.defs[expr] = schema
+	}
+	return schema
+}
+
+// mergeObjectSchema adds the properties and required names of src to dst.
+func mergeObjectSchema(dst, src map[string]any) {
+	props, _ := dst["properties"].(map[string]any)
+	if props == nil {
+		props = make(map[string]any)
+	}
+	if srcProps, ok := src["properties"].(map[string]any); ok {
+		maps.Copy(props, srcProps)
+	}
+	if len(props) > 0 {
+		dst["properties"] = props
+	}
+	required, _ := dst["required"].([]any)
+	if srcRequired, ok := src["required"].([]any); ok {
+		for _, r := range srcRequired {
+			if !slices.Contains(required, r) {
+				required = append(required, r)
+			}
+		}
+	}
+	if len(required) > 0 {
+		dst["required"] = required
+	}
+}
+
+// isRAML reports whether content starts with a RAML header.
+func isRAML(content []byte) bool {
+	return strings.HasPrefix(strings.TrimSpace(string(content)), "#%RAML")
+}
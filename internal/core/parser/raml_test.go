@@ -0,0 +1,194 @@
+package parser
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseRAML(t *testing.T) {
+	dir := t.TempDir()
+	writeSpecFile(t, dir, "libraries/common.raml", `#%RAML 1.0 Library
+types:
+  Error:
+    properties:
+      message: string
+      code?: integer
+`)
+	writeSpecFile(t, dir, "schemas/user.json", `{"type": "object", "required": ["id"], "properties": {"id": {"type": "integer"}}}`)
+	writeSpecFile(t, dir, "traits/paged.raml", `queryParameters:
+  page?:
+    type: integer
+    default: 1
+  limit?:
+    type: integer
+    maximum: 100
+`)
+	root := writeSpecFile(t, dir, "api.raml", `#%RAML 1.0
+title: Users API
+baseUri: https://api.example.com/{version}
+baseUriParameters:
+  version:
+    enum: [v1, v2]
+mediaType: application/json
+uses:
+  common: libraries/common.raml
+securitySchemes:
+  oauth:
+    type: OAuth 2.0
+    settings:
+      accessTokenUri: https://auth.example.com/token
+      authorizationGrants: [client_credentials]
+      scopes: [read, write]
+securedBy: [oauth]
+types:
+  User: !include schemas/user.json
+  NewUser:
+    type: object
+    properties:
+      name:
+        type: string
+        minLength: 1
+      email?: string
+      role:
+        enum: [admin, member]
+    example:
+      name: Ada
+      role: admin
+  Node:
+    properties:
+      children: Node[]
+traits:
+  paged: !include traits/paged.raml
+  errors:
+    responses:
+      400:
+        description: Invalid <<resourcePathName>>
+        body: common.Error
+resourceTypes:
+  collection:
+    get?:
+      description: List <<resourcePathName>>
+    post?:
+      description: Create a <<resourcePathName | !singularize>>
+/users:
+  type: collection
+  is: [errors]
+  get:
+    is: [paged]
+    responses:
+      200:
+        body:
+          type: User[]
+  post:
+    body: NewUser
+    responses:
+      201:
+        body:
+          application/json:
+            type: User
+            example: '{"id": 1}'
+  /{userId}:
+    uriParameters:
+      userId:
+        type: integer
+    get:
+      securedBy: [null]
+    delete:
+      securedBy: [{oauth: {scopes: [write]}}]
+      headers:
+        X-Request-Id?: string
+/tree:
+  get:
+    responses:
+      200:
+        body: Node
+`)
+
+	spec, err := ParseSpecification(root)
+	if err != nil {
+		t.Fatalf("ParseSpecification failed: %v", err)
+	}
+	if spec.Format != "raml" || spec.Version != "1.0" {
+		t.Errorf("expected raml 1.0, got %s %s", spec.Format, spec.Version)
+	}
+	if len(spec.Endpoints) != 5 {
+		t.Fatalf("expected 5 endpoints, got %d: %+v", len(spec.Endpoints), spec.Endpoints)
+	}
+	for _, name := range []string{"common.raml", "user.json", "paged.raml"} {
+		if !slices.ContainsFunc(spec.Files, func(f string) bool { return f[len(f)-len(name):] == name }) {
+			t.Errorf("expected %s in spec files %v", name, spec.Files)
+		}
+	}
+
+	list := findAsyncEndpoint(t, spec, "GET", "/users")
+	if list.Description != "List users" {
+		t.Errorf("expected description from the resource type, got %q", list.Description)
+	}
+	if page := findParam(list.Parameters, "page"); page == nil || page.In != "query" || page.Required || page.Default != 1 {
+		t.Errorf("expected optional page query parameter from the trait, got %+v", page)
+	}
+	if version := findParam(list.Parameters, "version"); version == nil || version.In != "path" || len(version.Enum) != 2 {
+		t.Errorf("expected version base URI parameter, got %+v", version)
+	}
+	if list.Responses["400"] != "Invalid users" {
+		t.Errorf("expected error response from the resource trait, got %v", list.Responses)
+	}
+	if errSchema := list.ResponseSchemas["400"]; errSchema["required"] == nil || len(errSchema["required"].([]any)) != 1 {
+		t.Errorf("expected library Error schema with one required property, got %v", errSchema)
+	}
+	items, _ := list.ResponseSchemas["200"]["items"].(map[string]any)
+	if list.ResponseSchemas["200"]["type"] != "array" || items["type"] != "object" {
+		t.Errorf("expected array of included JSON schema, got %v", list.ResponseSchemas["200"])
+	}
+	if !list.RequiresAuth || list.AuthType != "oauth2" {
+		t.Errorf("expected oauth2 from the root securedBy, got %v %q", list.RequiresAuth, list.AuthType)
+	}
+
+	create := findAsyncEndpoint(t, spec, "POST", "/users")
+	if create.Description != "Create a user" {
+		t.Errorf("expected description from the resource type, got %q", create.Description)
+	}
+	if create.RequestBody != `{"name":"Ada","role":"admin"}` {
+		t.Errorf("expected example request body, got %q", create.RequestBody)
+	}
+	props, _ := create.RequestSchema["properties"].(map[string]any)
+	if name, _ := props["name"].(map[string]any); name["minLength"] != 1 {
+		t.Errorf("expected minLength facet on name, got %v", props["name"])
+	}
+	if required, _ := create.RequestSchema["required"].([]any); !slices.Contains(required, any("name")) || slices.Contains(required, any("email")) {
+		t.Errorf("expected name required and email optional, got %v", create.RequestSchema["required"])
+	}
+	if example, _ := create.ResponseExamples["201"].(map[string]any); example["id"] != float64(1) {
+		t.Errorf("expected parsed JSON response example, got %v", create.ResponseExamples["201"])
+	}
+
+	get := findAsyncEndpoint(t, spec, "GET", "/users/{userId}")
+	if userID := findParam(get.Parameters, "userId"); userID == nil || userID.Type != "integer" || !userID.Required {
+		t.Errorf("expected integer userId, got %+v", userID)
+	}
+	if get.RequiresAuth {
+		t.Error("expected securedBy null to make the endpoint public")
+	}
+
+	del := findAsyncEndpoint(t, spec, "DELETE", "/users/{userId}")
+	if len(del.Security) != 1 || !slices.Contains(del.Security[0].Schemes[0].Scopes, "write") {
+		t.Errorf("expected write scope, got %+v", del.Security)
+	}
+	if header := findParam(del.Parameters, "X-Request-Id"); header == nil || header.In != "header" || header.Required {
+		t.Errorf("expected optional header, got %+v", header)
+	}
+
+	tree := findAsyncEndpoint(t, spec, "GET", "/tree")
+	if tree.ResponseSchemas["200"]["$defs"] == nil {
+		t.Errorf("expected recursive type kept in $defs, got %v", tree.ResponseSchemas["200"])
+	}
+}
+
+func findParam(params []Parameter, name string) *Parameter {
+	for i := range params {
+		if params[i].Name == name {
+			return &params[i]
+		}
+	}
+	return nil
+}
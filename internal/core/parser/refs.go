@@ -0,0 +1,282 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// refResolver resolves $ref pointers across the documents of a spec: the
+// root document, files relative to it and URLs. When a document is loaded,
+// each of its references is rewritten to the absolute form location#pointer,
+// with "" as the location of the root document, so a reference resolves the
+// same way wherever it is used.
+type refResolver struct {
+	root  string         // location of the root document, if known
+	docs  map[string]any // location -> document
+	files []string       // local files referenced by the spec, in load order
+}
+
+// newRefResolver loads every document the root references, recursively.
+func newRefResolver(root map[string]any, location string) (*refResolver, error) {
+	r := &refResolver{root: location, docs: map[string]any{"": root}}
+	if err := r.rewrite(root, ""); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// rewrite makes the references in a node of the document at location
+// absolute and loads the documents they point to.
+func (r *refResolver) rewrite(node any, location string) error {
+	switch val := node.(type) {
+	case map[string]any:
+		if ref, ok := val["$ref"].(string); ok {
+			abs := r.absolute(location, ref)
+			val["$ref"] = abs
+			target, _, _ := strings.Cut(abs, "#")
+			if _, loaded := r.docs[target]; !loaded {
+				if err := r.load(target); err != nil {
+					return fmt.Errorf("failed to resolve $ref %s: %w", ref, err)
+				}
+			}
+		}
+		for _, key := range slices.Sorted(maps.Keys(val)) {
+			if err := r.rewrite(val[key], location); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, item := range val {
+			if err := r.rewrite(item, location); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// absolute resolves a reference found in the document at base.
+func (r *refResolver) absolute(base, ref string) string {
+	location, fragment, _ := strings.Cut(ref, "#")
+	if location == "" {
+		return base + "#" + fragment
+	}
+
+	baseLocation := base
+	if baseLocation == "" {
+		baseLocation = r.root
+	}
+	switch {
+	case isHTTPURL(location):
+	case isHTTPURL(baseLocation):
+		if b, err := url.Parse(baseLocation); err == nil {
+			if l, err := url.Parse(location); err == nil {
+				location = b.ResolveReference(l).String()
+			}
+		}
+	default:
+		if !filepath.IsAbs(location) {
+			location = filepath.Join(filepath.Dir(baseLocation), filepath.FromSlash(location))
+		}
+		location = filepath.Clean(location)
+	}
+	if location == r.root {
+		location = ""
+	}
+	return location + "#" + fragment
+}
+
+// load reads a referenced file or URL.
+func (r *refResolver) load(location string) error {
+	var content []byte
+	var err error
+	if isHTTPURL(location) {
+		content, err = fetchFromURL(location)
+	} else {
+		content, err = os.ReadFile(location)
+	}
+	if err != nil {
+		return err
+	}
+
+	var doc any
+	if err := json.Unmarshal(content, &doc); err != nil {
+		if err := yaml.Unmarshal(content, &doc); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", location, err)
+		}
+	}
+	r.docs[location] = doc
+	if !isHTTPURL(location) {
+		r.files = append(r.files, location)
+	}
+	return r.rewrite(doc, location)
+}
+
+// lookup returns the node an absolute reference points to.
+func (r *refResolver) lookup(ref string) (any, bool) {
+	location, fragment, _ := strings.Cut(ref, "#")
+	doc, ok := r.docs[location]
+	if !ok {
+		return nil, false
+	}
+	return jsonPointer(doc, fragment)
+}
+
+// jsonPointer evaluates a URI fragment such as /components/schemas/User,
+// with percent-encoding and ~0/~1 escapes (RFC 6901).
+func jsonPointer(doc any, fragment string) (any, bool) {
+	if unescaped, err := url.PathUnescape(fragment); err == nil {
+		fragment = unescaped
+	}
+	if fragment == "" {
+		return doc, true
+	}
+	if !strings.HasPrefix(fragment, "/") {
+		return nil, false
+	}
+	current := doc
+	for _, token := range strings.Split(fragment[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch val := current.(type) {
+		case map[string]any:
+			next, ok := val[token]
+			if !ok {
+				return nil, false
+			}
+			current = next
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(val) {
+				return nil, false
+			}
+			current = val[i]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// deref follows $ref until it reaches an object that is not a reference,
+// e.g. a parameter, response or request body defined in components.
+func (r *refResolver) deref(v any) (map[string]any, bool) {
+	m, ok := v.(map[string]any)
+	for range 32 {
+		if !ok {
+			return nil, false
+		}
+		ref, isRef := m["$ref"].(string)
+		if !isRef {
+			return m, true
+		}
+		var target any
+		if target, ok = r.lookup(ref); ok {
+			m, ok = target.(map[string]any)
+		}
+	}
+	return nil, false
+}
+
+// schema returns a schema with every reference inlined. References back
+// into a schema that is being inlined would never end, so they are kept as
+// #/$defs/<name> and the schema they point to is added to $defs of the
+// returned schema, as JSON Schema bundles do.
+func (r *refResolver) schema(v any) map[string]any {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil
+	}
+	b := &schemaBundle{r: r, names: make(map[string]string), defs: make(map[string]any)}
+	resolved, ok := b.resolve(m).(map[string]any)
+	if !ok {
+		return nil
+	}
+	if len(b.defs) > 0 {
+		resolved = maps.Clone(resolved)
+		resolved["$defs"] = b.defs
+	}
+	return resolved
+}
+
+// schemaBundle inlines the references of one schema.
+type schemaBundle struct {
+	r     *refResolver
+	stack []string          // references being inlined
+	names map[string]string // recursive reference -> $defs name
+	defs  map[string]any
+}
+
+func (b *schemaBundle) resolve(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		if ref, ok := val["$ref"].(string); ok {
+			return b.ref(ref, val)
+		}
+		out := make(map[string]any, len(val))
+		for k, item := range val {
+			out[k] = b.resolve(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = b.resolve(item)
+		}
+		return out
+	}
+	return v
+}
+
+func (b *schemaBundle) ref(ref string, node map[string]any) any {
+	if slices.Contains(b.stack, ref) {
+		return map[string]any{"$ref": "#/$defs/" + b.name(ref)}
+	}
+	target, ok := b.r.lookup(ref)
+	if !ok {
+		return maps.Clone(node)
+	}
+
+	b.stack = append(b.stack, ref)
+	resolved := b.resolve(target)
+	b.stack = b.stack[:len(b.stack)-1]
+
+	if name, recursive := b.names[ref]; recursive {
+		if m, ok := resolved.(map[string]any); ok {
+			b.defs[name] = maps.Clone(m)
+		} else {
+			b.defs[name] = resolved
+		}
+	}
+	return resolved
+}
+
+// name returns the $defs name of a recursive reference: the last pointer
+// token, e.g. Node for ./tree.yaml#/Node, made unique if needed.
+func (b *schemaBundle) name(ref string) string {
+	if name, ok := b.names[ref]; ok {
+		return name
+	}
+	location, fragment, _ := strings.Cut(ref, "#")
+	base := fragment[strings.LastIndex(fragment, "/")+1:]
+	if base == "" {
+		base = strings.TrimSuffix(filepath.Base(location), filepath.Ext(location))
+	}
+	if base == "" || base == "." {
+		base = "schema"
+	}
+	name := base
+	for i := 2; slices.Contains(slices.Collect(maps.Values(b.names)), name); i++ {
+		name = base + strconv.Itoa(i)
+	}
+	b.names[ref] = name
+	return name
+}
@@ -0,0 +1,144 @@
+package parser
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeSpecFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestParseSpecification_MultiFileRefs(t *testing.T) {
+	shared := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"Error": {"type": "object", "properties": {"message": {"type": "string"}}}}`))
+	}))
+	defer shared.Close()
+
+	dir := t.TempDir()
+	writeSpecFile(t, dir, "schemas/user.yaml", `
+User:
+  type: object
+  properties:
+    name: {type: string}
+    manager: {$ref: "#/User"}
+    team: {$ref: "./team.yaml"}
+`)
+	writeSpecFile(t, dir, "schemas/team.yaml", `
+type: object
+properties:
+  members:
+    type: array
+    items: {$ref: "user.yaml#/User"}
+`)
+	writeSpecFile(t, dir, "common/parameters.yaml", `
+UserId: {name: id, in: path, required: true, schema: {type: integer}}
+`)
+	writeSpecFile(t, dir, "paths/users.yaml", `
+get:
+  operationId: getUser
+  parameters:
+    - $ref: "../common/parameters.yaml#/UserId"
+  responses:
+    "200":
+      description: OK
+      content:
+        application/json:
+          schema: {$ref: "../schemas/user.yaml#/User"}
+    "404":
+      $ref: "../openapi.yaml#/components/responses/Missing"
+`)
+	root := writeSpecFile(t, dir, "openapi.yaml", `
+openapi: "3.0.0"
+paths:
+  /users/{id}:
+    $ref: "./paths/users.yaml"
+  /a~b/{x}:
+    get:
+      responses:
+        "200": {description: OK, content: {application/json: {schema: {$ref: "#/components/schemas/%7BItem%7D~1v1"}}}}
+components:
+  schemas:
+    "{Item}/v1": {type: object}
+  responses:
+    Missing:
+      description: Not found
+      content:
+        application/json:
+          schema: {$ref: "`+shared.URL+`/errors.json#/Error"}
+`)
+
+	spec, err := ParseSpecification(root)
+	if err != nil {
+		t.Fatalf("ParseSpecification failed: %v", err)
+	}
+	if len(spec.Files) != 4 {
+		t.Errorf("expected 4 referenced files, got %v", spec.Files)
+	}
+
+	var ep, escaped *Endpoint
+	for i := range spec.Endpoints {
+		switch spec.Endpoints[i].Path {
+		case "/users/{id}":
+			ep = &spec.Endpoints[i]
+		case "/a~b/{x}":
+			escaped = &spec.Endpoints[i]
+		}
+	}
+	if ep == nil || escaped == nil {
+		t.Fatalf("expected both endpoints, got %+v", spec.Endpoints)
+	}
+
+	if ep.OperationID != "getUser" || len(ep.Parameters) != 1 || ep.Parameters[0].Type != "integer" {
+		t.Errorf("expected operation and parameter from referenced files, got %q %+v", ep.OperationID, ep.Parameters)
+	}
+
+	user := ep.ResponseSchemas["200"]
+	props, _ := user["properties"].(map[string]any)
+	if manager, _ := props["manager"].(map[string]any); manager["$ref"] != "#/$defs/User" {
+		t.Errorf("expected recursive manager reference, got %v", props["manager"])
+	}
+	team, _ := props["team"].(map[string]any)
+	members, _ := team["properties"].(map[string]any)["members"].(map[string]any)
+	if items, _ := members["items"].(map[string]any); items["$ref"] != "#/$defs/User" {
+		t.Errorf("expected cross-file cycle through team to stay a reference, got %v", members["items"])
+	}
+	if defs, _ := user["$defs"].(map[string]any); defs["User"] == nil {
+		t.Errorf("expected User in $defs, got %v", user["$defs"])
+	}
+
+	if ep.Responses["404"] != "Not found" {
+		t.Errorf("expected response referenced back into the root document, got %v", ep.Responses)
+	}
+	errProps, _ := ep.ResponseSchemas["404"]["properties"].(map[string]any)
+	if _, ok := errProps["message"]; !ok {
+		t.Errorf("expected schema from URL, got %v", ep.ResponseSchemas["404"])
+	}
+
+	if escaped.ResponseSchemas["200"]["type"] != "object" {
+		t.Errorf("expected escaped JSON pointer to resolve, got %v", escaped.ResponseSchemas)
+	}
+}
+
+func TestParseSpecification_MissingRefFile(t *testing.T) {
+	root := writeSpecFile(t, t.TempDir(), "openapi.yaml", `
+openapi: "3.0.0"
+paths:
+  /users:
+    $ref: "./missing.yaml"
+`)
+	if _, err := ParseSpecification(root); err == nil {
+		t.Error("expected error for a missing referenced file")
+	}
+}
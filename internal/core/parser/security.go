@@ -0,0 +1,267 @@
+package parser
+
+import (
+	"slices"
+	"strings"
+)
+
+// SecurityScheme is an entry of components.securitySchemes (OpenAPI 3.x) or
+// securityDefinitions (Swagger 2.0).
+type SecurityScheme struct {
+	Name             string      `json:"name"`                         // key in securitySchemes
+	Type             string      `json:"type"`                         // apiKey, http, oauth2, openIdConnect, mutualTLS
+	Scheme           string      `json:"scheme,omitempty"`             // http: bearer, basic, digest, ...
+	BearerFormat     string      `json:"bearer_format,omitempty"`      // http bearer: e.g. JWT
+	In               string      `json:"in,omitempty"`                 // apiKey: header, query or cookie
+	ParamName        string      `json:"param_name,omitempty"`         // apiKey: header, parameter or cookie name
+	Flows            []OAuthFlow `json:"flows,omitempty"`              // oauth2
+	OpenIDConnectURL string      `json:"openid_connect_url,omitempty"` // openIdConnect discovery document
+	Description      string      `json:"description,omitempty"`
+	Scopes           []string    `json:"scopes,omitempty"` // scopes an operation requires
+}
+
+// OAuthFlow is one OAuth2 flow of a security scheme.
+type OAuthFlow struct {
+	Type             string            `json:"type"` // clientCredentials, authorizationCode, password or implicit
+	AuthorizationURL string            `json:"authorization_url,omitempty"`
+	TokenURL         string            `json:"token_url,omitempty"`
+	RefreshURL       string            `json:"refresh_url,omitempty"`
+	Scopes           map[string]string `json:"scopes,omitempty"` // scope -> description
+}
+
+// SecurityRequirement is one alternative of a security list; all of its
+// schemes must be satisfied together.
+type SecurityRequirement struct {
+	Schemes []SecurityScheme `json:"schemes"`
+}
+
+// AuthType returns the octrafic auth type that satisfies the scheme:
+// "bearer", "basic", "apikey" or "oauth2", or "" if there is none.
+func (s SecurityScheme) AuthType() string {
+	switch strings.ToLower(s.Type) {
+	case "apikey":
+		return "apikey"
+	case "http":
+		switch strings.ToLower(s.Scheme) {
+		case "bearer":
+			return "bearer"
+		case "basic":
+			return "basic"
+		}
+	case "oauth2":
+		return "oauth2"
+	case "openidconnect":
+		return "bearer"
+	}
+	return ""
+}
+
+// Summary describes the scheme in a few words, e.g. "API key in header X-API-Key".
+func (s SecurityScheme) Summary() string {
+	switch strings.ToLower(s.Type) {
+	case "apikey":
+		return "API key in " + s.In + " " + s.ParamName
+	case "http":
+		if s.BearerFormat != "" {
+			return "HTTP " + s.Scheme + " (" + s.BearerFormat + ")"
+		}
+		return "HTTP " + s.Scheme
+	case "oauth2":
+		types := make([]string, 0, len(s.Flows))
+		for _, flow := range s.Flows {
+			types = append(types, flow.Type)
+		}
+		return "OAuth2 " + strings.Join(types, ", ")
+	case "openidconnect":
+		return "OpenID Connect"
+	}
+	return s.Type
+}
+
+// Flow returns the scheme's OAuth2 flow octrafic can run, preferring
+// client credentials over the authorization code flow.
+func (s SecurityScheme) Flow() (OAuthFlow, bool) {
+	for _, flowType := range []string{"clientCredentials", "authorizationCode"} {
+		for _, flow := range s.Flows {
+			if flow.Type == flowType {
+				return flow, true
+			}
+		}
+	}
+	if len(s.Flows) > 0 {
+		return s.Flows[0], true
+	}
+	return OAuthFlow{}, false
+}
+
+// SecuritySchemes returns the schemes used by any of the endpoints, sorted
+// by name.
+func SecuritySchemes(endpoints []Endpoint) []SecurityScheme {
+	seen := make(map[string]bool)
+	var schemes []SecurityScheme
+	for _, ep := range endpoints {
+		for _, req := range ep.Security {
+			for _, scheme := range req.Schemes {
+				if seen[scheme.Name] {
+					continue
+				}
+				seen[scheme.Name] = true
+				scheme.Scopes = nil
+				schemes = append(schemes, scheme)
+			}
+		}
+	}
+	slices.SortFunc(schemes, func(a, b SecurityScheme) int { return strings.Compare(a.Name, b.Name) })
+	return schemes
+}
+
+// parseSecuritySchemes reads components.securitySchemes and the Swagger 2.0
+// securityDefinitions.
+func parseSecuritySchemes(openapi map[string]any, refs *refResolver) map[string]SecurityScheme {
+	raw := make(map[string]any)
+	if components, ok := openapi["components"].(map[string]any); ok {
+		if schemes, ok := components["securitySchemes"].(map[string]any); ok {
+			for k, v := range schemes {
+				raw[k] = v
+			}
+		}
+	}
+	if defs, ok := openapi["securityDefinitions"].(map[string]any); ok {
+		for k, v := range defs {
+			raw[k] = v
+		}
+	}
+
+	schemes := make(map[string]SecurityScheme, len(raw))
+	for name, v := range raw {
+		if m, ok := refs.deref(v); ok {
+			schemes[name] = parseSecurityScheme(name, m)
+		}
+	}
+	return schemes
+}
+
+// parseSecurityScheme reads a security scheme object. The Swagger 2.0 basic
+// type and the AsyncAPI userPassword and httpApiKey types are mapped to
+// their OpenAPI 3 equivalents.
+func parseSecurityScheme(name string, m map[string]any) SecurityScheme {
+	scheme := SecurityScheme{Name: name}
+	scheme.Type, _ = m["type"].(string)
+	scheme.Scheme, _ = m["scheme"].(string)
+	scheme.BearerFormat, _ = m["bearerFormat"].(string)
+	scheme.In, _ = m["in"].(string)
+	scheme.ParamName, _ = m["name"].(string)
+	scheme.OpenIDConnectURL, _ = m["openIdConnectUrl"].(string)
+	scheme.Description, _ = m["description"].(string)
+
+	switch scheme.Type {
+	case "basic", "userPassword":
+		scheme.Type, scheme.Scheme = "http", "basic"
+	case "httpApiKey":
+		scheme.Type = "apiKey"
+	case "oauth2":
+		scheme.Flows = parseOAuthFlows(m)
+	}
+	return scheme
+}
+
+// swaggerFlows maps Swagger 2.0 flow names to their OpenAPI 3 equivalents.
+var swaggerFlows = map[string]string{
+	"application": "clientCredentials",
+	"accessCode":  "authorizationCode",
+	"password":    "password",
+	"implicit":    "implicit",
+}
+
+func parseOAuthFlows(m map[string]any) []OAuthFlow {
+	var flows []OAuthFlow
+	if flowMap, ok := m["flows"].(map[string]any); ok {
+		for flowType, v := range flowMap {
+			if fm, ok := v.(map[string]any); ok {
+				flows = append(flows, oauthFlow(flowType, fm))
+			}
+		}
+	} else if flowType, ok := m["flow"].(string); ok {
+		flows = append(flows, oauthFlow(swaggerFlows[flowType], m))
+	}
+	slices.SortFunc(flows, func(a, b OAuthFlow) int { return strings.Compare(a.Type, b.Type) })
+	return flows
+}
+
+func oauthFlow(flowType string, m map[string]any) OAuthFlow {
+	flow := OAuthFlow{Type: flowType}
+	flow.AuthorizationURL, _ = m["authorizationUrl"].(string)
+	flow.TokenURL, _ = m["tokenUrl"].(string)
+	flow.RefreshURL, _ = m["refreshUrl"].(string)
+	scopes, _ := m["scopes"].(map[string]any)
+	if available, ok := m["availableScopes"].(map[string]any); ok {
+		// AsyncAPI 3.x
+		scopes = available
+	}
+	if len(scopes) > 0 {
+		flow.Scopes = make(map[string]string, len(scopes))
+		for scope, desc := range scopes {
+			flow.Scopes[scope], _ = desc.(string)
+		}
+	}
+	return flow
+}
+
+// parseSecurity reads a security list into requirements with their schemes
+// resolved. Requirements that name an undefined scheme are skipped, and an
+// empty requirement ({}) makes authentication optional.
+func parseSecurity(v any, schemes map[string]SecurityScheme) []SecurityRequirement {
+	items, _ := v.([]any)
+	var reqs []SecurityRequirement
+	for _, item := range items {
+		m, ok := item.(map[string]any)
+		if !ok || len(m) == 0 {
+			continue
+		}
+		req := SecurityRequirement{}
+		names := make([]string, 0, len(m))
+		for name := range m {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			scheme, ok := schemes[name]
+			if !ok {
+				req.Schemes = nil
+				break
+			}
+			if scopes, ok := m[name].([]any); ok {
+				for _, s := range scopes {
+					if scope, ok := s.(string); ok {
+						scheme.Scopes = append(scheme.Scopes, scope)
+					}
+				}
+			}
+			req.Schemes = append(req.Schemes, scheme)
+		}
+		if len(req.Schemes) > 0 {
+			reqs = append(reqs, req)
+		}
+	}
+	return reqs
+}
+
+// securityAuthType returns the auth type of the first requirement that
+// octrafic can satisfy.
+func securityAuthType(reqs []SecurityRequirement) string {
+	for _, req := range reqs {
+		if len(req.Schemes) == 1 {
+			if authType := req.Schemes[0].AuthType(); authType != "" {
+				return authType
+			}
+		}
+	}
+	for _, req := range reqs {
+		for _, scheme := range req.Schemes {
+			if authType := scheme.AuthType(); authType != "" {
+				return authType
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,115 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestParseOpenAPI_SecuritySchemes(t *testing.T) {
+	content := `{
+		"openapi": "3.0.0",
+		"security": [{"api_key": []}],
+		"components": {
+			"securitySchemes": {
+				"api_key": {"type": "apiKey", "in": "header", "name": "X-API-Key"},
+				"jwt": {"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+				"oauth": {
+					"type": "oauth2",
+					"flows": {
+						"clientCredentials": {"tokenUrl": "https://auth.example.com/token", "scopes": {"read": "Read", "write": "Write"}}
+					}
+				}
+			}
+		},
+		"paths": {
+			"/users": {
+				"get": {"summary": "List users"},
+				"post": {"security": [{"oauth": ["write"]}, {"jwt": []}]}
+			},
+			"/health": {
+				"get": {"security": []}
+			}
+		}
+	}`
+
+	spec, err := parseOpenAPI([]byte(content))
+	if err != nil {
+		t.Fatalf("parseOpenAPI failed: %v", err)
+	}
+	if len(spec.SecuritySchemes) != 3 {
+		t.Fatalf("expected 3 security schemes, got %d", len(spec.SecuritySchemes))
+	}
+	if oauth := spec.SecuritySchemes["oauth"]; len(oauth.Flows) != 1 || oauth.Flows[0].TokenURL != "https://auth.example.com/token" {
+		t.Errorf("unexpected oauth scheme %+v", oauth)
+	}
+
+	endpoints := map[string]Endpoint{}
+	for _, ep := range spec.Endpoints {
+		endpoints[ep.Method+" "+ep.Path] = ep
+	}
+
+	list := endpoints["GET /users"]
+	if !list.RequiresAuth || list.AuthType != "apikey" || len(list.Security) != 1 {
+		t.Fatalf("expected the global api_key requirement, got %+v", list)
+	}
+	if s := list.Security[0].Schemes[0]; s.In != "header" || s.ParamName != "X-API-Key" {
+		t.Errorf("unexpected api key scheme %+v", s)
+	}
+
+	create := endpoints["POST /users"]
+	if create.AuthType != "oauth2" || len(create.Security) != 2 {
+		t.Fatalf("expected two alternatives with oauth2 first, got %+v", create)
+	}
+	if scopes := create.Security[0].Schemes[0].Scopes; len(scopes) != 1 || scopes[0] != "write" {
+		t.Errorf("expected the write scope, got %v", scopes)
+	}
+
+	if health := endpoints["GET /health"]; health.RequiresAuth || len(health.Security) != 0 {
+		t.Errorf("expected /health to be public, got %+v", health)
+	}
+
+	schemes := SecuritySchemes(spec.Endpoints)
+	if len(schemes) != 3 || schemes[0].Name != "api_key" || schemes[2].Scopes != nil {
+		t.Errorf("unexpected schemes %+v", schemes)
+	}
+}
+
+func TestParseOpenAPI_SwaggerSecurityDefinitions(t *testing.T) {
+	content := `
+swagger: "2.0"
+securityDefinitions:
+  basicAuth:
+    type: basic
+  petstore_auth:
+    type: oauth2
+    flow: accessCode
+    authorizationUrl: https://petstore.example.com/oauth/authorize
+    tokenUrl: https://petstore.example.com/oauth/token
+    scopes:
+      read:pets: read your pets
+paths:
+  /pets:
+    get:
+      security:
+        - basicAuth: []
+    post:
+      security:
+        - petstore_auth: [read:pets]
+`
+	spec, err := parseOpenAPI([]byte(content))
+	if err != nil {
+		t.Fatalf("parseOpenAPI failed: %v", err)
+	}
+	for _, ep := range spec.Endpoints {
+		switch ep.Method {
+		case "GET":
+			if ep.AuthType != "basic" || ep.Security[0].Schemes[0].Scheme != "basic" {
+				t.Errorf("expected basic auth, got %+v", ep)
+			}
+		case "POST":
+			flow, ok := ep.Security[0].Schemes[0].Flow()
+			if ep.AuthType != "oauth2" || !ok || flow.Type != "authorizationCode" || flow.AuthorizationURL == "" {
+				t.Errorf("expected the authorization code flow, got %+v", ep)
+			}
+		}
+	}
+}
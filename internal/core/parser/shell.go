@@ -1,11 +1,11 @@
 package parser
 
 import (
-	"bufio"
-	"bytes"
 	"fmt"
-	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -16,113 +16,581 @@ func ParseShellScript(path string) (*Specification, error) {
 		return nil, fmt.Errorf("failed to read shell script: %w", err)
 	}
 
-	return parseShellContent(content)
+	return parseShellContent(content, filepath.Dir(path), os.LookupEnv)
 }
 
-func parseShellContent(content []byte) (*Specification, error) {
+// parseShellContent extracts the curl commands of a script, including those
+// in command substitutions such as the polling loops of CurlExporter.
+// Variables are assigned as the script runs; unset ones come from lookup
+// and are otherwise left as written. Files referenced with @file are read
+// relative to dir.
+func parseShellContent(content []byte, dir string, lookup func(string) (string, bool)) (*Specification, error) {
 	spec := &Specification{
 		Format:     "sh",
 		RawContent: string(content),
+		Endpoints:  []Endpoint{},
 	}
 
-	scanner := bufio.NewScanner(bytes.NewReader(content))
-	var currentCommand string
-	inCommand := false
+	var comments []string
+	vars := make(map[string]string)
+	var run func(src string)
+	run = func(src string) {
+		sc := &shellScanner{src: src, vars: vars, lookup: lookup, comments: &comments}
+		for {
+			cmd, ok := sc.next()
+			if !ok {
+				return
+			}
+			// Substitutions run before the command they are part of.
+			for _, sub := range cmd.subshells {
+				run(sub)
+			}
+			if assignments(cmd.words, vars) {
+				continue
+			}
+			if args, ok := curlArgs(cmd.words); ok {
+				curl := parseCurlArgs(args, cmd.stdin, dir)
+				if ep, ok := curl.endpoint(comments); ok {
+					spec.Endpoints = append(spec.Endpoints, ep)
+				}
+				spec.Files = append(spec.Files, curl.files...)
+				comments = nil
+			}
+		}
+	}
+	run(string(content))
+	return spec, nil
+}
 
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
+// assignments applies a command made of NAME=value words, optionally after
+// export, readonly, local or declare, and reports whether it was one.
+func assignments(words []shellWord, vars map[string]string) bool {
+	if len(words) > 0 {
+		switch words[0].value {
+		case "export", "readonly", "local", "declare", "typeset":
+			words = words[1:]
+			for len(words) > 0 && strings.HasPrefix(words[0].value, "-") {
+				words = words[1:]
+			}
+			if len(words) == 0 {
+				return true
+			}
+		}
+	}
+	if len(words) == 0 {
+		return false
+	}
+	for _, w := range words {
+		if !shellAssignment.MatchString(w.value) {
+			return false
+		}
+	}
+	for _, w := range words {
+		name, value, _ := strings.Cut(w.value, "=")
+		vars[name] = value
+	}
+	return true
+}
 
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
+// curlArgs returns the arguments of a curl command. Assignments and
+// keywords such as do or then may precede it.
+func curlArgs(words []shellWord) ([]shellWord, bool) {
+	for i, w := range words {
+		switch {
+		case w.value == "curl" || strings.HasSuffix(w.value, "/curl"):
+			return words[i+1:], true
+		case shellAssignment.MatchString(w.value):
+		case w.value == "do", w.value == "then", w.value == "else", w.value == "time",
+			w.value == "exec", w.value == "command", w.value == "!", w.value == "{":
+		default:
+			return nil, false
 		}
+	}
+	return nil, false
+}
+
+var (
+	shellAssignment = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*=`)
+	shellName       = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*`)
+)
+
+// shellWord is a word of a command after quote removal and parameter
+// expansion.
+type shellWord struct {
+	value      string
+	lead       int  // length of the expansion the word starts with, e.g. ${BASE_URL}
+	unresolved bool // an unset variable or a command substitution was left as written
+}
+
+// shellCommand is a simple command of a script.
+type shellCommand struct {
+	words     []shellWord
+	stdin     string   // here-document or here-string
+	subshells []string // $(...) and `...` command substitutions
+}
+
+// shellScanner splits a POSIX shell script into simple commands. It
+// implements quoting (including bash's $'...'), parameter expansion with
+// defaults, line continuations, comments, redirections and here-documents;
+// control flow is not interpreted, so the commands of every branch and
+// loop body are returned once, in order.
+type shellScanner struct {
+	src      string
+	pos      int
+	vars     map[string]string
+	lookup   func(string) (string, bool)
+	comments *[]string // comment lines since the last blank line
+	lineUsed bool      // the current line is not blank
+}
+
+// wordBuilder accumulates the parts of a word.
+type wordBuilder struct {
+	b        strings.Builder
+	word     shellWord
+	quoted   bool
+	leadDone bool
+}
+
+func (w *wordBuilder) literal(s string) {
+	if s == "" {
+		return
+	}
+	w.b.WriteString(s)
+	w.leadDone = true
+}
+
+func (w *wordBuilder) expansion(s string, unresolved bool) {
+	w.b.WriteString(s)
+	if !w.leadDone {
+		w.word.lead = w.b.Len()
+		w.leadDone = true
+	}
+	if unresolved {
+		w.word.unresolved = true
+	}
+}
+
+func (s *shellScanner) peek(offset int) byte {
+	if s.pos+offset < len(s.src) {
+		return s.src[s.pos+offset]
+	}
+	return 0
+}
+
+// next returns the next simple command.
+func (s *shellScanner) next() (shellCommand, bool) {
+	var cmd shellCommand
+	for s.pos < len(s.src) {
+		c := s.src[s.pos]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r':
+			s.pos++
+		case c == '\\' && s.peek(1) == '\n':
+			s.pos += 2
+		case c == '\n':
+			s.pos++
+			if !s.lineUsed && s.comments != nil {
+				*s.comments = nil
+			}
+			s.lineUsed = false
+			if len(cmd.words) > 0 {
+				return cmd, true
+			}
+		case c == '#':
+			end := strings.IndexByte(s.src[s.pos:], '\n')
+			if end < 0 {
+				end = len(s.src) - s.pos
+			}
+			if len(cmd.words) == 0 && s.comments != nil {
+				*s.comments = append(*s.comments, strings.TrimSpace(s.src[s.pos+1:s.pos+end]))
+			}
+			s.pos += end
+			s.lineUsed = true
+		case c == ';' || c == '&' || c == '|' || c == '(' || c == ')':
+			s.pos++
+			s.lineUsed = true
+			if c == '&' && s.peek(0) == '>' {
+				s.redirect(&cmd)
+				continue
+			}
+			if (c == '&' || c == '|' || c == ';') && s.peek(0) == c {
+				s.pos++
+			}
+			if len(cmd.words) > 0 {
+				return cmd, true
+			}
+		case c == '<' || c == '>':
+			s.lineUsed = true
+			s.redirect(&cmd)
+		default:
+			s.lineUsed = true
+			word, fd := s.word(&cmd)
+			if !fd {
+				cmd.words = append(cmd.words, word)
+			}
+		}
+	}
+	return cmd, len(cmd.words) > 0
+}
 
-		if strings.HasPrefix(line, "curl ") {
-			inCommand = true
-			currentCommand = line
-		} else if inCommand {
-			currentCommand += " " + line
+// redirect consumes a redirection. Here-documents and here-strings become
+// the command's input.
+func (s *shellScanner) redirect(cmd *shellCommand) {
+	op := ""
+	for s.pos < len(s.src) && strings.IndexByte("<>&|-", s.src[s.pos]) >= 0 && len(op) < 3 {
+		op += string(s.src[s.pos])
+		s.pos++
+		if op == ">&" || op == "<&" || op == "&>" {
+			break
 		}
+	}
+	for s.pos < len(s.src) && (s.src[s.pos] == ' ' || s.src[s.pos] == '\t') {
+		s.pos++
+	}
+	start := s.pos
+	target, _ := s.word(cmd)
+	switch op {
+	case "<<", "<<-":
+		raw := s.src[start:s.pos]
+		cmd.stdin = s.heredoc(target.value, op == "<<-", !strings.ContainsAny(raw, `'"\`))
+	case "<<<":
+		cmd.stdin = target.value + "\n"
+	}
+}
 
-		if inCommand && !strings.HasSuffix(line, "\\") {
-			inCommand = false
-			currentCommand = strings.ReplaceAll(currentCommand, "\\", "")
+// heredoc removes the body of a here-document, which starts on the line
+// after the redirection, from the script and returns it.
+func (s *shellScanner) heredoc(delimiter string, stripTabs, expand bool) string {
+	lineEnd := strings.IndexByte(s.src[s.pos:], '\n')
+	if lineEnd < 0 {
+		return ""
+	}
+	start := s.pos + lineEnd + 1
+	i := start
+	var body strings.Builder
+	for i < len(s.src) {
+		end := strings.IndexByte(s.src[i:], '\n')
+		if end < 0 {
+			end = len(s.src) - i
+		}
+		line := s.src[i : i+end]
+		i = min(i+end+1, len(s.src))
+		if stripTabs {
+			line = strings.TrimLeft(line, "\t")
+		}
+		if line == delimiter {
+			break
+		}
+		body.WriteString(line + "\n")
+	}
+	s.src = s.src[:start] + s.src[i:]
+	if !expand {
+		return body.String()
+	}
+	return s.expandText(body.String())
+}
 
-			endpoint := parseCurlCommand(currentCommand)
-			if endpoint != nil {
-				spec.Endpoints = append(spec.Endpoints, *endpoint)
+// word reads a word. fd reports a file descriptor number before a
+// redirection, as in 2>&1, which is not a word.
+func (s *shellScanner) word(cmd *shellCommand) (shellWord, bool) {
+	var w wordBuilder
+	for s.pos < len(s.src) {
+		c := s.src[s.pos]
+		switch c {
+		case ' ', '\t', '\r', '\n', ';', '&', '|', '(', ')':
+			return w.done(), false
+		case '<', '>':
+			fd := !w.quoted && w.b.Len() > 0 && strings.Trim(w.b.String(), "0123456789") == ""
+			return w.done(), fd
+		case '\\':
+			s.pos++
+			if s.peek(0) == '\n' {
+				s.pos++
+			} else if s.pos < len(s.src) {
+				w.literal(s.src[s.pos : s.pos+1])
+				s.pos++
 			}
-			currentCommand = ""
+		case '\'':
+			w.quoted = true
+			end := strings.IndexByte(s.src[s.pos+1:], '\'')
+			if end < 0 {
+				end = len(s.src) - s.pos - 1
+			}
+			w.literal(s.src[s.pos+1 : s.pos+1+end])
+			s.pos = min(s.pos+end+2, len(s.src))
+		case '"':
+			w.quoted = true
+			s.pos++
+			s.doubleQuoted(&w, cmd, true)
+		case '`':
+			s.backquote(&w, cmd)
+		case '$':
+			switch s.peek(1) {
+			case '\'':
+				w.quoted = true
+				s.pos += 2
+				w.literal(s.ansiC())
+			case '"':
+				w.quoted = true
+				s.pos += 2
+				s.doubleQuoted(&w, cmd, true)
+			default:
+				s.dollar(&w, cmd)
+			}
+		default:
+			w.literal(s.src[s.pos : s.pos+1])
+			s.pos++
 		}
 	}
+	return w.done(), false
+}
+
+func (w *wordBuilder) done() shellWord {
+	w.word.value = w.b.String()
+	return w.word
+}
 
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading shell script: %w", err)
+// doubleQuoted reads the inside of double quotes up to the closing quote,
+// or to the end of the input when closing is false.
+func (s *shellScanner) doubleQuoted(w *wordBuilder, cmd *shellCommand, closing bool) {
+	for s.pos < len(s.src) {
+		c := s.src[s.pos]
+		switch {
+		case c == '"' && closing:
+			s.pos++
+			w.literal("")
+			return
+		case c == '\\':
+			next := s.peek(1)
+			switch {
+			case next == '\n':
+				s.pos += 2
+			case next == '$' || next == '`' || next == '"' || next == '\\':
+				w.literal(string(next))
+				s.pos += 2
+			default:
+				w.literal(`\`)
+				s.pos++
+			}
+		case c == '$':
+			s.dollar(w, cmd)
+		case c == '`':
+			s.backquote(w, cmd)
+		default:
+			w.literal(s.src[s.pos : s.pos+1])
+			s.pos++
+		}
 	}
+}
 
-	return spec, nil
+// expandText expands the variables of text as if it were double-quoted.
+func (s *shellScanner) expandText(text string) string {
+	sub := &shellScanner{src: text, vars: s.vars, lookup: s.lookup}
+	var w wordBuilder
+	sub.doubleQuoted(&w, &shellCommand{}, false)
+	return w.b.String()
 }
 
-func parseCurlCommand(cmd string) *Endpoint {
-	parts := strings.Fields(cmd)
-	if len(parts) < 2 {
-		return nil
+// dollar reads a parameter expansion or command substitution at $.
+func (s *shellScanner) dollar(w *wordBuilder, cmd *shellCommand) {
+	start := s.pos
+	switch next := s.peek(1); {
+	case next == '(':
+		end := s.matching(s.pos+1, '(', ')')
+		raw := s.src[start:end]
+		if !strings.HasPrefix(raw, "$((") && cmd != nil {
+			cmd.subshells = append(cmd.subshells, strings.TrimSuffix(raw[2:], ")"))
+		}
+		s.pos = end
+		w.expansion(raw, true)
+	case next == '{':
+		end := s.matching(s.pos+1, '{', '}')
+		s.pos = end
+		inner := strings.TrimSuffix(s.src[start+2:end], "}")
+		if value, ok := s.braced(inner); ok {
+			w.expansion(value, false)
+		} else {
+			w.expansion(s.src[start:end], true)
+		}
+	case shellName.MatchString(s.src[s.pos+1:]):
+		name := shellName.FindString(s.src[s.pos+1:])
+		s.pos += 1 + len(name)
+		if value, ok := s.variable(name); ok {
+			w.expansion(value, false)
+		} else {
+			w.expansion(s.src[start:s.pos], true)
+		}
+	case next != 0 && strings.IndexByte("0123456789@*#?$!-", next) >= 0:
+		s.pos += 2
+		w.expansion(s.src[start:s.pos], true)
+	default:
+		s.pos++
+		w.literal("$")
 	}
+}
 
-	endpoint := &Endpoint{
-		Method: "GET", // Default
+// braced expands ${name} and the ${name:-default}, ${name-default},
+// ${name:=default} and ${name:+alternative} forms.
+func (s *shellScanner) braced(inner string) (string, bool) {
+	name := shellName.FindString(inner)
+	if name == "" {
+		return "", false
 	}
+	value, set := s.variable(name)
+	op := inner[len(name):]
+	if op == "" {
+		return value, set
+	}
+	colon := strings.HasPrefix(op, ":")
+	op = strings.TrimPrefix(op, ":")
+	if op == "" {
+		return "", false
+	}
+	word := s.expandText(op[1:])
+	empty := !set || (colon && value == "")
+	switch op[0] {
+	case '-':
+		if empty {
+			return word, true
+		}
+		return value, true
+	case '=':
+		if empty {
+			s.vars[name] = word
+			return word, true
+		}
+		return value, true
+	case '+':
+		if empty {
+			return "", true
+		}
+		return word, true
+	}
+	return "", false
+}
 
-	var rawURL string
-	var bodyBuilder strings.Builder
+func (s *shellScanner) variable(name string) (string, bool) {
+	if value, ok := s.vars[name]; ok {
+		return value, true
+	}
+	if s.lookup != nil {
+		return s.lookup(name)
+	}
+	return "", false
+}
 
-	for i := 1; i < len(parts); i++ {
-		part := parts[i]
+// backquote reads a `...` command substitution.
+func (s *shellScanner) backquote(w *wordBuilder, cmd *shellCommand) {
+	start := s.pos
+	s.pos++
+	var inner strings.Builder
+	for s.pos < len(s.src) && s.src[s.pos] != '`' {
+		if s.src[s.pos] == '\\' && s.pos+1 < len(s.src) && strings.IndexByte("$`\\", s.src[s.pos+1]) >= 0 {
+			s.pos++
+		}
+		inner.WriteByte(s.src[s.pos])
+		s.pos++
+	}
+	s.pos = min(s.pos+1, len(s.src))
+	if cmd != nil {
+		cmd.subshells = append(cmd.subshells, inner.String())
+	}
+	w.expansion(s.src[start:s.pos], true)
+}
 
-		switch part {
-		case "-X", "--request":
-			if i+1 < len(parts) {
-				endpoint.Method = strings.ToUpper(strings.Trim(parts[i+1], "'\""))
-				i++
+// matching returns the position after the bracket closing the one at
+// open, skipping quoted text.
+func (s *shellScanner) matching(open int, left, right byte) int {
+	depth := 0
+	for i := open; i < len(s.src); i++ {
+		switch s.src[i] {
+		case '\\':
+			i++
+		case '\'':
+			if end := strings.IndexByte(s.src[i+1:], '\''); end >= 0 {
+				i += end + 1
 			}
-		case "-d", "--data", "--data-raw", "--data-binary":
-			if i+1 < len(parts) {
-				bodyContent := strings.Trim(parts[i+1], "'\"")
-				if bodyBuilder.Len() > 0 {
-					bodyBuilder.WriteString("&")
+		case '"':
+			for i++; i < len(s.src) && s.src[i] != '"'; i++ {
+				if s.src[i] == '\\' {
+					i++
 				}
-				bodyBuilder.WriteString(bodyContent)
-				if endpoint.Method == "GET" {
-					endpoint.Method = "POST"
-				}
-				i++
 			}
-		default:
-			if !strings.HasPrefix(part, "-") && rawURL == "" {
-				rawURL = strings.Trim(part, "'\"")
+		case left:
+			depth++
+		case right:
+			depth--
+			if depth == 0 {
+				return i + 1
 			}
 		}
 	}
+	return len(s.src)
+}
 
-	if rawURL != "" {
-		if parsedURL, err := url.Parse(rawURL); err == nil {
-			endpoint.Path = parsedURL.Path
-			if parsedURL.RawQuery != "" {
-				endpoint.Path += "?" + parsedURL.RawQuery
+// ansiC reads the inside of bash's $'...' quotes, which browsers use when
+// copying requests as cURL.
+func (s *shellScanner) ansiC() string {
+	var b strings.Builder
+	for s.pos < len(s.src) {
+		c := s.src[s.pos]
+		s.pos++
+		if c == '\'' {
+			break
+		}
+		if c != '\\' || s.pos >= len(s.src) {
+			b.WriteByte(c)
+			continue
+		}
+		e := s.src[s.pos]
+		s.pos++
+		switch e {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case 'a':
+			b.WriteByte('\a')
+		case 'b':
+			b.WriteByte('\b')
+		case 'e', 'E':
+			b.WriteByte(0x1b)
+		case 'f':
+			b.WriteByte('\f')
+		case 'v':
+			b.WriteByte('\v')
+		case 'x', 'u', 'U', '0', '1', '2', '3', '4', '5', '6', '7':
+			base, size := 16, map[byte]int{'x': 2, 'u': 4, 'U': 8}[e]
+			start := s.pos
+			if size == 0 {
+				base, size, start = 8, 3, s.pos-1
 			}
-			if endpoint.Path == "" {
-				endpoint.Path = "/"
+			end := start
+			for end < len(s.src) && end-start < size && isDigitOf(s.src[end], base) {
+				end++
 			}
-		} else {
-			endpoint.Path = rawURL
+			n, err := strconv.ParseUint(s.src[start:end], base, 32)
+			if err != nil {
+				b.WriteByte('\\')
+				b.WriteByte(e)
+				continue
+			}
+			s.pos = end
+			if e == 'u' || e == 'U' {
+				b.WriteString(string(rune(n)))
+			} else {
+				b.WriteByte(byte(n))
+			}
+		default: // \\, \', \" and \?
+			b.WriteByte(e)
 		}
-		endpoint.Description = fmt.Sprintf("Curl command to %s", endpoint.Path)
-	}
-
-	endpoint.RequestBody = bodyBuilder.String()
-
-	if rawURL == "" || endpoint.Path == "" {
-		return nil
 	}
+	return b.String()
+}
 
-	return endpoint
+func isDigitOf(c byte, base int) bool {
+	_, err := strconv.ParseUint(string(c), base, 8)
+	return err == nil
 }
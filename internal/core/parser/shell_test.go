@@ -0,0 +1,132 @@
+package parser
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func noEnv(string) (string, bool) { return "", false }
+
+func TestParseShellContent_Quoting(t *testing.T) {
+	dir := t.TempDir()
+	writeSpecFile(t, dir, "user.json", "{\"name\": \"Ada\",\n \"role\": \"admin\"}\n")
+	script := `#!/bin/bash
+API="https://api.example.com/v2"
+TOKEN=abc
+
+# Create a user
+curl -sS -X POST "$API/users?notify=true" \
+  -H 'Content-Type: application/json' \
+  -H "Authorization: Bearer $TOKEN" \
+  -d '{"name": "Ada Lovelace", "note": "it'\''s"}'
+
+curl "${API}/users/${USER_ID:-42}" -H "X-Trace: ${TRACE_ID}" # trailing comment
+curl -d @user.json https://api.example.com/import 2>&1 | jq .
+curl -G --data-urlencode 'q=a b&c' -d page=2 https://api.example.com/search
+curl -u admin:secret -F 'name=Ada' -F "avatar=@user.json;type=application/json" https://api.example.com/upload
+curl --json @- https://api.example.com/batch <<EOF
+{"items": ["$TOKEN"]}
+EOF
+curl $'https://api.example.com/echo' --data-raw $'line1\nline2 \'q\''
+`
+	spec, err := parseShellContent([]byte(script), dir, noEnv)
+	if err != nil {
+		t.Fatalf("parseShellContent failed: %v", err)
+	}
+	if len(spec.Endpoints) != 7 {
+		t.Fatalf("expected 7 endpoints, got %d: %+v", len(spec.Endpoints), spec.Endpoints)
+	}
+
+	create := spec.Endpoints[0]
+	if create.Method != "POST" || create.Path != "/users?notify=true" || create.Summary != "Create a user" {
+		t.Errorf("expected named POST /users?notify=true, got %s %s %q", create.Method, create.Path, create.Summary)
+	}
+	if create.RequestBody != `{"name": "Ada Lovelace", "note": "it's"}` {
+		t.Errorf("expected quoted body with spaces, got %q", create.RequestBody)
+	}
+	if auth := findParam(create.Parameters, "Authorization"); auth == nil || auth.Examples[0].Value != "Bearer abc" || create.AuthType != "bearer" {
+		t.Errorf("expected literal bearer token kept as header, got %+v %s", auth, create.AuthType)
+	}
+
+	get := spec.Endpoints[1]
+	if get.Method != "GET" || get.Path != "/users/42" {
+		t.Errorf("expected default applied, got %s %s", get.Method, get.Path)
+	}
+	if trace := findParam(get.Parameters, "X-Trace"); trace == nil || trace.Examples[0].Value != "${TRACE_ID}" {
+		t.Errorf("expected unset variable left as written, got %+v", trace)
+	}
+
+	imp := spec.Endpoints[2]
+	if imp.RequestBody != `{"name": "Ada", "role": "admin"}` {
+		t.Errorf("expected -d @file without newlines, got %q", imp.RequestBody)
+	}
+	if imp.RequestContent[0].ContentType != "application/x-www-form-urlencoded" {
+		t.Errorf("expected curl's default content type, got %s", imp.RequestContent[0].ContentType)
+	}
+
+	search := spec.Endpoints[3]
+	if search.Method != "GET" || search.Path != "/search?q=a%20b%26c&page=2" || search.RequestBody != "" {
+		t.Errorf("expected -G data in the query, got %s %s %q", search.Method, search.Path, search.RequestBody)
+	}
+
+	upload := spec.Endpoints[4]
+	if upload.Method != "POST" || upload.AuthType != "basic" {
+		t.Errorf("expected POST with basic auth, got %s %s", upload.Method, upload.AuthType)
+	}
+	if auth := findParam(upload.Parameters, "Authorization"); auth == nil || auth.Examples[0].Value != "Basic YWRtaW46c2VjcmV0" {
+		t.Errorf("expected basic credentials header, got %+v", auth)
+	}
+	if ct := upload.RequestContent[0].ContentType; ct != "multipart/form-data; boundary="+multipartBoundary {
+		t.Errorf("expected multipart content type with boundary, got %s", ct)
+	}
+	_, headers, body := upload.SampleRequest()
+	if headers["Content-Type"] != upload.RequestContent[0].ContentType || body == "" {
+		t.Errorf("expected an executable multipart request, got %v %q", headers, body)
+	}
+
+	batch := spec.Endpoints[5]
+	if batch.RequestBody != "{\"items\": [\"abc\"]}\n" || findParam(batch.Parameters, "Accept") == nil {
+		t.Errorf("expected --json body from the here-document, got %q %+v", batch.RequestBody, batch.Parameters)
+	}
+	if batch.RequestContent[0].ContentType != "application/json" {
+		t.Errorf("expected --json content type, got %s", batch.RequestContent[0].ContentType)
+	}
+
+	echo := spec.Endpoints[6]
+	if echo.Path != "/echo" || echo.RequestBody != "line1\nline2 'q'" {
+		t.Errorf("expected ANSI-C quoted body, got %s %q", echo.Path, echo.RequestBody)
+	}
+	if len(spec.Files) != 2 || spec.Files[0] != filepath.Join(dir, "user.json") {
+		t.Errorf("expected the read files to be tracked, got %v", spec.Files)
+	}
+}
+
+func TestParseShellContent_BrowserCopy(t *testing.T) {
+	script := `curl 'https://shop.example.com/api/cart/items' \
+  -H 'accept: application/json, text/plain, */*' \
+  -H 'content-type: application/json;charset=UTF-8' \
+  -b 'session=abc; theme=dark' \
+  -H 'x-api-key: k-123' \
+  --data-raw '{"sku":"A-1","qty":2}' \
+  --compressed`
+	spec, err := parseShellContent([]byte(script), "", noEnv)
+	if err != nil {
+		t.Fatalf("parseShellContent failed: %v", err)
+	}
+	if len(spec.Endpoints) != 1 {
+		t.Fatalf("expected 1 endpoint, got %+v", spec.Endpoints)
+	}
+	ep := spec.Endpoints[0]
+	if ep.Method != "POST" || ep.Path != "/api/cart/items" {
+		t.Errorf("expected POST /api/cart/items, got %s %s", ep.Method, ep.Path)
+	}
+	if cookie := findParam(ep.Parameters, "Cookie"); cookie == nil || cookie.Examples[0].Value != "session=abc; theme=dark" {
+		t.Errorf("expected cookie header, got %+v", cookie)
+	}
+	if ep.AuthType != "apikey" || findParam(ep.Parameters, "x-api-key") == nil {
+		t.Errorf("expected API key auth with the captured key, got %s %+v", ep.AuthType, ep.Parameters)
+	}
+	if ep.RequestSchema["type"] != "object" {
+		t.Errorf("expected JSON schema, got %v", ep.RequestSchema)
+	}
+}
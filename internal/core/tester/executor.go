@@ -3,6 +3,7 @@ package tester
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/Octrafic/octrafic-cli/internal/core/auth"
 	"io"
@@ -15,6 +16,7 @@ type TestResult struct {
 	StatusCode   int
 	ResponseBody string
 	Headers      map[string]string
+	Cookies      []*http.Cookie
 	Duration     time.Duration
 	Error        error
 }
@@ -23,8 +25,13 @@ type Executor struct {
 	baseURL      string
 	client       *http.Client
 	authProvider auth.AuthProvider
+	selectAuth   AuthSelector
 }
 
+// AuthSelector picks the provider for a request, or returns nil to use the
+// executor's default provider.
+type AuthSelector func(method, path string) auth.AuthProvider
+
 func NewExecutor(baseURL string, authProvider auth.AuthProvider) *Executor {
 	return &Executor{
 		baseURL:      baseURL,
@@ -40,6 +47,48 @@ func (e *Executor) UpdateAuthProvider(authProvider auth.AuthProvider) {
 	e.authProvider = authProvider
 }
 
+// WithAuth returns an executor for the same API that authenticates with
+// another provider, e.g. to run a test as a different identity.
+func (e *Executor) WithAuth(authProvider auth.AuthProvider) *Executor {
+	return &Executor{
+		baseURL:      e.baseURL,
+		client:       e.client,
+		authProvider: authProvider,
+	}
+}
+
+// WithoutRedirects returns an executor that reports redirects instead of
+// following them, so that a redirect to a login page is not mistaken for
+// the page it leads to.
+func (e *Executor) WithoutRedirects() *Executor {
+	client := *e.client
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return &Executor{
+		baseURL:      e.baseURL,
+		client:       &client,
+		authProvider: e.authProvider,
+		selectAuth:   e.selectAuth,
+	}
+}
+
+// SelectAuth authenticates requests with the provider a selector picks,
+// e.g. the credentials of the security scheme an endpoint requires.
+func (e *Executor) SelectAuth(selector AuthSelector) {
+	e.selectAuth = selector
+}
+
+// providerFor returns the provider that authenticates a request.
+func (e *Executor) providerFor(method, path string) auth.AuthProvider {
+	if e.selectAuth != nil {
+		if provider := e.selectAuth(method, path); provider != nil {
+			return provider
+		}
+	}
+	return e.authProvider
+}
+
 // UpdateBaseURL updates the base URL for the executor
 func (e *Executor) UpdateBaseURL(baseURL string) {
 	e.baseURL = baseURL
@@ -53,44 +102,39 @@ func (e *Executor) ExecuteTest(method, endpoint string, headers map[string]strin
 		fullURL = "http://" + fullURL
 	}
 
-	var reqBody io.Reader
+	var payload []byte
 	if body != nil {
 		switch b := body.(type) {
 		case string:
-			if b != "" {
-				reqBody = strings.NewReader(b)
-			}
+			payload = []byte(b)
 		default:
 			jsonBody, err := json.Marshal(body)
 			if err != nil {
 				return &TestResult{Error: fmt.Errorf("failed to marshal body: %w", err)}, err
 			}
-			reqBody = bytes.NewBuffer(jsonBody)
+			payload = jsonBody
 		}
 	}
 
-	req, err := http.NewRequest(method, fullURL, reqBody)
-	if err != nil {
-		return &TestResult{Error: fmt.Errorf("failed to create request: %w", err)}, err
-	}
-
-	if reqBody != nil {
-		req.Header.Set("Content-Type", "application/json")
-	}
-	for key, value := range headers {
-		req.Header.Set(key, value)
+	var provider auth.AuthProvider
+	if requiresAuth {
+		provider = e.providerFor(method, endpoint)
 	}
-
-	if requiresAuth && e.authProvider != nil {
-		if err := e.authProvider.Apply(req); err != nil {
-			return &TestResult{Error: fmt.Errorf("failed to apply auth: %w", err)}, err
+	resp, err := e.send(method, fullURL, headers, payload, provider)
+	// Renewable credentials (e.g. OAuth2) are refreshed once on 401.
+	if refresher, ok := provider.(auth.Refresher); ok && err == nil && resp.StatusCode == http.StatusUnauthorized {
+		if refreshErr := refresher.Refresh(); refreshErr == nil {
+			_ = resp.Body.Close()
+			resp, err = e.send(method, fullURL, headers, payload, provider)
 		}
 	}
-
-	resp, err := e.client.Do(req)
 	duration := time.Since(startTime)
 
 	if err != nil {
+		var reqErr *requestError
+		if errors.As(err, &reqErr) {
+			return &TestResult{Error: err}, err
+		}
 		return &TestResult{
 			Duration: duration,
 			Error:    fmt.Errorf("request failed: %w", err),
@@ -116,7 +160,42 @@ func (e *Executor) ExecuteTest(method, endpoint string, headers map[string]strin
 		StatusCode:   resp.StatusCode,
 		ResponseBody: string(respBody),
 		Headers:      responseHeaders,
+		Cookies:      resp.Cookies(),
 		Duration:     duration,
 		Error:        nil,
 	}, nil
 }
+
+// requestError is an error building a request, reported without "request failed".
+type requestError struct{ err error }
+
+func (e *requestError) Error() string { return e.err.Error() }
+func (e *requestError) Unwrap() error { return e.err }
+
+// send builds and sends one request, authenticated by provider if not nil.
+func (e *Executor) send(method, fullURL string, headers map[string]string, payload []byte, provider auth.AuthProvider) (*http.Response, error) {
+	var reqBody io.Reader
+	if len(payload) > 0 {
+		reqBody = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequest(method, fullURL, reqBody)
+	if err != nil {
+		return nil, &requestError{fmt.Errorf("failed to create request: %w", err)}
+	}
+
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	if provider != nil {
+		if err := provider.Apply(req); err != nil {
+			return nil, &requestError{fmt.Errorf("failed to apply auth: %w", err)}
+		}
+	}
+
+	return e.client.Do(req)
+}
@@ -0,0 +1,54 @@
+package tester
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Octrafic/octrafic-cli/internal/core/auth"
+)
+
+func TestExecuteTest_RefreshesOAuth2TokenOn401(t *testing.T) {
+	issued := 0
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		issued++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer","expires_in":3600}`, issued)
+	}))
+	defer tokenServer.Close()
+
+	// The API has revoked the first token.
+	var bodies []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf := make([]byte, 64)
+		n, _ := r.Body.Read(buf)
+		bodies = append(bodies, string(buf[:n]))
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer api.Close()
+
+	provider := auth.NewOAuth2Auth(auth.GrantClientCredentials, tokenServer.URL, "id", "secret")
+	executor := NewExecutor(api.URL, provider)
+
+	result, err := executor.ExecuteTest("POST", "/items", nil, map[string]any{"name": "a"}, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.StatusCode != http.StatusOK || issued != 2 {
+		t.Errorf("expected success after one refresh, got status %d with %d tokens issued", result.StatusCode, issued)
+	}
+	if len(bodies) != 2 || bodies[1] != `{"name":"a"}` {
+		t.Errorf("expected the body to be resent, got %q", bodies)
+	}
+
+	// Requests without auth are not retried.
+	result, _ = executor.ExecuteTest("GET", "/items", nil, nil, false)
+	if result.StatusCode != http.StatusUnauthorized || issued != 2 {
+		t.Errorf("expected no refresh for unauthenticated request, got status %d with %d tokens issued", result.StatusCode, issued)
+	}
+}
@@ -0,0 +1,182 @@
+package tester
+
+import (
+	"encoding/base64"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Octrafic/octrafic-cli/internal/core/auth"
+)
+
+// JWTProbe is a tampered variant of a bearer token that a correctly
+// verifying API must reject.
+type JWTProbe struct {
+	Name        string
+	Description string
+	Token       string
+}
+
+// JWTProbes builds the tampering variants of a token: a modified signature,
+// alg "none", an expired exp, a wrong aud and an elevated role claim.
+func JWTProbes(token *auth.JWT, now time.Time) []JWTProbe {
+	probes := make([]JWTProbe, 0, 5)
+
+	signature := token.Clone()
+	signature.Signature = flipSignature(token.Signature)
+	probes = append(probes, JWTProbe{
+		Name:        "modified-signature",
+		Description: "signature altered",
+		Token:       signature.Encode(),
+	})
+
+	none := token.Clone()
+	none.Header["alg"] = "none"
+	none.Signature = ""
+	probes = append(probes, JWTProbe{
+		Name:        "alg-none",
+		Description: `alg set to "none" without a signature`,
+		Token:       none.Encode(),
+	})
+
+	expired := token.Clone()
+	expired.Claims["exp"] = now.Add(-time.Hour).Unix()
+	if _, ok := expired.Claims["iat"]; ok {
+		expired.Claims["iat"] = now.Add(-2 * time.Hour).Unix()
+	}
+	probes = append(probes, JWTProbe{
+		Name:        "expired",
+		Description: "exp set to one hour ago",
+		Token:       expired.Encode(),
+	})
+
+	audience := token.Clone()
+	audience.Claims["aud"] = "https://invalid-audience.octrafic.test"
+	probes = append(probes, JWTProbe{
+		Name:        "wrong-audience",
+		Description: "aud set to an unrelated audience",
+		Token:       audience.Encode(),
+	})
+
+	elevated := token.Clone()
+	claim := elevateRole(elevated.Claims)
+	probes = append(probes, JWTProbe{
+		Name:        "elevated-role",
+		Description: "admin added to the " + claim + " claim",
+		Token:       elevated.Encode(),
+	})
+	return probes
+}
+
+// flipSignature changes the last byte of the signature.
+func flipSignature(signature string) string {
+	raw, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || len(raw) == 0 {
+		return signature + "AA"
+	}
+	raw[len(raw)-1] ^= 0x01
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// elevateRole grants admin in the token's role claim, or adds a role claim
+// if it has none, and returns the claim it changed.
+func elevateRole(claims map[string]any) string {
+	for _, name := range []string{"role", "roles", "groups", "permissions"} {
+		switch v := claims[name].(type) {
+		case string:
+			claims[name] = "admin"
+			return name
+		case []any:
+			claims[name] = append(v, "admin")
+			return name
+		}
+	}
+	for _, name := range []string{"scope", "scp"} {
+		if v, ok := claims[name].(string); ok {
+			claims[name] = strings.TrimSpace(v + " admin")
+			return name
+		}
+	}
+	if _, ok := claims["is_admin"]; ok {
+		claims["is_admin"] = true
+		return "is_admin"
+	}
+	claims["role"] = "admin"
+	return "role"
+}
+
+// JWTProbeResult is the outcome of one probe.
+type JWTProbeResult struct {
+	Probe      JWTProbe
+	StatusCode int
+	Error      error
+}
+
+// Rejected reports whether the API refused the tampered token.
+func (r JWTProbeResult) Rejected() bool {
+	return r.Error == nil && (r.StatusCode == http.StatusUnauthorized || r.StatusCode == http.StatusForbidden)
+}
+
+// Accepted reports whether the API served the request with the tampered
+// token, i.e. it does not verify what the probe changed. Redirects are not
+// accepted: APIs often send rejected callers to a login page.
+func (r JWTProbeResult) Accepted() bool {
+	return r.Error == nil && r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// JWTProbeReport holds the baseline request with the real token and the
+// result of every probe.
+type JWTProbeReport struct {
+	Method         string
+	Path           string
+	BaselineStatus int
+	Results        []JWTProbeResult
+}
+
+// BaselineOK reports whether the endpoint accepted the real token. If it did
+// not, rejections prove nothing.
+func (r *JWTProbeReport) BaselineOK() bool {
+	return r.BaselineStatus >= 200 && r.BaselineStatus < 300
+}
+
+// ProbeJWT sends the request once with the bearer token configured for the
+// endpoint and then once per tampered variant of it. Redirects are not
+// followed.
+func (e *Executor) ProbeJWT(method, path string, headers map[string]string, body any) (*JWTProbeReport, error) {
+	e = e.WithoutRedirects()
+	token, ok := auth.BearerJWT(e.providerFor(method, path))
+	if !ok {
+		return nil, fmt.Errorf("JWT probes need bearer authentication with a JWT")
+	}
+
+	report := &JWTProbeReport{Method: method, Path: path}
+	baseline, err := e.ExecuteTest(method, path, headers, body, true)
+	if err != nil {
+		return nil, fmt.Errorf("baseline request failed: %w", err)
+	}
+	report.BaselineStatus = baseline.StatusCode
+
+	for _, probe := range JWTProbes(token, time.Now()) {
+		probeHeaders := maps.Clone(headers)
+		if probeHeaders == nil {
+			probeHeaders = make(map[string]string)
+		}
+		for _, key := range slices.Collect(maps.Keys(probeHeaders)) {
+			if strings.EqualFold(key, "Authorization") {
+				delete(probeHeaders, key)
+			}
+		}
+		probeHeaders["Authorization"] = "Bearer " + probe.Token
+
+		result, err := e.ExecuteTest(method, path, probeHeaders, body, false)
+		report.Results = append(report.Results, JWTProbeResult{
+			Probe:      probe,
+			StatusCode: result.StatusCode,
+			Error:      err,
+		})
+	}
+	return report, nil
+}
@@ -0,0 +1,150 @@
+package tester
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Octrafic/octrafic-cli/internal/core/auth"
+)
+
+func TestProbeJWT(t *testing.T) {
+	enc := base64.RawURLEncoding.EncodeToString
+	header := enc([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	token := header + "." + enc([]byte(`{"sub":"u1","aud":"api","exp":`+formatUnix(time.Now().Add(time.Hour))+`,"role":"member"}`)) + "." + enc([]byte("sig"))
+
+	// The API only compares signatures, so it accepts tampered claims but
+	// rejects a changed signature and alg none.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), ".")
+		if len(parts) != 3 || parts[2] != enc([]byte("sig")) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	report, err := NewExecutor(server.URL, auth.NewBearerAuth(token)).ProbeJWT("GET", "/me", nil, nil)
+	if err != nil {
+		t.Fatalf("ProbeJWT failed: %v", err)
+	}
+	if !report.BaselineOK() {
+		t.Fatalf("Expected baseline to succeed, got %d", report.BaselineStatus)
+	}
+
+	rejected := map[string]bool{}
+	for _, result := range report.Results {
+		rejected[result.Probe.Name] = result.Rejected()
+		if result.Rejected() == result.Accepted() {
+			t.Errorf("%s: expected rejected or accepted, got %d", result.Probe.Name, result.StatusCode)
+		}
+	}
+	want := map[string]bool{
+		"modified-signature": true,
+		"alg-none":           true,
+		"expired":            false,
+		"wrong-audience":     false,
+		"elevated-role":      false,
+	}
+	for name, wantRejected := range want {
+		if got, ok := rejected[name]; !ok || got != wantRejected {
+			t.Errorf("%s: expected rejected=%v, got %v (ran: %v)", name, wantRejected, got, ok)
+		}
+	}
+}
+
+func TestProbeJWT_RedirectIsNotAccepted(t *testing.T) {
+	enc := base64.RawURLEncoding.EncodeToString
+	token := enc([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." + enc([]byte(`{"sub":"u1"}`)) + "." + enc([]byte("sig"))
+
+	// Tampered tokens are redirected to the login page.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" || r.Header.Get("Authorization") == "Bearer "+token {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		http.Redirect(w, r, "/login", http.StatusFound)
+	}))
+	defer server.Close()
+
+	report, err := NewExecutor(server.URL, auth.NewBearerAuth(token)).ProbeJWT("GET", "/me", nil, nil)
+	if err != nil {
+		t.Fatalf("ProbeJWT failed: %v", err)
+	}
+	if !report.BaselineOK() {
+		t.Fatalf("Expected baseline to succeed, got %d", report.BaselineStatus)
+	}
+	for _, result := range report.Results {
+		if result.StatusCode != http.StatusFound || result.Accepted() {
+			t.Errorf("%s: expected an unaccepted redirect, got %d", result.Probe.Name, result.StatusCode)
+		}
+	}
+}
+
+func TestProbeJWT_TransportErrorIsNeitherRejectedNorAccepted(t *testing.T) {
+	enc := base64.RawURLEncoding.EncodeToString
+	token := enc([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." + enc([]byte(`{"sub":"u1"}`)) + "." + enc([]byte("sig"))
+
+	// The connection drops for tampered tokens.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer "+token {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("Hijack failed: %v", err)
+			return
+		}
+		_ = conn.Close()
+	}))
+	defer server.Close()
+
+	report, err := NewExecutor(server.URL, auth.NewBearerAuth(token)).ProbeJWT("GET", "/me", nil, nil)
+	if err != nil {
+		t.Fatalf("ProbeJWT failed: %v", err)
+	}
+	for _, result := range report.Results {
+		if result.Error == nil || result.Rejected() || result.Accepted() {
+			t.Errorf("%s: expected a failed probe, got %+v", result.Probe.Name, result)
+		}
+	}
+}
+
+func TestJWTProbes_ElevateRole(t *testing.T) {
+	enc := base64.RawURLEncoding.EncodeToString
+	jwt, err := auth.ParseJWT(enc([]byte(`{"alg":"RS256"}`)) + "." + enc([]byte(`{"roles":["member"]}`)) + ".c2ln")
+	if err != nil {
+		t.Fatalf("ParseJWT failed: %v", err)
+	}
+
+	for _, probe := range JWTProbes(jwt, time.Now()) {
+		tampered, err := auth.ParseJWT(probe.Token)
+		if err != nil {
+			t.Fatalf("%s: invalid token: %v", probe.Name, err)
+		}
+		switch probe.Name {
+		case "elevated-role":
+			roles, _ := tampered.Claims["roles"].([]any)
+			if len(roles) != 2 || roles[1] != "admin" {
+				t.Errorf("Expected admin added to roles, got %v", tampered.Claims["roles"])
+			}
+		case "alg-none":
+			if tampered.Header["alg"] != "none" || tampered.Signature != "" {
+				t.Errorf("Expected alg none without signature, got %v %q", tampered.Header, tampered.Signature)
+			}
+		}
+	}
+	if _, ok := jwt.Claims["exp"]; ok {
+		t.Error("Expected probes not to modify the original token")
+	}
+}
+
+func formatUnix(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10)
+}
@@ -0,0 +1,408 @@
+package tester
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Octrafic/octrafic-cli/internal/core/auth"
+	"github.com/Octrafic/octrafic-cli/internal/infra/secrets"
+)
+
+// loginExpirySkew logs in again this long before the token expires.
+const loginExpirySkew = 30 * time.Second
+
+var envRefPattern = regexp.MustCompile(`\{\{\$env\.([A-Za-z_][A-Za-z0-9_]*)\}\}`)
+
+// LoginFlow describes an API's own login request and how to use the token
+// it returns. Path, Headers and Body may reference environment variables
+// as {{$env.NAME}}, and Headers and Body may be an env:, file: or cmd:
+// reference as a whole, so credentials do not have to be saved.
+type LoginFlow struct {
+	Method  string            `json:"method,omitempty"` // default POST
+	Path    string            `json:"path"`             // relative to the API base URL, or absolute
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+
+	// TokenFrom and ExpiresFrom are body:<path>, header:<name> or
+	// cookie:<name>; a bare value is a body path. The expiry may be seconds,
+	// a Unix timestamp or an RFC 3339 time.
+	TokenFrom   string `json:"token_from"`
+	ExpiresFrom string `json:"expires_from,omitempty"`
+
+	InjectAs     string `json:"inject_as,omitempty"`     // header (default), query or cookie
+	InjectName   string `json:"inject_name,omitempty"`   // default Authorization
+	InjectFormat string `json:"inject_format,omitempty"` // default "Bearer {{token}}" for Authorization, else "{{token}}"
+}
+
+// LoginAuth authenticates by running a LoginFlow, caching the token until it
+// expires or a request gets a 401.
+type LoginAuth struct {
+	LoginFlow
+	auth.References
+
+	// BaseURL is where relative login paths are sent. If empty, the scheme
+	// and host of the request being authenticated are used.
+	BaseURL string
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+	now    func() time.Time
+}
+
+// NewLoginAuth creates a login-flow provider.
+func NewLoginAuth(baseURL string, flow LoginFlow) *LoginAuth {
+	return &LoginAuth{LoginFlow: flow, BaseURL: baseURL, now: time.Now}
+}
+
+// ParseInject splits an injection spec like "header:Authorization" or
+// "cookie:session" into location and name.
+func ParseInject(s string) (location, name string) {
+	location, name, _ = strings.Cut(s, ":")
+	return location, name
+}
+
+// Apply logs in if needed and adds the token to the request
+func (l *LoginAuth) Apply(req *http.Request) error {
+	if err := l.Validate(); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.token == "" || (!l.expiry.IsZero() && !l.clock().Add(loginExpirySkew).Before(l.expiry)) {
+		if err := l.login(req.URL); err != nil {
+			return err
+		}
+	}
+
+	name := l.injectName()
+	value := strings.ReplaceAll(l.injectFormat(), "{{token}}", l.token)
+	switch l.injectAs() {
+	case "query":
+		q := req.URL.Query()
+		q.Set(name, value)
+		req.URL.RawQuery = q.Encode()
+	case "cookie":
+		req.AddCookie(&http.Cookie{Name: name, Value: value})
+	default:
+		req.Header.Set(name, value)
+	}
+	return nil
+}
+
+// Refresh discards the cached token; the next request logs in again.
+func (l *LoginAuth) Refresh() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.token = ""
+	return nil
+}
+
+// Type returns the authentication type
+func (l *LoginAuth) Type() string {
+	return "login"
+}
+
+// Validate checks if the configuration is valid
+func (l *LoginAuth) Validate() error {
+	if strings.TrimSpace(l.Path) == "" {
+		return fmt.Errorf("login path cannot be empty")
+	}
+	if strings.TrimSpace(l.TokenFrom) == "" {
+		return fmt.Errorf("token location (token_from) cannot be empty")
+	}
+	for _, source := range []string{l.TokenFrom, l.ExpiresFrom} {
+		if kind, name := parseSource(source); source != "" && (kind == "" || name == "") {
+			return fmt.Errorf("invalid response location %q (use body:<path>, header:<name> or cookie:<name>)", source)
+		}
+	}
+	switch l.injectAs() {
+	case "header", "query", "cookie":
+	default:
+		return fmt.Errorf("inject location must be 'header', 'query' or 'cookie', got: %s", l.InjectAs)
+	}
+	if l.injectAs() != "header" && l.InjectName == "" {
+		return fmt.Errorf("inject name is required for %s injection", l.injectAs())
+	}
+	return nil
+}
+
+// Redact returns a copy with literal credentials in headers and body hidden;
+// {{$env.NAME}} references are kept.
+func (l *LoginAuth) Redact() auth.AuthProvider {
+	flow := l.LoginFlow
+	if len(l.Headers) > 0 {
+		flow.Headers = make(map[string]string, len(l.Headers))
+		for k, v := range l.Headers {
+			flow.Headers[k] = redactLiteral(v)
+		}
+	}
+	flow.Body = redactBody(l.Body)
+	return &LoginAuth{LoginFlow: flow, BaseURL: l.BaseURL}
+}
+
+// String returns a human-readable representation
+func (l *LoginAuth) String() string {
+	return fmt.Sprintf("Login via %s %s (token from %s, sent in %s %s)", l.method(), l.Path, l.TokenFrom, l.injectAs(), l.injectName())
+}
+
+func (l *LoginAuth) clock() time.Time {
+	if l.now == nil {
+		return time.Now()
+	}
+	return l.now()
+}
+
+func (l *LoginAuth) method() string {
+	if l.Method == "" {
+		return "POST"
+	}
+	return strings.ToUpper(l.Method)
+}
+
+func (l *LoginAuth) injectAs() string {
+	if l.InjectAs == "" {
+		return "header"
+	}
+	return strings.ToLower(l.InjectAs)
+}
+
+func (l *LoginAuth) injectName() string {
+	if l.InjectName == "" && l.injectAs() == "header" {
+		return "Authorization"
+	}
+	return l.InjectName
+}
+
+func (l *LoginAuth) injectFormat() string {
+	switch {
+	case l.InjectFormat != "":
+		return l.InjectFormat
+	case strings.EqualFold(l.injectName(), "Authorization"):
+		return "Bearer {{token}}"
+	default:
+		return "{{token}}"
+	}
+}
+
+// login runs the login request and stores the token. The caller holds l.mu.
+func (l *LoginAuth) login(target *url.URL) error {
+	path, err := l.expandEnvRefs(l.Path)
+	if err != nil {
+		return err
+	}
+	headers := make(map[string]string, len(l.Headers))
+	for k, v := range l.Headers {
+		if headers[k], err = l.expandRefs(v); err != nil {
+			return err
+		}
+	}
+	var body any
+	if l.Body != "" {
+		expanded, err := l.expandRefs(l.Body)
+		if err != nil {
+			return err
+		}
+		body = expanded
+	}
+
+	baseURL := l.BaseURL
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		baseURL = ""
+	} else if baseURL == "" && target != nil {
+		baseURL = target.Scheme + "://" + target.Host
+	}
+
+	result, err := NewExecutor(baseURL, &auth.NoAuth{}).ExecuteTest(l.method(), path, headers, body, false)
+	if err != nil {
+		return fmt.Errorf("login request failed: %w", err)
+	}
+	if result.StatusCode < 200 || result.StatusCode >= 300 {
+		return fmt.Errorf("login failed: %s %s returned %d", l.method(), l.Path, result.StatusCode)
+	}
+
+	value, ok := extractValue(result, l.TokenFrom)
+	token := stringValue(value)
+	if !ok || token == "" {
+		return fmt.Errorf("login response has no token at %s", l.TokenFrom)
+	}
+	l.token = token
+	l.expiry = time.Time{}
+
+	if l.ExpiresFrom != "" {
+		value, ok := extractValue(result, l.ExpiresFrom)
+		if !ok {
+			return fmt.Errorf("login response has no expiry at %s", l.ExpiresFrom)
+		}
+		expiry, err := parseExpiry(value, l.clock())
+		if err != nil {
+			return err
+		}
+		l.expiry = expiry
+	} else if kind, name := parseSource(l.TokenFrom); kind == "cookie" {
+		for _, c := range result.Cookies {
+			if c.Name == name {
+				if c.MaxAge > 0 {
+					l.expiry = l.clock().Add(time.Duration(c.MaxAge) * time.Second)
+				} else if !c.Expires.IsZero() {
+					l.expiry = c.Expires
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// parseSource splits body:<path>, header:<name> or cookie:<name>.
+func parseSource(s string) (kind, name string) {
+	kind, name, found := strings.Cut(s, ":")
+	if !found {
+		return "body", s
+	}
+	switch kind {
+	case "body", "header", "cookie":
+		return kind, name
+	}
+	return "", ""
+}
+
+func extractValue(result *TestResult, source string) (any, bool) {
+	kind, name := parseSource(source)
+	switch kind {
+	case "header":
+		v, ok := result.Headers[http.CanonicalHeaderKey(name)]
+		return v, ok
+	case "cookie":
+		for _, c := range result.Cookies {
+			if c.Name == name {
+				return c.Value, true
+			}
+		}
+		return nil, false
+	case "body":
+		var parsed any
+		if err := json.Unmarshal([]byte(result.ResponseBody), &parsed); err != nil {
+			return nil, false
+		}
+		return ResolvePath(parsed, name)
+	}
+	return nil, false
+}
+
+func stringValue(v any) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
+
+// parseExpiry reads expires-in seconds, a Unix timestamp (seconds or
+// milliseconds) or an RFC 3339 time.
+func parseExpiry(v any, now time.Time) (time.Time, error) {
+	if s, ok := v.(string); ok {
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			return t, nil
+		}
+		if t, err := http.ParseTime(s); err == nil {
+			return t, nil
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("unrecognized token expiry: %q", s)
+		}
+		v = f
+	}
+	f, ok := v.(float64)
+	if !ok {
+		return time.Time{}, fmt.Errorf("unrecognized token expiry: %v", v)
+	}
+	switch {
+	case f > 1e12:
+		return time.UnixMilli(int64(f)), nil
+	case f > 1e9:
+		return time.Unix(int64(f), 0), nil
+	default:
+		return now.Add(time.Duration(f * float64(time.Second))), nil
+	}
+}
+
+// expandRefs resolves a value that is an env:, file:, cmd: or secret store
+// reference as a whole, and {{$env.NAME}} references within other values.
+// Values are used as written unless references are allowed.
+func (l *LoginAuth) expandRefs(s string) (string, error) {
+	if secrets.IsReference(s) {
+		return l.Resolve("login value", s)
+	}
+	return l.expandEnvRefs(s)
+}
+
+func (l *LoginAuth) expandEnvRefs(s string) (string, error) {
+	if !l.ReferencesAllowed() {
+		return s, nil
+	}
+	var missing string
+	out := envRefPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		name := envRefPattern.FindStringSubmatch(ref)[1]
+		v, ok := os.LookupEnv(name)
+		if !ok && missing == "" {
+			missing = name
+		}
+		return v
+	})
+	if missing != "" {
+		return "", fmt.Errorf("environment variable %s is not set", missing)
+	}
+	return out, nil
+}
+
+func redactLiteral(s string) string {
+	if s == "" || envRefPattern.ReplaceAllString(s, "") == "" {
+		return s
+	}
+	return auth.RedactString(s)
+}
+
+// redactBody hides literal string values in a JSON body, or the whole body
+// if it is not JSON.
+func redactBody(body string) string {
+	if body == "" {
+		return ""
+	}
+	var parsed any
+	if err := json.Unmarshal([]byte(body), &parsed); err != nil {
+		return redactLiteral(body)
+	}
+	var walk func(v any) any
+	walk = func(v any) any {
+		switch val := v.(type) {
+		case map[string]any:
+			for k, item := range val {
+				val[k] = walk(item)
+			}
+		case []any:
+			for i, item := range val {
+				val[i] = walk(item)
+			}
+		case string:
+			return redactLiteral(val)
+		}
+		return v
+	}
+	data, err := json.Marshal(walk(parsed))
+	if err != nil {
+		return auth.RedactString(body)
+	}
+	return string(data)
+}
@@ -0,0 +1,163 @@
+package tester
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// loginAPI issues numbered tokens from /login and accepts only the newest
+// one on /me.
+type loginAPI struct {
+	logins int
+	creds  map[string]string
+}
+
+func (a *loginAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	current := fmt.Sprintf("tok-%d", a.logins)
+	switch r.URL.Path {
+	case "/login":
+		_ = json.NewDecoder(r.Body).Decode(&a.creds)
+		a.logins++
+		token := fmt.Sprintf("tok-%d", a.logins)
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: token, MaxAge: 600})
+		w.Header().Set("X-Auth-Token", token)
+		_, _ = fmt.Fprintf(w, `{"data":{"token":"%s","expiresIn":120}}`, token)
+	case "/me":
+		got := r.Header.Get("Authorization")
+		if c, err := r.Cookie("session"); err == nil {
+			got = c.Value
+		}
+		if q := r.URL.Query().Get("access_token"); q != "" {
+			got = q
+		}
+		if strings.TrimPrefix(got, "Bearer ") != current {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"auth":%q}`, got)
+	}
+}
+
+func TestLoginAuth_BodyTokenWithExpiry(t *testing.T) {
+	t.Setenv("LOGIN_TEST_USER", "ada")
+	t.Setenv("LOGIN_TEST_PASS", "s3cret")
+	api := &loginAPI{}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	now := time.Now()
+	provider := NewLoginAuth("", LoginFlow{
+		Path:        "/login",
+		Body:        `{"user":"{{$env.LOGIN_TEST_USER}}","pass":"{{$env.LOGIN_TEST_PASS}}"}`,
+		TokenFrom:   "data.token",
+		ExpiresFrom: "body:data.expiresIn",
+	})
+	provider.now = func() time.Time { return now }
+	provider.AllowReferences()
+	executor := NewExecutor(server.URL, provider)
+
+	result, err := executor.ExecuteTest("GET", "/me", nil, nil, true)
+	if err != nil || result.StatusCode != 200 || !strings.Contains(result.ResponseBody, "Bearer tok-1") {
+		t.Fatalf("expected authenticated request, got %v %+v", err, result)
+	}
+	if api.creds["user"] != "ada" || api.creds["pass"] != "s3cret" {
+		t.Errorf("expected credentials from the environment, got %v", api.creds)
+	}
+
+	_, _ = executor.ExecuteTest("GET", "/me", nil, nil, true)
+	if api.logins != 1 {
+		t.Errorf("expected the token to be cached, got %d logins", api.logins)
+	}
+
+	// Close to expiry the provider logs in again before the request.
+	now = now.Add(100 * time.Second)
+	result, _ = executor.ExecuteTest("GET", "/me", nil, nil, true)
+	if api.logins != 2 || !strings.Contains(result.ResponseBody, "tok-2") {
+		t.Errorf("expected re-login before expiry, got %d logins and %s", api.logins, result.ResponseBody)
+	}
+
+	// A token revoked by the server is replaced after the 401.
+	api.logins++
+	result, _ = executor.ExecuteTest("GET", "/me", nil, nil, true)
+	if result.StatusCode != 200 || api.logins != 4 {
+		t.Errorf("expected re-login on 401, got status %d after %d logins", result.StatusCode, api.logins)
+	}
+}
+
+func TestLoginAuth_ReferencesNotAllowed(t *testing.T) {
+	t.Setenv("LOGIN_TEST_USER", "ada")
+	api := &loginAPI{}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	provider := NewLoginAuth(server.URL, LoginFlow{Path: "/login", Body: `{"user":"{{$env.LOGIN_TEST_USER}}"}`, TokenFrom: "data.token"})
+	if _, err := NewExecutor(server.URL, provider).ExecuteTest("GET", "/me", nil, nil, true); err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if api.creds["user"] != "{{$env.LOGIN_TEST_USER}}" {
+		t.Errorf("expected the body sent as written, got %v", api.creds)
+	}
+}
+
+func TestLoginAuth_CookieAndQueryInjection(t *testing.T) {
+	api := &loginAPI{}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	cookie := NewLoginAuth(server.URL, LoginFlow{Path: "/login", TokenFrom: "cookie:session", InjectAs: "cookie", InjectName: "session"})
+	result, err := NewExecutor(server.URL, cookie).ExecuteTest("GET", "/me", nil, nil, true)
+	if err != nil || result.StatusCode != 200 {
+		t.Fatalf("expected cookie session to work, got %v %+v", err, result)
+	}
+	if cookie.expiry.IsZero() {
+		t.Error("expected the cookie Max-Age to set the expiry")
+	}
+
+	inject, name := ParseInject("query:access_token")
+	query := NewLoginAuth(server.URL, LoginFlow{Path: server.URL + "/login", TokenFrom: "header:x-auth-token", InjectAs: inject, InjectName: name})
+	result, err = NewExecutor(server.URL, query).ExecuteTest("GET", "/me", nil, nil, true)
+	if err != nil || result.StatusCode != 200 || !strings.Contains(result.ResponseBody, `"tok-2"`) {
+		t.Fatalf("expected token from header sent as query parameter, got %v %+v", err, result)
+	}
+}
+
+func TestLoginAuth_ValidateAndRedact(t *testing.T) {
+	invalid := []LoginFlow{
+		{TokenFrom: "token"},
+		{Path: "/login"},
+		{Path: "/login", TokenFrom: "json:token"},
+		{Path: "/login", TokenFrom: "token", InjectAs: "body"},
+		{Path: "/login", TokenFrom: "token", InjectAs: "query"},
+	}
+	for _, flow := range invalid {
+		if err := NewLoginAuth("", flow).Validate(); err == nil {
+			t.Errorf("expected %+v to be invalid", flow)
+		}
+	}
+
+	provider := NewLoginAuth("", LoginFlow{
+		Path:      "/login",
+		Headers:   map[string]string{"X-Client-Key": "literal-client-key", "X-User": "{{$env.USER_NAME}}"},
+		Body:      `{"user":"{{$env.USER_NAME}}","password":"hunter2-literal"}`,
+		TokenFrom: "token",
+	})
+	redacted := provider.Redact().(*LoginAuth)
+	if strings.Contains(redacted.Body, "hunter2-literal") || redacted.Headers["X-Client-Key"] == "literal-client-key" {
+		t.Errorf("literal credentials were not redacted: %+v", redacted.LoginFlow)
+	}
+	if !strings.Contains(redacted.Body, "{{$env.USER_NAME}}") || redacted.Headers["X-User"] != "{{$env.USER_NAME}}" {
+		t.Errorf("environment references should be kept: %+v", redacted.LoginFlow)
+	}
+
+	missing := NewLoginAuth("http://127.0.0.1:1", LoginFlow{Path: "/login", Body: "{{$env.LOGIN_TEST_UNSET_VAR}}", TokenFrom: "token"})
+	missing.AllowReferences()
+	req, _ := http.NewRequest("GET", "http://127.0.0.1:1/me", nil)
+	if err := missing.Apply(req); err == nil || !strings.Contains(err.Error(), "LOGIN_TEST_UNSET_VAR") {
+		t.Errorf("expected missing environment variable error, got %v", err)
+	}
+}
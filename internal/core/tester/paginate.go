@@ -0,0 +1,419 @@
+package tester
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Pagination styles.
+const (
+	PaginationAuto   = ""       // Link header, then a next cursor/URL in the body
+	PaginationLink   = "link"   // RFC 8288 Link: <...>; rel="next"
+	PaginationCursor = "cursor" // next cursor or URL in the body
+	PaginationPage   = "page"   // ?page=N
+	PaginationOffset = "offset" // ?offset=N
+)
+
+// DefaultMaxPages limits traversal when no limit is given.
+const DefaultMaxPages = 10
+
+// PaginateOptions configures how list pages are followed.
+type PaginateOptions struct {
+	Style       string
+	BaseURL     string // used to turn absolute next links into endpoints
+	ItemsField  string // path to the items array; "" = auto-detect
+	NextField   string // path to the next cursor or URL (cursor style)
+	CursorParam string // query parameter for cursors, default "cursor"
+	PageParam   string // default "page"
+	OffsetParam string // default "offset"
+	SizeParam   string // optional page size parameter, e.g. "limit"
+	PageSize    int
+	StartPage   int // default 1
+	MaxPages    int
+	IDField     string // default "id"
+	TotalField  string // path to the total item count; X-Total-Count is used otherwise
+}
+
+// PaginationResult collects the items of all visited pages.
+type PaginationResult struct {
+	Items     []any
+	Pages     int
+	Total     int // reported total, -1 when unknown
+	Truncated bool
+	Issues    []string // duplicates, skipped items, broken cursors
+	First     *TestResult
+	Last      *TestResult
+}
+
+var linkNextRe = regexp.MustCompile(`<([^>]+)>\s*;[^,]*rel="?next"?`)
+
+// Paginate fetches endpoint and follows its pages up to MaxPages. fetch is
+// called with an endpoint relative to the base URL; First and Last hold the
+// first and most recent page's result.
+func Paginate(fetch func(endpoint string) (*TestResult, error), endpoint string, opts PaginateOptions) (*PaginationResult, error) {
+	if opts.MaxPages <= 0 {
+		opts.MaxPages = DefaultMaxPages
+	}
+	if opts.CursorParam == "" {
+		opts.CursorParam = "cursor"
+	}
+	if opts.PageParam == "" {
+		opts.PageParam = "page"
+	}
+	if opts.OffsetParam == "" {
+		opts.OffsetParam = "offset"
+	}
+	if opts.StartPage == 0 {
+		opts.StartPage = 1
+	}
+	if opts.IDField == "" {
+		opts.IDField = "id"
+	}
+
+	res := &PaginationResult{Total: -1}
+	seen := make(map[string]int)
+	visited := map[string]bool{}
+	page, offset := opts.StartPage, 0
+
+	current := endpoint
+	switch opts.Style {
+	case PaginationPage:
+		current = withQuery(endpoint, opts.PageParam, strconv.Itoa(page), opts.SizeParam, opts.PageSize)
+	case PaginationOffset:
+		current = withQuery(endpoint, opts.OffsetParam, "0", opts.SizeParam, opts.PageSize)
+	default:
+		if opts.SizeParam != "" && opts.PageSize > 0 {
+			current = setQuery(endpoint, opts.SizeParam, strconv.Itoa(opts.PageSize))
+		}
+	}
+
+	for {
+		visited[current] = true
+		result, err := fetch(current)
+		if err != nil {
+			if res.Pages == 0 {
+				return nil, err
+			}
+			res.Issues = append(res.Issues, fmt.Sprintf("page %d (%s) failed: %v", res.Pages+1, current, err))
+			return res, nil
+		}
+		res.Pages++
+		if res.Pages == 1 {
+			res.First = result
+		}
+		res.Last = result
+
+		if result.StatusCode < 200 || result.StatusCode >= 300 {
+			if res.Pages > 1 {
+				res.Issues = append(res.Issues, fmt.Sprintf("page %d (%s) returned status %d", res.Pages, current, result.StatusCode))
+			}
+			return res, nil
+		}
+
+		var body any
+		if err := json.Unmarshal([]byte(result.ResponseBody), &body); err != nil {
+			res.Issues = append(res.Issues, fmt.Sprintf("page %d: response is not JSON", res.Pages))
+			return res, nil
+		}
+		items, ok := pageItems(body, opts.ItemsField)
+		if !ok {
+			res.Issues = append(res.Issues, fmt.Sprintf("page %d: no items array found", res.Pages))
+			return res, nil
+		}
+		if res.Pages == 1 {
+			res.Total = pageTotal(body, result, opts.TotalField)
+		}
+
+		for _, item := range items {
+			id, ok := itemID(item, opts.IDField)
+			if !ok {
+				continue
+			}
+			if first, dup := seen[id]; dup {
+				res.Issues = append(res.Issues, fmt.Sprintf("duplicate %s %s on page %d (first seen on page %d)", opts.IDField, id, res.Pages, first))
+			} else {
+				seen[id] = res.Pages
+			}
+		}
+		res.Items = append(res.Items, items...)
+
+		next, hasNext := nextPage(body, result, current, opts, &page, &offset, len(items))
+		if hasNext && len(items) == 0 && opts.Style != PaginationPage && opts.Style != PaginationOffset {
+			res.Issues = append(res.Issues, fmt.Sprintf("page %d is empty but returned a next cursor", res.Pages))
+			return res, nil
+		}
+		if !hasNext {
+			break
+		}
+		if visited[next] {
+			res.Issues = append(res.Issues, fmt.Sprintf("broken cursor on page %d: next page %s was already visited", res.Pages, next))
+			return res, nil
+		}
+		if res.Pages >= opts.MaxPages {
+			res.Truncated = true
+			return res, nil
+		}
+		current = next
+	}
+
+	if res.Total >= 0 && len(res.Items) != res.Total {
+		if len(res.Items) < res.Total {
+			res.Issues = append(res.Issues, fmt.Sprintf("skipped items: total is %d but %d were returned across %d pages", res.Total, len(res.Items), res.Pages))
+		} else {
+			res.Issues = append(res.Issues, fmt.Sprintf("total is %d but %d items were returned across %d pages", res.Total, len(res.Items), res.Pages))
+		}
+	}
+	return res, nil
+}
+
+// nextPage determines the endpoint of the following page.
+func nextPage(body any, result *TestResult, current string, opts PaginateOptions, page, offset *int, count int) (string, bool) {
+	switch opts.Style {
+	case PaginationPage:
+		if count == 0 || (opts.PageSize > 0 && count < opts.PageSize) {
+			return "", false
+		}
+		*page++
+		return setQuery(current, opts.PageParam, strconv.Itoa(*page)), true
+	case PaginationOffset:
+		if count == 0 || (opts.PageSize > 0 && count < opts.PageSize) {
+			return "", false
+		}
+		*offset += count
+		return setQuery(current, opts.OffsetParam, strconv.Itoa(*offset)), true
+	}
+
+	if opts.Style != PaginationCursor {
+		if m := linkNextRe.FindStringSubmatch(result.Headers["Link"]); m != nil {
+			return relativeEndpoint(m[1], opts.BaseURL, current), true
+		}
+		if opts.Style == PaginationLink {
+			return "", false
+		}
+	}
+
+	fields := []string{opts.NextField}
+	if opts.NextField == "" {
+		fields = []string{"next", "next_cursor", "nextCursor", "links.next", "meta.next_cursor", "pagination.next_cursor", "paging.next"}
+	}
+	for _, field := range fields {
+		v, ok := ResolvePath(body, field)
+		if !ok || v == nil {
+			continue
+		}
+		var cursor string
+		switch c := v.(type) {
+		case string:
+			cursor = c
+		case float64:
+			cursor = strconv.FormatFloat(c, 'f', -1, 64)
+		case map[string]any:
+			// e.g. {"links": {"next": {"href": "..."}}}
+			cursor, _ = c["href"].(string)
+		}
+		if cursor == "" {
+			return "", false
+		}
+		if strings.HasPrefix(cursor, "/") || strings.Contains(cursor, "://") || strings.HasPrefix(cursor, "?") {
+			return relativeEndpoint(cursor, opts.BaseURL, current), true
+		}
+		return setQuery(current, opts.CursorParam, cursor), true
+	}
+	return "", false
+}
+
+// pageItems finds the list of items in a page body.
+func pageItems(body any, field string) ([]any, bool) {
+	if field != "" {
+		v, ok := ResolvePath(body, field)
+		if !ok {
+			return nil, false
+		}
+		items, ok := v.([]any)
+		return items, ok
+	}
+	if items, ok := body.([]any); ok {
+		return items, true
+	}
+	for _, key := range []string{"data", "items", "results", "records", "content", "entries"} {
+		if v, ok := ResolvePath(body, key); ok {
+			if items, ok := v.([]any); ok {
+				return items, true
+			}
+		}
+	}
+	// Fall back to the only array at the top level.
+	if obj, ok := body.(map[string]any); ok {
+		var found []any
+		count := 0
+		for _, v := range obj {
+			if items, ok := v.([]any); ok {
+				found = items
+				count++
+			}
+		}
+		if count == 1 {
+			return found, true
+		}
+	}
+	return nil, false
+}
+
+func pageTotal(body any, result *TestResult, field string) int {
+	fields := []string{field}
+	if field == "" {
+		fields = []string{"total", "total_count", "totalCount", "meta.total", "pagination.total"}
+	}
+	for _, f := range fields {
+		if v, ok := ResolvePath(body, f); ok {
+			if n, err := toFloat(v); err == nil {
+				return int(n)
+			}
+		}
+	}
+	if n, err := strconv.Atoi(result.Headers["X-Total-Count"]); err == nil {
+		return n
+	}
+	return -1
+}
+
+func itemID(item any, field string) (string, bool) {
+	if field == "" {
+		return "", false
+	}
+	v, ok := ResolvePath(item, field)
+	if !ok || v == nil {
+		return "", false
+	}
+	if s, ok := v.(string); ok {
+		return s, true
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", false
+	}
+	return string(b), true
+}
+
+// relativeEndpoint converts a next link into an endpoint relative to baseURL.
+func relativeEndpoint(link, baseURL, current string) string {
+	if strings.HasPrefix(link, "?") {
+		path, _, _ := strings.Cut(current, "?")
+		return path + link
+	}
+	if baseURL != "" && strings.HasPrefix(link, baseURL) {
+		return strings.TrimPrefix(link, baseURL)
+	}
+	if u, err := url.Parse(link); err == nil && u.IsAbs() {
+		endpoint := u.EscapedPath()
+		if base, err := url.Parse(baseURL); err == nil && base.Path != "" {
+			endpoint = strings.TrimPrefix(endpoint, strings.TrimSuffix(base.Path, "/"))
+		}
+		if u.RawQuery != "" {
+			endpoint += "?" + u.RawQuery
+		}
+		return endpoint
+	}
+	return link
+}
+
+func withQuery(endpoint, key, value, sizeParam string, size int) string {
+	endpoint = setQuery(endpoint, key, value)
+	if sizeParam != "" && size > 0 {
+		endpoint = setQuery(endpoint, sizeParam, strconv.Itoa(size))
+	}
+	return endpoint
+}
+
+// setQuery sets a query parameter on an endpoint, keeping other parameters.
+func setQuery(endpoint, key, value string) string {
+	path, rawQuery, _ := strings.Cut(endpoint, "?")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		query = url.Values{}
+	}
+	query.Set(key, value)
+	return path + "?" + query.Encode()
+}
+
+// RunAggregateAssertions evaluates assertions over all collected items.
+// The ops "unique", "sorted_asc" and "sorted_desc" apply to a field of every
+// item; other ops apply to the summary {"count", "pages", "total", "items"}.
+func RunAggregateAssertions(res *PaginationResult, assertions []map[string]any) []string {
+	var failures []string
+	var summary []map[string]any
+	for _, a := range assertions {
+		field, _ := a["field"].(string)
+		op, _ := a["op"].(string)
+		switch op {
+		case "unique":
+			failures = append(failures, checkUnique(res.Items, field)...)
+		case "sorted_asc", "sorted_desc":
+			failures = append(failures, checkSorted(res.Items, field, op == "sorted_desc")...)
+		default:
+			summary = append(summary, a)
+		}
+	}
+	if len(summary) > 0 {
+		doc, _ := json.Marshal(map[string]any{
+			"count": len(res.Items),
+			"pages": res.Pages,
+			"total": res.Total,
+			"items": res.Items,
+		})
+		failures = append(failures, RunAssertions(string(doc), summary)...)
+	}
+	return failures
+}
+
+func checkUnique(items []any, field string) []string {
+	seen := make(map[string]int)
+	var failures []string
+	for i, item := range items {
+		id, ok := itemID(item, field)
+		if !ok {
+			continue
+		}
+		if first, dup := seen[id]; dup {
+			failures = append(failures, fmt.Sprintf("field %q: value %s at item %d duplicates item %d", field, id, i, first))
+			continue
+		}
+		seen[id] = i
+	}
+	return failures
+}
+
+func checkSorted(items []any, field string, desc bool) []string {
+	values := make([]any, 0, len(items))
+	for _, item := range items {
+		v, ok := ResolvePath(item, field)
+		if !ok {
+			return []string{fmt.Sprintf("field %q: missing on some items, cannot check order", field)}
+		}
+		values = append(values, v)
+	}
+	for i := 1; i < len(values); i++ {
+		if (!desc && less(values[i], values[i-1])) || (desc && less(values[i-1], values[i])) {
+			dir := "ascending"
+			if desc {
+				dir = "descending"
+			}
+			return []string{fmt.Sprintf("field %q: not %s at item %d (%v after %v)", field, dir, i, values[i], values[i-1])}
+		}
+	}
+	return nil
+}
+
+// less orders numbers numerically and everything else as strings.
+func less(a, b any) bool {
+	af, errA := toFloat(a)
+	bf, errB := toFloat(b)
+	if errA == nil && errB == nil {
+		return af < bf
+	}
+	return fmt.Sprint(a) < fmt.Sprint(b)
+}
@@ -0,0 +1,128 @@
+package tester
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// fakePages serves canned responses by endpoint and records requests.
+func fakePages(pages map[string]*TestResult, requested *[]string) func(string) (*TestResult, error) {
+	return func(endpoint string) (*TestResult, error) {
+		*requested = append(*requested, endpoint)
+		if r, ok := pages[endpoint]; ok {
+			return r, nil
+		}
+		return &TestResult{StatusCode: 404, ResponseBody: `{}`}, nil
+	}
+}
+
+func TestPaginate_LinkHeader(t *testing.T) {
+	var requested []string
+	fetch := fakePages(map[string]*TestResult{
+		"/users": {StatusCode: 200, ResponseBody: `[{"id":1},{"id":2}]`, Headers: map[string]string{
+			"Link":          `<https://api.example.com/v1/users?page=2>; rel="next", <https://api.example.com/v1/users?page=2>; rel="last"`,
+			"X-Total-Count": "3",
+		}},
+		"/users?page=2": {StatusCode: 200, ResponseBody: `[{"id":3}]`},
+	}, &requested)
+
+	res, err := Paginate(fetch, "/users", PaginateOptions{BaseURL: "https://api.example.com/v1"})
+	if err != nil {
+		t.Fatalf("Paginate failed: %v", err)
+	}
+	if res.Pages != 2 || len(res.Items) != 3 {
+		t.Errorf("expected 2 pages and 3 items, got %d pages, %d items (%v)", res.Pages, len(res.Items), requested)
+	}
+	if len(res.Issues) != 0 {
+		t.Errorf("expected no issues, got %v", res.Issues)
+	}
+}
+
+func TestPaginate_CursorIssues(t *testing.T) {
+	var requested []string
+	fetch := fakePages(map[string]*TestResult{
+		"/items":           {StatusCode: 200, ResponseBody: `{"data":[{"id":"a"},{"id":"b"}],"meta":{"next_cursor":"c2"},"total":5}`},
+		"/items?cursor=c2": {StatusCode: 200, ResponseBody: `{"data":[{"id":"b"},{"id":"c"}],"meta":{"next_cursor":"c2"}}`},
+	}, &requested)
+
+	res, err := Paginate(fetch, "/items", PaginateOptions{Style: PaginationCursor})
+	if err != nil {
+		t.Fatalf("Paginate failed: %v", err)
+	}
+	joined := strings.Join(res.Issues, "\n")
+	if !strings.Contains(joined, "duplicate id b on page 2") {
+		t.Errorf("expected duplicate report, got %v", res.Issues)
+	}
+	if !strings.Contains(joined, "broken cursor on page 2") {
+		t.Errorf("expected broken cursor report, got %v", res.Issues)
+	}
+}
+
+func TestPaginate_PageParamsAndSkippedItems(t *testing.T) {
+	var requested []string
+	pages := map[string]*TestResult{}
+	for p := 1; p <= 3; p++ {
+		body := fmt.Sprintf(`{"results":[{"id":%d},{"id":%d}],"total":7}`, p*10, p*10+1)
+		if p == 3 {
+			body = `{"results":[{"id":30}],"total":7}`
+		}
+		pages[fmt.Sprintf("/orders?page=%d&per_page=2", p)] = &TestResult{StatusCode: 200, ResponseBody: body}
+	}
+	fetch := fakePages(pages, &requested)
+
+	res, err := Paginate(fetch, "/orders", PaginateOptions{Style: PaginationPage, SizeParam: "per_page", PageSize: 2})
+	if err != nil {
+		t.Fatalf("Paginate failed: %v", err)
+	}
+	if res.Pages != 3 || len(res.Items) != 5 {
+		t.Fatalf("expected 3 pages and 5 items, got %d, %d (%v)", res.Pages, len(res.Items), requested)
+	}
+	if len(res.Issues) != 1 || !strings.HasPrefix(res.Issues[0], "skipped items") {
+		t.Errorf("expected skipped items report, got %v", res.Issues)
+	}
+}
+
+func TestPaginate_MaxPages(t *testing.T) {
+	var requested []string
+	fetch := func(endpoint string) (*TestResult, error) {
+		requested = append(requested, endpoint)
+		return &TestResult{StatusCode: 200, ResponseBody: fmt.Sprintf(`{"items":[{"id":%d}]}`, len(requested))}, nil
+	}
+
+	res, err := Paginate(fetch, "/feed", PaginateOptions{Style: PaginationOffset, MaxPages: 3})
+	if err != nil {
+		t.Fatalf("Paginate failed: %v", err)
+	}
+	if !res.Truncated || res.Pages != 3 {
+		t.Errorf("expected truncation after 3 pages, got %+v", res)
+	}
+	if requested[2] != "/feed?offset=2" {
+		t.Errorf("expected offset to advance by page size, got %v", requested)
+	}
+}
+
+func TestRunAggregateAssertions(t *testing.T) {
+	res := &PaginationResult{
+		Pages: 2,
+		Total: -1,
+		Items: []any{
+			map[string]any{"id": "a", "created": float64(3)},
+			map[string]any{"id": "b", "created": float64(2)},
+			map[string]any{"id": "a", "created": float64(1)},
+		},
+	}
+
+	failures := RunAggregateAssertions(res, []map[string]any{
+		{"field": "count", "op": "eq", "value": float64(3)},
+		{"field": "id", "op": "unique"},
+		{"field": "created", "op": "sorted_desc"},
+		{"field": "created", "op": "sorted_asc"},
+	})
+	if len(failures) != 2 {
+		t.Fatalf("expected 2 failures (unique, sorted_asc), got %v", failures)
+	}
+	if !strings.Contains(failures[0], "duplicates item 0") || !strings.Contains(failures[1], "not ascending") {
+		t.Errorf("unexpected failures: %v", failures)
+	}
+}
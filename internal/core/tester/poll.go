@@ -0,0 +1,89 @@
+package tester
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultPollInterval = 2 * time.Second
+	DefaultPollTimeout  = 60 * time.Second
+)
+
+// PollOptions configures repeated execution of a request until a condition holds.
+type PollOptions struct {
+	Until       []map[string]any // assertions that must all pass
+	UntilStatus int              // status code that must match, 0 = any
+	Interval    time.Duration
+	Timeout     time.Duration
+}
+
+// PollResult is the outcome of a polling run.
+type PollResult struct {
+	Result    *TestResult
+	Attempts  int
+	Elapsed   time.Duration
+	Satisfied bool
+	Failures  []string // unmet conditions on the last attempt
+}
+
+// pollSleep is replaced in tests.
+var pollSleep = time.Sleep
+
+// Poll calls exec until the conditions hold or the timeout expires.
+// Request errors are retried like unmet conditions; the last error is
+// returned only when no attempt produced a response.
+func Poll(exec func() (*TestResult, error), opts PollOptions) (*PollResult, error) {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultPollInterval
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultPollTimeout
+	}
+
+	start := time.Now()
+	res := &PollResult{}
+	var lastErr error
+	for {
+		res.Attempts++
+		result, err := exec()
+		if err != nil {
+			lastErr = err
+			res.Failures = []string{fmt.Sprintf("request failed: %v", err)}
+		} else {
+			res.Result = result
+			res.Failures = PollFailures(result, opts)
+			if len(res.Failures) == 0 {
+				res.Satisfied = true
+				res.Elapsed = time.Since(start)
+				return res, nil
+			}
+		}
+
+		if time.Since(start)+opts.Interval > opts.Timeout {
+			res.Elapsed = time.Since(start)
+			if res.Result == nil {
+				return res, lastErr
+			}
+			return res, nil
+		}
+		pollSleep(opts.Interval)
+	}
+}
+
+// PollFailures returns the unmet polling conditions for a response.
+func PollFailures(result *TestResult, opts PollOptions) []string {
+	var failures []string
+	if opts.UntilStatus != 0 && result.StatusCode != opts.UntilStatus {
+		failures = append(failures, fmt.Sprintf("status: expected %d, got %d", opts.UntilStatus, result.StatusCode))
+	}
+	if len(opts.Until) > 0 {
+		if strings.TrimSpace(result.ResponseBody) == "" {
+			failures = append(failures, "empty response body")
+		} else {
+			failures = append(failures, RunAssertions(result.ResponseBody, opts.Until)...)
+		}
+	}
+	return failures
+}
@@ -0,0 +1,93 @@
+package tester
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func withoutSleep(t *testing.T) *[]time.Duration {
+	t.Helper()
+	var slept []time.Duration
+	orig := pollSleep
+	pollSleep = func(d time.Duration) { slept = append(slept, d) }
+	t.Cleanup(func() { pollSleep = orig })
+	return &slept
+}
+
+func TestPoll_SatisfiedAfterRetries(t *testing.T) {
+	slept := withoutSleep(t)
+	bodies := []string{`{"status":"queued"}`, `{"status":"running"}`, `{"status":"done"}`}
+	calls := 0
+	exec := func() (*TestResult, error) {
+		b := bodies[calls]
+		calls++
+		return &TestResult{StatusCode: 200, ResponseBody: b}, nil
+	}
+
+	res, err := Poll(exec, PollOptions{
+		Until:    []map[string]any{{"field": "status", "op": "eq", "value": "done"}},
+		Interval: time.Millisecond,
+		Timeout:  time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+	if !res.Satisfied || res.Attempts != 3 {
+		t.Errorf("expected satisfied after 3 attempts, got %+v", res)
+	}
+	if len(*slept) != 2 {
+		t.Errorf("expected 2 sleeps between attempts, got %d", len(*slept))
+	}
+}
+
+func TestPoll_Timeout(t *testing.T) {
+	withoutSleep(t)
+	exec := func() (*TestResult, error) {
+		return &TestResult{StatusCode: 202, ResponseBody: `{"status":"running"}`}, nil
+	}
+
+	res, err := Poll(exec, PollOptions{
+		UntilStatus: 200,
+		Interval:    time.Hour,
+		Timeout:     time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+	if res.Satisfied || res.Attempts != 1 {
+		t.Errorf("expected a single unsatisfied attempt, got %+v", res)
+	}
+	if len(res.Failures) != 1 {
+		t.Errorf("expected status failure, got %v", res.Failures)
+	}
+}
+
+func TestPoll_RetriesErrors(t *testing.T) {
+	withoutSleep(t)
+	calls := 0
+	exec := func() (*TestResult, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("connection refused")
+		}
+		return &TestResult{StatusCode: 200, ResponseBody: `{}`}, nil
+	}
+
+	res, err := Poll(exec, PollOptions{UntilStatus: 200, Interval: time.Millisecond, Timeout: time.Minute})
+	if err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+	if !res.Satisfied || res.Attempts != 2 {
+		t.Errorf("expected success on second attempt, got %+v", res)
+	}
+}
+
+func TestPollFailures_EmptyBody(t *testing.T) {
+	failures := PollFailures(&TestResult{StatusCode: 200}, PollOptions{
+		Until: []map[string]any{{"field": "id", "op": "exists"}},
+	})
+	if len(failures) != 1 || failures[0] != "empty response body" {
+		t.Errorf("expected empty body failure, got %v", failures)
+	}
+}
@@ -19,11 +19,26 @@ func ValidateSchema(body string, schema map[string]any) []string {
 	}
 
 	var errors []string
-	validateValue(parsed, schema, "", &errors)
+	validateValue(parsed, schema, schema, "", &errors)
 	return errors
 }
 
-func validateValue(value any, schema map[string]any, path string, errors *[]string) {
+// resolveDef returns the $defs entry of root that a recursive reference in a
+// bundled schema points to, e.g. {"$ref": "#/$defs/Node"}.
+func resolveDef(schema, root map[string]any) map[string]any {
+	ref, ok := schema["$ref"].(string)
+	if !ok || !strings.HasPrefix(ref, "#/$defs/") {
+		return schema
+	}
+	defs, _ := root["$defs"].(map[string]any)
+	if def, ok := defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]any); ok {
+		return def
+	}
+	return schema
+}
+
+func validateValue(value any, schema, root map[string]any, path string, errors *[]string) {
+	schema = resolveDef(schema, root)
 	schemaType, _ := schema["type"].(string)
 
 	if value == nil {
@@ -44,9 +59,9 @@ func validateValue(value any, schema map[string]any, path string, errors *[]stri
 
 	switch schemaType {
 	case "object":
-		validateObject(value, schema, path, errors)
+		validateObject(value, schema, root, path, errors)
 	case "array":
-		validateArray(value, schema, path, errors)
+		validateArray(value, schema, root, path, errors)
 	case "string":
 		if _, ok := value.(string); !ok {
 			*errors = append(*errors, fieldError(path, "string", value))
@@ -71,15 +86,15 @@ func validateValue(value any, schema map[string]any, path string, errors *[]stri
 		}
 	case "":
 		if _, hasProps := schema["properties"]; hasProps {
-			validateObject(value, schema, path, errors)
+			validateObject(value, schema, root, path, errors)
 		}
 		if _, hasItems := schema["items"]; hasItems {
-			validateArray(value, schema, path, errors)
+			validateArray(value, schema, root, path, errors)
 		}
 	}
 }
 
-func validateObject(value any, schema map[string]any, path string, errors *[]string) {
+func validateObject(value any, schema, root map[string]any, path string, errors *[]string) {
 	obj, ok := value.(map[string]any)
 	if !ok {
 		*errors = append(*errors, fieldError(path, "object", value))
@@ -106,12 +121,12 @@ func validateObject(value any, schema map[string]any, path string, errors *[]str
 			if !exists {
 				continue
 			}
-			validateValue(fieldValue, propMap, childPath(path, fieldName), errors)
+			validateValue(fieldValue, propMap, root, childPath(path, fieldName), errors)
 		}
 	}
 }
 
-func validateArray(value any, schema map[string]any, path string, errors *[]string) {
+func validateArray(value any, schema, root map[string]any, path string, errors *[]string) {
 	arr, ok := value.([]any)
 	if !ok {
 		*errors = append(*errors, fieldError(path, "array", value))
@@ -124,7 +139,7 @@ func validateArray(value any, schema map[string]any, path string, errors *[]stri
 	}
 
 	for i, item := range arr {
-		validateValue(item, items, fmt.Sprintf("%s[%d]", fieldPath(path), i), errors)
+		validateValue(item, items, root, fmt.Sprintf("%s[%d]", fieldPath(path), i), errors)
 		if len(*errors) >= 10 {
 			break
 		}
@@ -0,0 +1,213 @@
+package tester
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultWebhookTimeout is how long to wait for a callback when none is given.
+const DefaultWebhookTimeout = 30 * time.Second
+
+// maxWebhookBody limits the size of a recorded callback body.
+const maxWebhookBody = 1 << 20
+
+// ReceivedCallback is a request recorded by a WebhookReceiver.
+type ReceivedCallback struct {
+	Method     string
+	Path       string
+	Query      string
+	Headers    map[string]string
+	Body       string
+	ReceivedAt time.Time
+}
+
+// Fields returns the callback as a JSON object for assertions: method, path,
+// query, headers and body (decoded when it is JSON).
+func (c ReceivedCallback) Fields() map[string]any {
+	headers := make(map[string]any, len(c.Headers))
+	for k, v := range c.Headers {
+		headers[strings.ToLower(k)] = v
+	}
+	var body any = c.Body
+	var parsed any
+	if err := json.Unmarshal([]byte(c.Body), &parsed); err == nil {
+		body = parsed
+	}
+	return map[string]any{
+		"method":  c.Method,
+		"path":    c.Path,
+		"query":   c.Query,
+		"headers": headers,
+		"body":    body,
+	}
+}
+
+// WebhookReceiver is an ephemeral HTTP listener that records every request it
+// receives, so tests can assert on callbacks sent by the API under test.
+type WebhookReceiver struct {
+	server   *http.Server
+	listener net.Listener
+	url      string
+
+	mu        sync.Mutex
+	callbacks []ReceivedCallback
+	changed   chan struct{} // closed and replaced on every callback
+}
+
+// StartWebhookReceiver listens on addr ("" = a random local port). publicURL
+// overrides the advertised URL, e.g. when a tunnel forwards to the listener.
+func StartWebhookReceiver(addr, publicURL string) (*WebhookReceiver, error) {
+	if addr == "" {
+		addr = "127.0.0.1:0"
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start webhook receiver: %w", err)
+	}
+
+	r := &WebhookReceiver{
+		listener: listener,
+		url:      strings.TrimSuffix(publicURL, "/"),
+		changed:  make(chan struct{}),
+	}
+	if r.url == "" {
+		r.url = "http://" + listener.Addr().String()
+	}
+	r.server = &http.Server{Handler: http.HandlerFunc(r.record), ReadHeaderTimeout: 10 * time.Second}
+	go func() { _ = r.server.Serve(listener) }()
+	return r, nil
+}
+
+// URL is the address callbacks should be sent to.
+func (r *WebhookReceiver) URL() string {
+	return r.url
+}
+
+// Close stops the listener.
+func (r *WebhookReceiver) Close() error {
+	return r.server.Close()
+}
+
+func (r *WebhookReceiver) record(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(io.LimitReader(req.Body, maxWebhookBody))
+	headers := make(map[string]string, len(req.Header))
+	for k, v := range req.Header {
+		headers[k] = strings.Join(v, ", ")
+	}
+
+	r.mu.Lock()
+	r.callbacks = append(r.callbacks, ReceivedCallback{
+		Method:     req.Method,
+		Path:       req.URL.Path,
+		Query:      req.URL.RawQuery,
+		Headers:    headers,
+		Body:       string(body),
+		ReceivedAt: time.Now(),
+	})
+	close(r.changed)
+	r.changed = make(chan struct{})
+	r.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(`{"received":true}`))
+}
+
+// Received returns the callbacks recorded at or after since.
+func (r *WebhookReceiver) Received(since time.Time) []ReceivedCallback {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []ReceivedCallback
+	for _, c := range r.callbacks {
+		if !c.ReceivedAt.Before(since) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// WebhookOptions describes the callback a test expects.
+type WebhookOptions struct {
+	Path    string           // path prefix the callback must be sent to, "" = any
+	Method  string           // "" = any
+	Until   []map[string]any // assertions on method, path, query, headers.* and body.*
+	Timeout time.Duration
+}
+
+// WebhookResult is the outcome of waiting for a callback.
+type WebhookResult struct {
+	Callback  *ReceivedCallback // the matching callback, or the closest candidate
+	Received  int               // callbacks seen while waiting
+	Elapsed   time.Duration
+	Satisfied bool
+	Failures  []string // unmet conditions of the closest candidate
+}
+
+// Wait blocks until a callback received at or after since satisfies opts, or
+// the timeout expires.
+func (r *WebhookReceiver) Wait(since time.Time, opts WebhookOptions) *WebhookResult {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultWebhookTimeout
+	}
+	start := time.Now()
+	deadline := time.NewTimer(opts.Timeout)
+	defer deadline.Stop()
+
+	res := &WebhookResult{}
+	for {
+		r.mu.Lock()
+		changed := r.changed
+		r.mu.Unlock()
+
+		candidates := r.Received(since)
+		res.Received = len(candidates)
+		for i := range candidates {
+			failures := WebhookFailures(candidates[i], opts)
+			if len(failures) == 0 {
+				res.Callback = &candidates[i]
+				res.Satisfied = true
+				res.Failures = nil
+				res.Elapsed = time.Since(start)
+				return res
+			}
+			if res.Callback == nil || len(failures) <= len(res.Failures) {
+				res.Callback = &candidates[i]
+				res.Failures = failures
+			}
+		}
+
+		select {
+		case <-changed:
+		case <-deadline.C:
+			res.Elapsed = time.Since(start)
+			if res.Received == 0 {
+				res.Failures = []string{fmt.Sprintf("no callback received within %s", opts.Timeout)}
+			}
+			return res
+		}
+	}
+}
+
+// WebhookFailures returns the unmet conditions for a recorded callback.
+func WebhookFailures(c ReceivedCallback, opts WebhookOptions) []string {
+	var failures []string
+	if opts.Method != "" && !strings.EqualFold(c.Method, opts.Method) {
+		failures = append(failures, fmt.Sprintf("method: expected %s, got %s", strings.ToUpper(opts.Method), c.Method))
+	}
+	if opts.Path != "" && !strings.HasPrefix(c.Path, opts.Path) {
+		failures = append(failures, fmt.Sprintf("path: expected %s, got %s", opts.Path, c.Path))
+	}
+	if len(opts.Until) > 0 {
+		data, err := json.Marshal(c.Fields())
+		if err != nil {
+			return append(failures, fmt.Sprintf("failed to encode callback: %v", err))
+		}
+		failures = append(failures, RunAssertions(string(data), opts.Until)...)
+	}
+	return failures
+}
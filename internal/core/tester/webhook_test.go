@@ -0,0 +1,82 @@
+package tester
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func startReceiver(t *testing.T) *WebhookReceiver {
+	t.Helper()
+	r, err := StartWebhookReceiver("", "")
+	if err != nil {
+		t.Fatalf("failed to start receiver: %v", err)
+	}
+	t.Cleanup(func() { _ = r.Close() })
+	return r
+}
+
+func postCallback(t *testing.T, url, body string) {
+	t.Helper()
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Errorf("callback failed: %v", err)
+		return
+	}
+	_ = resp.Body.Close()
+}
+
+func TestWebhookReceiver_WaitForMatchingCallback(t *testing.T) {
+	r := startReceiver(t)
+	since := time.Now()
+
+	go func() {
+		postCallback(t, r.URL()+"/payments", `{"status":"pending"}`)
+		time.Sleep(20 * time.Millisecond)
+		postCallback(t, r.URL()+"/payments", `{"status":"paid","amount":10}`)
+	}()
+
+	res := r.Wait(since, WebhookOptions{
+		Path:    "/payments",
+		Method:  "post",
+		Until:   []map[string]any{{"field": "body.status", "op": "eq", "value": "paid"}},
+		Timeout: 5 * time.Second,
+	})
+	if !res.Satisfied {
+		t.Fatalf("expected matching callback, got %+v", res)
+	}
+	if res.Received != 2 || res.Callback.Headers["Content-Type"] != "application/json" {
+		t.Errorf("unexpected callback: %+v", res)
+	}
+}
+
+func TestWebhookReceiver_Timeout(t *testing.T) {
+	r := startReceiver(t)
+	since := time.Now()
+	postCallback(t, r.URL()+"/other", `{"status":"failed"}`)
+
+	res := r.Wait(since, WebhookOptions{
+		Until:   []map[string]any{{"field": "body.status", "op": "eq", "value": "paid"}},
+		Timeout: 50 * time.Millisecond,
+	})
+	if res.Satisfied {
+		t.Fatal("expected no match")
+	}
+	if res.Callback == nil || len(res.Failures) != 1 {
+		t.Errorf("expected closest candidate with one failure, got %+v", res)
+	}
+}
+
+func TestWebhookReceiver_IgnoresEarlierCallbacks(t *testing.T) {
+	r := startReceiver(t)
+	postCallback(t, r.URL(), `{}`)
+
+	res := r.Wait(time.Now(), WebhookOptions{Timeout: 20 * time.Millisecond})
+	if res.Satisfied || res.Received != 0 {
+		t.Fatalf("expected earlier callback to be ignored, got %+v", res)
+	}
+	if len(res.Failures) != 1 || !strings.HasPrefix(res.Failures[0], "no callback received") {
+		t.Errorf("unexpected failures: %v", res.Failures)
+	}
+}
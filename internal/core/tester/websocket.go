@@ -0,0 +1,303 @@
+package tester
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultWebSocketTimeout is how long to wait for messages when none is given.
+const DefaultWebSocketTimeout = 10 * time.Second
+
+// maxWebSocketMessage limits the size of a received message.
+const maxWebSocketMessage = 1 << 20
+
+// websocketGUID is the key suffix of the opening handshake (RFC 6455).
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket frame opcodes.
+const (
+	wsContinuation = 0x0
+	wsText         = 0x1
+	wsBinary       = 0x2
+	wsClose        = 0x8
+	wsPing         = 0x9
+	wsPong         = 0xA
+)
+
+// WebSocketOptions describes a WebSocket exchange: the messages sent after
+// connecting and how many messages to wait for.
+type WebSocketOptions struct {
+	Send    []any // JSON-encoded unless strings
+	Receive int
+	Timeout time.Duration
+}
+
+// ExecuteWebSocket connects to a WebSocket endpoint, sends the messages and
+// collects up to opts.Receive messages until the timeout. The result has the
+// handshake status, 101 on success, and a body of {"messages": [...],
+// "count": n} with JSON messages decoded. A refused upgrade is returned like
+// any other response.
+func (e *Executor) ExecuteWebSocket(method, endpoint string, headers map[string]string, opts WebSocketOptions, requiresAuth bool) (*TestResult, error) {
+	startTime := time.Now()
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultWebSocketTimeout
+	}
+	deadline := startTime.Add(opts.Timeout)
+
+	fullURL := e.baseURL + endpoint
+	if !strings.Contains(fullURL, "://") {
+		fullURL = "http://" + fullURL
+	}
+	target, err := url.Parse(fullURL)
+	if err != nil {
+		err = fmt.Errorf("failed to parse URL: %w", err)
+		return &TestResult{Error: err}, err
+	}
+	switch target.Scheme {
+	case "ws":
+		target.Scheme = "http"
+	case "wss":
+		target.Scheme = "https"
+	}
+
+	key := make([]byte, 16)
+	_, _ = rand.Read(key)
+	req, err := http.NewRequest(http.MethodGet, target.String(), nil)
+	if err != nil {
+		err = fmt.Errorf("failed to create request: %w", err)
+		return &TestResult{Error: err}, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", base64.StdEncoding.EncodeToString(key))
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	if requiresAuth {
+		if provider := e.providerFor(method, endpoint); provider != nil {
+			if err := provider.Apply(req); err != nil {
+				err = fmt.Errorf("failed to apply auth: %w", err)
+				return &TestResult{Error: err}, err
+			}
+		}
+	}
+
+	conn, err := dialWebSocket(target, deadline)
+	if err != nil {
+		return &TestResult{Duration: time.Since(startTime), Error: fmt.Errorf("request failed: %w", err)}, err
+	}
+	defer func() { _ = conn.Close() }()
+	_ = conn.SetDeadline(deadline)
+
+	if err := req.Write(conn); err != nil {
+		return &TestResult{Duration: time.Since(startTime), Error: fmt.Errorf("request failed: %w", err)}, err
+	}
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		return &TestResult{Duration: time.Since(startTime), Error: fmt.Errorf("request failed: %w", err)}, err
+	}
+
+	result := &TestResult{StatusCode: resp.StatusCode, Headers: make(map[string]string), Cookies: resp.Cookies()}
+	for k := range resp.Header {
+		result.Headers[k] = resp.Header.Get(k)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxWebSocketMessage))
+		_ = resp.Body.Close()
+		result.ResponseBody = string(body)
+		result.Duration = time.Since(startTime)
+		return result, nil
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != acceptKey(req.Header.Get("Sec-WebSocket-Key")) {
+		err := fmt.Errorf("invalid WebSocket handshake: Sec-WebSocket-Accept does not match")
+		return &TestResult{StatusCode: resp.StatusCode, Duration: time.Since(startTime), Error: err}, err
+	}
+
+	for _, msg := range opts.Send {
+		payload, ok := msg.(string)
+		if !ok {
+			data, err := json.Marshal(msg)
+			if err != nil {
+				err = fmt.Errorf("failed to marshal message: %w", err)
+				return &TestResult{Error: err}, err
+			}
+			payload = string(data)
+		}
+		if err := writeFrame(conn, wsText, []byte(payload), true); err != nil {
+			return &TestResult{Duration: time.Since(startTime), Error: fmt.Errorf("failed to send message: %w", err)}, err
+		}
+	}
+
+	messages := []any{}
+	for len(messages) < opts.Receive {
+		_, data, err := readMessage(reader, conn)
+		if err != nil {
+			var netErr net.Error
+			if errors.Is(err, io.EOF) || (errors.As(err, &netErr) && netErr.Timeout()) {
+				break
+			}
+			return &TestResult{Duration: time.Since(startTime), Error: fmt.Errorf("failed to read message: %w", err)}, err
+		}
+		var decoded any
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			decoded = string(data)
+		}
+		messages = append(messages, decoded)
+	}
+	_ = conn.SetWriteDeadline(time.Now().Add(time.Second))
+	_ = writeFrame(conn, wsClose, binary.BigEndian.AppendUint16(nil, 1000), true)
+
+	body, _ := json.Marshal(map[string]any{"messages": messages, "count": len(messages)})
+	result.ResponseBody = string(body)
+	result.Duration = time.Since(startTime)
+	return result, nil
+}
+
+// dialWebSocket opens the connection for a ws (http) or wss (https) URL.
+func dialWebSocket(target *url.URL, deadline time.Time) (net.Conn, error) {
+	host := target.Host
+	if target.Port() == "" {
+		if target.Scheme == "https" {
+			host = net.JoinHostPort(target.Hostname(), "443")
+		} else {
+			host = net.JoinHostPort(target.Hostname(), "80")
+		}
+	}
+	dialer := &net.Dialer{Deadline: deadline}
+	if target.Scheme == "https" {
+		return tls.DialWithDialer(dialer, "tcp", host, &tls.Config{ServerName: target.Hostname()})
+	}
+	return dialer.Dial("tcp", host)
+}
+
+// acceptKey returns the Sec-WebSocket-Accept value for a handshake key.
+func acceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// writeFrame writes a single unfragmented frame. Clients must mask frames,
+// servers must not.
+func writeFrame(w io.Writer, opcode byte, payload []byte, mask bool) error {
+	header := []byte{0x80 | opcode}
+	maskBit := byte(0)
+	if mask {
+		maskBit = 0x80
+	}
+	switch n := len(payload); {
+	case n < 126:
+		header = append(header, maskBit|byte(n))
+	case n <= 0xFFFF:
+		header = append(header, maskBit|126)
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header = append(header, maskBit|127)
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+	if mask {
+		key := make([]byte, 4)
+		_, _ = rand.Read(key)
+		header = append(header, key...)
+		masked := make([]byte, len(payload))
+		for i, b := range payload {
+			masked[i] = b ^ key[i%4]
+		}
+		payload = masked
+	}
+	_, err := w.Write(append(header, payload...))
+	return err
+}
+
+// readFrame reads one frame and unmasks its payload.
+func readFrame(r *bufio.Reader) (fin bool, opcode byte, payload []byte, err error) {
+	var head [2]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin = head[0]&0x80 != 0
+	opcode = head[0] & 0x0F
+	length := uint64(head[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > maxWebSocketMessage {
+		return false, 0, nil, fmt.Errorf("frame of %d bytes exceeds %d", length, maxWebSocketMessage)
+	}
+	var key [4]byte
+	masked := head[1]&0x80 != 0
+	if masked {
+		if _, err := io.ReadFull(r, key[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= key[i%4]
+		}
+	}
+	return fin, opcode, payload, nil
+}
+
+// readMessage returns the next text or binary message, joining fragments and
+// answering pings on w. A close frame ends the exchange with io.EOF.
+func readMessage(r *bufio.Reader, w io.Writer) (byte, []byte, error) {
+	var opcode byte
+	var message []byte
+	for {
+		fin, op, payload, err := readFrame(r)
+		if err != nil {
+			return 0, nil, err
+		}
+		switch op {
+		case wsPing:
+			if err := writeFrame(w, wsPong, payload, true); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case wsPong:
+			continue
+		case wsClose:
+			return 0, nil, io.EOF
+		case wsText, wsBinary:
+			opcode, message = op, payload
+		case wsContinuation:
+			message = append(message, payload...)
+		}
+		if len(message) > maxWebSocketMessage {
+			return 0, nil, fmt.Errorf("message exceeds %d bytes", maxWebSocketMessage)
+		}
+		if fin {
+			return opcode, message, nil
+		}
+	}
+}
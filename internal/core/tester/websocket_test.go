@@ -0,0 +1,122 @@
+package tester
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Octrafic/octrafic-cli/internal/core/auth"
+)
+
+// echoWebSocket upgrades authenticated requests, pings the client, answers
+// every message with {"echo": message} and closes after two replies.
+func echoWebSocket(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
+			return
+		}
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("hijack failed: %v", err)
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+			"Sec-WebSocket-Accept: " + acceptKey(r.Header.Get("Sec-WebSocket-Key")) + "\r\n\r\n")
+		_ = rw.Flush()
+
+		_ = writeFrame(conn, wsPing, []byte("ping"), false)
+		reader := bufio.NewReader(rw)
+		for range 2 {
+			// The pong to the ping arrives first and is skipped.
+			var opcode byte
+			var payload []byte
+			for opcode != wsText {
+				_, opcode, payload, err = readFrame(reader)
+				if err != nil {
+					return
+				}
+			}
+			reply, _ := json.Marshal(map[string]any{"echo": json.RawMessage(payload)})
+			_ = writeFrame(conn, wsText, reply, false)
+		}
+		_ = writeFrame(conn, wsClose, nil, false)
+	}))
+}
+
+func TestExecuteWebSocket(t *testing.T) {
+	server := echoWebSocket(t)
+	defer server.Close()
+
+	executor := NewExecutor(server.URL, &auth.BearerAuth{Token: "secret"})
+	result, err := executor.ExecuteWebSocket("PUBLISH", "/chat", nil, WebSocketOptions{
+		Send:    []any{map[string]any{"text": "hi"}, `{"text":"bye"}`},
+		Receive: 5,
+		Timeout: 5 * time.Second,
+	}, true)
+	if err != nil {
+		t.Fatalf("ExecuteWebSocket failed: %v", err)
+	}
+	if result.StatusCode != http.StatusSwitchingProtocols {
+		t.Errorf("expected 101, got %d", result.StatusCode)
+	}
+
+	var body struct {
+		Messages []map[string]any `json:"messages"`
+		Count    int              `json:"count"`
+	}
+	if err := json.Unmarshal([]byte(result.ResponseBody), &body); err != nil {
+		t.Fatalf("invalid body %q: %v", result.ResponseBody, err)
+	}
+	if body.Count != 2 || len(body.Messages) != 2 {
+		t.Fatalf("expected 2 messages before the server closed, got %s", result.ResponseBody)
+	}
+	echo, _ := body.Messages[1]["echo"].(map[string]any)
+	if echo["text"] != "bye" {
+		t.Errorf("expected second echo of bye, got %v", body.Messages[1])
+	}
+	if failures := RunAssertions(result.ResponseBody, []map[string]any{{"field": "messages.0.echo.text", "op": "eq", "value": "hi"}}); len(failures) > 0 {
+		t.Errorf("expected assertion on messages to pass: %v", failures)
+	}
+}
+
+func TestExecuteWebSocket_RefusedUpgrade(t *testing.T) {
+	server := echoWebSocket(t)
+	defer server.Close()
+
+	executor := NewExecutor(server.URL, nil)
+	result, err := executor.ExecuteWebSocket("SUBSCRIBE", "/chat", nil, WebSocketOptions{Receive: 1, Timeout: time.Second}, false)
+	if err != nil {
+		t.Fatalf("ExecuteWebSocket failed: %v", err)
+	}
+	if result.StatusCode != http.StatusUnauthorized || !strings.Contains(result.ResponseBody, "unauthorized") {
+		t.Errorf("expected 401 response, got %d %q", result.StatusCode, result.ResponseBody)
+	}
+}
+
+func TestExecuteWebSocket_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, rw, _ := w.(http.Hijacker).Hijack()
+		defer func() { _ = conn.Close() }()
+		_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+			"Sec-WebSocket-Accept: " + acceptKey(r.Header.Get("Sec-WebSocket-Key")) + "\r\n\r\n")
+		_ = rw.Flush()
+		_ = writeFrame(conn, wsText, []byte("ready"), false)
+		time.Sleep(time.Second)
+	}))
+	defer server.Close()
+
+	executor := NewExecutor(server.URL, nil)
+	result, err := executor.ExecuteWebSocket("SUBSCRIBE", "/events", nil, WebSocketOptions{Receive: 3, Timeout: 200 * time.Millisecond}, false)
+	if err != nil {
+		t.Fatalf("expected timeout to end the exchange without error, got %v", err)
+	}
+	if result.ResponseBody != `{"count":1,"messages":["ready"]}` {
+		t.Errorf("expected the one message received in time, got %s", result.ResponseBody)
+	}
+}
@@ -1,9 +1,14 @@
 package exporter
 
 import (
+	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"unicode"
 )
 
 type CurlExporter struct{}
@@ -38,8 +43,16 @@ func (e *CurlExporter) Export(req ExportRequest) error {
 			script.WriteString("\n")
 		}
 
-		fmt.Fprintf(&script, "# Test %d: %s %s\n", i+1, test.Method, test.Endpoint)
-		script.WriteString(e.buildCurlCommand(test, req))
+		if test.Name != "" {
+			fmt.Fprintf(&script, "# Test %d: %s (%s %s)\n", i+1, test.Name, test.Method, test.Endpoint)
+		} else {
+			fmt.Fprintf(&script, "# Test %d: %s %s\n", i+1, test.Method, test.Endpoint)
+		}
+		if test.Poll != nil {
+			script.WriteString(e.buildPollLoop(test, req))
+		} else {
+			script.WriteString(e.buildCurlCommand(test, req))
+		}
 		script.WriteString("\n")
 	}
 
@@ -56,37 +69,167 @@ func (e *CurlExporter) buildCurlCommand(test TestData, req ExportRequest) string
 	parts = append(parts, "curl")
 	parts = append(parts, fmt.Sprintf("-X %s", test.Method))
 
-	for key, value := range test.Headers {
-		parts = append(parts, fmt.Sprintf("-H '%s: %s'", key, value))
+	hasContentType := false
+	for _, key := range slices.Sorted(maps.Keys(test.Headers)) {
+		parts = append(parts, "-H "+singleQuote(key+": "+test.Headers[key]))
+		hasContentType = hasContentType || strings.EqualFold(key, "Content-Type")
 	}
 
-	if test.Body != nil {
-		if bodyStr, ok := test.Body.(string); ok && bodyStr != "" {
-			parts = append(parts, "-H 'Content-Type: application/json'")
-		}
+	body, hasBody := bodyString(test.Body)
+	if body != "" && !hasContentType {
+		parts = append(parts, "-H 'Content-Type: application/json'")
 	}
 
+	// Credentials are expanded by the shell, so they are double-quoted.
 	if test.RequiresAuth && req.AuthType != "" {
 		switch req.AuthType {
 		case "bearer":
-			parts = append(parts, "-H 'Authorization: Bearer ${AUTH_TOKEN}'")
+			parts = append(parts, "-H \"Authorization: Bearer ${AUTH_TOKEN}\"")
 		case "apikey":
 			if keyName, ok := req.AuthData["key_name"]; ok {
-				parts = append(parts, fmt.Sprintf("-H '%s: ${API_KEY_VALUE}'", keyName))
+				parts = append(parts, fmt.Sprintf("-H \"%s: ${API_KEY_VALUE}\"", doubleQuoteEscape(keyName)))
 			}
 		case "basic":
-			parts = append(parts, "-u '${AUTH_USER}:${AUTH_PASS}'")
+			parts = append(parts, "-u \"${AUTH_USER}:${AUTH_PASS}\"")
 		}
 	}
 
-	if test.Body != nil {
-		if bodyStr, ok := test.Body.(string); ok {
-			escapedBody := strings.ReplaceAll(bodyStr, "'", "'\\''")
-			parts = append(parts, fmt.Sprintf("-d '%s'", escapedBody))
+	if hasBody {
+		// -d reads a file for bodies starting with @.
+		if strings.HasPrefix(body, "@") {
+			parts = append(parts, "--data-raw "+singleQuote(body))
+		} else {
+			parts = append(parts, "-d "+singleQuote(body))
 		}
 	}
 
-	parts = append(parts, "\"${BASE_URL}"+test.Endpoint+"\"")
+	parts = append(parts, "\"${BASE_URL}"+doubleQuoteEscape(test.Endpoint)+"\"")
 
 	return strings.Join(parts, " \\\n  ")
 }
+
+// bodyString returns a request body as sent: strings as they are, other
+// values as JSON.
+func bodyString(body any) (string, bool) {
+	switch b := body.(type) {
+	case nil:
+		return "", false
+	case string:
+		return b, true
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+// singleQuote quotes s for the shell, without expansion.
+func singleQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "'\\''") + "'"
+}
+
+// doubleQuoteEscape escapes the characters that are special inside double
+// quotes.
+func doubleQuoteEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`").Replace(s)
+}
+
+// buildPollLoop repeats the curl command until the poll conditions hold.
+// Field conditions are evaluated with jq.
+func (e *CurlExporter) buildPollLoop(test TestData, req ExportRequest) string {
+	poll := test.Poll
+	cmd := strings.Replace(e.buildCurlCommand(test, req), "curl", "curl -s -w '\\n%{http_code}'", 1)
+	cmd = strings.ReplaceAll(cmd, "\n  ", "\n      ")
+
+	var conds []string
+	if poll.UntilStatus > 0 {
+		conds = append(conds, fmt.Sprintf("[ \"$status\" = \"%d\" ]", poll.UntilStatus))
+	}
+	if len(poll.Until) > 0 {
+		exprs := make([]string, len(poll.Until))
+		for i, c := range poll.Until {
+			exprs[i] = jqCondition(c)
+		}
+		jq := strings.ReplaceAll(strings.Join(exprs, " and "), "'", "'\\''")
+		conds = append(conds, fmt.Sprintf("echo \"$body\" | jq -e '%s' > /dev/null", jq))
+	}
+	if len(conds) == 0 {
+		conds = append(conds, "[ \"$status\" -lt 400 ]")
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Poll every %gs for up to %gs\n", poll.interval(), poll.timeout())
+	fmt.Fprintf(&b, "deadline=$((SECONDS + %d))\n", int(poll.timeout()))
+	b.WriteString("while true; do\n")
+	fmt.Fprintf(&b, "  response=$(%s)\n", cmd)
+	b.WriteString("  status=${response##*$'\\n'}\n")
+	b.WriteString("  body=${response%$'\\n'*}\n")
+	fmt.Fprintf(&b, "  if %s; then\n", strings.Join(conds, " && "))
+	b.WriteString("    echo \"$body\"\n")
+	b.WriteString("    break\n")
+	b.WriteString("  fi\n")
+	b.WriteString("  if [ \"$SECONDS\" -ge \"$deadline\" ]; then\n")
+	fmt.Fprintf(&b, "    echo '%s' >&2\n", strings.ReplaceAll(poll.message(), "'", "'\\''"))
+	b.WriteString("    exit 1\n")
+	b.WriteString("  fi\n")
+	fmt.Fprintf(&b, "  sleep %g\n", poll.interval())
+	b.WriteString("done")
+	return b.String()
+}
+
+// jqCondition renders an assertion as a jq boolean expression.
+func jqCondition(c Condition) string {
+	path := jqPath(c.Field)
+	value, err := json.Marshal(c.Value)
+	if err != nil {
+		value = []byte("null")
+	}
+	switch c.Op {
+	case "eq":
+		return fmt.Sprintf("%s == %s", path, value)
+	case "neq":
+		return fmt.Sprintf("%s != %s", path, value)
+	case "exists":
+		return fmt.Sprintf("%s != null", path)
+	case "not_exists":
+		return fmt.Sprintf("%s == null", path)
+	case "contains":
+		return fmt.Sprintf("(%s | tostring | contains(%s))", path, value)
+	case "gt", "gte", "lt", "lte":
+		ops := map[string]string{"gt": ">", "gte": ">=", "lt": "<", "lte": "<="}
+		return fmt.Sprintf("(%s != null and %s %s %s)", path, path, ops[c.Op], value)
+	}
+	return "false"
+}
+
+// jqPath converts dot notation ("items.0.id") into a jq path (".items[0].id").
+func jqPath(field string) string {
+	if field == "" {
+		return "."
+	}
+	var b strings.Builder
+	for _, key := range strings.Split(field, ".") {
+		if _, err := strconv.Atoi(key); err == nil {
+			fmt.Fprintf(&b, "[%s]", key)
+		} else if isIdentifier(key) {
+			b.WriteString("." + key)
+		} else {
+			fmt.Fprintf(&b, "[%s]", strconv.Quote(key))
+		}
+	}
+	path := b.String()
+	if strings.HasPrefix(path, "[") {
+		path = "." + path
+	}
+	return path
+}
+
+func isIdentifier(s string) bool {
+	for i, r := range s {
+		if r != '_' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,71 @@
+package exporter
+
+import (
+	"maps"
+	"path/filepath"
+	"testing"
+
+	"github.com/Octrafic/octrafic-cli/internal/core/parser"
+)
+
+func TestCurlExporter_RoundTrip(t *testing.T) {
+	tests := []TestData{
+		{Name: "createUser", Method: "POST", Endpoint: "/users?notify=true", RequiresAuth: true,
+			Headers: map[string]string{"X-Request-Id": "it's 1", "Accept": "application/json"},
+			Body:    `{"name": "Ada Lovelace", "bio": "Says \"hi\" & it's $HOME"}`},
+		{Method: "GET", Endpoint: "/users/42", RequiresAuth: true},
+		{Name: "updateUser", Method: "PATCH", Endpoint: "/users/42", Body: map[string]any{"name": "Grace"}},
+		{Name: "waitForExport", Method: "GET", Endpoint: "/exports/7", Poll: &PollData{UntilStatus: 200}},
+	}
+	for _, auth := range []string{"bearer", "basic", "apikey"} {
+		t.Run(auth, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "tests.sh")
+			err := (&CurlExporter{}).Export(ExportRequest{
+				BaseURL:  "https://api.example.com/v1",
+				Tests:    tests,
+				FilePath: path,
+				AuthType: auth,
+				AuthData: map[string]string{"key_name": "X-Api-Key"},
+			})
+			if err != nil {
+				t.Fatalf("Export failed: %v", err)
+			}
+			spec, err := parser.ParseShellScript(path)
+			if err != nil {
+				t.Fatalf("ParseShellScript failed: %v", err)
+			}
+			if len(spec.Endpoints) != len(tests) {
+				t.Fatalf("expected %d endpoints, got %d", len(tests), len(spec.Endpoints))
+			}
+
+			for i, test := range tests {
+				ep := spec.Endpoints[i]
+				if ep.Method != test.Method || ep.OperationID != test.Name {
+					t.Errorf("test %d: expected %s %q, got %s %q", i, test.Method, test.Name, ep.Method, ep.OperationID)
+				}
+				if ep.RequiresAuth != test.RequiresAuth || (test.RequiresAuth && ep.AuthType != auth) {
+					t.Errorf("test %d: expected auth %v %s, got %v %s", i, test.RequiresAuth, auth, ep.RequiresAuth, ep.AuthType)
+				}
+
+				path, headers, body := ep.SampleRequest()
+				if path != test.Endpoint {
+					t.Errorf("test %d: expected path %s, got %s", i, test.Endpoint, path)
+				}
+				want, _ := bodyString(test.Body)
+				if body != want {
+					t.Errorf("test %d: expected body %q, got %q", i, want, body)
+				}
+				wantHeaders := maps.Clone(test.Headers)
+				if wantHeaders == nil {
+					wantHeaders = map[string]string{}
+				}
+				if want != "" {
+					wantHeaders["Content-Type"] = "application/json"
+				}
+				if !maps.Equal(headers, wantHeaders) {
+					t.Errorf("test %d: expected headers %v, got %v", i, wantHeaders, headers)
+				}
+			}
+		})
+	}
+}
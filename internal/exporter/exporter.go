@@ -8,6 +8,7 @@ import (
 
 // TestData represents a single test result to export
 type TestData struct {
+	Name         string // operation name from the spec, e.g. its operationId
 	Method       string
 	Endpoint     string
 	Headers      map[string]string
@@ -17,6 +18,47 @@ type TestData struct {
 	DurationMS   int64
 	RequiresAuth bool
 	Error        string
+	Poll         *PollData
+}
+
+// Condition is an assertion on a response field, e.g. status eq "done".
+type Condition struct {
+	Field string
+	Op    string
+	Value any
+}
+
+// PollData describes a request that is repeated until its conditions hold.
+type PollData struct {
+	Until           []Condition
+	UntilStatus     int
+	IntervalSeconds float64
+	TimeoutSeconds  float64
+	Message         string
+}
+
+// interval returns the delay between attempts, defaulting to 2 seconds.
+func (p *PollData) interval() float64 {
+	if p.IntervalSeconds <= 0 {
+		return 2
+	}
+	return p.IntervalSeconds
+}
+
+// timeout returns the polling deadline, defaulting to 60 seconds.
+func (p *PollData) timeout() float64 {
+	if p.TimeoutSeconds <= 0 {
+		return 60
+	}
+	return p.TimeoutSeconds
+}
+
+// message returns the failure message shown when polling times out.
+func (p *PollData) message() string {
+	if p.Message == "" {
+		return "condition not met before timeout"
+	}
+	return p.Message
 }
 
 // ExportRequest contains all data needed for export
@@ -31,12 +31,28 @@ func (e *PostmanExporter) Export(req ExportRequest) error {
 func (e *PostmanExporter) buildCollection(req ExportRequest) map[string]interface{} {
 	items := make([]map[string]interface{}, 0, len(req.Tests))
 
-	for _, test := range req.Tests {
+	for i, test := range req.Tests {
+		name := fmt.Sprintf("%s %s", test.Method, test.Endpoint)
+		if test.Name != "" {
+			name = test.Name
+		}
 		item := map[string]interface{}{
-			"name":    fmt.Sprintf("%s %s", test.Method, test.Endpoint),
+			"name":    name,
 			"request": e.buildRequest(test, req),
 		}
 
+		if test.Poll != nil {
+			item["event"] = []map[string]interface{}{
+				{
+					"listen": "test",
+					"script": map[string]interface{}{
+						"type": "text/javascript",
+						"exec": e.buildPollScript(test.Poll, i),
+					},
+				},
+			}
+		}
+
 		if test.Error == "" && test.StatusCode > 0 {
 			item["response"] = []map[string]interface{}{
 				e.buildResponse(test),
@@ -140,3 +156,63 @@ func (e *PostmanExporter) buildResponse(test TestData) map[string]interface{} {
 		"body": test.ResponseBody,
 	}
 }
+
+// buildPollScript re-runs the request via setNextRequest until the poll
+// conditions hold. This requires running the collection in the Collection Runner.
+func (e *PostmanExporter) buildPollScript(poll *PollData, index int) []string {
+	var conds []string
+	if poll.UntilStatus > 0 {
+		conds = append(conds, fmt.Sprintf("pm.response.code === %d", poll.UntilStatus))
+	}
+	for _, c := range poll.Until {
+		conds = append(conds, jsCondition(c))
+	}
+	if len(conds) == 0 {
+		conds = append(conds, "pm.response.code < 400")
+	}
+	message, _ := json.Marshal(poll.message())
+
+	return []string{
+		"const _get = (d, p) => p.split('.').reduce((o, k) => (o == null ? undefined : o[k]), d);",
+		"let body;",
+		"try { body = pm.response.json(); } catch (e) { body = undefined; }",
+		fmt.Sprintf("const key = '_poll_started_%d';", index+1),
+		"const started = Number(pm.collectionVariables.get(key) || Date.now());",
+		"pm.collectionVariables.set(key, started);",
+		fmt.Sprintf("if (%s) {", strings.Join(conds, " && ")),
+		"    pm.collectionVariables.unset(key);",
+		fmt.Sprintf("} else if (Date.now() - started < %d) {", int(poll.timeout()*1000)),
+		fmt.Sprintf("    setTimeout(() => {}, %d);", int(poll.interval()*1000)),
+		"    postman.setNextRequest(pm.info.requestName);",
+		"} else {",
+		"    pm.collectionVariables.unset(key);",
+		fmt.Sprintf("    pm.test(%s, () => pm.expect.fail(%s));", message, message),
+		"}",
+	}
+}
+
+// jsCondition renders an assertion as a JavaScript boolean expression.
+func jsCondition(c Condition) string {
+	field, _ := json.Marshal(c.Field)
+	get := fmt.Sprintf("_get(body, %s)", field)
+	value, err := json.Marshal(c.Value)
+	if err != nil {
+		value = []byte("null")
+	}
+	switch c.Op {
+	case "eq":
+		return fmt.Sprintf("_.isEqual(%s, %s)", get, value)
+	case "neq":
+		return fmt.Sprintf("!_.isEqual(%s, %s)", get, value)
+	case "exists":
+		return fmt.Sprintf("%s != null", get)
+	case "not_exists":
+		return fmt.Sprintf("%s == null", get)
+	case "contains":
+		return fmt.Sprintf("String(%s ?? '').includes(%s)", get, value)
+	case "gt", "gte", "lt", "lte":
+		ops := map[string]string{"gt": ">", "gte": ">=", "lt": "<", "lte": "<="}
+		return fmt.Sprintf("(%s != null && %s %s %s)", get, get, ops[c.Op], value)
+	}
+	return "false"
+}
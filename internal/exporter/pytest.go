@@ -2,8 +2,13 @@ package exporter
 
 import (
 	"fmt"
+	"maps"
 	"os"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"unicode"
 )
 
 type PytestExporter struct{}
@@ -15,10 +20,25 @@ func (e *PytestExporter) FileExtension() string {
 func (e *PytestExporter) Export(req ExportRequest) error {
 	var script strings.Builder
 
+	hasPoll := false
+	for _, test := range req.Tests {
+		if test.Poll != nil {
+			hasPoll = true
+			break
+		}
+	}
+
 	script.WriteString("import os\n")
+	if hasPoll {
+		script.WriteString("import time\n")
+	}
 	script.WriteString("import requests\n")
 	script.WriteString("import pytest\n\n")
-	fmt.Fprintf(&script, "BASE_URL = \"%s\"\n\n", req.BaseURL)
+	fmt.Fprintf(&script, "BASE_URL = %s\n\n", pyLiteral(req.BaseURL))
+
+	if hasPoll {
+		script.WriteString(pytestPollHelpers)
+	}
 
 	if req.AuthType != "" {
 		script.WriteString("# Set credentials via environment variables before running\n")
@@ -27,7 +47,7 @@ func (e *PytestExporter) Export(req ExportRequest) error {
 			script.WriteString("AUTH_TOKEN = os.environ[\"AUTH_TOKEN\"]\n")
 		case "apikey":
 			if keyName, ok := req.AuthData["key_name"]; ok {
-				fmt.Fprintf(&script, "API_KEY_NAME = \"%s\"\n", keyName)
+				fmt.Fprintf(&script, "API_KEY_NAME = %s\n", pyLiteral(keyName))
 				script.WriteString("API_KEY_VALUE = os.environ[\"API_KEY_VALUE\"]\n")
 			}
 		case "basic":
@@ -41,21 +61,18 @@ func (e *PytestExporter) Export(req ExportRequest) error {
 		funcName := e.buildFunctionName(test, i)
 		fmt.Fprintf(&script, "def %s():\n", funcName)
 		fmt.Fprintf(&script, "    \"\"\"%s %s\"\"\"\n", test.Method, test.Endpoint)
-		fmt.Fprintf(&script, "    url = BASE_URL + \"%s\"\n", test.Endpoint)
+		fmt.Fprintf(&script, "    url = BASE_URL + %s\n", pyLiteral(test.Endpoint))
 
-		hasBody := test.Body != nil
-		if bodyStr, ok := test.Body.(string); ok && bodyStr == "" {
-			hasBody = false
-		}
+		body, hasBody := bodyString(test.Body)
+		hasBody = hasBody && body != ""
+		var headers []string
 		if hasBody {
-			script.WriteString("    headers = {\"Content-Type\": \"application/json\"")
-		} else {
-			script.WriteString("    headers = {")
+			headers = append(headers, `"Content-Type": "application/json"`)
 		}
-		for key, value := range test.Headers {
-			fmt.Fprintf(&script, "\"%s\": \"%s\", ", key, value)
+		for _, key := range slices.Sorted(maps.Keys(test.Headers)) {
+			headers = append(headers, pyLiteral(key)+": "+pyLiteral(test.Headers[key]))
 		}
-		script.WriteString("}\n")
+		fmt.Fprintf(&script, "    headers = {%s}\n", strings.Join(headers, ", "))
 
 		if test.RequiresAuth && req.AuthType != "" {
 			switch req.AuthType {
@@ -73,16 +90,16 @@ func (e *PytestExporter) Export(req ExportRequest) error {
 		}
 
 		method := strings.ToLower(test.Method)
-		if test.Body != nil {
-			if bodyStr, ok := test.Body.(string); ok {
-				escapedBody := strings.ReplaceAll(bodyStr, "\"", "\\\"")
-				fmt.Fprintf(&script, "    data = \"\"\"%s\"\"\"\n", escapedBody)
-				fmt.Fprintf(&script, "    response = requests.%s(url, headers=headers, data=data, auth=auth)\n", method)
-			} else {
-				fmt.Fprintf(&script, "    response = requests.%s(url, headers=headers, auth=auth)\n", method)
-			}
+		call := fmt.Sprintf("response = requests.%s(url, headers=headers, auth=auth)", method)
+		if hasBody {
+			fmt.Fprintf(&script, "    data = %s\n", pyLiteral(body))
+			call = fmt.Sprintf("response = requests.%s(url, headers=headers, data=data, auth=auth)", method)
+		}
+
+		if test.Poll != nil {
+			e.writePollLoop(&script, call, test.Poll)
 		} else {
-			fmt.Fprintf(&script, "    response = requests.%s(url, headers=headers, auth=auth)\n", method)
+			fmt.Fprintf(&script, "    %s\n", call)
 		}
 
 		script.WriteString("\n")
@@ -108,10 +125,18 @@ func (e *PytestExporter) Export(req ExportRequest) error {
 }
 
 func (e *PytestExporter) buildFunctionName(test TestData, index int) string {
+	if name := snakeCase(test.Name); name != "" {
+		return "test_" + name
+	}
 	method := strings.ToLower(test.Method)
-	endpoint := strings.ReplaceAll(test.Endpoint, "/", "_")
-	endpoint = strings.ReplaceAll(endpoint, "{", "")
+	endpoint := strings.ReplaceAll(test.Endpoint, "{", "")
 	endpoint = strings.ReplaceAll(endpoint, "}", "")
+	endpoint = strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return r
+		}
+		return '_'
+	}, endpoint)
 	endpoint = strings.Trim(endpoint, "_")
 
 	if endpoint == "" {
@@ -120,3 +145,129 @@ func (e *PytestExporter) buildFunctionName(test TestData, index int) string {
 
 	return fmt.Sprintf("test_%s_%s", method, endpoint)
 }
+
+// snakeCase turns an operation name such as getUserByID into get_user_by_id.
+func snakeCase(name string) string {
+	var b strings.Builder
+	var prev rune
+	for _, r := range name {
+		switch {
+		case unicode.IsUpper(r):
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		case prev != '_' && b.Len() > 0:
+			r = '_'
+			b.WriteByte('_')
+		default:
+			r = '_'
+		}
+		prev = r
+	}
+	return strings.Trim(b.String(), "_")
+}
+
+const pytestPollHelpers = `def _json(response):
+    try:
+        return response.json()
+    except ValueError:
+        return None
+
+
+def _get(data, path):
+    for key in path.split("."):
+        if isinstance(data, list) and key.isdigit() and int(key) < len(data):
+            data = data[int(key)]
+        elif isinstance(data, dict) and key in data:
+            data = data[key]
+        else:
+            return None
+    return data
+
+
+`
+
+// writePollLoop repeats the request until the poll conditions hold.
+func (e *PytestExporter) writePollLoop(script *strings.Builder, call string, poll *PollData) {
+	var conds []string
+	if poll.UntilStatus > 0 {
+		conds = append(conds, fmt.Sprintf("response.status_code == %d", poll.UntilStatus))
+	}
+	for _, c := range poll.Until {
+		conds = append(conds, pytestCondition(c))
+	}
+	if len(conds) == 0 {
+		conds = append(conds, "response.ok")
+	}
+
+	fmt.Fprintf(script, "    deadline = time.monotonic() + %g\n", poll.timeout())
+	script.WriteString("    while True:\n")
+	fmt.Fprintf(script, "        %s\n", call)
+	script.WriteString("        body = _json(response)\n")
+	fmt.Fprintf(script, "        if %s:\n", strings.Join(conds, " and "))
+	script.WriteString("            break\n")
+	fmt.Fprintf(script, "        assert time.monotonic() < deadline, %s\n", pyLiteral(poll.message()))
+	fmt.Fprintf(script, "        time.sleep(%g)\n", poll.interval())
+}
+
+// pytestCondition renders an assertion as a Python boolean expression.
+func pytestCondition(c Condition) string {
+	get := fmt.Sprintf("_get(body, %s)", pyLiteral(c.Field))
+	value := pyLiteral(c.Value)
+	switch c.Op {
+	case "eq":
+		return fmt.Sprintf("%s == %s", get, value)
+	case "neq":
+		return fmt.Sprintf("%s != %s", get, value)
+	case "exists":
+		return fmt.Sprintf("%s is not None", get)
+	case "not_exists":
+		return fmt.Sprintf("%s is None", get)
+	case "contains":
+		return fmt.Sprintf("%s in str(%s or \"\")", value, get)
+	case "gt", "gte", "lt", "lte":
+		ops := map[string]string{"gt": ">", "gte": ">=", "lt": "<", "lte": "<="}
+		return fmt.Sprintf("(%s is not None and %s %s %s)", get, get, ops[c.Op], value)
+	}
+	return "False"
+}
+
+// pyLiteral renders a JSON value as a Python literal.
+func pyLiteral(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "None"
+	case bool:
+		if val {
+			return "True"
+		}
+		return "False"
+	case string:
+		return strconv.Quote(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(val)
+	case []any:
+		items := make([]string, len(val))
+		for i, item := range val {
+			items[i] = pyLiteral(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case map[string]any:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		items := make([]string, len(keys))
+		for i, k := range keys {
+			items[i] = strconv.Quote(k) + ": " + pyLiteral(val[k])
+		}
+		return "{" + strings.Join(items, ", ") + "}"
+	}
+	return strconv.Quote(fmt.Sprint(v))
+}
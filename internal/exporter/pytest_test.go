@@ -0,0 +1,99 @@
+package exporter
+
+import (
+	"maps"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Octrafic/octrafic-cli/internal/core/parser"
+)
+
+func TestPytestExporter_RoundTrip(t *testing.T) {
+	tests := []TestData{
+		{Name: "createUser", Method: "POST", Endpoint: "/users?notify=true", RequiresAuth: true, StatusCode: 201,
+			Headers: map[string]string{"X-Request-Id": "it's 1", "Accept": "application/json"},
+			Body:    `{"name": "Ada Lovelace", "bio": "Says \"hi\" \\ bye"}`},
+		{Method: "GET", Endpoint: "/users/{id}/export-v2", RequiresAuth: true, StatusCode: 200},
+		{Name: "updateUser", Method: "PATCH", Endpoint: "/users/42", Body: map[string]any{"name": "Grace"}, StatusCode: 200},
+		{Name: "waitForExport", Method: "GET", Endpoint: "/exports/7", StatusCode: 200, Poll: &PollData{
+			UntilStatus: 200, IntervalSeconds: 0.5, TimeoutSeconds: 30, Message: "export never finished",
+			Until: []Condition{
+				{Field: "status", Op: "eq", Value: "done"},
+				{Field: "items.0.id", Op: "exists"},
+				{Field: "size", Op: "gte", Value: float64(10)},
+				{Field: "log", Op: "contains", Value: "ok"},
+			},
+		}},
+	}
+	for _, auth := range []string{"bearer", "basic", "apikey"} {
+		t.Run(auth, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "test_api.py")
+			err := (&PytestExporter{}).Export(ExportRequest{
+				BaseURL:  "https://api.example.com/v1",
+				Tests:    tests,
+				FilePath: path,
+				AuthType: auth,
+				AuthData: map[string]string{"key_name": "X-Api-Key"},
+			})
+			if err != nil {
+				t.Fatalf("Export failed: %v", err)
+			}
+			file, err := parser.ReadPytestFile(path)
+			if err != nil {
+				t.Fatalf("ReadPytestFile failed: %v", err)
+			}
+			if file.BaseURL != "https://api.example.com/v1" || len(file.Tests) != len(tests) {
+				t.Fatalf("expected %d tests against the base URL, got %s %d", len(tests), file.BaseURL, len(file.Tests))
+			}
+			if len(file.Report) != 0 {
+				t.Errorf("expected nothing to report, got %q", file.Report)
+			}
+			if auth == "apikey" && file.APIKeyName != "X-Api-Key" {
+				t.Errorf("expected API key header name, got %q", file.APIKeyName)
+			}
+
+			for i, test := range tests {
+				got := file.Tests[i]
+				if got.Method != test.Method || got.Path != test.Endpoint || got.ExpectedStatus != test.StatusCode {
+					t.Errorf("test %d: expected %s %s %d, got %s %s %d", i, test.Method, test.Endpoint, test.StatusCode, got.Method, got.Path, got.ExpectedStatus)
+				}
+				wantAuth := ""
+				if test.RequiresAuth {
+					wantAuth = auth
+				}
+				if got.AuthType != wantAuth {
+					t.Errorf("test %d: expected auth %q, got %q", i, wantAuth, got.AuthType)
+				}
+				want, _ := bodyString(test.Body)
+				if got.Body != want {
+					t.Errorf("test %d: expected body %q, got %q", i, want, got.Body)
+				}
+				wantHeaders := maps.Clone(test.Headers)
+				if wantHeaders == nil {
+					wantHeaders = map[string]string{}
+				}
+				if want != "" {
+					wantHeaders["Content-Type"] = "application/json"
+				}
+				if !maps.Equal(got.Headers, wantHeaders) {
+					t.Errorf("test %d: expected headers %v, got %v", i, wantHeaders, got.Headers)
+				}
+			}
+
+			poll := file.Tests[3].Poll
+			want := &parser.PytestPoll{
+				UntilStatus: 200, IntervalSeconds: 0.5, TimeoutSeconds: 30, Message: "export never finished",
+				Until: []parser.PytestCondition{
+					{Field: "status", Op: "eq", Value: "done"},
+					{Field: "items.0.id", Op: "exists"},
+					{Field: "size", Op: "gte", Value: float64(10)},
+					{Field: "log", Op: "contains", Value: "ok"},
+				},
+			}
+			if !reflect.DeepEqual(poll, want) {
+				t.Errorf("expected poll %+v, got %+v", want, poll)
+			}
+		})
+	}
+}
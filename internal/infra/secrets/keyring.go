@@ -0,0 +1,116 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+const keyringService = "octrafic"
+
+// Keyring stores secrets in the OS keyring: the login keychain on macOS
+// (security) and the Secret Service on Linux (secret-tool).
+type Keyring struct {
+	service string
+}
+
+// NewKeyring returns the OS keyring backend.
+func NewKeyring() *Keyring {
+	return &Keyring{service: keyringService}
+}
+
+// KeyringAvailable reports whether the OS keyring can be used.
+func KeyringAvailable() bool {
+	switch runtime.GOOS {
+	case "darwin":
+		_, err := exec.LookPath("security")
+		return err == nil
+	case "linux", "freebsd", "openbsd":
+		if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+			return false
+		}
+		_, err := exec.LookPath("secret-tool")
+		return err == nil
+	default:
+		return false
+	}
+}
+
+// Name returns the backend name.
+func (k *Keyring) Name() string {
+	return "OS keyring"
+}
+
+// Get returns the secret stored under a key.
+func (k *Keyring) Get(key string) (string, error) {
+	var out string
+	var err error
+	if runtime.GOOS == "darwin" {
+		out, err = runKeyringTool("", "security", "find-generic-password", "-s", k.service, "-a", key, "-w")
+		if exitCode(err) == 44 {
+			return "", ErrNotFound
+		}
+	} else {
+		out, err = runKeyringTool("", "secret-tool", "lookup", "service", k.service, "key", key)
+		if exitCode(err) == 1 && out == "" {
+			return "", ErrNotFound
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(out, "\n"), nil
+}
+
+// Set stores a secret under a key, replacing an existing one.
+func (k *Keyring) Set(key, value string) error {
+	if runtime.GOOS == "darwin" {
+		// Pass the value on stdin so it does not show up in the process list.
+		cmd := fmt.Sprintf("add-generic-password -U -s %q -a %q -X %q\n", k.service, key, hex.EncodeToString([]byte(value)))
+		_, err := runKeyringTool(cmd, "security", "-i")
+		return err
+	}
+	_, err := runKeyringTool(value, "secret-tool", "store", "--label", "Octrafic: "+key, "service", k.service, "key", key)
+	return err
+}
+
+// Delete removes the secret stored under a key.
+func (k *Keyring) Delete(key string) error {
+	if runtime.GOOS == "darwin" {
+		_, err := runKeyringTool("", "security", "delete-generic-password", "-s", k.service, "-a", key)
+		if exitCode(err) == 44 {
+			return ErrNotFound
+		}
+		return err
+	}
+	_, err := runKeyringTool("", "secret-tool", "clear", "service", k.service, "key", key)
+	return err
+}
+
+func runKeyringTool(stdin, name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return stdout.String(), fmt.Errorf("%s: %s: %w", name, msg, err)
+		}
+		return stdout.String(), fmt.Errorf("%s: %w", name, err)
+	}
+	return stdout.String(), nil
+}
+
+func exitCode(err error) int {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return 0
+}
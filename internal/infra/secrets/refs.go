@@ -0,0 +1,186 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// External references point to credentials kept outside octrafic. They are
+// saved as written and resolved each time the value is needed:
+//
+//	env:STAGING_TOKEN         environment variable
+//	file:/run/secrets/token   file contents, without the trailing newline
+//	cmd:pass show api/staging standard output of a shell command
+const (
+	envPrefix  = "env:"
+	filePrefix = "file:"
+	cmdPrefix  = "cmd:"
+)
+
+// Command output is reused for a while so that a password manager is not
+// asked on every request.
+const (
+	cmdCacheTTL = 5 * time.Minute
+	cmdTimeout  = 30 * time.Second
+)
+
+type cmdResult struct {
+	value   string
+	expires time.Time
+}
+
+var (
+	cmdMu    sync.Mutex
+	cmdCache = map[string]cmdResult{}
+
+	// resolved maps the values handed out by Resolve to their references,
+	// so Scrub can hide them again.
+	resolvedMu sync.Mutex
+	resolved   = map[string]string{}
+)
+
+// minScrubLength keeps very short values (e.g. "1") from being replaced
+// everywhere they happen to occur.
+const minScrubLength = 4
+
+// IsReference reports whether a value is a reference of any kind rather
+// than a literal credential.
+func IsReference(value string) bool {
+	return IsRef(value) || strings.HasPrefix(value, envPrefix) || strings.HasPrefix(value, filePrefix) || strings.HasPrefix(value, cmdPrefix)
+}
+
+func resolveExternal(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, envPrefix):
+		name := strings.TrimPrefix(value, envPrefix)
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		return v, nil
+	case strings.HasPrefix(value, filePrefix):
+		path := strings.TrimPrefix(value, filePrefix)
+		if rest, ok := strings.CutPrefix(path, "~/"); ok {
+			if homeDir, err := os.UserHomeDir(); err == nil {
+				path = filepath.Join(homeDir, rest)
+			}
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret file: %w", err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	default:
+		return runSecretCommand(strings.TrimPrefix(value, cmdPrefix))
+	}
+}
+
+func runSecretCommand(command string) (string, error) {
+	cmdMu.Lock()
+	defer cmdMu.Unlock()
+	if cached, ok := cmdCache[command]; ok && time.Now().Before(cached.expires) {
+		return cached.value, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cmdTimeout)
+	defer cancel()
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("secret command %q failed: %s: %w", command, msg, err)
+		}
+		return "", fmt.Errorf("secret command %q failed: %w", command, err)
+	}
+	value := strings.TrimRight(stdout.String(), "\r\n")
+	if value == "" {
+		return "", fmt.Errorf("secret command %q printed nothing", command)
+	}
+	cmdCache[command] = cmdResult{value: value, expires: time.Now().Add(cmdCacheTTL)}
+	return value, nil
+}
+
+func remember(value, ref string) {
+	if len(value) < minScrubLength {
+		return
+	}
+	resolvedMu.Lock()
+	defer resolvedMu.Unlock()
+	resolved[value] = ref
+}
+
+// Scrub replaces every credential resolved so far with a placeholder naming
+// its reference. It is applied to text that goes to the LLM or into saved
+// conversations.
+func Scrub(s string) string {
+	resolvedMu.Lock()
+	defer resolvedMu.Unlock()
+	if len(resolved) == 0 || s == "" {
+		return s
+	}
+	// Longest first, so a value containing another is replaced whole.
+	values := slices.SortedFunc(maps.Keys(resolved), func(a, b string) int { return len(b) - len(a) })
+	for _, v := range values {
+		if strings.Contains(s, v) {
+			s = strings.ReplaceAll(s, v, "[redacted "+resolved[v]+"]")
+		}
+	}
+	return s
+}
+
+// ScrubValue returns a copy of a decoded JSON-like value with Scrub applied
+// to every string in it. Values of other types are returned as they are.
+func ScrubValue(v any) any {
+	switch val := v.(type) {
+	case string:
+		return Scrub(val)
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, item := range val {
+			out[k] = ScrubValue(item)
+		}
+		return out
+	case map[string]string:
+		out := make(map[string]string, len(val))
+		for k, item := range val {
+			out[k] = Scrub(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = ScrubValue(item)
+		}
+		return out
+	case []map[string]any:
+		out := make([]map[string]any, len(val))
+		for i, item := range val {
+			out[i] = ScrubValue(item).(map[string]any)
+		}
+		return out
+	case []string:
+		out := make([]string, len(val))
+		for i, item := range val {
+			out[i] = Scrub(item)
+		}
+		return out
+	}
+	return v
+}
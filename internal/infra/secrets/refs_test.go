@@ -0,0 +1,87 @@
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveExternalReferences(t *testing.T) {
+	t.Setenv("OCTRAFIC_TEST_TOKEN", "env-token-value")
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("file-token-value\n"), 0600); err != nil {
+		t.Fatalf("failed to write token file: %v", err)
+	}
+
+	tests := map[string]string{
+		"env:OCTRAFIC_TEST_TOKEN":   "env-token-value",
+		"file:" + path:              "file-token-value",
+		"cmd:echo cmd-token-value":  "cmd-token-value",
+		"plain-literal-token-value": "plain-literal-token-value",
+	}
+	for ref, want := range tests {
+		got, err := Resolve(ref)
+		if err != nil {
+			t.Errorf("Resolve(%q) failed: %v", ref, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("Resolve(%q) = %q, want %q", ref, got, want)
+		}
+	}
+
+	if _, err := Resolve("env:OCTRAFIC_TEST_UNSET"); err == nil {
+		t.Error("expected an error for an unset variable")
+	}
+	if _, err := Resolve("cmd:exit 3"); err == nil {
+		t.Error("expected an error for a failing command")
+	}
+	if ref, _ := Put("projects/1/auth/token", "env:OCTRAFIC_TEST_TOKEN"); ref != "env:OCTRAFIC_TEST_TOKEN" {
+		t.Errorf("external references should be stored unchanged, got %q", ref)
+	}
+}
+
+func TestSecretCommandIsCached(t *testing.T) {
+	counter := filepath.Join(t.TempDir(), "count")
+	ref := "cmd:echo x >> " + counter + "; echo cached-secret"
+	for range 2 {
+		if got, err := Resolve(ref); err != nil || got != "cached-secret" {
+			t.Fatalf("Resolve = %q, %v", got, err)
+		}
+	}
+	data, err := os.ReadFile(counter)
+	if err != nil {
+		t.Fatalf("failed to read counter: %v", err)
+	}
+	if runs := strings.Count(string(data), "x"); runs != 1 {
+		t.Errorf("expected the command to run once, ran %d times", runs)
+	}
+}
+
+func TestScrub(t *testing.T) {
+	t.Setenv("OCTRAFIC_TEST_SCRUB", "scrub-me-please")
+	if _, err := Resolve("env:OCTRAFIC_TEST_SCRUB"); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+
+	got := Scrub(`{"echo":"Bearer scrub-me-please"}`)
+	if strings.Contains(got, "scrub-me-please") || !strings.Contains(got, "[redacted env:OCTRAFIC_TEST_SCRUB]") {
+		t.Errorf("Scrub = %q", got)
+	}
+
+	value := map[string]any{
+		"headers": map[string]string{"Authorization": "Bearer scrub-me-please"},
+		"items":   []any{"scrub-me-please", 42},
+	}
+	scrubbed := ScrubValue(value).(map[string]any)
+	if scrubbed["headers"].(map[string]string)["Authorization"] == "Bearer scrub-me-please" {
+		t.Error("nested map was not scrubbed")
+	}
+	if scrubbed["items"].([]any)[0] == "scrub-me-please" || scrubbed["items"].([]any)[1] != 42 {
+		t.Errorf("unexpected items %v", scrubbed["items"])
+	}
+	if value["headers"].(map[string]string)["Authorization"] != "Bearer scrub-me-please" {
+		t.Error("ScrubValue modified its input")
+	}
+}
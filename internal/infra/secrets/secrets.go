@@ -0,0 +1,151 @@
+// Package secrets keeps credentials out of the project and config files.
+// Files store references of the form "secret:<key>" and the values live in
+// the OS keyring or, where there is none, in a passphrase-encrypted vault.
+// Credentials kept elsewhere can be referenced with env:, file: and cmd:.
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Backend stores secret values under keys.
+type Backend interface {
+	Name() string
+	Get(key string) (string, error)
+	Set(key, value string) error
+	Delete(key string) error
+}
+
+var (
+	ErrNotFound = errors.New("secret not found")
+	ErrLocked   = errors.New("secret vault is locked: run octrafic in a terminal to enter the passphrase, or set " + PassphraseEnv)
+	ErrDisabled = errors.New("secret storage is disabled (" + BackendEnv + "=none)")
+)
+
+const (
+	// BackendEnv selects the backend: keyring, file or none. By default the
+	// keyring is used when available and the vault file otherwise.
+	BackendEnv = "OCTRAFIC_SECRETS_BACKEND"
+	// PassphraseEnv unlocks the vault without a prompt, e.g. in CI.
+	PassphraseEnv = "OCTRAFIC_SECRETS_PASSPHRASE"
+
+	refPrefix = "secret:"
+	vaultFile = "secrets.vault"
+)
+
+var (
+	defaultOnce    sync.Once
+	defaultBackend Backend
+)
+
+// Default returns the backend selected for this machine, or nil when secret
+// storage is disabled.
+func Default() Backend {
+	defaultOnce.Do(func() {
+		defaultBackend = selectBackend(os.Getenv(BackendEnv))
+	})
+	return defaultBackend
+}
+
+// SetDefault replaces the default backend.
+func SetDefault(b Backend) {
+	defaultOnce.Do(func() {})
+	defaultBackend = b
+}
+
+func selectBackend(name string) Backend {
+	switch strings.ToLower(name) {
+	case "none":
+		return nil
+	case "keyring":
+		return NewKeyring()
+	case "file":
+	default:
+		if KeyringAvailable() {
+			return NewKeyring()
+		}
+	}
+
+	path := vaultFile
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		path = filepath.Join(homeDir, ".octrafic", vaultFile)
+	}
+	vault := NewVault(path)
+	if passphrase := os.Getenv(PassphraseEnv); passphrase != "" {
+		// A wrong passphrase leaves the vault locked; lookups then fail.
+		_ = vault.Unlock(passphrase)
+	}
+	return vault
+}
+
+// IsRef reports whether a stored value is a reference to a secret.
+func IsRef(value string) bool {
+	return strings.HasPrefix(value, refPrefix)
+}
+
+// Ref returns the reference stored in place of the secret with a key.
+func Ref(key string) string {
+	return refPrefix + key
+}
+
+// Key returns the key of a reference.
+func Key(ref string) string {
+	return strings.TrimPrefix(ref, refPrefix)
+}
+
+// Resolve returns the value a reference points to: a secret store entry or
+// an env:, file: or cmd: reference. Other values are returned unchanged.
+func Resolve(value string) (string, error) {
+	if !IsReference(value) {
+		return value, nil
+	}
+	var secret string
+	if IsRef(value) {
+		b := Default()
+		if b == nil {
+			return "", ErrDisabled
+		}
+		var err error
+		if secret, err = b.Get(Key(value)); err != nil {
+			return "", fmt.Errorf("failed to read secret %s: %w", Key(value), err)
+		}
+	} else {
+		var err error
+		if secret, err = resolveExternal(value); err != nil {
+			return "", err
+		}
+	}
+	remember(secret, value)
+	return secret, nil
+}
+
+// Put stores a plain-text value under a key and returns the reference to
+// save instead. Empty values and references of any kind are returned
+// unchanged.
+func Put(key, value string) (string, error) {
+	if value == "" || IsReference(value) {
+		return value, nil
+	}
+	b := Default()
+	if b == nil {
+		return "", ErrDisabled
+	}
+	if err := b.Set(key, value); err != nil {
+		return "", fmt.Errorf("failed to store secret %s: %w", key, err)
+	}
+	return Ref(key), nil
+}
+
+// Delete removes the secret a reference points to.
+func Delete(ref string) error {
+	b := Default()
+	if b == nil {
+		return ErrDisabled
+	}
+	return b.Delete(Key(ref))
+}
@@ -0,0 +1,253 @@
+package secrets
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/scrypt"
+)
+
+// ErrWrongPassphrase is returned when the vault cannot be decrypted.
+var ErrWrongPassphrase = errors.New("wrong passphrase")
+
+// scrypt work factor, as used by age for passphrase-encrypted files.
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// vaultFileFormat is the on-disk form of a vault. The secrets are a JSON
+// object sealed with XChaCha20-Poly1305 under a key derived from the
+// passphrase with scrypt.
+type vaultFileFormat struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// Vault stores secrets in a passphrase-encrypted file. It must be unlocked
+// before use.
+type Vault struct {
+	path string
+
+	mu         sync.Mutex
+	passphrase string
+	entries    map[string]string // nil while locked
+}
+
+// NewVault returns a locked vault backed by the file at path.
+func NewVault(path string) *Vault {
+	return &Vault{path: path}
+}
+
+// Name returns the backend name.
+func (v *Vault) Name() string {
+	return "vault " + v.path
+}
+
+// Path returns the vault file path.
+func (v *Vault) Path() string {
+	return v.path
+}
+
+// Exists reports whether the vault file has been created.
+func (v *Vault) Exists() bool {
+	_, err := os.Stat(v.path)
+	return err == nil
+}
+
+// Locked reports whether the vault still needs its passphrase.
+func (v *Vault) Locked() bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.entries == nil
+}
+
+// Unlock decrypts the vault. If the file does not exist yet, the passphrase
+// is used to create it on the first write.
+func (v *Vault) Unlock(passphrase string) error {
+	if passphrase == "" {
+		return fmt.Errorf("passphrase is required")
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	entries := map[string]string{}
+	data, err := os.ReadFile(v.path)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return fmt.Errorf("failed to read vault: %w", err)
+	default:
+		if entries, err = decryptVault(data, passphrase); err != nil {
+			return err
+		}
+	}
+	v.passphrase = passphrase
+	v.entries = entries
+	return nil
+}
+
+// ChangePassphrase re-encrypts the vault with a new passphrase.
+func (v *Vault) ChangePassphrase(passphrase string) error {
+	if passphrase == "" {
+		return fmt.Errorf("passphrase is required")
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.entries == nil {
+		return ErrLocked
+	}
+	old := v.passphrase
+	v.passphrase = passphrase
+	if err := v.save(); err != nil {
+		v.passphrase = old
+		return err
+	}
+	return nil
+}
+
+// Keys returns the keys of all stored secrets in sorted order.
+func (v *Vault) Keys() ([]string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.entries == nil {
+		return nil, ErrLocked
+	}
+	return slices.Sorted(maps.Keys(v.entries)), nil
+}
+
+// Get returns the secret stored under a key.
+func (v *Vault) Get(key string) (string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.entries == nil {
+		return "", ErrLocked
+	}
+	value, ok := v.entries[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+// Set stores a secret under a key and rewrites the vault file.
+func (v *Vault) Set(key, value string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.entries == nil {
+		return ErrLocked
+	}
+	if current, ok := v.entries[key]; ok && current == value {
+		return nil
+	}
+	v.entries[key] = value
+	return v.save()
+}
+
+// Delete removes the secret stored under a key.
+func (v *Vault) Delete(key string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.entries == nil {
+		return ErrLocked
+	}
+	if _, ok := v.entries[key]; !ok {
+		return ErrNotFound
+	}
+	delete(v.entries, key)
+	return v.save()
+}
+
+// save encrypts the entries and replaces the file atomically.
+func (v *Vault) save() error {
+	data, err := encryptVault(v.entries, v.passphrase)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(v.path), 0700); err != nil {
+		return fmt.Errorf("failed to create vault directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(v.path), ".secrets-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write vault: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write vault: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write vault: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), v.path); err != nil {
+		return fmt.Errorf("failed to write vault: %w", err)
+	}
+	return nil
+}
+
+func encryptVault(entries map[string]string, passphrase string) ([]byte, error) {
+	plaintext, err := json.Marshal(entries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal secrets: %w", err)
+	}
+	f := vaultFileFormat{Version: 1, KDF: "scrypt", N: scryptN, R: scryptR, P: scryptP, Salt: make([]byte, 16), Nonce: make([]byte, chacha20poly1305.NonceSizeX)}
+	if _, err := rand.Read(f.Salt); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(f.Nonce); err != nil {
+		return nil, err
+	}
+	aead, err := vaultCipher(passphrase, f)
+	if err != nil {
+		return nil, err
+	}
+	f.Ciphertext = aead.Seal(nil, f.Nonce, plaintext, nil)
+	return json.MarshalIndent(f, "", "  ")
+}
+
+func decryptVault(data []byte, passphrase string) (map[string]string, error) {
+	var f vaultFileFormat
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse vault: %w", err)
+	}
+	if f.Version != 1 || f.KDF != "scrypt" {
+		return nil, fmt.Errorf("unsupported vault format (version %d, kdf %q)", f.Version, f.KDF)
+	}
+	aead, err := vaultCipher(passphrase, f)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, f.Nonce, f.Ciphertext, nil)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	entries := map[string]string{}
+	if err := json.Unmarshal(plaintext, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse vault: %w", err)
+	}
+	return entries, nil
+}
+
+func vaultCipher(passphrase string, f vaultFileFormat) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), f.Salt, f.N, f.R, f.P, chacha20poly1305.KeySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive vault key: %w", err)
+	}
+	return chacha20poly1305.NewX(key)
+}
@@ -0,0 +1,95 @@
+package secrets
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVault(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.vault")
+	v := NewVault(path)
+	if _, err := v.Get("a"); !errors.Is(err, ErrLocked) {
+		t.Fatalf("expected locked vault, got %v", err)
+	}
+	if err := v.Unlock("correct horse"); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+	if v.Exists() {
+		t.Error("vault file should not exist before the first write")
+	}
+	if err := v.Set("projects/1/auth/token", "tok-123"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read vault: %v", err)
+	}
+	if strings.Contains(string(data), "tok-123") || strings.Contains(string(data), "projects/1") {
+		t.Errorf("vault file leaks its contents: %s", data)
+	}
+
+	reopened := NewVault(path)
+	if err := reopened.Unlock("wrong"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("expected wrong passphrase error, got %v", err)
+	}
+	if !reopened.Locked() {
+		t.Error("vault should stay locked after a wrong passphrase")
+	}
+	if err := reopened.Unlock("correct horse"); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+	if got, err := reopened.Get("projects/1/auth/token"); err != nil || got != "tok-123" {
+		t.Errorf("Get = %q, %v", got, err)
+	}
+
+	if err := reopened.ChangePassphrase("battery staple"); err != nil {
+		t.Fatalf("ChangePassphrase failed: %v", err)
+	}
+	if err := reopened.Delete("projects/1/auth/token"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := reopened.Delete("projects/1/auth/token"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected not found, got %v", err)
+	}
+	if err := NewVault(path).Unlock("battery staple"); err != nil {
+		t.Errorf("expected the new passphrase to unlock the vault: %v", err)
+	}
+}
+
+func TestPutResolve(t *testing.T) {
+	v := NewVault(filepath.Join(t.TempDir(), "secrets.vault"))
+	if err := v.Unlock("pw"); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+	SetDefault(v)
+	defer SetDefault(nil)
+
+	ref, err := Put("llm/api_key", "sk-test")
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if ref != "secret:llm/api_key" || !IsRef(ref) {
+		t.Errorf("unexpected reference %q", ref)
+	}
+	if again, _ := Put("llm/api_key", ref); again != ref {
+		t.Errorf("references should be stored unchanged, got %q", again)
+	}
+	if value, err := Resolve(ref); err != nil || value != "sk-test" {
+		t.Errorf("Resolve = %q, %v", value, err)
+	}
+	if value, _ := Resolve("plain"); value != "plain" {
+		t.Errorf("plain values should resolve to themselves, got %q", value)
+	}
+	if _, err := Resolve("secret:missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected not found, got %v", err)
+	}
+
+	SetDefault(nil)
+	if _, err := Put("llm/api_key", "sk-test"); !errors.Is(err, ErrDisabled) {
+		t.Errorf("expected disabled store error, got %v", err)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/Octrafic/octrafic-cli/internal/infra/secrets"
 	_ "modernc.org/sqlite"
 )
 
@@ -193,7 +194,8 @@ func ListConversations(projectID string) ([]*Conversation, error) {
 	return conversations, nil
 }
 
-// SaveMessage saves a message to the conversation
+// SaveMessage saves a message to the conversation. Resolved credentials are
+// scrubbed from the content and metadata.
 func SaveMessage(projectID, conversationID, messageType, content string, metadata map[string]interface{}) error {
 	dbPath, err := GetConversationPath(projectID, conversationID)
 	if err != nil {
@@ -213,8 +215,17 @@ func SaveMessage(projectID, conversationID, messageType, content string, metadat
 			return fmt.Errorf("failed to marshal metadata: %w", err)
 		}
 		str := string(jsonBytes)
+		if secrets.Scrub(str) != str {
+			var decoded any
+			if err := json.Unmarshal(jsonBytes, &decoded); err == nil {
+				if scrubbed, err := json.Marshal(secrets.ScrubValue(decoded)); err == nil {
+					str = string(scrubbed)
+				}
+			}
+		}
 		metadataJSON = &str
 	}
+	content = secrets.Scrub(content)
 
 	query := `INSERT INTO messages (conversation_id, type, content, metadata, timestamp) 
 	          VALUES (?, ?, ?, ?, ?)`
@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const findingsFile = "findings.json"
+
+// Finding is a security result recorded for a project, e.g. whether an
+// endpoint rejected a tampered JWT.
+type Finding struct {
+	Time       time.Time `json:"time"`
+	Category   string    `json:"category"` // jwt
+	Check      string    `json:"check"`    // e.g. alg-none
+	Severity   string    `json:"severity"` // pass, info, high, critical
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Detail     string    `json:"detail"`
+}
+
+// Severe reports whether the finding is a high or critical issue.
+func (f Finding) Severe() bool {
+	return f.Severity == "high" || f.Severity == "critical"
+}
+
+// AddFindings appends findings to the project's findings.json.
+func AddFindings(projectID string, isTemporary bool, findings ...Finding) error {
+	existing, err := LoadFindings(projectID, isTemporary)
+	if err != nil {
+		return err
+	}
+	projectPath, err := GetProjectPathByType(projectID, isTemporary)
+	if err != nil {
+		return fmt.Errorf("failed to get project path: %w", err)
+	}
+	if err := os.MkdirAll(projectPath, 0755); err != nil {
+		return fmt.Errorf("failed to create project directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(append(existing, findings...), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal findings: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(projectPath, findingsFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write findings file: %w", err)
+	}
+	return nil
+}
+
+// LoadFindings reads the findings recorded for a project, oldest first.
+func LoadFindings(projectID string, isTemporary bool) ([]Finding, error) {
+	projectPath, err := GetProjectPathByType(projectID, isTemporary)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project path: %w", err)
+	}
+	data, err := os.ReadFile(filepath.Join(projectPath, findingsFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read findings file: %w", err)
+	}
+	var findings []Finding
+	if err := json.Unmarshal(data, &findings); err != nil {
+		return nil, fmt.Errorf("failed to parse findings file: %w", err)
+	}
+	return findings, nil
+}
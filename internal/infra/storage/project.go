@@ -4,15 +4,21 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/Octrafic/octrafic-cli/internal/agents"
+	"github.com/Octrafic/octrafic-cli/internal/core/auth"
 	"github.com/Octrafic/octrafic-cli/internal/core/parser"
+	"github.com/Octrafic/octrafic-cli/internal/core/tester"
+	"github.com/Octrafic/octrafic-cli/internal/infra/logger"
 )
 
 const projectsDir = "projects"
@@ -25,23 +31,49 @@ type Project struct {
 	BaseURL        string      `json:"base_url"`
 	SpecPath       string      `json:"spec_path,omitempty"`
 	SpecHash       string      `json:"spec_hash,omitempty"`
+	SpecFiles      []string    `json:"spec_files,omitempty"` // files the spec references with $ref
 	IsTemporary    bool        `json:"is_temporary"`
 	AuthConfig     *AuthConfig `json:"auth_config,omitempty"`
 	CreatedAt      time.Time   `json:"created_at"`
 	UpdatedAt      time.Time   `json:"updated_at"`
 	LastAccessedAt time.Time   `json:"last_accessed_at"`
+
+	// Identities are named credentials (e.g. admin, member, readonly) that
+	// tests can run as, in addition to AuthConfig.
+	Identities map[string]*AuthConfig `json:"identities,omitempty"`
+
+	// SchemeAuth holds credentials for the security schemes of the spec, by
+	// scheme name, for APIs that use different schemes on different endpoints.
+	SchemeAuth map[string]*AuthConfig `json:"scheme_auth,omitempty"`
 }
 
-// AuthConfig stores authentication configuration for a project
-// WARNING: Credentials are stored in plain text
+// AnonymousIdentity is the built-in identity that sends no credentials.
+const AnonymousIdentity = "anonymous"
+
+// AuthConfig stores authentication configuration for a project.
+// Credentials are saved as references to the secret store (see secretFields).
 type AuthConfig struct {
-	Type     string `json:"type"`                // none, bearer, apikey, basic
+	Type     string `json:"type"`                // none, bearer, apikey, basic, oauth2, login, sigv4, hmac
 	Token    string `json:"token,omitempty"`     // Bearer token
 	KeyName  string `json:"key_name,omitempty"`  // API key name (e.g., X-API-Key)
 	KeyValue string `json:"key_value,omitempty"` // API key value
-	Location string `json:"location,omitempty"`  // header or query
+	Location string `json:"location,omitempty"`  // header, query or cookie
 	Username string `json:"username,omitempty"`  // Basic auth username
 	Password string `json:"password,omitempty"`  // Basic auth password
+
+	GrantType    string `json:"grant_type,omitempty"`    // OAuth2: client_credentials, refresh_token or authorization_code
+	TokenURL     string `json:"token_url,omitempty"`     // OAuth2 token endpoint
+	ClientID     string `json:"client_id,omitempty"`     // OAuth2 client ID
+	ClientSecret string `json:"client_secret,omitempty"` // OAuth2 client secret
+	RefreshToken string `json:"refresh_token,omitempty"` // OAuth2 refresh token, updated when rotated or after sign-in
+	Scopes       string `json:"scopes,omitempty"`        // OAuth2 scopes, space-separated
+	Audience     string `json:"audience,omitempty"`      // OAuth2 audience
+	AuthorizeURL string `json:"authorize_url,omitempty"` // OAuth2 authorization endpoint (authorization_code)
+	RedirectURL  string `json:"redirect_url,omitempty"`  // OAuth2 loopback redirect URL (authorization_code)
+
+	Login *tester.LoginFlow `json:"login,omitempty"` // Login request and token rule (login)
+	SigV4 *auth.SigV4Auth   `json:"sigv4,omitempty"` // AWS Signature V4 credentials (sigv4)
+	HMAC  *auth.HMACAuth    `json:"hmac,omitempty"`  // HMAC signing scheme (hmac)
 }
 
 // ClearAuth removes authentication configuration from project
@@ -54,6 +86,53 @@ func (p *Project) HasAuth() bool {
 	return p.AuthConfig != nil && p.AuthConfig.Type != "" && p.AuthConfig.Type != "none"
 }
 
+// ValidateIdentityName checks that an identity name is usable in tests and
+// on the command line.
+func ValidateIdentityName(name string) error {
+	if !suiteNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid identity name %q: use letters, digits, '.', '_' and '-'", name)
+	}
+	if name == AnonymousIdentity {
+		return fmt.Errorf("identity name %q is reserved for unauthenticated requests", name)
+	}
+	return nil
+}
+
+// SetIdentity saves authentication under a name, replacing an identity with
+// the same name.
+func (p *Project) SetIdentity(name string, cfg *AuthConfig) error {
+	if err := ValidateIdentityName(name); err != nil {
+		return err
+	}
+	if p.Identities == nil {
+		p.Identities = make(map[string]*AuthConfig)
+	}
+	p.Identities[name] = cfg
+	return nil
+}
+
+// RemoveIdentity deletes a named identity and reports whether it existed.
+func (p *Project) RemoveIdentity(name string) bool {
+	if _, ok := p.Identities[name]; !ok {
+		return false
+	}
+	delete(p.Identities, name)
+	return true
+}
+
+// SetSchemeAuth saves the credentials for a security scheme of the spec.
+func (p *Project) SetSchemeAuth(scheme string, cfg *AuthConfig) {
+	if p.SchemeAuth == nil {
+		p.SchemeAuth = make(map[string]*AuthConfig)
+	}
+	p.SchemeAuth[scheme] = cfg
+}
+
+// IdentityNames returns the names of the saved identities in sorted order.
+func (p *Project) IdentityNames() []string {
+	return slices.Sorted(maps.Keys(p.Identities))
+}
+
 // ProjectContext encapsulates project data with pre-computed paths
 type ProjectContext struct {
 	Project       *Project
@@ -146,13 +225,35 @@ func SaveProject(project *Project) error {
 
 	project.UpdatedAt = time.Now()
 
-	filePath := filepath.Join(projectPath, "project.json")
+	// Credentials go to the secret store; if it is locked or disabled they
+	// stay in the file, which only the owner can read.
+	if err := project.sealSecrets(); err != nil {
+		logger.Warn("Credentials kept in project file", logger.String("project", project.Name), logger.Err(err))
+	}
+
+	return writeProjectFile(filepath.Join(projectPath, "project.json"), project)
+}
+
+// writeProject writes project.json without touching UpdatedAt.
+func writeProject(project *Project) error {
+	projectPath, err := GetProjectPathByType(project.ID, project.IsTemporary)
+	if err != nil {
+		return err
+	}
+	return writeProjectFile(filepath.Join(projectPath, "project.json"), project)
+}
+
+func writeProjectFile(filePath string, project *Project) error {
 	data, err := json.MarshalIndent(project, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal project: %w", err)
 	}
 
-	if err := os.WriteFile(filePath, data, 0644); err != nil {
+	if err := os.WriteFile(filePath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write project file: %w", err)
+	}
+	// Files written by older versions were world-readable.
+	if err := os.Chmod(filePath, 0600); err != nil {
 		return fmt.Errorf("failed to write project file: %w", err)
 	}
 
@@ -248,54 +349,72 @@ func ComputeFileHash(filePath string) (string, error) {
 	return hex.EncodeToString(hash.Sum(nil)), nil
 }
 
-// LoadOrParseSpec parses the spec file and returns endpoints with hash
+// ComputeSpecHash computes the hash of a spec together with the files it
+// references, so editing any of them changes it. Without referenced files it
+// is the hash of the spec file.
+func ComputeSpecHash(specPath string, files []string) (string, error) {
+	specHash, err := ComputeFileHash(specPath)
+	if err != nil || len(files) == 0 {
+		return specHash, err
+	}
+	hash := sha256.New()
+	hash.Write([]byte(specHash))
+	for _, file := range files {
+		fileHash, err := ComputeFileHash(file)
+		if err != nil {
+			return "", err
+		}
+		_, _ = fmt.Fprintf(hash, "\n%s %s", file, fileHash)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// LoadOrParseSpec parses the spec file and returns endpoints with hash and
+// the files the spec references.
 // Now uses endpoints.json storage instead of Bleve index
-func LoadOrParseSpec(specPath, projectID, baseURL, apiKey string, isTemporary bool) ([]parser.Endpoint, string, error) {
-	// Compute current hash
-	currentHash, err := ComputeFileHash(specPath)
-	if err != nil {
-		return nil, "", err
+func LoadOrParseSpec(specPath, projectID, baseURL, apiKey string, isTemporary bool) ([]parser.Endpoint, string, []string, error) {
+	if _, err := ComputeFileHash(specPath); err != nil {
+		return nil, "", nil, err
 	}
 
 	// Check if we have cached endpoints with matching hash
 	if HasEndpoints(projectID, isTemporary) {
-		storedHash, err := getStoredHash(projectID, isTemporary)
-		if err == nil && storedHash == currentHash {
-			// Hash matches, load cached endpoints
-			endpoints, err := LoadEndpoints(projectID, isTemporary)
-			return endpoints, currentHash, err
+		storedHash, files, err := getStoredHash(projectID, isTemporary)
+		if err == nil {
+			if currentHash, err := ComputeSpecHash(specPath, files); err == nil && storedHash == currentHash {
+				// Hash matches, load cached endpoints
+				endpoints, err := LoadEndpoints(projectID, isTemporary)
+				return endpoints, currentHash, files, err
+			}
 		}
 	}
 
-	// Parse spec file
-	ext := strings.ToLower(filepath.Ext(specPath))
-
+	// Parse spec file with the local parser (fast, no backend needed)
 	var endpoints []parser.Endpoint
+	var files []string
 
-	// For JSON/YAML/GraphQL/Markdown, use local parser (fast, no backend needed)
-	if ext == ".json" || ext == ".yaml" || ext == ".yml" || ext == ".graphql" || ext == ".gql" || ext == ".md" || ext == ".markdown" {
-		spec, err := parser.ParseSpecification(specPath)
-		if err != nil {
-			return nil, "", fmt.Errorf("failed to parse spec: %w", err)
-		}
+	spec, err := parser.ParseSpecification(specPath)
+	if err == nil {
 		endpoints = spec.Endpoints
+		files = spec.Files
+	} else if !errors.Is(err, parser.ErrUnsupportedFormat) {
+		return nil, "", nil, fmt.Errorf("failed to parse spec: %w", err)
 	} else {
-		// For other formats (RAML, Proto, etc), use local AI processing
-		// Read spec file content
+		// Only formats without a native parser (Proto, etc) use local AI processing
 		specContent, err := os.ReadFile(specPath)
 		if err != nil {
-			return nil, "", fmt.Errorf("failed to read spec file: %w", err)
+			return nil, "", nil, fmt.Errorf("failed to read spec file: %w", err)
 		}
 
 		// Create local agent and process spec
 		localAgent, err := agent.NewAgent(baseURL)
 		if err != nil {
-			return nil, "", fmt.Errorf("failed to create agent: %w", err)
+			return nil, "", nil, fmt.Errorf("failed to create agent: %w", err)
 		}
 
 		apiEndpoints, err := localAgent.ProcessSpecification(string(specContent), baseURL)
 		if err != nil {
-			return nil, "", fmt.Errorf("failed to process spec with AI: %w", err)
+			return nil, "", nil, fmt.Errorf("failed to process spec with AI: %w", err)
 		}
 
 		// Convert agent response to parser.Endpoint format
@@ -312,15 +431,20 @@ func LoadOrParseSpec(specPath, projectID, baseURL, apiKey string, isTemporary bo
 
 	// Save endpoints to JSON
 	if err := SaveEndpoints(projectID, endpoints, isTemporary); err != nil {
-		return nil, "", fmt.Errorf("failed to save endpoints: %w", err)
+		return nil, "", nil, fmt.Errorf("failed to save endpoints: %w", err)
+	}
+
+	currentHash, err := ComputeSpecHash(specPath, files)
+	if err != nil {
+		return nil, "", nil, err
 	}
 
 	// Store the hash
-	if err := storeHash(projectID, currentHash, isTemporary); err != nil {
-		return nil, "", fmt.Errorf("failed to store hash: %w", err)
+	if err := storeHash(projectID, currentHash, files, isTemporary); err != nil {
+		return nil, "", nil, fmt.Errorf("failed to store hash: %w", err)
 	}
 
-	return endpoints, currentHash, nil
+	return endpoints, currentHash, files, nil
 }
 
 // CreateOrUpdateProject creates or updates a project with spec parsing
@@ -328,11 +452,12 @@ func LoadOrParseSpec(specPath, projectID, baseURL, apiKey string, isTemporary bo
 func CreateOrUpdateProject(projectID, name, baseURL, specPath, apiKey string, isTemporary bool) (*Project, []parser.Endpoint, error) {
 	var endpoints []parser.Endpoint
 	var specHash string
+	var specFiles []string
 
 	if specPath != "" {
 		// Parse spec and save endpoints to JSON
 		var err error
-		endpoints, specHash, err = LoadOrParseSpec(specPath, projectID, baseURL, apiKey, isTemporary)
+		endpoints, specHash, specFiles, err = LoadOrParseSpec(specPath, projectID, baseURL, apiKey, isTemporary)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to parse spec: %w", err)
 		}
@@ -348,6 +473,7 @@ func CreateOrUpdateProject(projectID, name, baseURL, specPath, apiKey string, is
 			BaseURL:     baseURL,
 			SpecPath:    specPath,
 			SpecHash:    specHash,
+			SpecFiles:   specFiles,
 			IsTemporary: isTemporary,
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
@@ -363,6 +489,7 @@ func CreateOrUpdateProject(projectID, name, baseURL, specPath, apiKey string, is
 		if specPath != "" {
 			project.SpecPath = specPath
 			project.SpecHash = specHash
+			project.SpecFiles = specFiles
 		}
 		project.UpdatedAt = time.Now()
 	}
@@ -540,25 +667,28 @@ func CleanupTempProjects() error {
 }
 
 // getStoredHash retrieves the stored spec hash for a project
-func getStoredHash(projectID string, isTemporary bool) (string, error) {
+func getStoredHash(projectID string, isTemporary bool) (string, []string, error) {
 	projectPath, err := GetProjectPathByType(projectID, isTemporary)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 	hashPath := filepath.Join(projectPath, "spec.hash")
 	content, err := os.ReadFile(hashPath)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
-	return strings.TrimSpace(string(content)), nil
+	// The hash is followed by the referenced files it covers, one per line.
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	return lines[0], lines[1:], nil
 }
 
 // storeHash stores the spec hash for a project
-func storeHash(projectID, hash string, isTemporary bool) error {
+func storeHash(projectID, hash string, files []string, isTemporary bool) error {
 	projectPath, err := GetProjectPathByType(projectID, isTemporary)
 	if err != nil {
 		return err
 	}
 	hashPath := filepath.Join(projectPath, "spec.hash")
-	return os.WriteFile(hashPath, []byte(hash), 0644)
+	content := strings.Join(append([]string{hash}, files...), "\n")
+	return os.WriteFile(hashPath, []byte(content), 0644)
 }
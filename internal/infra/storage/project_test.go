@@ -3,8 +3,13 @@ package storage
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/Octrafic/octrafic-cli/internal/agents"
+	"github.com/Octrafic/octrafic-cli/internal/core/auth"
+	"github.com/Octrafic/octrafic-cli/internal/infra/secrets"
 )
 
 func TestProjectManagement(t *testing.T) {
@@ -155,3 +160,143 @@ func TestCleanupTempProjects(t *testing.T) {
 		t.Errorf("Expected no temporary projects after cleanup, found %d", len(entries))
 	}
 }
+
+func TestTestSuites(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	suite := &TestSuite{
+		Name:   "checkout-flow",
+		Source: "recorded",
+		Tests: []agent.TestCase{
+			{Method: "POST", Endpoint: "/orders", ExpectedStatus: 201, Extract: []agent.Extract{{Field: "id", As: "order_id"}}},
+			{Method: "GET", Endpoint: "/orders/{{order_id}}", ExpectedStatus: 200},
+		},
+	}
+	if err := SaveTestSuite("suite-project", false, suite); err != nil {
+		t.Fatalf("SaveTestSuite failed: %v", err)
+	}
+
+	names, err := ListTestSuites("suite-project", false)
+	if err != nil || len(names) != 1 || names[0] != "checkout-flow" {
+		t.Fatalf("expected [checkout-flow], got %v (%v)", names, err)
+	}
+
+	loaded, err := LoadTestSuite("suite-project", false, "checkout-flow")
+	if err != nil {
+		t.Fatalf("LoadTestSuite failed: %v", err)
+	}
+	if len(loaded.Tests) != 2 || loaded.Tests[1].Endpoint != "/orders/{{order_id}}" || loaded.Tests[0].Extract[0].As != "order_id" {
+		t.Errorf("suite did not round-trip: %+v", loaded.Tests)
+	}
+	if loaded.CreatedAt.IsZero() {
+		t.Error("expected CreatedAt to be set")
+	}
+
+	if _, err := LoadTestSuite("suite-project", false, "missing"); err == nil {
+		t.Error("expected error for missing suite")
+	}
+	if err := SaveTestSuite("suite-project", false, &TestSuite{Name: "../escape"}); err == nil {
+		t.Error("expected invalid suite name to be rejected")
+	}
+}
+
+func TestSaveProject_MovesCredentialsToSecretStore(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	vault := secrets.NewVault(filepath.Join(t.TempDir(), "secrets.vault"))
+	if err := vault.Unlock("pw"); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+	secrets.SetDefault(vault)
+	defer secrets.SetDefault(nil)
+
+	project := &Project{
+		ID:         "secret-project",
+		Name:       "shop",
+		AuthConfig: &AuthConfig{Type: "bearer", Token: "tok-123"},
+		Identities: map[string]*AuthConfig{
+			"admin": {Type: "sigv4", SigV4: auth.NewSigV4Auth("AKID", "aws-secret", "", "eu-west-1", "")},
+		},
+	}
+	if err := SaveProject(project); err != nil {
+		t.Fatalf("SaveProject failed: %v", err)
+	}
+
+	path := filepath.Join(os.Getenv("HOME"), storageDir, projectsDir, project.ID, "project.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read project file: %v", err)
+	}
+	if strings.Contains(string(data), "tok-123") || strings.Contains(string(data), "aws-secret") {
+		t.Errorf("project file holds plain-text credentials: %s", data)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("expected project file mode 0600, got %v", info.Mode().Perm())
+	}
+
+	loaded, err := LoadProject(project.ID)
+	if err != nil {
+		t.Fatalf("LoadProject failed: %v", err)
+	}
+	if loaded.HasPlaintextSecrets() {
+		t.Error("expected no plain-text credentials after saving")
+	}
+	admin, err := loaded.Identities["admin"].Resolve()
+	if err != nil || admin.SigV4.SecretAccessKey != "aws-secret" || admin.SigV4.AccessKeyID != "AKID" {
+		t.Errorf("identity did not resolve: %+v, %v", admin, err)
+	}
+	if !secrets.IsRef(loaded.Identities["admin"].SigV4.SecretAccessKey) {
+		t.Error("Resolve must not modify the saved configuration")
+	}
+
+	refs := loaded.SecretRefs()
+	if len(refs) != 2 || refs[0].Key != "projects/secret-project/auth/token" || refs[1].Field != "identity admin: secret access key" {
+		t.Errorf("unexpected refs: %+v", refs)
+	}
+	if !loaded.ClearSecretRef(refs[0].Key) || loaded.AuthConfig.Token != "" {
+		t.Error("expected ClearSecretRef to empty the token")
+	}
+}
+
+func TestLoadOrParseSpec_ReferencedFileInvalidatesCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "openapi.yaml")
+	refPath := filepath.Join(dir, "users.yaml")
+	spec := "openapi: \"3.0.0\"\npaths:\n  /users:\n    $ref: \"./users.yaml\"\n"
+	if err := os.WriteFile(specPath, []byte(spec), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(refPath, []byte("get:\n  summary: List users\n  responses: {\"200\": {description: OK}}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	endpoints, hash, files, err := LoadOrParseSpec(specPath, "refs-project", "", "", false)
+	if err != nil {
+		t.Fatalf("LoadOrParseSpec failed: %v", err)
+	}
+	if len(endpoints) != 1 || endpoints[0].Method != "GET" {
+		t.Fatalf("expected GET /users from referenced file, got %+v", endpoints)
+	}
+	if len(files) != 1 || files[0] != refPath {
+		t.Errorf("expected referenced file %s, got %v", refPath, files)
+	}
+	specOnly, _ := ComputeFileHash(specPath)
+	if hash == specOnly {
+		t.Error("expected hash to cover the referenced file")
+	}
+
+	if err := os.WriteFile(refPath, []byte("post:\n  summary: Create user\n  responses: {\"201\": {description: Created}}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	endpoints, newHash, _, err := LoadOrParseSpec(specPath, "refs-project", "", "", false)
+	if err != nil {
+		t.Fatalf("LoadOrParseSpec failed: %v", err)
+	}
+	if newHash == hash {
+		t.Error("expected hash to change after editing the referenced file")
+	}
+	if len(endpoints) != 1 || endpoints[0].Method != "POST" {
+		t.Errorf("expected endpoints to be reparsed, got %+v", endpoints)
+	}
+}
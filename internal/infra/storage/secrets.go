@@ -0,0 +1,180 @@
+package storage
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/Octrafic/octrafic-cli/internal/infra/secrets"
+)
+
+// secretField is a credential field of an AuthConfig.
+type secretField struct {
+	name  string
+	value *string
+}
+
+// secretFields returns the credential fields that are kept in the secret
+// store instead of project.json.
+func (c *AuthConfig) secretFields() []secretField {
+	fields := []secretField{
+		{"token", &c.Token},
+		{"key_value", &c.KeyValue},
+		{"password", &c.Password},
+		{"client_secret", &c.ClientSecret},
+		{"refresh_token", &c.RefreshToken},
+	}
+	if c.Login != nil {
+		fields = append(fields, secretField{"login_body", &c.Login.Body})
+	}
+	if c.SigV4 != nil {
+		fields = append(fields, secretField{"secret_access_key", &c.SigV4.SecretAccessKey}, secretField{"session_token", &c.SigV4.SessionToken})
+	}
+	if c.HMAC != nil {
+		fields = append(fields, secretField{"hmac_secret", &c.HMAC.Secret})
+	}
+	return fields
+}
+
+// Resolve returns a copy of the configuration with secret store references
+// replaced by their values. env:, file: and cmd: references are kept; the
+// providers resolve them per request.
+func (c *AuthConfig) Resolve() (*AuthConfig, error) {
+	resolved := *c
+	if c.Login != nil {
+		login := *c.Login
+		resolved.Login = &login
+	}
+	if c.SigV4 != nil {
+		sigv4 := *c.SigV4
+		resolved.SigV4 = &sigv4
+	}
+	if c.HMAC != nil {
+		hmac := *c.HMAC
+		resolved.HMAC = &hmac
+	}
+	for _, f := range resolved.secretFields() {
+		if !secrets.IsRef(*f.value) {
+			continue
+		}
+		value, err := secrets.Resolve(*f.value)
+		if err != nil {
+			return nil, err
+		}
+		*f.value = value
+	}
+	return &resolved, nil
+}
+
+// authConfigs calls fn for the project authentication, every identity and
+// the credentials of each security scheme, with the scope their secrets are
+// stored under.
+func (p *Project) authConfigs(fn func(scope, label string, cfg *AuthConfig)) {
+	if p.AuthConfig != nil {
+		fn("auth", "auth", p.AuthConfig)
+	}
+	for _, name := range p.IdentityNames() {
+		if cfg := p.Identities[name]; cfg != nil {
+			fn("identities/"+name, "identity "+name+":", cfg)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(p.SchemeAuth)) {
+		if cfg := p.SchemeAuth[name]; cfg != nil {
+			fn("schemes/"+name, "scheme "+name+":", cfg)
+		}
+	}
+}
+
+// HasPlaintextSecrets reports whether the project holds credentials that are
+// not in the secret store yet.
+func (p *Project) HasPlaintextSecrets() bool {
+	found := false
+	p.authConfigs(func(_, _ string, cfg *AuthConfig) {
+		for _, f := range cfg.secretFields() {
+			if *f.value != "" && !secrets.IsReference(*f.value) {
+				found = true
+			}
+		}
+	})
+	return found
+}
+
+// sealSecrets moves plain-text credentials into the secret store and leaves
+// references in their place. Credentials that cannot be stored stay as they
+// are and the first error is returned.
+func (p *Project) sealSecrets() error {
+	var firstErr error
+	p.authConfigs(func(scope, _ string, cfg *AuthConfig) {
+		for _, f := range cfg.secretFields() {
+			ref, err := secrets.Put(fmt.Sprintf("projects/%s/%s/%s", p.ID, scope, f.name), *f.value)
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+			*f.value = ref
+		}
+	})
+	return firstErr
+}
+
+// SecretRef is a secret used by a project or the LLM configuration.
+type SecretRef struct {
+	Key   string
+	Owner string // project name, or "" for the LLM configuration
+	Field string // e.g. "auth token" or "identity admin: password"
+}
+
+// SecretRefs returns the secrets a project refers to.
+func (p *Project) SecretRefs() []SecretRef {
+	var refs []SecretRef
+	p.authConfigs(func(_, label string, cfg *AuthConfig) {
+		for _, f := range cfg.secretFields() {
+			if secrets.IsRef(*f.value) {
+				field := label + " " + strings.ReplaceAll(f.name, "_", " ")
+				refs = append(refs, SecretRef{Key: secrets.Key(*f.value), Owner: p.Name, Field: field})
+			}
+		}
+	})
+	return refs
+}
+
+// ClearSecretRef empties the fields that refer to a secret key and reports
+// whether there were any.
+func (p *Project) ClearSecretRef(key string) bool {
+	cleared := false
+	p.authConfigs(func(_, _ string, cfg *AuthConfig) {
+		for _, f := range cfg.secretFields() {
+			if *f.value == secrets.Ref(key) {
+				*f.value = ""
+				cleared = true
+			}
+		}
+	})
+	return cleared
+}
+
+// MigrateSecrets moves plain-text credentials of all saved projects into the
+// secret store and returns the number of projects updated.
+func MigrateSecrets() (int, error) {
+	projects, err := ListProjects()
+	if err != nil {
+		return 0, err
+	}
+	migrated := 0
+	for _, project := range projects {
+		if !project.HasPlaintextSecrets() {
+			continue
+		}
+		if err := project.sealSecrets(); err != nil {
+			return migrated, err
+		}
+		if err := writeProject(project); err != nil {
+			return migrated, err
+		}
+		migrated++
+	}
+	return migrated, nil
+}
@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Octrafic/octrafic-cli/internal/agents"
+)
+
+const suitesDir = "suites"
+
+var suiteNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// TestSuite is a named, replayable list of test cases saved in a project,
+// e.g. recorded with `octrafic record` or saved by the agent.
+type TestSuite struct {
+	Name      string           `json:"name"`
+	Source    string           `json:"source,omitempty"` // recorded, agent
+	BaseURL   string           `json:"base_url,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+	Tests     []agent.TestCase `json:"tests"`
+}
+
+// ValidateSuiteName checks that a suite name is usable as a file name.
+func ValidateSuiteName(name string) error {
+	if !suiteNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid suite name %q: use letters, digits, '.', '_' and '-'", name)
+	}
+	return nil
+}
+
+func suitePath(projectID string, isTemporary bool, name string) (string, error) {
+	if err := ValidateSuiteName(name); err != nil {
+		return "", err
+	}
+	projectPath, err := GetProjectPathByType(projectID, isTemporary)
+	if err != nil {
+		return "", fmt.Errorf("failed to get project path: %w", err)
+	}
+	return filepath.Join(projectPath, suitesDir, name+".json"), nil
+}
+
+// SaveTestSuite writes a suite into the project, replacing one with the same name.
+func SaveTestSuite(projectID string, isTemporary bool, suite *TestSuite) error {
+	path, err := suitePath(projectID, isTemporary, suite.Name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create suites directory: %w", err)
+	}
+
+	now := time.Now()
+	if suite.CreatedAt.IsZero() {
+		suite.CreatedAt = now
+	}
+	suite.UpdatedAt = now
+
+	data, err := json.MarshalIndent(suite, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal suite: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write suite file: %w", err)
+	}
+	return nil
+}
+
+// LoadTestSuite reads a suite saved in the project.
+func LoadTestSuite(projectID string, isTemporary bool, name string) (*TestSuite, error) {
+	path, err := suitePath(projectID, isTemporary, name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, fmt.Errorf("suite %q not found", name)
+	}
+	return LoadTestSuiteFile(path)
+}
+
+// LoadTestSuiteFile reads a suite from any path.
+func LoadTestSuiteFile(path string) (*TestSuite, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read suite file: %w", err)
+	}
+	var suite TestSuite
+	if err := json.Unmarshal(data, &suite); err != nil {
+		return nil, fmt.Errorf("failed to parse suite file: %w", err)
+	}
+	if suite.Name == "" {
+		suite.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return &suite, nil
+}
+
+// ListTestSuites returns the names of the suites saved in the project, sorted.
+func ListTestSuites(projectID string, isTemporary bool) ([]string, error) {
+	projectPath, err := GetProjectPathByType(projectID, isTemporary)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project path: %w", err)
+	}
+	entries, err := os.ReadDir(filepath.Join(projectPath, suitesDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read suites directory: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			names = append(names, strings.TrimSuffix(entry.Name(), ".json"))
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
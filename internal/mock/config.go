@@ -0,0 +1,70 @@
+package mock
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Octrafic/octrafic-cli/internal/core/parser"
+	"gopkg.in/yaml.v3"
+)
+
+// Config controls mock behaviour. It is read from a YAML or JSON file:
+//
+//	stateful: true
+//	latency_ms: 50
+//	endpoints:
+//	  - method: POST
+//	    path: /orders
+//	    latency_ms: 800
+//	    error_rate: 0.2
+//	    error_status: 503
+type Config struct {
+	Stateful  bool   `yaml:"stateful" json:"stateful"`
+	LatencyMs int    `yaml:"latency_ms" json:"latency_ms"`
+	Rules     []Rule `yaml:"endpoints" json:"endpoints"`
+}
+
+// Rule overrides behaviour for endpoints matching Method and Path. An empty
+// Method matches any method; Path may be a template or a concrete path.
+type Rule struct {
+	Method      string  `yaml:"method" json:"method"`
+	Path        string  `yaml:"path" json:"path"`
+	LatencyMs   int     `yaml:"latency_ms" json:"latency_ms"`
+	ErrorRate   float64 `yaml:"error_rate" json:"error_rate"` // 0..1
+	ErrorStatus int     `yaml:"error_status" json:"error_status"`
+	ErrorBody   any     `yaml:"error_body" json:"error_body"`
+	Status      int     `yaml:"status" json:"status"` // response status to return instead of the default
+}
+
+// LoadConfig reads a mock configuration file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mock config: %w", err)
+	}
+	cfg := &Config{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse mock config: %w", err)
+	}
+	for i, r := range cfg.Rules {
+		if r.ErrorRate < 0 || r.ErrorRate > 1 {
+			return nil, fmt.Errorf("endpoint %d (%s %s): error_rate must be between 0 and 1", i+1, r.Method, r.Path)
+		}
+	}
+	return cfg, nil
+}
+
+// rule returns the first rule matching the endpoint.
+func (c *Config) rule(ep parser.Endpoint) *Rule {
+	for i := range c.Rules {
+		r := &c.Rules[i]
+		if r.Method != "" && !strings.EqualFold(r.Method, ep.Method) {
+			continue
+		}
+		if r.Path == ep.Path || parser.MatchPath(ep.Path, r.Path) {
+			return r
+		}
+	}
+	return nil
+}
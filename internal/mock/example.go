@@ -0,0 +1,139 @@
+package mock
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// maxExampleDepth stops synthesis of deeply nested or recursive schemas.
+const maxExampleDepth = 6
+
+// Example builds a sample value for a JSON schema, preferring declared
+// example, default, const and enum values over synthesised ones.
+func Example(schema map[string]any) any {
+	return example(schema, schema, 0)
+}
+
+func example(schema, root map[string]any, depth int) any {
+	if schema == nil || depth > maxExampleDepth {
+		return nil
+	}
+	// Recursive schemas refer to themselves through $defs of the root.
+	if ref, ok := schema["$ref"].(string); ok && strings.HasPrefix(ref, "#/$defs/") {
+		defs, _ := root["$defs"].(map[string]any)
+		def, _ := defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]any)
+		return example(def, root, depth+1)
+	}
+	for _, key := range []string{"example", "default", "const"} {
+		if v, ok := schema[key]; ok {
+			return v
+		}
+	}
+	if examples, ok := schema["examples"].([]any); ok && len(examples) > 0 {
+		return examples[0]
+	}
+	if enum, ok := schema["enum"].([]any); ok && len(enum) > 0 {
+		return enum[0]
+	}
+
+	if all, ok := schema["allOf"].([]any); ok {
+		merged := map[string]any{}
+		for _, s := range all {
+			if sm, ok := s.(map[string]any); ok {
+				if obj, ok := example(sm, root, depth+1).(map[string]any); ok {
+					maps.Copy(merged, obj)
+				}
+			}
+		}
+		return merged
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if variants, ok := schema[key].([]any); ok && len(variants) > 0 {
+			if sm, ok := variants[0].(map[string]any); ok {
+				return example(sm, root, depth+1)
+			}
+		}
+	}
+
+	switch schemaType(schema) {
+	case "object":
+		obj := map[string]any{}
+		props, _ := schema["properties"].(map[string]any)
+		for _, name := range slices.Sorted(maps.Keys(props)) {
+			if ps, ok := props[name].(map[string]any); ok {
+				obj[name] = example(ps, root, depth+1)
+			}
+		}
+		return obj
+	case "array":
+		items, _ := schema["items"].(map[string]any)
+		if v := example(items, root, depth+1); v != nil {
+			return []any{v}
+		}
+		return []any{}
+	case "integer":
+		if min, ok := schema["minimum"].(float64); ok {
+			return min
+		}
+		return 1
+	case "number":
+		if min, ok := schema["minimum"].(float64); ok {
+			return min
+		}
+		return 1.5
+	case "boolean":
+		return true
+	case "string":
+		return stringExample(schema)
+	}
+	return nil
+}
+
+// schemaType returns the schema's type, inferring object and array from
+// properties and items; for type arrays the first non-null type is used.
+func schemaType(schema map[string]any) string {
+	switch t := schema["type"].(type) {
+	case string:
+		return t
+	case []any:
+		for _, v := range t {
+			if s, ok := v.(string); ok && s != "null" {
+				return s
+			}
+		}
+	}
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+	if _, ok := schema["items"]; ok {
+		return "array"
+	}
+	return ""
+}
+
+func stringExample(schema map[string]any) string {
+	switch schema["format"] {
+	case "date-time":
+		return "2024-01-01T00:00:00Z"
+	case "date":
+		return "2024-01-01"
+	case "email":
+		return "user@example.com"
+	case "uuid":
+		return "3fa85f64-5717-4562-b3fc-2c963f66afa6"
+	case "uri", "url":
+		return "https://example.com"
+	case "ipv4":
+		return "192.0.2.1"
+	case "byte":
+		return "c3RyaW5n"
+	}
+	s := "string"
+	if minLen, ok := schema["minLength"].(float64); ok {
+		for len(s) < int(minLen) {
+			s += "s"
+		}
+	}
+	return s
+}
@@ -0,0 +1,429 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Octrafic/octrafic-cli/internal/core/parser"
+	"github.com/Octrafic/octrafic-cli/internal/core/tester"
+)
+
+// maxRequestBody limits the size of request bodies read by the mock.
+const maxRequestBody = 10 << 20
+
+// Server serves spec endpoints with example or synthesised responses.
+type Server struct {
+	endpoints []parser.Endpoint
+	cfg       *Config
+	store     *store
+
+	// Log, if set, is called after every request.
+	Log func(method, path string, status int, elapsed time.Duration)
+
+	random func() float64
+	sleep  func(time.Duration)
+}
+
+// NewServer creates a mock for the given endpoints. GraphQL operations,
+// webhooks and AsyncAPI channels other than HTTP-bound ones are not served.
+func NewServer(endpoints []parser.Endpoint, cfg *Config) *Server {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	var served []parser.Endpoint
+	for _, ep := range endpoints {
+		if ep.AsyncAPI != nil && !ep.AsyncAPI.IsHTTP() {
+			continue
+		}
+		if ep.GraphQL == nil && ep.Webhook == nil {
+			served = append(served, ep)
+		}
+	}
+	return &Server{
+		endpoints: served,
+		cfg:       cfg,
+		store:     newStore(),
+		random:    rand.Float64,
+		sleep:     time.Sleep,
+	}
+}
+
+// Endpoints returns the endpoints the server responds to.
+func (s *Server) Endpoints() []parser.Endpoint {
+	return s.endpoints
+}
+
+// statusRecorder captures the response status for logging.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	s.serve(rec, r)
+	if s.Log != nil {
+		s.Log(r.Method, r.URL.RequestURI(), rec.status, time.Since(start))
+	}
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	// Browsers call the mock from other origins during frontend development.
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	ep, allowed := s.match(r.Method, r.URL.Path)
+	if ep == nil {
+		if r.Method == http.MethodOptions && len(allowed) > 0 {
+			w.Header().Set("Access-Control-Allow-Methods", strings.Join(allowed, ", "))
+			if h := r.Header.Get("Access-Control-Request-Headers"); h != "" {
+				w.Header().Set("Access-Control-Allow-Headers", h)
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if len(allowed) > 0 {
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			writeJSON(w, http.StatusMethodNotAllowed, errorBody(fmt.Sprintf("method %s not allowed for %s", r.Method, r.URL.Path)))
+			return
+		}
+		writeJSON(w, http.StatusNotFound, errorBody(fmt.Sprintf("no endpoint matches %s %s", r.Method, r.URL.Path)))
+		return
+	}
+
+	rule := s.cfg.rule(*ep)
+	latency := s.cfg.LatencyMs
+	if rule != nil && rule.LatencyMs > 0 {
+		latency = rule.LatencyMs
+	}
+	if latency > 0 {
+		s.sleep(time.Duration(latency) * time.Millisecond)
+	}
+	if rule != nil && rule.ErrorRate > 0 && s.random() < rule.ErrorRate {
+		status := rule.ErrorStatus
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		body := rule.ErrorBody
+		if body == nil {
+			body = errorBody("injected failure")
+		}
+		writeJSON(w, status, body)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBody))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorBody(fmt.Sprintf("failed to read request body: %v", err)))
+		return
+	}
+	if errs := validateRequest(*ep, r, body); len(errs) > 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]any{
+			"error":   "request validation failed",
+			"details": errs,
+		})
+		return
+	}
+
+	if s.cfg.Stateful && s.serveStateful(w, *ep, r, body) {
+		return
+	}
+
+	status := successStatus(*ep)
+	if rule != nil && rule.Status != 0 {
+		status = rule.Status
+	}
+	writeJSON(w, status, responseBody(*ep, status))
+}
+
+// match finds the endpoint for a request. Templates with more literal
+// segments win, so /users/me is preferred over /users/{id}. allowed lists
+// the methods of endpoints whose path matches.
+func (s *Server) match(method, path string) (*parser.Endpoint, []string) {
+	var best *parser.Endpoint
+	bestScore := -1
+	var allowed []string
+	for i := range s.endpoints {
+		ep := &s.endpoints[i]
+		if !parser.MatchPath(ep.Path, path) {
+			continue
+		}
+		if !slices.Contains(allowed, ep.Method) {
+			allowed = append(allowed, ep.Method)
+		}
+		if !strings.EqualFold(ep.Method, method) {
+			continue
+		}
+		if score := literalSegments(ep.Path); score > bestScore {
+			best, bestScore = ep, score
+		}
+	}
+	slices.Sort(allowed)
+	return best, allowed
+}
+
+func literalSegments(template string) int {
+	n := 0
+	for _, seg := range strings.Split(strings.Trim(template, "/"), "/") {
+		if !isTemplateSegment(seg) {
+			n++
+		}
+	}
+	return n
+}
+
+func isTemplateSegment(seg string) bool {
+	return strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}")
+}
+
+// pathParams extracts template parameter values from a concrete path.
+func pathParams(template, path string) map[string]string {
+	params := make(map[string]string)
+	tParts := strings.Split(strings.Trim(template, "/"), "/")
+	aParts := strings.Split(strings.Trim(path, "/"), "/")
+	for i, tp := range tParts {
+		if i < len(aParts) && isTemplateSegment(tp) {
+			params[strings.Trim(tp, "{}")] = aParts[i]
+		}
+	}
+	return params
+}
+
+// validateRequest checks required parameters, parameter types and the JSON
+// body against the endpoint's request schema.
+func validateRequest(ep parser.Endpoint, r *http.Request, body []byte) []string {
+	var errs []string
+	query := r.URL.Query()
+	path := pathParams(ep.Path, r.URL.Path)
+	for _, p := range ep.Parameters {
+		var value string
+		var present bool
+		switch p.In {
+		case "query":
+			present = query.Has(p.Name)
+			value = query.Get(p.Name)
+		case "header":
+			value = r.Header.Get(p.Name)
+			present = value != ""
+		case "path":
+			value, present = path[p.Name]
+		case "cookie":
+			if c, err := r.Cookie(p.Name); err == nil {
+				value, present = c.Value, true
+			}
+		default:
+			continue
+		}
+		if !present {
+			if p.Required {
+				errs = append(errs, fmt.Sprintf("missing required %s parameter %q", p.In, p.Name))
+			}
+			continue
+		}
+		if msg := checkParamType(p.Type, value); msg != "" {
+			errs = append(errs, fmt.Sprintf("%s parameter %q: %s", p.In, p.Name, msg))
+		} else if len(p.Enum) > 0 && !slices.ContainsFunc(p.Enum, func(v any) bool { return fmt.Sprint(v) == value }) {
+			errs = append(errs, fmt.Sprintf("%s parameter %q: %q is not one of %v", p.In, p.Name, value, p.Enum))
+		}
+	}
+
+	trimmed := strings.TrimSpace(string(body))
+	if ep.RequestSchema != nil && trimmed != "" {
+		// curl -d sends form content types, so JSON-looking bodies are checked too.
+		ct := r.Header.Get("Content-Type")
+		if ct == "" || strings.Contains(ct, "json") || strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+			var parsed any
+			if err := json.Unmarshal(body, &parsed); err != nil {
+				errs = append(errs, fmt.Sprintf("request body is not valid JSON: %v", err))
+			} else {
+				for _, e := range tester.ValidateSchema(string(body), ep.RequestSchema) {
+					errs = append(errs, "body: "+e)
+				}
+			}
+		}
+	}
+	return errs
+}
+
+func checkParamType(typ, value string) string {
+	switch typ {
+	case "integer":
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Sprintf("expected integer, got %q", value)
+		}
+	case "number":
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Sprintf("expected number, got %q", value)
+		}
+	case "boolean":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Sprintf("expected boolean, got %q", value)
+		}
+	}
+	return ""
+}
+
+// successStatus is the lowest 2xx status documented for the endpoint,
+// defaulting to 201 for POST and 200 otherwise.
+func successStatus(ep parser.Endpoint) int {
+	best := 0
+	for _, keys := range [][]string{
+		slices.Collect(maps.Keys(ep.Responses)),
+		slices.Collect(maps.Keys(ep.ResponseSchemas)),
+		slices.Collect(maps.Keys(ep.ResponseExamples)),
+	} {
+		for _, k := range keys {
+			code, err := strconv.Atoi(k)
+			if err == nil && code >= 200 && code < 300 && (best == 0 || code < best) {
+				best = code
+			}
+		}
+	}
+	if best != 0 {
+		return best
+	}
+	if ep.Method == http.MethodPost {
+		return http.StatusCreated
+	}
+	return http.StatusOK
+}
+
+// responseBody returns the declared example for status, or one synthesised
+// from its schema.
+func responseBody(ep parser.Endpoint, status int) any {
+	key := strconv.Itoa(status)
+	if ex, ok := ep.ResponseExamples[key]; ok {
+		return ex
+	}
+	if schema, ok := ep.ResponseSchemas[key]; ok {
+		return Example(schema)
+	}
+	return nil
+}
+
+// serveStateful handles simple CRUD: POST to a collection stores the body,
+// GET/PUT/PATCH/DELETE on {collection}/{id} act on stored items and GET on
+// the collection lists them. It reports whether the request was handled.
+func (s *Server) serveStateful(w http.ResponseWriter, ep parser.Endpoint, r *http.Request, body []byte) bool {
+	segments := strings.Split(strings.Trim(ep.Path, "/"), "/")
+	itemRoute := isTemplateSegment(segments[len(segments)-1])
+	path := "/" + strings.Trim(r.URL.Path, "/")
+	status := successStatus(ep)
+	const idField = "id"
+
+	if !itemRoute {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, status, listBody(ep, status, s.store.list(path)))
+			return true
+		case http.MethodPost:
+			item, ok := jsonObject(body)
+			if !ok {
+				return false
+			}
+			base, _ := responseBody(ep, status).(map[string]any)
+			merged := map[string]any{}
+			for k, v := range base {
+				if k != idField {
+					merged[k] = v
+				}
+			}
+			maps.Copy(merged, item)
+			writeJSON(w, status, s.store.create(path, idField, merged))
+			return true
+		}
+		return false
+	}
+
+	collection := path[:strings.LastIndex(path, "/")]
+	id := path[strings.LastIndex(path, "/")+1:]
+	notFound := func() {
+		writeJSON(w, http.StatusNotFound, errorBody(fmt.Sprintf("%s not found", path)))
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		item, ok := s.store.get(collection, id)
+		if !ok {
+			notFound()
+			return true
+		}
+		writeJSON(w, status, item)
+	case http.MethodPut, http.MethodPatch:
+		item, ok := jsonObject(body)
+		if !ok {
+			return false
+		}
+		updated, found := s.store.update(collection, idField, id, item, r.Method == http.MethodPatch)
+		if !found {
+			notFound()
+			return true
+		}
+		writeJSON(w, status, updated)
+	case http.MethodDelete:
+		if !s.store.delete(collection, id) {
+			notFound()
+			return true
+		}
+		writeJSON(w, status, responseBody(ep, status))
+	default:
+		return false
+	}
+	return true
+}
+
+// listBody shapes stored items like the documented list response: a bare
+// array, or an object whose first array property holds the items.
+func listBody(ep parser.Endpoint, status int, items []any) any {
+	schema := ep.ResponseSchemas[strconv.Itoa(status)]
+	if schema == nil || schemaType(schema) != "object" {
+		return items
+	}
+	obj, ok := Example(schema).(map[string]any)
+	if !ok {
+		obj = map[string]any{}
+	}
+	props, _ := schema["properties"].(map[string]any)
+	for _, name := range slices.Sorted(maps.Keys(props)) {
+		if ps, ok := props[name].(map[string]any); ok && schemaType(ps) == "array" {
+			obj[name] = items
+			return obj
+		}
+	}
+	return items
+}
+
+func jsonObject(body []byte) (map[string]any, bool) {
+	var obj map[string]any
+	if err := json.Unmarshal(body, &obj); err != nil || obj == nil {
+		return nil, false
+	}
+	return obj, true
+}
+
+func errorBody(msg string) map[string]any {
+	return map[string]any{"error": msg}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	if body == nil || status == http.StatusNoContent || status == http.StatusNotModified {
+		w.WriteHeader(status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,200 @@
+package mock
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Octrafic/octrafic-cli/internal/core/parser"
+)
+
+var userSchema = map[string]any{
+	"type":     "object",
+	"required": []any{"name"},
+	"properties": map[string]any{
+		"id":    map[string]any{"type": "integer"},
+		"name":  map[string]any{"type": "string"},
+		"email": map[string]any{"type": "string", "format": "email"},
+	},
+}
+
+func testEndpoints() []parser.Endpoint {
+	return []parser.Endpoint{
+		{
+			Method:          "GET",
+			Path:            "/users",
+			Parameters:      []parser.Parameter{{Name: "limit", In: "query", Type: "integer"}},
+			ResponseSchemas: map[string]map[string]any{"200": {"type": "array", "items": userSchema}},
+		},
+		{
+			Method:          "POST",
+			Path:            "/users",
+			RequestSchema:   userSchema,
+			ResponseSchemas: map[string]map[string]any{"201": userSchema},
+		},
+		{
+			Method:           "GET",
+			Path:             "/users/{id}",
+			Parameters:       []parser.Parameter{{Name: "id", In: "path", Type: "integer", Required: true}},
+			ResponseExamples: map[string]any{"200": map[string]any{"id": float64(7), "name": "Ada"}},
+		},
+		{Method: "GET", Path: "/users/me", Responses: map[string]string{"200": "Current user"}},
+		{Method: "PATCH", Path: "/users/{id}", ResponseSchemas: map[string]map[string]any{"200": userSchema}},
+		{Method: "DELETE", Path: "/users/{id}", Responses: map[string]string{"204": "Deleted"}},
+	}
+}
+
+func do(t *testing.T, h http.Handler, method, path, body string) (*httptest.ResponseRecorder, any) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	var decoded any
+	if rec.Body.Len() > 0 {
+		if err := json.Unmarshal(rec.Body.Bytes(), &decoded); err != nil {
+			t.Fatalf("%s %s: invalid JSON response %q", method, path, rec.Body.String())
+		}
+	}
+	return rec, decoded
+}
+
+func TestServer_ExamplesAndSynthesis(t *testing.T) {
+	s := NewServer(testEndpoints(), nil)
+
+	rec, body := do(t, s, "GET", "/users/42", "")
+	if rec.Code != 200 || body.(map[string]any)["name"] != "Ada" {
+		t.Errorf("expected declared example, got %d %v", rec.Code, body)
+	}
+
+	rec, body = do(t, s, "GET", "/users", "")
+	list, ok := body.([]any)
+	if rec.Code != 200 || !ok || len(list) != 1 {
+		t.Fatalf("expected synthesised list, got %d %v", rec.Code, body)
+	}
+	if user := list[0].(map[string]any); user["email"] != "user@example.com" {
+		t.Errorf("expected format-aware sample, got %v", user)
+	}
+
+	if rec, _ := do(t, s, "GET", "/users/me", ""); rec.Code != 200 {
+		t.Errorf("expected literal /users/me to win over /users/{id}, got %d", rec.Code)
+	}
+}
+
+func TestServer_RoutingErrors(t *testing.T) {
+	s := NewServer(testEndpoints(), nil)
+
+	if rec, _ := do(t, s, "GET", "/orders", ""); rec.Code != 404 {
+		t.Errorf("expected 404 for unknown path, got %d", rec.Code)
+	}
+	rec, _ := do(t, s, "PUT", "/users/1", "")
+	if rec.Code != 405 || rec.Header().Get("Allow") != "DELETE, GET, PATCH" {
+		t.Errorf("expected 405 with Allow header, got %d %q", rec.Code, rec.Header().Get("Allow"))
+	}
+}
+
+func TestServer_RequestValidation(t *testing.T) {
+	s := NewServer(testEndpoints(), nil)
+
+	tests := []struct {
+		method, path, body string
+		detail             string
+	}{
+		{"GET", "/users?limit=ten", "", `query parameter "limit": expected integer`},
+		{"GET", "/users/abc", "", `path parameter "id": expected integer`},
+		{"POST", "/users", `{"email":"a@b.c"}`, "body: "},
+		{"POST", "/users", `{"name":`, "not valid JSON"},
+	}
+	for _, tt := range tests {
+		rec, body := do(t, s, tt.method, tt.path, tt.body)
+		if rec.Code != 400 {
+			t.Errorf("%s %s: expected 400, got %d", tt.method, tt.path, rec.Code)
+			continue
+		}
+		var details []string
+		for _, d := range body.(map[string]any)["details"].([]any) {
+			details = append(details, d.(string))
+		}
+		if joined := strings.Join(details, "\n"); !strings.Contains(joined, tt.detail) {
+			t.Errorf("%s %s: expected detail %q, got %q", tt.method, tt.path, tt.detail, joined)
+		}
+	}
+}
+
+func TestServer_StatefulCRUD(t *testing.T) {
+	s := NewServer(testEndpoints(), &Config{Stateful: true})
+
+	rec, body := do(t, s, "POST", "/users", `{"name":"Grace"}`)
+	created, _ := body.(map[string]any)
+	if rec.Code != 201 || created["id"] != float64(1) || created["name"] != "Grace" {
+		t.Fatalf("unexpected create response: %d %v", rec.Code, body)
+	}
+
+	if _, body := do(t, s, "GET", "/users/1", ""); body.(map[string]any)["name"] != "Grace" {
+		t.Errorf("expected stored item, got %v", body)
+	}
+	if _, body := do(t, s, "PATCH", "/users/1", `{"email":"g@example.com"}`); body.(map[string]any)["name"] != "Grace" {
+		t.Errorf("expected PATCH to merge, got %v", body)
+	}
+	if _, body := do(t, s, "GET", "/users", ""); len(body.([]any)) != 1 {
+		t.Errorf("expected one listed item, got %v", body)
+	}
+	if rec, _ := do(t, s, "DELETE", "/users/1", ""); rec.Code != 204 {
+		t.Errorf("expected 204 on delete, got %d", rec.Code)
+	}
+	if rec, _ := do(t, s, "GET", "/users/1", ""); rec.Code != 404 {
+		t.Errorf("expected 404 after delete, got %d", rec.Code)
+	}
+}
+
+func TestServer_LatencyAndErrorInjection(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "mock.yaml")
+	cfg := `
+latency_ms: 10
+endpoints:
+  - method: GET
+    path: /users/{id}
+    latency_ms: 500
+    error_rate: 0.5
+    error_status: 503
+`
+	if err := os.WriteFile(cfgPath, []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadConfig(cfgPath)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	s := NewServer(testEndpoints(), loaded)
+	var slept []time.Duration
+	s.sleep = func(d time.Duration) { slept = append(slept, d) }
+	roll := 0.9
+	s.random = func() float64 { return roll }
+
+	if rec, _ := do(t, s, "GET", "/users/1", ""); rec.Code != 200 {
+		t.Errorf("expected success above error rate, got %d", rec.Code)
+	}
+	roll = 0.1
+	if rec, _ := do(t, s, "GET", "/users/1", ""); rec.Code != 503 {
+		t.Errorf("expected injected 503, got %d", rec.Code)
+	}
+	do(t, s, "GET", "/users", "")
+
+	want := []time.Duration{500 * time.Millisecond, 500 * time.Millisecond, 10 * time.Millisecond}
+	if len(slept) != len(want) {
+		t.Fatalf("expected %d sleeps, got %v", len(want), slept)
+	}
+	for i := range want {
+		if slept[i] != want[i] {
+			t.Errorf("sleep %d: expected %s, got %s", i, want[i], slept[i])
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package mock
+
+import (
+	"fmt"
+	"maps"
+	"strconv"
+	"sync"
+)
+
+// store keeps resources created through the mock, keyed by collection path
+// (e.g. "/users") and id.
+type store struct {
+	mu          sync.Mutex
+	collections map[string]*collection
+}
+
+type collection struct {
+	items  map[string]map[string]any
+	order  []string
+	nextID int
+}
+
+func newStore() *store {
+	return &store{collections: make(map[string]*collection)}
+}
+
+func (s *store) collection(path string) *collection {
+	c, ok := s.collections[path]
+	if !ok {
+		c = &collection{items: make(map[string]map[string]any), nextID: 1}
+		s.collections[path] = c
+	}
+	return c
+}
+
+// create stores item, assigning an id when it has none.
+func (s *store) create(path, idField string, item map[string]any) map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.collection(path)
+	id := idString(item[idField])
+	if id == "" {
+		id = strconv.Itoa(c.nextID)
+		item[idField] = c.nextID
+		c.nextID++
+	}
+	if _, exists := c.items[id]; !exists {
+		c.order = append(c.order, id)
+	}
+	c.items[id] = item
+	return maps.Clone(item)
+}
+
+func (s *store) get(path, id string) (map[string]any, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item, ok := s.collection(path).items[id]
+	if !ok {
+		return nil, false
+	}
+	return maps.Clone(item), true
+}
+
+func (s *store) list(path string) []any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.collection(path)
+	out := make([]any, 0, len(c.order))
+	for _, id := range c.order {
+		out = append(out, maps.Clone(c.items[id]))
+	}
+	return out
+}
+
+// update replaces (or, with merge, patches) an existing item.
+func (s *store) update(path, idField, id string, item map[string]any, merge bool) (map[string]any, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.collection(path)
+	existing, ok := c.items[id]
+	if !ok {
+		return nil, false
+	}
+	if merge {
+		updated := maps.Clone(existing)
+		maps.Copy(updated, item)
+		item = updated
+	}
+	item[idField] = existing[idField]
+	c.items[id] = item
+	return maps.Clone(item), true
+}
+
+func (s *store) delete(path, id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.collection(path)
+	if _, ok := c.items[id]; !ok {
+		return false
+	}
+	delete(c.items, id)
+	for i, v := range c.order {
+		if v == id {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+func idString(v any) string {
+	switch id := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return id
+	case float64:
+		return strconv.FormatFloat(id, 'f', -1, 64)
+	default:
+		return fmt.Sprint(id)
+	}
+}
@@ -0,0 +1,25 @@
+package recorder
+
+import (
+	"github.com/Octrafic/octrafic-cli/internal/core/parser"
+)
+
+// FromHAR converts the entries of a HAR file (see parser.ReadHAR) into
+// exchanges, so BuildTests can turn them into an ordered, chained suite.
+// Paths are relative to the entry's host, as the proxy records them.
+func FromHAR(entries []parser.HAREntry) []Exchange {
+	exchanges := make([]Exchange, 0, len(entries))
+	for _, e := range entries {
+		exchanges = append(exchanges, Exchange{
+			Method:          e.Method,
+			Path:            e.URL.RequestURI(),
+			RequestHeaders:  e.RequestHeaders,
+			RequestBody:     e.RequestBody,
+			Status:          e.Status,
+			ResponseHeaders: e.ResponseHeaders,
+			ResponseBody:    e.ResponseBody,
+			Duration:        e.Duration,
+		})
+	}
+	return exchanges
+}
@@ -0,0 +1,39 @@
+package recorder
+
+import (
+	"testing"
+
+	"github.com/Octrafic/octrafic-cli/internal/core/parser"
+)
+
+func TestFromHAR_BuildsChainedSuite(t *testing.T) {
+	har := `{"log": {"version": "1.2", "entries": [
+	  {"request": {"method": "POST", "url": "https://api.example.com/v1/users", "headers": [],
+	    "postData": {"mimeType": "application/json", "text": "{\"name\":\"Ada\"}"}},
+	   "response": {"status": 201, "headers": [{"name": "content-type", "value": "application/json"}],
+	    "content": {"mimeType": "application/json", "text": "{\"id\":\"u-81f2c\",\"name\":\"Ada\"}"}}},
+	  {"request": {"method": "GET", "url": "https://fonts.example.org/css?family=Inter", "headers": []},
+	   "response": {"status": 200, "headers": [], "content": {"mimeType": "text/css"}}},
+	  {"request": {"method": "GET", "url": "https://api.example.com/v1/users/u-81f2c?expand=true", "headers": []},
+	   "response": {"status": 200, "headers": [{"name": "content-type", "value": "application/json"}],
+	    "content": {"mimeType": "application/json", "text": "{\"id\":\"u-81f2c\",\"name\":\"Ada\"}"}}}
+	]}}`
+
+	entries, err := parser.ReadHAR([]byte(har), []string{"api.example.com"})
+	if err != nil {
+		t.Fatalf("ReadHAR failed: %v", err)
+	}
+	tests := BuildTests(FromHAR(entries))
+	if len(tests) != 2 {
+		t.Fatalf("expected 2 tests, got %d: %+v", len(tests), tests)
+	}
+	if tests[0].Method != "POST" || tests[0].Endpoint != "/v1/users" || tests[0].ExpectedStatus != 201 {
+		t.Errorf("expected recorded POST expecting 201, got %s %s %d", tests[0].Method, tests[0].Endpoint, tests[0].ExpectedStatus)
+	}
+	if len(tests[0].Extract) != 1 || tests[0].Extract[0].Field != "id" {
+		t.Errorf("expected the created id to be extracted, got %+v", tests[0].Extract)
+	}
+	if want := "/v1/users/{{" + tests[0].Extract[0].As + "}}?expand=true"; tests[1].Endpoint != want {
+		t.Errorf("expected %s, got %s", want, tests[1].Endpoint)
+	}
+}
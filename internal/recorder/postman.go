@@ -0,0 +1,42 @@
+package recorder
+
+import (
+	"slices"
+	"strings"
+
+	agent "github.com/Octrafic/octrafic-cli/internal/agents"
+	"github.com/Octrafic/octrafic-cli/internal/core/parser"
+)
+
+// FromPostman converts the requests of a Postman collection (see
+// parser.ReadPostman) into test cases, in run order. Translated test script
+// checks become assertions and variables scripts set from responses become
+// extracted {{variables}}. Requests with Postman authentication require
+// authentication, which the run's provider adds.
+func FromPostman(col *parser.PostmanCollection) []agent.TestCase {
+	tests := make([]agent.TestCase, 0, len(col.Requests))
+	for i, req := range col.Requests {
+		tc := agent.TestCase{
+			ID:             i + 1,
+			Description:    strings.Join(append(slices.Clone(req.Folder), req.Name), " / "),
+			Method:         req.Method,
+			Endpoint:       req.Path,
+			ExpectedStatus: req.ExpectedStatus,
+			RequiresAuth:   req.Auth != nil,
+		}
+		if len(req.Headers) > 0 {
+			tc.Headers = req.Headers
+		}
+		if req.Body != "" {
+			tc.Body = req.Body
+		}
+		for _, a := range req.Assertions {
+			tc.Assertions = append(tc.Assertions, agent.Assertion{Field: a.Field, Op: a.Op, Value: a.Value})
+		}
+		for _, e := range req.Extract {
+			tc.Extract = append(tc.Extract, agent.Extract{Field: e.Field, As: e.As})
+		}
+		tests = append(tests, tc)
+	}
+	return tests
+}
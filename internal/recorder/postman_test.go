@@ -0,0 +1,49 @@
+package recorder
+
+import (
+	"testing"
+
+	"github.com/Octrafic/octrafic-cli/internal/core/parser"
+)
+
+func TestFromPostman_ChainsScriptVariables(t *testing.T) {
+	collection := `{
+	  "info": {"name": "Users", "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"},
+	  "auth": {"type": "bearer", "bearer": [{"key": "token", "value": "{{token}}"}]},
+	  "variable": [{"key": "baseUrl", "value": "https://api.example.com"}],
+	  "item": [
+	    {"name": "Create user",
+	     "event": [{"listen": "test", "script": {"exec": [
+	       "pm.test('created', function () { pm.response.to.have.status(201); });",
+	       "pm.collectionVariables.set('userId', pm.response.json().id);"
+	     ]}}],
+	     "request": {"method": "POST", "url": "{{baseUrl}}/users",
+	       "body": {"mode": "raw", "raw": "{\"name\": \"Ada\"}", "options": {"raw": {"language": "json"}}}}},
+	    {"name": "Get user",
+	     "event": [{"listen": "test", "script": {"exec": ["pm.expect(pm.response.json().name).to.eql('Ada');"]}}],
+	     "request": {"method": "GET", "url": "{{baseUrl}}/users/{{userId}}"}}
+	  ]
+	}`
+	col, err := parser.ReadPostman([]byte(collection), nil)
+	if err != nil {
+		t.Fatalf("ReadPostman failed: %v", err)
+	}
+	tests := FromPostman(col)
+	if len(tests) != 2 {
+		t.Fatalf("expected 2 tests, got %d", len(tests))
+	}
+	create := tests[0]
+	if create.Method != "POST" || create.Endpoint != "/users" || create.ExpectedStatus != 201 || !create.RequiresAuth {
+		t.Errorf("expected authenticated POST /users expecting 201, got %+v", create)
+	}
+	if create.Body != `{"name": "Ada"}` || create.Headers["Content-Type"] != "application/json" {
+		t.Errorf("expected JSON body, got %v %v", create.Body, create.Headers)
+	}
+	if len(create.Extract) != 1 || create.Extract[0].Field != "id" || create.Extract[0].As != "userId" {
+		t.Errorf("expected userId extracted, got %+v", create.Extract)
+	}
+	get := tests[1]
+	if get.Endpoint != "/users/{{userId}}" || len(get.Assertions) != 1 || get.Assertions[0].Value != "Ada" {
+		t.Errorf("expected chained GET with assertion, got %s %+v", get.Endpoint, get.Assertions)
+	}
+}
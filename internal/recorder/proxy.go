@@ -0,0 +1,193 @@
+package recorder
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxBodySize caps how much of a request or response body is kept per exchange.
+const maxBodySize = 1 << 20
+
+// hopHeaders are connection-level headers that must not be forwarded.
+var hopHeaders = []string{
+	"Connection", "Proxy-Connection", "Keep-Alive", "Proxy-Authenticate",
+	"Proxy-Authorization", "Te", "Trailer", "Transfer-Encoding", "Upgrade",
+}
+
+// Exchange is one captured request and its response.
+type Exchange struct {
+	Method          string            `json:"method"`
+	Path            string            `json:"path"` // relative to the target, with query
+	RequestHeaders  map[string]string `json:"request_headers,omitempty"`
+	RequestBody     string            `json:"request_body,omitempty"`
+	Status          int               `json:"status"`
+	ResponseHeaders map[string]string `json:"response_headers,omitempty"`
+	ResponseBody    string            `json:"response_body,omitempty"`
+	Duration        time.Duration     `json:"duration"`
+}
+
+// Proxy forwards traffic to the API under test and records every exchange.
+// It works as a reverse proxy (clients point at the proxy instead of the API)
+// and as a plain HTTP forward proxy (clients use it via HTTP_PROXY). In
+// forward mode, requests to other hosts are passed through unrecorded.
+type Proxy struct {
+	target    *url.URL
+	transport http.RoundTripper
+
+	mu        sync.Mutex
+	exchanges []Exchange
+
+	// Log, if set, is called after each recorded exchange.
+	Log func(Exchange)
+}
+
+// NewProxy creates a recording proxy for the API at target.
+func NewProxy(target string) (*Proxy, error) {
+	u, err := url.Parse(strings.TrimSuffix(target, "/"))
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("invalid target URL %q: expected http(s)://host[/base]", target)
+	}
+	return &Proxy{target: u, transport: http.DefaultTransport}, nil
+}
+
+// Exchanges returns the exchanges recorded so far, in order.
+func (p *Proxy) Exchanges() []Exchange {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Exchange(nil), p.exchanges...)
+}
+
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodConnect {
+		http.Error(w, "HTTPS tunnelling is not supported; point the client at the proxy directly (reverse mode)", http.StatusNotImplemented)
+		return
+	}
+
+	outURL, path, record := p.resolve(r.URL)
+
+	// The body is streamed upstream in full; only the recorded copy is capped.
+	var reqBody cappedBuffer
+	var body io.Reader = http.NoBody
+	if r.Body != nil && r.ContentLength != 0 {
+		body = io.TeeReader(r.Body, &reqBody)
+	}
+	out, err := http.NewRequestWithContext(r.Context(), r.Method, outURL.String(), body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to create request: %v", err), http.StatusBadGateway)
+		return
+	}
+	out.ContentLength = r.ContentLength
+	out.Header = r.Header.Clone()
+	removeHopHeaders(out.Header)
+	// Without the client's Accept-Encoding the transport asks for gzip itself
+	// and decompresses the response, so exchanges are recorded as plain text.
+	out.Header.Del("Accept-Encoding")
+	out.Host = outURL.Host
+
+	start := time.Now()
+	resp, err := p.transport.RoundTrip(out)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("upstream request failed: %v", err), http.StatusBadGateway)
+		return
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, err := io.ReadAll(resp.Body)
+	duration := time.Since(start)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read upstream response: %v", err), http.StatusBadGateway)
+		return
+	}
+
+	header := resp.Header.Clone()
+	removeHopHeaders(header)
+	for k, v := range header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(resp.StatusCode)
+	_, _ = w.Write(respBody)
+
+	if !record {
+		return
+	}
+	if len(respBody) > maxBodySize {
+		respBody = respBody[:maxBodySize]
+	}
+	ex := Exchange{
+		Method:          r.Method,
+		Path:            path,
+		RequestHeaders:  flatten(r.Header),
+		RequestBody:     reqBody.String(),
+		Status:          resp.StatusCode,
+		ResponseHeaders: flatten(resp.Header),
+		ResponseBody:    string(respBody),
+		Duration:        duration,
+	}
+	p.mu.Lock()
+	p.exchanges = append(p.exchanges, ex)
+	p.mu.Unlock()
+	if p.Log != nil {
+		p.Log(ex)
+	}
+}
+
+// resolve returns the upstream URL for a request, its path relative to the
+// target, and whether the exchange belongs to the target and is recorded.
+func (p *Proxy) resolve(u *url.URL) (*url.URL, string, bool) {
+	base := strings.TrimSuffix(p.target.Path, "/")
+	if u.IsAbs() {
+		if !strings.EqualFold(u.Host, p.target.Host) || (u.Path != base && !strings.HasPrefix(u.Path, base+"/")) {
+			return u, "", false
+		}
+		return u, requestPath(strings.TrimPrefix(u.Path, base), u.RawQuery), true
+	}
+
+	out := *p.target
+	out.Path = base + u.Path
+	out.RawPath = ""
+	out.RawQuery = u.RawQuery
+	return &out, requestPath(u.Path, u.RawQuery), true
+}
+
+// cappedBuffer keeps the first maxBodySize bytes written to it and discards
+// the rest.
+type cappedBuffer struct {
+	bytes.Buffer
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := maxBodySize - b.Len(); room > 0 {
+		b.Buffer.Write(p[:min(len(p), room)])
+	}
+	return len(p), nil
+}
+
+func requestPath(path, query string) string {
+	if path == "" {
+		path = "/"
+	}
+	if query != "" {
+		path += "?" + query
+	}
+	return path
+}
+
+func removeHopHeaders(h http.Header) {
+	for _, name := range hopHeaders {
+		h.Del(name)
+	}
+}
+
+func flatten(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for k := range h {
+		out[k] = h.Get(k)
+	}
+	return out
+}
@@ -0,0 +1,169 @@
+package recorder
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestProxy_ReverseModeRecordsExchanges(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"path":"` + r.URL.Path + `","got":` + string(body) + `}`))
+	}))
+	defer api.Close()
+
+	p, err := NewProxy(api.URL + "/v1")
+	if err != nil {
+		t.Fatalf("NewProxy failed: %v", err)
+	}
+	var logged []Exchange
+	p.Log = func(ex Exchange) { logged = append(logged, ex) }
+
+	req := httptest.NewRequest("POST", "/users?notify=true", strings.NewReader(`{"name":"Ada"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, req)
+
+	if rec.Code != 201 || !strings.Contains(rec.Body.String(), `"path":"/v1/users"`) {
+		t.Fatalf("expected proxied response from /v1/users, got %d %s", rec.Code, rec.Body.String())
+	}
+	exchanges := p.Exchanges()
+	if len(exchanges) != 1 || len(logged) != 1 {
+		t.Fatalf("expected 1 recorded exchange, got %d (logged %d)", len(exchanges), len(logged))
+	}
+	ex := exchanges[0]
+	if ex.Method != "POST" || ex.Path != "/users?notify=true" || ex.Status != 201 {
+		t.Errorf("unexpected exchange: %+v", ex)
+	}
+	if ex.RequestBody != `{"name":"Ada"}` || ex.ResponseHeaders["Content-Type"] != "application/json" {
+		t.Errorf("expected bodies and headers to be captured, got %+v", ex)
+	}
+}
+
+func TestProxy_ForwardModeRecordsOnlyTarget(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer api.Close()
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`other`))
+	}))
+	defer other.Close()
+
+	p, err := NewProxy(api.URL)
+	if err != nil {
+		t.Fatalf("NewProxy failed: %v", err)
+	}
+
+	for _, target := range []string{api.URL + "/health", other.URL + "/assets/app.js"} {
+		rec := httptest.NewRecorder()
+		p.ServeHTTP(rec, httptest.NewRequest("GET", target, nil))
+		if rec.Code != 200 {
+			t.Errorf("GET %s: expected 200, got %d", target, rec.Code)
+		}
+	}
+
+	exchanges := p.Exchanges()
+	if len(exchanges) != 1 || exchanges[0].Path != "/health" {
+		t.Errorf("expected only the target request to be recorded, got %+v", exchanges)
+	}
+
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, httptest.NewRequest("CONNECT", api.URL, nil))
+	if rec.Code != http.StatusNotImplemented {
+		t.Errorf("expected CONNECT to be rejected, got %d", rec.Code)
+	}
+}
+
+func TestProxy_ForwardsLargeBodies(t *testing.T) {
+	var received int
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = len(body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer api.Close()
+
+	p, err := NewProxy(api.URL)
+	if err != nil {
+		t.Fatalf("NewProxy failed: %v", err)
+	}
+	upload := strings.Repeat("x", maxBodySize+100)
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, httptest.NewRequest("PUT", "/files/1", strings.NewReader(upload)))
+
+	if rec.Code != http.StatusNoContent || received != len(upload) {
+		t.Fatalf("expected the full %d byte upload forwarded, got %d bytes (status %d)", len(upload), received, rec.Code)
+	}
+	if ex := p.Exchanges(); len(ex) != 1 || len(ex[0].RequestBody) != maxBodySize {
+		t.Errorf("expected the recorded body capped at %d bytes", maxBodySize)
+	}
+}
+
+func TestProxy_ForwardModeMatchesBasePathSegments(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer api.Close()
+
+	p, err := NewProxy(api.URL + "/api")
+	if err != nil {
+		t.Fatalf("NewProxy failed: %v", err)
+	}
+	for _, path := range []string{"/api", "/api/users", "/apiv2/users"} {
+		p.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", api.URL+path, nil))
+	}
+
+	var paths []string
+	for _, ex := range p.Exchanges() {
+		paths = append(paths, ex.Path)
+	}
+	if strings.Join(paths, " ") != "/ /users" {
+		t.Errorf("expected only paths under /api recorded, got %v", paths)
+	}
+}
+
+func TestProxy_RecordsCompressedResponsesDecoded(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			_, _ = w.Write([]byte(`{"id":1}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		_, _ = gz.Write([]byte(`{"id":1}`))
+		_ = gz.Close()
+	}))
+	defer api.Close()
+
+	p, err := NewProxy(api.URL)
+	if err != nil {
+		t.Fatalf("NewProxy failed: %v", err)
+	}
+	req := httptest.NewRequest("GET", "/users/1", nil)
+	req.Header.Set("Accept-Encoding", "gzip, deflate, br")
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, req)
+
+	if rec.Body.String() != `{"id":1}` || rec.Header().Get("Content-Encoding") != "" {
+		t.Errorf("expected a decoded response for the client, got %q (%s)", rec.Body.String(), rec.Header().Get("Content-Encoding"))
+	}
+	if ex := p.Exchanges(); len(ex) != 1 || ex[0].ResponseBody != `{"id":1}` {
+		t.Errorf("expected the decoded body recorded, got %+v", ex)
+	}
+}
+
+func TestNewProxy_InvalidTarget(t *testing.T) {
+	for _, target := range []string{"", "localhost:8080", "ftp://example.com"} {
+		if _, err := NewProxy(target); err == nil {
+			t.Errorf("expected error for target %q", target)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package recorder
+
+import (
+	agent "github.com/Octrafic/octrafic-cli/internal/agents"
+	"github.com/Octrafic/octrafic-cli/internal/core/parser"
+)
+
+// FromPytest converts the test functions of a pytest file (see
+// parser.ReadPytest) into test cases, in file order. Tests sending the
+// file's credentials require authentication, which the run's provider adds.
+func FromPytest(file *parser.PytestFile) []agent.TestCase {
+	tests := make([]agent.TestCase, 0, len(file.Tests))
+	for i, test := range file.Tests {
+		tc := agent.TestCase{
+			ID:             i + 1,
+			Description:    test.Name,
+			Method:         test.Method,
+			Endpoint:       test.Path,
+			ExpectedStatus: test.ExpectedStatus,
+			RequiresAuth:   test.AuthType != "",
+		}
+		if len(test.Headers) > 0 {
+			tc.Headers = test.Headers
+		}
+		if test.Body != "" {
+			tc.Body = test.Body
+		}
+		if p := test.Poll; p != nil {
+			tc.Poll = &agent.Poll{
+				UntilStatus:     p.UntilStatus,
+				IntervalSeconds: p.IntervalSeconds,
+				TimeoutSeconds:  p.TimeoutSeconds,
+				Message:         p.Message,
+			}
+			for _, c := range p.Until {
+				tc.Poll.Until = append(tc.Poll.Until, agent.Assertion{Field: c.Field, Op: c.Op, Value: c.Value})
+			}
+		}
+		tests = append(tests, tc)
+	}
+	return tests
+}
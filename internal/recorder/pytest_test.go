@@ -0,0 +1,53 @@
+package recorder
+
+import (
+	"testing"
+
+	"github.com/Octrafic/octrafic-cli/internal/core/parser"
+)
+
+func TestFromPytest_Poll(t *testing.T) {
+	script := `import os
+import time
+import requests
+
+BASE_URL = "https://api.example.com"
+AUTH_TOKEN = os.environ["AUTH_TOKEN"]
+
+def test_wait_for_export():
+    """GET /exports/7"""
+    url = BASE_URL + "/exports/7"
+    headers = {}
+    headers["Authorization"] = f"Bearer {AUTH_TOKEN}"
+    auth = None
+    deadline = time.monotonic() + 30
+    while True:
+        response = requests.get(url, headers=headers, auth=auth)
+        body = _json(response)
+        if response.status_code == 200 and _get(body, "status") == "done":
+            break
+        assert time.monotonic() < deadline, "export never finished"
+        time.sleep(1)
+
+    assert response.status_code == 200
+`
+	file, err := parser.ReadPytest([]byte(script))
+	if err != nil {
+		t.Fatalf("ReadPytest failed: %v", err)
+	}
+	tests := FromPytest(file)
+	if len(tests) != 1 {
+		t.Fatalf("expected 1 test, got %d", len(tests))
+	}
+	tc := tests[0]
+	if tc.Method != "GET" || tc.Endpoint != "/exports/7" || tc.ExpectedStatus != 200 || !tc.RequiresAuth || tc.Headers != nil {
+		t.Errorf("expected authenticated GET /exports/7 expecting 200, got %+v", tc)
+	}
+	p := tc.Poll
+	if p == nil || p.UntilStatus != 200 || p.TimeoutSeconds != 30 || p.IntervalSeconds != 1 || p.Message != "export never finished" {
+		t.Fatalf("expected poll loop, got %+v", p)
+	}
+	if len(p.Until) != 1 || p.Until[0].Field != "status" || p.Until[0].Op != "eq" || p.Until[0].Value != "done" {
+		t.Errorf("expected status eq done, got %+v", p.Until)
+	}
+}
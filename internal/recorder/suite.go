@@ -0,0 +1,542 @@
+package recorder
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	agent "github.com/Octrafic/octrafic-cli/internal/agents"
+)
+
+const (
+	// maxAssertions caps the inferred assertions per test.
+	maxAssertions = 10
+	// maxAssertDepth limits how deep into nested objects assertions go.
+	maxAssertDepth = 2
+	// maxChainDepth limits how deep into responses chained values are searched.
+	maxChainDepth = 4
+)
+
+// droppedHeaders are request headers set by browsers, clients or the
+// executor that carry no meaning for a replayed test.
+var droppedHeaders = []string{
+	"Accept", "Accept-Encoding", "Accept-Language", "Cache-Control", "Connection",
+	"Content-Length", "Cookie", "Dnt", "Host", "If-Modified-Since", "If-None-Match",
+	"Origin", "Pragma", "Priority", "Referer", "Upgrade-Insecure-Requests", "User-Agent",
+}
+
+// staticTypes are response content types of assets rather than API calls.
+var staticTypes = []string{"text/html", "text/css", "javascript", "image/", "font/", "audio/", "video/"}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// variable is a response value that later requests may reuse. It is named
+// on first use so unused values do not claim names.
+type variable struct {
+	name     string
+	key      string // field name the value was found under
+	producer int    // index of the test whose response holds the value
+	field    string // dot path of the value in that response
+}
+
+// builder turns exchanges into chained test cases.
+type builder struct {
+	tests []agent.TestCase
+	vars  map[string]*variable // keyed by the value as it appears in requests
+	names map[string]bool
+}
+
+// BuildTests converts recorded exchanges into test cases. Values returned by
+// one response and sent in a later request (path segments, query values,
+// headers, JSON fields) become extracted {{variables}}; each test expects the
+// recorded status and asserts the response fields that look stable.
+func BuildTests(exchanges []Exchange) []agent.TestCase {
+	b := &builder{vars: make(map[string]*variable), names: make(map[string]bool)}
+	var responses []any
+	for _, ex := range exchanges {
+		if skipExchange(ex) {
+			continue
+		}
+		tc := b.request(ex)
+		if n := len(b.tests); n > 0 && sameRequest(b.tests[n-1], tc) {
+			continue
+		}
+		tc.ID = len(b.tests) + 1
+		b.tests = append(b.tests, tc)
+
+		var body any
+		if json.Unmarshal([]byte(ex.ResponseBody), &body) != nil {
+			body = nil
+		}
+		responses = append(responses, body)
+		b.register(len(b.tests)-1, body, requestValues(ex))
+	}
+	b.assert(responses)
+	return b.tests
+}
+
+func skipExchange(ex Exchange) bool {
+	if ex.Method == "OPTIONS" || ex.Method == "HEAD" || ex.Method == "CONNECT" {
+		return true
+	}
+	contentType := strings.ToLower(ex.ResponseHeaders["Content-Type"])
+	for _, t := range staticTypes {
+		if strings.Contains(contentType, t) {
+			return true
+		}
+	}
+	return false
+}
+
+func sameRequest(a, b agent.TestCase) bool {
+	return a.Method == b.Method && a.Endpoint == b.Endpoint && a.Body == b.Body && a.ExpectedStatus == b.ExpectedStatus
+}
+
+// request builds the test case for an exchange, substituting known values.
+func (b *builder) request(ex Exchange) agent.TestCase {
+	tc := agent.TestCase{
+		Method:         ex.Method,
+		Description:    fmt.Sprintf("%s %s (recorded)", ex.Method, ex.Path),
+		Reasoning:      "Recorded from live traffic",
+		ExpectedStatus: ex.Status,
+	}
+
+	path, query, _ := strings.Cut(ex.Path, "?")
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		if v, err := url.PathUnescape(seg); err == nil && v != "" {
+			if ref := b.use(v); ref != "" {
+				segments[i] = ref
+			}
+		}
+	}
+	tc.Endpoint = strings.Join(segments, "/")
+	if query != "" {
+		tc.Endpoint += "?" + b.substituteForm(query)
+	}
+
+	isJSON := false
+	for name, value := range ex.RequestHeaders {
+		if slices.ContainsFunc(droppedHeaders, func(h string) bool { return strings.EqualFold(h, name) }) ||
+			strings.HasPrefix(strings.ToLower(name), "sec-") || strings.HasPrefix(strings.ToLower(name), "x-forwarded-") {
+			continue
+		}
+		if strings.EqualFold(name, "Content-Type") {
+			if strings.Contains(value, "json") {
+				isJSON = true
+				continue
+			}
+		}
+		substituted := b.substituteHeader(value)
+		if isCredentialHeader(name) && substituted == value {
+			// Credentials come from the project's auth configuration.
+			tc.RequiresAuth = true
+			continue
+		}
+		if tc.Headers == nil {
+			tc.Headers = make(map[string]string)
+		}
+		tc.Headers[name] = substituted
+	}
+
+	if ex.RequestBody != "" {
+		var parsed any
+		switch {
+		case json.Unmarshal([]byte(ex.RequestBody), &parsed) == nil:
+			tc.Body = b.substituteJSON(ex.RequestBody, parsed)
+		case !isJSON && strings.Contains(ex.RequestHeaders["Content-Type"], "x-www-form-urlencoded"):
+			tc.Body = b.substituteForm(ex.RequestBody)
+		default:
+			tc.Body = ex.RequestBody
+		}
+	}
+	return tc
+}
+
+// use returns the {{placeholder}} for a known value and makes sure the
+// producing test extracts it.
+func (b *builder) use(value string) string {
+	v, ok := b.vars[value]
+	if !ok {
+		return ""
+	}
+	producer := &b.tests[v.producer]
+	if v.name == "" {
+		v.name = b.varName(v.key, producer.Endpoint)
+	}
+	if !slices.ContainsFunc(producer.Extract, func(e agent.Extract) bool { return e.As == v.name }) {
+		producer.Extract = append(producer.Extract, agent.Extract{Field: v.field, As: v.name})
+	}
+	return "{{" + v.name + "}}"
+}
+
+// substituteForm replaces known values in a query string or form body.
+func (b *builder) substituteForm(raw string) string {
+	pairs := strings.Split(raw, "&")
+	for i, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		if v, err := url.QueryUnescape(value); err == nil {
+			if ref := b.use(v); ref != "" {
+				pairs[i] = key + "=" + ref
+			}
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// substituteHeader replaces a known value used as a whole header value or
+// as the credential after a scheme such as "Bearer".
+func (b *builder) substituteHeader(value string) string {
+	if ref := b.use(value); ref != "" {
+		return ref
+	}
+	if scheme, credential, ok := strings.Cut(value, " "); ok {
+		if ref := b.use(credential); ref != "" {
+			return scheme + " " + ref
+		}
+	}
+	return value
+}
+
+// substituteJSON replaces known values among the leaves of a JSON body.
+// Strings keep their quotes; numbers become bare placeholders, which are
+// valid JSON again once the variable is applied.
+func (b *builder) substituteJSON(raw string, parsed any) string {
+	for _, leaf := range leaves(parsed, "", maxChainDepth, true) {
+		key := scalarString(leaf.value)
+		if key == "" {
+			continue
+		}
+		ref := b.use(key)
+		if ref == "" {
+			continue
+		}
+		if s, ok := leaf.value.(string); ok {
+			quoted, _ := json.Marshal(s)
+			raw = strings.ReplaceAll(raw, string(quoted), `"`+ref+`"`)
+			continue
+		}
+		number := regexp.MustCompile(`([\[:,]\s*)` + regexp.QuoteMeta(key) + `(\s*[,\]}])`)
+		for next := number.ReplaceAllString(raw, "${1}"+ref+"${2}"); next != raw; next = number.ReplaceAllString(raw, "${1}"+ref+"${2}") {
+			raw = next
+		}
+	}
+	return raw
+}
+
+// register remembers chainable values from a test's response. Values the
+// request itself sent are echoes, not new data, and are ignored.
+func (b *builder) register(test int, body any, sent map[string]bool) {
+	for _, leaf := range leaves(body, "", maxChainDepth, true) {
+		if !chainable(leaf.key, leaf.value) {
+			continue
+		}
+		value := scalarString(leaf.value)
+		if sent[value] {
+			continue
+		}
+		if _, known := b.vars[value]; known {
+			continue
+		}
+		b.vars[value] = &variable{key: leaf.key, producer: test, field: leaf.path}
+	}
+}
+
+// varName derives a unique variable name, e.g. "id" from POST /users
+// becomes "user_id" and "accessToken" becomes "access_token".
+func (b *builder) varName(key, endpoint string) string {
+	name := snakeCase(key)
+	if name == "id" {
+		if resource := lastResource(endpoint); resource != "" {
+			name = resource + "_id"
+		}
+	}
+	unique := name
+	for i := 2; b.names[unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", name, i)
+	}
+	b.names[unique] = true
+	return unique
+}
+
+// assert infers assertions for each test. Exchanges are grouped by method
+// and path shape; a field is asserted equal only if it looks stable and
+// every response in the group agrees on its value.
+func (b *builder) assert(responses []any) {
+	groups := make(map[string][]int)
+	for i, tc := range b.tests {
+		key := tc.Method + " " + pathShape(tc.Endpoint)
+		groups[key] = append(groups[key], i)
+	}
+
+	for i := range b.tests {
+		tc := &b.tests[i]
+		obj, ok := responses[i].(map[string]any)
+		if !ok {
+			continue
+		}
+		group := groups[tc.Method+" "+pathShape(tc.Endpoint)]
+		for _, leaf := range leaves(obj, "", maxAssertDepth, false) {
+			if len(tc.Assertions) >= maxAssertions {
+				break
+			}
+			if leaf.value == nil {
+				continue
+			}
+			present, same := true, true
+			for _, j := range group {
+				other, found := resolve(responses[j], leaf.path)
+				if !found {
+					present = false
+					break
+				}
+				same = same && sameValue(other, leaf.value)
+			}
+			_, isObject := leaf.value.(map[string]any)
+			_, isArray := leaf.value.([]any)
+			switch {
+			case !present:
+				continue
+			case !same, isObject, isArray, volatile(leaf.key, leaf.value),
+				slices.ContainsFunc(tc.Extract, func(e agent.Extract) bool { return e.Field == leaf.path }):
+				tc.Assertions = append(tc.Assertions, agent.Assertion{Field: leaf.path, Op: "exists"})
+			default:
+				tc.Assertions = append(tc.Assertions, agent.Assertion{Field: leaf.path, Op: "eq", Value: leaf.value})
+			}
+		}
+	}
+}
+
+func resolve(body any, path string) (any, bool) {
+	current := body
+	for _, part := range strings.Split(path, ".") {
+		switch v := current.(type) {
+		case map[string]any:
+			next, ok := v[part]
+			if !ok {
+				return nil, false
+			}
+			current = next
+		case []any:
+			idx, err := strconv.Atoi(part)
+			if err != nil || idx < 0 || idx >= len(v) {
+				return nil, false
+			}
+			current = v[idx]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func sameValue(a, b any) bool {
+	aj, _ := json.Marshal(a)
+	bj, _ := json.Marshal(b)
+	return string(aj) == string(bj)
+}
+
+// leaf is a value inside a JSON document with its dot path.
+type leaf struct {
+	path  string
+	key   string
+	value any
+}
+
+// leaves lists the values in a JSON document depth-first in key order,
+// descending into objects (and arrays, if enabled) up to depth levels.
+// Containers that are not descended into are listed themselves; array items
+// inherit the key of their array.
+func leaves(body any, prefix string, depth int, arrays bool) []leaf {
+	var out []leaf
+	add := func(path, key string, value any) {
+		_, isObject := value.(map[string]any)
+		_, isArray := value.([]any)
+		if depth > 1 && (isObject || (isArray && arrays)) {
+			for _, l := range leaves(value, path, depth-1, arrays) {
+				if l.key == "" {
+					l.key = key
+				}
+				out = append(out, l)
+			}
+			return
+		}
+		out = append(out, leaf{path: path, key: key, value: value})
+	}
+	switch v := body.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			add(joinPath(prefix, k), k, v[k])
+		}
+	case []any:
+		for i, item := range v {
+			add(joinPath(prefix, strconv.Itoa(i)), "", item)
+		}
+	}
+	return out
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// requestValues collects every scalar an exchange sent, so echoed values
+// are not mistaken for server-generated ones.
+func requestValues(ex Exchange) map[string]bool {
+	sent := make(map[string]bool)
+	path, query, _ := strings.Cut(ex.Path, "?")
+	for _, seg := range strings.Split(path, "/") {
+		if v, err := url.PathUnescape(seg); err == nil {
+			sent[v] = true
+		}
+	}
+	if values, err := url.ParseQuery(query); err == nil {
+		for _, vs := range values {
+			for _, v := range vs {
+				sent[v] = true
+			}
+		}
+	}
+	var body any
+	if json.Unmarshal([]byte(ex.RequestBody), &body) == nil {
+		for _, l := range leaves(body, "", maxChainDepth, true) {
+			sent[scalarString(l.value)] = true
+		}
+	}
+	return sent
+}
+
+func scalarString(v any) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case float64:
+		return strconv.FormatFloat(s, 'f', -1, 64)
+	}
+	return ""
+}
+
+// idLike reports whether a field name suggests an identifier or credential.
+func idLike(key string) bool {
+	lower := strings.ToLower(key)
+	return lower == "id" || strings.HasSuffix(lower, "_id") || strings.HasSuffix(key, "Id") ||
+		strings.HasSuffix(key, "ID") || strings.Contains(lower, "uuid") || strings.Contains(lower, "token") ||
+		strings.Contains(lower, "key") || lower == "slug"
+}
+
+// chainable reports whether a response value is distinctive enough to be
+// recognised when a later request sends it back.
+func chainable(key string, value any) bool {
+	switch v := value.(type) {
+	case string:
+		if idLike(key) {
+			return v != ""
+		}
+		return len(v) >= 8 && !strings.ContainsAny(v, " \t\n")
+	case float64:
+		return idLike(key) && v != 0 && v == float64(int64(v))
+	}
+	return false
+}
+
+// volatile reports whether a value is likely to differ between runs.
+func volatile(key string, value any) bool {
+	if idLike(key) {
+		return true
+	}
+	lower := strings.ToLower(key)
+	if strings.HasSuffix(lower, "_at") || strings.HasSuffix(key, "At") || strings.Contains(lower, "date") ||
+		strings.Contains(lower, "time") || strings.Contains(lower, "expires") {
+		return true
+	}
+	s, ok := value.(string)
+	if !ok {
+		return false
+	}
+	if uuidPattern.MatchString(s) || (len(s) >= 20 && !strings.ContainsAny(s, " \t\n")) {
+		return true
+	}
+	for _, layout := range []string{time.RFC3339, time.DateTime, time.DateOnly} {
+		if _, err := time.Parse(layout, s); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func isCredentialHeader(name string) bool {
+	lower := strings.ToLower(name)
+	return lower == "authorization" || strings.Contains(lower, "api-key") || strings.Contains(lower, "apikey") ||
+		strings.Contains(lower, "api_key") || strings.Contains(lower, "token") || strings.Contains(lower, "auth")
+}
+
+// pathShape normalises variable parts of a path so repeated calls to the
+// same route group together: /users/{{user_id}} and /users/7 both become
+// /users/{}.
+func pathShape(endpoint string) string {
+	path, _, _ := strings.Cut(endpoint, "?")
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		if strings.HasPrefix(seg, "{{") || uuidPattern.MatchString(seg) {
+			segments[i] = "{}"
+		} else if _, err := strconv.ParseFloat(seg, 64); err == nil {
+			segments[i] = "{}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// lastResource returns the singular name of the last literal path segment,
+// e.g. "order" for /users/{{user_id}}/orders.
+func lastResource(endpoint string) string {
+	segments := strings.Split(pathShape(endpoint), "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		seg := segments[i]
+		if seg == "" || seg == "{}" {
+			continue
+		}
+		seg = snakeCase(seg)
+		switch {
+		case strings.HasSuffix(seg, "ies"):
+			return strings.TrimSuffix(seg, "ies") + "y"
+		case strings.HasSuffix(seg, "s") && !strings.HasSuffix(seg, "ss"):
+			return strings.TrimSuffix(seg, "s")
+		}
+		return seg
+	}
+	return ""
+}
+
+func snakeCase(s string) string {
+	var sb strings.Builder
+	for i, r := range s {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			if i > 0 && !(s[i-1] >= 'A' && s[i-1] <= 'Z') {
+				sb.WriteByte('_')
+			}
+			sb.WriteRune(r + ('a' - 'A'))
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			sb.WriteRune(r)
+		default:
+			sb.WriteByte('_')
+		}
+	}
+	return strings.Trim(sb.String(), "_")
+}
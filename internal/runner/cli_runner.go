@@ -2,8 +2,11 @@ package runner
 
 import (
 	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/Octrafic/octrafic-cli/internal/core/auth"
+	"github.com/Octrafic/octrafic-cli/internal/core/graphql"
 	"github.com/Octrafic/octrafic-cli/internal/core/parser"
 	"github.com/Octrafic/octrafic-cli/internal/core/tester"
 )
@@ -13,6 +16,7 @@ type Options struct {
 	BaseURL      string
 	AuthProvider auth.AuthProvider
 	FailFast     bool
+	Identities   map[string]auth.AuthProvider // named identities tests can run as
 }
 
 // RunTests Headlessly executes tests inside the specification
@@ -25,19 +29,52 @@ func RunTests(spec *parser.Specification, opts Options) int {
 	fmt.Printf("Starting execution of %d tests in %s spec...\n\n", len(spec.Endpoints), spec.Format)
 
 	executor := tester.NewExecutor(opts.BaseURL, opts.AuthProvider)
-	failed := 0
+	failed, skipped := 0, 0
 
 	for i, endpoint := range spec.Endpoints {
 		fmt.Printf("[%d/%d] Running %s %s... ", i+1, len(spec.Endpoints), endpoint.Method, endpoint.Path)
 
-		headers := make(map[string]string)
-		var body any
+		// Webhooks are sent by the API, not to it.
+		if endpoint.Webhook != nil {
+			fmt.Printf("SKIPPED (webhook)\n")
+			skipped++
+			continue
+		}
 
-		if endpoint.RequestBody != "" {
-			body = endpoint.RequestBody
+		// Only HTTP and WebSocket channels of AsyncAPI specs can be reached.
+		if endpoint.AsyncAPI != nil && !endpoint.AsyncAPI.Executable() {
+			fmt.Printf("SKIPPED (%s channel)\n", endpoint.AsyncAPI.Protocol)
+			skipped++
+			continue
+		}
+
+		path, headers, sample := endpoint.SampleRequest()
+		method := endpoint.Method
+		var body any
+		if sample != "" {
+			body = sample
+		}
+		if op := endpoint.GraphQL; op != nil {
+			method, path = "POST", op.Endpoint
+			body = graphql.RequestBody(op.Query, op.Variables, op.OperationName)
 		}
 
-		result, err := executor.ExecuteTest(endpoint.Method, endpoint.Path, headers, body, endpoint.RequiresAuth)
+		// WebSocket channels pass when the connection is upgraded; a sample
+		// message is sent to PUBLISH channels and one is awaited on SUBSCRIBE.
+		isWebSocket := endpoint.AsyncAPI != nil && endpoint.AsyncAPI.IsWebSocket()
+		var result *tester.TestResult
+		var err error
+		if isWebSocket {
+			ws := tester.WebSocketOptions{Timeout: 5 * time.Second}
+			if method == "SUBSCRIBE" {
+				ws.Receive = 1
+			} else if sample != "" {
+				ws.Send = []any{sample}
+			}
+			result, err = executor.ExecuteWebSocket(method, path, headers, ws, endpoint.RequiresAuth)
+		} else {
+			result, err = executor.ExecuteTest(method, path, headers, body, endpoint.RequiresAuth)
+		}
 		if err != nil {
 			fmt.Printf("FAILED ❌\n")
 			fmt.Printf("      Error: %v\n", err)
@@ -50,7 +87,22 @@ func RunTests(spec *parser.Specification, opts Options) int {
 
 		// Very basic status code assertion if we had assertions, but for now
 		// assume 2xx is pass.
-		if result.StatusCode >= 200 && result.StatusCode < 400 {
+		if endpoint.GraphQL != nil {
+			// GraphQL reports failures in the body, usually with a 200.
+			if errs := graphql.ResponseErrors(result.ResponseBody); len(errs) > 0 {
+				fmt.Printf("FAILED (GraphQL errors) ❌\n")
+				for _, e := range errs {
+					fmt.Printf("      Error: %s\n", e)
+				}
+				failed++
+				if opts.FailFast {
+					break
+				}
+				continue
+			}
+		}
+
+		if (result.StatusCode >= 200 && result.StatusCode < 400) || (isWebSocket && result.StatusCode == http.StatusSwitchingProtocols) {
 			fmt.Printf("OK (%dms) ✅\n", result.Duration.Milliseconds())
 		} else {
 			fmt.Printf("FAILED (Status %d) ❌\n", result.StatusCode)
@@ -63,7 +115,8 @@ func RunTests(spec *parser.Specification, opts Options) int {
 	}
 
 	fmt.Println("\n=============================================")
-	fmt.Printf("Summary: %d executed, %d passed, %d failed\n", len(spec.Endpoints), len(spec.Endpoints)-failed, failed)
+	executed := len(spec.Endpoints) - skipped
+	fmt.Printf("Summary: %d executed, %d passed, %d failed\n", executed, executed-failed, failed)
 
 	if failed > 0 {
 		return 1
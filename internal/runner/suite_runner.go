@@ -0,0 +1,216 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	agent "github.com/Octrafic/octrafic-cli/internal/agents"
+	"github.com/Octrafic/octrafic-cli/internal/core/graphql"
+	"github.com/Octrafic/octrafic-cli/internal/core/tester"
+)
+
+// RunSuite headlessly replays saved test cases in order, passing extracted
+// {{variables}} between them and checking expected status and assertions.
+func RunSuite(tests []agent.TestCase, opts Options) int {
+	if len(tests) == 0 {
+		fmt.Println("No tests found to execute in the provided suite.")
+		return 0
+	}
+
+	fmt.Printf("Starting execution of %d tests...\n\n", len(tests))
+
+	executor := tester.NewExecutor(opts.BaseURL, opts.AuthProvider)
+	vars := make(map[string]string)
+	failed, skipped := 0, 0
+
+	for i, tc := range tests {
+		method, endpoint := tc.Method, applyVars(tc.Endpoint, vars)
+		as := ""
+		if tc.Identity != "" {
+			as = " as " + tc.Identity
+		}
+		fmt.Printf("[%d/%d] Running %s %s%s... ", i+1, len(tests), method, endpoint, as)
+
+		// Callbacks and pagination need the interactive runner.
+		if tc.Webhook != nil || tc.Paginate != nil {
+			fmt.Printf("SKIPPED (webhook/paginate)\n")
+			skipped++
+			continue
+		}
+
+		headers := make(map[string]string, len(tc.Headers))
+		for k, v := range tc.Headers {
+			headers[k] = applyVars(v, vars)
+		}
+		var body any
+		switch b := tc.Body.(type) {
+		case nil:
+		case string:
+			body = applyVars(b, vars)
+		default:
+			data, err := json.Marshal(b)
+			if err != nil {
+				body = b
+			} else {
+				body = applyVars(string(data), vars)
+			}
+		}
+		if tc.GraphQL != nil {
+			method = "POST"
+			body = applyVars(graphql.RequestBody(tc.GraphQL.Query, tc.GraphQL.Variables, tc.GraphQL.OperationName), vars)
+		}
+
+		testExecutor, requiresAuth := executor, tc.RequiresAuth
+		if tc.Identity != "" {
+			provider, ok := opts.Identities[tc.Identity]
+			if !ok {
+				fmt.Printf("FAILED ❌\n")
+				fmt.Printf("      Error: unknown identity %q\n", tc.Identity)
+				failed++
+				if opts.FailFast {
+					break
+				}
+				continue
+			}
+			testExecutor, requiresAuth = executor.WithAuth(provider), true
+		}
+
+		exec := func() (*tester.TestResult, error) {
+			return testExecutor.ExecuteTest(method, endpoint, headers, body, requiresAuth)
+		}
+		if ws := tc.WebSocket; ws != nil {
+			opts := tester.WebSocketOptions{
+				Receive: ws.Receive,
+				Timeout: time.Duration(ws.TimeoutSeconds * float64(time.Second)),
+			}
+			for _, msg := range ws.Send {
+				if text, ok := msg.(string); ok {
+					opts.Send = append(opts.Send, applyVars(text, vars))
+				} else if data, err := json.Marshal(msg); err == nil {
+					opts.Send = append(opts.Send, applyVars(string(data), vars))
+				}
+			}
+			exec = func() (*tester.TestResult, error) {
+				return testExecutor.ExecuteWebSocket(method, endpoint, headers, opts, requiresAuth)
+			}
+		}
+		var failures []string
+		var result *tester.TestResult
+		var err error
+		if tc.Poll != nil {
+			var res *tester.PollResult
+			res, err = tester.Poll(exec, tester.PollOptions{
+				Until:       assertionMaps(tc.Poll.Until),
+				UntilStatus: tc.Poll.UntilStatus,
+				Interval:    time.Duration(tc.Poll.IntervalSeconds * float64(time.Second)),
+				Timeout:     time.Duration(tc.Poll.TimeoutSeconds * float64(time.Second)),
+			})
+			if res != nil {
+				result = res.Result
+				if !res.Satisfied {
+					failures = append(failures, res.Failures...)
+				}
+			}
+		} else {
+			result, err = exec()
+		}
+		if err != nil {
+			fmt.Printf("FAILED ❌\n")
+			fmt.Printf("      Error: %v\n", err)
+			failed++
+			if opts.FailFast {
+				break
+			}
+			continue
+		}
+
+		expected := tc.ExpectedStatus
+		if expected == 0 {
+			expected = 200
+		}
+		if result.StatusCode != expected {
+			failures = append(failures, fmt.Sprintf("status: expected %d, got %d", expected, result.StatusCode))
+		}
+		assertions, extracts := assertionMaps(tc.Assertions), tc.Extract
+		if tc.GraphQL != nil {
+			failures = append(failures, graphql.ResponseErrors(result.ResponseBody)...)
+			for _, a := range assertions {
+				a["field"] = graphql.DataPath(a["field"].(string))
+			}
+			extracts = make([]agent.Extract, len(tc.Extract))
+			for i, e := range tc.Extract {
+				extracts[i] = agent.Extract{Field: graphql.DataPath(e.Field), As: e.As}
+			}
+		}
+		failures = append(failures, tester.RunAssertions(result.ResponseBody, assertions)...)
+		extractVars(result.ResponseBody, extracts, vars)
+
+		if len(failures) == 0 {
+			fmt.Printf("OK (%dms) ✅\n", result.Duration.Milliseconds())
+			continue
+		}
+		fmt.Printf("FAILED ❌\n")
+		for _, f := range failures {
+			fmt.Printf("      %s\n", f)
+		}
+		failed++
+		if opts.FailFast {
+			break
+		}
+	}
+
+	fmt.Println("\n=============================================")
+	executed := len(tests) - skipped
+	fmt.Printf("Summary: %d executed, %d passed, %d failed\n", executed, executed-failed, failed)
+
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+func applyVars(s string, vars map[string]string) string {
+	for k, v := range vars {
+		s = strings.ReplaceAll(s, "{{"+k+"}}", v)
+	}
+	return s
+}
+
+// extractVars stores extracted response values as strings, writing numbers
+// without exponents and other non-string values as JSON.
+func extractVars(body string, extracts []agent.Extract, vars map[string]string) {
+	if len(extracts) == 0 {
+		return
+	}
+	var parsed any
+	if err := json.Unmarshal([]byte(body), &parsed); err != nil {
+		return
+	}
+	for _, e := range extracts {
+		val, ok := tester.ResolvePath(parsed, e.Field)
+		if !ok || val == nil || e.As == "" {
+			continue
+		}
+		switch v := val.(type) {
+		case string:
+			vars[e.As] = v
+		case float64:
+			vars[e.As] = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			if data, err := json.Marshal(v); err == nil {
+				vars[e.As] = string(data)
+			}
+		}
+	}
+}
+
+func assertionMaps(assertions []agent.Assertion) []map[string]any {
+	out := make([]map[string]any, len(assertions))
+	for i, a := range assertions {
+		out[i] = map[string]any{"field": a.Field, "op": a.Op, "value": a.Value}
+	}
+	return out
+}
@@ -0,0 +1,79 @@
+package runner
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	agent "github.com/Octrafic/octrafic-cli/internal/agents"
+	"github.com/Octrafic/octrafic-cli/internal/core/auth"
+)
+
+func TestRunSuite_ChainsVariables(t *testing.T) {
+	var paths []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case "POST":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = fmt.Fprint(w, `{"id":1700000000,"name":"Ada"}`)
+		default:
+			_, _ = fmt.Fprint(w, `{"id":1700000000,"name":"Ada"}`)
+		}
+	}))
+	defer api.Close()
+
+	tests := []agent.TestCase{
+		{Method: "POST", Endpoint: "/users", Body: `{"name":"Ada"}`, ExpectedStatus: 201, Extract: []agent.Extract{{Field: "id", As: "user_id"}}},
+		{Method: "GET", Endpoint: "/users/{{user_id}}", ExpectedStatus: 200, Assertions: []agent.Assertion{{Field: "name", Op: "eq", Value: "Ada"}}},
+	}
+	if code := RunSuite(tests, Options{BaseURL: api.URL}); code != 0 {
+		t.Errorf("expected exit code 0, got %d", code)
+	}
+	if len(paths) != 2 || paths[1] != "GET /users/1700000000" {
+		t.Errorf("expected extracted id in the second request, got %v", paths)
+	}
+
+	tests[1].Assertions[0].Value = "Grace"
+	if code := RunSuite(tests, Options{BaseURL: api.URL}); code != 1 {
+		t.Errorf("expected failing assertion to exit 1, got %d", code)
+	}
+}
+
+func TestRunSuite_Identities(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("Authorization") {
+		case "Bearer admin":
+			w.WriteHeader(http.StatusNoContent)
+		case "":
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer api.Close()
+
+	opts := Options{
+		BaseURL:      api.URL,
+		AuthProvider: auth.NewBearerAuth("admin"),
+		Identities: map[string]auth.AuthProvider{
+			"member":    auth.NewBearerAuth("member"),
+			"anonymous": &auth.NoAuth{},
+		},
+	}
+	tests := []agent.TestCase{
+		{Method: "DELETE", Endpoint: "/users/1", RequiresAuth: true, ExpectedStatus: 204},
+		{Method: "DELETE", Endpoint: "/users/1", Identity: "member", ExpectedStatus: 403},
+		{Method: "DELETE", Endpoint: "/users/1", Identity: "anonymous", ExpectedStatus: 401},
+	}
+	if code := RunSuite(tests, opts); code != 0 {
+		t.Errorf("expected each test to run as its identity, got exit code %d", code)
+	}
+
+	tests = append(tests, agent.TestCase{Method: "GET", Endpoint: "/users", Identity: "auditor", ExpectedStatus: 200})
+	if code := RunSuite(tests, opts); code != 1 {
+		t.Errorf("expected an unknown identity to fail, got exit code %d", code)
+	}
+}